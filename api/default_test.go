@@ -32,6 +32,13 @@ func setupConfigs() (*params.NodeConfig, *requests.APIConfig) {
 		WSEnabled:        true,
 		WSHost:           "192.168.0.1",
 		WSPort:           7777,
+		MetricsEnabled:   true,
+		MetricsAddress:   "127.0.0.1:9305",
+
+		TracingEnabled:      true,
+		TracingSampleRatio:  0.5,
+		TracingOTLPEndpoint: "http://localhost:4318",
+		TracingFilePath:     "/tmp/spans.jsonl",
 	}
 
 	return newNodeConfig, apiConfig
@@ -50,6 +57,12 @@ func TestOverrideApiConfig(t *testing.T) {
 	require.Equal(t, apiConfig.WSEnabled, newNodeConfig.WSEnabled)
 	require.Equal(t, apiConfig.WSHost, newNodeConfig.WSHost)
 	require.Equal(t, apiConfig.WSPort, newNodeConfig.WSPort)
+	require.Equal(t, apiConfig.MetricsEnabled, newNodeConfig.MetricsConfig.Enabled)
+	require.Equal(t, apiConfig.MetricsAddress, newNodeConfig.MetricsConfig.Address)
+	require.Equal(t, apiConfig.TracingEnabled, newNodeConfig.TracingConfig.Enabled)
+	require.Equal(t, apiConfig.TracingSampleRatio, newNodeConfig.TracingConfig.SampleRatio)
+	require.Equal(t, apiConfig.TracingOTLPEndpoint, newNodeConfig.TracingConfig.OTLPEndpoint)
+	require.Equal(t, apiConfig.TracingFilePath, newNodeConfig.TracingConfig.FilePath)
 }
 
 func TestOverrideApiConfigTest(t *testing.T) {
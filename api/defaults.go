@@ -245,6 +245,14 @@ func overrideApiConfigProd(nodeConfig *params.NodeConfig, config *requests.APICo
 	nodeConfig.WSEnabled = config.WSEnabled
 	nodeConfig.WSHost = config.WSHost
 	nodeConfig.WSPort = config.WSPort
+
+	nodeConfig.MetricsConfig.Enabled = config.MetricsEnabled
+	nodeConfig.MetricsConfig.Address = config.MetricsAddress
+
+	nodeConfig.TracingConfig.Enabled = config.TracingEnabled
+	nodeConfig.TracingConfig.SampleRatio = config.TracingSampleRatio
+	nodeConfig.TracingConfig.OTLPEndpoint = config.TracingOTLPEndpoint
+	nodeConfig.TracingConfig.FilePath = config.TracingFilePath
 }
 
 func DefaultNodeConfig(installationID string, request *requests.CreateAccount, opts ...params.Option) (*params.NodeConfig, error) {
@@ -9,6 +9,8 @@ import (
 	"math/big"
 	"os"
 	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
 	"sync"
 	"time"
@@ -32,11 +34,13 @@ import (
 	centralizedmetricscommon "github.com/status-im/status-go/centralizedmetrics/common"
 	"github.com/status-im/status-go/common/dbsetup"
 	"github.com/status-im/status-go/connection"
+	"github.com/status-im/status-go/diagnostics"
 	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
 	"github.com/status-im/status-go/images"
 	"github.com/status-im/status-go/internal/sentry"
 	"github.com/status-im/status-go/internal/version"
+	"github.com/status-im/status-go/localbackup"
 	"github.com/status-im/status-go/logutils"
 	"github.com/status-im/status-go/multiaccounts"
 	"github.com/status-im/status-go/multiaccounts/accounts"
@@ -984,6 +988,418 @@ func (b *GethStatusBackend) ImportUnencryptedDatabase(acc multiaccounts.Account,
 	return nil
 }
 
+// ExportLocalBackup writes the app and wallet databases of the logged in account into
+// an archive encrypted with the chat key. Progress is reported with localBackup.progress signals.
+func (b *GethStatusBackend) ExportLocalBackup(request *requests.ExportLocalBackup) (err error) {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	// The export takes a while, the lock is only held to read the state of the backend
+	b.mu.Lock()
+	if b.account == nil || b.appDB == nil || b.walletDB == nil {
+		b.mu.Unlock()
+		return ErrDBNotAvailable
+	}
+	account := *b.account
+	appDB := b.appDB
+	walletDB := b.walletDB
+	b.mu.Unlock()
+
+	chatAccount, err := b.accountManager.SelectedChatAccount()
+	if err != nil {
+		return err
+	}
+
+	appDBVersion, _, err := sqlite.GetLastMigrationVersion(appDB)
+	if err != nil {
+		return err
+	}
+
+	walletDBVersion, _, err := sqlite.GetLastMigrationVersion(walletDB)
+	if err != nil {
+		return err
+	}
+
+	kdfIterations := account.KDFIterations
+	if kdfIterations == 0 {
+		kdfIterations = dbsetup.ReducedKDFIterationsNumber
+	}
+
+	appDBPath, err := b.getAppDBPath(account.KeyUID)
+	if err != nil {
+		return err
+	}
+
+	walletDBPath, err := b.getWalletDBPath(account.KeyUID)
+	if err != nil {
+		return err
+	}
+
+	// The decrypted databases are staged next to the encrypted ones, in the data dir of the
+	// account rather than in a shared temporary directory, and removed whatever the outcome
+	tmpDir, err := os.MkdirTemp(filepath.Dir(appDBPath), ".local-backup-")
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if removeErr := os.RemoveAll(tmpDir); removeErr != nil {
+			b.logger.Error("failed to remove decrypted local backup databases", zap.Error(removeErr))
+		}
+	}()
+
+	files := map[string]string{
+		localbackup.AppDBFile:    filepath.Join(tmpDir, localbackup.AppDBFile),
+		localbackup.WalletDBFile: filepath.Join(tmpDir, localbackup.WalletDBFile),
+	}
+
+	err = sqlite.DecryptDB(appDBPath, files[localbackup.AppDBFile], request.Password, kdfIterations)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt app database")
+	}
+
+	err = sqlite.DecryptDB(walletDBPath, files[localbackup.WalletDBFile], request.Password, kdfIterations)
+	if err != nil {
+		return errors.Wrap(err, "failed to decrypt wallet database")
+	}
+
+	file, err := os.OpenFile(request.BackupPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		closeErr := file.Close()
+		if err == nil {
+			err = closeErr
+		}
+		if err != nil {
+			_ = os.Remove(request.BackupPath)
+		}
+	}()
+
+	manifest := localbackup.Manifest{
+		Account:         account,
+		AppDBVersion:    appDBVersion,
+		WalletDBVersion: walletDBVersion,
+	}
+
+	err = localbackup.Export(file, chatAccount.AccountKey.PrivateKey, manifest, files, func(progress localbackup.Progress) {
+		signal.SendLocalBackupProgress(progress)
+	})
+	if err != nil {
+		return err
+	}
+
+	return file.Sync()
+}
+
+// ExportDiagnostics writes a zip archive with the logs and the state of the node that can be attached
+// to bug reports, secrets and personal data are redacted. It also works when logged out,
+// the parts that couldn't be collected are listed in the errors file of the archive.
+func (b *GethStatusBackend) ExportDiagnostics(request *requests.ExportDiagnostics) error {
+	if err := request.Validate(); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	config := b.config
+	databases := map[string]*sql.DB{
+		"app":    b.appDB,
+		"wallet": b.walletDB,
+	}
+	if b.multiaccountsDB != nil {
+		databases["multiaccounts"] = b.multiaccountsDB.DB()
+	}
+	logFile := filepath.Join(b.rootDataDir, DefaultLogFile)
+	b.mu.Unlock()
+
+	bundle := diagnostics.NewBundle()
+	bundle.AddJSON("metadata.json", map[string]interface{}{
+		"version":   version.Version(),
+		"gitCommit": version.GitCommit(),
+		"os":        runtime.GOOS,
+		"arch":      runtime.GOARCH,
+		"createdAt": time.Now().UTC(),
+	})
+
+	if config != nil {
+		bundle.AddJSON("node_config.json", config)
+		logFile = config.LogFile
+	}
+	diagnostics.CollectLogs(bundle, logFile)
+	apiLogFile := filepath.Join(filepath.Dir(logFile), DefaultAPILogFile)
+	if _, err := os.Stat(apiLogFile); err == nil {
+		diagnostics.CollectLogs(bundle, apiLogFile)
+	}
+
+	schemaVersions := make(map[string][]diagnostics.SchemaVersion)
+	for name, db := range databases {
+		if db == nil {
+			continue
+		}
+		versions, err := diagnostics.SchemaVersions(db)
+		if err != nil {
+			bundle.AddError("schema_versions.json", errors.Wrap(err, name))
+			continue
+		}
+		schemaVersions[name] = versions
+	}
+	bundle.AddJSON("schema_versions.json", schemaVersions)
+
+	statusNode := b.StatusNode()
+	if statusNode != nil && statusNode.IsRunning() {
+		b.collectNodeDiagnostics(bundle, statusNode)
+	} else {
+		bundle.AddError("node", errors.New("node is not running"))
+	}
+
+	diagnostics.CollectProfiles(bundle)
+
+	return bundle.WriteFile(request.BundlePath)
+}
+
+func (b *GethStatusBackend) collectNodeDiagnostics(bundle *diagnostics.Bundle, statusNode *node.StatusNode) {
+	if waku := statusNode.WakuV2Service(); waku != nil {
+		bundle.AddJSON("waku/peers.json", waku.PeerState())
+	}
+
+	if messenger := b.Messenger(); messenger != nil {
+		state, err := messenger.StorenodeCycleState()
+		if err != nil {
+			bundle.AddError("waku/storenodes.json", err)
+		} else {
+			bundle.AddJSON("waku/storenodes.json", state)
+		}
+	}
+
+	if rpcClient := statusNode.RPCClient(); rpcClient != nil {
+		bundle.AddJSON("rpc/providers_health.json", rpcClient.GetBlockchainHealthStatus())
+	}
+
+	if tracker := statusNode.PendingTracker(); tracker != nil {
+		pending, err := tracker.GetAllPending()
+		if err != nil {
+			bundle.AddError("wallet/pending_transactions.json", err)
+		} else {
+			bundle.AddJSON("wallet/pending_transactions.json", pendingTransactionsDiagnostics(pending))
+		}
+	}
+}
+
+// pendingTransactionDiagnostics is what a bundle shows of a pending transaction. Addresses, hashes,
+// values and call data are left out, they would tie the wallet to the bug report.
+type pendingTransactionDiagnostics struct {
+	ChainID   uint64                      `json:"chainId"`
+	Type      transactions.PendingTrxType `json:"type"`
+	Symbol    string                      `json:"symbol"`
+	Nonce     uint64                      `json:"nonce"`
+	Timestamp uint64                      `json:"timestamp"`
+	Status    *transactions.TxStatus      `json:"status,omitempty"`
+}
+
+func pendingTransactionsDiagnostics(pending []*transactions.PendingTransaction) []pendingTransactionDiagnostics {
+	result := make([]pendingTransactionDiagnostics, 0, len(pending))
+	for _, tx := range pending {
+		result = append(result, pendingTransactionDiagnostics{
+			ChainID:   uint64(tx.ChainID),
+			Type:      tx.Type,
+			Symbol:    tx.Symbol,
+			Nonce:     tx.Nonce,
+			Timestamp: tx.Timestamp,
+			Status:    tx.Status,
+		})
+	}
+	return result
+}
+
+// restoreLocalBackupAndLogin restores the databases of an archive exported with ExportLocalBackup,
+// migrates them to the current schema and logs in. The databases are migrated in a temporary
+// directory first, and everything is removed again if any step fails, so that it can be retried.
+func (b *GethStatusBackend) restoreLocalBackupAndLogin(request *requests.RestoreAccount) (_ *multiaccounts.Account, err error) {
+	info, err := b.generateAccountInfo(request.Mnemonic)
+	if err != nil {
+		return nil, err
+	}
+
+	_, keyStoreDir := DefaultKeystorePath(request.RootDataDir, info.KeyUID)
+	keyStoreEntries, _ := os.ReadDir(keyStoreDir)
+
+	_, err = b.InitKeyStoreDirWithAccount(request.RootDataDir, info.KeyUID)
+	if err != nil {
+		return nil, err
+	}
+
+	if b.appDBExists(info.KeyUID) {
+		return nil, errors.New("account already exists")
+	}
+
+	derivedAddresses, err := b.getDerivedAddresses(info.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	chatKey, err := crypto.HexToECDSA(strings.TrimPrefix(derivedAddresses[pathDefaultChat].PrivateKey, "0x"))
+	if err != nil {
+		return nil, err
+	}
+
+	appDBPath, err := b.getAppDBPath(info.KeyUID)
+	if err != nil {
+		return nil, err
+	}
+
+	walletDBPath, err := b.getWalletDBPath(info.KeyUID)
+	if err != nil {
+		return nil, err
+	}
+
+	archive, err := os.Open(request.LocalBackupPath)
+	if err != nil {
+		return nil, err
+	}
+	defer archive.Close()
+
+	// Next to the databases, so that they can be moved in place
+	tmpDir, err := os.MkdirTemp(filepath.Dir(appDBPath), ".local-backup-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(tmpDir)
+
+	manifest, err := localbackup.Restore(archive, chatKey, tmpDir, func(progress localbackup.Progress) {
+		signal.SendLocalBackupProgress(progress)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.Account.KeyUID != info.KeyUID {
+		return nil, errors.New("local backup belongs to another account")
+	}
+
+	kdfIterations := request.KdfIterations
+	if kdfIterations == 0 {
+		kdfIterations = dbsetup.ReducedKDFIterationsNumber
+	}
+
+	restoredAppDBPath := filepath.Join(tmpDir, filepath.Base(appDBPath))
+	restoredWalletDBPath := filepath.Join(tmpDir, filepath.Base(walletDBPath))
+
+	err = sqlite.EncryptDB(filepath.Join(tmpDir, localbackup.AppDBFile), restoredAppDBPath, request.Password, kdfIterations, signal.SendReEncryptionStarted, signal.SendReEncryptionFinished)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt app database")
+	}
+
+	err = sqlite.EncryptDB(filepath.Join(tmpDir, localbackup.WalletDBFile), restoredWalletDBPath, request.Password, kdfIterations, signal.SendReEncryptionStarted, signal.SendReEncryptionFinished)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to encrypt wallet database")
+	}
+
+	// Run the migrations added since the backup was exported
+	appDB, err := appdatabase.InitializeDB(restoredAppDBPath, request.Password, kdfIterations)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to migrate app database")
+	}
+	appDBVersion, _, err := sqlite.GetLastMigrationVersion(appDB)
+	if err != nil {
+		_ = appDB.Close()
+		return nil, err
+	}
+	derivationPaths, err := restoredDerivationPaths(appDB, info.KeyUID)
+	_ = appDB.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	walletDB, err := walletdatabase.InitializeDB(restoredWalletDBPath, request.Password, kdfIterations)
+	if err != nil {
+		return nil, errors.Wrap(err, "failed to migrate wallet database")
+	}
+	walletDBVersion, _, err := sqlite.GetLastMigrationVersion(walletDB)
+	_ = walletDB.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	if appDBVersion < manifest.AppDBVersion || walletDBVersion < manifest.WalletDBVersion {
+		return nil, errors.New("local backup was exported by a newer version")
+	}
+
+	// Nothing was written outside of the temporary directory so far
+	defer func() {
+		if err == nil {
+			return
+		}
+		if len(keyStoreEntries) != 0 {
+			// Don't remove keys that were there before
+			keyStoreDir = ""
+		}
+		if cleanupErr := b.DeleteMultiaccount(info.KeyUID, keyStoreDir); cleanupErr != nil {
+			b.logger.Error("failed to clean up after local backup restore", zap.Error(cleanupErr))
+		}
+	}()
+
+	err = b.storeAccount(info.ID, request.Password, derivationPaths)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(restoredAppDBPath, appDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	err = os.Rename(restoredWalletDBPath, walletDBPath)
+	if err != nil {
+		return nil, err
+	}
+
+	account := manifest.Account
+	account.KDFIterations = kdfIterations
+	err = b.SaveAccount(account)
+	if err != nil {
+		return nil, err
+	}
+
+	err = b.loginAccount(&requests.Login{
+		Password:            request.Password,
+		KeyUID:              info.KeyUID,
+		KdfIterations:       kdfIterations,
+		WalletSecretsConfig: request.WalletSecretsConfig,
+		APIConfig:           request.APIConfig,
+		StatusProxyEnabled:  request.StatusProxyEnabled,
+	})
+	if err != nil {
+		_ = b.StopNode()
+		return nil, err
+	}
+
+	return &account, b.LoggedIn(account.KeyUID, nil)
+}
+
+// restoredDerivationPaths returns the default derivation paths and the ones of the wallet
+// accounts of the profile keypair, whose keys are derived again from the mnemonic
+func restoredDerivationPaths(appDB *sql.DB, keyUID string) ([]string, error) {
+	accountsDB, err := accounts.NewDB(appDB)
+	if err != nil {
+		return nil, err
+	}
+
+	keypair, err := accountsDB.GetKeypairByKeyUID(keyUID)
+	if err != nil {
+		return nil, err
+	}
+
+	derivationPaths := append([]string{}, paths...)
+	for _, account := range keypair.Accounts {
+		if account.Path != "" && !slices.Contains(derivationPaths, account.Path) {
+			derivationPaths = append(derivationPaths, account.Path)
+		}
+	}
+
+	return derivationPaths, nil
+}
+
 func (b *GethStatusBackend) reEncryptKeyStoreDir(currentPassword string, newPassword string) error {
 	config := b.StatusNode().Config()
 	keyDir := ""
@@ -1329,6 +1745,10 @@ func (b *GethStatusBackend) RestoreAccountAndLogin(request *requests.RestoreAcco
 		return nil, err
 	}
 
+	if request.LocalBackupPath != "" {
+		return b.restoreLocalBackupAndLogin(request)
+	}
+
 	response, err := b.generateOrImportAccount(request.Mnemonic, 0, request.FetchBackup, &request.CreateAccount)
 	if err != nil {
 		return nil, err
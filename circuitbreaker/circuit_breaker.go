@@ -182,6 +182,9 @@ func (cb *CircuitBreaker) Execute(cmd *Command) CommandResult {
 				}
 				return err
 			}, nil)
+			if err == hystrix.ErrCircuitOpen {
+				circuitOpenRejectionsCounter.WithLabelValues(circuitName).Inc()
+			}
 		}
 		if err == nil {
 			break
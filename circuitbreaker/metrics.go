@@ -0,0 +1,14 @@
+package circuitbreaker
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var circuitOpenRejectionsCounter = prom.NewCounterVec(prom.CounterOpts{
+	Name: "circuitbreaker_open_rejections_total",
+	Help: "Number of calls skipped because the circuit of the provider was open.",
+}, []string{"circuit"})
+
+func init() {
+	prom.MustRegister(circuitOpenRejectionsCounter)
+}
@@ -0,0 +1,36 @@
+package main
+
+import (
+	"flag"
+	stdlog "log"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/server/pairing/relay"
+)
+
+var address = flag.String("address", "127.0.0.1:8080", "host:port to listen")
+
+func main() {
+	flag.Parse()
+
+	logger, err := zap.NewProduction()
+	if err != nil {
+		stdlog.Fatalf("failed to initialize log: %v", err)
+	}
+	defer func() { _ = logger.Sync() }()
+
+	srv := &http.Server{
+		Addr:              *address,
+		Handler:           relay.NewRelay(logger.Named("pairing-relay")),
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	logger.Info("pairing relay started", zap.String("address", *address))
+	err = srv.ListenAndServe()
+	if err != nil {
+		logger.Error("pairing relay stopped", zap.Error(err))
+	}
+}
@@ -35,3 +35,19 @@ func (f *configFlags) Set(value string) error {
 	*f = append(*f, value)
 	return nil
 }
+
+// topicsFlag represents a comma-separated list of topics passed to a command line utility
+type topicsFlag []string
+
+func (f *topicsFlag) String() string {
+	return strings.Join(*f, ",")
+}
+
+func (f *topicsFlag) Set(value string) error {
+	for _, topic := range strings.Split(value, ",") {
+		if topic = strings.TrimSpace(topic); topic != "" {
+			*f = append(*f, topic)
+		}
+	}
+	return nil
+}
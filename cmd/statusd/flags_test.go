@@ -94,3 +94,11 @@ func TestStatusFlag(t *testing.T) {
 		}
 	}
 }
+
+func TestTopicsFlag(t *testing.T) {
+	var topics topicsFlag
+	require.NoError(t, topics.Set("/waku/2/rs/16/32, /waku/2/rs/16/64,"))
+	require.NoError(t, topics.Set("/waku/2/rs/16/128"))
+	require.Equal(t, topicsFlag{"/waku/2/rs/16/32", "/waku/2/rs/16/64", "/waku/2/rs/16/128"}, topics)
+	require.Equal(t, "/waku/2/rs/16/32,/waku/2/rs/16/64,/waku/2/rs/16/128", topics.String())
+}
@@ -0,0 +1,110 @@
+// Package diagnostics collects the state of a node into a single zip archive users can attach
+// to bug reports. All the contents of the archive go through a redaction pass which removes
+// secrets, e.g. passwords, private keys, mnemonics and API keys, and personal data, e.g. chat
+// public keys, emails and IP addresses.
+package diagnostics
+
+import (
+	"archive/zip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+// ErrorsFile lists the parts of the bundle that couldn't be collected
+const ErrorsFile = "errors.txt"
+
+type entry struct {
+	name string
+	data []byte
+}
+
+// Bundle is a set of named files written into a zip archive.
+// Failures to collect a part are recorded rather than aborting the export,
+// so that a bundle is produced even when the node is partially broken.
+type Bundle struct {
+	entries []entry
+	errors  []string
+}
+
+func NewBundle() *Bundle {
+	return &Bundle{}
+}
+
+// AddFile adds a file, its content is redacted when the archive is written
+func (b *Bundle) AddFile(name string, data []byte) {
+	b.entries = append(b.entries, entry{name: name, data: data})
+}
+
+// AddJSON adds v as an indented JSON file, the values of sensitive keys are redacted
+func (b *Bundle) AddJSON(name string, v interface{}) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		b.AddError(name, err)
+		return
+	}
+
+	data, err = redaction.JSON(data, redaction.Full)
+	if err != nil {
+		b.AddError(name, err)
+		return
+	}
+	b.AddFile(name, data)
+}
+
+// AddError records that the part name couldn't be collected
+func (b *Bundle) AddError(name string, err error) {
+	b.errors = append(b.errors, fmt.Sprintf("%s: %v", name, err))
+}
+
+// Errors returns the failures recorded so far
+func (b *Bundle) Errors() []string {
+	return b.errors
+}
+
+// WriteZip redacts the files of the bundle and writes them into a zip archive
+func (b *Bundle) WriteZip(w io.Writer) error {
+	archive := zip.NewWriter(w)
+	now := time.Now()
+
+	entries := b.entries
+	if len(b.errors) > 0 {
+		entries = append(entries, entry{name: ErrorsFile, data: []byte(strings.Join(b.errors, "\n") + "\n")})
+	}
+
+	for _, e := range entries {
+		file, err := archive.CreateHeader(&zip.FileHeader{
+			Name:     e.name,
+			Method:   zip.Deflate,
+			Modified: now,
+		})
+		if err != nil {
+			return err
+		}
+		if _, err = file.Write(redaction.Text(e.data, redaction.Full)); err != nil {
+			return err
+		}
+	}
+
+	return archive.Close()
+}
+
+// WriteFile writes the bundle into a zip archive at path
+func (b *Bundle) WriteFile(path string) (err error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_TRUNC|os.O_WRONLY, 0600)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if closeErr := file.Close(); err == nil {
+			err = closeErr
+		}
+	}()
+
+	return b.WriteZip(file)
+}
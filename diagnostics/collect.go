@@ -0,0 +1,201 @@
+package diagnostics
+
+import (
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/status-im/status-go/profiling"
+)
+
+const (
+	logsDir     = "logs"
+	profilesDir = "profiles"
+
+	// maxLogSize is the size of the end of each log kept in the bundle
+	maxLogSize = 16 * 1024 * 1024
+	// maxLogsSize is the size of all the logs kept in the bundle, the most recent ones are kept first
+	maxLogsSize = 64 * 1024 * 1024
+)
+
+var ErrLogsTooLarge = errors.New("skipped, the logs kept in the bundle are already too large")
+
+// CollectLogs adds logFile and its rotated backups to the bundle, compressed backups are decompressed.
+// Only the end of each log is kept, and older backups are skipped once maxLogsSize is reached.
+func CollectLogs(b *Bundle, logFile string) {
+	files, err := logFiles(logFile)
+	if err != nil {
+		b.AddError(path.Join(logsDir, filepath.Base(logFile)), err)
+		return
+	}
+
+	remaining := maxLogsSize
+	for i := len(files) - 1; i >= 0; i-- {
+		name := path.Join(logsDir, strings.TrimSuffix(filepath.Base(files[i]), ".gz"))
+		if remaining <= 0 {
+			b.AddError(name, ErrLogsTooLarge)
+			continue
+		}
+
+		data, err := readLog(files[i], min(maxLogSize, remaining))
+		if err != nil {
+			b.AddError(name, err)
+			continue
+		}
+		remaining -= len(data)
+		b.AddFile(name, data)
+	}
+}
+
+// logFiles returns logFile and the backups made by the log rotation, which are
+// named <name>-<timestamp><ext> next to it and gzipped when compression is enabled
+func logFiles(logFile string) ([]string, error) {
+	if _, err := os.Stat(logFile); err != nil {
+		return nil, err
+	}
+
+	ext := filepath.Ext(logFile)
+	prefix := strings.TrimSuffix(logFile, ext) + "-"
+
+	var files []string
+	for _, pattern := range []string{prefix + "*" + ext, prefix + "*" + ext + ".gz"} {
+		matches, err := filepath.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, matches...)
+	}
+	sort.Strings(files)
+
+	return append(files, logFile), nil
+}
+
+// readLog streams a log, decompressing it if needed, and returns its last maxSize bytes
+func readLog(file string, maxSize int) ([]byte, error) {
+	f, err := os.Open(file)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	tail := &tailWriter{maxSize: maxSize}
+	var reader io.Reader = f
+	if strings.HasSuffix(file, ".gz") {
+		gzipReader, err := gzip.NewReader(f)
+		if err != nil {
+			return nil, err
+		}
+		defer gzipReader.Close()
+		reader = gzipReader
+	} else if info, err := f.Stat(); err == nil && info.Size() > int64(maxSize) {
+		// Plain logs don't need to be read from the start
+		if _, err := f.Seek(info.Size()-int64(maxSize), io.SeekStart); err != nil {
+			return nil, err
+		}
+		tail.truncated = true
+	}
+
+	if _, err := io.Copy(tail, reader); err != nil {
+		return nil, err
+	}
+	return tail.Bytes(), nil
+}
+
+// tailWriter keeps the last maxSize bytes written to it, it buffers up to twice as much
+// so that the kept bytes are only moved once in a while
+type tailWriter struct {
+	maxSize   int
+	buf       []byte
+	truncated bool
+}
+
+func (w *tailWriter) Write(p []byte) (int, error) {
+	w.buf = append(w.buf, p...)
+	if len(w.buf) > 2*w.maxSize {
+		w.buf = append(w.buf[:0], w.buf[len(w.buf)-w.maxSize:]...)
+		w.truncated = true
+	}
+	return len(p), nil
+}
+
+// Bytes returns the kept bytes, starting from the first complete line if the start was dropped
+func (w *tailWriter) Bytes() []byte {
+	if len(w.buf) > w.maxSize {
+		w.buf = w.buf[len(w.buf)-w.maxSize:]
+		w.truncated = true
+	}
+	if !w.truncated {
+		return w.buf
+	}
+	if i := bytes.IndexByte(w.buf, '\n'); i >= 0 {
+		return w.buf[i+1:]
+	}
+	return w.buf
+}
+
+// CollectProfiles adds the stacks of all goroutines and a heap profile, both as text
+func CollectProfiles(b *Bundle) {
+	var goroutines bytes.Buffer
+	if err := profiling.WriteGoroutines(&goroutines); err != nil {
+		b.AddError(path.Join(profilesDir, "goroutines.txt"), err)
+	} else {
+		b.AddFile(path.Join(profilesDir, "goroutines.txt"), goroutines.Bytes())
+	}
+
+	var heap bytes.Buffer
+	if err := profiling.WriteHeap(&heap, 1); err != nil {
+		b.AddError(path.Join(profilesDir, "heap.txt"), err)
+	} else {
+		b.AddFile(path.Join(profilesDir, "heap.txt"), heap.Bytes())
+	}
+}
+
+// SchemaVersion is the state of a set of migrations applied to a database
+type SchemaVersion struct {
+	Table   string `json:"table"`
+	Version uint   `json:"version"`
+	Dirty   bool   `json:"dirty"`
+}
+
+// SchemaVersions returns the version of each migration set applied to db,
+// each set keeps its version in its own schema_migrations table
+func SchemaVersions(db *sql.DB) ([]SchemaVersion, error) {
+	rows, err := db.Query(`SELECT name FROM sqlite_master WHERE type = 'table' AND name LIKE '%schema_migrations' ORDER BY name`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var tables []string
+	for rows.Next() {
+		var table string
+		if err := rows.Scan(&table); err != nil {
+			return nil, err
+		}
+		tables = append(tables, table)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	versions := make([]SchemaVersion, 0, len(tables))
+	for _, table := range tables {
+		version := SchemaVersion{Table: table}
+		// table names come from sqlite_master, they can't be bound as parameters
+		err := db.QueryRow(fmt.Sprintf(`SELECT version, dirty FROM "%s" LIMIT 1`, table)).Scan(&version.Version, &version.Dirty)
+		if err != nil && err != sql.ErrNoRows {
+			return nil, err
+		}
+		versions = append(versions, version)
+	}
+
+	return versions, nil
+}
@@ -0,0 +1,140 @@
+package diagnostics
+
+import (
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"database/sql"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	_ "github.com/mutecomm/go-sqlcipher/v4" // require go-sqlcipher that overrides default implementation
+)
+
+func readZip(t *testing.T, data []byte) map[string]string {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	require.NoError(t, err)
+
+	files := make(map[string]string)
+	for _, file := range reader.File {
+		f, err := file.Open()
+		require.NoError(t, err)
+		content, err := io.ReadAll(f)
+		require.NoError(t, err)
+		require.NoError(t, f.Close())
+		files[file.Name] = string(content)
+	}
+	return files
+}
+
+func TestBundleWriteZip(t *testing.T) {
+	b := NewBundle()
+	b.AddFile("notes.txt", []byte("reach me at bob@example.com"))
+	b.AddJSON("config.json", map[string]string{"NodeKey": "secret", "Name": "node"})
+	b.AddError("wallet.json", errors.New("wallet is not running"))
+
+	var buf bytes.Buffer
+	require.NoError(t, b.WriteZip(&buf))
+
+	files := readZip(t, buf.Bytes())
+	require.Len(t, files, 3)
+	require.Equal(t, "reach me at [REDACTED:email]", files["notes.txt"])
+	require.Contains(t, files["config.json"], `"NodeKey": "[REDACTED]"`)
+	require.Contains(t, files["config.json"], `"Name": "node"`)
+	require.Equal(t, "wallet.json: wallet is not running\n", files[ErrorsFile])
+}
+
+func TestCollectLogs(t *testing.T) {
+	dir := t.TempDir()
+	logFile := filepath.Join(dir, "geth.log")
+	require.NoError(t, os.WriteFile(logFile, []byte("current"), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "geth-2024-01-01T00-00-00.000.log"), []byte("rotated"), 0600))
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte("compressed"))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "geth-2023-01-01T00-00-00.000.log.gz"), compressed.Bytes(), 0600))
+
+	b := NewBundle()
+	CollectLogs(b, logFile)
+	require.Empty(t, b.Errors())
+
+	var buf bytes.Buffer
+	require.NoError(t, b.WriteZip(&buf))
+
+	files := readZip(t, buf.Bytes())
+	require.Equal(t, map[string]string{
+		"logs/geth.log":                         "current",
+		"logs/geth-2024-01-01T00-00-00.000.log": "rotated",
+		"logs/geth-2023-01-01T00-00-00.000.log": "compressed",
+	}, files)
+}
+
+func TestReadLogKeepsTheEnd(t *testing.T) {
+	dir := t.TempDir()
+	lines := "first line\nsecond line\nthird line\n"
+
+	plain := filepath.Join(dir, "geth.log")
+	require.NoError(t, os.WriteFile(plain, []byte(lines), 0600))
+
+	var compressed bytes.Buffer
+	writer := gzip.NewWriter(&compressed)
+	_, err := writer.Write([]byte(lines))
+	require.NoError(t, err)
+	require.NoError(t, writer.Close())
+	gzipped := filepath.Join(dir, "geth-2023-01-01T00-00-00.000.log.gz")
+	require.NoError(t, os.WriteFile(gzipped, compressed.Bytes(), 0600))
+
+	for _, file := range []string{plain, gzipped} {
+		// The partial line at the start is dropped
+		data, err := readLog(file, 15)
+		require.NoError(t, err)
+		require.Equal(t, "third line\n", string(data), file)
+
+		data, err = readLog(file, len(lines))
+		require.NoError(t, err)
+		require.Equal(t, lines, string(data), file)
+	}
+
+	tail := &tailWriter{maxSize: 4}
+	for _, chunk := range []string{"ab\n", "cd\n", "efg", "\nhi"} {
+		_, err := tail.Write([]byte(chunk))
+		require.NoError(t, err)
+	}
+	require.Equal(t, "hi", string(tail.Bytes()))
+}
+
+func TestCollectLogsMissingFile(t *testing.T) {
+	b := NewBundle()
+	CollectLogs(b, filepath.Join(t.TempDir(), "geth.log"))
+	require.Len(t, b.Errors(), 1)
+}
+
+func TestSchemaVersions(t *testing.T) {
+	db, err := sql.Open("sqlite3", ":memory:")
+	require.NoError(t, err)
+	defer db.Close()
+
+	_, err = db.Exec(`
+		CREATE TABLE status_go_schema_migrations (version uint64, dirty bool);
+		INSERT INTO status_go_schema_migrations VALUES (1700000000, 0);
+		CREATE TABLE status_protocol_go_schema_migrations (version uint64, dirty bool);
+		INSERT INTO status_protocol_go_schema_migrations VALUES (42, 1);
+		CREATE TABLE settings (name TEXT);
+	`)
+	require.NoError(t, err)
+
+	versions, err := SchemaVersions(db)
+	require.NoError(t, err)
+	require.Equal(t, []SchemaVersion{
+		{Table: "status_go_schema_migrations", Version: 1700000000},
+		{Table: "status_protocol_go_schema_migrations", Version: 42, Dirty: true},
+	}, versions)
+}
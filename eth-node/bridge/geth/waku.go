@@ -364,3 +364,15 @@ func (w *GethWakuWrapper) PerformStorenodeTask(fn func() error, opts ...history.
 func (w *GethWakuWrapper) DisconnectActiveStorenode(ctx context.Context, backoff time.Duration, shouldCycle bool) {
 	panic("not available in WakuV1")
 }
+
+func (w *GethWakuWrapper) PingStorenode(ctx context.Context, peerInfo peer.AddrInfo) (time.Duration, error) {
+	return 0, errors.New("not available in WakuV1")
+}
+
+func (w *GethWakuWrapper) RecentMessageHashes(pubsubTopic string, from time.Time, to time.Time, limit int) []common.Hash {
+	return nil
+}
+
+func (w *GethWakuWrapper) CountStoredMessages(ctx context.Context, peerID peer.ID, hashes []common.Hash) (int, error) {
+	return 0, errors.New("not available in WakuV1")
+}
@@ -354,7 +354,15 @@ func (w *gethWakuV2Wrapper) ProcessMailserverBatch(
 		ContentFilter: protocol.NewContentFilter(pubsubTopic, contentTopics...),
 	}
 
-	return w.waku.HistoryRetriever.Query(ctx, criteria, storenodeID, pageLimit, shouldProcessNextPage, processEnvelopes)
+	start := time.Now()
+	err := w.waku.HistoryRetriever.Query(ctx, criteria, storenodeID, pageLimit, shouldProcessNextPage, processEnvelopes)
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	wakucommon.StoreQueryDurationMeter.WithLabelValues(result).Observe(time.Since(start).Seconds())
+
+	return err
 }
 
 func (w *gethWakuV2Wrapper) IsStorenodeAvailable(peerID peer.ID) bool {
@@ -374,3 +382,15 @@ func (w *gethWakuV2Wrapper) DisconnectActiveStorenode(ctx context.Context, backo
 		w.waku.StorenodeCycle.Cycle(ctx)
 	}
 }
+
+func (w *gethWakuV2Wrapper) PingStorenode(ctx context.Context, peerInfo peer.AddrInfo) (time.Duration, error) {
+	return w.waku.PingStorenode(ctx, peerInfo)
+}
+
+func (w *gethWakuV2Wrapper) RecentMessageHashes(pubsubTopic string, from time.Time, to time.Time, limit int) []common.Hash {
+	return w.waku.RecentMessageHashes(pubsubTopic, from, to, limit)
+}
+
+func (w *gethWakuV2Wrapper) CountStoredMessages(ctx context.Context, peerID peer.ID, hashes []common.Hash) (int, error) {
+	return w.waku.CountStoredMessages(ctx, peerID, hashes)
+}
@@ -234,6 +234,16 @@ type Waku interface {
 
 	// DisconnectActiveStorenode will trigger a disconnection of the active storenode, and potentially execute a cycling so a new storenode is promoted
 	DisconnectActiveStorenode(ctx context.Context, backoff time.Duration, shouldCycle bool)
+
+	// PingStorenode returns the round trip time to a storenode
+	PingStorenode(ctx context.Context, peerInfo peer.AddrInfo) (time.Duration, error)
+
+	// RecentMessageHashes returns up to limit hashes of messages received via relay with a timestamp between from and to,
+	// on pubsubTopic or on any pubsub topic if it is empty
+	RecentMessageHashes(pubsubTopic string, from time.Time, to time.Time, limit int) []common.Hash
+
+	// CountStoredMessages returns how many of the messages are stored by a storenode
+	CountStoredMessages(ctx context.Context, peerID peer.ID, hashes []common.Hash) (int, error)
 }
 
 type MailserverBatch struct {
@@ -0,0 +1,400 @@
+package images
+
+import (
+	"bytes"
+	"errors"
+	"image"
+	"image/color"
+	"image/color/palette"
+	"image/draw"
+	"image/gif"
+
+	"github.com/nfnt/resize"
+)
+
+const (
+	// maxAnimationPixels bounds the pixels of the frames of an animation once composited,
+	// frames are kept in memory while they're cropped, resized and encoded
+	maxAnimationPixels = 32 * 1024 * 1024
+	// maxAnimationDimension bounds the width and the height of the canvas of an animation
+	maxAnimationDimension = 4096
+
+	// animationTransparency is the index of the transparent color of animationPalette
+	animationTransparency = 0
+)
+
+var (
+	ErrNotAnimated       = errors.New("image is not animated")
+	ErrAnimationTooLarge = errors.New("animation is too large")
+	ErrInvalidAnimation  = errors.New("invalid animation")
+
+	opaquePalette    = color.Palette(palette.Plan9[:255])
+	animationPalette = append(color.Palette{color.Transparent}, opaquePalette...)
+)
+
+// Animation is an animated image, its frames are composited so that each of them is the full canvas
+type Animation struct {
+	Frames []image.Image
+	// Delays are the times each frame is displayed, in 100ths of a second
+	Delays []int
+	// LoopCount follows the GIF convention: 0 loops forever, -1 plays once, n plays n+1 times
+	LoopCount int
+}
+
+// IsAnimated returns whether buf is a GIF or WebP of several frames
+func IsAnimated(buf []byte) bool {
+	switch GetType(buf) {
+	case GIF:
+		frames, err := gifFrameCount(buf)
+		return err == nil && frames > 1
+	case WEBP:
+		return isAnimatedWebP(buf)
+	default:
+		return false
+	}
+}
+
+// DecodeAnimation decodes an animated GIF or WebP, ErrNotAnimated is returned for still images
+func DecodeAnimation(buf []byte) (*Animation, error) {
+	var anim *Animation
+	var err error
+	switch GetType(buf) {
+	case GIF:
+		anim, err = decodeGIFAnimation(buf)
+	case WEBP:
+		anim, err = decodeWebPAnimation(buf)
+	default:
+		return nil, ErrNotAnimated
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(anim.Frames) < 2 {
+		return nil, ErrNotAnimated
+	}
+	return anim, nil
+}
+
+func checkAnimationSize(frames int, canvas image.Rectangle) error {
+	if canvas.Empty() {
+		return ErrInvalidAnimation
+	}
+	if canvas.Dx() > maxAnimationDimension || canvas.Dy() > maxAnimationDimension {
+		return ErrAnimationTooLarge
+	}
+	// in int64, as it'd overflow an int on 32 bits platforms
+	if int64(frames)*int64(canvas.Dx())*int64(canvas.Dy()) > maxAnimationPixels {
+		return ErrAnimationTooLarge
+	}
+	return nil
+}
+
+func cloneRGBA(img *image.RGBA) *image.RGBA {
+	clone := image.NewRGBA(img.Bounds())
+	copy(clone.Pix, img.Pix)
+	return clone
+}
+
+// gifFrameCount counts the frames of a GIF by walking its blocks, without decoding them
+func gifFrameCount(buf []byte) (int, error) {
+	// header and logical screen descriptor
+	pos := 13
+	if len(buf) < pos {
+		return 0, ErrInvalidAnimation
+	}
+	if buf[10]&0x80 != 0 {
+		pos += 3 << (buf[10]&0x07 + 1)
+	}
+
+	frames := 0
+	for pos < len(buf) {
+		var err error
+		switch buf[pos] {
+		case 0x21:
+			// extension, its label then its data sub-blocks
+			pos, err = skipGIFSubBlocks(buf, pos+2)
+		case 0x2c:
+			// image descriptor, its local color table, the LZW minimum code size then its data sub-blocks
+			if pos+10 > len(buf) {
+				return 0, ErrInvalidAnimation
+			}
+			packed := buf[pos+9]
+			pos += 10
+			if packed&0x80 != 0 {
+				pos += 3 << (packed&0x07 + 1)
+			}
+			pos, err = skipGIFSubBlocks(buf, pos+1)
+			frames++
+		case 0x3b:
+			return frames, nil
+		default:
+			return 0, ErrInvalidAnimation
+		}
+		if err != nil {
+			return 0, err
+		}
+	}
+	return 0, ErrInvalidAnimation
+}
+
+func skipGIFSubBlocks(buf []byte, pos int) (int, error) {
+	for pos < len(buf) {
+		size := int(buf[pos])
+		pos++
+		if size == 0 {
+			return pos, nil
+		}
+		pos += size
+	}
+	return 0, ErrInvalidAnimation
+}
+
+func decodeGIFAnimation(buf []byte) (*Animation, error) {
+	// the size of the animation is checked before its frames are decoded
+	config, err := gif.DecodeConfig(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	frames, err := gifFrameCount(buf)
+	if err != nil {
+		return nil, err
+	}
+	if frames < 2 {
+		return nil, ErrNotAnimated
+	}
+	bounds := image.Rect(0, 0, config.Width, config.Height)
+	if err := checkAnimationSize(frames, bounds); err != nil {
+		return nil, err
+	}
+
+	g, err := gif.DecodeAll(bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	if len(g.Image) < 2 {
+		return nil, ErrNotAnimated
+	}
+
+	anim := &Animation{LoopCount: g.LoopCount}
+	canvas := image.NewRGBA(bounds)
+	for i, frame := range g.Image {
+		var disposal byte
+		if i < len(g.Disposal) {
+			disposal = g.Disposal[i]
+		}
+
+		var previous *image.RGBA
+		if disposal == gif.DisposalPrevious {
+			previous = cloneRGBA(canvas)
+		}
+
+		draw.Draw(canvas, frame.Bounds(), frame, frame.Bounds().Min, draw.Over)
+		anim.Frames = append(anim.Frames, cloneRGBA(canvas))
+		anim.Delays = append(anim.Delays, g.Delay[i])
+
+		switch disposal {
+		case gif.DisposalBackground:
+			draw.Draw(canvas, frame.Bounds(), image.Transparent, image.Point{}, draw.Src)
+		case gif.DisposalPrevious:
+			canvas = previous
+		}
+	}
+
+	return anim, nil
+}
+
+// apply returns the animation with f applied to each of its frames
+func (a *Animation) apply(f func(image.Image) (image.Image, error)) (*Animation, error) {
+	frames := make([]image.Image, len(a.Frames))
+	for i, frame := range a.Frames {
+		var err error
+		frames[i], err = f(frame)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &Animation{Frames: frames, Delays: a.Delays, LoopCount: a.LoopCount}, nil
+}
+
+// Crop crops every frame of the animation to rect
+func (a *Animation) Crop(rect image.Rectangle) (*Animation, error) {
+	return a.apply(func(frame image.Image) (image.Image, error) {
+		return Crop(frame, rect)
+	})
+}
+
+// CropCenter crops every frame of the animation to its largest central square
+func (a *Animation) CropCenter() (*Animation, error) {
+	return a.apply(CropCenter)
+}
+
+// Resize resizes every frame of the animation as Resize does
+func (a *Animation) Resize(size ResizeDimension) *Animation {
+	width, height := resizeDimensions(size, a.Frames[0])
+	anim, _ := a.apply(func(frame image.Image) (image.Image, error) {
+		return resize.Resize(width, height, frame, resize.Bilinear), nil
+	})
+	return anim
+}
+
+// ShrinkOnly resizes every frame of the animation as ShrinkOnly does
+func (a *Animation) ShrinkOnly(size ResizeDimension) *Animation {
+	bounds := a.Frames[0].Bounds()
+	if int(size) > bounds.Dx() {
+		size = ResizeDimension(bounds.Dx())
+	}
+	if int(size) > bounds.Dy() {
+		size = ResizeDimension(bounds.Dy())
+	}
+	return a.Resize(size)
+}
+
+// EncodeAnimationToLimit encodes an animation to a GIF of at most maxSize bytes. Every other
+// frame is dropped, its delay given to the previous frame, until the GIF fits. A FileSizeError
+// is returned when it still doesn't fit once the animation is down to two frames.
+func EncodeAnimationToLimit(bb *bytes.Buffer, anim *Animation, maxSize int) error {
+	if len(anim.Frames) == 0 {
+		return ErrInvalidAnimation
+	}
+
+	frames := quantizeFrames(anim.Frames)
+	delays := anim.Delays
+	for {
+		bb.Reset()
+		err := encodeGIFAnimation(bb, frames, delays, anim.LoopCount)
+		if err != nil {
+			return err
+		}
+		if bb.Len() <= maxSize {
+			return nil
+		}
+		if len(frames) <= 2 {
+			return &FileSizeError{expected: maxSize, received: bb.Len()}
+		}
+		frames, delays = dropEveryOtherFrame(frames, delays)
+	}
+}
+
+func dropEveryOtherFrame(frames []*image.Paletted, delays []int) ([]*image.Paletted, []int) {
+	keptFrames := make([]*image.Paletted, 0, (len(frames)+1)/2)
+	keptDelays := make([]int, 0, (len(frames)+1)/2)
+	for i := 0; i < len(frames); i += 2 {
+		delay := delays[i]
+		if i+1 < len(frames) {
+			delay += delays[i+1]
+		}
+		keptFrames = append(keptFrames, frames[i])
+		keptDelays = append(keptDelays, delay)
+	}
+	return keptFrames, keptDelays
+}
+
+// quantizeFrames maps the frames to a palette shared by all of them, so that it's stored once in the GIF
+func quantizeFrames(frames []image.Image) []*image.Paletted {
+	indexes := make(map[color.RGBA]uint8)
+	quantized := make([]*image.Paletted, len(frames))
+	for i, frame := range frames {
+		bounds := frame.Bounds()
+		paletted := image.NewPaletted(image.Rect(0, 0, bounds.Dx(), bounds.Dy()), animationPalette)
+		for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+			for x := bounds.Min.X; x < bounds.Max.X; x++ {
+				c := color.RGBAModel.Convert(frame.At(x, y)).(color.RGBA)
+				index, ok := indexes[c]
+				if !ok {
+					if c.A < 128 {
+						index = animationTransparency
+					} else {
+						index = uint8(opaquePalette.Index(c) + 1)
+					}
+					indexes[c] = index
+				}
+				paletted.SetColorIndex(x-bounds.Min.X, y-bounds.Min.Y, index)
+			}
+		}
+		quantized[i] = paletted
+	}
+	return quantized
+}
+
+// encodeGIFAnimation writes full frames. When frames are opaque, the pixels unchanged
+// since the previous frame are made transparent, which compresses much better.
+func encodeGIFAnimation(bb *bytes.Buffer, frames []*image.Paletted, delays []int, loopCount int) error {
+	bounds := frames[0].Bounds()
+	g := &gif.GIF{
+		LoopCount: loopCount,
+		Config: image.Config{
+			ColorModel: animationPalette,
+			Width:      bounds.Dx(),
+			Height:     bounds.Dy(),
+		},
+	}
+
+	opaque := true
+	for _, frame := range frames {
+		if bytes.IndexByte(frame.Pix, animationTransparency) != -1 {
+			opaque = false
+			break
+		}
+	}
+
+	for i, frame := range frames {
+		if !opaque {
+			g.Image = append(g.Image, frame)
+			g.Delay = append(g.Delay, delays[i])
+			g.Disposal = append(g.Disposal, gif.DisposalBackground)
+			continue
+		}
+
+		if i == 0 {
+			g.Image = append(g.Image, frame)
+			g.Delay = append(g.Delay, delays[i])
+			g.Disposal = append(g.Disposal, gif.DisposalNone)
+			continue
+		}
+
+		delta := frameDelta(frames[i-1], frame)
+		if delta == nil {
+			// the frame is the same as the previous one
+			g.Delay[len(g.Delay)-1] += delays[i]
+			continue
+		}
+		g.Image = append(g.Image, delta)
+		g.Delay = append(g.Delay, delays[i])
+		g.Disposal = append(g.Disposal, gif.DisposalNone)
+	}
+
+	return gif.EncodeAll(bb, g)
+}
+
+// frameDelta returns the smallest part of frame that differs from previous, with the pixels
+// that don't differ made transparent, or nil if the frames are the same
+func frameDelta(previous, frame *image.Paletted) *image.Paletted {
+	bounds := frame.Bounds()
+	changed := image.Rectangle{Min: bounds.Max, Max: bounds.Min}
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			if previous.ColorIndexAt(x, y) == frame.ColorIndexAt(x, y) {
+				continue
+			}
+			changed.Min.X = min(changed.Min.X, x)
+			changed.Min.Y = min(changed.Min.Y, y)
+			changed.Max.X = max(changed.Max.X, x+1)
+			changed.Max.Y = max(changed.Max.Y, y+1)
+		}
+	}
+	if changed.Empty() {
+		return nil
+	}
+
+	delta := image.NewPaletted(changed, animationPalette)
+	for y := changed.Min.Y; y < changed.Max.Y; y++ {
+		for x := changed.Min.X; x < changed.Max.X; x++ {
+			index := frame.ColorIndexAt(x, y)
+			if previous.ColorIndexAt(x, y) == index {
+				index = animationTransparency
+			}
+			delta.SetColorIndex(x, y, index)
+		}
+	}
+	return delta
+}
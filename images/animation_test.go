@@ -0,0 +1,266 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"image/gif"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+var (
+	red  = color.RGBA{R: 0xff, A: 0xff}
+	blue = color.RGBA{B: 0xff, A: 0xff}
+)
+
+func makeTestGIF(t *testing.T) []byte {
+	framePalette := color.Palette{color.Transparent, red, blue}
+
+	background := image.NewPaletted(image.Rect(0, 0, 20, 10), framePalette)
+	for i := range background.Pix {
+		background.Pix[i] = 1
+	}
+	// only covers the left half of the canvas, the rest being kept from the previous frame
+	square := image.NewPaletted(image.Rect(0, 0, 10, 10), framePalette)
+	for i := range square.Pix {
+		square.Pix[i] = 2
+	}
+
+	bb := bytes.NewBuffer([]byte{})
+	err := gif.EncodeAll(bb, &gif.GIF{
+		Image:    []*image.Paletted{background, square},
+		Delay:    []int{10, 20},
+		Disposal: []byte{gif.DisposalNone, gif.DisposalNone},
+		Config:   image.Config{ColorModel: framePalette, Width: 20, Height: 10},
+	})
+	require.NoError(t, err)
+	return bb.Bytes()
+}
+
+func TestDecodeAnimation_GIF(t *testing.T) {
+	buf := makeTestGIF(t)
+	require.True(t, IsAnimated(buf))
+
+	anim, err := DecodeAnimation(buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Frames, 2)
+	require.Equal(t, []int{10, 20}, anim.Delays)
+	require.Equal(t, image.Rect(0, 0, 20, 10), anim.Frames[1].Bounds())
+
+	require.Equal(t, red, color.RGBAModel.Convert(anim.Frames[0].At(5, 5)))
+	require.Equal(t, blue, color.RGBAModel.Convert(anim.Frames[1].At(5, 5)))
+	require.Equal(t, red, color.RGBAModel.Convert(anim.Frames[1].At(15, 5)))
+}
+
+func TestGIFFrameCount(t *testing.T) {
+	buf := makeTestGIF(t)
+	frames, err := gifFrameCount(buf)
+	require.NoError(t, err)
+	require.Equal(t, 2, frames)
+
+	still := bytes.NewBuffer([]byte{})
+	require.NoError(t, gif.Encode(still, image.NewPaletted(image.Rect(0, 0, 4, 4), color.Palette{red, blue}), nil))
+	frames, err = gifFrameCount(still.Bytes())
+	require.NoError(t, err)
+	require.Equal(t, 1, frames)
+
+	_, err = gifFrameCount(buf[:len(buf)-10])
+	require.ErrorIs(t, err, ErrInvalidAnimation)
+}
+
+func TestDecodeAnimation_GIFTooLarge(t *testing.T) {
+	buf := makeTestGIF(t)
+	// claims a canvas of 65535x65535 without its frames being any larger
+	binary.LittleEndian.PutUint16(buf[6:8], 0xffff)
+	binary.LittleEndian.PutUint16(buf[8:10], 0xffff)
+
+	_, err := DecodeAnimation(buf)
+	require.ErrorIs(t, err, ErrAnimationTooLarge)
+}
+
+func TestDecodeAnimation_NotAnimated(t *testing.T) {
+	for _, file := range []string{"status.png", "rose.webp"} {
+		buf, err := os.ReadFile(path + file)
+		require.NoError(t, err)
+		require.False(t, IsAnimated(buf))
+
+		_, err = DecodeAnimation(buf)
+		require.ErrorIs(t, err, ErrNotAnimated)
+	}
+}
+
+// makeTestWebP makes an animated WebP of the rose, with a lossless frame of a single pixel drawn over its corner
+func makeTestWebP(t *testing.T) []byte {
+	rose, err := os.ReadFile(path + "rose.webp")
+	require.NoError(t, err)
+	pixel, err := os.ReadFile(path + "1.gif")
+	require.NoError(t, err)
+
+	roseChunks, err := webpChunks(rose)
+	require.NoError(t, err)
+	pixelChunks, err := webpChunks(pixel)
+	require.NoError(t, err)
+
+	anmf := func(width, height, duration int, flags byte, chunks []riffChunk) []byte {
+		header := make([]byte, anmfHeaderSize)
+		putUint24(header[6:9], width-1)
+		putUint24(header[9:12], height-1)
+		putUint24(header[12:15], duration)
+		header[15] = flags
+		for _, chunk := range chunks {
+			if chunk.fourCC != "VP8X" {
+				header = appendRIFFChunk(header, chunk.fourCC, chunk.data)
+			}
+		}
+		return header
+	}
+
+	// the canvas is the size of the rose, 400x301
+	vp8x := make([]byte, 10)
+	vp8x[0] = webpAnimationFlag | webpAlphaFlag
+	copy(vp8x[4:10], roseChunks[0].data[4:10])
+
+	anim := make([]byte, 6)
+	binary.LittleEndian.PutUint16(anim[4:6], 3)
+
+	var body []byte
+	body = appendRIFFChunk(body, "VP8X", vp8x)
+	body = appendRIFFChunk(body, "ANIM", anim)
+	body = appendRIFFChunk(body, "ANMF", anmf(400, 301, 100, 0, roseChunks))
+	body = appendRIFFChunk(body, "ANMF", anmf(1, 1, 250, anmfNoBlendFlag, pixelChunks))
+
+	container := []byte("RIFF")
+	container = binary.LittleEndian.AppendUint32(container, uint32(4+len(body)))
+	container = append(container, "WEBP"...)
+	return append(container, body...)
+}
+
+func TestDecodeAnimation_WebP(t *testing.T) {
+	buf := makeTestWebP(t)
+	require.True(t, IsAnimated(buf))
+
+	anim, err := DecodeAnimation(buf)
+	require.NoError(t, err)
+	require.Len(t, anim.Frames, 2)
+	require.Equal(t, []int{10, 25}, anim.Delays)
+	require.Equal(t, 2, anim.LoopCount)
+	require.Equal(t, image.Rect(0, 0, 400, 301), anim.Frames[1].Bounds())
+
+	rose, err := os.Open(path + "rose.webp")
+	require.NoError(t, err)
+	defer rose.Close()
+	roseImg, err := webp.Decode(rose)
+	require.NoError(t, err)
+
+	pixel, err := os.Open(path + "1.gif")
+	require.NoError(t, err)
+	defer pixel.Close()
+	pixelImg, err := webp.Decode(pixel)
+	require.NoError(t, err)
+
+	require.Equal(t, color.RGBAModel.Convert(pixelImg.At(0, 0)), anim.Frames[1].At(0, 0))
+	require.Equal(t, anim.Frames[0].At(200, 150), anim.Frames[1].At(200, 150))
+	// the rose is opaque in its center
+	_, _, _, a := roseImg.At(200, 150).RGBA()
+	require.Equal(t, uint32(0xffff), a)
+}
+
+func TestDecodeWebPFrame_SizeMismatch(t *testing.T) {
+	pixel, err := os.ReadFile(path + "1.gif")
+	require.NoError(t, err)
+	chunks, err := webpChunks(pixel)
+	require.NoError(t, err)
+	data := appendRIFFChunk(nil, chunks[0].fourCC, chunks[0].data)
+
+	_, err = decodeWebPFrame(data, 1, 1)
+	require.NoError(t, err)
+
+	// the bitstream of the frame is smaller than the frame claims, or larger
+	_, err = decodeWebPFrame(data, 2, 2)
+	require.ErrorIs(t, err, ErrInvalidAnimation)
+}
+
+func TestEncodeAnimationToLimit(t *testing.T) {
+	buf, err := os.ReadFile(path + "spin.gif")
+	require.NoError(t, err)
+	anim, err := DecodeAnimation(buf)
+	require.NoError(t, err)
+	anim = anim.Resize(SmallDim)
+
+	bb := bytes.NewBuffer([]byte{})
+	err = EncodeAnimationToLimit(bb, anim, 1024*1024)
+	require.NoError(t, err)
+	full := bb.Len()
+
+	g, err := gif.DecodeAll(bytes.NewReader(bb.Bytes()))
+	require.NoError(t, err)
+	require.Equal(t, int(SmallDim), g.Config.Width)
+	require.Equal(t, sum(anim.Delays), sum(g.Delay))
+	frames := len(g.Image)
+
+	// frames are dropped to fit, keeping the animation length
+	err = EncodeAnimationToLimit(bb, anim, full*3/4)
+	require.NoError(t, err)
+	require.LessOrEqual(t, bb.Len(), full*3/4)
+
+	g, err = gif.DecodeAll(bytes.NewReader(bb.Bytes()))
+	require.NoError(t, err)
+	require.Less(t, len(g.Image), frames)
+	require.Equal(t, sum(anim.Delays), sum(g.Delay))
+
+	err = EncodeAnimationToLimit(bb, anim, 100)
+	fse := (*FileSizeError)(nil)
+	require.True(t, errors.As(err, &fse))
+}
+
+func sum(values []int) int {
+	total := 0
+	for _, v := range values {
+		total += v
+	}
+	return total
+}
+
+func TestGenerateIdentityImages_Animated(t *testing.T) {
+	iis, err := GenerateIdentityImages(path+"spin.gif", 28, 28, 228, 228)
+	require.NoError(t, err)
+	require.Len(t, iis, len(ResizeDimensions))
+
+	for _, ii := range iis {
+		require.Equal(t, JPEG, GetType(ii.Payload))
+		require.Equal(t, GIF, GetType(ii.AnimatedPayload))
+		require.True(t, IsAnimated(ii.AnimatedPayload))
+		require.LessOrEqual(t, len(ii.AnimatedPayload), AnimatedDimensionSizeLimit[ResizeDimension(ii.ResizeTarget)])
+
+		g, err := gif.DecodeConfig(bytes.NewReader(ii.AnimatedPayload))
+		require.NoError(t, err)
+		require.Equal(t, ii.Width, g.Width)
+		require.Equal(t, ii.Height, g.Height)
+	}
+}
+
+func TestGenerateIdentityImages_Still(t *testing.T) {
+	iis, err := GenerateIdentityImages(path+"status.png", 0, 0, 200, 200)
+	require.NoError(t, err)
+	require.Len(t, iis, len(ResizeDimensions))
+
+	for _, ii := range iis {
+		require.Equal(t, JPEG, GetType(ii.Payload))
+		require.Nil(t, ii.AnimatedPayload)
+	}
+}
+
+func TestGenerateBannerImage_Animated(t *testing.T) {
+	banner, err := GenerateBannerImage(path+"spin.gif", 0, 0, 256, 128)
+	require.NoError(t, err)
+	require.Equal(t, JPEG, GetType(banner.Payload))
+	require.True(t, IsAnimated(banner.AnimatedPayload))
+	require.Exactly(t, 256, banner.Width)
+	require.Exactly(t, 128, banner.Height)
+}
@@ -57,6 +57,15 @@ func Decode(fileName string) (image.Image, error) {
 }
 
 func DecodeFromURL(path string) (image.Image, error) {
+	bodyBytes, err := fetchImageData(path)
+	if err != nil {
+		return nil, err
+	}
+
+	return DecodeImageData(bodyBytes, bytes.NewReader(bodyBytes))
+}
+
+func fetchImageData(path string) ([]byte, error) {
 	client := http.Client{
 		Timeout: 5 * time.Second,
 	}
@@ -75,12 +84,7 @@ func DecodeFromURL(path string) (image.Image, error) {
 		return nil, errors.New(http.StatusText(res.StatusCode))
 	}
 
-	bodyBytes, err := ioutil.ReadAll(res.Body)
-	if err != nil {
-		return nil, err
-	}
-
-	return DecodeImageData(bodyBytes, bytes.NewReader(bodyBytes))
+	return ioutil.ReadAll(res.Body)
 }
 
 func prepareFileForDecode(file *os.File) ([]byte, error) {
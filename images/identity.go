@@ -18,6 +18,9 @@ type IdentityImage struct {
 	ResizeTarget int    `json:"resizeTarget"`
 	Clock        uint64 `json:"clock"`
 	LocalURL     string `json:"localUrl,omitempty"`
+	// AnimatedPayload is the animated GIF of an animated image, Payload being its first frame
+	AnimatedPayload  []byte `json:"animatedPayload,omitempty"`
+	AnimatedLocalURL string `json:"animatedLocalUrl,omitempty"`
 }
 
 func (i IdentityImage) GetType() (ImageType, error) {
@@ -43,16 +46,26 @@ func (i IdentityImage) MarshalJSON() ([]byte, error) {
 		return nil, err
 	}
 
+	var animatedURI string
+	if len(i.AnimatedPayload) > 0 {
+		animatedURI, err = GetPayloadDataURI(i.AnimatedPayload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	temp := struct {
-		KeyUID       string `json:"keyUid"`
-		Name         string `json:"type"`
-		URI          string `json:"uri"`
-		Width        int    `json:"width"`
-		Height       int    `json:"height"`
-		FileSize     int    `json:"fileSize"`
-		ResizeTarget int    `json:"resizeTarget"`
-		Clock        uint64 `json:"clock"`
-		LocalURL     string `json:"localUrl,omitempty"`
+		KeyUID           string `json:"keyUid"`
+		Name             string `json:"type"`
+		URI              string `json:"uri"`
+		Width            int    `json:"width"`
+		Height           int    `json:"height"`
+		FileSize         int    `json:"fileSize"`
+		ResizeTarget     int    `json:"resizeTarget"`
+		Clock            uint64 `json:"clock"`
+		LocalURL         string `json:"localUrl,omitempty"`
+		AnimatedURI      string `json:"animatedUri,omitempty"`
+		AnimatedLocalURL string `json:"animatedLocalUrl,omitempty"`
 	}{
 		KeyUID:       i.KeyUID,
 		Name:         i.Name,
@@ -63,6 +76,9 @@ func (i IdentityImage) MarshalJSON() ([]byte, error) {
 		ResizeTarget: i.ResizeTarget,
 		Clock:        i.Clock,
 		LocalURL:     i.LocalURL,
+
+		AnimatedURI:      animatedURI,
+		AnimatedLocalURL: i.AnimatedLocalURL,
 	}
 
 	return json.Marshal(temp)
@@ -78,6 +94,8 @@ func (i *IdentityImage) ToProtobuf() *protobuf.MultiAccount_IdentityImage {
 		Filesize:     int64(i.FileSize),
 		ResizeTarget: int64(i.ResizeTarget),
 		Clock:        i.Clock,
+
+		AnimatedPayload: i.AnimatedPayload,
 	}
 }
 
@@ -90,8 +108,9 @@ func (i *IdentityImage) FromProtobuf(ii *protobuf.MultiAccount_IdentityImage) {
 	i.FileSize = int(ii.Filesize)
 	i.ResizeTarget = int(ii.ResizeTarget)
 	i.Clock = ii.Clock
+	i.AnimatedPayload = ii.AnimatedPayload
 }
 
 func (i IdentityImage) IsEmpty() bool {
-	return i.KeyUID == "" && i.Name == "" && len(i.Payload) == 0 && i.Width == 0 && i.Height == 0 && i.FileSize == 0 && i.ResizeTarget == 0 && i.Clock == 0
+	return i.KeyUID == "" && i.Name == "" && len(i.Payload) == 0 && i.Width == 0 && i.Height == 0 && i.FileSize == 0 && i.ResizeTarget == 0 && i.Clock == 0 && len(i.AnimatedPayload) == 0
 }
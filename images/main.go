@@ -2,7 +2,13 @@ package images
 
 import (
 	"bytes"
+	"errors"
 	"image"
+	"os"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/logutils"
 )
 
 func GenerateImageVariants(cImg image.Image) ([]IdentityImage, error) {
@@ -33,8 +39,52 @@ func GenerateImageVariants(cImg image.Image) ([]IdentityImage, error) {
 	return iis, nil
 }
 
+// GenerateAnimatedImageVariants generates the variants of the first frame of an animation,
+// along with the animated payload of each variant that fits its size limit
+func GenerateAnimatedImageVariants(cAnim *Animation) ([]IdentityImage, error) {
+	iis, err := GenerateImageVariants(cAnim.Frames[0])
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range iis {
+		s := ResizeDimension(iis[i].ResizeTarget)
+		iis[i].AnimatedPayload, err = encodeAnimatedVariant(cAnim.Resize(s), AnimatedDimensionSizeLimit[s])
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return iis, nil
+}
+
+// encodeAnimatedVariant returns nil when the animation doesn't fit maxSize, the variant is then a still image
+func encodeAnimatedVariant(anim *Animation, maxSize int) ([]byte, error) {
+	bb := bytes.NewBuffer([]byte{})
+	err := EncodeAnimationToLimit(bb, anim, maxSize)
+	if fse := (*FileSizeError)(nil); errors.As(err, &fse) {
+		logutils.ZapLogger().Info("animation exceeds size limit, falling back to a still image", zap.Error(err))
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return bb.Bytes(), nil
+}
+
+// decodeIfAnimated returns the animation in buf, or nil for still images and animations
+// too large to process, which are handled as still images
+func decodeIfAnimated(buf []byte) (*Animation, error) {
+	anim, err := DecodeAnimation(buf)
+	if errors.Is(err, ErrNotAnimated) || errors.Is(err, ErrAnimationTooLarge) {
+		return nil, nil
+	}
+	return anim, err
+}
+
 func GenerateIdentityImages(filepath string, aX, aY, bX, bY int) ([]IdentityImage, error) {
-	img, err := Decode(filepath)
+	buf, err := os.ReadFile(filepath)
 	if err != nil {
 		return nil, err
 	}
@@ -43,6 +93,25 @@ func GenerateIdentityImages(filepath string, aX, aY, bX, bY int) ([]IdentityImag
 		Min: image.Point{X: aX, Y: aY},
 		Max: image.Point{X: bX, Y: bY},
 	}
+
+	anim, err := decodeIfAnimated(buf)
+	if err != nil {
+		return nil, err
+	}
+	if anim != nil {
+		cAnim, err := anim.Crop(cropRect)
+		if err != nil {
+			return nil, err
+		}
+
+		return GenerateAnimatedImageVariants(cAnim)
+	}
+
+	img, err := DecodeImageData(buf, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
 	cImg, err := Crop(img, cropRect)
 	if err != nil {
 		return nil, err
@@ -52,7 +121,25 @@ func GenerateIdentityImages(filepath string, aX, aY, bX, bY int) ([]IdentityImag
 }
 
 func GenerateIdentityImagesFromURL(url string) ([]IdentityImage, error) {
-	img, err := DecodeFromURL(url)
+	buf, err := fetchImageData(url)
+	if err != nil {
+		return nil, err
+	}
+
+	anim, err := decodeIfAnimated(buf)
+	if err != nil {
+		return nil, err
+	}
+	if anim != nil {
+		cAnim, err := anim.CropCenter()
+		if err != nil {
+			return nil, err
+		}
+
+		return GenerateAnimatedImageVariants(cAnim)
+	}
+
+	img, err := DecodeImageData(buf, bytes.NewReader(buf))
 	if err != nil {
 		return nil, err
 	}
@@ -66,7 +153,7 @@ func GenerateIdentityImagesFromURL(url string) ([]IdentityImage, error) {
 }
 
 func GenerateBannerImage(filepath string, aX, aY, bX, bY int) (*IdentityImage, error) {
-	img, err := Decode(filepath)
+	buf, err := os.ReadFile(filepath)
 	if err != nil {
 		return nil, err
 	}
@@ -75,17 +162,50 @@ func GenerateBannerImage(filepath string, aX, aY, bX, bY int) (*IdentityImage, e
 		Min: image.Point{X: aX, Y: aY},
 		Max: image.Point{X: bX, Y: bY},
 	}
+
+	anim, err := decodeIfAnimated(buf)
+	if err != nil {
+		return nil, err
+	}
+	if anim != nil {
+		croppedAnim, err := anim.Crop(cropRect)
+		if err != nil {
+			return nil, err
+		}
+
+		resizedAnim := croppedAnim.ShrinkOnly(BannerDim)
+
+		ii, err := encodeBannerImage(resizedAnim.Frames[0])
+		if err != nil {
+			return nil, err
+		}
+
+		ii.AnimatedPayload, err = encodeAnimatedVariant(resizedAnim, GetAnimatedBannerSizeLimit())
+		if err != nil {
+			return nil, err
+		}
+
+		return ii, nil
+	}
+
+	img, err := DecodeImageData(buf, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+
 	croppedImg, err := Crop(img, cropRect)
 	if err != nil {
 		return nil, err
 	}
 
-	resizedImg := ShrinkOnly(BannerDim, croppedImg)
+	return encodeBannerImage(ShrinkOnly(BannerDim, croppedImg))
+}
 
+func encodeBannerImage(resizedImg image.Image) (*IdentityImage, error) {
 	sizeLimits := GetBannerDimensionLimits()
 
 	bb := bytes.NewBuffer([]byte{})
-	err = EncodeToLimits(bb, resizedImg, sizeLimits)
+	err := EncodeToLimits(bb, resizedImg, sizeLimits)
 	if err != nil {
 		return nil, err
 	}
@@ -36,17 +36,20 @@ func (c *Circle) At(x, y int) color.Color {
 	return color.Alpha{0}
 }
 
-func Resize(size ResizeDimension, img image.Image) image.Image {
-	var width, height uint
-
+// resizeDimensions returns the width and height passed to resize.Resize, 0 keeps the aspect ratio
+func resizeDimensions(size ResizeDimension, img image.Image) (width, height uint) {
 	switch {
 	case img.Bounds().Max.X == img.Bounds().Max.Y:
-		width, height = uint(size), uint(size)
+		return uint(size), uint(size)
 	case img.Bounds().Max.X > img.Bounds().Max.Y:
-		width, height = 0, uint(size)
+		return 0, uint(size)
 	default:
-		width, height = uint(size), 0
+		return uint(size), 0
 	}
+}
+
+func Resize(size ResizeDimension, img image.Image) image.Image {
+	width, height := resizeDimensions(size, img)
 
 	logutils.ZapLogger().Info("resizing",
 		zap.Uint("size", uint(size)),
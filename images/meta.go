@@ -43,6 +43,13 @@ var (
 		},
 	}
 
+	// AnimatedDimensionSizeLimit the size limits imposed on the animated variant of each resize dimension,
+	// animations over the limit even once down to two frames fall back to the still variant only
+	AnimatedDimensionSizeLimit = map[ResizeDimension]int{
+		SmallDim: 32768,  // 32 KB, a few seconds of a simple animation at 80px
+		LargeDim: 131072, // 128 KB
+	}
+
 	// ResizeDimensionToName maps a ResizeDimension to its assigned string name
 	ResizeDimensionToName = map[ResizeDimension]string{
 		SmallDim: SmallDimName,
@@ -70,3 +77,7 @@ func GetBannerDimensionLimits() FileSizeLimits {
 		Max:   460800, // Can't go bigger than 450 KB
 	}
 }
+
+func GetAnimatedBannerSizeLimit() int {
+	return 460800 // Same as the max of a still banner
+}
@@ -0,0 +1,102 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"math"
+	"math/bits"
+	"sort"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+const (
+	// pHashSize is the side of the grayscale image the DCT of a perceptual hash is computed on
+	pHashSize = 32
+	// pHashLowFrequencies is the side of the block of lowest frequencies making up the hash
+	pHashLowFrequencies = 8
+)
+
+// pHashCosines are the DCT-II factors of the lowest frequencies, pHashCosines[u][x] being cos((2x+1)uπ/2N)
+var pHashCosines = func() [pHashLowFrequencies][pHashSize]float64 {
+	var cosines [pHashLowFrequencies][pHashSize]float64
+	for u := 0; u < pHashLowFrequencies; u++ {
+		for x := 0; x < pHashSize; x++ {
+			cosines[u][x] = math.Cos(float64(2*x+1) * float64(u) * math.Pi / (2 * pHashSize))
+		}
+	}
+	return cosines
+}()
+
+// grayscale draws img over a white background and scales it to width by height
+func grayscale(img image.Image, width, height int) *image.Gray {
+	gray := image.NewGray(image.Rect(0, 0, width, height))
+	draw.Draw(gray, gray.Bounds(), image.NewUniform(color.White), image.Point{}, draw.Src)
+	xdraw.BiLinear.Scale(gray, gray.Bounds(), img, img.Bounds(), draw.Over, nil)
+	return gray
+}
+
+// PerceptualHash returns the pHash of img: the signs, relative to their median, of the 64 lowest
+// frequencies of the DCT of the image shrunk to 32x32 grayscale. Similar images, such as the same
+// image resized or compressed again, have hashes a few bits apart.
+func PerceptualHash(img image.Image) uint64 {
+	gray := grayscale(img, pHashSize, pHashSize)
+
+	// the DCT is separable, rows are transformed first
+	var rows [pHashSize][pHashLowFrequencies]float64
+	for y := 0; y < pHashSize; y++ {
+		for u := 0; u < pHashLowFrequencies; u++ {
+			sum := 0.0
+			for x := 0; x < pHashSize; x++ {
+				sum += float64(gray.Pix[y*gray.Stride+x]) * pHashCosines[u][x]
+			}
+			rows[y][u] = sum
+		}
+	}
+
+	coefficients := make([]float64, 0, pHashLowFrequencies*pHashLowFrequencies)
+	for v := 0; v < pHashLowFrequencies; v++ {
+		for u := 0; u < pHashLowFrequencies; u++ {
+			sum := 0.0
+			for y := 0; y < pHashSize; y++ {
+				sum += rows[y][u] * pHashCosines[v][y]
+			}
+			coefficients = append(coefficients, sum)
+		}
+	}
+
+	// the DC coefficient is the mean brightness, it's left out of the median
+	sorted := append([]float64(nil), coefficients[1:]...)
+	sort.Float64s(sorted)
+	median := (sorted[len(sorted)/2-1] + sorted[len(sorted)/2]) / 2
+
+	var hash uint64
+	for i, coefficient := range coefficients {
+		if coefficient > median {
+			hash |= 1 << uint(i)
+		}
+	}
+	return hash
+}
+
+// DifferenceHash returns the dHash of img: whether each pixel of the image shrunk to 9x8
+// grayscale is brighter than its right neighbour. It's cheaper but less robust than the pHash.
+func DifferenceHash(img image.Image) uint64 {
+	gray := grayscale(img, 9, 8)
+
+	var hash uint64
+	for y := 0; y < 8; y++ {
+		for x := 0; x < 8; x++ {
+			if gray.Pix[y*gray.Stride+x] > gray.Pix[y*gray.Stride+x+1] {
+				hash |= 1 << uint(y*8+x)
+			}
+		}
+	}
+	return hash
+}
+
+// HammingDistance returns the number of bits two hashes differ by
+func HammingDistance(a, b uint64) int {
+	return bits.OnesCount64(a ^ b)
+}
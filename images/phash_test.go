@@ -0,0 +1,64 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/jpeg"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestPerceptualHashes(t *testing.T) {
+	rose, err := Decode(path + "rose.webp")
+	require.NoError(t, err)
+	status, err := Decode(path + "status.png")
+	require.NoError(t, err)
+
+	// the rose shrunk and compressed again, its transparent background made white as the hashes do
+	flattened := image.NewRGBA(rose.Bounds())
+	draw.Draw(flattened, flattened.Bounds(), image.White, image.Point{}, draw.Src)
+	draw.Draw(flattened, flattened.Bounds(), rose, image.Point{}, draw.Over)
+	bb := bytes.NewBuffer([]byte{})
+	err = jpeg.Encode(bb, ResizeTo(50, flattened), &jpeg.Options{Quality: 40})
+	require.NoError(t, err)
+	altered, err := jpeg.Decode(bb)
+	require.NoError(t, err)
+
+	for name, hash := range map[string]func(image.Image) uint64{"pHash": PerceptualHash, "dHash": DifferenceHash} {
+		require.Equal(t, hash(rose), hash(rose), name)
+		require.LessOrEqual(t, HammingDistance(hash(rose), hash(altered)), 8, name)
+		require.Greater(t, HammingDistance(hash(rose), hash(status)), 16, name)
+	}
+}
+
+func TestPerceptualHash_Transparency(t *testing.T) {
+	// transparent pixels hash as white, whatever their color
+	white := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	transparent := image.NewNRGBA(image.Rect(0, 0, 16, 16))
+	for i := range white.Pix {
+		white.Pix[i] = 0xff
+		if i%4 != 3 {
+			transparent.Pix[i] = 0x20
+		}
+	}
+	// a black square in the middle, for the hashes not to be all zeros
+	for y := 4; y < 12; y++ {
+		for x := 4; x < 12; x++ {
+			white.Set(x, y, color.Black)
+			transparent.Set(x, y, color.Black)
+		}
+	}
+
+	require.Equal(t, DifferenceHash(white), DifferenceHash(transparent))
+	require.Equal(t, PerceptualHash(white), PerceptualHash(transparent))
+	require.NotZero(t, PerceptualHash(white))
+}
+
+func TestHammingDistance(t *testing.T) {
+	require.Equal(t, 0, HammingDistance(0xff, 0xff))
+	require.Equal(t, 64, HammingDistance(0, ^uint64(0)))
+	require.Equal(t, 2, HammingDistance(0b1010, 0b0110))
+}
@@ -0,0 +1,301 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"image"
+	"os"
+	"strings"
+
+	"github.com/nfnt/resize"
+)
+
+const (
+	// placeholderSide is the longest side of the placeholders, in pixels
+	placeholderSide    = 32
+	placeholderQuality = 30
+	// MaxPlaceholderSize is the maximum size of a placeholder carried along an image
+	MaxPlaceholderSize = 4096
+
+	exifOrientationTag = 0x0112
+)
+
+var errInvalidJpeg = errors.New("invalid jpeg")
+
+// SanitizedImage is a chat image stripped of its metadata, along with
+// a placeholder to show while the image loads
+type SanitizedImage struct {
+	Payload     []byte
+	Placeholder []byte
+}
+
+// OpenAndSanitizeImage decodes a chat image, rotates it as its EXIF orientation says and re-encodes it
+// within the chat message limits, which drops its metadata, GPS location included
+func OpenAndSanitizeImage(inputImage CroppedImage, crop bool) (*SanitizedImage, error) {
+	imgPath := inputImage.ImagePath
+	if strings.HasPrefix(imgPath, "http://") || strings.HasPrefix(imgPath, "https://") {
+		var err error
+		imgPath, err = FetchAndStoreRemoteImage(imgPath)
+		if err != nil {
+			return nil, err
+		}
+		defer os.Remove(imgPath) // Clean up the temporary file
+	}
+
+	buf, err := os.ReadFile(imgPath)
+	if err != nil {
+		return nil, err
+	}
+
+	img, err := DecodeImageData(buf, bytes.NewReader(buf))
+	if err != nil {
+		return nil, err
+	}
+	img = NormalizeOrientation(img, ExifOrientation(buf))
+
+	payload, err := AdjustImage(img, crop, inputImage)
+	if err != nil {
+		return nil, err
+	}
+
+	placeholder, err := Placeholder(img)
+	if err != nil {
+		return nil, err
+	}
+
+	return &SanitizedImage{Payload: payload, Placeholder: placeholder}, nil
+}
+
+// StripMetadata drops the metadata of an encoded image. JPEG and PNG images are left untouched
+// otherwise, unless they need to be rotated, the other formats are re-encoded.
+func StripMetadata(payload []byte) ([]byte, error) {
+	switch GetType(payload) {
+	case JPEG:
+		if orientation := ExifOrientation(payload); orientation > 1 {
+			return reencode(payload, orientation)
+		}
+		return stripJpegMetadata(payload)
+	case PNG:
+		return stripPngMetadata(payload)
+	default:
+		return reencode(payload, 1)
+	}
+}
+
+// PlaceholderFromPayload returns the placeholder of an encoded image
+func PlaceholderFromPayload(payload []byte) ([]byte, error) {
+	img, err := DecodeImageData(payload, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	return Placeholder(NormalizeOrientation(img, ExifOrientation(payload)))
+}
+
+// Placeholder returns a tiny and blurry version of an image, to show before the image is loaded
+func Placeholder(img image.Image) ([]byte, error) {
+	thumbnail := resize.Thumbnail(placeholderSide, placeholderSide, img, resize.Bilinear)
+
+	bb := bytes.NewBuffer([]byte{})
+	if err := Encode(bb, thumbnail, EncodeConfig{Quality: placeholderQuality}); err != nil {
+		return nil, err
+	}
+	return bb.Bytes(), nil
+}
+
+func reencode(payload []byte, orientation int) ([]byte, error) {
+	img, err := DecodeImageData(payload, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	return AdjustImage(NormalizeOrientation(img, orientation), false, CroppedImage{})
+}
+
+// NormalizeOrientation rotates and flips an image as its EXIF orientation says,
+// so that it shows the right way up once the orientation is dropped
+func NormalizeOrientation(img image.Image, orientation int) image.Image {
+	if orientation < 2 || orientation > 8 {
+		return img
+	}
+
+	bounds := img.Bounds()
+	w, h := bounds.Dx(), bounds.Dy()
+
+	// orientations from 5 to 8 swap width and height
+	out := image.NewRGBA(image.Rect(0, 0, w, h))
+	if orientation >= 5 {
+		out = image.NewRGBA(image.Rect(0, 0, h, w))
+	}
+
+	outBounds := out.Bounds()
+	for y := 0; y < outBounds.Dy(); y++ {
+		for x := 0; x < outBounds.Dx(); x++ {
+			var sx, sy int
+			switch orientation {
+			case 2:
+				sx, sy = w-1-x, y
+			case 3:
+				sx, sy = w-1-x, h-1-y
+			case 4:
+				sx, sy = x, h-1-y
+			case 5:
+				sx, sy = y, x
+			case 6:
+				sx, sy = y, h-1-x
+			case 7:
+				sx, sy = w-1-y, h-1-x
+			case 8:
+				sx, sy = w-1-y, x
+			}
+			out.Set(x, y, img.At(bounds.Min.X+sx, bounds.Min.Y+sy))
+		}
+	}
+	return out
+}
+
+// jpegSegment is a segment of a JPEG image, up to the start of the scan
+type jpegSegment struct {
+	marker byte
+	data   []byte // the whole segment, marker included
+}
+
+func readJpegSegments(buf []byte) ([]jpegSegment, []byte, error) {
+	if !IsJpeg(buf) {
+		return nil, nil, errInvalidJpeg
+	}
+
+	var segments []jpegSegment
+	i := 2
+	for {
+		// skip fill bytes
+		for i+1 < len(buf) && buf[i] == 0xFF && buf[i+1] == 0xFF {
+			i++
+		}
+		if i+4 > len(buf) || buf[i] != 0xFF {
+			return nil, nil, errInvalidJpeg
+		}
+
+		marker := buf[i+1]
+		// the start of scan is followed by the compressed data, kept as is
+		if marker == 0xDA {
+			return segments, buf[i:], nil
+		}
+
+		length := int(binary.BigEndian.Uint16(buf[i+2 : i+4]))
+		if length < 2 || i+2+length > len(buf) {
+			return nil, nil, errInvalidJpeg
+		}
+		segments = append(segments, jpegSegment{marker: marker, data: buf[i : i+2+length]})
+		i += 2 + length
+	}
+}
+
+// stripJpegMetadata drops the APP segments but the JFIF header and the ICC profile, and the comments
+func stripJpegMetadata(buf []byte) ([]byte, error) {
+	segments, scan, err := readJpegSegments(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	result := bytes.NewBuffer(make([]byte, 0, len(buf)))
+	result.Write(buf[:2])
+	for _, segment := range segments {
+		isApp := segment.marker >= 0xE0 && segment.marker <= 0xEF
+		isJfif := segment.marker == 0xE0 && bytes.HasPrefix(segment.data[4:], []byte("JFIF\x00"))
+		isICC := segment.marker == 0xE2 && bytes.HasPrefix(segment.data[4:], []byte("ICC_PROFILE\x00"))
+		if (isApp && !isJfif && !isICC) || segment.marker == 0xFE {
+			continue
+		}
+		result.Write(segment.data)
+	}
+	result.Write(scan)
+	return result.Bytes(), nil
+}
+
+// ExifOrientation returns the orientation of a JPEG image stored in its EXIF metadata, 1 when missing
+func ExifOrientation(buf []byte) int {
+	segments, _, err := readJpegSegments(buf)
+	if err != nil {
+		return 1
+	}
+
+	for _, segment := range segments {
+		if segment.marker != 0xE1 || !bytes.HasPrefix(segment.data[4:], []byte("Exif\x00\x00")) {
+			continue
+		}
+		if orientation := tiffOrientation(segment.data[10:]); orientation != 0 {
+			return orientation
+		}
+	}
+	return 1
+}
+
+// tiffOrientation reads the orientation tag of the first IFD of a TIFF header
+func tiffOrientation(tiff []byte) int {
+	if len(tiff) < 8 {
+		return 0
+	}
+
+	var order binary.ByteOrder
+	switch string(tiff[0:2]) {
+	case "II":
+		order = binary.LittleEndian
+	case "MM":
+		order = binary.BigEndian
+	default:
+		return 0
+	}
+
+	// offsets are kept in uint64, they'd overflow an int on 32 bits platforms
+	size := uint64(len(tiff))
+	offset := uint64(order.Uint32(tiff[4:8]))
+	if offset+2 > size {
+		return 0
+	}
+	count := uint64(order.Uint16(tiff[offset : offset+2]))
+	for i := uint64(0); i < count; i++ {
+		entry := offset + 2 + i*12
+		if entry+12 > size {
+			return 0
+		}
+		if order.Uint16(tiff[entry:entry+2]) == exifOrientationTag {
+			return int(order.Uint16(tiff[entry+8 : entry+10]))
+		}
+	}
+	return 0
+}
+
+// pngMetadataChunks are the chunks of a PNG image holding text, dates and EXIF metadata
+var pngMetadataChunks = map[string]bool{
+	"tEXt": true,
+	"zTXt": true,
+	"iTXt": true,
+	"eXIf": true,
+	"tIME": true,
+}
+
+func stripPngMetadata(buf []byte) ([]byte, error) {
+	const signatureLength = 8
+
+	result := bytes.NewBuffer(make([]byte, 0, len(buf)))
+	result.Write(buf[:signatureLength])
+
+	i := signatureLength
+	for i < len(buf) {
+		if i+8 > len(buf) {
+			return nil, errors.New("invalid png")
+		}
+		length := uint64(binary.BigEndian.Uint32(buf[i : i+4]))
+		kind := string(buf[i+4 : i+8])
+		// length, type, data and CRC, in uint64 as it'd overflow an int on 32 bits platforms
+		if uint64(i)+12+length > uint64(len(buf)) {
+			return nil, errors.New("invalid png")
+		}
+		end := i + 12 + int(length)
+		if !pngMetadataChunks[kind] {
+			result.Write(buf[i:end])
+		}
+		i = end
+	}
+	return result.Bytes(), nil
+}
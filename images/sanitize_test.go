@@ -0,0 +1,130 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// withExifOrientation inserts an EXIF segment holding the given orientation after the start of a JPEG image
+func withExifOrientation(t *testing.T, buf []byte, orientation uint16) []byte {
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08")
+	ifd := make([]byte, 2+12+4)
+	binary.BigEndian.PutUint16(ifd[0:2], 1)
+	binary.BigEndian.PutUint16(ifd[2:4], exifOrientationTag)
+	binary.BigEndian.PutUint16(ifd[4:6], 3) // SHORT
+	binary.BigEndian.PutUint32(ifd[6:10], 1)
+	binary.BigEndian.PutUint16(ifd[10:12], orientation)
+	data := append([]byte("Exif\x00\x00"), append(tiff, ifd...)...)
+
+	segment := []byte{0xFF, 0xE1, 0, 0}
+	binary.BigEndian.PutUint16(segment[2:4], uint16(len(data)+2))
+	segment = append(segment, data...)
+
+	require.True(t, IsJpeg(buf))
+	return append(append(append([]byte{}, buf[:2]...), segment...), buf[2:]...)
+}
+
+func testImage(width int, height int) image.Image {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 40), G: uint8(y * 40), A: 255})
+		}
+	}
+	return img
+}
+
+func TestNormalizeOrientation(t *testing.T) {
+	img := testImage(4, 2)
+
+	// rotated clockwise, the bottom left corner goes on top
+	rotated := NormalizeOrientation(img, 6)
+	require.Equal(t, image.Rect(0, 0, 2, 4), rotated.Bounds())
+	require.Equal(t, img.At(0, 1), rotated.At(0, 0))
+	require.Equal(t, img.At(3, 1), rotated.At(0, 3))
+
+	// rotated counterclockwise, the top right corner goes on top
+	rotated = NormalizeOrientation(img, 8)
+	require.Equal(t, img.At(3, 0), rotated.At(0, 0))
+
+	flipped := NormalizeOrientation(img, 2)
+	require.Equal(t, img.Bounds(), flipped.Bounds())
+	require.Equal(t, img.At(3, 0), flipped.At(0, 0))
+
+	require.Equal(t, img, NormalizeOrientation(img, 1))
+}
+
+func TestTiffOrientationInvalidOffsets(t *testing.T) {
+	for _, offset := range []uint32{0xfffffffe, 0x80000000, 9} {
+		tiff := []byte("MM\x00\x2a\x00\x00\x00\x00\x00\x01")
+		binary.BigEndian.PutUint32(tiff[4:8], offset)
+		require.Zero(t, tiffOrientation(tiff))
+	}
+
+	// an IFD claiming more entries than there are
+	tiff := []byte("MM\x00\x2a\x00\x00\x00\x08\xff\xff")
+	require.Zero(t, tiffOrientation(tiff))
+}
+
+func TestStripJpegMetadata(t *testing.T) {
+	bb := bytes.NewBuffer([]byte{})
+	require.NoError(t, jpeg.Encode(bb, testImage(40, 20), nil))
+
+	// Upright images keep their pixels, only the metadata is dropped
+	upright := withExifOrientation(t, bb.Bytes(), 1)
+	require.Equal(t, 1, ExifOrientation(upright))
+
+	stripped, err := StripMetadata(upright)
+	require.NoError(t, err)
+	require.Equal(t, bb.Bytes(), stripped)
+
+	// Rotated images are re-encoded the right way up
+	rotated := withExifOrientation(t, bb.Bytes(), 6)
+	require.Equal(t, 6, ExifOrientation(rotated))
+
+	stripped, err = StripMetadata(rotated)
+	require.NoError(t, err)
+	require.Equal(t, 1, ExifOrientation(stripped))
+	require.NotContains(t, string(stripped), "Exif")
+
+	img, err := jpeg.Decode(bytes.NewReader(stripped))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 20, 40), img.Bounds())
+}
+
+func TestStripPngMetadata(t *testing.T) {
+	bb := bytes.NewBuffer([]byte{})
+	require.NoError(t, png.Encode(bb, testImage(4, 2)))
+	buf := bb.Bytes()
+
+	// a text chunk, inserted after the signature and the header
+	chunk := []byte{0, 0, 0, 4}
+	chunk = append(chunk, []byte("tEXtGPS!")...)
+	chunk = append(chunk, 0, 0, 0, 0)
+	headerEnd := 8 + 12 + 13
+	withText := append(append(append([]byte{}, buf[:headerEnd]...), chunk...), buf[headerEnd:]...)
+
+	stripped, err := StripMetadata(withText)
+	require.NoError(t, err)
+	require.Equal(t, buf, stripped)
+}
+
+func TestPlaceholder(t *testing.T) {
+	bb := bytes.NewBuffer([]byte{})
+	require.NoError(t, jpeg.Encode(bb, testImage(400, 200), nil))
+
+	placeholder, err := PlaceholderFromPayload(withExifOrientation(t, bb.Bytes(), 6))
+	require.NoError(t, err)
+	require.LessOrEqual(t, len(placeholder), MaxPlaceholderSize)
+
+	img, err := jpeg.Decode(bytes.NewReader(placeholder))
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 16, 32), img.Bounds())
+}
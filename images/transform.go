@@ -0,0 +1,105 @@
+package images
+
+import (
+	"errors"
+	"image"
+	"image/draw"
+
+	xdraw "golang.org/x/image/draw"
+)
+
+// FitMode is how an image is fitted into the box of a ResizeToFit
+type FitMode string
+
+const (
+	// FitCover fills the box, cropping the center of the image to the aspect ratio of the box
+	FitCover FitMode = "cover"
+	// FitContain keeps the whole image within the box, keeping its aspect ratio
+	FitContain FitMode = "contain"
+	// FitFill stretches the image to the box
+	FitFill FitMode = "fill"
+)
+
+var (
+	ErrUnknownFitMode      = errors.New("unknown fit mode")
+	ErrInvalidResizeTarget = errors.New("invalid resize target")
+)
+
+func ParseFitMode(s string) (FitMode, error) {
+	switch fit := FitMode(s); fit {
+	case FitCover, FitContain, FitFill:
+		return fit, nil
+	default:
+		return "", ErrUnknownFitMode
+	}
+}
+
+// ResizeToFit resizes img to fit a box of width by height pixels. A zero width or height is computed from
+// the other one, keeping the aspect ratio, and images are never upscaled so the box is shrunk to the image.
+func ResizeToFit(img image.Image, width, height int, fit FitMode) (image.Image, error) {
+	if width < 0 || height < 0 {
+		return nil, ErrInvalidResizeTarget
+	}
+	bounds := img.Bounds()
+	if bounds.Empty() {
+		return nil, ErrInvalidResizeTarget
+	}
+	if width == 0 && height == 0 {
+		return img, nil
+	}
+
+	srcWidth, srcHeight := bounds.Dx(), bounds.Dy()
+	switch {
+	case width == 0:
+		width = max(1, srcWidth*height/srcHeight)
+		fit = FitFill
+	case height == 0:
+		height = max(1, srcHeight*width/srcWidth)
+		fit = FitFill
+	}
+
+	src := bounds
+	switch fit {
+	case FitCover:
+		// the largest central part of the image with the aspect ratio of the box
+		cropWidth, cropHeight := srcWidth, srcHeight
+		if srcWidth*height > srcHeight*width {
+			cropWidth = max(1, srcHeight*width/height)
+		} else {
+			cropHeight = max(1, srcWidth*height/width)
+		}
+		src = image.Rect(0, 0, cropWidth, cropHeight).Add(bounds.Min).Add(image.Pt((srcWidth-cropWidth)/2, (srcHeight-cropHeight)/2))
+		if width > cropWidth || height > cropHeight {
+			width, height = cropWidth, cropHeight
+		}
+	case FitContain:
+		if srcWidth*height > srcHeight*width {
+			height = max(1, srcHeight*width/srcWidth)
+		} else {
+			width = max(1, srcWidth*height/srcHeight)
+		}
+		if width > srcWidth || height > srcHeight {
+			width, height = srcWidth, srcHeight
+		}
+	case FitFill:
+		width, height = min(width, srcWidth), min(height, srcHeight)
+	default:
+		return nil, ErrUnknownFitMode
+	}
+
+	resized := image.NewNRGBA(image.Rect(0, 0, width, height))
+	xdraw.BiLinear.Scale(resized, resized.Bounds(), img, src, draw.Src, nil)
+	return resized, nil
+}
+
+// CropCircle crops img to the circle inscribed in its largest central square, the corners being transparent
+func CropCircle(img image.Image) image.Image {
+	bounds := img.Bounds()
+	side := min(bounds.Dx(), bounds.Dy())
+	offset := image.Pt((bounds.Dx()-side)/2, (bounds.Dy()-side)/2)
+
+	circle := image.NewRGBA(image.Rect(0, 0, side, side))
+	mask := &Circle{X: side / 2, Y: side / 2, R: side / 2}
+	draw.DrawMask(circle, circle.Bounds(), img, bounds.Min.Add(offset), mask, image.Point{}, draw.Over)
+	return circle
+}
@@ -0,0 +1,81 @@
+package images
+
+import (
+	"image"
+	"image/color"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestResizeToFit(t *testing.T) {
+	img := image.NewRGBA(image.Rect(0, 0, 400, 200))
+
+	cases := []struct {
+		width, height int
+		fit           FitMode
+		expected      image.Rectangle
+	}{
+		{100, 100, FitCover, image.Rect(0, 0, 100, 100)},
+		{100, 100, FitContain, image.Rect(0, 0, 100, 50)},
+		{100, 100, FitFill, image.Rect(0, 0, 100, 100)},
+		{100, 0, FitCover, image.Rect(0, 0, 100, 50)},
+		{0, 50, FitContain, image.Rect(0, 0, 100, 50)},
+		// images are never upscaled
+		{800, 800, FitContain, image.Rect(0, 0, 400, 200)},
+		{800, 800, FitCover, image.Rect(0, 0, 200, 200)},
+		{800, 100, FitFill, image.Rect(0, 0, 400, 100)},
+		{0, 0, FitCover, image.Rect(0, 0, 400, 200)},
+	}
+	for _, c := range cases {
+		resized, err := ResizeToFit(img, c.width, c.height, c.fit)
+		require.NoError(t, err)
+		require.Equal(t, c.expected, resized.Bounds(), "%dx%d %s", c.width, c.height, c.fit)
+	}
+
+	_, err := ResizeToFit(img, -1, 100, FitCover)
+	require.ErrorIs(t, err, ErrInvalidResizeTarget)
+	_, err = ResizeToFit(img, 100, 100, "zoom")
+	require.ErrorIs(t, err, ErrUnknownFitMode)
+}
+
+func TestResizeToFit_CoverCropsCenter(t *testing.T) {
+	// a red square between blue sides, not starting at the origin
+	img := image.NewRGBA(image.Rect(10, 10, 40, 20))
+	for y := 10; y < 20; y++ {
+		for x := 10; x < 40; x++ {
+			img.Set(x, y, blue)
+			if x >= 20 && x < 30 {
+				img.Set(x, y, red)
+			}
+		}
+	}
+
+	resized, err := ResizeToFit(img, 10, 10, FitCover)
+	require.NoError(t, err)
+	require.Equal(t, image.Rect(0, 0, 10, 10), resized.Bounds())
+	require.Equal(t, red, color.RGBAModel.Convert(resized.At(0, 0)))
+	require.Equal(t, red, color.RGBAModel.Convert(resized.At(9, 9)))
+}
+
+func TestCropCircle(t *testing.T) {
+	img := image.NewRGBA(image.Rect(5, 5, 45, 25))
+	for i := 0; i < len(img.Pix); i += 4 {
+		copy(img.Pix[i:i+4], []byte{0xff, 0, 0, 0xff})
+	}
+
+	circle := CropCircle(img)
+	require.Equal(t, image.Rect(0, 0, 20, 20), circle.Bounds())
+	require.Equal(t, red, color.RGBAModel.Convert(circle.At(10, 10)))
+	require.Equal(t, color.RGBA{}, color.RGBAModel.Convert(circle.At(0, 0)))
+	require.Equal(t, color.RGBA{}, color.RGBAModel.Convert(circle.At(19, 19)))
+}
+
+func TestParseFitMode(t *testing.T) {
+	fit, err := ParseFitMode("contain")
+	require.NoError(t, err)
+	require.Equal(t, FitContain, fit)
+
+	_, err = ParseFitMode("")
+	require.ErrorIs(t, err, ErrUnknownFitMode)
+}
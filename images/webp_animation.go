@@ -0,0 +1,223 @@
+package images
+
+import (
+	"bytes"
+	"encoding/binary"
+	"image"
+	"image/draw"
+
+	"golang.org/x/image/webp"
+)
+
+const (
+	webpAnimationFlag = 0x02
+	webpAlphaFlag     = 0x10
+
+	// anmfHeaderSize is the size of the frame header in an ANMF chunk, before the frame data
+	anmfHeaderSize = 16
+	// anmfDisposeFlag disposes the frame area to the background once the frame is displayed
+	anmfDisposeFlag = 0x01
+	// anmfNoBlendFlag overwrites the frame area instead of alpha-blending the frame onto it
+	anmfNoBlendFlag = 0x02
+)
+
+type riffChunk struct {
+	fourCC string
+	data   []byte
+}
+
+// webpChunks returns the chunks of a RIFF WebP container
+func webpChunks(buf []byte) ([]riffChunk, error) {
+	if len(buf) < 12 || string(buf[0:4]) != "RIFF" || string(buf[8:12]) != "WEBP" {
+		return nil, ErrInvalidAnimation
+	}
+	end := 8 + int64(binary.LittleEndian.Uint32(buf[4:8]))
+	if end > int64(len(buf)) {
+		end = int64(len(buf))
+	}
+	return readRIFFChunks(buf[12:end])
+}
+
+func readRIFFChunks(buf []byte) ([]riffChunk, error) {
+	var chunks []riffChunk
+	for len(buf) > 0 {
+		if len(buf) < 8 {
+			return nil, ErrInvalidAnimation
+		}
+		size := int64(binary.LittleEndian.Uint32(buf[4:8]))
+		if size > int64(len(buf)-8) {
+			return nil, ErrInvalidAnimation
+		}
+		chunks = append(chunks, riffChunk{fourCC: string(buf[0:4]), data: buf[8 : 8+size]})
+
+		// chunks are padded to an even size
+		next := 8 + size + size&1
+		if next > int64(len(buf)) {
+			next = int64(len(buf))
+		}
+		buf = buf[next:]
+	}
+	return chunks, nil
+}
+
+func appendRIFFChunk(buf []byte, fourCC string, data []byte) []byte {
+	buf = append(buf, fourCC...)
+	buf = binary.LittleEndian.AppendUint32(buf, uint32(len(data)))
+	buf = append(buf, data...)
+	if len(data)%2 == 1 {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+func uint24(b []byte) int {
+	return int(b[0]) | int(b[1])<<8 | int(b[2])<<16
+}
+
+func putUint24(b []byte, v int) {
+	b[0] = byte(v)
+	b[1] = byte(v >> 8)
+	b[2] = byte(v >> 16)
+}
+
+// hasAnimationFlag returns whether the chunks of a WebP start with an extended header flagging an animation
+func hasAnimationFlag(chunks []riffChunk) bool {
+	if len(chunks) == 0 {
+		return false
+	}
+	vp8x := chunks[0]
+	return vp8x.fourCC == "VP8X" && len(vp8x.data) >= 10 && vp8x.data[0]&webpAnimationFlag != 0
+}
+
+func isAnimatedWebP(buf []byte) bool {
+	chunks, err := webpChunks(buf)
+	return err == nil && hasAnimationFlag(chunks)
+}
+
+func decodeWebPAnimation(buf []byte) (*Animation, error) {
+	chunks, err := webpChunks(buf)
+	if err != nil {
+		return nil, err
+	}
+	if !hasAnimationFlag(chunks) {
+		return nil, ErrNotAnimated
+	}
+
+	vp8x := chunks[0].data
+	bounds := image.Rect(0, 0, 1+uint24(vp8x[4:7]), 1+uint24(vp8x[7:10]))
+
+	anim := &Animation{}
+	var frames []riffChunk
+	for _, chunk := range chunks[1:] {
+		switch chunk.fourCC {
+		case "ANIM":
+			if len(chunk.data) < 6 {
+				return nil, ErrInvalidAnimation
+			}
+			// WebP counts plays, 0 looping forever
+			switch loops := int(binary.LittleEndian.Uint16(chunk.data[4:6])); loops {
+			case 0:
+				anim.LoopCount = 0
+			case 1:
+				anim.LoopCount = -1
+			default:
+				anim.LoopCount = loops - 1
+			}
+		case "ANMF":
+			frames = append(frames, chunk)
+		}
+	}
+	if err := checkAnimationSize(len(frames), bounds); err != nil {
+		return nil, err
+	}
+
+	canvas := image.NewRGBA(bounds)
+	for _, frame := range frames {
+		header := frame.data
+		if len(header) < anmfHeaderSize {
+			return nil, ErrInvalidAnimation
+		}
+		x := 2 * uint24(header[0:3])
+		y := 2 * uint24(header[3:6])
+		width := 1 + uint24(header[6:9])
+		height := 1 + uint24(header[9:12])
+		duration := uint24(header[12:15])
+		flags := header[15]
+
+		frameRect := image.Rect(x, y, x+width, y+height)
+		if !frameRect.In(bounds) {
+			return nil, ErrInvalidAnimation
+		}
+
+		img, err := decodeWebPFrame(header[anmfHeaderSize:], width, height)
+		if err != nil {
+			return nil, err
+		}
+
+		op := draw.Over
+		if flags&anmfNoBlendFlag != 0 {
+			op = draw.Src
+		}
+		draw.Draw(canvas, frameRect, img, img.Bounds().Min, op)
+		anim.Frames = append(anim.Frames, cloneRGBA(canvas))
+		// durations are in milliseconds
+		anim.Delays = append(anim.Delays, (duration+5)/10)
+
+		if flags&anmfDisposeFlag != 0 {
+			draw.Draw(canvas, frameRect, image.Transparent, image.Point{}, draw.Src)
+		}
+	}
+
+	return anim, nil
+}
+
+// decodeWebPFrame decodes the data of an ANMF chunk, by wrapping its bitstream in a WebP container of its own.
+// The bitstream must be of the size of the frame, it's checked before being decoded.
+func decodeWebPFrame(data []byte, width, height int) (image.Image, error) {
+	chunks, err := readRIFFChunks(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var alpha, bitstream *riffChunk
+	for i := range chunks {
+		switch chunks[i].fourCC {
+		case "ALPH":
+			alpha = &chunks[i]
+		case "VP8 ", "VP8L":
+			bitstream = &chunks[i]
+		}
+	}
+	if bitstream == nil {
+		return nil, ErrInvalidAnimation
+	}
+
+	config, err := webp.DecodeConfig(bytes.NewReader(webpContainer(appendRIFFChunk(nil, bitstream.fourCC, bitstream.data))))
+	if err != nil {
+		return nil, err
+	}
+	if config.Width != width || config.Height != height {
+		return nil, ErrInvalidAnimation
+	}
+
+	var body []byte
+	if alpha != nil && bitstream.fourCC == "VP8 " {
+		vp8x := make([]byte, 10)
+		vp8x[0] = webpAlphaFlag
+		putUint24(vp8x[4:7], width-1)
+		putUint24(vp8x[7:10], height-1)
+		body = appendRIFFChunk(body, "VP8X", vp8x)
+		body = appendRIFFChunk(body, alpha.fourCC, alpha.data)
+	}
+	body = appendRIFFChunk(body, bitstream.fourCC, bitstream.data)
+
+	return webp.Decode(bytes.NewReader(webpContainer(body)))
+}
+
+// webpContainer wraps chunks in a RIFF WebP container
+func webpContainer(body []byte) []byte {
+	container := []byte("RIFF")
+	container = binary.LittleEndian.AppendUint32(container, uint32(4+len(body)))
+	container = append(container, "WEBP"...)
+	return append(container, body...)
+}
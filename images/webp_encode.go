@@ -0,0 +1,505 @@
+package images
+
+import (
+	"bytes"
+	"container/heap"
+	"encoding/binary"
+	"errors"
+	"image"
+	"image/color"
+	"io"
+)
+
+// VP8L, the lossless WebP bitstream, is written with a predictor and a subtract green transform, a single
+// group of prefix codes, and backward references to the pixels on the left and above only, without color
+// cache. That keeps the encoder simple while compressing about as well as PNG.
+const (
+	vp8lSignature  = 0x2f
+	vp8lMaxDim     = 1 << 14
+	vp8lMaxCodeLen = 15
+
+	vp8lPredictorTransform     = 0
+	vp8lSubtractGreenTransform = 2
+	// vp8lPredictorBits is the log-2 size of the tiles of the predictor transform, every tile uses vp8lPredictorMode
+	vp8lPredictorBits = 9
+	// vp8lPredictorMode predicts pixels as the average of the left and top pixels
+	vp8lPredictorMode = 7
+
+	// backward references copy runs of pixels, at most 4096 of them
+	vp8lMinBackwardRefLength = 3
+	vp8lMaxBackwardRefLength = 4096
+
+	vp8lLiteralCodes  = 256
+	vp8lLengthCodes   = 24
+	vp8lDistanceCodes = 40
+
+	// code lengths are written with a prefix code of their own, with codes 17 and 18 repeating zeros
+	vp8lCodeLengthCodes = 19
+	vp8lMaxCodeLenLen   = 7
+	vp8lRepeatZeros     = 17
+	vp8lRepeatManyZeros = 18
+)
+
+var (
+	ErrWebPDimensions = errors.New("image dimensions not supported by WebP")
+
+	vp8lCodeLengthCodeOrder = [vp8lCodeLengthCodes]int{17, 18, 0, 1, 2, 3, 4, 5, 16, 6, 7, 8, 9, 10, 11, 12, 13, 14, 15}
+)
+
+// EncodeWebPLossless writes img as a lossless WebP
+func EncodeWebPLossless(w io.Writer, img image.Image) error {
+	bounds := img.Bounds()
+	width, height := bounds.Dx(), bounds.Dy()
+	if width == 0 || height == 0 || width > vp8lMaxDim || height > vp8lMaxDim {
+		return ErrWebPDimensions
+	}
+
+	// pixels are stored as NRGBA, 4 bytes each
+	pix := make([]byte, 0, 4*width*height)
+	hasAlpha := false
+	for y := bounds.Min.Y; y < bounds.Max.Y; y++ {
+		for x := bounds.Min.X; x < bounds.Max.X; x++ {
+			c := color.NRGBAModel.Convert(img.At(x, y)).(color.NRGBA)
+			pix = append(pix, c.R, c.G, c.B, c.A)
+			hasAlpha = hasAlpha || c.A != 0xff
+		}
+	}
+
+	bw := &bitWriter{}
+	bw.writeBits(vp8lSignature, 8)
+	bw.writeBits(uint32(width-1), 14)
+	bw.writeBits(uint32(height-1), 14)
+	if hasAlpha {
+		bw.writeBits(1, 1)
+	} else {
+		bw.writeBits(0, 1)
+	}
+	bw.writeBits(0, 3) // version
+
+	// the transforms are applied in the order they're written, and undone in reverse
+	bw.writeBits(1, 1)
+	bw.writeBits(vp8lPredictorTransform, 2)
+	bw.writeBits(vp8lPredictorBits-2, 3)
+	writeVP8LPredictorImage(bw)
+	pix = applyVP8LPredictor(pix, width, height)
+
+	bw.writeBits(1, 1)
+	bw.writeBits(vp8lSubtractGreenTransform, 2)
+	for p := 0; p < len(pix); p += 4 {
+		pix[p+0] -= pix[p+1]
+		pix[p+2] -= pix[p+1]
+	}
+
+	bw.writeBits(0, 1) // no more transforms
+	bw.writeBits(0, 1) // no color cache
+	bw.writeBits(0, 1) // no meta prefix codes
+
+	refs := findVP8LBackwardRefs(pix, width)
+
+	var histograms [5][]int
+	histograms[0] = make([]int, vp8lLiteralCodes+vp8lLengthCodes)
+	for i := 1; i < 4; i++ {
+		histograms[i] = make([]int, vp8lLiteralCodes)
+	}
+	histograms[4] = make([]int, vp8lDistanceCodes)
+	for p, r := 0, 0; p < len(pix); {
+		if r < len(refs) && refs[r].p == p {
+			histograms[0][vp8lLiteralCodes+refs[r].length.symbol]++
+			histograms[4][refs[r].distance.symbol]++
+			p += 4 * refs[r].pixels
+			r++
+			continue
+		}
+		histograms[0][pix[p+1]]++
+		histograms[1][pix[p+0]]++
+		histograms[2][pix[p+2]]++
+		histograms[3][pix[p+3]]++
+		p += 4
+	}
+
+	// codes are written green, red, blue, alpha then distance
+	var codes [5]*prefixCode
+	for i, histogram := range histograms {
+		codes[i] = newPrefixCode(histogram, vp8lMaxCodeLen)
+		codes[i].write(bw)
+	}
+
+	for p, r := 0, 0; p < len(pix); {
+		if r < len(refs) && refs[r].p == p {
+			ref := refs[r]
+			codes[0].writeSymbol(bw, vp8lLiteralCodes+ref.length.symbol)
+			bw.writeBits(ref.length.extra, ref.length.extraBits)
+			codes[4].writeSymbol(bw, ref.distance.symbol)
+			bw.writeBits(ref.distance.extra, ref.distance.extraBits)
+			p += 4 * ref.pixels
+			r++
+			continue
+		}
+		codes[0].writeSymbol(bw, int(pix[p+1]))
+		codes[1].writeSymbol(bw, int(pix[p+0]))
+		codes[2].writeSymbol(bw, int(pix[p+2]))
+		codes[3].writeSymbol(bw, int(pix[p+3]))
+		p += 4
+	}
+
+	data := bw.bytes()
+
+	header := make([]byte, 0, 20)
+	header = append(header, "RIFF"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(4+8+len(data)+len(data)%2))
+	header = append(header, "WEBP"...)
+	header = append(header, "VP8L"...)
+	header = binary.LittleEndian.AppendUint32(header, uint32(len(data)))
+	if len(data)%2 == 1 {
+		data = append(data, 0)
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return err
+	}
+	_, err := w.Write(data)
+	return err
+}
+
+// writeVP8LPredictorImage writes the sub-image of the predictor modes of the tiles, all of them
+// using vp8lPredictorMode its pixels take no bits once the codes are written
+func writeVP8LPredictorImage(bw *bitWriter) {
+	bw.writeBits(0, 1) // no color cache
+	writeVP8LSingleSymbolCode(bw, vp8lPredictorMode)
+	for i := 0; i < 4; i++ {
+		writeVP8LSingleSymbolCode(bw, 0)
+	}
+}
+
+// applyVP8LPredictor returns the residuals of the pixels once predicted as the decoder does
+func applyVP8LPredictor(pix []byte, width, height int) []byte {
+	residuals := make([]byte, len(pix))
+	stride := 4 * width
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			p := y*stride + 4*x
+			for c := 0; c < 4; c++ {
+				var predicted byte
+				switch {
+				case x == 0 && y == 0:
+					// opaque black
+					if c == 3 {
+						predicted = 0xff
+					}
+				case y == 0:
+					predicted = pix[p-4+c]
+				case x == 0:
+					predicted = pix[p-stride+c]
+				default:
+					predicted = byte((int(pix[p-4+c]) + int(pix[p-stride+c])) / 2)
+				}
+				residuals[p+c] = pix[p+c] - predicted
+			}
+		}
+	}
+	return residuals
+}
+
+// lz77Value is a length or distance of a backward reference, written as a prefix code symbol and extra bits
+type lz77Value struct {
+	symbol    int
+	extra     uint32
+	extraBits uint
+}
+
+func newLZ77Value(v int) lz77Value {
+	d := v - 1
+	if d < 4 {
+		return lz77Value{symbol: d}
+	}
+	highBit := 0
+	for d>>(highBit+1) != 0 {
+		highBit++
+	}
+	extraBits := uint(highBit - 1)
+	return lz77Value{
+		symbol:    2*highBit + (d>>extraBits)&1,
+		extra:     uint32(d) & (1<<extraBits - 1),
+		extraBits: extraBits,
+	}
+}
+
+type vp8lBackwardRef struct {
+	// p is the offset in the pixel data where the reference starts
+	p        int
+	pixels   int
+	length   lz77Value
+	distance lz77Value
+}
+
+// findVP8LBackwardRefs greedily looks for runs of pixels repeating the pixel to their left or above them
+func findVP8LBackwardRefs(pix []byte, width int) []vp8lBackwardRef {
+	var refs []vp8lBackwardRef
+	pixels := len(pix) / 4
+	for i := 0; i < pixels; {
+		bestLength, bestCode := 0, 0
+		// distance code 1 is the pixel above, 2 the pixel to the left
+		for code, distance := range []int{1: width, 2: 1} {
+			if code == 0 {
+				continue
+			}
+			if i < distance {
+				continue
+			}
+			length := 0
+			for i+length < pixels && length < vp8lMaxBackwardRefLength &&
+				bytes.Equal(pix[4*(i+length):4*(i+length+1)], pix[4*(i+length-distance):4*(i+length-distance+1)]) {
+				length++
+			}
+			if length > bestLength {
+				bestLength, bestCode = length, code
+			}
+		}
+		if bestLength < vp8lMinBackwardRefLength {
+			i++
+			continue
+		}
+		refs = append(refs, vp8lBackwardRef{
+			p:        4 * i,
+			pixels:   bestLength,
+			length:   newLZ77Value(bestLength),
+			distance: newLZ77Value(bestCode),
+		})
+		i += bestLength
+	}
+	return refs
+}
+
+// writeVP8LSingleSymbolCode writes a simple prefix code of a single symbol, which takes no bits
+func writeVP8LSingleSymbolCode(bw *bitWriter, symbol uint32) {
+	bw.writeBits(1, 1) // simple code
+	bw.writeBits(0, 1) // one symbol
+	if symbol < 2 {
+		bw.writeBits(0, 1)
+		bw.writeBits(symbol, 1)
+		return
+	}
+	bw.writeBits(1, 1)
+	bw.writeBits(symbol, 8)
+}
+
+type bitWriter struct {
+	buf   []byte
+	bits  uint64
+	nBits uint
+}
+
+// writeBits writes the n lowest bits of v, least significant bit first
+func (w *bitWriter) writeBits(v uint32, n uint) {
+	w.bits |= uint64(v) << w.nBits
+	w.nBits += n
+	for w.nBits >= 8 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits >>= 8
+		w.nBits -= 8
+	}
+}
+
+func (w *bitWriter) bytes() []byte {
+	if w.nBits > 0 {
+		w.buf = append(w.buf, byte(w.bits))
+		w.bits, w.nBits = 0, 0
+	}
+	return w.buf
+}
+
+// prefixCode is a canonical Huffman code
+type prefixCode struct {
+	lengths []int
+	// codes are bit reversed, prefix codes being read from their most significant bit
+	codes []uint32
+	// symbol is set when a single symbol is used, it's then written with no bits
+	symbol int
+}
+
+func newPrefixCode(histogram []int, maxLength int) *prefixCode {
+	used := 0
+	symbol := 0
+	for s, count := range histogram {
+		if count > 0 {
+			used++
+			symbol = s
+		}
+	}
+	if used <= 1 {
+		return &prefixCode{symbol: symbol}
+	}
+
+	lengths := huffmanCodeLengths(histogram, maxLength)
+	return &prefixCode{lengths: lengths, codes: canonicalCodes(lengths)}
+}
+
+func (c *prefixCode) writeSymbol(bw *bitWriter, symbol int) {
+	if c.lengths == nil {
+		return
+	}
+	bw.writeBits(c.codes[symbol], uint(c.lengths[symbol]))
+}
+
+func (c *prefixCode) write(bw *bitWriter) {
+	if c.lengths == nil {
+		writeVP8LSingleSymbolCode(bw, uint32(c.symbol))
+		return
+	}
+	bw.writeBits(0, 1) // normal code
+
+	// runs of zeros are written with the repeat codes, other lengths as is
+	type token struct{ symbol, extra, extraBits int }
+	var tokens []token
+	for i := 0; i < len(c.lengths); {
+		if c.lengths[i] != 0 {
+			tokens = append(tokens, token{symbol: c.lengths[i]})
+			i++
+			continue
+		}
+		run := 0
+		for i+run < len(c.lengths) && c.lengths[i+run] == 0 && run < 138 {
+			run++
+		}
+		switch {
+		case run >= 11:
+			tokens = append(tokens, token{symbol: vp8lRepeatManyZeros, extra: run - 11, extraBits: 7})
+		case run >= 3:
+			tokens = append(tokens, token{symbol: vp8lRepeatZeros, extra: run - 3, extraBits: 3})
+		default:
+			run = 1
+			tokens = append(tokens, token{symbol: 0})
+		}
+		i += run
+	}
+
+	histogram := make([]int, vp8lCodeLengthCodes)
+	for _, t := range tokens {
+		histogram[t.symbol]++
+	}
+	lengthCode := newPrefixCode(histogram, vp8lMaxCodeLenLen)
+	lengths := lengthCode.lengths
+	if lengths == nil {
+		// a single code length symbol is used, its code then takes no bits
+		lengths = make([]int, vp8lCodeLengthCodes)
+		lengths[lengthCode.symbol] = 1
+	}
+
+	n := vp8lCodeLengthCodes
+	for n > 4 && lengths[vp8lCodeLengthCodeOrder[n-1]] == 0 {
+		n--
+	}
+	bw.writeBits(uint32(n-4), 4)
+	for _, s := range vp8lCodeLengthCodeOrder[:n] {
+		bw.writeBits(uint32(lengths[s]), 3)
+	}
+	bw.writeBits(0, 1) // all the symbols of the alphabet are written
+
+	for _, t := range tokens {
+		lengthCode.writeSymbol(bw, t.symbol)
+		if t.extraBits > 0 {
+			bw.writeBits(uint32(t.extra), uint(t.extraBits))
+		}
+	}
+}
+
+func canonicalCodes(lengths []int) []uint32 {
+	var count [vp8lMaxCodeLen + 2]int
+	for _, l := range lengths {
+		count[l]++
+	}
+	count[0] = 0
+
+	var next [vp8lMaxCodeLen + 2]uint32
+	code := uint32(0)
+	for l := 1; l < len(next); l++ {
+		code = (code + uint32(count[l-1])) << 1
+		next[l] = code
+	}
+
+	codes := make([]uint32, len(lengths))
+	for s, l := range lengths {
+		if l == 0 {
+			continue
+		}
+		codes[s] = reverseCode(next[l], l)
+		next[l]++
+	}
+	return codes
+}
+
+func reverseCode(code uint32, length int) uint32 {
+	reversed := uint32(0)
+	for i := 0; i < length; i++ {
+		reversed = reversed<<1 | code&1
+		code >>= 1
+	}
+	return reversed
+}
+
+type huffmanNode struct {
+	count       int
+	symbol      int
+	left, right *huffmanNode
+}
+
+type huffmanHeap []*huffmanNode
+
+func (h huffmanHeap) Len() int { return len(h) }
+func (h huffmanHeap) Less(i, j int) bool {
+	if h[i].count == h[j].count {
+		return h[i].symbol < h[j].symbol
+	}
+	return h[i].count < h[j].count
+}
+func (h huffmanHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *huffmanHeap) Push(x interface{}) { *h = append(*h, x.(*huffmanNode)) }
+func (h *huffmanHeap) Pop() interface{} {
+	old := *h
+	node := old[len(old)-1]
+	*h = old[:len(old)-1]
+	return node
+}
+
+// huffmanCodeLengths returns the code lengths of a Huffman code of the symbols of histogram, at
+// least two of them being used. Counts are flattened until no code is longer than maxLength.
+func huffmanCodeLengths(histogram []int, maxLength int) []int {
+	counts := append([]int(nil), histogram...)
+	for {
+		h := &huffmanHeap{}
+		for s, count := range counts {
+			if count > 0 {
+				*h = append(*h, &huffmanNode{count: count, symbol: s})
+			}
+		}
+		heap.Init(h)
+		for h.Len() > 1 {
+			a := heap.Pop(h).(*huffmanNode)
+			b := heap.Pop(h).(*huffmanNode)
+			heap.Push(h, &huffmanNode{count: a.count + b.count, symbol: min(a.symbol, b.symbol), left: a, right: b})
+		}
+
+		lengths := make([]int, len(counts))
+		tooLong := false
+		var walk func(node *huffmanNode, depth int)
+		walk = func(node *huffmanNode, depth int) {
+			if node.left == nil {
+				lengths[node.symbol] = depth
+				tooLong = tooLong || depth > maxLength
+				return
+			}
+			walk(node.left, depth+1)
+			walk(node.right, depth+1)
+		}
+		walk((*h)[0], 0)
+		if !tooLong {
+			return lengths
+		}
+
+		for s, count := range counts {
+			if count > 0 {
+				counts[s] = (count + 1) / 2
+			}
+		}
+	}
+}
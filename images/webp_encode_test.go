@@ -0,0 +1,82 @@
+package images
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"math/rand"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"golang.org/x/image/webp"
+)
+
+func requireWebPRoundTrip(t *testing.T, img image.Image) {
+	bb := bytes.NewBuffer([]byte{})
+	err := EncodeWebPLossless(bb, img)
+	require.NoError(t, err)
+	require.Equal(t, WEBP, GetType(bb.Bytes()))
+
+	decoded, err := webp.Decode(bb)
+	require.NoError(t, err)
+
+	bounds := img.Bounds()
+	require.Equal(t, bounds.Dx(), decoded.Bounds().Dx())
+	require.Equal(t, bounds.Dy(), decoded.Bounds().Dy())
+	for y := 0; y < bounds.Dy(); y++ {
+		for x := 0; x < bounds.Dx(); x++ {
+			expected := color.NRGBAModel.Convert(img.At(bounds.Min.X+x, bounds.Min.Y+y))
+			require.Equal(t, expected, color.NRGBAModel.Convert(decoded.At(x, y)), "pixel %d,%d", x, y)
+		}
+	}
+}
+
+func TestEncodeWebPLossless(t *testing.T) {
+	for _, file := range []string{"status.png", "rose.webp"} {
+		f, err := os.Open(path + file)
+		require.NoError(t, err)
+		img, _, err := image.Decode(f)
+		f.Close()
+		require.NoError(t, err)
+
+		requireWebPRoundTrip(t, img)
+	}
+}
+
+func TestEncodeWebPLossless_Edges(t *testing.T) {
+	// a single pixel, and an image of a single color, where every code has a single symbol
+	pixel := image.NewNRGBA(image.Rect(0, 0, 1, 1))
+	pixel.Set(0, 0, color.NRGBA{R: 10, G: 20, B: 30, A: 40})
+	requireWebPRoundTrip(t, pixel)
+
+	uniform := image.NewNRGBA(image.Rect(0, 0, 30, 20))
+	for i := range uniform.Pix {
+		uniform.Pix[i] = 0xff
+	}
+	requireWebPRoundTrip(t, uniform)
+
+	// noise uses every symbol, not starting at the origin
+	noise := image.NewNRGBA(image.Rect(5, 5, 70, 50))
+	random := rand.New(rand.NewSource(1))
+	random.Read(noise.Pix)
+	requireWebPRoundTrip(t, noise)
+
+	// skewed histograms make for long codes
+	skewed := image.NewNRGBA(image.Rect(0, 0, 600, 600))
+	for i := range skewed.Pix {
+		skewed.Pix[i] = byte(bitsLen(random.Intn(1 << 20)))
+	}
+	requireWebPRoundTrip(t, skewed)
+
+	err := EncodeWebPLossless(bytes.NewBuffer([]byte{}), image.NewNRGBA(image.Rect(0, 0, 0, 1)))
+	require.ErrorIs(t, err, ErrWebPDimensions)
+}
+
+func bitsLen(v int) int {
+	n := 0
+	for ; v > 0; v >>= 1 {
+		n++
+	}
+	return n
+}
@@ -2,6 +2,7 @@ package version
 
 import (
 	_ "embed"
+	"regexp"
 	"strings"
 )
 
@@ -34,3 +35,12 @@ func Version() string {
 func GitCommit() string {
 	return gitCommit
 }
+
+// releaseVersion matches the versions of builds made from a release tag, development builds
+// are described relatively to the last tag, e.g. v0.179.10-3-gabcdef1 or v0.179.10-dirty
+var releaseVersion = regexp.MustCompile(`^v[0-9]+\.[0-9]+\.[0-9]+$`)
+
+// IsRelease returns true if the build was made from a release tag
+func IsRelease() bool {
+	return releaseVersion.MatchString(version)
+}
@@ -0,0 +1,262 @@
+package ipfs
+
+import (
+	"container/list"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultCacheMaxSize is the size over which unpinned content is evicted, least recently used first
+	defaultCacheMaxSize = 100 * 1024 * 1024
+
+	pinsFile = "pins.json"
+	// tempPrefix prefixes files being written, which aren't part of the cache until renamed
+	tempPrefix = ".tmp-"
+)
+
+type cacheEntry struct {
+	key  string
+	size int64
+}
+
+// cache stores content in a directory, one file per key. Keys are content addresses,
+// so files are never rewritten with different content. Content pinned by an owner
+// is kept until unpinned, the rest is evicted once the cache grows over its maximum size.
+type cache struct {
+	dir     string
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru has the most recently used entries at the front
+	lru  *list.List
+	size int64
+	// pins are the keys pinned by each owner
+	pins map[string][]string
+}
+
+// newCache indexes the content already in dir, ordered by access time
+func newCache(dir string, maxSize int64) (*cache, error) {
+	c := &cache{
+		dir:     dir,
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+		pins:    make(map[string][]string),
+	}
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+
+	type indexed struct {
+		cacheEntry
+		accessed time.Time
+	}
+	var files []indexed
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() || name == pinsFile {
+			continue
+		}
+		if strings.HasPrefix(name, tempPrefix) {
+			// left over by an interrupted write
+			_ = os.Remove(filepath.Join(dir, name))
+			continue
+		}
+		info, err := dirEntry.Info()
+		if err != nil {
+			continue
+		}
+		files = append(files, indexed{cacheEntry{key: name, size: info.Size()}, info.ModTime()})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].accessed.After(files[j].accessed)
+	})
+	for _, file := range files {
+		entry := file.cacheEntry
+		c.entries[entry.key] = c.lru.PushBack(&entry)
+		c.size += entry.size
+	}
+
+	pins, err := os.ReadFile(filepath.Join(dir, pinsFile))
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, err
+	}
+	if len(pins) > 0 {
+		if err := json.Unmarshal(pins, &c.pins); err != nil {
+			// owners pin their content again when they start
+			c.pins = make(map[string][]string)
+		}
+	}
+
+	c.evict()
+
+	return c, nil
+}
+
+// Get returns the content stored under key, or nil if there's none
+func (c *cache) Get(key string) ([]byte, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil, nil
+	}
+
+	path := filepath.Join(c.dir, key)
+	content, err := os.ReadFile(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		c.remove(element)
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// the modification time records the last access, so that the order survives restarts
+	now := time.Now()
+	_ = os.Chtimes(path, now, now)
+	c.lru.MoveToFront(element)
+
+	return content, nil
+}
+
+// Has returns whether content is stored under key
+func (c *cache) Has(key string) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	_, ok := c.entries[key]
+	return ok
+}
+
+// Put stores content under key, then evicts content if the cache is over its maximum size
+func (c *cache) Put(key string, content []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(element)
+		return nil
+	}
+
+	file, err := os.CreateTemp(c.dir, tempPrefix)
+	if err != nil {
+		return err
+	}
+	_, err = file.Write(content)
+	if closeErr := file.Close(); err == nil {
+		err = closeErr
+	}
+	if err == nil {
+		err = os.Rename(file.Name(), filepath.Join(c.dir, key))
+	}
+	if err != nil {
+		_ = os.Remove(file.Name())
+		return err
+	}
+
+	entry := &cacheEntry{key: key, size: int64(len(content))}
+	c.entries[key] = c.lru.PushFront(entry)
+	c.size += entry.size
+	c.evict()
+
+	return nil
+}
+
+// Pin keeps the content stored under keys in the cache until owner unpins it.
+// Keys can be pinned before their content is stored.
+func (c *cache) Pin(owner string, keys ...string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	pinned := make(map[string]bool)
+	for _, key := range c.pins[owner] {
+		pinned[key] = true
+	}
+	for _, key := range keys {
+		if !pinned[key] {
+			pinned[key] = true
+			c.pins[owner] = append(c.pins[owner], key)
+		}
+	}
+
+	return c.savePins()
+}
+
+// Unpin releases the content pinned by owner, it's evicted right away if the cache is over its maximum size
+func (c *cache) Unpin(owner string) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if _, ok := c.pins[owner]; !ok {
+		return nil
+	}
+	delete(c.pins, owner)
+
+	if err := c.savePins(); err != nil {
+		return err
+	}
+	c.evict()
+
+	return nil
+}
+
+// Pinned returns the keys pinned by owner
+func (c *cache) Pinned(owner string) []string {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	return append([]string(nil), c.pins[owner]...)
+}
+
+func (c *cache) savePins() error {
+	pins, err := json.Marshal(c.pins)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, pinsFile), pins, 0600)
+}
+
+func (c *cache) evict() {
+	if c.size <= c.maxSize {
+		return
+	}
+
+	pinned := make(map[string]bool)
+	for _, keys := range c.pins {
+		for _, key := range keys {
+			pinned[key] = true
+		}
+	}
+
+	element := c.lru.Back()
+	for element != nil && c.size > c.maxSize {
+		previous := element.Prev()
+		entry := element.Value.(*cacheEntry)
+		if !pinned[entry.key] {
+			err := os.Remove(filepath.Join(c.dir, entry.key))
+			if err == nil || errors.Is(err, fs.ErrNotExist) {
+				c.remove(element)
+			}
+		}
+		element = previous
+	}
+}
+
+func (c *cache) remove(element *list.Element) {
+	entry := c.lru.Remove(element).(*cacheEntry)
+	delete(c.entries, entry.key)
+	c.size -= entry.size
+}
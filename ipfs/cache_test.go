@@ -0,0 +1,63 @@
+package ipfs
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheEviction(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newCache(dir, 12)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Pin("owner", "a"))
+	require.NoError(t, c.Put("a", []byte("aaaa")))
+	require.NoError(t, c.Put("b", []byte("bbbb")))
+	require.NoError(t, c.Put("c", []byte("cccc")))
+
+	// reading b makes c the least recently used, as a is pinned
+	content, err := c.Get("b")
+	require.NoError(t, err)
+	require.Equal(t, []byte("bbbb"), content)
+
+	require.NoError(t, c.Put("d", []byte("dddd")))
+	require.True(t, c.Has("a"))
+	require.True(t, c.Has("b"))
+	require.False(t, c.Has("c"))
+	require.True(t, c.Has("d"))
+	_, err = os.Stat(filepath.Join(dir, "c"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+
+	// once unpinned, a is the least recently used
+	require.NoError(t, c.Unpin("owner"))
+	require.NoError(t, c.Put("e", []byte("eeee")))
+	require.False(t, c.Has("a"))
+	require.True(t, c.Has("b"))
+
+	content, err = c.Get("a")
+	require.NoError(t, err)
+	require.Nil(t, content)
+}
+
+func TestCacheReopen(t *testing.T) {
+	dir := t.TempDir()
+	c, err := newCache(dir, 100)
+	require.NoError(t, err)
+
+	require.NoError(t, c.Put("a", []byte("aaaa")))
+	require.NoError(t, c.Pin("owner", "a", "b"))
+	require.NoError(t, c.Pin("owner", "b"))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, tempPrefix+"1"), []byte("partial"), 0600))
+
+	c, err = newCache(dir, 100)
+	require.NoError(t, err)
+	require.True(t, c.Has("a"))
+	require.Equal(t, []string{"a", "b"}, c.Pinned("owner"))
+	require.False(t, c.Has(tempPrefix+"1"))
+	require.False(t, c.Has(pinsFile))
+	_, err = os.Stat(filepath.Join(dir, tempPrefix+"1"))
+	require.ErrorIs(t, err, os.ErrNotExist)
+}
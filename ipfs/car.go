@@ -0,0 +1,392 @@
+package ipfs
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-varint"
+)
+
+const (
+	// maxCARSize bounds the blocks read from a CAR, which are held in memory until its files are assembled
+	maxCARSize = 128 * 1024 * 1024
+	// maxBlockSize is well over the blocks produced by IPFS implementations, which don't exceed 1MiB
+	maxBlockSize = 4 * 1024 * 1024
+	// maxDAGDepth bounds the directories and files nested in a CAR
+	maxDAGDepth = 32
+	// maxDAGNodes bounds the nodes visited while assembling the files of a CAR,
+	// blocks linked several times are visited every time
+	maxDAGNodes = 100000
+
+	carV2HeaderSize = 40
+	// carV2PragmaSize is the size of the CARv1 header announcing a CARv2, length prefix included
+	carV2PragmaSize = 11
+
+	cborTagCID   = 42
+	maxCBORDepth = 8
+	carVersion2  = 2
+)
+
+var (
+	ErrInvalidCAR       = errors.New("invalid CAR")
+	ErrMissingBlock     = errors.New("block missing from CAR")
+	ErrUnsupportedBlock = errors.New("block codec not supported")
+)
+
+// carFile is a file read from a CAR
+type carFile struct {
+	cid     cid.Cid
+	content []byte
+}
+
+// readCARFiles reads a CARv1 or CARv2 and returns the files it has under its roots.
+// Directories are walked, each of their files is returned under its own CID.
+func readCARFiles(r io.Reader) ([]carFile, error) {
+	roots, blocks, err := readCAR(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &carWalker{blocks: blocks, visited: make(map[string]bool)}
+	for _, root := range roots {
+		if err := w.walk(root, 0); err != nil {
+			return nil, err
+		}
+	}
+	return w.files, nil
+}
+
+// readCARFile reads a CAR as gateways answer with ?format=car, and returns the content of the file c.
+// Every block is verified against its CID, so is the file assembled from them.
+func readCARFile(r io.Reader, c cid.Cid) ([]byte, error) {
+	_, blocks, err := readCAR(bufio.NewReader(r))
+	if err != nil {
+		return nil, err
+	}
+
+	w := &carWalker{blocks: blocks, visited: make(map[string]bool)}
+	var content []byte
+	if err := w.assemble(c, 0, &content); err != nil {
+		return nil, err
+	}
+	return content, nil
+}
+
+// readCAR returns the roots of a CAR and its blocks, keyed by the binary form of their CID.
+// Every block is verified against its CID.
+func readCAR(r *bufio.Reader) ([]cid.Cid, map[string][]byte, error) {
+	version, roots, err := readCARHeader(r)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if version == carVersion2 {
+		// a CARv2 wraps a CARv1, found at the data offset
+		var v2Header [carV2HeaderSize]byte
+		if _, err := io.ReadFull(r, v2Header[:]); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+		}
+		dataOffset := binary.LittleEndian.Uint64(v2Header[16:24])
+		dataSize := binary.LittleEndian.Uint64(v2Header[24:32])
+		if dataOffset < carV2PragmaSize+carV2HeaderSize || dataOffset > math.MaxInt32 || dataSize > math.MaxInt64 {
+			return nil, nil, ErrInvalidCAR
+		}
+		if _, err := r.Discard(int(dataOffset - carV2PragmaSize - carV2HeaderSize)); err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+		}
+
+		r = bufio.NewReader(io.LimitReader(r, int64(dataSize)))
+		version, roots, err = readCARHeader(r)
+		if err != nil {
+			return nil, nil, err
+		}
+		if version == carVersion2 {
+			return nil, nil, fmt.Errorf("%w: nested CARv2", ErrInvalidCAR)
+		}
+	}
+
+	blocks := make(map[string][]byte)
+	total := 0
+	for {
+		section, err := readCARSection(r)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+		}
+
+		total += len(section)
+		if total > maxCARSize {
+			return nil, nil, fmt.Errorf("%w: over %d bytes", ErrInvalidCAR, maxCARSize)
+		}
+
+		n, c, err := cid.CidFromBytes(section)
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+		}
+		block := section[n:]
+		if err := verifyBlock(c, block); err != nil {
+			return nil, nil, fmt.Errorf("block %s: %w", c, err)
+		}
+		blocks[c.KeyString()] = block
+	}
+
+	return roots, blocks, nil
+}
+
+// readCARSection reads a length prefixed section, it returns io.EOF only if there are no more sections
+func readCARSection(r *bufio.Reader) ([]byte, error) {
+	size, err := varint.ReadUvarint(r)
+	if err != nil {
+		return nil, err
+	}
+	if size > maxBlockSize {
+		return nil, fmt.Errorf("section of %d bytes", size)
+	}
+	section := make([]byte, size)
+	if _, err := io.ReadFull(r, section); err != nil {
+		if err == io.EOF {
+			return nil, io.ErrUnexpectedEOF
+		}
+		return nil, err
+	}
+	return section, nil
+}
+
+func readCARHeader(r *bufio.Reader) (uint64, []cid.Cid, error) {
+	header, err := readCARSection(r)
+	if err != nil {
+		return 0, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+	}
+
+	value, rest, err := decodeCBOR(header, 0)
+	if err != nil {
+		return 0, nil, err
+	}
+	fields, ok := value.(map[string]interface{})
+	if !ok || len(rest) != 0 {
+		return 0, nil, ErrInvalidCAR
+	}
+
+	version, ok := fields["version"].(uint64)
+	if !ok || version == 0 || version > carVersion2 {
+		return 0, nil, fmt.Errorf("%w: unsupported version", ErrInvalidCAR)
+	}
+	if version == carVersion2 {
+		return version, nil, nil
+	}
+
+	values, ok := fields["roots"].([]interface{})
+	if !ok || len(values) == 0 {
+		return 0, nil, fmt.Errorf("%w: no roots", ErrInvalidCAR)
+	}
+	roots := make([]cid.Cid, len(values))
+	for i, value := range values {
+		root, ok := value.(cid.Cid)
+		if !ok {
+			return 0, nil, fmt.Errorf("%w: invalid root", ErrInvalidCAR)
+		}
+		roots[i] = root
+	}
+	return version, roots, nil
+}
+
+// decodeCBOR decodes the subset of DAG-CBOR used by CAR headers: unsigned integers,
+// strings, arrays, maps keyed by strings and CIDs
+func decodeCBOR(buf []byte, depth int) (interface{}, []byte, error) {
+	if depth > maxCBORDepth || len(buf) == 0 {
+		return nil, nil, ErrInvalidCAR
+	}
+
+	major := buf[0] >> 5
+	info := buf[0] & 0x1f
+	buf = buf[1:]
+
+	var argument uint64
+	switch {
+	case info < 24:
+		argument = uint64(info)
+	case info <= 27:
+		size := 1 << (info - 24)
+		if len(buf) < size {
+			return nil, nil, ErrInvalidCAR
+		}
+		for _, b := range buf[:size] {
+			argument = argument<<8 | uint64(b)
+		}
+		buf = buf[size:]
+	default:
+		return nil, nil, ErrInvalidCAR
+	}
+
+	switch major {
+	case 0:
+		return argument, buf, nil
+	case 2, 3:
+		if argument > uint64(len(buf)) {
+			return nil, nil, ErrInvalidCAR
+		}
+		value := buf[:argument]
+		if major == 3 {
+			return string(value), buf[argument:], nil
+		}
+		return value, buf[argument:], nil
+	case 4:
+		// every item takes at least one byte
+		if argument > uint64(len(buf)) {
+			return nil, nil, ErrInvalidCAR
+		}
+		items := make([]interface{}, argument)
+		for i := range items {
+			var err error
+			items[i], buf, err = decodeCBOR(buf, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return items, buf, nil
+	case 5:
+		if argument > uint64(len(buf)) {
+			return nil, nil, ErrInvalidCAR
+		}
+		fields := make(map[string]interface{}, argument)
+		for i := uint64(0); i < argument; i++ {
+			key, rest, err := decodeCBOR(buf, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+			name, ok := key.(string)
+			if !ok {
+				return nil, nil, ErrInvalidCAR
+			}
+			fields[name], buf, err = decodeCBOR(rest, depth+1)
+			if err != nil {
+				return nil, nil, err
+			}
+		}
+		return fields, buf, nil
+	case 6:
+		value, rest, err := decodeCBOR(buf, depth+1)
+		if err != nil {
+			return nil, nil, err
+		}
+		link, ok := value.([]byte)
+		// CIDs are prefixed with the identity multibase
+		if argument != cborTagCID || !ok || len(link) == 0 || link[0] != 0 {
+			return nil, nil, ErrInvalidCAR
+		}
+		c, err := cid.Cast(link[1:])
+		if err != nil {
+			return nil, nil, fmt.Errorf("%w: %v", ErrInvalidCAR, err)
+		}
+		return c, rest, nil
+	default:
+		return nil, nil, ErrInvalidCAR
+	}
+}
+
+// carWalker assembles the UnixFS files of a CAR
+type carWalker struct {
+	blocks  map[string][]byte
+	visited map[string]bool
+	files   []carFile
+	// nodes is the number of blocks visited, shared ones included
+	nodes int
+}
+
+func (w *carWalker) block(c cid.Cid) ([]byte, error) {
+	w.nodes++
+	if w.nodes > maxDAGNodes {
+		return nil, fmt.Errorf("%w: over %d nodes", ErrInvalidCAR, maxDAGNodes)
+	}
+	block, ok := w.blocks[c.KeyString()]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrMissingBlock, c)
+	}
+	return block, nil
+}
+
+func (w *carWalker) walk(c cid.Cid, depth int) error {
+	if depth > maxDAGDepth {
+		return ErrInvalidCAR
+	}
+	if w.visited[c.KeyString()] {
+		return nil
+	}
+	w.visited[c.KeyString()] = true
+
+	if c.Type() == cid.DagProtobuf {
+		block, err := w.block(c)
+		if err != nil {
+			return err
+		}
+		node, err := decodeUnixFSNode(block)
+		if err != nil {
+			return err
+		}
+		if node.hasUnixFS && node.kind == unixFSDirectory {
+			for _, link := range node.links {
+				if err := w.walk(link.cid, depth+1); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}
+
+	var content []byte
+	if err := w.assemble(c, depth, &content); err != nil {
+		return err
+	}
+	w.files = append(w.files, carFile{cid: c, content: content})
+	return nil
+}
+
+// assemble appends the content of the file c to content
+func (w *carWalker) assemble(c cid.Cid, depth int, content *[]byte) error {
+	if depth > maxDAGDepth {
+		return ErrInvalidCAR
+	}
+
+	block, err := w.block(c)
+	if err != nil {
+		return err
+	}
+
+	var data []byte
+	var links []dagPBLink
+	switch c.Type() {
+	case cid.Raw:
+		data = block
+	case cid.DagProtobuf:
+		node, err := decodeUnixFSNode(block)
+		if err != nil {
+			return err
+		}
+		if !node.hasUnixFS || (node.kind != unixFSFile && node.kind != unixFSRaw) {
+			return fmt.Errorf("%w: %s isn't a file", ErrUnsupportedBlock, c)
+		}
+		data = node.data
+		links = node.links
+	default:
+		return fmt.Errorf("%w: %s", ErrUnsupportedBlock, c)
+	}
+
+	if len(*content)+len(data) > maxContentSize {
+		return fmt.Errorf("%w: file over %d bytes", ErrInvalidCAR, maxContentSize)
+	}
+	*content = append(*content, data...)
+
+	for _, link := range links {
+		if err := w.assemble(link.cid, depth+1, content); err != nil {
+			return err
+		}
+	}
+	return nil
+}
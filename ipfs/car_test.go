@@ -0,0 +1,171 @@
+package ipfs
+
+import (
+	"bytes"
+	"encoding/binary"
+	"testing"
+
+	"github.com/ipfs/go-cid"
+	"github.com/multiformats/go-multihash"
+	"github.com/multiformats/go-varint"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+func sumCID(t *testing.T, version uint64, codec uint64, block []byte) cid.Cid {
+	c, err := cid.Prefix{Version: version, Codec: codec, MhType: multihash.SHA2_256, MhLength: -1}.Sum(block)
+	require.NoError(t, err)
+	return c
+}
+
+// encodeDagPBNode returns a dag-pb block with the given UnixFS data and links
+func encodeDagPBNode(kind uint64, data []byte, links ...dagPBLink) []byte {
+	var node []byte
+	for _, link := range links {
+		var pbLink []byte
+		pbLink = protowire.AppendTag(pbLink, 1, protowire.BytesType)
+		pbLink = protowire.AppendBytes(pbLink, link.cid.Bytes())
+		pbLink = protowire.AppendTag(pbLink, 2, protowire.BytesType)
+		pbLink = protowire.AppendBytes(pbLink, []byte(link.name))
+		node = protowire.AppendTag(node, 2, protowire.BytesType)
+		node = protowire.AppendBytes(node, pbLink)
+	}
+
+	var unixFS []byte
+	unixFS = protowire.AppendTag(unixFS, 1, protowire.VarintType)
+	unixFS = protowire.AppendVarint(unixFS, kind)
+	if data != nil {
+		unixFS = protowire.AppendTag(unixFS, 2, protowire.BytesType)
+		unixFS = protowire.AppendBytes(unixFS, data)
+	}
+	node = protowire.AppendTag(node, 1, protowire.BytesType)
+	return protowire.AppendBytes(node, unixFS)
+}
+
+func appendCARSection(car []byte, section []byte) []byte {
+	car = append(car, varint.ToUvarint(uint64(len(section)))...)
+	return append(car, section...)
+}
+
+// makeCAR returns a CARv1 with the given roots and blocks
+func makeCAR(roots []cid.Cid, blocks map[cid.Cid][]byte) []byte {
+	// {"roots": [...], "version": 1} in DAG-CBOR
+	header := []byte{0xa2, 0x65}
+	header = append(header, "roots"...)
+	header = append(header, 0x80|byte(len(roots)))
+	for _, root := range roots {
+		link := append([]byte{0}, root.Bytes()...)
+		header = append(header, 0xd8, cborTagCID, 0x58, byte(len(link)))
+		header = append(header, link...)
+	}
+	header = append(header, 0x67)
+	header = append(header, "version"...)
+	header = append(header, 0x01)
+
+	car := appendCARSection(nil, header)
+	for c, block := range blocks {
+		car = appendCARSection(car, append(c.Bytes(), block...))
+	}
+	return car
+}
+
+// makeCARv2 wraps a CARv1, with some padding before it
+func makeCARv2(v1 []byte) []byte {
+	car := []byte{0x0a, 0xa1, 0x67}
+	car = append(car, "version"...)
+	car = append(car, 0x02)
+
+	header := make([]byte, carV2HeaderSize)
+	binary.LittleEndian.PutUint64(header[16:24], carV2PragmaSize+carV2HeaderSize+7)
+	binary.LittleEndian.PutUint64(header[24:32], uint64(len(v1)))
+	car = append(car, header...)
+	car = append(car, make([]byte, 7)...)
+	return append(car, v1...)
+}
+
+func TestVerifyContent(t *testing.T) {
+	content := []byte("hello world\n")
+
+	// the CIDv0 of the file added to IPFS
+	c, err := cid.Decode("QmT78zSuBmuS4z925WZfrqQ1qHaJ56DQaTfyMUF7F8ff5o")
+	require.NoError(t, err)
+	require.NoError(t, verifyContent(c, content))
+	require.ErrorIs(t, verifyContent(c, []byte("hello world!")), ErrContentMismatch)
+
+	raw := sumCID(t, 1, cid.Raw, content)
+	require.NoError(t, verifyContent(raw, content))
+	require.ErrorIs(t, verifyContent(raw, []byte("hello world!")), ErrContentMismatch)
+
+	require.ErrorIs(t, verifyContent(c, make([]byte, chunkSize+1)), ErrUnverifiable)
+}
+
+func TestReadCARFiles(t *testing.T) {
+	// a file of two chunks and a file of a single chunk, in a directory
+	chunk1 := bytes.Repeat([]byte("a"), 100)
+	chunk2 := bytes.Repeat([]byte("b"), 50)
+	chunk1CID := sumCID(t, 1, cid.Raw, chunk1)
+	chunk2CID := sumCID(t, 1, cid.Raw, chunk2)
+	bigFile := encodeDagPBNode(unixFSFile, nil, dagPBLink{cid: chunk1CID}, dagPBLink{cid: chunk2CID})
+	bigFileCID := sumCID(t, 0, cid.DagProtobuf, bigFile)
+
+	smallContent := []byte("hello world\n")
+	smallFile := encodeUnixFSLeaf(smallContent)
+	smallFileCID := sumCID(t, 0, cid.DagProtobuf, smallFile)
+
+	directory := encodeDagPBNode(unixFSDirectory, nil,
+		dagPBLink{cid: bigFileCID, name: "big"},
+		dagPBLink{cid: smallFileCID, name: "small"})
+	directoryCID := sumCID(t, 1, cid.DagProtobuf, directory)
+
+	blocks := map[cid.Cid][]byte{
+		chunk1CID:    chunk1,
+		chunk2CID:    chunk2,
+		bigFileCID:   bigFile,
+		smallFileCID: smallFile,
+		directoryCID: directory,
+	}
+	car := makeCAR([]cid.Cid{directoryCID}, blocks)
+
+	expected := []carFile{
+		{cid: bigFileCID, content: append(append([]byte(nil), chunk1...), chunk2...)},
+		{cid: smallFileCID, content: smallContent},
+	}
+
+	files, err := readCARFiles(bytes.NewReader(car))
+	require.NoError(t, err)
+	require.Equal(t, expected, files)
+
+	files, err = readCARFiles(bytes.NewReader(makeCARv2(car)))
+	require.NoError(t, err)
+	require.Equal(t, expected, files)
+
+	// a block that doesn't match its CID
+	blocks[chunk2CID] = []byte("tampered")
+	_, err = readCARFiles(bytes.NewReader(makeCAR([]cid.Cid{directoryCID}, blocks)))
+	require.ErrorIs(t, err, ErrContentMismatch)
+
+	delete(blocks, chunk2CID)
+	_, err = readCARFiles(bytes.NewReader(makeCAR([]cid.Cid{directoryCID}, blocks)))
+	require.ErrorIs(t, err, ErrMissingBlock)
+
+	_, err = readCARFiles(bytes.NewReader(car[:len(car)-1]))
+	require.ErrorIs(t, err, ErrInvalidCAR)
+
+	_, err = readCARFiles(bytes.NewReader([]byte("not a car")))
+	require.ErrorIs(t, err, ErrInvalidCAR)
+}
+
+func TestReadCARFileSharedLinks(t *testing.T) {
+	// every node links twice to the one below, the leaf is visited 2^20 times
+	leaf := []byte{}
+	c := sumCID(t, 1, cid.Raw, leaf)
+	blocks := map[cid.Cid][]byte{c: leaf}
+	for i := 0; i < 20; i++ {
+		node := encodeDagPBNode(unixFSFile, nil, dagPBLink{cid: c}, dagPBLink{cid: c})
+		c = sumCID(t, 0, cid.DagProtobuf, node)
+		blocks[c] = node
+	}
+
+	_, err := readCARFile(bytes.NewReader(makeCAR([]cid.Cid{c}, blocks)), c)
+	require.ErrorIs(t, err, ErrInvalidCAR)
+}
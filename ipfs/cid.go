@@ -0,0 +1,186 @@
+package ipfs
+
+import (
+	"errors"
+
+	"github.com/ipfs/go-cid"
+	"google.golang.org/protobuf/encoding/protowire"
+)
+
+const (
+	// chunkSize is the size of the blocks files are split in when added to IPFS with the default settings
+	chunkSize = 256 * 1024
+
+	unixFSRaw       = 0
+	unixFSDirectory = 1
+	unixFSFile      = 2
+)
+
+var (
+	ErrContentMismatch = errors.New("content doesn't match its cid")
+	ErrUnverifiable    = errors.New("content spanning several blocks can't be verified")
+	ErrInvalidBlock    = errors.New("invalid dag-pb block")
+)
+
+// verifyContent checks that content is the file identified by c. Gateways return files rather
+// than blocks, so files of a single block are rebuilt into the block added to IPFS.
+func verifyContent(c cid.Cid, content []byte) error {
+	var block []byte
+	switch c.Type() {
+	case cid.Raw:
+		block = content
+	case cid.DagProtobuf:
+		if len(content) > chunkSize {
+			return ErrUnverifiable
+		}
+		block = encodeUnixFSLeaf(content)
+	default:
+		return ErrUnverifiable
+	}
+	return verifyBlock(c, block)
+}
+
+// verifyBlock checks that block hashes to c
+func verifyBlock(c cid.Cid, block []byte) error {
+	sum, err := c.Prefix().Sum(block)
+	if err != nil {
+		return err
+	}
+	if !sum.Equals(c) {
+		return ErrContentMismatch
+	}
+	return nil
+}
+
+// encodeUnixFSLeaf returns the dag-pb block of a file of a single block
+func encodeUnixFSLeaf(content []byte) []byte {
+	var data []byte
+	data = protowire.AppendTag(data, 1, protowire.VarintType)
+	data = protowire.AppendVarint(data, unixFSFile)
+	if len(content) > 0 {
+		data = protowire.AppendTag(data, 2, protowire.BytesType)
+		data = protowire.AppendBytes(data, content)
+	}
+	data = protowire.AppendTag(data, 3, protowire.VarintType)
+	data = protowire.AppendVarint(data, uint64(len(content)))
+
+	var node []byte
+	node = protowire.AppendTag(node, 1, protowire.BytesType)
+	return protowire.AppendBytes(node, data)
+}
+
+// dagPBLink is a link of a dag-pb node
+type dagPBLink struct {
+	cid  cid.Cid
+	name string
+}
+
+// unixFSNode is a dag-pb node with its UnixFS data decoded
+type unixFSNode struct {
+	links     []dagPBLink
+	kind      uint64
+	data      []byte
+	hasUnixFS bool
+}
+
+// decodeUnixFSNode decodes a dag-pb block and the UnixFS data it carries
+func decodeUnixFSNode(block []byte) (*unixFSNode, error) {
+	node := &unixFSNode{}
+	var unixFSData []byte
+	err := forEachField(block, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			unixFSData = value
+		case 2:
+			link, err := decodeDagPBLink(value)
+			if err != nil {
+				return err
+			}
+			node.links = append(node.links, link)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if unixFSData == nil {
+		return node, nil
+	}
+	node.hasUnixFS = true
+	err = forEachField(unixFSData, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			kind, n := protowire.ConsumeVarint(value)
+			if n < 0 {
+				return ErrInvalidBlock
+			}
+			node.kind = kind
+		case 2:
+			node.data = value
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return node, nil
+}
+
+func decodeDagPBLink(buf []byte) (dagPBLink, error) {
+	var link dagPBLink
+	err := forEachField(buf, func(num protowire.Number, value []byte) error {
+		switch num {
+		case 1:
+			c, err := cid.Cast(value)
+			if err != nil {
+				return ErrInvalidBlock
+			}
+			link.cid = c
+		case 2:
+			link.name = string(value)
+		}
+		return nil
+	})
+	if err != nil {
+		return link, err
+	}
+	if !link.cid.Defined() {
+		return link, ErrInvalidBlock
+	}
+	return link, nil
+}
+
+// forEachField calls f with the fields of a protobuf message, varints are passed encoded
+func forEachField(buf []byte, f func(num protowire.Number, value []byte) error) error {
+	for len(buf) > 0 {
+		num, typ, n := protowire.ConsumeTag(buf)
+		if n < 0 {
+			return ErrInvalidBlock
+		}
+		buf = buf[n:]
+
+		var value []byte
+		switch typ {
+		case protowire.BytesType:
+			v, n := protowire.ConsumeBytes(buf)
+			if n < 0 {
+				return ErrInvalidBlock
+			}
+			value = v
+			buf = buf[n:]
+		default:
+			n := protowire.ConsumeFieldValue(num, typ, buf)
+			if n < 0 {
+				return ErrInvalidBlock
+			}
+			value = buf[:n]
+			buf = buf[n:]
+		}
+
+		if err := f(num, value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
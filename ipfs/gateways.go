@@ -0,0 +1,106 @@
+package ipfs
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+const (
+	gatewayMinBackoff = 30 * time.Second
+	gatewayMaxBackoff = time.Hour
+)
+
+// fallbackGatewayURLs are tried after the gateway configured in params
+var fallbackGatewayURLs = []string{
+	"https://ipfs.io/ipfs/",
+	"https://dweb.link/ipfs/",
+}
+
+type gateway struct {
+	url string
+	// failures is the number of requests that failed in a row
+	failures int
+	// retryAt is the time before which the gateway is skipped, after it failed
+	retryAt time.Time
+}
+
+// gateways tracks the health of IPFS gateways, those that fail are backed off exponentially
+type gateways struct {
+	mu       sync.Mutex
+	gateways []*gateway
+	now      func() time.Time
+}
+
+func newGateways(urls []string) *gateways {
+	g := &gateways{now: time.Now}
+	for _, url := range urls {
+		g.gateways = append(g.gateways, &gateway{url: url})
+	}
+	return g
+}
+
+// Ordered returns the URLs of the gateways to try, healthiest first. Gateways
+// backing off come last, so that content is still fetched when they all failed.
+func (g *gateways) Ordered() []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	now := g.now()
+	ordered := append([]*gateway(nil), g.gateways...)
+	sort.SliceStable(ordered, func(i, j int) bool {
+		iAvailable := !now.Before(ordered[i].retryAt)
+		jAvailable := !now.Before(ordered[j].retryAt)
+		if iAvailable != jAvailable {
+			return iAvailable
+		}
+		if !iAvailable {
+			return ordered[i].retryAt.Before(ordered[j].retryAt)
+		}
+		return ordered[i].failures < ordered[j].failures
+	})
+
+	urls := make([]string, len(ordered))
+	for i, gw := range ordered {
+		urls[i] = gw.url
+	}
+	return urls
+}
+
+func (g *gateways) Succeeded(url string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	if gw := g.find(url); gw != nil {
+		gw.failures = 0
+		gw.retryAt = time.Time{}
+	}
+}
+
+func (g *gateways) Failed(url string) {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+
+	gw := g.find(url)
+	if gw == nil {
+		return
+	}
+	gw.failures++
+	backoff := gatewayMinBackoff
+	for i := 1; i < gw.failures && backoff < gatewayMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > gatewayMaxBackoff {
+		backoff = gatewayMaxBackoff
+	}
+	gw.retryAt = g.now().Add(backoff)
+}
+
+func (g *gateways) find(url string) *gateway {
+	for _, gw := range g.gateways {
+		if gw.url == url {
+			return gw
+		}
+	}
+	return nil
+}
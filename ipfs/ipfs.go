@@ -1,9 +1,11 @@
 package ipfs
 
 import (
+	"bytes"
 	"context"
 	"errors"
-	"io/ioutil"
+	"fmt"
+	"io"
 	"net/http"
 	"os"
 	"path/filepath"
@@ -20,7 +22,15 @@ import (
 	"github.com/status-im/status-go/params"
 )
 
-const maxRequestsPerSecond = 3
+const (
+	maxRequestsPerSecond = 3
+	// maxContentSize bounds the content downloaded or imported for a CID
+	maxContentSize = 20 * 1024 * 1024
+	// maxCARDownloadSize bounds the CARs downloaded, the blocks of a file weigh more than its content
+	maxCARDownloadSize = 2 * maxContentSize
+
+	carContentType = "application/vnd.ipld.car"
+)
 
 type taskResponse struct {
 	err      error
@@ -28,11 +38,12 @@ type taskResponse struct {
 }
 
 type taskRequest struct {
-	cid      string
-	download bool
+	cid      cid.Cid
 	doneChan chan taskResponse
 }
 
+// Downloader fetches content from IPFS gateways and keeps it in a local cache.
+// Content is verified against its CID, and pinned content stays available offline.
 type Downloader struct {
 	ctx             context.Context
 	cancel          func()
@@ -41,11 +52,31 @@ type Downloader struct {
 	rateLimiterChan chan taskRequest
 	inputTaskChan   chan taskRequest
 	client          *http.Client
+	cache           *cache
+	cacheMaxSize    int64
+	gatewayURLs     []string
+	gateways        *gateways
 
 	quit chan struct{}
 }
 
-func NewDownloader(rootDir string) *Downloader {
+type DownloaderOption func(*Downloader)
+
+// WithGateways replaces the gateways content is fetched from, the URLs are prefixes of the CIDs
+func WithGateways(urls ...string) DownloaderOption {
+	return func(d *Downloader) {
+		d.gatewayURLs = urls
+	}
+}
+
+// WithCacheMaxSize sets the size over which unpinned content is evicted from the cache
+func WithCacheMaxSize(size int64) DownloaderOption {
+	return func(d *Downloader) {
+		d.cacheMaxSize = size
+	}
+}
+
+func NewDownloader(rootDir string, opts ...DownloaderOption) *Downloader {
 	ipfsDir := filepath.Clean(filepath.Join(rootDir, "./ipfs"))
 	if err := os.MkdirAll(ipfsDir, 0700); err != nil {
 		panic("could not create IPFSDir")
@@ -63,10 +94,23 @@ func NewDownloader(rootDir string) *Downloader {
 		client: &http.Client{
 			Timeout: time.Second * 5,
 		},
+		cacheMaxSize: defaultCacheMaxSize,
+		gatewayURLs:  append([]string{params.IpfsGatewayURL}, fallbackGatewayURLs...),
 
 		quit: make(chan struct{}, 1),
 	}
 
+	for _, opt := range opts {
+		opt(d)
+	}
+
+	contentCache, err := newCache(ipfsDir, d.cacheMaxSize)
+	if err != nil {
+		panic("could not index IPFSDir")
+	}
+	d.cache = contentCache
+	d.gateways = newGateways(d.gatewayURLs)
+
 	go d.taskDispatcher()
 	go d.worker()
 
@@ -87,7 +131,7 @@ func (d *Downloader) Stop() {
 func (d *Downloader) worker() {
 	defer common.LogOnPanic()
 	for request := range d.rateLimiterChan {
-		resp, err := d.download(request.cid, request.download)
+		resp, err := d.download(request.cid)
 		request.doneChan <- taskResponse{
 			err:      err,
 			response: resp,
@@ -111,7 +155,7 @@ func (d *Downloader) taskDispatcher() {
 	}
 }
 
-func hashToCid(hash []byte) (string, error) {
+func hashToCid(hash []byte) (cid.Cid, error) {
 	// contract response includes a contenthash, which needs to be decoded to reveal
 	// an IPFS identifier. Once decoded, download the content from IPFS. This content
 	// is in EDN format, ie https://ipfs.infura.io/ipfs/QmWVVLwVKCwkVNjYJrRzQWREVvEk917PhbHYAUhA1gECTM
@@ -119,53 +163,49 @@ func hashToCid(hash []byte) (string, error) {
 
 	data, codec, err := multicodec.RemoveCodec(hash)
 	if err != nil {
-		return "", err
+		return cid.Undef, err
 	}
 
 	codecName, err := multicodec.Name(codec)
 	if err != nil {
-		return "", err
+		return cid.Undef, err
 	}
 
 	if codecName != "ipfs-ns" {
-		return "", errors.New("codecName is not ipfs-ns")
+		return cid.Undef, errors.New("codecName is not ipfs-ns")
 	}
 
-	thisCID, err := cid.Parse(data)
-	if err != nil {
-		return "", err
-	}
-
-	return thisCID.Hash().B58String(), nil
+	return cid.Parse(data)
 }
 
-func decodeStringHash(input string) (string, error) {
+func decodeStringHash(input string) (cid.Cid, error) {
 	hash, err := hexutil.Decode("0x" + input)
 	if err != nil {
-		return "", err
+		return cid.Undef, err
 	}
 
-	cid, err := hashToCid(hash)
-	if err != nil {
-		return "", err
-	}
+	return hashToCid(hash)
+}
 
-	return cid, nil
+// cacheKey is the name of the file content is cached in, the base58 multihash
+// is the CIDv0 of content added with the default settings
+func cacheKey(c cid.Cid) string {
+	return c.Hash().B58String()
 }
 
-// Get checks if an IPFS image exists and returns it from cache
-// otherwise downloads it from INFURA's ipfs gateway
-func (d *Downloader) Get(hash string, download bool) ([]byte, error) {
-	cid, err := decodeStringHash(hash)
+// Get returns the content of an IPFS contenthash, from the cache if it's there,
+// otherwise it's downloaded from the healthiest gateway and cached
+func (d *Downloader) Get(hash string) ([]byte, error) {
+	c, err := decodeStringHash(hash)
 	if err != nil {
 		return nil, err
 	}
 
-	exists, content, err := d.exists(cid)
+	content, err := d.cache.Get(cacheKey(c))
 	if err != nil {
 		return nil, err
 	}
-	if exists {
+	if content != nil {
 		return content, nil
 	}
 
@@ -174,8 +214,7 @@ func (d *Downloader) Get(hash string, download bool) ([]byte, error) {
 	d.wg.Add(1)
 
 	d.inputTaskChan <- taskRequest{
-		cid:      cid,
-		download: download,
+		cid:      c,
 		doneChan: doneChan,
 	}
 
@@ -187,24 +226,122 @@ func (d *Downloader) Get(hash string, download bool) ([]byte, error) {
 	return done.response, done.err
 }
 
-func (d *Downloader) exists(cid string) (bool, []byte, error) {
-	path := filepath.Join(d.ipfsDir, cid)
-	_, err := os.Stat(path)
-	if err == nil {
-		fileContent, err := os.ReadFile(path)
-		return true, fileContent, err
+// Pin keeps the content of IPFS contenthashes in the cache until owner unpins it
+func (d *Downloader) Pin(owner string, hashes ...string) error {
+	keys := make([]string, 0, len(hashes))
+	for _, hash := range hashes {
+		c, err := decodeStringHash(hash)
+		if err != nil {
+			return err
+		}
+		keys = append(keys, cacheKey(c))
+	}
+	return d.cache.Pin(owner, keys...)
+}
+
+// Unpin releases the content pinned by owner, it can then be evicted from the cache
+func (d *Downloader) Unpin(owner string) error {
+	return d.cache.Unpin(owner)
+}
+
+// Prefetch downloads the content of IPFS contenthashes that isn't cached yet
+func (d *Downloader) Prefetch(hashes ...string) {
+	for _, hash := range hashes {
+		if d.ctx.Err() != nil {
+			return
+		}
+		if _, err := d.Get(hash); err != nil {
+			logutils.ZapLogger().Warn("failed to prefetch ipfs content", zap.String("hash", hash), zap.Error(err))
+		}
+	}
+}
+
+// ImportCAR caches the files of a CAR and pins them for owner, so that they're available
+// offline. It returns the CIDs of the files imported.
+func (d *Downloader) ImportCAR(r io.Reader, owner string) ([]string, error) {
+	files, err := readCARFiles(r)
+	if err != nil {
+		return nil, err
 	}
 
-	return false, nil, nil
+	cids := make([]string, len(files))
+	keys := make([]string, len(files))
+	for i, file := range files {
+		cids[i] = file.cid.String()
+		keys[i] = cacheKey(file.cid)
+	}
+
+	// pinned first, so that the files aren't evicted as they're stored
+	if err := d.cache.Pin(owner, keys...); err != nil {
+		return nil, err
+	}
+	for i, file := range files {
+		if err := d.cache.Put(keys[i], file.content); err != nil {
+			return nil, err
+		}
+	}
+	return cids, nil
+}
+
+// download fetches content from the gateways in turn until one returns content matching c
+func (d *Downloader) download(c cid.Cid) ([]byte, error) {
+	err := errors.New("no ipfs gateway")
+	for _, gatewayURL := range d.gateways.Ordered() {
+		var content []byte
+		content, err = d.fetchVerified(gatewayURL, c)
+		if d.ctx.Err() != nil {
+			return nil, d.ctx.Err()
+		}
+		if err != nil {
+			logutils.ZapLogger().Warn("could not load data from ipfs gateway",
+				zap.String("gateway", gatewayURL),
+				zap.Stringer("cid", c),
+				zap.Error(err))
+			d.gateways.Failed(gatewayURL)
+			continue
+		}
+
+		d.gateways.Succeeded(gatewayURL)
+		if err := d.cache.Put(cacheKey(c), content); err != nil {
+			return nil, err
+		}
+		return content, nil
+	}
+
+	return nil, err
 }
 
-func (d *Downloader) download(cid string, download bool) ([]byte, error) {
-	path := filepath.Join(d.ipfsDir, cid)
+// fetchVerified fetches the content of c from a gateway and verifies it against c. Gateways return files
+// rather than the blocks they're made of, files spanning several blocks are fetched again as a CAR
+// whose blocks are verified one by one. Content that can't be verified is never returned.
+func (d *Downloader) fetchVerified(gatewayURL string, c cid.Cid) ([]byte, error) {
+	content, err := d.fetch(gatewayURL+c.String(), "", maxContentSize)
+	if err != nil {
+		return nil, err
+	}
+	err = verifyContent(c, content)
+	if err == nil {
+		return content, nil
+	}
+	if !errors.Is(err, ErrUnverifiable) {
+		return nil, err
+	}
+
+	car, err := d.fetch(gatewayURL+c.String()+"?format=car", carContentType, maxCARDownloadSize)
+	if err != nil {
+		return nil, err
+	}
+	return readCARFile(bytes.NewReader(car), c)
+}
 
-	req, err := http.NewRequest(http.MethodGet, params.IpfsGatewayURL+cid, nil)
+func (d *Downloader) fetch(url string, accept string, maxSize int) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
 	if err != nil {
 		return nil, err
 	}
+	if accept != "" {
+		req.Header.Set("Accept", accept)
+	}
 
 	req = req.WithContext(d.ctx)
 
@@ -220,21 +357,15 @@ func (d *Downloader) download(cid string, download bool) ([]byte, error) {
 	}()
 
 	if resp.StatusCode < 200 || resp.StatusCode > 299 {
-		logutils.ZapLogger().Error("could not load data for", zap.String("cid", cid), zap.Int("code", resp.StatusCode))
-		return nil, errors.New("could not load ipfs data")
+		return nil, fmt.Errorf("could not load ipfs data: status %d", resp.StatusCode)
 	}
 
-	fileContent, err := ioutil.ReadAll(resp.Body)
+	fileContent, err := io.ReadAll(io.LimitReader(resp.Body, int64(maxSize)+1))
 	if err != nil {
 		return nil, err
 	}
-
-	if download {
-		// #nosec G306
-		err = os.WriteFile(path, fileContent, 0700)
-		if err != nil {
-			return nil, err
-		}
+	if len(fileContent) > maxSize {
+		return nil, fmt.Errorf("could not load ipfs data: over %d bytes", maxSize)
 	}
 
 	return fileContent, nil
@@ -0,0 +1,153 @@
+package ipfs
+
+import (
+	"bytes"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/ipfs/go-cid"
+	"github.com/stretchr/testify/require"
+	"github.com/wealdtech/go-multicodec"
+)
+
+func contentHash(t *testing.T, c cid.Cid) string {
+	hash, err := multicodec.AddCodec("ipfs-ns", c.Bytes())
+	require.NoError(t, err)
+	return hex.EncodeToString(hash)
+}
+
+func newGatewayServer(content []byte, status int, requests *int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(requests, 1)
+		w.WriteHeader(status)
+		_, _ = w.Write(content)
+	}))
+}
+
+func TestDownloaderGatewayFallback(t *testing.T) {
+	content := []byte("hello world\n")
+	c := sumCID(t, 1, cid.DagProtobuf, encodeUnixFSLeaf(content))
+
+	var failingRequests, lyingRequests, healthyRequests int32
+	failing := newGatewayServer(nil, http.StatusBadGateway, &failingRequests)
+	defer failing.Close()
+	lying := newGatewayServer([]byte("not the content"), http.StatusOK, &lyingRequests)
+	defer lying.Close()
+	healthy := newGatewayServer(content, http.StatusOK, &healthyRequests)
+	defer healthy.Close()
+
+	d := NewDownloader(t.TempDir(), WithGateways(failing.URL+"/", lying.URL+"/", healthy.URL+"/"))
+	defer d.Stop()
+
+	fetched, err := d.Get(contentHash(t, c))
+	require.NoError(t, err)
+	require.Equal(t, content, fetched)
+	require.Equal(t, []int32{1, 1, 1}, []int32{failingRequests, lyingRequests, healthyRequests})
+	require.Equal(t, []string{healthy.URL + "/", failing.URL + "/", lying.URL + "/"}, d.gateways.Ordered())
+
+	// served from the cache
+	fetched, err = d.Get(contentHash(t, c))
+	require.NoError(t, err)
+	require.Equal(t, content, fetched)
+	require.Equal(t, int32(1), healthyRequests)
+
+	// none of the gateways has the content
+	other := sumCID(t, 1, cid.Raw, []byte("other"))
+	_, err = d.Get(contentHash(t, other))
+	require.ErrorIs(t, err, ErrContentMismatch)
+}
+
+func TestDownloaderVerifiesCAR(t *testing.T) {
+	// a file of two chunks, which can't be verified from its content alone
+	chunk1 := bytes.Repeat([]byte("a"), chunkSize)
+	chunk2 := bytes.Repeat([]byte("b"), 50)
+	chunk1CID := sumCID(t, 1, cid.Raw, chunk1)
+	chunk2CID := sumCID(t, 1, cid.Raw, chunk2)
+	file := encodeDagPBNode(unixFSFile, nil, dagPBLink{cid: chunk1CID}, dagPBLink{cid: chunk2CID})
+	c := sumCID(t, 0, cid.DagProtobuf, file)
+	content := append(append([]byte(nil), chunk1...), chunk2...)
+	car := makeCAR([]cid.Cid{c}, map[cid.Cid][]byte{c: file, chunk1CID: chunk1, chunk2CID: chunk2})
+
+	// the lying gateway serves other content, and a CAR missing the file
+	newServer := func(content []byte, car []byte) *httptest.Server {
+		return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Query().Get("format") == "car" {
+				require.Equal(t, carContentType, r.Header.Get("Accept"))
+				_, _ = w.Write(car)
+				return
+			}
+			_, _ = w.Write(content)
+		}))
+	}
+	lying := newServer(bytes.Repeat([]byte("c"), len(content)), makeCAR([]cid.Cid{c}, map[cid.Cid][]byte{chunk1CID: chunk1}))
+	defer lying.Close()
+
+	d := NewDownloader(t.TempDir(), WithGateways(lying.URL+"/"))
+	defer d.Stop()
+
+	_, err := d.Get(contentHash(t, c))
+	require.ErrorIs(t, err, ErrMissingBlock)
+	require.False(t, d.cache.Has(cacheKey(c)))
+
+	healthy := newServer(content, car)
+	defer healthy.Close()
+
+	d = NewDownloader(t.TempDir(), WithGateways(healthy.URL+"/"))
+	defer d.Stop()
+
+	fetched, err := d.Get(contentHash(t, c))
+	require.NoError(t, err)
+	require.Equal(t, content, fetched)
+}
+
+func TestDownloaderImportCAR(t *testing.T) {
+	content := []byte("hello world\n")
+	file := encodeUnixFSLeaf(content)
+	c := sumCID(t, 0, cid.DagProtobuf, file)
+	car := makeCAR([]cid.Cid{c}, map[cid.Cid][]byte{c: file})
+
+	var requests int32
+	gateway := newGatewayServer(nil, http.StatusNotFound, &requests)
+	defer gateway.Close()
+
+	d := NewDownloader(t.TempDir(), WithGateways(gateway.URL+"/"), WithCacheMaxSize(0))
+	defer d.Stop()
+
+	cids, err := d.ImportCAR(bytes.NewReader(car), "import")
+	require.NoError(t, err)
+	require.Equal(t, []string{c.String()}, cids)
+
+	// pinned, so kept offline even though the cache is full
+	fetched, err := d.Get(contentHash(t, c))
+	require.NoError(t, err)
+	require.Equal(t, content, fetched)
+	require.Zero(t, requests)
+}
+
+func TestGatewaysBackoff(t *testing.T) {
+	now := time.Unix(0, 0)
+	g := newGateways([]string{"a", "b", "c"})
+	g.now = func() time.Time { return now }
+
+	g.Failed("a")
+	g.Failed("b")
+	g.Failed("b")
+	g.Failed("b")
+	require.Equal(t, []string{"c", "a", "b"}, g.Ordered())
+
+	// a is tried again once its backoff is over, after the gateways that didn't fail
+	now = now.Add(gatewayMinBackoff)
+	require.Equal(t, []string{"c", "a", "b"}, g.Ordered())
+	g.Succeeded("a")
+	g.Failed("c")
+	require.Equal(t, []string{"a", "c", "b"}, g.Ordered())
+
+	for i := 0; i < 20; i++ {
+		g.Failed("a")
+	}
+	require.Equal(t, now.Add(gatewayMaxBackoff), g.gateways[0].retryAt)
+}
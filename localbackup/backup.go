@@ -0,0 +1,305 @@
+// Package localbackup exports the databases of an account into a single encrypted
+// archive and restores them. Message media is stored in the app database, so it's
+// part of the archive.
+package localbackup
+
+import (
+	"archive/tar"
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/status-im/status-go/multiaccounts"
+)
+
+// FormatVersion is the version of the archive layout, bumped on incompatible changes
+const FormatVersion = 1
+
+const manifestName = "manifest.json"
+
+const (
+	AppDBFile    = "app.db"
+	WalletDBFile = "wallet.db"
+)
+
+var (
+	ErrMissingManifest      = errors.New("local backup has no manifest")
+	ErrUnexpectedFile       = errors.New("local backup contains an unexpected file")
+	ErrIntegrityCheckFailed = errors.New("local backup integrity check failed")
+)
+
+type Stage string
+
+const (
+	StageExport  Stage = "export"
+	StageRestore Stage = "restore"
+)
+
+// Progress is reported while an archive is written or read. Sizes are in bytes.
+type Progress struct {
+	Stage     Stage  `json:"stage"`
+	File      string `json:"file"`
+	Processed uint64 `json:"processed"`
+	Total     uint64 `json:"total"`
+}
+
+type ProgressFunc func(Progress)
+
+// File is an entry of the archive
+type File struct {
+	Name   string `json:"name"`
+	Size   uint64 `json:"size"`
+	SHA256 string `json:"sha256"`
+}
+
+// Manifest describes the content of an archive. Schema versions are the last
+// migration applied to each database and are used to migrate them on restore.
+type Manifest struct {
+	FormatVersion   int                   `json:"formatVersion"`
+	CreatedAt       int64                 `json:"createdAt"`
+	Account         multiaccounts.Account `json:"account"`
+	AppDBVersion    uint                  `json:"appDbVersion"`
+	WalletDBVersion uint                  `json:"walletDbVersion"`
+	Files           []File                `json:"files"`
+}
+
+func (m *Manifest) file(name string) *File {
+	for i := range m.Files {
+		if m.Files[i].Name == name {
+			return &m.Files[i]
+		}
+	}
+	return nil
+}
+
+func (m *Manifest) totalSize() uint64 {
+	var total uint64
+	for _, f := range m.Files {
+		total += f.Size
+	}
+	return total
+}
+
+// Export writes the files, keyed by their name in the archive, to w encrypted with privateKey.
+// The file list of the manifest is filled in by Export.
+func Export(w io.Writer, privateKey *ecdsa.PrivateKey, manifest Manifest, paths map[string]string, progress ProgressFunc) error {
+	manifest.FormatVersion = FormatVersion
+	manifest.CreatedAt = time.Now().Unix()
+	manifest.Files = nil
+
+	names := []string{AppDBFile, WalletDBFile}
+	for name := range paths {
+		if name != AppDBFile && name != WalletDBFile {
+			names = append(names, name)
+		}
+	}
+
+	for _, name := range names {
+		path, ok := paths[name]
+		if !ok {
+			continue
+		}
+		file, err := hashFile(name, path)
+		if err != nil {
+			return err
+		}
+		manifest.Files = append(manifest.Files, *file)
+	}
+
+	encrypted, err := newEncryptWriter(w, privateKey)
+	if err != nil {
+		return err
+	}
+
+	archive := tar.NewWriter(encrypted)
+
+	manifestJSON, err := json.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+
+	err = writeEntry(archive, manifestName, int64(len(manifestJSON)), bytes.NewReader(manifestJSON))
+	if err != nil {
+		return err
+	}
+
+	tracker := &progressTracker{stage: StageExport, total: manifest.totalSize(), progress: progress}
+	for _, f := range manifest.Files {
+		err = tracker.copyFile(archive, f, paths[f.Name])
+		if err != nil {
+			return err
+		}
+	}
+
+	err = archive.Close()
+	if err != nil {
+		return err
+	}
+
+	return encrypted.Close()
+}
+
+// Restore decrypts the archive read from r and extracts its files into dir,
+// verifying their size and checksum against the manifest
+func Restore(r io.Reader, privateKey *ecdsa.PrivateKey, dir string, progress ProgressFunc) (*Manifest, error) {
+	decrypted, err := newDecryptReader(r, privateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	archive := tar.NewReader(decrypted)
+
+	header, err := archive.Next()
+	if err != nil {
+		return nil, err
+	}
+	if header.Name != manifestName {
+		return nil, ErrMissingManifest
+	}
+
+	manifest := &Manifest{}
+	err = json.NewDecoder(archive).Decode(manifest)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest.FormatVersion > FormatVersion {
+		return nil, ErrUnsupportedFormat
+	}
+
+	tracker := &progressTracker{stage: StageRestore, total: manifest.totalSize(), progress: progress}
+	restored := make(map[string]bool)
+	for {
+		header, err := archive.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		expected := manifest.file(header.Name)
+		if expected == nil || restored[header.Name] || filepath.Base(header.Name) != header.Name {
+			return nil, ErrUnexpectedFile
+		}
+
+		err = tracker.extractFile(archive, *expected, filepath.Join(dir, header.Name))
+		if err != nil {
+			return nil, err
+		}
+		restored[header.Name] = true
+	}
+
+	if len(restored) != len(manifest.Files) {
+		return nil, ErrIntegrityCheckFailed
+	}
+
+	return manifest, nil
+}
+
+func hashFile(name string, path string) (*File, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	hash := sha256.New()
+	size, err := io.Copy(hash, f)
+	if err != nil {
+		return nil, err
+	}
+
+	return &File{
+		Name:   name,
+		Size:   uint64(size),
+		SHA256: hex.EncodeToString(hash.Sum(nil)),
+	}, nil
+}
+
+func writeEntry(archive *tar.Writer, name string, size int64, r io.Reader) error {
+	err := archive.WriteHeader(&tar.Header{
+		Name:     name,
+		Mode:     0600,
+		Size:     size,
+		Typeflag: tar.TypeReg,
+	})
+	if err != nil {
+		return err
+	}
+
+	_, err = io.Copy(archive, r)
+	return err
+}
+
+// progressTracker reports progress every percent of the total size
+type progressTracker struct {
+	stage     Stage
+	processed uint64
+	reported  uint64
+	total     uint64
+	progress  ProgressFunc
+	file      string
+}
+
+func (p *progressTracker) Write(b []byte) (int, error) {
+	p.processed += uint64(len(b))
+	if p.progress != nil && (p.processed == p.total || p.processed-p.reported >= p.total/100) {
+		p.reported = p.processed
+		p.progress(Progress{Stage: p.stage, File: p.file, Processed: p.processed, Total: p.total})
+	}
+	return len(b), nil
+}
+
+func (p *progressTracker) copyFile(archive *tar.Writer, f File, path string) error {
+	file, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	p.file = f.Name
+	hash := sha256.New()
+	// The file could have changed since it was hashed, the entry is limited to the hashed size
+	// and checked again while it's written
+	reader := io.TeeReader(io.LimitReader(file, int64(f.Size)), io.MultiWriter(hash, p))
+	err = writeEntry(archive, f.Name, int64(f.Size), reader)
+	if err != nil {
+		return err
+	}
+
+	if hex.EncodeToString(hash.Sum(nil)) != f.SHA256 {
+		return fmt.Errorf("%s changed while exporting: %w", f.Name, ErrIntegrityCheckFailed)
+	}
+
+	return nil
+}
+
+func (p *progressTracker) extractFile(archive io.Reader, f File, path string) error {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0600)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	p.file = f.Name
+	hash := sha256.New()
+	size, err := io.Copy(io.MultiWriter(file, hash, p), archive)
+	if err != nil {
+		return err
+	}
+
+	if uint64(size) != f.Size || hex.EncodeToString(hash.Sum(nil)) != f.SHA256 {
+		return ErrIntegrityCheckFailed
+	}
+
+	return file.Sync()
+}
@@ -0,0 +1,98 @@
+package localbackup
+
+import (
+	"bytes"
+	"crypto/rand"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/multiaccounts"
+)
+
+func writeTestFile(t *testing.T, dir string, name string, size int) (string, []byte) {
+	content := make([]byte, size)
+	_, err := rand.Read(content)
+	require.NoError(t, err)
+
+	path := filepath.Join(dir, name)
+	require.NoError(t, os.WriteFile(path, content, 0600))
+	return path, content
+}
+
+func TestExportAndRestore(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	srcDir := t.TempDir()
+	appDBPath, appDBContent := writeTestFile(t, srcDir, "app", 3*chunkSize+123)
+	walletDBPath, walletDBContent := writeTestFile(t, srcDir, "wallet", 42)
+
+	manifest := Manifest{
+		Account:         multiaccounts.Account{KeyUID: "0x1234", Name: "alice"},
+		AppDBVersion:    1732867315,
+		WalletDBVersion: 1700000000,
+	}
+
+	var progress []Progress
+	archive := &bytes.Buffer{}
+	err = Export(archive, key, manifest, map[string]string{AppDBFile: appDBPath, WalletDBFile: walletDBPath}, func(p Progress) {
+		progress = append(progress, p)
+	})
+	require.NoError(t, err)
+	require.NotEmpty(t, progress)
+	require.Equal(t, progress[len(progress)-1].Total, progress[len(progress)-1].Processed)
+
+	dstDir := t.TempDir()
+	restored, err := Restore(bytes.NewReader(archive.Bytes()), key, dstDir, nil)
+	require.NoError(t, err)
+	require.Equal(t, FormatVersion, restored.FormatVersion)
+	require.Equal(t, "0x1234", restored.Account.KeyUID)
+	require.Equal(t, uint(1732867315), restored.AppDBVersion)
+	require.Len(t, restored.Files, 2)
+
+	content, err := os.ReadFile(filepath.Join(dstDir, AppDBFile))
+	require.NoError(t, err)
+	require.Equal(t, appDBContent, content)
+
+	content, err = os.ReadFile(filepath.Join(dstDir, WalletDBFile))
+	require.NoError(t, err)
+	require.Equal(t, walletDBContent, content)
+}
+
+func TestRestoreFailures(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	srcDir := t.TempDir()
+	appDBPath, _ := writeTestFile(t, srcDir, "app", 2*chunkSize)
+
+	archive := &bytes.Buffer{}
+	err = Export(archive, key, Manifest{}, map[string]string{AppDBFile: appDBPath}, nil)
+	require.NoError(t, err)
+	data := archive.Bytes()
+
+	// Wrong key
+	otherKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	_, err = Restore(bytes.NewReader(data), otherKey, t.TempDir(), nil)
+	require.ErrorIs(t, err, ErrDecryptionFailed)
+
+	// Not an archive
+	_, err = Restore(bytes.NewReader([]byte("not an archive")), key, t.TempDir(), nil)
+	require.ErrorIs(t, err, ErrInvalidArchive)
+
+	// Tampered content
+	tampered := append([]byte{}, data...)
+	tampered[len(tampered)/2] ^= 0xff
+	_, err = Restore(bytes.NewReader(tampered), key, t.TempDir(), nil)
+	require.ErrorIs(t, err, ErrDecryptionFailed)
+
+	// Truncated after a full chunk
+	truncated := data[:headerSize+4+chunkSize+16]
+	_, err = Restore(bytes.NewReader(truncated), key, t.TempDir(), nil)
+	require.ErrorIs(t, err, ErrTruncatedArchive)
+}
@@ -0,0 +1,242 @@
+package localbackup
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/ecdsa"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"io"
+
+	"golang.org/x/crypto/hkdf"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+)
+
+const (
+	magic      = "STLB"
+	saltSize   = 32
+	prefixSize = 7
+	headerSize = len(magic) + 2 + saltSize + prefixSize
+	// chunkSize is the size of the plaintext chunks, each one is sealed separately
+	chunkSize = 1 << 20
+	keyInfo   = "status-local-backup"
+)
+
+var (
+	ErrInvalidArchive    = errors.New("not a local backup archive")
+	ErrUnsupportedFormat = errors.New("local backup format version is not supported")
+	ErrDecryptionFailed  = errors.New("failed to decrypt local backup, wrong key or corrupted archive")
+	ErrTruncatedArchive  = errors.New("local backup archive is truncated")
+)
+
+// deriveKey derives the archive key from the user's chat key
+func deriveKey(privateKey *ecdsa.PrivateKey, salt []byte) ([]byte, error) {
+	key := make([]byte, 32)
+	reader := hkdf.New(sha256.New, crypto.FromECDSA(privateKey), salt, []byte(keyInfo))
+	if _, err := io.ReadFull(reader, key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+// chunkNonce builds the nonce of a chunk from the archive prefix, the chunk index
+// and whether it's the last chunk, so that chunks can't be reordered or dropped
+func chunkNonce(prefix []byte, index uint32, last bool) []byte {
+	nonce := make([]byte, 12)
+	copy(nonce, prefix)
+	binary.BigEndian.PutUint32(nonce[prefixSize:], index)
+	if last {
+		nonce[11] = 1
+	}
+	return nonce
+}
+
+// encryptWriter seals the stream in chunks of chunkSize
+type encryptWriter struct {
+	w      io.Writer
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	buffer []byte
+}
+
+func newEncryptWriter(w io.Writer, privateKey *ecdsa.PrivateKey) (*encryptWriter, error) {
+	header := make([]byte, headerSize)
+	copy(header, magic)
+	binary.BigEndian.PutUint16(header[len(magic):], FormatVersion)
+
+	salt := header[len(magic)+2 : len(magic)+2+saltSize]
+	prefix := header[len(magic)+2+saltSize:]
+	if _, err := rand.Read(header[len(magic)+2:]); err != nil {
+		return nil, err
+	}
+
+	key, err := deriveKey(privateKey, salt)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := w.Write(header); err != nil {
+		return nil, err
+	}
+
+	return &encryptWriter{
+		w:      w,
+		aead:   aead,
+		header: header,
+		prefix: prefix,
+		buffer: make([]byte, 0, chunkSize),
+	}, nil
+}
+
+func (e *encryptWriter) Write(p []byte) (int, error) {
+	written := 0
+	for len(p) > 0 {
+		n := copy(e.buffer[len(e.buffer):chunkSize], p)
+		e.buffer = e.buffer[:len(e.buffer)+n]
+		p = p[n:]
+		written += n
+
+		// Keep a full buffer around so the last chunk can be flagged on Close
+		if len(e.buffer) == chunkSize && len(p) > 0 {
+			if err := e.flush(false); err != nil {
+				return written, err
+			}
+		}
+	}
+	return written, nil
+}
+
+func (e *encryptWriter) flush(last bool) error {
+	sealed := e.aead.Seal(nil, chunkNonce(e.prefix, e.index, last), e.buffer, e.header)
+
+	length := make([]byte, 4)
+	binary.BigEndian.PutUint32(length, uint32(len(sealed)))
+	if _, err := e.w.Write(length); err != nil {
+		return err
+	}
+	if _, err := e.w.Write(sealed); err != nil {
+		return err
+	}
+
+	e.index++
+	e.buffer = e.buffer[:0]
+	return nil
+}
+
+// Close seals the remaining data as the last chunk
+func (e *encryptWriter) Close() error {
+	return e.flush(true)
+}
+
+// decryptReader opens the chunks sealed by encryptWriter
+type decryptReader struct {
+	r      io.Reader
+	aead   cipher.AEAD
+	header []byte
+	prefix []byte
+	index  uint32
+	buffer []byte
+	done   bool
+}
+
+func newDecryptReader(r io.Reader, privateKey *ecdsa.PrivateKey) (*decryptReader, error) {
+	header := make([]byte, headerSize)
+	if _, err := io.ReadFull(r, header); err != nil {
+		return nil, ErrInvalidArchive
+	}
+
+	if string(header[:len(magic)]) != magic {
+		return nil, ErrInvalidArchive
+	}
+
+	if binary.BigEndian.Uint16(header[len(magic):]) > FormatVersion {
+		return nil, ErrUnsupportedFormat
+	}
+
+	key, err := deriveKey(privateKey, header[len(magic)+2:len(magic)+2+saltSize])
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := newAEAD(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return &decryptReader{
+		r:      r,
+		aead:   aead,
+		header: header,
+		prefix: header[len(magic)+2+saltSize:],
+	}, nil
+}
+
+func (d *decryptReader) Read(p []byte) (int, error) {
+	for len(d.buffer) == 0 {
+		if d.done {
+			return 0, io.EOF
+		}
+		if err := d.next(); err != nil {
+			return 0, err
+		}
+	}
+
+	n := copy(p, d.buffer)
+	d.buffer = d.buffer[n:]
+	return n, nil
+}
+
+func (d *decryptReader) next() error {
+	length := make([]byte, 4)
+	if _, err := io.ReadFull(d.r, length); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrTruncatedArchive
+		}
+		return err
+	}
+
+	size := binary.BigEndian.Uint32(length)
+	if size > chunkSize+uint32(d.aead.Overhead()) {
+		return ErrDecryptionFailed
+	}
+
+	sealed := make([]byte, size)
+	if _, err := io.ReadFull(d.r, sealed); err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return ErrTruncatedArchive
+		}
+		return err
+	}
+
+	// The last chunk is sealed with a different nonce, try it if the regular one fails
+	plain, err := d.aead.Open(nil, chunkNonce(d.prefix, d.index, false), sealed, d.header)
+	if err != nil {
+		plain, err = d.aead.Open(nil, chunkNonce(d.prefix, d.index, true), sealed, d.header)
+		if err != nil {
+			return ErrDecryptionFailed
+		}
+		d.done = true
+	}
+
+	d.index++
+	d.buffer = plain
+	return nil
+}
@@ -25,7 +25,7 @@ func OverrideRootLoggerWithConfig(settings LogSettings) error {
 }
 
 func overrideCoreWithConfig(filteringCore *namespaceFilteringCore, settings LogSettings) error {
-	core := filteringCore.Parent().(*Core)
+	core := filteringCore.Parent().(*redactingCore).Parent().(*Core)
 	if !settings.Enabled {
 		core.UpdateSyncer(zapcore.AddSync(io.Discard))
 		return nil
@@ -0,0 +1,188 @@
+package logutils
+
+import (
+	"crypto/ecdsa"
+	"fmt"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+// maxBinaryLength is the size above which binary fields are masked by the full policy.
+// Identifiers and hashes are kept, payloads and uncompressed public keys are masked.
+const maxBinaryLength = 64
+
+var rootRedactionPolicies = newRedactionPolicies()
+
+// redactingCore masks secrets and personal data of the entries before passing them to its parent,
+// according to the redaction policy of the namespace of the entry. The fields added with With are kept
+// until the entries are written, as the policy to apply depends on the namespace of each entry.
+type redactingCore struct {
+	parent   zapcore.Core
+	policies *redactionPolicies
+	fields   []zapcore.Field
+}
+
+var (
+	_ zapcore.Core = (*redactingCore)(nil)
+)
+
+func newRedactingCore(core zapcore.Core, policies *redactionPolicies) *redactingCore {
+	return &redactingCore{
+		parent:   core,
+		policies: policies,
+	}
+}
+
+// NewRedactingCore wraps core so that its entries are redacted with the policies of the root logger
+func NewRedactingCore(core zapcore.Core) zapcore.Core {
+	return newRedactingCore(core, rootRedactionPolicies)
+}
+
+// SetRedactionPolicies sets the redaction policies of the root logger and of the cores made with NewRedactingCore.
+// See redactionPolicies.Rebuild for the format.
+func SetRedactionPolicies(policies string) error {
+	return rootRedactionPolicies.Rebuild(policies)
+}
+
+func (core *redactingCore) Enabled(lvl zapcore.Level) bool {
+	return core.parent.Enabled(lvl)
+}
+
+func (core *redactingCore) With(fields []zapcore.Field) zapcore.Core {
+	clone := *core
+	clone.fields = make([]zapcore.Field, 0, len(core.fields)+len(fields))
+	clone.fields = append(clone.fields, core.fields...)
+	clone.fields = append(clone.fields, fields...)
+	return &clone
+}
+
+func (core *redactingCore) Check(ent zapcore.Entry, ce *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if core.Enabled(ent.Level) {
+		return ce.AddCore(ent, core)
+	}
+	return ce
+}
+
+func (core *redactingCore) Write(ent zapcore.Entry, fields []zapcore.Field) error {
+	if len(core.fields) > 0 {
+		fields = append(core.fields[:len(core.fields):len(core.fields)], fields...)
+	}
+
+	policy := core.policies.PolicyFor(ent.LoggerName)
+	if policy == redaction.None {
+		return core.parent.Write(ent, fields)
+	}
+
+	ent.Message = redaction.String(ent.Message, policy)
+	redacted := make([]zapcore.Field, len(fields))
+	for i, field := range fields {
+		redacted[i] = redactField(field, policy)
+	}
+	return core.parent.Write(ent, redacted)
+}
+
+func (core *redactingCore) Sync() error {
+	return core.parent.Sync()
+}
+
+func (core *redactingCore) Parent() zapcore.Core {
+	return core.parent
+}
+
+func redactField(field zapcore.Field, policy redaction.Policy) zapcore.Field {
+	switch field.Type {
+	case zapcore.NamespaceType, zapcore.SkipType:
+		return field
+	}
+
+	if redaction.IsSensitiveKey(field.Key, policy) {
+		return zap.String(field.Key, redaction.Placeholder)
+	}
+
+	switch field.Type {
+	case zapcore.StringType:
+		field.String = redaction.String(field.String, policy)
+		return field
+	case zapcore.ByteStringType:
+		return zap.ByteString(field.Key, redaction.Text(field.Interface.([]byte), policy))
+	case zapcore.BinaryType:
+		if policy == redaction.Full && len(field.Interface.([]byte)) > maxBinaryLength {
+			return zap.String(field.Key, redaction.Placeholder)
+		}
+		return field
+	case zapcore.ErrorType:
+		err, ok := field.Interface.(error)
+		if !ok || err == nil {
+			return field
+		}
+		message := err.Error()
+		if redacted := redaction.String(message, policy); redacted != message {
+			return zap.String(field.Key, redacted)
+		}
+		return field
+	case zapcore.ObjectMarshalerType:
+		field.Interface = redactingObject{marshaler: field.Interface.(zapcore.ObjectMarshaler), policy: policy}
+		return field
+	case zapcore.ArrayMarshalerType:
+		field.Interface = redactingArray{marshaler: field.Interface.(zapcore.ArrayMarshaler), policy: policy}
+		return field
+	case zapcore.StringerType, zapcore.ReflectType:
+		return redactValue(field, policy)
+	}
+	return field
+}
+
+// redactValue masks the values logged with zap.Any, zap.Stringer and zap.Reflect, by type
+func redactValue(field zapcore.Field, policy redaction.Policy) zapcore.Field {
+	switch v := field.Interface.(type) {
+	case nil:
+		return field
+	case *ecdsa.PrivateKey, ecdsa.PrivateKey:
+		return zap.String(field.Key, redaction.Placeholder)
+	case *ecdsa.PublicKey, ecdsa.PublicKey:
+		if policy == redaction.Full {
+			return zap.String(field.Key, redaction.Placeholder)
+		}
+		return field
+	case types.HexBytes:
+		if policy == redaction.Full && len(v) > maxBinaryLength {
+			return zap.String(field.Key, redaction.Placeholder)
+		}
+		return field
+	case map[string]interface{}, []interface{}:
+		// decoded JSON, e.g. requests and responses of the request log
+		return zap.Reflect(field.Key, redaction.Value(v, policy))
+	}
+
+	if field.Type == zapcore.ReflectType {
+		return zap.Reflect(field.Key, redactReflected(field.Interface, policy))
+	}
+
+	stringer, ok := field.Interface.(fmt.Stringer)
+	if !ok {
+		return field
+	}
+	s, ok := stringOf(stringer)
+	if !ok {
+		return field
+	}
+	if redacted := redaction.String(s, policy); redacted != s {
+		return zap.String(field.Key, redacted)
+	}
+	return field
+}
+
+// stringOf calls String, which may panic for nil receivers. The field is then left to the encoder,
+// which reports the panic in the log entry.
+func stringOf(stringer fmt.Stringer) (s string, ok bool) {
+	defer func() {
+		if recover() != nil {
+			ok = false
+		}
+	}()
+	return stringer.String(), true
+}
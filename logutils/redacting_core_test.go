@@ -0,0 +1,146 @@
+package logutils
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+const testMnemonic = "abandon ability able about above absent absorb abstract absurd abuse access accident"
+
+func newTestRedactingLogger(t *testing.T, policies string) (*zap.Logger, *bytes.Buffer) {
+	buffer := bytes.NewBuffer(nil)
+	core := zapcore.NewCore(
+		zapcore.NewJSONEncoder(zap.NewDevelopmentEncoderConfig()),
+		zapcore.AddSync(buffer),
+		zap.DebugLevel,
+	)
+
+	redactionPolicies := newRedactionPolicies()
+	require.NoError(t, redactionPolicies.Rebuild(policies))
+
+	return zap.New(newRedactingCore(core, redactionPolicies)), buffer
+}
+
+func TestRedactingCoreByKey(t *testing.T) {
+	logger, buffer := newTestRedactingLogger(t, "full")
+
+	logger.Info("login", zap.String("password", "hunter2"), zap.String("keyUID", "0x01"), zap.String("text", "hello there"))
+
+	require.Contains(t, buffer.String(), `"password":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), `"text":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), `"keyUID":"0x01"`)
+	require.NotContains(t, buffer.String(), "hunter2")
+	require.NotContains(t, buffer.String(), "hello there")
+}
+
+func TestRedactingCoreByType(t *testing.T) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	logger, buffer := newTestRedactingLogger(t, "full")
+
+	logger.Info("keys",
+		zap.Any("identity", key),
+		zap.Any("sender", &key.PublicKey),
+		zap.Any("messageID", types.HexBytes(bytes.Repeat([]byte{1}, 32))),
+		zap.Any("payload", types.HexBytes(bytes.Repeat([]byte{2}, 128))),
+		zap.Binary("signature", bytes.Repeat([]byte{3}, 65)),
+	)
+
+	require.Contains(t, buffer.String(), `"identity":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), `"sender":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), `"payload":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), `"signature":"[REDACTED]"`)
+	require.Contains(t, buffer.String(), types.EncodeHex(bytes.Repeat([]byte{1}, 32)))
+}
+
+func TestRedactingCoreByPattern(t *testing.T) {
+	logger, buffer := newTestRedactingLogger(t, "secrets")
+
+	privateKey := strings.Repeat("1f", 32)
+	logger.Info("imported "+testMnemonic,
+		zap.String("input", "key "+privateKey),
+		zap.Error(errors.New("invalid mnemonic "+testMnemonic)),
+		zap.Any("request", map[string]interface{}{"password": "hunter2", "name": "bob"}),
+	)
+
+	require.NotContains(t, buffer.String(), testMnemonic)
+	require.NotContains(t, buffer.String(), privateKey)
+	require.NotContains(t, buffer.String(), "hunter2")
+	require.Contains(t, buffer.String(), "imported [REDACTED:mnemonic]")
+	require.Contains(t, buffer.String(), `"name":"bob"`)
+}
+
+type testCredentials struct {
+	KeyUID   string `json:"keyUID"`
+	Password string `json:"password"`
+}
+
+func (c testCredentials) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	enc.AddString("keyUID", c.KeyUID)
+	enc.AddString("password", c.Password)
+	return enc.AddArray("mnemonics", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+		enc.AppendString(testMnemonic)
+		return nil
+	}))
+}
+
+func TestRedactingCoreMarshalers(t *testing.T) {
+	logger, buffer := newTestRedactingLogger(t, "secrets")
+
+	credentials := testCredentials{KeyUID: "0x01", Password: "hunter2"}
+	logger.Info("login",
+		zap.Object("object", credentials),
+		zap.Array("array", zapcore.ArrayMarshalerFunc(func(enc zapcore.ArrayEncoder) error {
+			return enc.AppendObject(credentials)
+		})),
+		zap.Reflect("struct", struct{ Request testCredentials }{Request: credentials}),
+	)
+
+	require.NotContains(t, buffer.String(), "hunter2")
+	require.NotContains(t, buffer.String(), testMnemonic)
+	require.Contains(t, buffer.String(), `"object":{"keyUID":"0x01","password":"[REDACTED]","mnemonics":["[REDACTED:mnemonic]"]}`)
+	require.Contains(t, buffer.String(), `"struct":{"Request":{"keyUID":"0x01","password":"[REDACTED]"}}`)
+}
+
+func TestRedactingCoreWith(t *testing.T) {
+	logger, buffer := newTestRedactingLogger(t, "none,protocol:secrets")
+
+	child := logger.With(zap.String("password", "hunter2"))
+	child.Info("first")
+	require.Contains(t, buffer.String(), "hunter2")
+
+	buffer.Reset()
+	child.Named("protocol").Named("messenger").Info("second")
+	require.Contains(t, buffer.String(), `"password":"[REDACTED]"`)
+	require.NotContains(t, buffer.String(), "hunter2")
+}
+
+func TestRedactionPolicies(t *testing.T) {
+	policies := newRedactionPolicies()
+	require.Equal(t, defaultRedactionPolicy(), policies.PolicyFor("protocol"))
+
+	require.NoError(t, policies.Rebuild("secrets,protocol:full,protocol.messenger.wallet:none"))
+	require.Equal(t, redaction.Secrets, policies.PolicyFor(""))
+	require.Equal(t, redaction.Secrets, policies.PolicyFor("wallet"))
+	require.Equal(t, redaction.Full, policies.PolicyFor("protocol"))
+	require.Equal(t, redaction.Full, policies.PolicyFor("protocol.messenger"))
+	require.Equal(t, redaction.None, policies.PolicyFor("protocol.messenger.wallet.transfer"))
+	require.Equal(t, redaction.Secrets, policies.PolicyFor("protocolx"))
+
+	require.NoError(t, policies.Rebuild(""))
+	require.Equal(t, defaultRedactionPolicy(), policies.PolicyFor("protocol"))
+
+	require.Error(t, policies.Rebuild("protocol:partial"))
+	require.Error(t, policies.Rebuild("protocol"))
+}
@@ -0,0 +1,134 @@
+package logutils
+
+import (
+	"bytes"
+	"encoding/json"
+
+	"go.uber.org/zap/zapcore"
+
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+// redactingObject encodes an ObjectMarshaler through a redactingObjectEncoder
+type redactingObject struct {
+	marshaler zapcore.ObjectMarshaler
+	policy    redaction.Policy
+}
+
+func (o redactingObject) MarshalLogObject(enc zapcore.ObjectEncoder) error {
+	return o.marshaler.MarshalLogObject(&redactingObjectEncoder{ObjectEncoder: enc, policy: o.policy})
+}
+
+// redactingArray encodes an ArrayMarshaler through a redactingArrayEncoder
+type redactingArray struct {
+	marshaler zapcore.ArrayMarshaler
+	policy    redaction.Policy
+}
+
+func (a redactingArray) MarshalLogArray(enc zapcore.ArrayEncoder) error {
+	return a.marshaler.MarshalLogArray(&redactingArrayEncoder{ArrayEncoder: enc, policy: a.policy})
+}
+
+// redactingObjectEncoder masks the values of sensitive keys and the strings added to its encoder,
+// nested objects and arrays are encoded through redacting encoders too
+type redactingObjectEncoder struct {
+	zapcore.ObjectEncoder
+	policy redaction.Policy
+}
+
+func (enc *redactingObjectEncoder) sensitive(key string) bool {
+	if redaction.IsSensitiveKey(key, enc.policy) {
+		enc.ObjectEncoder.AddString(key, redaction.Placeholder)
+		return true
+	}
+	return false
+}
+
+func (enc *redactingObjectEncoder) AddString(key, value string) {
+	if !enc.sensitive(key) {
+		enc.ObjectEncoder.AddString(key, redaction.String(value, enc.policy))
+	}
+}
+
+func (enc *redactingObjectEncoder) AddByteString(key string, value []byte) {
+	if !enc.sensitive(key) {
+		enc.ObjectEncoder.AddByteString(key, redaction.Text(value, enc.policy))
+	}
+}
+
+func (enc *redactingObjectEncoder) AddBinary(key string, value []byte) {
+	if enc.sensitive(key) {
+		return
+	}
+	if enc.policy == redaction.Full && len(value) > maxBinaryLength {
+		enc.ObjectEncoder.AddString(key, redaction.Placeholder)
+		return
+	}
+	enc.ObjectEncoder.AddBinary(key, value)
+}
+
+func (enc *redactingObjectEncoder) AddObject(key string, marshaler zapcore.ObjectMarshaler) error {
+	if enc.sensitive(key) {
+		return nil
+	}
+	return enc.ObjectEncoder.AddObject(key, redactingObject{marshaler: marshaler, policy: enc.policy})
+}
+
+func (enc *redactingObjectEncoder) AddArray(key string, marshaler zapcore.ArrayMarshaler) error {
+	if enc.sensitive(key) {
+		return nil
+	}
+	return enc.ObjectEncoder.AddArray(key, redactingArray{marshaler: marshaler, policy: enc.policy})
+}
+
+func (enc *redactingObjectEncoder) AddReflected(key string, value interface{}) error {
+	if enc.sensitive(key) {
+		return nil
+	}
+	return enc.ObjectEncoder.AddReflected(key, redactReflected(value, enc.policy))
+}
+
+// redactingArrayEncoder masks the strings appended to its encoder
+type redactingArrayEncoder struct {
+	zapcore.ArrayEncoder
+	policy redaction.Policy
+}
+
+func (enc *redactingArrayEncoder) AppendString(value string) {
+	enc.ArrayEncoder.AppendString(redaction.String(value, enc.policy))
+}
+
+func (enc *redactingArrayEncoder) AppendByteString(value []byte) {
+	enc.ArrayEncoder.AppendByteString(redaction.Text(value, enc.policy))
+}
+
+func (enc *redactingArrayEncoder) AppendObject(marshaler zapcore.ObjectMarshaler) error {
+	return enc.ArrayEncoder.AppendObject(redactingObject{marshaler: marshaler, policy: enc.policy})
+}
+
+func (enc *redactingArrayEncoder) AppendArray(marshaler zapcore.ArrayMarshaler) error {
+	return enc.ArrayEncoder.AppendArray(redactingArray{marshaler: marshaler, policy: enc.policy})
+}
+
+func (enc *redactingArrayEncoder) AppendReflected(value interface{}) error {
+	return enc.ArrayEncoder.AppendReflected(redactReflected(value, enc.policy))
+}
+
+// redactReflected masks a value encoded by reflection, e.g. a request struct logged with zap.Any.
+// The value goes through its JSON encoding, which is what the encoders write, so that its
+// fields are masked by their JSON key. Values that can't be encoded are left to the encoder.
+func redactReflected(value interface{}, policy redaction.Policy) interface{} {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return value
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+
+	var decoded interface{}
+	if err := decoder.Decode(&decoded); err != nil {
+		return value
+	}
+	return redaction.Value(decoded, policy)
+}
@@ -0,0 +1,9 @@
+//go:build disable_log_redaction
+
+package logutils
+
+import "github.com/status-im/status-go/logutils/redaction"
+
+func defaultRedactionPolicy() redaction.Policy {
+	return redaction.None
+}
@@ -0,0 +1,17 @@
+//go:build !disable_log_redaction
+
+package logutils
+
+import (
+	"github.com/status-im/status-go/internal/version"
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+// defaultRedactionPolicy masks secrets and personal data in release builds, and only secrets in
+// development builds, whose logs are read by developers. Builds tagged with disable_log_redaction log everything.
+func defaultRedactionPolicy() redaction.Policy {
+	if version.IsRelease() {
+		return redaction.Full
+	}
+	return redaction.Secrets
+}
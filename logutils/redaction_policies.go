@@ -0,0 +1,92 @@
+package logutils
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+
+	"github.com/status-im/status-go/logutils/redaction"
+)
+
+var redactionPoliciesRegex = regexp.MustCompile(`^([a-zA-Z0-9_\.]+:)?(none|secrets|full)(,([a-zA-Z0-9_\.]+:)?(none|secrets|full))*$`)
+var errInvalidRedactionPoliciesFormat = fmt.Errorf("invalid redaction policies format")
+
+func ValidateRedactionPolicies(policies string) error {
+	if policies != "" && !redactionPoliciesRegex.MatchString(policies) {
+		return errInvalidRedactionPoliciesFormat
+	}
+	return nil
+}
+
+// redactionPolicies holds the redaction policy of each namespace
+type redactionPolicies struct {
+	defaultPolicy redaction.Policy
+	namespaces    map[string]redaction.Policy
+	mu            sync.RWMutex
+}
+
+func newRedactionPolicies() *redactionPolicies {
+	return &redactionPolicies{
+		defaultPolicy: defaultRedactionPolicy(),
+		namespaces:    make(map[string]redaction.Policy),
+	}
+}
+
+// PolicyFor returns the policy of the closest namespace with an explicit policy, or the default one
+func (p *redactionPolicies) PolicyFor(namespace string) redaction.Policy {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	if len(p.namespaces) == 0 {
+		return p.defaultPolicy
+	}
+
+	for namespace != "" {
+		if policy, ok := p.namespaces[namespace]; ok {
+			return policy
+		}
+		i := strings.LastIndex(namespace, ".")
+		if i == -1 {
+			break
+		}
+		namespace = namespace[:i]
+	}
+	return p.defaultPolicy
+}
+
+// Rebuild replaces the policies with a comma-separated list of policies, where policy is one of:
+// "none", "secrets", "full". Policies prefixed with a namespace apply to the namespace and its children,
+// the one without a namespace, if any, replaces the default policy.
+// An empty string restores the default policy of the build.
+//
+// Example: "secrets,protocol:full,wallet.transfer:none"
+func (p *redactionPolicies) Rebuild(policies string) error {
+	if err := ValidateRedactionPolicies(policies); err != nil {
+		return err
+	}
+
+	defaultPolicy := defaultRedactionPolicy()
+	namespaces := make(map[string]redaction.Policy)
+	if policies != "" {
+		for _, entry := range strings.Split(policies, ",") {
+			parts := strings.Split(entry, ":")
+			policy, err := redaction.ParsePolicy(parts[len(parts)-1])
+			if err != nil {
+				return err
+			}
+			if len(parts) == 1 {
+				defaultPolicy = policy
+				continue
+			}
+			namespaces[parts[0]] = policy
+		}
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.defaultPolicy = defaultPolicy
+	p.namespaces = namespaces
+
+	return nil
+}
@@ -35,7 +35,7 @@ func CreateRequestLogger(file string) (*zap.Logger, error) {
 		zap.DebugLevel,
 	)
 
-	return zap.New(core).Named("RequestLogger"), nil
+	return zap.New(logutils.NewRedactingCore(core)).Named("RequestLogger"), nil
 }
 
 func ConfigureAndEnableRequestLogging(file string) error {
@@ -15,11 +15,17 @@ const (
 	dbCleanerPeriod    = time.Hour
 )
 
+// pruner is implemented by the DBs the cleaner removes old messages from
+type pruner interface {
+	// Prune removes messages older than time
+	Prune(time.Time, int) (int, error)
+}
+
 // dbCleaner removes old messages from a db.
 type dbCleaner struct {
 	sync.RWMutex
 
-	db        DB
+	db        pruner
 	batchSize int
 	retention time.Duration
 
@@ -28,7 +34,7 @@ type dbCleaner struct {
 }
 
 // newDBCleaner returns a new cleaner for db.
-func newDBCleaner(db DB, retention time.Duration) *dbCleaner {
+func newDBCleaner(db pruner, retention time.Duration) *dbCleaner {
 	return &dbCleaner{
 		db:        db,
 		retention: retention,
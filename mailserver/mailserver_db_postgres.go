@@ -9,6 +9,9 @@ import (
 	"github.com/lib/pq"
 	"go.uber.org/zap"
 
+	"github.com/waku-org/go-waku/waku/v2/protocol"
+	storepb "github.com/waku-org/go-waku/waku/v2/protocol/store/pb"
+
 	// Import postgres driver
 	_ "github.com/lib/pq"
 	"github.com/status-im/migrate/v4"
@@ -26,11 +29,14 @@ import (
 )
 
 type PostgresDB struct {
-	db   *sql.DB
-	name string
-	done chan struct{}
+	db       *sql.DB
+	messages *wakuMessagesTable
+	name     string
+	done     chan struct{}
 }
 
+var _ MessageDB = (*PostgresDB)(nil)
+
 func NewPostgresDB(uri string) (*PostgresDB, error) {
 	db, err := sql.Open("postgres", uri)
 	if err != nil {
@@ -38,8 +44,9 @@ func NewPostgresDB(uri string) (*PostgresDB, error) {
 	}
 
 	instance := &PostgresDB{
-		db:   db,
-		done: make(chan struct{}),
+		db:       db,
+		messages: &wakuMessagesTable{db: db, postgres: true},
+		done:     make(chan struct{}),
 	}
 	if err := instance.setup(); err != nil {
 		return nil, err
@@ -310,3 +317,21 @@ func toBitString(bloom []byte) string {
 	}
 	return val
 }
+
+func (i *PostgresDB) SaveMessage(env *protocol.Envelope) error {
+	return i.messages.save(env)
+}
+
+func (i *PostgresDB) QueryMessages(query MessageQuery) ([]*storepb.WakuMessageKeyValue, []byte, error) {
+	return i.messages.query(query)
+}
+
+// PruneMessages removes the Waku v2 messages older than t, the envelopes are pruned by Prune
+// with the retention of the mailserver
+func (i *PostgresDB) PruneMessages(t time.Time, batch int) (int, error) {
+	return i.messages.prune(t, batch)
+}
+
+func (i *PostgresDB) PruneMessagesToCapacity(capacity int) (int, error) {
+	return i.messages.pruneToCapacity(capacity)
+}
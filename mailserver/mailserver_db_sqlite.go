@@ -0,0 +1,59 @@
+package mailserver
+
+import (
+	"time"
+
+	"github.com/waku-org/go-waku/waku/v2/protocol"
+	storepb "github.com/waku-org/go-waku/waku/v2/protocol/store/pb"
+
+	"github.com/status-im/status-go/sqlite"
+)
+
+const sqliteSchema = `
+CREATE TABLE IF NOT EXISTS waku_messages (message_hash BLOB NOT NULL PRIMARY KEY, pubsub_topic TEXT NOT NULL, content_topic TEXT NOT NULL, timestamp INTEGER NOT NULL, data BLOB NOT NULL);
+
+CREATE INDEX IF NOT EXISTS waku_messages_topics_timestamp_idx ON waku_messages (pubsub_topic, content_topic, timestamp, message_hash);
+CREATE INDEX IF NOT EXISTS waku_messages_timestamp_idx ON waku_messages (timestamp, message_hash);
+`
+
+// SQLiteDB stores the Waku v2 messages served by WakuStore in a SQLite database.
+// It is meant for small storenodes, e.g. the one of a single community.
+type SQLiteDB struct {
+	table *wakuMessagesTable
+}
+
+var _ MessageDB = (*SQLiteDB)(nil)
+
+func NewSQLiteDB(path string) (*SQLiteDB, error) {
+	db, err := sqlite.OpenUnecryptedDB(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := db.Exec(sqliteSchema); err != nil {
+		db.Close()
+		return nil, err
+	}
+
+	return &SQLiteDB{table: &wakuMessagesTable{db: db}}, nil
+}
+
+func (i *SQLiteDB) Close() error {
+	return i.table.db.Close()
+}
+
+func (i *SQLiteDB) SaveMessage(env *protocol.Envelope) error {
+	return i.table.save(env)
+}
+
+func (i *SQLiteDB) QueryMessages(query MessageQuery) ([]*storepb.WakuMessageKeyValue, []byte, error) {
+	return i.table.query(query)
+}
+
+func (i *SQLiteDB) PruneMessages(t time.Time, batch int) (int, error) {
+	return i.table.prune(t, batch)
+}
+
+func (i *SQLiteDB) PruneMessagesToCapacity(capacity int) (int, error) {
+	return i.table.pruneToCapacity(capacity)
+}
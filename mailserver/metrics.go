@@ -64,6 +64,18 @@ var (
 		Name: "mailserver_envelope_queries_total",
 		Help: "Number of queries for envelopes in the DB.",
 	}, []string{"filter", "history"})
+	archivedMessagesCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "storenode_archived_messages_total",
+		Help: "Number of Waku v2 messages stored.",
+	})
+	archivedMessagesErrorsCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "storenode_archived_messages_failures_total",
+		Help: "Number of failures storing a Waku v2 message.",
+	})
+	storeQueriesCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "storenode_queries_total",
+		Help: "Number of store queries processed.",
+	}, []string{"status"})
 )
 
 func init() {
@@ -81,4 +93,7 @@ func init() {
 	prom.MustRegister(archivedEnvelopesGauge)
 	prom.MustRegister(archivedEnvelopeSizeMeter)
 	prom.MustRegister(envelopeQueriesCounter)
+	prom.MustRegister(archivedMessagesCounter)
+	prom.MustRegister(archivedMessagesErrorsCounter)
+	prom.MustRegister(storeQueriesCounter)
 }
@@ -0,0 +1,266 @@
+package mailserver
+
+import (
+	"context"
+	"errors"
+	"math"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/host"
+	"github.com/libp2p/go-libp2p/core/network"
+	"github.com/libp2p/go-msgio/pbio"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/waku-org/go-waku/waku/v2/protocol"
+	"github.com/waku-org/go-waku/waku/v2/protocol/relay"
+	"github.com/waku-org/go-waku/waku/v2/protocol/store"
+	storepb "github.com/waku-org/go-waku/waku/v2/protocol/store/pb"
+
+	"github.com/status-im/status-go/common"
+)
+
+// Status codes of the store protocol responses
+const (
+	storeStatusOK            = 200
+	storeStatusBadRequest    = 400
+	storeStatusInternalError = 500
+)
+
+// WakuStoreConfig holds the topics and retention policies of a WakuStore
+type WakuStoreConfig struct {
+	// PubsubTopics are the pubsub topics the relayed messages are stored from
+	PubsubTopics []string
+	// ContentTopics restricts the stored messages to these content topics, all are stored when empty
+	ContentTopics []string
+	// Retention is how long messages are kept, forever when 0
+	Retention time.Duration
+	// Capacity is the maximum number of messages kept, unlimited when 0
+	Capacity int
+}
+
+// WakuStore is a Waku v2 store protocol server. It stores the messages relayed on its pubsub topics
+// and answers the store queries of the clients, so that a node can be used as a storenode,
+// e.g. the one of a community.
+type WakuStore struct {
+	db      MessageDB
+	config  WakuStoreConfig
+	cleaner *dbCleaner
+	logger  *zap.Logger
+
+	h      host.Host
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWakuStore returns a store serving the messages of db. The store owns db, which is closed when the store stops.
+func NewWakuStore(db MessageDB, config WakuStoreConfig, logger *zap.Logger) (*WakuStore, error) {
+	if len(config.PubsubTopics) == 0 {
+		return nil, errors.New("at least one pubsub topic is required")
+	}
+
+	s := &WakuStore{
+		db:     db,
+		config: config,
+		logger: logger.Named("storenode"),
+	}
+
+	if config.Retention > 0 || config.Capacity > 0 {
+		s.cleaner = newDBCleaner(&storeRetention{db: db, retention: config.Retention, capacity: config.Capacity}, config.Retention)
+	}
+
+	return s, nil
+}
+
+// Start subscribes to the pubsub topics of the store with relay and starts serving store queries
+func (s *WakuStore) Start(ctx context.Context, h host.Host, r *relay.WakuRelay) error {
+	ctx, cancel := context.WithCancel(ctx)
+
+	for _, pubsubTopic := range s.config.PubsubTopics {
+		subs, err := r.Subscribe(ctx, protocol.NewContentFilter(pubsubTopic, s.config.ContentTopics...))
+		if err != nil {
+			cancel()
+			s.wg.Wait()
+			return err
+		}
+
+		for _, sub := range subs {
+			s.wg.Add(1)
+			go s.archiveLoop(ctx, sub)
+		}
+	}
+
+	s.h = h
+	s.cancel = cancel
+	h.SetStreamHandler(store.StoreQueryID_v300, s.onRequest)
+
+	if s.cleaner != nil {
+		s.cleaner.Start()
+	}
+
+	s.logger.Info("started storenode",
+		zap.Strings("pubsubTopics", s.config.PubsubTopics),
+		zap.Strings("contentTopics", s.config.ContentTopics),
+		zap.Duration("retention", s.config.Retention),
+		zap.Int("capacity", s.config.Capacity))
+
+	return nil
+}
+
+// Stop stops serving store queries and closes the DB
+func (s *WakuStore) Stop() error {
+	if s.h != nil {
+		s.h.RemoveStreamHandler(store.StoreQueryID_v300)
+	}
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	if s.cleaner != nil {
+		s.cleaner.Stop()
+	}
+
+	return s.db.Close()
+}
+
+func (s *WakuStore) archiveLoop(ctx context.Context, sub *relay.Subscription) {
+	defer common.LogOnPanic()
+	defer s.wg.Done()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case env, ok := <-sub.Ch:
+			if !ok {
+				return
+			}
+			s.Archive(env)
+		}
+	}
+}
+
+// Archive stores a relayed message. Ephemeral messages are not stored.
+func (s *WakuStore) Archive(env *protocol.Envelope) {
+	if env.Message().GetEphemeral() {
+		return
+	}
+
+	if err := s.db.SaveMessage(env); err != nil {
+		archivedMessagesErrorsCounter.Inc()
+		s.logger.Error("failed to store message", zap.Stringer("hash", env.Hash()), zap.Error(err))
+		return
+	}
+	archivedMessagesCounter.Inc()
+}
+
+func (s *WakuStore) onRequest(stream network.Stream) {
+	defer common.LogOnPanic()
+
+	logger := s.logger.With(zap.Stringer("peer", stream.Conn().RemotePeer()))
+	reader := pbio.NewDelimitedReader(stream, math.MaxInt32)
+	writer := pbio.NewDelimitedWriter(stream)
+
+	request := &storepb.StoreQueryRequest{}
+	if err := reader.ReadMsg(request); err != nil {
+		logger.Debug("failed to read store request", zap.Error(err))
+		_ = stream.Reset()
+		return
+	}
+
+	response := s.Query(request)
+	if err := writer.WriteMsg(response); err != nil {
+		logger.Debug("failed to write store response", zap.String("requestId", request.RequestId), zap.Error(err))
+		_ = stream.Reset()
+		return
+	}
+
+	_ = stream.Close()
+}
+
+// Query answers a store query request
+func (s *WakuStore) Query(request *storepb.StoreQueryRequest) *storepb.StoreQueryResponse {
+	response := &storepb.StoreQueryResponse{RequestId: request.RequestId}
+
+	if err := request.Validate(); err != nil {
+		return s.queryFailed(response, storeStatusBadRequest, err.Error())
+	}
+
+	limit := int(request.GetPaginationLimit())
+	if limit == 0 {
+		limit = store.DefaultPageSize
+	} else if limit > store.MaxPageSize {
+		limit = store.MaxPageSize
+	}
+
+	messages, cursor, err := s.db.QueryMessages(MessageQuery{
+		PubsubTopic:   request.GetPubsubTopic(),
+		ContentTopics: request.ContentTopics,
+		MessageHashes: request.MessageHashes,
+		TimeStart:     request.GetTimeStart(),
+		TimeEnd:       request.GetTimeEnd(),
+		Cursor:        request.PaginationCursor,
+		Forward:       request.PaginationForward,
+		Limit:         limit,
+	})
+	if errors.Is(err, ErrCursorNotFound) {
+		return s.queryFailed(response, storeStatusBadRequest, err.Error())
+	}
+	if err != nil {
+		s.logger.Error("failed to query messages", zap.String("requestId", request.RequestId), zap.Error(err))
+		return s.queryFailed(response, storeStatusInternalError, "internal error")
+	}
+
+	if !request.IncludeData {
+		for _, message := range messages {
+			message.Message = nil
+			message.PubsubTopic = nil
+		}
+	}
+
+	storeQueriesCounter.WithLabelValues("ok").Inc()
+	response.StatusCode = proto.Uint32(storeStatusOK)
+	response.StatusDesc = proto.String("OK")
+	response.Messages = messages
+	response.PaginationCursor = cursor
+
+	return response
+}
+
+func (s *WakuStore) queryFailed(response *storepb.StoreQueryResponse, code uint32, desc string) *storepb.StoreQueryResponse {
+	storeQueriesCounter.WithLabelValues("failed").Inc()
+	response.StatusCode = proto.Uint32(code)
+	response.StatusDesc = proto.String(desc)
+	return response
+}
+
+// storeRetention applies the retention policies of a WakuStore when run by the cleaner
+type storeRetention struct {
+	db        MessageDB
+	retention time.Duration
+	capacity  int
+}
+
+func (r *storeRetention) Prune(t time.Time, batch int) (int, error) {
+	count := 0
+
+	if r.retention > 0 {
+		n, err := r.db.PruneMessages(t, batch)
+		if err != nil {
+			return count, err
+		}
+		count += n
+	}
+
+	if r.capacity > 0 {
+		n, err := r.db.PruneMessagesToCapacity(r.capacity)
+		if err != nil {
+			return count, err
+		}
+		count += n
+	}
+
+	return count, nil
+}
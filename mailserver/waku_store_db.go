@@ -0,0 +1,308 @@
+package mailserver
+
+import (
+	"bytes"
+	"database/sql"
+	"errors"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"google.golang.org/protobuf/proto"
+
+	"github.com/waku-org/go-waku/waku/v2/protocol"
+	wpb "github.com/waku-org/go-waku/waku/v2/protocol/pb"
+	storepb "github.com/waku-org/go-waku/waku/v2/protocol/store/pb"
+)
+
+var ErrCursorNotFound = errors.New("cursor not found")
+
+// maxQueryHashes is the number of message hashes looked up per statement,
+// below the limit of 999 parameters of the older SQLite versions
+const maxQueryHashes = 500
+
+// MessageDB is an interface to abstract the storage of the Waku v2 messages
+// served by WakuStore, so that it is agnostic to the underlying technology used
+type MessageDB interface {
+	Close() error
+	// SaveMessage stores a message received on a pubsub topic
+	SaveMessage(*protocol.Envelope) error
+	// QueryMessages returns a page of messages ordered by timestamp, and the cursor of the next page if any
+	QueryMessages(MessageQuery) ([]*storepb.WakuMessageKeyValue, []byte, error)
+	// PruneMessages removes messages older than time, batch messages at a time
+	PruneMessages(t time.Time, batch int) (int, error)
+	// PruneMessagesToCapacity removes the oldest messages so that at most capacity messages are kept
+	PruneMessagesToCapacity(capacity int) (int, error)
+}
+
+// MessageQuery holds the criteria of a store query. Messages are looked up either
+// by MessageHashes or by PubsubTopic, ContentTopics and time range.
+type MessageQuery struct {
+	PubsubTopic   string
+	ContentTopics []string
+	MessageHashes [][]byte
+	// TimeStart and TimeEnd are timestamps in nanoseconds, 0 means unbounded
+	TimeStart int64
+	TimeEnd   int64
+
+	// Cursor is the hash of the message the page starts after (excluded)
+	Cursor  []byte
+	Forward bool
+	Limit   int
+}
+
+// wakuMessagesTable implements the storage of the Waku v2 messages for both
+// the Postgres and SQLite backends. Statements are written with ? placeholders.
+type wakuMessagesTable struct {
+	db       *sql.DB
+	postgres bool
+}
+
+// bind replaces the ? placeholders with positional ones for Postgres
+func (t *wakuMessagesTable) bind(statement string) string {
+	if !t.postgres {
+		return statement
+	}
+
+	var b strings.Builder
+	n := 0
+	for _, c := range statement {
+		if c == '?' {
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteRune(c)
+	}
+	return b.String()
+}
+
+func (t *wakuMessagesTable) count() (int, error) {
+	var count int
+	return count, t.db.QueryRow("SELECT count(*) FROM waku_messages").Scan(&count)
+}
+
+func (t *wakuMessagesTable) save(env *protocol.Envelope) error {
+	msg := env.Message()
+	data, err := proto.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	// messages without a timestamp are ordered by the time they have been received
+	timestamp := msg.GetTimestamp()
+	if timestamp == 0 {
+		timestamp = env.Index().ReceiverTime
+	}
+
+	statement := "INSERT INTO waku_messages (message_hash, pubsub_topic, content_topic, timestamp, data) VALUES (?, ?, ?, ?, ?) ON CONFLICT (message_hash) DO NOTHING"
+	_, err = t.db.Exec(t.bind(statement), env.Hash().Bytes(), env.PubsubTopic(), msg.ContentTopic, timestamp, data)
+	return err
+}
+
+func (t *wakuMessagesTable) query(q MessageQuery) ([]*storepb.WakuMessageKeyValue, []byte, error) {
+	page := messagesPage{forward: q.Forward, limit: q.Limit}
+
+	if len(q.Cursor) > 0 {
+		var cursorTimestamp int64
+		err := t.db.QueryRow(t.bind("SELECT timestamp FROM waku_messages WHERE message_hash = ?"), q.Cursor).Scan(&cursorTimestamp)
+		if err == sql.ErrNoRows {
+			return nil, nil, ErrCursorNotFound
+		}
+		if err != nil {
+			return nil, nil, err
+		}
+		page.cursor = q.Cursor
+		page.cursorTimestamp = cursorTimestamp
+	}
+
+	var messages []storedMessage
+	if len(q.MessageHashes) > 0 {
+		// hashes are looked up in chunks to stay below the number of parameters of a statement,
+		// the pages of the chunks are then merged
+		for start := 0; start < len(q.MessageHashes); start += maxQueryHashes {
+			hashes := q.MessageHashes[start:min(start+maxQueryHashes, len(q.MessageHashes))]
+			args := make([]interface{}, len(hashes))
+			for i, hash := range hashes {
+				args[i] = hash
+			}
+
+			chunk, err := t.queryPage([]string{"message_hash IN (" + placeholders(len(hashes)) + ")"}, args, page)
+			if err != nil {
+				return nil, nil, err
+			}
+			messages = append(messages, chunk...)
+		}
+		page.sort(messages)
+		if len(messages) > q.Limit+1 {
+			messages = messages[:q.Limit+1]
+		}
+	} else {
+		conditions := []string{"pubsub_topic = ?"}
+		args := []interface{}{q.PubsubTopic}
+		if len(q.ContentTopics) > 0 {
+			conditions = append(conditions, "content_topic IN ("+placeholders(len(q.ContentTopics))+")")
+			for _, topic := range q.ContentTopics {
+				args = append(args, topic)
+			}
+		}
+		if q.TimeStart > 0 {
+			conditions = append(conditions, "timestamp >= ?")
+			args = append(args, q.TimeStart)
+		}
+		if q.TimeEnd > 0 {
+			conditions = append(conditions, "timestamp <= ?")
+			args = append(args, q.TimeEnd)
+		}
+
+		var err error
+		messages, err = t.queryPage(conditions, args, page)
+		if err != nil {
+			return nil, nil, err
+		}
+	}
+
+	var cursor []byte
+	if len(messages) > q.Limit {
+		messages = messages[:q.Limit]
+		cursor = messages[q.Limit-1].MessageHash
+	}
+
+	// pages are always returned in chronological order
+	result := make([]*storepb.WakuMessageKeyValue, len(messages))
+	for i, message := range messages {
+		if q.Forward {
+			result[i] = message.WakuMessageKeyValue
+		} else {
+			result[len(messages)-1-i] = message.WakuMessageKeyValue
+		}
+	}
+
+	return result, cursor, nil
+}
+
+// messagesPage is the position, order and size of the page of a query
+type messagesPage struct {
+	cursor          []byte
+	cursorTimestamp int64
+	forward         bool
+	limit           int
+}
+
+// storedMessage is a message of a page along with the timestamp it is ordered by
+type storedMessage struct {
+	*storepb.WakuMessageKeyValue
+	timestamp int64
+}
+
+// sort orders messages the way they are returned by the statements of the page
+func (p messagesPage) sort(messages []storedMessage) {
+	sort.Slice(messages, func(i, j int) bool {
+		a, b := messages[i], messages[j]
+		if p.forward {
+			a, b = b, a
+		}
+		if a.timestamp != b.timestamp {
+			return a.timestamp > b.timestamp
+		}
+		return bytes.Compare(a.MessageHash, b.MessageHash) > 0
+	})
+}
+
+// queryPage returns the messages matching conditions after the cursor of the page,
+// one more message than the page size is returned to know whether there is a next page
+func (t *wakuMessagesTable) queryPage(conditions []string, args []interface{}, page messagesPage) ([]storedMessage, error) {
+	comparison, order := "<", "DESC"
+	if page.forward {
+		comparison, order = ">", "ASC"
+	}
+
+	if len(page.cursor) > 0 {
+		conditions = append(conditions, "(timestamp "+comparison+" ? OR (timestamp = ? AND message_hash "+comparison+" ?))")
+		args = append(args, page.cursorTimestamp, page.cursorTimestamp, page.cursor)
+	}
+
+	statement := "SELECT message_hash, pubsub_topic, timestamp, data FROM waku_messages WHERE " + strings.Join(conditions, " AND ") +
+		" ORDER BY timestamp " + order + ", message_hash " + order + " LIMIT ?"
+	args = append(args, page.limit+1)
+
+	rows, err := t.db.Query(t.bind(statement), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []storedMessage
+	for rows.Next() {
+		var hash, data []byte
+		var pubsubTopic string
+		var timestamp int64
+		if err := rows.Scan(&hash, &pubsubTopic, &timestamp, &data); err != nil {
+			return nil, err
+		}
+
+		msg := &wpb.WakuMessage{}
+		if err := proto.Unmarshal(data, msg); err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, storedMessage{
+			WakuMessageKeyValue: &storepb.WakuMessageKeyValue{
+				MessageHash: hash,
+				Message:     msg,
+				PubsubTopic: proto.String(pubsubTopic),
+			},
+			timestamp: timestamp,
+		})
+	}
+	return messages, rows.Err()
+}
+
+// prune removes the messages older than before, batch messages at a time so that
+// the database isn't locked for the whole pruning
+func (t *wakuMessagesTable) prune(before time.Time, batch int) (int, error) {
+	statement := t.bind("DELETE FROM waku_messages WHERE message_hash IN (SELECT message_hash FROM waku_messages WHERE timestamp < ? LIMIT ?)")
+
+	count := 0
+	for {
+		result, err := t.db.Exec(statement, before.UnixNano(), batch)
+		if err != nil {
+			return count, err
+		}
+		rows, err := result.RowsAffected()
+		if err != nil {
+			return count, err
+		}
+		count += int(rows)
+		if int(rows) < batch {
+			return count, nil
+		}
+	}
+}
+
+func (t *wakuMessagesTable) pruneToCapacity(capacity int) (int, error) {
+	// the timestamp of the newest message above capacity, messages with the same timestamp are removed too
+	var threshold int64
+	err := t.db.QueryRow(t.bind("SELECT timestamp FROM waku_messages ORDER BY timestamp DESC LIMIT 1 OFFSET ?"), capacity).Scan(&threshold)
+	if err == sql.ErrNoRows {
+		return 0, nil
+	}
+	if err != nil {
+		return 0, err
+	}
+
+	result, err := t.db.Exec(t.bind("DELETE FROM waku_messages WHERE timestamp <= ?"), threshold)
+	if err != nil {
+		return 0, err
+	}
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(rows), nil
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
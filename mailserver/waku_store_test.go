@@ -0,0 +1,222 @@
+package mailserver
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/waku-org/go-waku/waku/v2/protocol"
+	wpb "github.com/waku-org/go-waku/waku/v2/protocol/pb"
+	storepb "github.com/waku-org/go-waku/waku/v2/protocol/store/pb"
+
+	"github.com/status-im/status-go/sqlite"
+)
+
+const (
+	testPubsubTopic      = "/waku/2/rs/16/32"
+	testOtherPubsubTopic = "/waku/2/rs/16/64"
+)
+
+func setupTestWakuStore(t *testing.T, config WakuStoreConfig) (*WakuStore, *SQLiteDB) {
+	db, err := NewSQLiteDB(sqlite.InMemoryPath)
+	require.NoError(t, err)
+
+	if len(config.PubsubTopics) == 0 {
+		config.PubsubTopics = []string{testPubsubTopic}
+	}
+	s, err := NewWakuStore(db, config, zap.NewNop())
+	require.NoError(t, err)
+	t.Cleanup(func() { require.NoError(t, s.Stop()) })
+
+	return s, db
+}
+
+func archiveTestMessage(s *WakuStore, pubsubTopic string, contentTopic string, timestamp time.Time) *protocol.Envelope {
+	msg := &wpb.WakuMessage{
+		Payload:      []byte(fmt.Sprintf("%s %d", contentTopic, timestamp.UnixNano())),
+		ContentTopic: contentTopic,
+		Timestamp:    proto.Int64(timestamp.UnixNano()),
+	}
+	env := protocol.NewEnvelope(msg, timestamp.UnixNano(), pubsubTopic)
+	s.Archive(env)
+	return env
+}
+
+func payloads(messages []*storepb.WakuMessageKeyValue) []string {
+	var result []string
+	for _, m := range messages {
+		result = append(result, string(m.Message.Payload))
+	}
+	return result
+}
+
+func TestWakuStoreQuery(t *testing.T) {
+	s, _ := setupTestWakuStore(t, WakuStoreConfig{})
+
+	now := time.Now()
+	var expected []string
+	for i := 5; i > 0; i-- {
+		env := archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(-time.Duration(i)*time.Minute))
+		expected = append(expected, string(env.Message().Payload))
+	}
+	archiveTestMessage(s, testPubsubTopic, "/status/1/b/proto", now)
+	archiveTestMessage(s, testOtherPubsubTopic, "/status/1/a/proto", now)
+
+	for _, forward := range []bool{false, true} {
+		t.Run(fmt.Sprintf("forward=%v", forward), func(t *testing.T) {
+			request := &storepb.StoreQueryRequest{
+				RequestId:         "1",
+				IncludeData:       true,
+				PubsubTopic:       proto.String(testPubsubTopic),
+				ContentTopics:     []string{"/status/1/a/proto"},
+				PaginationForward: forward,
+				PaginationLimit:   proto.Uint64(2),
+			}
+
+			var pages [][]string
+			for {
+				response := s.Query(request)
+				require.Equal(t, uint32(storeStatusOK), response.GetStatusCode())
+				require.NoError(t, response.Validate(request.RequestId))
+				pages = append(pages, payloads(response.Messages))
+				if response.PaginationCursor == nil {
+					break
+				}
+				request.PaginationCursor = response.PaginationCursor
+			}
+
+			if forward {
+				require.Equal(t, [][]string{expected[0:2], expected[2:4], expected[4:5]}, pages)
+			} else {
+				require.Equal(t, [][]string{expected[3:5], expected[1:3], expected[0:1]}, pages)
+			}
+		})
+	}
+}
+
+func TestWakuStoreQueryFilters(t *testing.T) {
+	s, _ := setupTestWakuStore(t, WakuStoreConfig{})
+
+	now := time.Now()
+	old := archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(-time.Hour))
+	recent := archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now)
+
+	response := s.Query(&storepb.StoreQueryRequest{
+		RequestId:   "1",
+		IncludeData: true,
+		PubsubTopic: proto.String(testPubsubTopic),
+		TimeStart:   proto.Int64(now.Add(-time.Minute).UnixNano()),
+	})
+	require.Equal(t, []string{string(recent.Message().Payload)}, payloads(response.Messages))
+
+	response = s.Query(&storepb.StoreQueryRequest{
+		RequestId:     "2",
+		MessageHashes: [][]byte{old.Hash().Bytes()},
+	})
+	require.Equal(t, uint32(storeStatusOK), response.GetStatusCode())
+	require.Len(t, response.Messages, 1)
+	require.Equal(t, old.Hash().Bytes(), response.Messages[0].MessageHash)
+	require.Nil(t, response.Messages[0].Message)
+
+	response = s.Query(&storepb.StoreQueryRequest{RequestId: "3"})
+	require.Equal(t, uint32(storeStatusBadRequest), response.GetStatusCode())
+
+	response = s.Query(&storepb.StoreQueryRequest{
+		RequestId:        "4",
+		PubsubTopic:      proto.String(testPubsubTopic),
+		PaginationCursor: make([]byte, 32),
+	})
+	require.Equal(t, uint32(storeStatusBadRequest), response.GetStatusCode())
+}
+
+func TestWakuStoreEphemeralMessages(t *testing.T) {
+	s, db := setupTestWakuStore(t, WakuStoreConfig{})
+
+	msg := &wpb.WakuMessage{ContentTopic: "/status/1/a/proto", Ephemeral: proto.Bool(true), Timestamp: proto.Int64(time.Now().UnixNano())}
+	s.Archive(protocol.NewEnvelope(msg, time.Now().UnixNano(), testPubsubTopic))
+
+	count, err := db.table.count()
+	require.NoError(t, err)
+	require.Equal(t, 0, count)
+}
+
+func TestWakuStoreRetention(t *testing.T) {
+	s, db := setupTestWakuStore(t, WakuStoreConfig{Retention: time.Hour, Capacity: 2})
+	require.NotNil(t, s.cleaner)
+
+	now := time.Now()
+	archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(-2*time.Hour))
+	for i := 3; i > 0; i-- {
+		archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(-time.Duration(i)*time.Minute))
+	}
+
+	count, err := s.cleaner.PruneEntriesOlderThan(now.Add(-s.config.Retention))
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+
+	count, err = db.table.count()
+	require.NoError(t, err)
+	require.Equal(t, 2, count)
+}
+
+func TestWakuStoreQueryManyHashes(t *testing.T) {
+	s, _ := setupTestWakuStore(t, WakuStoreConfig{})
+
+	now := time.Now()
+	var hashes [][]byte
+	var expected []string
+	for i := 0; i < 3*maxQueryHashes; i++ {
+		// every chunk of hashes matches a few stored messages
+		if i%maxQueryHashes < 2 {
+			env := archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(time.Duration(i)*time.Millisecond))
+			hashes = append(hashes, env.Hash().Bytes())
+			expected = append(expected, string(env.Message().Payload))
+			continue
+		}
+		hash := make([]byte, 32)
+		hash[0], hash[1] = byte(i>>8), byte(i)
+		hashes = append(hashes, hash)
+	}
+
+	request := &storepb.StoreQueryRequest{
+		RequestId:         "1",
+		IncludeData:       true,
+		MessageHashes:     hashes,
+		PaginationForward: true,
+		PaginationLimit:   proto.Uint64(4),
+	}
+
+	var pages [][]string
+	for {
+		response := s.Query(request)
+		require.Equal(t, uint32(storeStatusOK), response.GetStatusCode())
+		pages = append(pages, payloads(response.Messages))
+		if response.PaginationCursor == nil {
+			break
+		}
+		request.PaginationCursor = response.PaginationCursor
+	}
+	require.Equal(t, [][]string{expected[0:4], expected[4:6]}, pages)
+}
+
+func TestWakuStorePruneBatches(t *testing.T) {
+	s, db := setupTestWakuStore(t, WakuStoreConfig{})
+
+	now := time.Now()
+	for i := 5; i > 0; i-- {
+		archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now.Add(-time.Duration(i)*time.Hour))
+	}
+	archiveTestMessage(s, testPubsubTopic, "/status/1/a/proto", now)
+
+	count, err := db.PruneMessages(now.Add(-time.Minute), 2)
+	require.NoError(t, err)
+	require.Equal(t, 5, count)
+
+	count, err = db.table.count()
+	require.NoError(t, err)
+	require.Equal(t, 1, count)
+}
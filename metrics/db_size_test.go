@@ -0,0 +1,33 @@
+package metrics
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDBSizeCollector(t *testing.T) {
+	dir := t.TempDir()
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "accounts.db"), make([]byte, 10), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0x1234-wallet.db"), make([]byte, 20), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "0x1234-wallet.db-wal"), make([]byte, 5), 0600))
+	require.NoError(t, os.WriteFile(filepath.Join(dir, "notes.txt"), make([]byte, 100), 0600))
+
+	registry := prom.NewRegistry()
+	require.NoError(t, registry.Register(NewDBSizeCollector(dir)))
+
+	families, err := registry.Gather()
+	require.NoError(t, err)
+	require.Len(t, families, 1)
+	require.Equal(t, "status_db_size_bytes", families[0].GetName())
+
+	sizes := map[string]float64{}
+	for _, m := range families[0].GetMetric() {
+		require.Len(t, m.GetLabel(), 1)
+		sizes[m.GetLabel()[0].GetValue()] = m.GetGauge().GetValue()
+	}
+	require.Equal(t, map[string]float64{"accounts": 10, "wallet": 25}, sizes)
+}
@@ -1,6 +1,7 @@
 package metrics
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 	"time"
@@ -23,12 +24,17 @@ type Server struct {
 }
 
 func NewMetricsServer(port int, r metrics.Registry) *Server {
+	return NewMetricsServerWithAddress(fmt.Sprintf(":%d", port), r)
+}
+
+// NewMetricsServerWithAddress returns a Server listening on the given host:port.
+func NewMetricsServerWithAddress(address string, r metrics.Registry) *Server {
 	mux := http.NewServeMux()
 	mux.Handle("/health", healthHandler())
 	mux.Handle("/metrics", Handler(r))
 	p := Server{
 		server: &http.Server{
-			Addr:              fmt.Sprintf(":%d", port),
+			Addr:              address,
 			ReadHeaderTimeout: 5 * time.Second,
 			Handler:           mux,
 		},
@@ -62,3 +68,10 @@ func (p *Server) Listen() {
 	defer common.LogOnPanic()
 	logutils.ZapLogger().Info("metrics server stopped", zap.Error(p.server.ListenAndServe()))
 }
+
+// Stop shuts the HTTP server down, waiting for the in-flight scrapes.
+func (p *Server) Stop() error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return p.server.Shutdown(ctx)
+}
@@ -158,6 +158,11 @@ func initializeLogging(request *requests.InitializeApplication) error {
 		return err
 	}
 
+	err = logutils.SetRedactionPolicies(request.LogRedaction)
+	if err != nil {
+		return err
+	}
+
 	logutils.ZapLogger().Info("logging initialised",
 		zap.Any("logSettings", logSettings),
 		zap.Bool("APILoggingEnabled", request.APILoggingEnabled),
@@ -1454,6 +1459,36 @@ func exportUnencryptedDatabaseV2(requestJSON string) string {
 	return makeJSONResponse(err)
 }
 
+// ExportLocalBackup writes the databases of the logged in account into an encrypted archive
+func ExportLocalBackup(requestJSON string) string {
+	return callWithResponse(exportLocalBackup, requestJSON)
+}
+
+func exportLocalBackup(requestJSON string) string {
+	var request requests.ExportLocalBackup
+	err := json.Unmarshal([]byte(requestJSON), &request)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	err = statusBackend.ExportLocalBackup(&request)
+	return makeJSONResponse(err)
+}
+
+// ExportDiagnosticsBundle writes the logs and the state of the node into a redacted zip archive for bug reports
+func ExportDiagnosticsBundle(requestJSON string) string {
+	return callWithResponse(exportDiagnosticsBundle, requestJSON)
+}
+
+func exportDiagnosticsBundle(requestJSON string) string {
+	var request requests.ExportDiagnostics
+	err := json.Unmarshal([]byte(requestJSON), &request)
+	if err != nil {
+		return makeJSONResponse(err)
+	}
+	err = statusBackend.ExportDiagnostics(&request)
+	return makeJSONResponse(err)
+}
+
 // Deprecated: Use ImportUnencryptedDatabaseV2 instead.
 func ImportUnencryptedDatabase(accountData, password, databasePath string) string {
 	return importUnencryptedDatabase(accountData, password, databasePath)
@@ -148,7 +148,7 @@ func (db *Database) GetAccountKDFIterationsNumber(keyUID string) (kdfIterationsN
 }
 
 func (db *Database) GetAccounts() (rst []Account, err error) {
-	rows, err := db.db.Query("SELECT  a.name, a.loginTimestamp, a.identicon, a.colorHash, a.colorId, a.customizationColor, a.customizationColorClock, a.keycardPairing, a.keyUid, a.kdfIterations, a.hasAcceptedTerms, ii.name, ii.image_payload, ii.width, ii.height, ii.file_size, ii.resize_target, ii.clock FROM accounts AS a LEFT JOIN identity_images AS ii ON ii.key_uid = a.keyUid ORDER BY loginTimestamp DESC")
+	rows, err := db.db.Query("SELECT  a.name, a.loginTimestamp, a.identicon, a.colorHash, a.colorId, a.customizationColor, a.customizationColorClock, a.keycardPairing, a.keyUid, a.kdfIterations, a.hasAcceptedTerms, ii.name, ii.image_payload, ii.width, ii.height, ii.file_size, ii.resize_target, ii.clock, ii.animated_payload FROM accounts AS a LEFT JOIN identity_images AS ii ON ii.key_uid = a.keyUid ORDER BY loginTimestamp DESC")
 	if err != nil {
 		return nil, err
 	}
@@ -190,6 +190,7 @@ func (db *Database) GetAccounts() (rst []Account, err error) {
 			&iiFileSize,
 			&iiResizeTarget,
 			&iiClock,
+			&ii.AnimatedPayload,
 		)
 		if err != nil {
 			return nil, err
@@ -247,7 +248,7 @@ func (db *Database) GetAccountsCount() (int, error) {
 }
 
 func (db *Database) GetAccount(keyUID string) (*Account, error) {
-	rows, err := db.db.Query("SELECT  a.name, a.loginTimestamp, a.identicon, a.colorHash, a.colorId, a.customizationColor, a.customizationColorClock, a.keycardPairing, a.keyUid, a.kdfIterations, a.hasAcceptedTerms, ii.key_uid, ii.name, ii.image_payload, ii.width, ii.height, ii.file_size, ii.resize_target, ii.clock FROM accounts AS a LEFT JOIN identity_images AS ii ON ii.key_uid = a.keyUid WHERE a.keyUid = ? ORDER BY loginTimestamp DESC", keyUID)
+	rows, err := db.db.Query("SELECT  a.name, a.loginTimestamp, a.identicon, a.colorHash, a.colorId, a.customizationColor, a.customizationColorClock, a.keycardPairing, a.keyUid, a.kdfIterations, a.hasAcceptedTerms, ii.key_uid, ii.name, ii.image_payload, ii.width, ii.height, ii.file_size, ii.resize_target, ii.clock, ii.animated_payload FROM accounts AS a LEFT JOIN identity_images AS ii ON ii.key_uid = a.keyUid WHERE a.keyUid = ? ORDER BY loginTimestamp DESC", keyUID)
 	if err != nil {
 		return nil, err
 	}
@@ -292,6 +293,7 @@ func (db *Database) GetAccount(keyUID string) (*Account, error) {
 			&iiFileSize,
 			&iiResizeTarget,
 			&iiClock,
+			&ii.AnimatedPayload,
 		)
 		if err != nil {
 			return nil, err
@@ -395,7 +397,7 @@ func (db *Database) DeleteAccount(keyUID string) error {
 
 // Account images
 func (db *Database) GetIdentityImages(keyUID string) (iis []*images.IdentityImage, err error) {
-	rows, err := db.db.Query(`SELECT key_uid, name, image_payload, width, height, file_size, resize_target, clock FROM identity_images WHERE key_uid = ?`, keyUID)
+	rows, err := db.db.Query(`SELECT key_uid, name, image_payload, width, height, file_size, resize_target, clock, animated_payload FROM identity_images WHERE key_uid = ?`, keyUID)
 	if err != nil {
 		return nil, err
 	}
@@ -406,7 +408,7 @@ func (db *Database) GetIdentityImages(keyUID string) (iis []*images.IdentityImag
 
 	for rows.Next() {
 		ii := &images.IdentityImage{}
-		err = rows.Scan(&ii.KeyUID, &ii.Name, &ii.Payload, &ii.Width, &ii.Height, &ii.FileSize, &ii.ResizeTarget, &ii.Clock)
+		err = rows.Scan(&ii.KeyUID, &ii.Name, &ii.Payload, &ii.Width, &ii.Height, &ii.FileSize, &ii.ResizeTarget, &ii.Clock, &ii.AnimatedPayload)
 		if err != nil {
 			return nil, err
 		}
@@ -419,7 +421,7 @@ func (db *Database) GetIdentityImages(keyUID string) (iis []*images.IdentityImag
 
 func (db *Database) GetIdentityImage(keyUID, it string) (*images.IdentityImage, error) {
 	var ii images.IdentityImage
-	err := db.db.QueryRow("SELECT key_uid, name, image_payload, width, height, file_size, resize_target, clock FROM identity_images WHERE key_uid = ? AND name = ?", keyUID, it).Scan(&ii.KeyUID, &ii.Name, &ii.Payload, &ii.Width, &ii.Height, &ii.FileSize, &ii.ResizeTarget, &ii.Clock)
+	err := db.db.QueryRow("SELECT key_uid, name, image_payload, width, height, file_size, resize_target, clock, animated_payload FROM identity_images WHERE key_uid = ? AND name = ?", keyUID, it).Scan(&ii.KeyUID, &ii.Name, &ii.Payload, &ii.Width, &ii.Height, &ii.FileSize, &ii.ResizeTarget, &ii.Clock, &ii.AnimatedPayload)
 	if err == sql.ErrNoRows {
 		return nil, nil
 	} else if err != nil {
@@ -450,7 +452,7 @@ func (db *Database) StoreIdentityImages(keyUID string, iis []images.IdentityImag
 		}
 		iis[i].KeyUID = keyUID
 		_, err := tx.Exec(
-			"INSERT INTO identity_images (key_uid, name, image_payload, width, height, file_size, resize_target, clock) VALUES (?, ?, ?, ?, ?, ?, ?, ?)",
+			"INSERT INTO identity_images (key_uid, name, image_payload, width, height, file_size, resize_target, clock, animated_payload) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)",
 			keyUID,
 			ii.Name,
 			ii.Payload,
@@ -459,6 +461,7 @@ func (db *Database) StoreIdentityImages(keyUID string, iis []images.IdentityImag
 			ii.FileSize,
 			ii.ResizeTarget,
 			ii.Clock,
+			ii.AnimatedPayload,
 		)
 		if err != nil {
 			return err
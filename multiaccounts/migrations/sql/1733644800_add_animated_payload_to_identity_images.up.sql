@@ -0,0 +1 @@
+ALTER TABLE identity_images ADD COLUMN animated_payload BLOB;
@@ -11,11 +11,13 @@ import (
 	"reflect"
 	"sync"
 
+	prom "github.com/prometheus/client_golang/prometheus"
 	"github.com/syndtr/goleveldb/leveldb"
 	"go.uber.org/zap"
 
 	"github.com/ethereum/go-ethereum/accounts"
 	"github.com/ethereum/go-ethereum/event"
+	gethmetrics "github.com/ethereum/go-ethereum/metrics"
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/p2p"
 	"github.com/ethereum/go-ethereum/p2p/enode"
@@ -27,6 +29,7 @@ import (
 	"github.com/status-im/status-go/db"
 	"github.com/status-im/status-go/discovery"
 	"github.com/status-im/status-go/ipfs"
+	"github.com/status-im/status-go/metrics"
 	"github.com/status-im/status-go/multiaccounts"
 	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/peers"
@@ -58,6 +61,7 @@ import (
 	"github.com/status-im/status-go/services/wallet"
 	"github.com/status-im/status-go/services/web3provider"
 	"github.com/status-im/status-go/timesource"
+	"github.com/status-im/status-go/tracing"
 	"github.com/status-im/status-go/transactions"
 	"github.com/status-im/status-go/waku"
 	"github.com/status-im/status-go/wakuv2"
@@ -92,6 +96,9 @@ type StatusNode struct {
 	mediaServerEnableTLS *bool
 	httpServer           *server.MediaServer
 
+	metricsServer   *metrics.Server
+	dbSizeCollector *metrics.DBSizeCollector
+
 	discovery discovery.Discovery
 	register  *peers.Register
 	peerPool  *peers.PeerPool
@@ -272,9 +279,58 @@ func (n *StatusNode) StartWithOptions(config *params.NodeConfig, options StartOp
 		return err
 	}
 
+	if config.MetricsConfig.Enabled {
+		n.startMetricsServer(config)
+	}
+
+	if config.TracingConfig.Enabled {
+		n.startTracing(config)
+	}
+
 	return nil
 }
 
+// startTracing records the spans of messenger, RPC and wallet flows, a failure only disables tracing
+func (n *StatusNode) startTracing(config *params.NodeConfig) {
+	err := tracing.Init(tracing.Config{
+		SampleRatio:  config.TracingConfig.SampleRatio,
+		OTLPEndpoint: config.TracingConfig.OTLPEndpoint,
+		FilePath:     config.TracingConfig.FilePath,
+	}, n.logger.Named("tracing"))
+	if err != nil {
+		n.logger.Warn("failed to start tracing", zap.Error(err))
+		return
+	}
+	n.logger.Info("tracing started", zap.Float64("sampleRatio", config.TracingConfig.SampleRatio))
+}
+
+// startMetricsServer exposes the Prometheus collectors of all services on the configured address
+func (n *StatusNode) startMetricsServer(config *params.NodeConfig) {
+	n.dbSizeCollector = metrics.NewDBSizeCollector(config.RootDataDir)
+	if err := prom.Register(n.dbSizeCollector); err != nil {
+		n.logger.Warn("failed to register db size collector", zap.Error(err))
+		n.dbSizeCollector = nil
+	}
+
+	n.metricsServer = metrics.NewMetricsServerWithAddress(config.MetricsConfig.Address, gethmetrics.DefaultRegistry)
+	go n.metricsServer.Listen()
+	n.logger.Info("metrics server started", zap.String("address", config.MetricsConfig.Address))
+}
+
+func (n *StatusNode) stopMetricsServer() {
+	if n.dbSizeCollector != nil {
+		prom.Unregister(n.dbSizeCollector)
+		n.dbSizeCollector = nil
+	}
+
+	if n.metricsServer != nil {
+		if err := n.metricsServer.Stop(); err != nil {
+			n.logger.Error("failed to stop metrics server", zap.Error(err))
+		}
+		n.metricsServer = nil
+	}
+}
+
 func (n *StatusNode) SetMediaServerEnableTLS(enableTLS *bool) {
 	n.mediaServerEnableTLS = enableTLS
 }
@@ -471,6 +527,9 @@ func (n *StatusNode) stop() error {
 		n.discovery = nil
 	}
 
+	n.stopMetricsServer()
+	tracing.Shutdown()
+
 	if err := n.gethNode.Close(); err != nil {
 		return err
 	}
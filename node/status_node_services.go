@@ -7,7 +7,9 @@ import (
 	"errors"
 	"fmt"
 	"os"
+	"path/filepath"
 	"reflect"
+	"strings"
 	"time"
 
 	"go.uber.org/zap"
@@ -388,6 +390,13 @@ func (b *StatusNode) wakuV2Service(nodeConfig *params.NodeConfig) (*wakuv2.Waku,
 		if err != nil {
 			return nil, err
 		}
+
+		// enable store protocol server
+		if nodeConfig.StorenodeConfig.Enabled {
+			if err := registerWakuV2StoreServer(w, nodeConfig); err != nil {
+				return nil, fmt.Errorf("failed to register WakuStore: %v", err)
+			}
+		}
 		b.wakuV2Srvc = w
 	}
 
@@ -635,6 +644,52 @@ func registerWakuMailServer(wakuService *waku.Waku, config *params.WakuConfig) (
 	return mailServer.Init(wakuService, config)
 }
 
+func registerWakuV2StoreServer(wakuService *wakuv2.Waku, nodeConfig *params.NodeConfig) error {
+	config := nodeConfig.StorenodeConfig
+
+	var db mailserver.MessageDB
+	var err error
+	if strings.HasPrefix(config.DatabaseURI, "postgres://") || strings.HasPrefix(config.DatabaseURI, "postgresql://") {
+		db, err = mailserver.NewPostgresDB(config.DatabaseURI)
+	} else {
+		path := config.DatabaseURI
+		if path == "" {
+			dataDir := nodeConfig.WakuV2Config.DataDir
+			if dataDir == "" {
+				dataDir = nodeConfig.DataDir
+			}
+			path = filepath.Join(dataDir, "storenode.sqlite")
+		}
+		db, err = mailserver.NewSQLiteDB(path)
+	}
+	if err != nil {
+		return err
+	}
+
+	pubsubTopics := config.PubsubTopics
+	if len(pubsubTopics) == 0 {
+		pubsubTopics = []string{shard.DefaultShardPubsubTopic()}
+	}
+
+	storeServer, err := mailserver.NewWakuStore(db, mailserver.WakuStoreConfig{
+		PubsubTopics:  pubsubTopics,
+		ContentTopics: config.ContentTopics,
+		Retention:     time.Duration(config.RetentionSeconds) * time.Second,
+		Capacity:      config.Capacity,
+	}, logutils.ZapLogger())
+	if err != nil {
+		db.Close()
+		return err
+	}
+
+	if err := wakuService.SetStoreServer(storeServer); err != nil {
+		db.Close()
+		return err
+	}
+
+	return nil
+}
+
 func appendIf(condition bool, services []common.StatusService, service common.StatusService) []common.StatusService {
 	if !condition {
 		return services
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"fmt"
 	"io/ioutil"
+	"net"
 	"os"
 	"path/filepath"
 	"strings"
@@ -512,6 +513,18 @@ type NodeConfig struct {
 
 	// ProcessBackedupMessages should be set to true when user follows recovery (using seed phrase or keycard) onboarding flow
 	ProcessBackedupMessages bool
+
+	// MetricsConfig is the configuration of the Prometheus /metrics endpoint.
+	// note: this field won't be saved into db, it's local to the process.
+	MetricsConfig MetricsConfig `json:"MetricsConfig" validate:"structonly"`
+
+	// TracingConfig is the configuration of the OpenTelemetry tracing.
+	// note: this field won't be saved into db, it's local to the process.
+	TracingConfig TracingConfig `json:"TracingConfig" validate:"structonly"`
+
+	// StorenodeConfig is the configuration of the Waku v2 store protocol server.
+	// note: this field won't be saved into db, it's local to the process.
+	StorenodeConfig StorenodeConfig `json:"StorenodeConfig" validate:"structonly"`
 }
 
 type TokenOverride struct {
@@ -608,6 +621,53 @@ type ConnectorConfig struct {
 	Enabled bool
 }
 
+// MetricsConfig provides configuration for the Prometheus/OpenMetrics exporter.
+type MetricsConfig struct {
+	// Enabled exposes the /metrics and /health endpoints when the node starts.
+	Enabled bool
+
+	// Address is the host:port the exporter listens on, e.g. 127.0.0.1:9305.
+	Address string
+}
+
+// TracingConfig provides configuration for the OpenTelemetry tracing of messenger, RPC and wallet flows.
+type TracingConfig struct {
+	// Enabled records spans when the node starts.
+	Enabled bool
+
+	// SampleRatio is the ratio of traces that are recorded, between 0 and 1.
+	SampleRatio float64
+
+	// OTLPEndpoint is the base URL of an OTLP/HTTP collector, e.g. http://localhost:4318 for a local Jaeger.
+	OTLPEndpoint string
+
+	// FilePath is a file spans are appended to, one OTLP JSON document per line.
+	FilePath string
+}
+
+// StorenodeConfig provides configuration for serving the history of the relayed messages
+// with the Waku v2 store protocol, e.g. as the storenode of a community.
+type StorenodeConfig struct {
+	// Enabled makes the node a storenode. It requires WakuV2 to run as a full node.
+	Enabled bool
+
+	// DatabaseURI is either a postgres:// URI or the path of a SQLite database.
+	// Defaults to storenode.sqlite in WakuV2Config.DataDir.
+	DatabaseURI string
+
+	// RetentionSeconds is the number of seconds messages are kept, forever when 0.
+	RetentionSeconds int
+
+	// Capacity is the maximum number of messages kept, unlimited when 0.
+	Capacity int
+
+	// PubsubTopics are the pubsub topics messages are stored from. Defaults to the default shard.
+	PubsubTopics []string
+
+	// ContentTopics restricts the stored messages to these content topics, all are stored when empty.
+	ContentTopics []string
+}
+
 // BridgeConfig provides configuration for Whisper-Waku bridge.
 type BridgeConfig struct {
 	Enabled bool
@@ -704,6 +764,25 @@ type ShhextConfig struct {
 
 	// BandwidthStatsEnabled indicates if a signal is going to be emitted to indicate the upload and download rate
 	BandwidthStatsEnabled bool
+
+	// LinkPreviewProxyURL is the SOCKS5 or HTTP proxy link previews are fetched through, e.g. socks5://127.0.0.1:9050
+	LinkPreviewProxyURL string
+
+	// LinkPreviewRelayURL is the relay link previews are fetched through, it's used instead of the proxy when set
+	LinkPreviewRelayURL string
+
+	// LinkPreviewAllowedDomains, when not empty, are the only domains link previews are fetched from
+	LinkPreviewAllowedDomains []string
+
+	// LinkPreviewDeniedDomains are the domains link previews are never fetched from
+	LinkPreviewDeniedDomains []string
+
+	// LinkPreviewMastodonInstances are the Mastodon instances whose posts are unfurled, besides the largest ones
+	LinkPreviewMastodonInstances []string
+
+	// AudioTranscoderFFmpegPath is the ffmpeg binary the AMR audios received are transcoded to AAC with,
+	// looked up in PATH when it has no separator. Audios aren't transcoded when empty.
+	AudioTranscoderFFmpegPath string
 }
 
 // TorrentConfig provides configuration for the BitTorrent client used for message history archives.
@@ -857,6 +936,10 @@ func (c *NodeConfig) UpdateWithDefaults() error {
 		c.WakuConfig.MinimumPoW = WakuMinimumPoW
 	}
 
+	if c.MetricsConfig.Enabled && c.MetricsConfig.Address == "" {
+		c.MetricsConfig.Address = DefaultMetricsAddress
+	}
+
 	// Ensure TorrentConfig is valid
 	if c.TorrentConfig.Enabled {
 		if c.TorrentConfig.DataDir == "" {
@@ -1089,6 +1172,62 @@ func (c *NodeConfig) validateChildStructs(validate *validator.Validate) error {
 	if err := c.TorrentConfig.Validate(validate); err != nil {
 		return err
 	}
+	if err := c.MetricsConfig.Validate(); err != nil {
+		return err
+	}
+	if err := c.TracingConfig.Validate(); err != nil {
+		return err
+	}
+	if err := c.StorenodeConfig.Validate(); err != nil {
+		return err
+	}
+	return nil
+}
+
+// Validate validates the MetricsConfig struct and returns an error if inconsistent values are found
+func (c *MetricsConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if _, _, err := net.SplitHostPort(c.Address); err != nil {
+		return fmt.Errorf("MetricsConfig.Address is invalid (%s): %v", c.Address, err)
+	}
+
+	return nil
+}
+
+// Validate validates the TracingConfig struct and returns an error if inconsistent values are found
+func (c *TracingConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.SampleRatio < 0 || c.SampleRatio > 1 {
+		return fmt.Errorf("TracingConfig.SampleRatio must be between 0 and 1, got %v", c.SampleRatio)
+	}
+
+	if c.OTLPEndpoint == "" && c.FilePath == "" {
+		return fmt.Errorf("TracingConfig.OTLPEndpoint or TracingConfig.FilePath is required")
+	}
+
+	return nil
+}
+
+// Validate validates the StorenodeConfig struct and returns an error if inconsistent values are found
+func (c *StorenodeConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.RetentionSeconds < 0 {
+		return fmt.Errorf("StorenodeConfig.RetentionSeconds must be positive, got %d", c.RetentionSeconds)
+	}
+
+	if c.Capacity < 0 {
+		return fmt.Errorf("StorenodeConfig.Capacity must be positive, got %d", c.Capacity)
+	}
+
 	return nil
 }
 
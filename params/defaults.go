@@ -39,6 +39,9 @@ const (
 	// PersonalRecoverMethodName defines the name for `personal.recover` API.
 	PersonalRecoverMethodName = "personal_ecRecover"
 
+	// DefaultMetricsAddress is the address of the Prometheus /metrics endpoint when none is configured
+	DefaultMetricsAddress = "127.0.0.1:9305"
+
 	// DefaultGas default amount of gas used for transactions
 	DefaultGas = 180000
 
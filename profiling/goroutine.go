@@ -0,0 +1,12 @@
+package profiling
+
+import (
+	"io"
+	"runtime/pprof"
+)
+
+// WriteGoroutines writes the stack traces of all current goroutines to w,
+// in the same format as an unrecovered panic.
+func WriteGoroutines(w io.Writer) error {
+	return pprof.Lookup("goroutine").WriteTo(w, 2)
+}
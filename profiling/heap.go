@@ -1,6 +1,7 @@
 package profiling
 
 import (
+	"io"
 	"os"
 	"path/filepath"
 	"runtime"
@@ -27,8 +28,12 @@ func WriteHeapFile(dataDir string) error {
 		}()
 	}
 
-	runtime.GC()
-	err = pprof.WriteHeapProfile(memFile)
+	return WriteHeap(memFile, 0)
+}
 
-	return err
+// WriteHeap writes the heap profile to w after a garbage collection.
+// With debug set to 0 the profile is in the protobuf format, with 1 it's human readable text.
+func WriteHeap(w io.Writer, debug int) error {
+	runtime.GC()
+	return pprof.Lookup("heap").WriteTo(w, debug)
 }
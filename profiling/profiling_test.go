@@ -1,6 +1,7 @@
 package profiling
 
 import (
+	"bytes"
 	"os"
 	"path/filepath"
 	"testing"
@@ -56,3 +57,11 @@ func TestProfilingMem(t *testing.T) {
 	require.NoError(t, err)
 	require.True(t, info.Size() > 0, "a file with memory profile is empty")
 }
+
+func TestProfilingGoroutines(t *testing.T) {
+	var buf bytes.Buffer
+
+	err := WriteGoroutines(&buf)
+	require.NoError(t, err)
+	require.Contains(t, buf.String(), "TestProfilingGoroutines")
+}
@@ -42,6 +42,8 @@ const (
 	ActivityCenterNotificationTypeCommunityUnbanned
 	ActivityCenterNotificationTypeNewInstallationReceived
 	ActivityCenterNotificationTypeNewInstallationCreated
+	ActivityCenterNotificationTypeCommunityMessageReport
+	ActivityCenterNotificationTypeHiddenMedia
 )
 
 type ActivityCenterMembershipStatus int
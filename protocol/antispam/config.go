@@ -0,0 +1,48 @@
+package antispam
+
+// Config of the local anti-spam pipeline. Intervals are in seconds.
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// RateLimitMessages is the number of messages a sender can post in RateLimitWindow
+	RateLimitMessages int    `json:"rateLimitMessages"`
+	RateLimitWindow   uint64 `json:"rateLimitWindow"`
+
+	// DuplicateThreshold is the number of near-duplicate messages seen in DuplicateWindow,
+	// from any sender of the same chat, after which the content is considered spam.
+	// Short texts, such as common replies, are never considered duplicates.
+	DuplicateThreshold int    `json:"duplicateThreshold"`
+	DuplicateWindow    uint64 `json:"duplicateWindow"`
+	// DuplicateSimilarity is the minimum jaccard similarity for two messages to be near-duplicates
+	DuplicateSimilarity float64 `json:"duplicateSimilarity"`
+
+	// MaxLinks is the number of links a message can contain
+	MaxLinks int `json:"maxLinks"`
+	// MaxLinkRatio is the maximum ratio of links to words for messages with more than one link
+	MaxLinkRatio float64 `json:"maxLinkRatio"`
+
+	// ProbationPeriod applies stricter limits to senders first seen less than this long ago
+	ProbationPeriod            uint64 `json:"probationPeriod"`
+	ProbationRateLimitMessages int    `json:"probationRateLimitMessages"`
+	ProbationMaxLinks          int    `json:"probationMaxLinks"`
+
+	// AutoReport makes control nodes report quarantined messages to the community moderators
+	AutoReport bool `json:"autoReport"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled:                    true,
+		RateLimitMessages:          10,
+		RateLimitWindow:            30,
+		DuplicateThreshold:         3,
+		DuplicateWindow:            10 * 60,
+		DuplicateSimilarity:        0.8,
+		MaxLinks:                   5,
+		MaxLinkRatio:               0.5,
+		ProbationPeriod:            24 * 60 * 60,
+		ProbationRateLimitMessages: 3,
+		ProbationMaxLinks:          1,
+		AutoReport:                 false,
+	}
+}
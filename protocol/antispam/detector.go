@@ -0,0 +1,235 @@
+package antispam
+
+import (
+	"regexp"
+	"strings"
+	"sync"
+)
+
+type Reason string
+
+const (
+	ReasonRateLimit Reason = "rate-limit"
+	ReasonDuplicate Reason = "duplicate"
+	ReasonLinks     Reason = "links"
+	ReasonProbation Reason = "probation"
+)
+
+var linkRegexp = regexp.MustCompile(`(?i)\b(?:https?://|www\.)\S+`)
+
+// maxTrackedSenders bounds the rate limit state, senders with no message in the window
+// are evicted once it's reached
+const maxTrackedSenders = 10000
+
+// maxTrackedChats bounds the duplicate state the same way, by chat
+const maxTrackedChats = 10000
+
+// maxRecentMessages bounds the messages of a chat each message is compared to, the oldest being dropped
+const maxRecentMessages = 200
+
+// minDuplicateShingles is the number of shingles under which texts are too short to be told
+// apart from common replies, and are never considered duplicates
+const minDuplicateShingles = 8
+
+// Message is the part of a received message the detector needs. Timestamps are in milliseconds.
+type Message struct {
+	ID       string
+	SenderID string
+	ChatID   string
+	Text     string
+	// Timestamp the message was sent at
+	Timestamp uint64
+	// FirstSeen is when the sender was first seen in the community, 0 if unknown
+	FirstSeen uint64
+}
+
+type Verdict struct {
+	Spam    bool     `json:"spam"`
+	Reasons []Reason `json:"reasons"`
+}
+
+type recentMessage struct {
+	timestamp   uint64
+	fingerprint fingerprint
+}
+
+// Detector flags messages based on per-sender rate limits, near-duplicate content,
+// link density and new-sender probation. State is kept in memory only.
+type Detector struct {
+	mutex   sync.Mutex
+	config  Config
+	senders map[string][]uint64
+	// recent holds the fingerprints of the messages in the duplicate window, by chat
+	recent map[string][]recentMessage
+}
+
+func NewDetector(config Config) *Detector {
+	return &Detector{
+		config:  config,
+		senders: make(map[string][]uint64),
+		recent:  make(map[string][]recentMessage),
+	}
+}
+
+func (d *Detector) Config() Config {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.config
+}
+
+func (d *Detector) SetConfig(config Config) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.config = config
+}
+
+func (d *Detector) Check(message Message) Verdict {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	var verdict Verdict
+	if !d.config.Enabled {
+		return verdict
+	}
+
+	onProbation := d.config.ProbationPeriod > 0 && message.FirstSeen > 0 &&
+		message.Timestamp < message.FirstSeen+d.config.ProbationPeriod*1000
+
+	rateLimit := d.config.RateLimitMessages
+	maxLinks := d.config.MaxLinks
+	if onProbation {
+		rateLimit = d.config.ProbationRateLimitMessages
+		maxLinks = d.config.ProbationMaxLinks
+	}
+
+	if d.exceedsRateLimit(message, rateLimit) {
+		verdict.flag(ReasonRateLimit, onProbation)
+	}
+
+	if d.isRepeatedContent(message) {
+		verdict.flag(ReasonDuplicate, onProbation)
+	}
+
+	if d.isLinkHeavy(message.Text, maxLinks) {
+		verdict.flag(ReasonLinks, onProbation)
+	}
+
+	return verdict
+}
+
+func (v *Verdict) flag(reason Reason, onProbation bool) {
+	if !v.Spam && onProbation {
+		v.Reasons = append(v.Reasons, ReasonProbation)
+	}
+	v.Spam = true
+	v.Reasons = append(v.Reasons, reason)
+}
+
+func (d *Detector) exceedsRateLimit(message Message, limit int) bool {
+	if limit <= 0 || d.config.RateLimitWindow == 0 {
+		return false
+	}
+
+	windowStart := windowStart(message.Timestamp, d.config.RateLimitWindow)
+	timestamps := d.senders[message.SenderID][:0]
+	for _, t := range d.senders[message.SenderID] {
+		if t >= windowStart {
+			timestamps = append(timestamps, t)
+		}
+	}
+	timestamps = append(timestamps, message.Timestamp)
+	d.senders[message.SenderID] = timestamps
+
+	if len(d.senders) > maxTrackedSenders {
+		d.evictIdleSenders(windowStart)
+	}
+
+	return len(timestamps) > limit
+}
+
+// evictIdleSenders drops the senders with no message since windowStart.
+// If all of them are active, the whole state is dropped rather than growing further.
+func (d *Detector) evictIdleSenders(windowStart uint64) {
+	for sender, timestamps := range d.senders {
+		if len(timestamps) == 0 || timestamps[len(timestamps)-1] < windowStart {
+			delete(d.senders, sender)
+		}
+	}
+
+	if len(d.senders) > maxTrackedSenders {
+		d.senders = make(map[string][]uint64)
+	}
+}
+
+func (d *Detector) isRepeatedContent(message Message) bool {
+	if d.config.DuplicateThreshold <= 0 || d.config.DuplicateWindow == 0 {
+		return false
+	}
+
+	current := newFingerprint(message.Text)
+	if len(current) < minDuplicateShingles {
+		return false
+	}
+
+	windowStart := windowStart(message.Timestamp, d.config.DuplicateWindow)
+	recent := d.recent[message.ChatID][:0]
+	duplicates := 0
+	for _, r := range d.recent[message.ChatID] {
+		if r.timestamp < windowStart {
+			continue
+		}
+		recent = append(recent, r)
+		if r.fingerprint.similarity(current) >= d.config.DuplicateSimilarity {
+			duplicates++
+		}
+	}
+	recent = append(recent, recentMessage{
+		timestamp:   message.Timestamp,
+		fingerprint: current,
+	})
+	if len(recent) > maxRecentMessages {
+		recent = append(recent[:0], recent[len(recent)-maxRecentMessages:]...)
+	}
+	d.recent[message.ChatID] = recent
+
+	if len(d.recent) > maxTrackedChats {
+		d.evictIdleChats(windowStart)
+	}
+
+	return duplicates >= d.config.DuplicateThreshold
+}
+
+// evictIdleChats drops the chats with no message since windowStart.
+// If all of them are active, the whole state is dropped rather than growing further.
+func (d *Detector) evictIdleChats(windowStart uint64) {
+	for chatID, recent := range d.recent {
+		if len(recent) == 0 || recent[len(recent)-1].timestamp < windowStart {
+			delete(d.recent, chatID)
+		}
+	}
+
+	if len(d.recent) > maxTrackedChats {
+		d.recent = make(map[string][]recentMessage)
+	}
+}
+
+func (d *Detector) isLinkHeavy(text string, maxLinks int) bool {
+	links := len(linkRegexp.FindAllStringIndex(text, -1))
+	if links == 0 {
+		return false
+	}
+
+	if links > maxLinks {
+		return true
+	}
+
+	words := len(strings.Fields(text))
+	return links > 1 && d.config.MaxLinkRatio > 0 && float64(links)/float64(words) >= d.config.MaxLinkRatio
+}
+
+func windowStart(timestamp uint64, window uint64) uint64 {
+	if timestamp < window*1000 {
+		return 0
+	}
+	return timestamp - window*1000
+}
@@ -0,0 +1,128 @@
+package antispam
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func testConfig() Config {
+	config := DefaultConfig()
+	config.ProbationPeriod = 0
+	return config
+}
+
+func TestDetectorDisabled(t *testing.T) {
+	config := testConfig()
+	config.Enabled = false
+	config.RateLimitMessages = 1
+	d := NewDetector(config)
+
+	for i := uint64(1); i <= 5; i++ {
+		require.False(t, d.Check(Message{SenderID: "a", Text: "hello", Timestamp: i}).Spam)
+	}
+}
+
+func TestDetectorRateLimit(t *testing.T) {
+	config := testConfig()
+	config.RateLimitMessages = 2
+	config.RateLimitWindow = 10
+	d := NewDetector(config)
+
+	require.False(t, d.Check(Message{SenderID: "a", Text: "first message", Timestamp: 1000}).Spam)
+	require.False(t, d.Check(Message{SenderID: "a", Text: "second one", Timestamp: 2000}).Spam)
+
+	verdict := d.Check(Message{SenderID: "a", Text: "and a third", Timestamp: 3000})
+	require.True(t, verdict.Spam)
+	require.Equal(t, []Reason{ReasonRateLimit}, verdict.Reasons)
+
+	// Other senders are not affected
+	require.False(t, d.Check(Message{SenderID: "b", Text: "something else", Timestamp: 3000}).Spam)
+
+	// Window has passed
+	require.False(t, d.Check(Message{SenderID: "a", Text: "later on", Timestamp: 20000}).Spam)
+}
+
+func TestDetectorDuplicates(t *testing.T) {
+	config := testConfig()
+	config.DuplicateThreshold = 2
+	d := NewDetector(config)
+
+	require.False(t, d.Check(Message{SenderID: "a", Text: "Buy cheap tokens now at our store", Timestamp: 1000}).Spam)
+	require.False(t, d.Check(Message{SenderID: "b", Text: "buy cheap tokens now, at our store!", Timestamp: 2000}).Spam)
+
+	verdict := d.Check(Message{SenderID: "c", Text: "BUY cheap tokens now at our store", Timestamp: 3000})
+	require.True(t, verdict.Spam)
+	require.Equal(t, []Reason{ReasonDuplicate}, verdict.Reasons)
+
+	require.False(t, d.Check(Message{SenderID: "d", Text: "what time is the community call today?", Timestamp: 4000}).Spam)
+
+	// the same content in another chat
+	require.False(t, d.Check(Message{SenderID: "e", ChatID: "other", Text: "buy cheap tokens now at our store", Timestamp: 5000}).Spam)
+
+	// short replies are never duplicates
+	for i, sender := range []string{"a", "b", "c", "d"} {
+		require.False(t, d.Check(Message{SenderID: sender, Text: "thanks!", Timestamp: uint64(6000 + i)}).Spam)
+	}
+}
+
+func TestDetectorBoundsRecentMessages(t *testing.T) {
+	d := NewDetector(testConfig())
+
+	for i := 0; i < maxRecentMessages+10; i++ {
+		d.Check(Message{SenderID: "a", Text: fmt.Sprintf("message number %d of the test", i), Timestamp: uint64(1000 + i)})
+	}
+	require.Len(t, d.recent[""], maxRecentMessages)
+}
+
+func TestDetectorLinks(t *testing.T) {
+	config := testConfig()
+	config.MaxLinks = 2
+	config.MaxLinkRatio = 0.5
+	d := NewDetector(config)
+
+	require.False(t, d.Check(Message{SenderID: "a", Text: "have a look at https://status.app it's great", Timestamp: 1000}).Spam)
+
+	verdict := d.Check(Message{SenderID: "b", Text: "https://a.com https://b.com https://c.com", Timestamp: 2000})
+	require.True(t, verdict.Spam)
+	require.Equal(t, []Reason{ReasonLinks}, verdict.Reasons)
+
+	verdict = d.Check(Message{SenderID: "c", Text: "go www.a.com https://b.com", Timestamp: 3000})
+	require.True(t, verdict.Spam)
+}
+
+func TestDetectorProbation(t *testing.T) {
+	config := testConfig()
+	config.ProbationPeriod = 60
+	config.ProbationMaxLinks = 0
+	d := NewDetector(config)
+
+	verdict := d.Check(Message{SenderID: "a", Text: "see https://status.app", Timestamp: 2000, FirstSeen: 1000})
+	require.True(t, verdict.Spam)
+	require.Equal(t, []Reason{ReasonProbation, ReasonLinks}, verdict.Reasons)
+
+	// Probation is over
+	require.False(t, d.Check(Message{SenderID: "a", Text: "see https://status.app", Timestamp: 62000, FirstSeen: 1000}).Spam)
+}
+
+func TestDetectorEvictsIdleSenders(t *testing.T) {
+	config := testConfig()
+	config.RateLimitWindow = 1
+	d := NewDetector(config)
+
+	for i := 0; i < maxTrackedSenders; i++ {
+		d.Check(Message{SenderID: fmt.Sprintf("sender-%d", i), Timestamp: 1000})
+	}
+	require.Len(t, d.senders, maxTrackedSenders)
+
+	d.Check(Message{SenderID: "new-sender", Timestamp: 5000})
+	require.Len(t, d.senders, 1)
+}
+
+func TestFingerprintSimilarity(t *testing.T) {
+	require.Equal(t, 1.0, newFingerprint("Hello   World!").similarity(newFingerprint("hello world")))
+	require.Equal(t, 1.0, newFingerprint("hey").similarity(newFingerprint("HEY")))
+	require.Less(t, newFingerprint("hello world").similarity(newFingerprint("completely different")), 0.2)
+	require.Equal(t, 0.0, newFingerprint("").similarity(newFingerprint("hello")))
+}
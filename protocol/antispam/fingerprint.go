@@ -0,0 +1,87 @@
+package antispam
+
+import (
+	"strings"
+	"unicode"
+)
+
+// shingleSize is the number of runes covered by each rolling hash
+const shingleSize = 5
+
+// rollingHashBase is the base of the rabin-karp rolling hash, arithmetic is mod 2^64
+const rollingHashBase uint64 = 1099511628211
+
+type fingerprint map[uint64]struct{}
+
+// normalize lowercases the text, drops punctuation and collapses whitespace
+// so that trivial variations of the same content hash the same way
+func normalize(text string) []rune {
+	var result []rune
+	space := false
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case unicode.IsLetter(r) || unicode.IsDigit(r):
+			if space && len(result) > 0 {
+				result = append(result, ' ')
+			}
+			result = append(result, r)
+			space = false
+		case unicode.IsSpace(r):
+			space = true
+		}
+	}
+	return result
+}
+
+// newFingerprint computes the set of rolling hashes of all the shingles of the text
+func newFingerprint(text string) fingerprint {
+	runes := normalize(text)
+	result := make(fingerprint)
+	if len(runes) == 0 {
+		return result
+	}
+
+	if len(runes) < shingleSize {
+		var hash uint64
+		for _, r := range runes {
+			hash = hash*rollingHashBase + uint64(r)
+		}
+		result[hash] = struct{}{}
+		return result
+	}
+
+	var power uint64 = 1
+	for i := 1; i < shingleSize; i++ {
+		power *= rollingHashBase
+	}
+
+	var hash uint64
+	for i := 0; i < shingleSize; i++ {
+		hash = hash*rollingHashBase + uint64(runes[i])
+	}
+	result[hash] = struct{}{}
+
+	for i := shingleSize; i < len(runes); i++ {
+		hash = (hash-uint64(runes[i-shingleSize])*power)*rollingHashBase + uint64(runes[i])
+		result[hash] = struct{}{}
+	}
+
+	return result
+}
+
+// similarity returns the jaccard index of the two fingerprints
+func (f fingerprint) similarity(other fingerprint) float64 {
+	if len(f) == 0 || len(other) == 0 {
+		return 0
+	}
+
+	intersection := 0
+	for hash := range f {
+		if _, ok := other[hash]; ok {
+			intersection++
+		}
+	}
+
+	union := len(f) + len(other) - intersection
+	return float64(intersection) / float64(union)
+}
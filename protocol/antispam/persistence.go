@@ -0,0 +1,203 @@
+package antispam
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+var ErrQuarantinedMessageNotFound = errors.New("quarantined message not found")
+
+// QuarantinedMessage is a received message that was flagged as spam and held back
+// from the chat. Payload is the serialized protobuf.ChatMessage.
+type QuarantinedMessage struct {
+	ID               string   `json:"id"`
+	ChatID           string   `json:"chatId"`
+	CommunityID      string   `json:"communityId"`
+	SenderID         string   `json:"senderId"`
+	Text             string   `json:"text"`
+	Payload          []byte   `json:"-"`
+	WhisperTimestamp uint64   `json:"whisperTimestamp"`
+	Reasons          []Reason `json:"reasons"`
+	QuarantinedAt    uint64   `json:"quarantinedAt"`
+}
+
+type Persistence struct {
+	db *sql.DB
+}
+
+func NewPersistence(db *sql.DB) *Persistence {
+	return &Persistence{db: db}
+}
+
+func (p *Persistence) SaveQuarantinedMessage(message *QuarantinedMessage) error {
+	_, err := p.db.Exec(`INSERT OR REPLACE INTO antispam_quarantined_messages (id, chat_id, community_id, sender_id, text, payload, whisper_timestamp, reasons, quarantined_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		message.ID, message.ChatID, message.CommunityID, message.SenderID, message.Text, message.Payload, message.WhisperTimestamp, joinReasons(message.Reasons), message.QuarantinedAt)
+	return err
+}
+
+func (p *Persistence) QuarantinedMessage(id string) (*QuarantinedMessage, error) {
+	messages, err := p.queryQuarantinedMessages(`WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, ErrQuarantinedMessageNotFound
+	}
+
+	return messages[0], nil
+}
+
+// QuarantinedMessages returns the quarantined messages of a community, all of them if communityID is empty
+func (p *Persistence) QuarantinedMessages(communityID string) ([]*QuarantinedMessage, error) {
+	if communityID == "" {
+		return p.queryQuarantinedMessages(`ORDER BY quarantined_at DESC`)
+	}
+	return p.queryQuarantinedMessages(`WHERE community_id = ? ORDER BY quarantined_at DESC`, communityID)
+}
+
+func (p *Persistence) DeleteQuarantinedMessages(ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	args := make([]interface{}, 0, len(ids))
+	for _, id := range ids {
+		args = append(args, id)
+	}
+	inVector := strings.Repeat("?, ", len(ids)-1) + "?"
+	_, err := p.db.Exec(`DELETE FROM antispam_quarantined_messages WHERE id IN (`+inVector+`)`, args...) // nolint: gosec
+	return err
+}
+
+// FirstQuarantinedTimestampFromSender returns the whisper timestamp of the first message
+// of the sender quarantined in the community, 0 if none
+func (p *Persistence) FirstQuarantinedTimestampFromSender(communityID string, senderID string) (uint64, error) {
+	var timestamp sql.NullInt64
+	err := p.db.QueryRow(`SELECT MIN(whisper_timestamp) FROM antispam_quarantined_messages WHERE community_id = ? AND sender_id = ?`, communityID, senderID).Scan(&timestamp)
+	if err != nil {
+		return 0, err
+	}
+	return uint64(timestamp.Int64), nil
+}
+
+func (p *Persistence) QuarantinedMessageIDsBySender(senderID string) ([]string, error) {
+	rows, err := p.db.Query(`SELECT id FROM antispam_quarantined_messages WHERE sender_id = ?`, senderID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (p *Persistence) queryQuarantinedMessages(where string, args ...interface{}) ([]*QuarantinedMessage, error) {
+	rows, err := p.db.Query(`SELECT id, chat_id, community_id, sender_id, text, payload, whisper_timestamp, reasons, quarantined_at FROM antispam_quarantined_messages `+where, args...) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*QuarantinedMessage
+	for rows.Next() {
+		var reasons string
+		message := &QuarantinedMessage{}
+		err := rows.Scan(&message.ID, &message.ChatID, &message.CommunityID, &message.SenderID, &message.Text, &message.Payload, &message.WhisperTimestamp, &reasons, &message.QuarantinedAt)
+		if err != nil {
+			return nil, err
+		}
+		message.Reasons = splitReasons(reasons)
+		messages = append(messages, message)
+	}
+	return messages, rows.Err()
+}
+
+func (p *Persistence) AddToWhitelist(senderID string, addedAt uint64) error {
+	_, err := p.db.Exec(`INSERT OR REPLACE INTO antispam_whitelist (sender_id, added_at) VALUES (?, ?)`, senderID, addedAt)
+	return err
+}
+
+func (p *Persistence) RemoveFromWhitelist(senderID string) error {
+	_, err := p.db.Exec(`DELETE FROM antispam_whitelist WHERE sender_id = ?`, senderID)
+	return err
+}
+
+func (p *Persistence) IsWhitelisted(senderID string) (bool, error) {
+	var exists bool
+	err := p.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM antispam_whitelist WHERE sender_id = ?)`, senderID).Scan(&exists)
+	return exists, err
+}
+
+func (p *Persistence) Whitelist() ([]string, error) {
+	rows, err := p.db.Query(`SELECT sender_id FROM antispam_whitelist ORDER BY added_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var senders []string
+	for rows.Next() {
+		var sender string
+		if err := rows.Scan(&sender); err != nil {
+			return nil, err
+		}
+		senders = append(senders, sender)
+	}
+	return senders, rows.Err()
+}
+
+func (p *Persistence) SaveConfig(config Config) error {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(`INSERT OR REPLACE INTO antispam_config (id, config) VALUES (1, ?)`, encoded)
+	return err
+}
+
+// GetConfig returns the stored config, or the default one if none was saved
+func (p *Persistence) GetConfig() (Config, error) {
+	var encoded []byte
+	err := p.db.QueryRow(`SELECT config FROM antispam_config WHERE id = 1`).Scan(&encoded)
+	if err == sql.ErrNoRows {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	config := DefaultConfig()
+	err = json.Unmarshal(encoded, &config)
+	return config, err
+}
+
+func joinReasons(reasons []Reason) string {
+	values := make([]string, len(reasons))
+	for i, r := range reasons {
+		values[i] = string(r)
+	}
+	return strings.Join(values, ",")
+}
+
+func splitReasons(value string) []Reason {
+	if value == "" {
+		return nil
+	}
+
+	var reasons []Reason
+	for _, r := range strings.Split(value, ",") {
+		reasons = append(reasons, Reason(r))
+	}
+	return reasons
+}
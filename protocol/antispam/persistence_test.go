@@ -0,0 +1,107 @@
+package antispam
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/appdatabase"
+	"github.com/status-im/status-go/protocol/sqlite"
+	"github.com/status-im/status-go/t/helpers"
+)
+
+func TestPersistenceSuite(t *testing.T) {
+	suite.Run(t, new(PersistenceSuite))
+}
+
+type PersistenceSuite struct {
+	suite.Suite
+	p *Persistence
+}
+
+func (s *PersistenceSuite) SetupTest() {
+	db, err := helpers.SetupTestMemorySQLDB(appdatabase.DbInitializer{})
+	s.Require().NoError(err)
+
+	err = sqlite.Migrate(db)
+	s.Require().NoError(err)
+
+	s.p = NewPersistence(db)
+}
+
+func (s *PersistenceSuite) TestQuarantinedMessages() {
+	message := &QuarantinedMessage{
+		ID:               "message-1",
+		ChatID:           "chat-1",
+		CommunityID:      "community-1",
+		SenderID:         "sender-1",
+		Text:             "spam",
+		Payload:          []byte{1, 2, 3},
+		WhisperTimestamp: 10,
+		Reasons:          []Reason{ReasonProbation, ReasonLinks},
+		QuarantinedAt:    20,
+	}
+	s.Require().NoError(s.p.SaveQuarantinedMessage(message))
+
+	fetched, err := s.p.QuarantinedMessage("message-1")
+	s.Require().NoError(err)
+	s.Require().Equal(message, fetched)
+
+	messages, err := s.p.QuarantinedMessages("community-1")
+	s.Require().NoError(err)
+	s.Require().Len(messages, 1)
+
+	messages, err = s.p.QuarantinedMessages("community-2")
+	s.Require().NoError(err)
+	s.Require().Len(messages, 0)
+
+	first, err := s.p.FirstQuarantinedTimestampFromSender("community-1", "sender-1")
+	s.Require().NoError(err)
+	s.Require().Equal(uint64(10), first)
+
+	first, err = s.p.FirstQuarantinedTimestampFromSender("community-2", "sender-1")
+	s.Require().NoError(err)
+	s.Require().Zero(first)
+
+	ids, err := s.p.QuarantinedMessageIDsBySender("sender-1")
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"message-1"}, ids)
+
+	s.Require().NoError(s.p.DeleteQuarantinedMessages(ids))
+	_, err = s.p.QuarantinedMessage("message-1")
+	s.Require().ErrorIs(err, ErrQuarantinedMessageNotFound)
+}
+
+func (s *PersistenceSuite) TestWhitelist() {
+	whitelisted, err := s.p.IsWhitelisted("sender-1")
+	s.Require().NoError(err)
+	s.Require().False(whitelisted)
+
+	s.Require().NoError(s.p.AddToWhitelist("sender-1", 1))
+	whitelisted, err = s.p.IsWhitelisted("sender-1")
+	s.Require().NoError(err)
+	s.Require().True(whitelisted)
+
+	senders, err := s.p.Whitelist()
+	s.Require().NoError(err)
+	s.Require().Equal([]string{"sender-1"}, senders)
+
+	s.Require().NoError(s.p.RemoveFromWhitelist("sender-1"))
+	whitelisted, err = s.p.IsWhitelisted("sender-1")
+	s.Require().NoError(err)
+	s.Require().False(whitelisted)
+}
+
+func (s *PersistenceSuite) TestConfig() {
+	config, err := s.p.GetConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(DefaultConfig(), config)
+
+	config.AutoReport = true
+	config.RateLimitMessages = 42
+	s.Require().NoError(s.p.SaveConfig(config))
+
+	fetched, err := s.p.GetConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(config, fetched)
+}
@@ -0,0 +1,54 @@
+package audio
+
+import (
+	"time"
+)
+
+const (
+	adtsHeaderSize = 7
+	// aacFrameSamples is the number of samples per channel of an AAC raw data block
+	aacFrameSamples = 1024
+)
+
+// adtsSampleRates are the sample rates by the sampling frequency index of ADTS headers
+var adtsSampleRates = []int{96000, 88200, 64000, 48000, 44100, 32000, 24000, 22050, 16000, 12000, 11025, 8000, 7350}
+
+// parseADTS reads the frames of an AAC stream in ADTS, the duration of a frame is
+// given by its sample rate and the number of raw data blocks it carries
+func parseADTS(buf []byte) (*frames, error) {
+	result := &frames{}
+	var samples int64
+	sampleRate := 0
+
+	for len(buf) > 0 {
+		if len(buf) < adtsHeaderSize || buf[0] != 0xFF || buf[1]&0xF6 != 0xF0 {
+			return nil, ErrInvalidStream
+		}
+
+		frameSampleRate := int(buf[2]>>2) & 0x0F
+		if frameSampleRate >= len(adtsSampleRates) {
+			return nil, ErrInvalidStream
+		}
+		if sampleRate == 0 {
+			sampleRate = adtsSampleRates[frameSampleRate]
+		} else if sampleRate != adtsSampleRates[frameSampleRate] {
+			return nil, ErrInvalidStream
+		}
+
+		frameLength := int(buf[3]&0x03)<<11 | int(buf[4])<<3 | int(buf[5])>>5
+		if frameLength < adtsHeaderSize || frameLength > len(buf) {
+			return nil, ErrInvalidStream
+		}
+		blocks := int(buf[6]&0x03) + 1
+
+		samples += int64(blocks * aacFrameSamples)
+		result.sizes = append(result.sizes, frameLength/blocks)
+		buf = buf[frameLength:]
+	}
+
+	if sampleRate == 0 {
+		return nil, ErrInvalidStream
+	}
+	result.duration = time.Duration(samples) * time.Second / time.Duration(sampleRate)
+	return result, nil
+}
@@ -0,0 +1,48 @@
+package audio
+
+import (
+	"time"
+)
+
+const (
+	amrHeader = "#!AMR\n"
+	// amrFrameDuration is the duration of every AMR-NB frame
+	amrFrameDuration = 20 * time.Millisecond
+	// amrLastSpeechFrameType is the last frame type carrying speech, the next ones are silence
+	amrLastSpeechFrameType = 7
+)
+
+// amrFrameSizes are the sizes of the speech data by frame type, without the frame header.
+// Types 0 to 7 are the codec modes, 8 to 11 comfort noise and 12 to 14 are reserved.
+var amrFrameSizes = []int{12, 13, 15, 17, 19, 20, 26, 31, 5, 6, 5, 5, 0, 0, 0, 0}
+
+// parseAMR reads the frames of an AMR-NB stream in the storage format of RFC 4867,
+// frames of comfort noise or without data count as silence in the waveform
+func parseAMR(buf []byte) (*frames, error) {
+	if len(buf) < len(amrHeader) || string(buf[:len(amrHeader)]) != amrHeader {
+		return nil, ErrInvalidStream
+	}
+	buf = buf[len(amrHeader):]
+
+	result := &frames{}
+	for len(buf) > 0 {
+		frameType := int(buf[0]>>3) & 0x0F
+		frameLength := 1 + amrFrameSizes[frameType]
+		if frameLength > len(buf) {
+			return nil, ErrInvalidStream
+		}
+
+		size := frameLength
+		if frameType > amrLastSpeechFrameType {
+			size = 0
+		}
+		result.sizes = append(result.sizes, size)
+		buf = buf[frameLength:]
+	}
+
+	if len(result.sizes) == 0 {
+		return nil, ErrInvalidStream
+	}
+	result.duration = time.Duration(len(result.sizes)) * amrFrameDuration
+	return result, nil
+}
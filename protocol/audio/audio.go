@@ -0,0 +1,99 @@
+package audio
+
+import (
+	"errors"
+	"time"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// WaveformSize is the number of bars of a waveform, shorter audios have one bar per frame
+const WaveformSize = 64
+
+var (
+	ErrEmpty             = errors.New("audio is empty")
+	ErrUnsupportedFormat = errors.New("audio format not supported")
+	ErrInvalidStream     = errors.New("invalid audio stream")
+)
+
+// Metadata describes an audio as read from its stream, it's computed on our side
+// so that the duration and waveform shown don't depend on what the sender claims
+type Metadata struct {
+	Type     protobuf.AudioMessage_AudioType
+	Duration time.Duration
+	// Waveform has up to WaveformSize bars, from 0 to 255
+	Waveform []byte
+}
+
+// frames are the sizes of the frames of an audio stream, in order, and the duration of the stream
+type frames struct {
+	sizes    []int
+	duration time.Duration
+}
+
+// Parse reads the duration and waveform of an AAC, in ADTS or M4A, or AMR audio
+func Parse(buf []byte) (*Metadata, error) {
+	if len(buf) == 0 {
+		return nil, ErrEmpty
+	}
+
+	audioType := Type(buf)
+
+	var f *frames
+	var err error
+	switch audioType {
+	case protobuf.AudioMessage_AAC:
+		f, err = parseADTS(buf)
+	case protobuf.AudioMessage_AMR:
+		f, err = parseAMR(buf)
+	case protobuf.AudioMessage_M4A:
+		f, err = parseM4A(buf)
+	default:
+		return nil, ErrUnsupportedFormat
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return &Metadata{
+		Type:     audioType,
+		Duration: f.duration,
+		Waveform: waveform(f.sizes, WaveformSize),
+	}, nil
+}
+
+// waveform downsamples the sizes of the frames of an audio to the given number of bars.
+// Audios aren't decoded: the waveform follows how many bits the encoder spent over time,
+// silence being cheap to encode, which approximates the loudness well enough to be drawn.
+func waveform(sizes []int, bars int) []byte {
+	if len(sizes) == 0 {
+		return nil
+	}
+	if len(sizes) < bars {
+		bars = len(sizes)
+	}
+
+	averages := make([]float64, bars)
+	var highest float64
+	for i := range averages {
+		start := i * len(sizes) / bars
+		end := (i + 1) * len(sizes) / bars
+		var sum int
+		for _, size := range sizes[start:end] {
+			sum += size
+		}
+		averages[i] = float64(sum) / float64(end-start)
+		if averages[i] > highest {
+			highest = averages[i]
+		}
+	}
+
+	result := make([]byte, bars)
+	if highest == 0 {
+		return result
+	}
+	for i, average := range averages {
+		result[i] = byte(average / highest * 255)
+	}
+	return result
+}
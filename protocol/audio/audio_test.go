@@ -0,0 +1,157 @@
+package audio
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// makeADTSFrame returns an ADTS frame at 44.1kHz with a single raw data block of payloadSize bytes
+func makeADTSFrame(payloadSize int) []byte {
+	frameLength := adtsHeaderSize + payloadSize
+	frame := make([]byte, frameLength)
+	frame[0] = 0xFF
+	frame[1] = 0xF1
+	// AAC LC, sampling frequency index 4, 2 channels
+	frame[2] = 0x50
+	frame[3] = 0x80 | byte(frameLength>>11)&0x03
+	frame[4] = byte(frameLength >> 3)
+	frame[5] = byte(frameLength&0x07)<<5 | 0x1F
+	frame[6] = 0xFC
+	return frame
+}
+
+func makeADTS(payloadSizes ...int) []byte {
+	var buf []byte
+	for _, size := range payloadSizes {
+		buf = append(buf, makeADTSFrame(size)...)
+	}
+	return buf
+}
+
+// makeAMR returns an AMR stream of 12.2kbit/s speech frames, and of frames without data where speech is false
+func makeAMR(speech ...bool) []byte {
+	buf := []byte(amrHeader)
+	for _, isSpeech := range speech {
+		if isSpeech {
+			buf = append(buf, 7<<3|0x04)
+			buf = append(buf, make([]byte, amrFrameSizes[7])...)
+		} else {
+			buf = append(buf, 15<<3|0x04)
+		}
+	}
+	return buf
+}
+
+func makeBox(kind string, payloads ...[]byte) []byte {
+	size := 8
+	for _, payload := range payloads {
+		size += len(payload)
+	}
+	buf := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(size))
+	copy(buf[4:8], kind)
+	for _, payload := range payloads {
+		buf = append(buf, payload...)
+	}
+	return buf
+}
+
+func makeM4A(codec string, duration time.Duration, sampleSizes ...uint32) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], uint32(duration.Milliseconds()))
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], "soun")
+
+	stsd := make([]byte, 8)
+	binary.BigEndian.PutUint32(stsd[4:8], 1)
+	stsd = append(stsd, makeBox(codec, make([]byte, 28))...)
+
+	stsz := make([]byte, 12)
+	binary.BigEndian.PutUint32(stsz[8:12], uint32(len(sampleSizes)))
+	for _, size := range sampleSizes {
+		stsz = binary.BigEndian.AppendUint32(stsz, size)
+	}
+
+	trak := makeBox("trak",
+		makeBox("mdia",
+			makeBox("hdlr", hdlr),
+			makeBox("minf", makeBox("stbl", makeBox("stsd", stsd), makeBox("stsz", stsz)))))
+
+	ftyp := make([]byte, 8)
+	copy(ftyp[0:4], "M4A ")
+
+	return append(makeBox("ftyp", ftyp), makeBox("moov", makeBox("mvhd", mvhd), trak)...)
+}
+
+func TestType(t *testing.T) {
+	require.Equal(t, protobuf.AudioMessage_AAC, Type(makeADTS(10)))
+	require.Equal(t, protobuf.AudioMessage_AMR, Type(makeAMR(true, true)))
+	require.Equal(t, protobuf.AudioMessage_M4A, Type(makeM4A("mp4a", time.Second, 10)))
+	require.Equal(t, protobuf.AudioMessage_UNKNOWN_AUDIO_TYPE, Type([]byte("not an audio")))
+}
+
+func TestParseADTS(t *testing.T) {
+	sizes := make([]int, 100)
+	for i := range sizes {
+		sizes[i] = 100 + i
+	}
+
+	metadata, err := Parse(makeADTS(sizes...))
+	require.NoError(t, err)
+	require.Equal(t, protobuf.AudioMessage_AAC, metadata.Type)
+	// 100 frames of 1024 samples at 44.1kHz
+	require.Equal(t, 100*1024*time.Second/44100, metadata.Duration)
+	require.Len(t, metadata.Waveform, WaveformSize)
+	require.Equal(t, byte(255), metadata.Waveform[WaveformSize-1])
+	require.Less(t, metadata.Waveform[0], metadata.Waveform[WaveformSize-1])
+
+	// truncated frame
+	buf := makeADTS(100, 100)
+	_, err = Parse(buf[:len(buf)-1])
+	require.ErrorIs(t, err, ErrInvalidStream)
+}
+
+func TestParseAMR(t *testing.T) {
+	metadata, err := Parse(makeAMR(true, false, false, true))
+	require.NoError(t, err)
+	require.Equal(t, protobuf.AudioMessage_AMR, metadata.Type)
+	require.Equal(t, 80*time.Millisecond, metadata.Duration)
+	require.Equal(t, []byte{255, 0, 0, 255}, metadata.Waveform)
+
+	// truncated speech frame
+	_, err = Parse(makeAMR(true)[:len(amrHeader)+10])
+	require.ErrorIs(t, err, ErrInvalidStream)
+}
+
+func TestParseM4A(t *testing.T) {
+	metadata, err := Parse(makeM4A("mp4a", 2500*time.Millisecond, 10, 20, 0, 20))
+	require.NoError(t, err)
+	require.Equal(t, protobuf.AudioMessage_M4A, metadata.Type)
+	require.Equal(t, 2500*time.Millisecond, metadata.Duration)
+	require.Equal(t, []byte{127, 255, 0, 255}, metadata.Waveform)
+
+	_, err = Parse(makeM4A("alac", time.Second, 10))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestParseUnsupported(t *testing.T) {
+	_, err := Parse(nil)
+	require.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Parse([]byte("not an audio"))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+}
+
+func TestWaveform(t *testing.T) {
+	require.Nil(t, waveform(nil, WaveformSize))
+	require.Equal(t, []byte{0, 0}, waveform([]int{0, 0}, WaveformSize))
+	// bars average the frames they cover
+	require.Equal(t, []byte{63, 255}, waveform([]int{1, 1, 4, 4}, 2))
+}
@@ -0,0 +1,92 @@
+package audio
+
+import (
+	"encoding/binary"
+	"math"
+	"time"
+
+	"github.com/status-im/status-go/protocol/mp4"
+)
+
+// parseM4A reads the frames of the AAC track of an MP4 container from its sample table,
+// the duration is the one of the movie
+func parseM4A(buf []byte) (*frames, error) {
+	moov, ok := mp4.FindBox(buf, "moov")
+	if !ok {
+		return nil, ErrInvalidStream
+	}
+	mvhd, ok := mp4.FindBox(moov.Payload, "mvhd")
+	if !ok {
+		return nil, ErrInvalidStream
+	}
+	duration, err := mp4.MovieDuration(mvhd.Payload, time.Duration(math.MaxInt64))
+	if err != nil {
+		return nil, ErrInvalidStream
+	}
+
+	traks, err := mp4.ReadBoxes(moov.Payload)
+	if err != nil {
+		return nil, ErrInvalidStream
+	}
+	for _, trak := range traks {
+		if trak.Kind != "trak" {
+			continue
+		}
+		hdlr, ok := mp4.FindBox(trak.Payload, "mdia", "hdlr")
+		if !ok || len(hdlr.Payload) < 12 || string(hdlr.Payload[8:12]) != "soun" {
+			continue
+		}
+
+		stsd, ok := mp4.FindBox(trak.Payload, "mdia", "minf", "stbl", "stsd")
+		if !ok || len(stsd.Payload) < 16 {
+			return nil, ErrInvalidStream
+		}
+		// version and flags, entry count, then the first sample entry
+		if string(stsd.Payload[12:16]) != "mp4a" {
+			return nil, ErrUnsupportedFormat
+		}
+
+		stsz, ok := mp4.FindBox(trak.Payload, "mdia", "minf", "stbl", "stsz")
+		if !ok {
+			return nil, ErrInvalidStream
+		}
+		sizes, err := parseSampleSizes(stsz.Payload, len(buf))
+		if err != nil {
+			return nil, err
+		}
+		return &frames{sizes: sizes, duration: duration}, nil
+	}
+
+	return nil, ErrUnsupportedFormat
+}
+
+// parseSampleSizes reads the sizes of the samples of a track, the payload of an stsz box.
+// The samples can't take more than the mediaSize bytes of the file they're in.
+func parseSampleSizes(stsz []byte, mediaSize int) ([]int, error) {
+	// version and flags, the size shared by all samples if any, then the sample count
+	if len(stsz) < 12 {
+		return nil, ErrInvalidStream
+	}
+	sampleSize := uint64(binary.BigEndian.Uint32(stsz[4:8]))
+	count := uint64(binary.BigEndian.Uint32(stsz[8:12]))
+
+	if sampleSize != 0 {
+		if count*sampleSize > uint64(mediaSize) {
+			return nil, ErrInvalidStream
+		}
+		sizes := make([]int, count)
+		for i := range sizes {
+			sizes[i] = int(sampleSize)
+		}
+		return sizes, nil
+	}
+
+	if uint64(len(stsz)) < 12+4*count {
+		return nil, ErrInvalidStream
+	}
+	sizes := make([]int, 0, count)
+	for i := 0; i < int(count); i++ {
+		sizes = append(sizes, int(binary.BigEndian.Uint32(stsz[12+4*i:16+4*i])))
+	}
+	return sizes, nil
+}
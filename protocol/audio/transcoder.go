@@ -0,0 +1,81 @@
+package audio
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os/exec"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// Transcoder converts audio to AAC in ADTS, which every client can play
+type Transcoder interface {
+	Transcode(ctx context.Context, payload []byte, from protobuf.AudioMessage_AudioType) ([]byte, error)
+}
+
+// FFmpegTranscoder transcodes audio with an ffmpeg binary, one audio at a time
+type FFmpegTranscoder struct {
+	path string
+	// busy is taken while ffmpeg runs
+	busy chan struct{}
+}
+
+// NewFFmpegTranscoder returns a transcoder running the ffmpeg binary at path,
+// which is looked up in PATH when it has no separator
+func NewFFmpegTranscoder(path string) (*FFmpegTranscoder, error) {
+	resolved, err := exec.LookPath(path)
+	if err != nil {
+		return nil, fmt.Errorf("ffmpeg not found: %w", err)
+	}
+	return &FFmpegTranscoder{
+		path: resolved,
+		busy: make(chan struct{}, 1),
+	}, nil
+}
+
+func ffmpegInputFormat(from protobuf.AudioMessage_AudioType) (string, error) {
+	switch from {
+	case protobuf.AudioMessage_AMR:
+		return "amr", nil
+	case protobuf.AudioMessage_M4A:
+		return "mp4", nil
+	case protobuf.AudioMessage_AAC:
+		return "aac", nil
+	default:
+		return "", ErrUnsupportedFormat
+	}
+}
+
+func (t *FFmpegTranscoder) Transcode(ctx context.Context, payload []byte, from protobuf.AudioMessage_AudioType) ([]byte, error) {
+	inputFormat, err := ffmpegInputFormat(from)
+	if err != nil {
+		return nil, err
+	}
+
+	select {
+	case t.busy <- struct{}{}:
+		defer func() { <-t.busy }()
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+
+	// nolint: gosec
+	cmd := exec.CommandContext(ctx, t.path,
+		"-hide_banner", "-loglevel", "error",
+		"-f", inputFormat, "-i", "pipe:0",
+		"-vn", "-c:a", "aac", "-b:a", "64k",
+		"-f", "adts", "pipe:1")
+	cmd.Stdin = bytes.NewReader(payload)
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("ffmpeg failed: %w: %s", err, bytes.TrimSpace(stderr.Bytes()))
+	}
+	if !aac(stdout.Bytes()) {
+		return nil, ErrInvalidStream
+	}
+	return stdout.Bytes(), nil
+}
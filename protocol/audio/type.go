@@ -17,13 +17,34 @@ func amr(buf []byte) bool {
 		buf[4] == 0x52 && buf[5] == 0x0A
 }
 
+// m4a matches MP4 containers, the codec of their audio track is checked when parsed
+func m4a(buf []byte) bool {
+	return len(buf) > 11 &&
+		buf[4] == 'f' && buf[5] == 't' &&
+		buf[6] == 'y' && buf[7] == 'p'
+}
+
 func Type(buf []byte) protobuf.AudioMessage_AudioType {
 	switch {
 	case aac(buf):
 		return protobuf.AudioMessage_AAC
 	case amr(buf):
 		return protobuf.AudioMessage_AMR
+	case m4a(buf):
+		return protobuf.AudioMessage_M4A
 	default:
 		return protobuf.AudioMessage_UNKNOWN_AUDIO_TYPE
 	}
 }
+
+// MimeType returns the MIME type of an audio type
+func MimeType(audioType protobuf.AudioMessage_AudioType) string {
+	switch audioType {
+	case protobuf.AudioMessage_AMR:
+		return "audio/amr"
+	case protobuf.AudioMessage_M4A:
+		return "audio/mp4"
+	default:
+		return "audio/aac"
+	}
+}
@@ -0,0 +1,115 @@
+package common
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"mime"
+	"net/http"
+	"path/filepath"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	// MaxFileSize is the maximum size of a file attachment, the message carrying it is split in segments
+	MaxFileSize = 10 * 1024 * 1024
+
+	fileKeyLength = 32
+	// fileEncryptionOverhead is the size of the nonce and of the authentication tag added by AES-GCM
+	fileEncryptionOverhead = nonceLength + 16
+)
+
+var (
+	ErrFileEmpty           = errors.New("file is empty")
+	ErrFileTooLarge        = errors.New("file is too large")
+	ErrFileIntegrity       = errors.New("file doesn't match its hash")
+	ErrFileInvalidManifest = errors.New("invalid file manifest")
+)
+
+// EncryptFile encrypts a file with a new key and returns the message carrying it
+func EncryptFile(name string, content []byte) (*protobuf.FileMessage, error) {
+	if len(content) == 0 {
+		return nil, ErrFileEmpty
+	}
+	if len(content) > MaxFileSize {
+		return nil, ErrFileTooLarge
+	}
+
+	key := make([]byte, fileKeyLength)
+	if _, err := rand.Read(key); err != nil {
+		return nil, err
+	}
+
+	payload, err := Encrypt(content, key, rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	return &protobuf.FileMessage{
+		Payload:  payload,
+		Name:     filepath.Base(name),
+		Size:     uint64(len(content)),
+		MimeType: FileMimeType(name, content),
+		Hash:     hash[:],
+		Key:      key,
+	}, nil
+}
+
+// DecryptFile decrypts the payload of a file and checks it against the manifest
+func DecryptFile(manifest *protobuf.FileMessage, payload []byte) ([]byte, error) {
+	if err := ValidateFileManifest(manifest); err != nil {
+		return nil, err
+	}
+
+	content, err := Decrypt(payload, manifest.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	hash := sha256.Sum256(content)
+	if uint64(len(content)) != manifest.Size || !bytes.Equal(hash[:], manifest.Hash) {
+		return nil, ErrFileIntegrity
+	}
+	return content, nil
+}
+
+// ValidateFileManifest checks the fields describing a file, leaving out the payload
+func ValidateFileManifest(manifest *protobuf.FileMessage) error {
+	if manifest == nil || manifest.Name == "" || len(manifest.Hash) != sha256.Size || len(manifest.Key) != fileKeyLength {
+		return ErrFileInvalidManifest
+	}
+	if manifest.Size == 0 {
+		return ErrFileEmpty
+	}
+	if manifest.Size > MaxFileSize {
+		return ErrFileTooLarge
+	}
+	return nil
+}
+
+// ValidateFilePayload checks that the encrypted payload has the size expected from the manifest
+func ValidateFilePayload(manifest *protobuf.FileMessage, payload []byte) error {
+	if uint64(len(payload)) != manifest.Size+fileEncryptionOverhead {
+		return ErrFileIntegrity
+	}
+	return nil
+}
+
+// FilePayloadTopic returns the name of the public topic the payload of a file is published on.
+// It's derived from the key of the file so that only the recipients of the file can find it.
+func FilePayloadTopic(manifest *protobuf.FileMessage) string {
+	hash := sha256.Sum256(manifest.Key)
+	return "file-" + hex.EncodeToString(hash[:])
+}
+
+// FileMimeType guesses the MIME type of a file from its extension, then from its content
+func FileMimeType(name string, content []byte) string {
+	if mimeType := mime.TypeByExtension(filepath.Ext(name)); mimeType != "" {
+		return mimeType
+	}
+	return http.DetectContentType(content)
+}
@@ -0,0 +1,52 @@
+package common
+
+import (
+	"crypto/rand"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestEncryptFile(t *testing.T) {
+	content := []byte("some content")
+
+	file, err := EncryptFile("/tmp/notes.txt", content)
+	require.NoError(t, err)
+	require.Equal(t, "notes.txt", file.Name)
+	require.Equal(t, uint64(len(content)), file.Size)
+	require.Equal(t, "text/plain; charset=utf-8", file.MimeType)
+	require.NotEqual(t, content, file.Payload)
+	require.NoError(t, ValidateFileManifest(file))
+	require.NoError(t, ValidateFilePayload(file, file.Payload))
+	require.ErrorIs(t, ValidateFilePayload(file, file.Payload[1:]), ErrFileIntegrity)
+
+	decrypted, err := DecryptFile(file, file.Payload)
+	require.NoError(t, err)
+	require.Equal(t, content, decrypted)
+
+	// A file encrypted with another key doesn't match the manifest
+	other, err := EncryptFile("notes.txt", []byte("other content"))
+	require.NoError(t, err)
+	_, err = DecryptFile(file, other.Payload)
+	require.Error(t, err)
+}
+
+func TestEncryptFileLimits(t *testing.T) {
+	_, err := EncryptFile("empty", nil)
+	require.ErrorIs(t, err, ErrFileEmpty)
+
+	_, err = EncryptFile("large", make([]byte, MaxFileSize+1))
+	require.ErrorIs(t, err, ErrFileTooLarge)
+}
+
+func TestDecryptFileIntegrity(t *testing.T) {
+	file, err := EncryptFile("notes.txt", []byte("some content"))
+	require.NoError(t, err)
+
+	// Same size, other content, encrypted with the key of the manifest
+	payload, err := Encrypt([]byte("some CONTENT"), file.Key, rand.Reader)
+	require.NoError(t, err)
+
+	_, err = DecryptFile(file, payload)
+	require.ErrorIs(t, err, ErrFileIntegrity)
+}
@@ -23,6 +23,7 @@ import (
 	"github.com/status-im/status-go/images"
 	"github.com/status-im/status-go/protocol/audio"
 	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/video"
 )
 
 // QuotedMessage contains the original text of the message replied to
@@ -177,6 +178,20 @@ type Message struct {
 	ImageLocalURL string `json:"imageLocalUrl,omitempty"`
 	// AudioLocalURL is the local url of the audio
 	AudioLocalURL string `json:"audioLocalUrl,omitempty"`
+	// FilePath is the path of the file to be sent
+	FilePath string `json:"filePath,omitempty"`
+	// FileLocalURL is the local url of the file
+	FileLocalURL string `json:"fileLocalUrl,omitempty"`
+	// FilePayload is the encrypted content of the file being sent, published apart from the message
+	FilePayload []byte `json:"-"`
+	// VideoPath is the path of the video to be sent
+	VideoPath string `json:"videoPath,omitempty"`
+	// VideoThumbnailPath is the path of the poster frame of the video to be sent
+	VideoThumbnailPath string `json:"videoThumbnailPath,omitempty"`
+	// VideoLocalURL is the local url of the video
+	VideoLocalURL string `json:"videoLocalUrl,omitempty"`
+	// VideoThumbnailLocalURL is the local url of the poster frame of the video
+	VideoThumbnailLocalURL string `json:"videoThumbnailLocalUrl,omitempty"`
 	// StickerLocalURL is the local url of the sticker
 	StickerLocalURL string `json:"stickerLocalUrl,omitempty"`
 
@@ -203,6 +218,7 @@ type Message struct {
 	Links              []string
 	LinkPreviews       []LinkPreview       `json:"linkPreviews"`
 	StatusLinkPreviews []StatusLinkPreview `json:"statusLinkPreviews"`
+	RichLinkPreviews   []RichLinkPreview   `json:"richLinkPreviews"`
 
 	// EditedAt indicates the clock value it was edited
 	EditedAt uint64 `json:"editedAt"`
@@ -214,6 +230,9 @@ type Message struct {
 
 	DeletedForMe bool `json:"deletedForMe"`
 
+	// MediaHidden indicates the media of the message matched a blocklist and is hidden until revealed
+	MediaHidden bool `json:"mediaHidden"`
+
 	// ContactRequestState is the state of the contact request message
 	ContactRequestState ContactRequestState `json:"contactRequestState,omitempty"`
 
@@ -260,8 +279,19 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		ImageWidth               uint32                           `json:"imageWidth,omitempty"`
 		ImageHeight              uint32                           `json:"imageHeight,omitempty"`
 		AlbumImagesCount         uint32                           `json:"albumImagesCount,omitempty"`
+		ImagePlaceholder         string                           `json:"imagePlaceholder,omitempty"`
 		Audio                    string                           `json:"audio,omitempty"`
 		AudioDurationMs          uint64                           `json:"audioDurationMs,omitempty"`
+		AudioWaveform            []byte                           `json:"audioWaveform,omitempty"`
+		File                     string                           `json:"file,omitempty"`
+		FileName                 string                           `json:"fileName,omitempty"`
+		FileSize                 uint64                           `json:"fileSize,omitempty"`
+		FileMimeType             string                           `json:"fileMimeType,omitempty"`
+		Video                    string                           `json:"video,omitempty"`
+		VideoThumbnail           string                           `json:"videoThumbnail,omitempty"`
+		VideoDurationMs          uint64                           `json:"videoDurationMs,omitempty"`
+		VideoWidth               uint32                           `json:"videoWidth,omitempty"`
+		VideoHeight              uint32                           `json:"videoHeight,omitempty"`
 		CommunityID              string                           `json:"communityId,omitempty"`
 		Sticker                  *StickerAlias                    `json:"sticker,omitempty"`
 		CommandParameters        *CommandParameters               `json:"commandParameters,omitempty"`
@@ -275,15 +305,18 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		Links                    []string                         `json:"links,omitempty"`
 		LinkPreviews             []LinkPreview                    `json:"linkPreviews,omitempty"`
 		StatusLinkPreviews       []StatusLinkPreview              `json:"statusLinkPreviews,omitempty"`
+		RichLinkPreviews         []RichLinkPreview                `json:"richLinkPreviews,omitempty"`
 		EditedAt                 uint64                           `json:"editedAt,omitempty"`
 		Deleted                  bool                             `json:"deleted,omitempty"`
 		DeletedBy                string                           `json:"deletedBy,omitempty"`
 		DeletedForMe             bool                             `json:"deletedForMe,omitempty"`
+		MediaHidden              bool                             `json:"mediaHidden,omitempty"`
 		ContactRequestState      ContactRequestState              `json:"contactRequestState,omitempty"`
 		ContactVerificationState ContactVerificationState         `json:"contactVerificationState,omitempty"`
 		DiscordMessage           *protobuf.DiscordMessage         `json:"discordMessage,omitempty"`
 		BridgeMessage            *protobuf.BridgeMessage          `json:"bridgeMessage,omitempty"`
 		PaymentRequests          []*protobuf.PaymentRequest       `json:"paymentRequests,omitempty"`
+		ForwardedFrom            *protobuf.ForwardedFrom          `json:"forwardedFrom,omitempty"`
 	}
 	item := MessageStructType{
 		ID:                       m.ID,
@@ -308,6 +341,9 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		DisplayName:              m.DisplayName,
 		Image:                    m.ImageLocalURL,
 		Audio:                    m.AudioLocalURL,
+		File:                     m.FileLocalURL,
+		Video:                    m.VideoLocalURL,
+		VideoThumbnail:           m.VideoThumbnailLocalURL,
 		CommunityID:              m.CommunityID,
 		Timestamp:                m.Timestamp,
 		ContentType:              m.ContentType,
@@ -317,6 +353,7 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		Links:                    m.Links,
 		LinkPreviews:             m.LinkPreviews,
 		StatusLinkPreviews:       m.StatusLinkPreviews,
+		RichLinkPreviews:         m.RichLinkPreviews,
 		MessageType:              m.MessageType,
 		CommandParameters:        m.CommandParameters,
 		GapParameters:            m.GapParameters,
@@ -324,9 +361,11 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		Deleted:                  m.Deleted,
 		DeletedBy:                m.DeletedBy,
 		DeletedForMe:             m.DeletedForMe,
+		MediaHidden:              m.MediaHidden,
 		ContactRequestState:      m.ContactRequestState,
 		ContactVerificationState: m.ContactVerificationState,
 		PaymentRequests:          m.PaymentRequests,
+		ForwardedFrom:            m.GetForwardedFrom(),
 	}
 
 	if sticker := m.GetSticker(); sticker != nil {
@@ -339,6 +378,19 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 
 	if audio := m.GetAudio(); audio != nil {
 		item.AudioDurationMs = audio.DurationMs
+		item.AudioWaveform = audio.Waveform
+	}
+
+	if file := m.GetFile(); file != nil {
+		item.FileName = file.Name
+		item.FileSize = file.Size
+		item.FileMimeType = file.MimeType
+	}
+
+	if video := m.GetVideo(); video != nil {
+		item.VideoDurationMs = video.DurationMs
+		item.VideoWidth = video.Width
+		item.VideoHeight = video.Height
 	}
 
 	if image := m.GetImage(); image != nil {
@@ -346,6 +398,15 @@ func (m *Message) MarshalJSON() ([]byte, error) {
 		item.ImageWidth = image.Width
 		item.ImageHeight = image.Height
 		item.AlbumImagesCount = image.AlbumImagesCount
+
+		// placeholders of unsupported types are dropped, the image is shown once loaded
+		if images.GetProtobufImageFormat(image.Placeholder) != protobuf.ImageFormat_UNKNOWN_IMAGE_FORMAT {
+			placeholder, err := images.GetPayloadDataURI(image.Placeholder)
+			if err != nil {
+				return nil, err
+			}
+			item.ImagePlaceholder = placeholder
+		}
 	}
 
 	if discordMessage := m.GetDiscordMessage(); discordMessage != nil {
@@ -378,6 +439,7 @@ func (m *Message) UnmarshalJSON(data []byte) error {
 		ChatID             string                           `json:"chatId"`
 		Sticker            *protobuf.StickerMessage         `json:"sticker"`
 		AudioDurationMs    uint64                           `json:"audioDurationMs"`
+		FileName           string                           `json:"fileName"`
 		ParsedText         json.RawMessage                  `json:"parsedText"`
 		ContentType        protobuf.ChatMessage_ContentType `json:"contentType"`
 		AlbumID            string                           `json:"albumId"`
@@ -403,6 +465,12 @@ func (m *Message) UnmarshalJSON(data []byte) error {
 		}
 	}
 
+	if aux.ContentType == protobuf.ChatMessage_FILE {
+		m.Payload = &protobuf.ChatMessage_File{
+			File: &protobuf.FileMessage{Name: aux.FileName},
+		}
+	}
+
 	if aux.ContentType == protobuf.ChatMessage_IMAGE {
 		m.Payload = &protobuf.ChatMessage_Image{
 			Image: &protobuf.ImageMessage{
@@ -641,6 +709,12 @@ func (m *Message) GetSimplifiedText(identity string, canonicalNames map[string]s
 	if m.ContentType == protobuf.ChatMessage_IMAGE {
 		return "Image", nil
 	}
+	if m.ContentType == protobuf.ChatMessage_FILE {
+		return "File", nil
+	}
+	if m.ContentType == protobuf.ChatMessage_VIDEO {
+		return "Video", nil
+	}
 	if m.ContentType == protobuf.ChatMessage_COMMUNITY {
 		return "Community", nil
 	}
@@ -665,6 +739,8 @@ func getAudioMessageMIME(i *protobuf.AudioMessage) (string, error) {
 		return "aac", nil
 	case protobuf.AudioMessage_AMR:
 		return "amr", nil
+	case protobuf.AudioMessage_M4A:
+		return "mp4", nil
 	}
 
 	return "", errors.New("audio format not supported")
@@ -740,20 +816,124 @@ func (m *Message) LoadAudio() error {
 		return errors.New("no audio has been passed")
 	}
 	audioMessage.Payload = payload
-	audioMessage.Type = audio.Type(payload)
 	m.Payload = &protobuf.ChatMessage_Audio{Audio: audioMessage}
+	if err := m.SetAudioMetadata(); err != nil {
+		return err
+	}
 	return os.Remove(m.AudioPath)
 }
 
-func (m *Message) LoadImage() error {
-	payload, err := images.OpenAndAdjustImage(images.CroppedImage{ImagePath: m.ImagePath}, false)
+// SetAudioMetadata sets the type, duration and waveform of the audio of the message from its payload,
+// replacing the ones given by the client or the sender
+func (m *Message) SetAudioMetadata() error {
+	audioMessage := m.GetAudio()
+	if audioMessage == nil || len(audioMessage.Payload) == 0 {
+		return nil
+	}
 
+	metadata, err := audio.Parse(audioMessage.Payload)
 	if err != nil {
 		return err
 	}
+	audioMessage.Type = metadata.Type
+	audioMessage.DurationMs = uint64(metadata.Duration.Milliseconds())
+	audioMessage.Waveform = metadata.Waveform
+	return nil
+}
+
+// SanitizeImage drops the metadata of an image sent from an existing payload, as shared
+// or forwarded images are, and adds the placeholder missing from the older images
+func (m *Message) SanitizeImage() error {
 	imageMessage := m.GetImage()
+	if imageMessage == nil || len(imageMessage.Payload) == 0 {
+		return nil
+	}
+
+	payload, err := images.StripMetadata(imageMessage.Payload)
+	if err != nil {
+		return err
+	}
 	imageMessage.Payload = payload
 	imageMessage.Format = images.GetProtobufImageFormat(payload)
+
+	if len(imageMessage.Placeholder) == 0 {
+		imageMessage.Placeholder, err = images.PlaceholderFromPayload(payload)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// LoadFile encrypts the file at FilePath, the manifest goes in the payload of the message and the
+// encrypted content in FilePayload. The name set by the client is kept, the file itself is left in place.
+func (m *Message) LoadFile() error {
+	content, err := os.ReadFile(m.FilePath)
+	if err != nil {
+		return err
+	}
+
+	name := m.FilePath
+	if fileMessage := m.GetFile(); fileMessage != nil && fileMessage.Name != "" {
+		name = fileMessage.Name
+	}
+
+	fileMessage, err := EncryptFile(name, content)
+	if err != nil {
+		return err
+	}
+	m.FilePayload = fileMessage.Payload
+	fileMessage.Payload = nil
+	m.Payload = &protobuf.ChatMessage_File{File: fileMessage}
+	return nil
+}
+
+// LoadVideo reads the video at VideoPath in the payload of the message, after checking its container
+// and codecs. Poster frames aren't extracted from the video, the one at VideoThumbnailPath is sent if any.
+func (m *Message) LoadVideo() error {
+	payload, err := os.ReadFile(m.VideoPath)
+	if err != nil {
+		return err
+	}
+
+	metadata, err := video.Validate(payload)
+	if err != nil {
+		return err
+	}
+
+	videoMessage := &protobuf.VideoMessage{
+		Payload:    payload,
+		Format:     metadata.Format,
+		DurationMs: uint64(metadata.Duration.Milliseconds()),
+		Width:      metadata.Width,
+		Height:     metadata.Height,
+		VideoCodec: metadata.VideoCodec,
+		AudioCodec: metadata.AudioCodec,
+	}
+
+	if len(m.VideoThumbnailPath) != 0 {
+		thumbnail, err := images.OpenAndAdjustImage(images.CroppedImage{ImagePath: m.VideoThumbnailPath}, false)
+		if err != nil {
+			return err
+		}
+		videoMessage.Thumbnail = thumbnail
+		videoMessage.ThumbnailFormat = images.GetProtobufImageFormat(thumbnail)
+	}
+
+	m.Payload = &protobuf.ChatMessage_Video{Video: videoMessage}
+	return nil
+}
+
+func (m *Message) LoadImage() error {
+	sanitized, err := images.OpenAndSanitizeImage(images.CroppedImage{ImagePath: m.ImagePath}, false)
+
+	if err != nil {
+		return err
+	}
+	imageMessage := m.GetImage()
+	imageMessage.Payload = sanitized.Payload
+	imageMessage.Format = images.GetProtobufImageFormat(sanitized.Payload)
+	imageMessage.Placeholder = sanitized.Placeholder
 	m.Payload = &protobuf.ChatMessage_Image{Image: imageMessage}
 
 	return nil
@@ -0,0 +1,328 @@
+package common
+
+import (
+	"fmt"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+type GitHubLinkPreview struct {
+	// Repository is owner/name
+	Repository string                            `json:"repository"`
+	Kind       protobuf.UnfurledGitHubLink_Kind  `json:"kind"`
+	Number     uint64                            `json:"number"`
+	Title      string                            `json:"title"`
+	State      protobuf.UnfurledGitHubLink_State `json:"state"`
+	Labels     []string                          `json:"labels,omitempty"`
+	Author     string                            `json:"author,omitempty"`
+}
+
+type SocialPostLinkPreview struct {
+	Platform     protobuf.UnfurledSocialPostLink_Platform `json:"platform"`
+	AuthorName   string                                   `json:"authorName"`
+	AuthorHandle string                                   `json:"authorHandle"`
+	Text         string                                   `json:"text"`
+	// PostedAt is a unix timestamp in seconds, 0 when unknown
+	PostedAt uint64 `json:"postedAt,omitempty"`
+}
+
+type TokenTransferPreview struct {
+	ContractAddress string `json:"contractAddress"`
+	Symbol          string `json:"symbol"`
+	Decimals        uint32 `json:"decimals"`
+	To              string `json:"to"`
+	// Amount is in the smallest unit of the token
+	Amount string `json:"amount"`
+}
+
+type TransactionLinkPreview struct {
+	ChainID uint64 `json:"chainId"`
+	Hash    string `json:"hash"`
+	From    string `json:"from,omitempty"`
+	To      string `json:"to,omitempty"`
+	// Value is in wei
+	Value         string                                  `json:"value"`
+	Status        protobuf.UnfurledTransactionLink_Status `json:"status"`
+	BlockNumber   uint64                                  `json:"blockNumber,omitempty"`
+	TokenTransfer *TokenTransferPreview                   `json:"tokenTransfer,omitempty"`
+}
+
+type AddressLinkPreview struct {
+	ChainID    uint64 `json:"chainId"`
+	Address    string `json:"address"`
+	IsContract bool   `json:"isContract"`
+	// Balance is the native balance, in wei
+	Balance       string `json:"balance"`
+	TokenName     string `json:"tokenName,omitempty"`
+	TokenSymbol   string `json:"tokenSymbol,omitempty"`
+	TokenDecimals uint32 `json:"tokenDecimals,omitempty"`
+}
+
+type CollectibleLinkPreview struct {
+	ChainID         uint64               `json:"chainId"`
+	ContractAddress string               `json:"contractAddress"`
+	TokenID         string               `json:"tokenId"`
+	Name            string               `json:"name"`
+	CollectionName  string               `json:"collectionName,omitempty"`
+	Description     string               `json:"description,omitempty"`
+	Image           LinkPreviewThumbnail `json:"image,omitempty"`
+}
+
+// RichLinkPreview is a structured preview of a link to a code host, a social post or an on-chain entity,
+// only one of the previews is set
+type RichLinkPreview struct {
+	URL         string                  `json:"url"`
+	GitHub      *GitHubLinkPreview      `json:"github,omitempty"`
+	SocialPost  *SocialPostLinkPreview  `json:"socialPost,omitempty"`
+	Transaction *TransactionLinkPreview `json:"transaction,omitempty"`
+	Address     *AddressLinkPreview     `json:"address,omitempty"`
+	Collectible *CollectibleLinkPreview `json:"collectible,omitempty"`
+}
+
+func (preview *RichLinkPreview) validateForProto() error {
+	if preview.URL == "" {
+		return fmt.Errorf("url can't be empty")
+	}
+
+	set := 0
+	for _, isSet := range []bool{
+		preview.GitHub != nil,
+		preview.SocialPost != nil,
+		preview.Transaction != nil,
+		preview.Address != nil,
+		preview.Collectible != nil,
+	} {
+		if isSet {
+			set++
+		}
+	}
+	if set != 1 {
+		return fmt.Errorf("exactly one of github/socialPost/transaction/address/collectible must be set, got %d", set)
+	}
+
+	switch {
+	case preview.GitHub != nil:
+		if preview.GitHub.Repository == "" || preview.GitHub.Number == 0 {
+			return fmt.Errorf("github repository or number is empty")
+		}
+	case preview.SocialPost != nil:
+		if preview.SocialPost.AuthorHandle == "" && preview.SocialPost.Text == "" {
+			return fmt.Errorf("social post author and text are empty")
+		}
+	case preview.Transaction != nil:
+		if preview.Transaction.ChainID == 0 || preview.Transaction.Hash == "" {
+			return fmt.Errorf("transaction chainId or hash is empty")
+		}
+	case preview.Address != nil:
+		if preview.Address.ChainID == 0 || preview.Address.Address == "" {
+			return fmt.Errorf("address chainId or address is empty")
+		}
+	case preview.Collectible != nil:
+		if preview.Collectible.ChainID == 0 || preview.Collectible.ContractAddress == "" || preview.Collectible.TokenID == "" {
+			return fmt.Errorf("collectible chainId, contractAddress or tokenId is empty")
+		}
+		if err := preview.Collectible.Image.validateForProto(); err != nil {
+			return fmt.Errorf("collectible image is invalid: %w", err)
+		}
+	}
+	return nil
+}
+
+func (preview *RichLinkPreview) convertToProto() (*protobuf.UnfurledRichLink, error) {
+	ul := &protobuf.UnfurledRichLink{
+		Url: preview.URL,
+	}
+
+	switch {
+	case preview.GitHub != nil:
+		ul.Payload = &protobuf.UnfurledRichLink_Github{
+			Github: &protobuf.UnfurledGitHubLink{
+				Repository: preview.GitHub.Repository,
+				Kind:       preview.GitHub.Kind,
+				Number:     preview.GitHub.Number,
+				Title:      preview.GitHub.Title,
+				State:      preview.GitHub.State,
+				Labels:     preview.GitHub.Labels,
+				Author:     preview.GitHub.Author,
+			},
+		}
+
+	case preview.SocialPost != nil:
+		ul.Payload = &protobuf.UnfurledRichLink_SocialPost{
+			SocialPost: &protobuf.UnfurledSocialPostLink{
+				Platform:     preview.SocialPost.Platform,
+				AuthorName:   preview.SocialPost.AuthorName,
+				AuthorHandle: preview.SocialPost.AuthorHandle,
+				Text:         preview.SocialPost.Text,
+				PostedAt:     preview.SocialPost.PostedAt,
+			},
+		}
+
+	case preview.Transaction != nil:
+		transaction := &protobuf.UnfurledTransactionLink{
+			ChainId:     preview.Transaction.ChainID,
+			Hash:        preview.Transaction.Hash,
+			From:        preview.Transaction.From,
+			To:          preview.Transaction.To,
+			Value:       preview.Transaction.Value,
+			Status:      preview.Transaction.Status,
+			BlockNumber: preview.Transaction.BlockNumber,
+		}
+		if transfer := preview.Transaction.TokenTransfer; transfer != nil {
+			transaction.TokenTransfer = &protobuf.UnfurledTokenTransfer{
+				ContractAddress: transfer.ContractAddress,
+				Symbol:          transfer.Symbol,
+				Decimals:        transfer.Decimals,
+				To:              transfer.To,
+				Amount:          transfer.Amount,
+			}
+		}
+		ul.Payload = &protobuf.UnfurledRichLink_Transaction{Transaction: transaction}
+
+	case preview.Address != nil:
+		ul.Payload = &protobuf.UnfurledRichLink_Address{
+			Address: &protobuf.UnfurledAddressLink{
+				ChainId:       preview.Address.ChainID,
+				Address:       preview.Address.Address,
+				IsContract:    preview.Address.IsContract,
+				Balance:       preview.Address.Balance,
+				TokenName:     preview.Address.TokenName,
+				TokenSymbol:   preview.Address.TokenSymbol,
+				TokenDecimals: preview.Address.TokenDecimals,
+			},
+		}
+
+	case preview.Collectible != nil:
+		image, err := preview.Collectible.Image.convertToProto()
+		if err != nil {
+			return nil, err
+		}
+		ul.Payload = &protobuf.UnfurledRichLink_Collectible{
+			Collectible: &protobuf.UnfurledCollectibleLink{
+				ChainId:         preview.Collectible.ChainID,
+				ContractAddress: preview.Collectible.ContractAddress,
+				TokenId:         preview.Collectible.TokenID,
+				Name:            preview.Collectible.Name,
+				CollectionName:  preview.Collectible.CollectionName,
+				Description:     preview.Collectible.Description,
+				Image:           image,
+			},
+		}
+	}
+
+	return ul, nil
+}
+
+// ConvertRichLinkPreviewsToProto expects the previews to be unfurled by the client beforehand,
+// they are validated as a safety net
+func (m *Message) ConvertRichLinkPreviewsToProto() (*protobuf.UnfurledRichLinks, error) {
+	if len(m.RichLinkPreviews) == 0 {
+		return nil, nil
+	}
+
+	unfurledLinks := make([]*protobuf.UnfurledRichLink, 0, len(m.RichLinkPreviews))
+	for _, preview := range m.RichLinkPreviews {
+		if err := preview.validateForProto(); err != nil {
+			return nil, fmt.Errorf("invalid rich link preview, url='%s': %w", preview.URL, err)
+		}
+
+		ul, err := preview.convertToProto()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert rich link preview, url='%s': %w", preview.URL, err)
+		}
+		unfurledLinks = append(unfurledLinks, ul)
+	}
+
+	return &protobuf.UnfurledRichLinks{UnfurledRichLinks: unfurledLinks}, nil
+}
+
+func (m *Message) ConvertFromProtoToRichLinkPreviews(makeImageMediaServerURL func(msgID string, previewURL string) string) []RichLinkPreview {
+	links := m.GetUnfurledRichLinks().GetUnfurledRichLinks()
+	if links == nil {
+		return nil
+	}
+
+	previews := make([]RichLinkPreview, 0, len(links))
+	for _, link := range links {
+		lp := RichLinkPreview{
+			URL: link.Url,
+		}
+
+		if g := link.GetGithub(); g != nil {
+			lp.GitHub = &GitHubLinkPreview{
+				Repository: g.Repository,
+				Kind:       g.Kind,
+				Number:     g.Number,
+				Title:      g.Title,
+				State:      g.State,
+				Labels:     g.Labels,
+				Author:     g.Author,
+			}
+		}
+
+		if p := link.GetSocialPost(); p != nil {
+			lp.SocialPost = &SocialPostLinkPreview{
+				Platform:     p.Platform,
+				AuthorName:   p.AuthorName,
+				AuthorHandle: p.AuthorHandle,
+				Text:         p.Text,
+				PostedAt:     p.PostedAt,
+			}
+		}
+
+		if t := link.GetTransaction(); t != nil {
+			lp.Transaction = &TransactionLinkPreview{
+				ChainID:     t.ChainId,
+				Hash:        t.Hash,
+				From:        t.From,
+				To:          t.To,
+				Value:       t.Value,
+				Status:      t.Status,
+				BlockNumber: t.BlockNumber,
+			}
+			if transfer := t.GetTokenTransfer(); transfer != nil {
+				lp.Transaction.TokenTransfer = &TokenTransferPreview{
+					ContractAddress: transfer.ContractAddress,
+					Symbol:          transfer.Symbol,
+					Decimals:        transfer.Decimals,
+					To:              transfer.To,
+					Amount:          transfer.Amount,
+				}
+			}
+		}
+
+		if a := link.GetAddress(); a != nil {
+			lp.Address = &AddressLinkPreview{
+				ChainID:       a.ChainId,
+				Address:       a.Address,
+				IsContract:    a.IsContract,
+				Balance:       a.Balance,
+				TokenName:     a.TokenName,
+				TokenSymbol:   a.TokenSymbol,
+				TokenDecimals: a.TokenDecimals,
+			}
+		}
+
+		if c := link.GetCollectible(); c != nil {
+			lp.Collectible = &CollectibleLinkPreview{
+				ChainID:         c.ChainId,
+				ContractAddress: c.ContractAddress,
+				TokenID:         c.TokenId,
+				Name:            c.Name,
+				CollectionName:  c.CollectionName,
+				Description:     c.Description,
+			}
+			if image := c.GetImage(); image != nil {
+				lp.Collectible.Image.Width = int(image.Width)
+				lp.Collectible.Image.Height = int(image.Height)
+				if len(image.Payload) > 0 {
+					lp.Collectible.Image.URL = makeImageMediaServerURL(m.ID, link.Url)
+				}
+			}
+		}
+
+		previews = append(previews, lp)
+	}
+
+	return previews
+}
@@ -24,6 +24,7 @@ import (
 	"github.com/status-im/status-go/protocol/protobuf"
 	"github.com/status-im/status-go/protocol/transport"
 	v1protocol "github.com/status-im/status-go/protocol/v1"
+	"github.com/status-im/status-go/tracing"
 )
 
 // Whisper message properties.
@@ -160,7 +161,10 @@ func (s *MessageSender) SendPrivate(
 	ctx context.Context,
 	recipient *ecdsa.PublicKey,
 	rawMessage *RawMessage,
-) ([]byte, error) {
+) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "MessageSender.SendPrivate", tracing.String("messageType", rawMessage.MessageType.String()))
+	defer func() { span.EndWithError(err) }()
+
 	s.logger.Debug(
 		"sending a private message",
 		zap.String("public-key", types.EncodeHex(crypto.FromECDSAPub(recipient))),
@@ -186,7 +190,10 @@ func (s *MessageSender) SendPrivate(
 func (s *MessageSender) SendCommunityMessage(
 	ctx context.Context,
 	rawMessage *RawMessage,
-) ([]byte, error) {
+) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "MessageSender.SendCommunityMessage", tracing.String("messageType", rawMessage.MessageType.String()))
+	defer func() { span.EndWithError(err) }()
+
 	s.logger.Debug(
 		"sending a community message",
 		zap.String("communityId", types.EncodeHex(rawMessage.CommunityID)),
@@ -238,7 +245,13 @@ func (s *MessageSender) SendGroup(
 	ctx context.Context,
 	recipients []*ecdsa.PublicKey,
 	rawMessage RawMessage,
-) ([]byte, error) {
+) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "MessageSender.SendGroup",
+		tracing.String("messageType", rawMessage.MessageType.String()),
+		tracing.Int("recipients", len(recipients)),
+	)
+	defer func() { span.EndWithError(err) }()
+
 	s.logger.Debug(
 		"sending a private group message",
 		zap.String("site", "SendGroup"),
@@ -392,7 +405,9 @@ func (s *MessageSender) sendCommunity(
 
 	// If it's a chat message, we send it on the community chat topic
 	if ShouldCommunityMessageBeEncrypted(rawMessage.MessageType) {
+		_, encryptSpan := tracing.Start(ctx, "encryption.BuildHashRatchetMessage")
 		messageSpec, err := s.protocol.BuildHashRatchetMessage(rawMessage.HashRatchetGroupID, wrappedMessage)
+		encryptSpan.EndWithError(err)
 		if err != nil {
 			return nil, err
 		}
@@ -432,7 +447,7 @@ func (s *MessageSender) sendCommunity(
 		zap.String("messageType", "community"),
 		zap.Any("contentType", rawMessage.MessageType),
 		zap.Strings("hashes", types.EncodeHexes(hashes)))
-	s.transport.Track(messageID, hashes, newMessages)
+	s.transport.Track(ctx, messageID, hashes, newMessages)
 
 	return messageID, nil
 }
@@ -527,10 +542,12 @@ func (s *MessageSender) sendPrivate(
 			zap.String("messageType", "private"),
 			zap.Any("contentType", rawMessage.MessageType),
 			zap.Strings("hashes", types.EncodeHexes(hashes)))
-		s.transport.Track(messageID, hashes, newMessages)
+		s.transport.Track(ctx, messageID, hashes, newMessages)
 
 	} else {
+		_, encryptSpan := tracing.Start(ctx, "encryption.BuildEncryptedMessage")
 		messageSpec, err := s.protocol.BuildEncryptedMessage(rawMessage.Sender, recipient, wrappedMessage)
+		encryptSpan.EndWithError(err)
 		if err != nil {
 			return nil, errors.Wrap(err, "failed to encrypt message")
 		}
@@ -547,7 +564,7 @@ func (s *MessageSender) sendPrivate(
 			zap.Any("contentType", rawMessage.MessageType),
 			zap.String("messageType", "private"),
 			zap.Strings("hashes", types.EncodeHexes(hashes)))
-		s.transport.Track(messageID, hashes, newMessages)
+		s.transport.Track(ctx, messageID, hashes, newMessages)
 	}
 
 	return messageID, nil
@@ -578,7 +595,7 @@ func (s *MessageSender) SendPairInstallation(
 		return nil, errors.Wrap(err, "failed to send a message spec")
 	}
 
-	s.transport.Track(messageID, hashes, newMessages)
+	s.transport.Track(ctx, messageID, hashes, newMessages)
 
 	return messageID, nil
 }
@@ -698,14 +715,16 @@ func (s *MessageSender) SendPublic(
 	ctx context.Context,
 	chatName string,
 	rawMessage RawMessage,
-) ([]byte, error) {
+) (_ []byte, err error) {
+	ctx, span := tracing.Start(ctx, "MessageSender.SendPublic", tracing.String("messageType", rawMessage.MessageType.String()))
+	defer func() { span.EndWithError(err) }()
+
 	// Set sender
 	if rawMessage.Sender == nil {
 		rawMessage.Sender = s.identity
 	}
 
 	var wrappedMessage []byte
-	var err error
 	if rawMessage.SkipApplicationWrap {
 		wrappedMessage = rawMessage.Payload
 	} else {
@@ -808,7 +827,7 @@ func (s *MessageSender) SendPublic(
 		zap.Any("contentType", rawMessage.MessageType),
 		zap.String("messageType", "public"),
 		zap.Strings("hashes", types.EncodeHexes(hashes)))
-	s.transport.Track(messageID, hashes, newMessages)
+	s.transport.Track(ctx, messageID, hashes, newMessages)
 
 	return messageID, nil
 }
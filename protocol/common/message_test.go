@@ -67,6 +67,32 @@ func TestGetAudioMessageMIME(t *testing.T) {
 	mime, err = getAudioMessageMIME(amr)
 	require.NoError(t, err)
 	require.Equal(t, "amr", mime)
+
+	m4a := &protobuf.AudioMessage{Type: protobuf.AudioMessage_M4A}
+	mime, err = getAudioMessageMIME(m4a)
+	require.NoError(t, err)
+	require.Equal(t, "mp4", mime)
+}
+
+func TestSetAudioMetadata(t *testing.T) {
+	payload, err := os.ReadFile("../../_assets/tests/test.aac")
+	require.NoError(t, err)
+
+	message := NewMessage()
+	message.ContentType = protobuf.ChatMessage_AUDIO
+	// The duration claimed by the sender is replaced
+	message.Payload = &protobuf.ChatMessage_Audio{Audio: &protobuf.AudioMessage{
+		Payload:    payload,
+		DurationMs: 60000,
+	}}
+
+	require.NoError(t, message.SetAudioMetadata())
+	require.Equal(t, protobuf.AudioMessage_AAC, message.GetAudio().Type)
+	require.Equal(t, uint64(116), message.GetAudio().DurationMs)
+	require.Len(t, message.GetAudio().Waveform, 5)
+
+	message.GetAudio().Payload = []byte("not an audio")
+	require.Error(t, message.SetAudioMetadata())
 }
 
 func TestPrepareContentMentions(t *testing.T) {
@@ -639,6 +665,156 @@ func assertMarshalAndUnmarshalJSON[T any](t *testing.T, obj *T, msgAndArgs ...an
 	require.Equal(t, obj, &unmarshalled, msgAndArgs...)
 }
 
+func TestConvertRichLinkPreviewsToProto(t *testing.T) {
+	msg := Message{
+		ChatMessage: &protobuf.ChatMessage{},
+		RichLinkPreviews: []RichLinkPreview{
+			{
+				URL: "https://github.com/status-im/status-go/pull/1",
+				GitHub: &GitHubLinkPreview{
+					Repository: "status-im/status-go",
+					Kind:       protobuf.UnfurledGitHubLink_PULL_REQUEST,
+					Number:     1,
+					Title:      "Title_4",
+					State:      protobuf.UnfurledGitHubLink_MERGED,
+					Labels:     []string{"Label_5"},
+					Author:     "Author_6",
+				},
+			},
+			{
+				URL: "https://etherscan.io/tx/0x01",
+				Transaction: &TransactionLinkPreview{
+					ChainID: 1,
+					Hash:    "0x01",
+					Value:   "0",
+					Status:  protobuf.UnfurledTransactionLink_SUCCESS,
+					TokenTransfer: &TokenTransferPreview{
+						ContractAddress: "0x02",
+						Symbol:          "SNT",
+						Decimals:        18,
+						To:              "0x03",
+						Amount:          "1000",
+					},
+				},
+			},
+		},
+	}
+
+	unfurledLinks, err := msg.ConvertRichLinkPreviewsToProto()
+	require.NoError(t, err)
+	require.Len(t, unfurledLinks.UnfurledRichLinks, 2)
+
+	github := unfurledLinks.UnfurledRichLinks[0].GetGithub()
+	require.NotNil(t, github)
+	require.Equal(t, msg.RichLinkPreviews[0].URL, unfurledLinks.UnfurledRichLinks[0].Url)
+	require.Equal(t, "status-im/status-go", github.Repository)
+	require.Equal(t, protobuf.UnfurledGitHubLink_PULL_REQUEST, github.Kind)
+	require.Equal(t, protobuf.UnfurledGitHubLink_MERGED, github.State)
+	require.Equal(t, []string{"Label_5"}, github.Labels)
+
+	transaction := unfurledLinks.UnfurledRichLinks[1].GetTransaction()
+	require.NotNil(t, transaction)
+	require.Equal(t, uint64(1), transaction.ChainId)
+	require.Equal(t, "SNT", transaction.TokenTransfer.Symbol)
+	require.Equal(t, "1000", transaction.TokenTransfer.Amount)
+
+	// Exactly one preview must be set
+	msg.RichLinkPreviews[0].SocialPost = &SocialPostLinkPreview{Text: "Text_1"}
+	_, err = msg.ConvertRichLinkPreviewsToProto()
+	require.Error(t, err)
+
+	msg.RichLinkPreviews[0].GitHub = nil
+	msg.RichLinkPreviews[0].SocialPost = nil
+	_, err = msg.ConvertRichLinkPreviewsToProto()
+	require.Error(t, err)
+
+	// Collectible images must be data URIs
+	msg.RichLinkPreviews = []RichLinkPreview{{
+		URL: "https://opensea.io/assets/ethereum/0x02/1",
+		Collectible: &CollectibleLinkPreview{
+			ChainID:         1,
+			ContractAddress: "0x02",
+			TokenID:         "1",
+			Image:           LinkPreviewThumbnail{Width: 1, Height: 1, DataURI: "invalid"},
+		},
+	}}
+	_, err = msg.ConvertRichLinkPreviewsToProto()
+	require.Error(t, err)
+
+	msg.RichLinkPreviews[0].Collectible.Image.DataURI = expectedJPEG
+	unfurledLinks, err = msg.ConvertRichLinkPreviewsToProto()
+	require.NoError(t, err)
+	require.NotEmpty(t, unfurledLinks.UnfurledRichLinks[0].GetCollectible().Image.Payload)
+}
+
+func TestConvertFromProtoToRichLinkPreviews(t *testing.T) {
+	thumbnailPayload, err := base64.StdEncoding.DecodeString("iVBORw0KGgoAAAANSUg=")
+	require.NoError(t, err)
+
+	msg := Message{
+		ID: "42",
+		ChatMessage: &protobuf.ChatMessage{
+			UnfurledRichLinks: &protobuf.UnfurledRichLinks{
+				UnfurledRichLinks: []*protobuf.UnfurledRichLink{
+					{
+						Url: "https://mastodon.social/@user/1",
+						Payload: &protobuf.UnfurledRichLink_SocialPost{
+							SocialPost: &protobuf.UnfurledSocialPostLink{
+								Platform:     protobuf.UnfurledSocialPostLink_MASTODON,
+								AuthorName:   "AuthorName_1",
+								AuthorHandle: "@user@mastodon.social",
+								Text:         "Text_2",
+								PostedAt:     3,
+							},
+						},
+					},
+					{
+						Url: "https://opensea.io/assets/ethereum/0x02/1",
+						Payload: &protobuf.UnfurledRichLink_Collectible{
+							Collectible: &protobuf.UnfurledCollectibleLink{
+								ChainId:         1,
+								ContractAddress: "0x02",
+								TokenId:         "1",
+								Name:            "Name_4",
+								CollectionName:  "CollectionName_5",
+								Image: &protobuf.UnfurledLinkThumbnail{
+									Width:   10,
+									Height:  20,
+									Payload: thumbnailPayload,
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+
+	urlMaker := func(msgID string, linkURL string) string {
+		return "https://localhost:6666/" + msgID + "-" + linkURL
+	}
+
+	previews := msg.ConvertFromProtoToRichLinkPreviews(urlMaker)
+	require.Len(t, previews, 2)
+
+	post := previews[0].SocialPost
+	require.NotNil(t, post)
+	require.Nil(t, previews[0].Collectible)
+	require.Equal(t, protobuf.UnfurledSocialPostLink_MASTODON, post.Platform)
+	require.Equal(t, "@user@mastodon.social", post.AuthorHandle)
+	require.Equal(t, "Text_2", post.Text)
+	require.Equal(t, uint64(3), post.PostedAt)
+
+	collectible := previews[1].Collectible
+	require.NotNil(t, collectible)
+	require.Equal(t, "Name_4", collectible.Name)
+	require.Equal(t, "CollectionName_5", collectible.CollectionName)
+	require.Equal(t, 10, collectible.Image.Width)
+	require.Equal(t, 20, collectible.Image.Height)
+	require.Equal(t, urlMaker(msg.ID, previews[1].URL), collectible.Image.URL)
+	require.Empty(t, collectible.Image.DataURI)
+}
+
 func TestMarshalMessageJSON(t *testing.T) {
 	msg := NewMessage()
 	msg.ID = "1"
@@ -669,3 +845,18 @@ func TestMarshalMessageJSON(t *testing.T) {
 
 	assertMarshalAndUnmarshalJSON(t, msg, "message ID='%s'", msg.ID)
 }
+
+func TestMarshalMessageJSONInvalidPlaceholder(t *testing.T) {
+	msg := NewMessage()
+	msg.ID = "1"
+	msg.ContentType = protobuf.ChatMessage_IMAGE
+	msg.Payload = &protobuf.ChatMessage_Image{Image: &protobuf.ImageMessage{
+		Format:      protobuf.ImageFormat_PNG,
+		Placeholder: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+	}}
+
+	// the message is still marshaled, without its placeholder
+	data, err := json.Marshal(msg)
+	require.NoError(t, err)
+	require.NotContains(t, string(data), "imagePlaceholder")
+}
@@ -123,6 +123,7 @@ type CommunityChat struct {
 	TokenGated              bool                                 `json:"tokenGated"`
 	HideIfPermissionsNotMet bool                                 `json:"hideIfPermissionsNotMet"`
 	MissingEncryptionKey    bool                                 `json:"missingEncryptionKey"`
+	SlowModeInterval        uint32                               `json:"slowModeInterval"`
 }
 
 type CommunityCategory struct {
@@ -146,6 +147,15 @@ const (
 	CommunityMemberBanWithAllMessagesDelete
 )
 
+// animatedPayload returns the animated variant of a community image. Animated variants are GIFs,
+// as for contacts anything else is dropped and the image displayed still.
+func animatedPayload(image *protobuf.IdentityImage) []byte {
+	if images.GetType(image.AnimatedPayload) != images.GIF {
+		return nil
+	}
+	return image.AnimatedPayload
+}
+
 func (o *Community) MarshalPublicAPIJSON() ([]byte, error) {
 	if o.config.MemberIdentity == nil {
 		return nil, errors.New("member identity not set")
@@ -223,6 +233,7 @@ func (o *Community) MarshalPublicAPIJSON() ([]byte, error) {
 				CategoryID:              c.CategoryId,
 				HideIfPermissionsNotMet: c.HideIfPermissionsNotMet,
 				Position:                int(c.Position),
+				SlowModeInterval:        c.SlowModeInterval,
 			}
 			communityItem.Chats[id] = chat
 		}
@@ -248,7 +259,7 @@ func (o *Community) MarshalPublicAPIJSON() ([]byte, error) {
 				if communityItem.Images == nil {
 					communityItem.Images = make(map[string]images.IdentityImage)
 				}
-				communityItem.Images[t] = images.IdentityImage{Name: t, Payload: i.Payload}
+				communityItem.Images[t] = images.IdentityImage{Name: t, Payload: i.Payload, AnimatedPayload: animatedPayload(i)}
 
 			}
 		}
@@ -270,7 +281,8 @@ func (o *Community) MarshalJSON() ([]byte, error) {
 	}
 
 	type Image struct {
-		Uri string `json:"uri"`
+		Uri         string `json:"uri"`
+		AnimatedUri string `json:"animatedUri,omitempty"`
 	}
 	communityItem := struct {
 		ID                          types.HexBytes                       `json:"id"`
@@ -303,6 +315,7 @@ func (o *Community) MarshalJSON() ([]byte, error) {
 		CommunityAdminSettings      CommunityAdminSettings               `json:"adminSettings"`
 		Encrypted                   bool                                 `json:"encrypted"`
 		PendingAndBannedMembers     map[string]CommunityMemberState      `json:"pendingAndBannedMembers"`
+		TimedOutMembers             map[string]uint64                    `json:"timedOutMembers"`
 		TokenPermissions            map[string]*CommunityTokenPermission `json:"tokenPermissions"`
 		CommunityTokensMetadata     []*protobuf.CommunityTokenMetadata   `json:"communityTokensMetadata"`
 		ActiveMembersCount          uint64                               `json:"activeMembersCount"`
@@ -376,6 +389,7 @@ func (o *Community) MarshalJSON() ([]byte, error) {
 				HideIfPermissionsNotMet: c.HideIfPermissionsNotMet,
 				Position:                int(c.Position),
 				MissingEncryptionKey:    o.HasMissingEncryptionKey(id),
+				SlowModeInterval:        c.SlowModeInterval,
 			}
 
 			if chat.TokenGated {
@@ -385,6 +399,7 @@ func (o *Community) MarshalJSON() ([]byte, error) {
 		}
 		communityItem.TokenPermissions = o.tokenPermissions()
 		communityItem.PendingAndBannedMembers = o.PendingAndBannedMembers()
+		communityItem.TimedOutMembers = o.timedOutMembers(o.timesource.GetCurrentTime())
 		communityItem.Members = o.config.CommunityDescription.Members
 		communityItem.Permissions = o.config.CommunityDescription.Permissions
 		communityItem.IntroMessage = o.config.CommunityDescription.IntroMessage
@@ -409,11 +424,15 @@ func (o *Community) MarshalJSON() ([]byte, error) {
 			communityItem.Description = o.config.CommunityDescription.Identity.Description
 
 			if !utils.IsNil(o.mediaServer) {
-				for t := range o.config.CommunityDescription.Identity.Images {
+				for t, i := range o.config.CommunityDescription.Identity.Images {
 					if communityItem.Images == nil {
 						communityItem.Images = make(map[string]Image)
 					}
-					communityItem.Images[t] = Image{Uri: o.mediaServer.MakeCommunityImageURL(o.IDString(), t)}
+					image := Image{Uri: o.mediaServer.MakeCommunityImageURL(o.IDString(), t)}
+					if len(animatedPayload(i)) > 0 {
+						image.AnimatedUri = o.mediaServer.MakeAnimatedCommunityImageURL(o.IDString(), t)
+					}
+					communityItem.Images[t] = image
 				}
 			}
 		}
@@ -1030,6 +1049,164 @@ func (o *Community) BanUserFromCommunity(pk *ecdsa.PublicKey, communityBanInfo *
 	return o.config.CommunityDescription, nil
 }
 
+// TimeoutMember prevents the member from posting in the community until expiresAt (unix ms).
+// Timeouts are only managed by the control node.
+func (o *Community) TimeoutMember(pk *ecdsa.PublicKey, expiresAt uint64) (*protobuf.CommunityDescription, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.IsControlNode() {
+		return nil, ErrNotAuthorized
+	}
+
+	if o.IsPrivilegedMember(pk) {
+		return nil, ErrCannotTimeoutOwnerOrAdmin
+	}
+
+	if !o.hasMember(pk) {
+		return nil, ErrMemberNotFound
+	}
+
+	now := o.timesource.GetCurrentTime()
+	if expiresAt <= now {
+		return nil, ErrInvalidMemberTimeout
+	}
+
+	o.removeExpiredMemberTimeouts(now)
+
+	if o.config.CommunityDescription.TimedOutMembers == nil {
+		o.config.CommunityDescription.TimedOutMembers = make(map[string]*protobuf.CommunityMemberTimeout)
+	}
+
+	o.config.CommunityDescription.TimedOutMembers[common.PubkeyToHex(pk)] = &protobuf.CommunityMemberTimeout{
+		StartedAt: now,
+		ExpiresAt: expiresAt,
+	}
+	o.increaseClock()
+
+	return o.config.CommunityDescription, nil
+}
+
+func (o *Community) RemoveMemberTimeout(pk *ecdsa.PublicKey) (*protobuf.CommunityDescription, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.IsControlNode() {
+		return nil, ErrNotAuthorized
+	}
+
+	key := common.PubkeyToHex(pk)
+	if _, exists := o.config.CommunityDescription.TimedOutMembers[key]; !exists {
+		return nil, ErrTimedOutMemberNotFound
+	}
+
+	delete(o.config.CommunityDescription.TimedOutMembers, key)
+	o.removeExpiredMemberTimeouts(o.timesource.GetCurrentTime())
+	o.increaseClock()
+
+	return o.config.CommunityDescription, nil
+}
+
+// IsTimedOutAt returns whether the member was not allowed to post at the given unix ms timestamp
+func (o *Community) IsTimedOutAt(pk *ecdsa.PublicKey, timestamp uint64) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	timeout, exists := o.config.CommunityDescription.TimedOutMembers[common.PubkeyToHex(pk)]
+	if !exists {
+		return false
+	}
+
+	return timestamp >= timeout.StartedAt && timestamp < timeout.ExpiresAt
+}
+
+func (o *Community) TimedOutMembers() map[string]uint64 {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.timedOutMembers(o.timesource.GetCurrentTime())
+}
+
+func (o *Community) timedOutMembers(now uint64) map[string]uint64 {
+	result := make(map[string]uint64)
+	for memberID, timeout := range o.config.CommunityDescription.TimedOutMembers {
+		if timeout.ExpiresAt > now {
+			result[memberID] = timeout.ExpiresAt
+		}
+	}
+	return result
+}
+
+func (o *Community) removeExpiredMemberTimeouts(now uint64) {
+	for memberID, timeout := range o.config.CommunityDescription.TimedOutMembers {
+		if timeout.ExpiresAt <= now {
+			delete(o.config.CommunityDescription.TimedOutMembers, memberID)
+		}
+	}
+}
+
+// SetChatSlowModeInterval sets the minimum interval in seconds between two messages
+// of the same member in the channel. Zero disables slow mode.
+func (o *Community) SetChatSlowModeInterval(chatID string, interval uint32) (*CommunityChanges, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.IsControlNode() {
+		return nil, ErrNotAuthorized
+	}
+
+	chat, exists := o.config.CommunityDescription.Chats[chatID]
+	if !exists {
+		return nil, ErrChatNotFound
+	}
+
+	chat.SlowModeInterval = interval
+	o.increaseClock()
+
+	changes := o.emptyCommunityChanges()
+	changes.ChatsModified[chatID] = &CommunityChatChanges{
+		ChatModified: chat,
+	}
+
+	return changes, nil
+}
+
+func (o *Community) ChatSlowModeInterval(chatID string) uint32 {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	chat, exists := o.config.CommunityDescription.Chats[chatID]
+	if !exists {
+		return 0
+	}
+
+	return chat.SlowModeInterval
+}
+
+// SetMediaBlocklist replaces the perceptual hashes of the media the community doesn't allow.
+// Members match the media they receive in the community against them. Only the control node
+// can set them, admins can't as the blocklist isn't carried by community events.
+func (o *Community) SetMediaBlocklist(hashes []*protobuf.CommunityMediaHash) (*protobuf.CommunityDescription, error) {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if !o.IsControlNode() {
+		return nil, ErrNotAuthorized
+	}
+
+	o.config.CommunityDescription.MediaBlocklist = hashes
+	o.increaseClock()
+
+	return o.config.CommunityDescription, nil
+}
+
+func (o *Community) MediaBlocklist() []*protobuf.CommunityMediaHash {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	return o.config.CommunityDescription.MediaBlocklist
+}
+
 func (o *Community) setRoleToMember(pk *ecdsa.PublicKey, role protobuf.CommunityMember_Roles, setter func(member *protobuf.CommunityMember, role protobuf.CommunityMember_Roles) bool) (*protobuf.CommunityDescription, error) {
 	updated := false
 
@@ -1924,6 +2101,41 @@ func (o *Community) ChannelEncrypted(channelID string) bool {
 	return o.channelEncrypted(channelID)
 }
 
+// ChannelViewableFromChannel returns true if every member of the target channel can view the source channel,
+// that is if the source channel isn't token gated, or if each permission granting access to the target
+// channel grants access to the source channel too
+func (o *Community) ChannelViewableFromChannel(sourceChannelID string, targetChannelID string) bool {
+	o.mutex.Lock()
+	defer o.mutex.Unlock()
+
+	if sourceChannelID == targetChannelID {
+		return true
+	}
+
+	sourceChatID := o.ChatID(sourceChannelID)
+	targetChatID := o.ChatID(targetChannelID)
+	permissions := o.config.CommunityDescription.TokenPermissions
+
+	if !channelEncrypted(sourceChatID, permissions) {
+		return true
+	}
+
+	if !channelEncrypted(targetChatID, permissions) {
+		return false
+	}
+
+	for _, p := range permissions {
+		if !includes(p.ChatIds, targetChatID) {
+			continue
+		}
+		if !includes(p.ChatIds, sourceChatID) {
+			return false
+		}
+	}
+
+	return true
+}
+
 func (o *Community) HasMissingEncryptionKey(channelID string) bool {
 	o.mutex.Lock()
 	defer o.mutex.Unlock()
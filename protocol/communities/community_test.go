@@ -303,6 +303,67 @@ func (s *CommunitySuite) TestRemoveUserFormOrg() {
 	s.Require().False(ok)
 }
 
+func (s *CommunitySuite) TestTimeoutMember() {
+	org := s.buildCommunity(&s.identity.PublicKey)
+	org.config.PrivateKey = nil
+	org.config.ID = nil
+	now := org.timesource.GetCurrentTime()
+
+	// Not the control node
+	_, err := org.TimeoutMember(&s.member1.PublicKey, now+60000)
+	s.Require().Equal(ErrNotAuthorized, err)
+
+	org.config.PrivateKey = s.identity
+	org.config.ID = &s.identity.PublicKey
+
+	_, err = org.TimeoutMember(&s.member1.PublicKey, now)
+	s.Require().Equal(ErrInvalidMemberTimeout, err)
+
+	_, err = org.TimeoutMember(&s.member3.PublicKey, now+60000)
+	s.Require().Equal(ErrMemberNotFound, err)
+
+	description, err := org.TimeoutMember(&s.member1.PublicKey, now+60000)
+	s.Require().NoError(err)
+	s.Require().Contains(description.TimedOutMembers, s.member1Key)
+
+	s.Require().True(org.IsTimedOutAt(&s.member1.PublicKey, now))
+	s.Require().False(org.IsTimedOutAt(&s.member1.PublicKey, now+60000))
+	s.Require().False(org.IsTimedOutAt(&s.member2.PublicKey, now))
+	s.Require().Equal(map[string]uint64{s.member1Key: now + 60000}, org.TimedOutMembers())
+
+	_, err = org.RemoveMemberTimeout(&s.member1.PublicKey)
+	s.Require().NoError(err)
+	s.Require().False(org.IsTimedOutAt(&s.member1.PublicKey, now))
+
+	_, err = org.RemoveMemberTimeout(&s.member1.PublicKey)
+	s.Require().Equal(ErrTimedOutMemberNotFound, err)
+}
+
+func (s *CommunitySuite) TestSetChatSlowModeInterval() {
+	org := s.buildCommunity(&s.identity.PublicKey)
+	org.config.PrivateKey = nil
+	org.config.ID = nil
+
+	// Not the control node
+	_, err := org.SetChatSlowModeInterval(testChatID1, 30)
+	s.Require().Equal(ErrNotAuthorized, err)
+
+	org.config.PrivateKey = s.identity
+	org.config.ID = &s.identity.PublicKey
+
+	_, err = org.SetChatSlowModeInterval("unknown-chat", 30)
+	s.Require().Equal(ErrChatNotFound, err)
+
+	changes, err := org.SetChatSlowModeInterval(testChatID1, 30)
+	s.Require().NoError(err)
+	s.Require().Contains(changes.ChatsModified, testChatID1)
+	s.Require().Equal(uint32(30), org.ChatSlowModeInterval(testChatID1))
+
+	_, err = org.SetChatSlowModeInterval(testChatID1, 0)
+	s.Require().NoError(err)
+	s.Require().Equal(uint32(0), org.ChatSlowModeInterval(testChatID1))
+}
+
 func (s *CommunitySuite) TestRemoveOurselvesFormOrg() {
 	org := s.buildCommunity(&s.identity.PublicKey)
 
@@ -809,6 +870,43 @@ func (s *CommunitySuite) TestChannelEncrypted() {
 	s.Require().False(org.channelEncrypted(someChannelID))
 }
 
+func (s *CommunitySuite) TestChannelViewableFromChannel() {
+	org := s.buildCommunity(&s.identity.PublicKey)
+	open := "open-channel-id"
+	gated := "gated-channel-id"
+	sameGate := "same-gate-channel-id"
+	otherGate := "other-gate-channel-id"
+
+	s.Require().True(org.ChannelViewableFromChannel(open, gated))
+	s.Require().True(org.ChannelViewableFromChannel(gated, gated))
+
+	_, err := org.UpsertTokenPermission(&protobuf.CommunityTokenPermission{
+		Id:            "A",
+		Type:          protobuf.CommunityTokenPermission_CAN_VIEW_CHANNEL,
+		TokenCriteria: []*protobuf.TokenCriteria{&protobuf.TokenCriteria{}},
+		ChatIds:       []string{org.ChatID(gated), org.ChatID(sameGate)},
+	})
+	s.Require().NoError(err)
+
+	_, err = org.UpsertTokenPermission(&protobuf.CommunityTokenPermission{
+		Id:            "B",
+		Type:          protobuf.CommunityTokenPermission_CAN_VIEW_AND_POST_CHANNEL,
+		TokenCriteria: []*protobuf.TokenCriteria{&protobuf.TokenCriteria{}},
+		ChatIds:       []string{org.ChatID(otherGate)},
+	})
+	s.Require().NoError(err)
+
+	// Every member can view a channel that isn't gated
+	s.Require().True(org.ChannelViewableFromChannel(open, gated))
+
+	// Members of an open channel or of a channel with other requirements might not hold the tokens
+	s.Require().False(org.ChannelViewableFromChannel(gated, open))
+	s.Require().False(org.ChannelViewableFromChannel(gated, otherGate))
+
+	// Both channels are gated by the same permission
+	s.Require().True(org.ChannelViewableFromChannel(gated, sameGate))
+}
+
 func (s *CommunitySuite) emptyCommunityDescription() *protobuf.CommunityDescription {
 	return &protobuf.CommunityDescription{
 		Permissions: &protobuf.CommunityPermissions{},
@@ -899,6 +997,18 @@ func (s *CommunitySuite) config() Config {
 	return config
 }
 
+func (s *CommunitySuite) TestMarshalPublicAPIJSONInvalidAnimatedImages() {
+	org := s.buildCommunity(&s.identity.PublicKey)
+	for _, image := range org.config.CommunityDescription.Identity.Images {
+		image.AnimatedPayload = []byte("not a gif")
+	}
+
+	// animated variants that aren't GIFs are dropped, the community is still marshaled
+	data, err := org.MarshalPublicAPIJSON()
+	s.Require().NoError(err)
+	s.Require().NotContains(string(data), "animatedUri")
+}
+
 func (s *CommunitySuite) buildCommunityDescription() *protobuf.CommunityDescription {
 	config := s.configOnRequestOrgOnRequestChat()
 	desc := config.CommunityDescription
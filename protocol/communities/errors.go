@@ -43,6 +43,13 @@ var ErrMemberWalletNotFound = errors.New("member wallet not found")
 var ErrNotEnoughPermissions = errors.New("not enough permissions for this community")
 var ErrCannotRemoveOwnerOrAdmin = errors.New("not allowed to remove admin or owner")
 var ErrCannotBanOwnerOrAdmin = errors.New("not allowed to ban admin or owner")
+var ErrCannotTimeoutOwnerOrAdmin = errors.New("not allowed to timeout admin or owner")
+var ErrInvalidMemberTimeout = errors.New("member timeout must expire in the future")
+var ErrTimedOutMemberNotFound = errors.New("timed out member not found")
+var ErrInvalidCommunityMediaBlocklist = errors.New("invalid community media blocklist")
+var ErrOldMessageReport = errors.New("old message report")
+var ErrMessageReportNotFound = errors.New("message report not found")
+var ErrInvalidMessageReport = errors.New("invalid message report")
 var ErrInvalidManageTokensPermission = errors.New("no privileges to manage tokens")
 var ErrRevealedAccountsAbsent = errors.New("revealed accounts is absent")
 var ErrNoRevealedAccountsSignature = errors.New("revealed accounts without the signature")
@@ -1574,6 +1574,10 @@ func (m *Manager) DeleteCommunity(id types.HexBytes) error {
 	if err != nil {
 		return err
 	}
+	err = m.persistence.DeleteMessageReports(id)
+	if err != nil {
+		return err
+	}
 	return m.persistence.DeleteCommunitySettings(id)
 }
 
@@ -1872,9 +1876,10 @@ func (m *Manager) EditChat(communityID types.HexBytes, chatID string, chat *prot
 		return nil, nil, err
 	}
 
-	// We can't edit permissions and members with an Edit, so we set to what we had, otherwise they will be lost
+	// We can't edit permissions, members and slow mode with an Edit, so we set to what we had, otherwise they will be lost
 	chat.Permissions = oldChat.Permissions
 	chat.Members = oldChat.Members
+	chat.SlowModeInterval = oldChat.SlowModeInterval
 
 	changes, err := community.EditChat(chatID, chat)
 	if err != nil {
@@ -3733,6 +3738,11 @@ func (m *Manager) LeaveCommunity(id types.HexBytes) (*Community, error) {
 		return nil, err
 	}
 
+	// reports are only kept by the members moderating the community
+	if err = m.persistence.DeleteMessageReports(id); err != nil {
+		return nil, err
+	}
+
 	return community, nil
 }
 
@@ -3756,6 +3766,11 @@ func (m *Manager) KickedOutOfCommunity(id types.HexBytes, spectateMode bool) (*C
 		return nil, err
 	}
 
+	// reports are only kept by the members moderating the community
+	if err = m.persistence.DeleteMessageReports(id); err != nil {
+		return nil, err
+	}
+
 	return community, nil
 }
 
@@ -3929,6 +3944,108 @@ func (m *Manager) BanUserFromCommunity(request *requests.BanUserFromCommunity) (
 	return community, nil
 }
 
+func (m *Manager) TimeoutUserFromCommunity(request *requests.TimeoutUserFromCommunity) (*Community, error) {
+	m.communityLock.Lock(request.CommunityID)
+	defer m.communityLock.Unlock(request.CommunityID)
+
+	publicKey, err := common.HexToPubkey(request.User.String())
+	if err != nil {
+		return nil, err
+	}
+
+	community, err := m.GetByID(request.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := m.timesource.GetCurrentTime() + uint64((time.Duration(request.Duration) * time.Minute).Milliseconds())
+	_, err = community.TimeoutMember(publicKey, expiresAt)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.saveAndPublish(community)
+	if err != nil {
+		return nil, err
+	}
+
+	return community, nil
+}
+
+func (m *Manager) RemoveUserTimeoutFromCommunity(request *requests.RemoveUserTimeoutFromCommunity) (*Community, error) {
+	m.communityLock.Lock(request.CommunityID)
+	defer m.communityLock.Unlock(request.CommunityID)
+
+	publicKey, err := common.HexToPubkey(request.User.String())
+	if err != nil {
+		return nil, err
+	}
+
+	community, err := m.GetByID(request.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = community.RemoveMemberTimeout(publicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.saveAndPublish(community)
+	if err != nil {
+		return nil, err
+	}
+
+	return community, nil
+}
+
+func (m *Manager) SetCommunityChannelSlowMode(request *requests.SetCommunityChannelSlowMode) (*Community, *CommunityChanges, error) {
+	m.communityLock.Lock(request.CommunityID)
+	defer m.communityLock.Unlock(request.CommunityID)
+
+	community, err := m.GetByID(request.CommunityID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Remove communityID prefix from chatID if exists
+	chatID := strings.TrimPrefix(request.ChatID, request.CommunityID.String())
+
+	changes, err := community.SetChatSlowModeInterval(chatID, request.Interval)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	err = m.saveAndPublish(community)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return community, changes, nil
+}
+
+func (m *Manager) SetCommunityMediaBlocklist(request *requests.SetCommunityMediaBlocklist) (*Community, error) {
+	m.communityLock.Lock(request.CommunityID)
+	defer m.communityLock.Unlock(request.CommunityID)
+
+	community, err := m.GetByID(request.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = community.SetMediaBlocklist(request.ToProtobuf())
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.saveAndPublish(community)
+	if err != nil {
+		return nil, err
+	}
+
+	return community, nil
+}
+
 func (m *Manager) dbRecordBundleToCommunity(r *CommunityRecordBundle) (*Community, error) {
 	var descriptionEncryptor DescriptionEncryptor
 	if m.encryptor != nil {
@@ -5351,3 +5468,19 @@ func unmarshalCommunityDescriptionMessage(signedDescription []byte, signerPubkey
 
 	return description, nil
 }
+
+func (m *Manager) SaveMessageReport(report *MessageReport) error {
+	return m.persistence.SaveMessageReport(report)
+}
+
+func (m *Manager) GetMessageReport(id types.HexBytes) (*MessageReport, error) {
+	return m.persistence.GetMessageReport(id)
+}
+
+func (m *Manager) GetMessageReports(communityID types.HexBytes, status MessageReportStatus) ([]*MessageReport, error) {
+	return m.persistence.GetMessageReports(communityID, status)
+}
+
+func (m *Manager) ResolveMessageReports(communityID types.HexBytes, messageID string, status MessageReportStatus, resolvedBy string) error {
+	return m.persistence.ResolveMessageReports(communityID, messageID, status, resolvedBy, m.timesource.GetCurrentTime())
+}
@@ -0,0 +1,80 @@
+package communities
+
+import (
+	"fmt"
+
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	// MaxMessageReportDescriptionLength is the maximum length, in characters, of the description of a report
+	MaxMessageReportDescriptionLength = 1024
+	// MaxMessageReportEvidenceSize is the maximum size of the reported message attached to a report
+	MaxMessageReportEvidenceSize = 64 * 1024
+)
+
+type MessageReportStatus uint
+
+const (
+	MessageReportStatusOpen MessageReportStatus = iota + 1
+	MessageReportStatusActioned
+	MessageReportStatusDismissed
+)
+
+// MessageReport is a member's report of a community message, queued for moderation
+// on the control node and privileged members
+type MessageReport struct {
+	ID          types.HexBytes                         `json:"id"`
+	CommunityID types.HexBytes                         `json:"communityId"`
+	ChatID      string                                 `json:"chatId"`
+	MessageID   string                                 `json:"messageId"`
+	MemberID    string                                 `json:"memberId"`
+	ReporterID  string                                 `json:"reporterId"`
+	Reason      protobuf.CommunityMessageReport_Reason `json:"reason"`
+	Description string                                 `json:"description"`
+	Evidence    types.HexBytes                         `json:"evidence,omitempty"`
+	Clock       uint64                                 `json:"clock"`
+	Status      MessageReportStatus                    `json:"status"`
+	ResolvedBy  string                                 `json:"resolvedBy,omitempty"`
+	ResolvedAt  uint64                                 `json:"resolvedAt,omitempty"`
+}
+
+func CalculateMessageReportID(reporterID string, messageID string) types.HexBytes {
+	idString := fmt.Sprintf("%s-%s", reporterID, messageID)
+	return crypto.Keccak256([]byte(idString))
+}
+
+func NewMessageReport(reporterID string, report *protobuf.CommunityMessageReport) *MessageReport {
+	return &MessageReport{
+		ID:          CalculateMessageReportID(reporterID, report.MessageId),
+		CommunityID: report.CommunityId,
+		ChatID:      report.ChatId,
+		MessageID:   report.MessageId,
+		MemberID:    report.MemberId,
+		ReporterID:  reporterID,
+		Reason:      report.Reason,
+		Description: report.Description,
+		Evidence:    report.Evidence,
+		Clock:       report.Clock,
+		Status:      MessageReportStatusOpen,
+	}
+}
+
+// ValidateMessageReport checks a received report, reports are sent by any member
+func ValidateMessageReport(report *protobuf.CommunityMessageReport) error {
+	if len(report.MessageId) == 0 || len(report.ChatId) == 0 || len(report.MemberId) == 0 {
+		return ErrInvalidMessageReport
+	}
+
+	if _, ok := protobuf.CommunityMessageReport_Reason_name[int32(report.Reason)]; !ok || report.Reason == protobuf.CommunityMessageReport_UNKNOWN_REASON {
+		return ErrInvalidMessageReport
+	}
+
+	if len([]rune(report.Description)) > MaxMessageReportDescriptionLength || len(report.Evidence) > MaxMessageReportEvidenceSize {
+		return ErrInvalidMessageReport
+	}
+
+	return nil
+}
@@ -0,0 +1,44 @@
+package communities
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func TestValidateMessageReport(t *testing.T) {
+	valid := func() *protobuf.CommunityMessageReport {
+		return &protobuf.CommunityMessageReport{
+			ChatId:      "chat-id",
+			MessageId:   "message-id",
+			MemberId:    "member-id",
+			Reason:      protobuf.CommunityMessageReport_SPAM,
+			Description: "spam",
+			Evidence:    []byte{1, 2, 3},
+		}
+	}
+	require.NoError(t, ValidateMessageReport(valid()))
+
+	report := valid()
+	report.MessageId = ""
+	require.ErrorIs(t, ValidateMessageReport(report), ErrInvalidMessageReport)
+
+	report = valid()
+	report.Reason = protobuf.CommunityMessageReport_UNKNOWN_REASON
+	require.ErrorIs(t, ValidateMessageReport(report), ErrInvalidMessageReport)
+
+	report = valid()
+	report.Reason = protobuf.CommunityMessageReport_Reason(100)
+	require.ErrorIs(t, ValidateMessageReport(report), ErrInvalidMessageReport)
+
+	report = valid()
+	report.Description = strings.Repeat("a", MaxMessageReportDescriptionLength+1)
+	require.ErrorIs(t, ValidateMessageReport(report), ErrInvalidMessageReport)
+
+	report = valid()
+	report.Evidence = make([]byte, MaxMessageReportEvidenceSize+1)
+	require.ErrorIs(t, ValidateMessageReport(report), ErrInvalidMessageReport)
+}
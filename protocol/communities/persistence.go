@@ -2192,3 +2192,97 @@ func (p *Persistence) UpdateAndPruneEncryptionKeyRequests(communityID types.HexB
 
 	return nil
 }
+
+const messageReportColumns = `id, community_id, chat_id, message_id, member_id, reporter_id, reason, description, evidence, clock, status, resolved_by, resolved_at`
+
+// SaveMessageReport stores a report, a report with a lower clock than the stored one is ignored.
+// An updated report keeps the resolution of the stored one, re-reporting doesn't reopen it.
+func (p *Persistence) SaveMessageReport(report *MessageReport) error {
+	var clock uint64
+	err := p.db.QueryRow(`SELECT clock FROM community_message_reports WHERE id = ?`, report.ID).Scan(&clock)
+	if err != nil && err != sql.ErrNoRows {
+		return err
+	}
+
+	if err == nil && clock >= report.Clock {
+		return ErrOldMessageReport
+	}
+
+	_, err = p.db.Exec(`
+		INSERT INTO community_message_reports (`+messageReportColumns+`)
+		VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+		ON CONFLICT(id)
+		DO UPDATE SET
+			reason = excluded.reason,
+			description = excluded.description,
+			evidence = excluded.evidence,
+			clock = excluded.clock
+		WHERE excluded.clock > community_message_reports.clock`,
+		report.ID, report.CommunityID, report.ChatID, report.MessageID, report.MemberID, report.ReporterID, report.Reason,
+		report.Description, report.Evidence, report.Clock, report.Status, report.ResolvedBy, report.ResolvedAt)
+	return err
+}
+
+func (p *Persistence) GetMessageReport(id types.HexBytes) (*MessageReport, error) {
+	rows, err := p.db.Query(`SELECT `+messageReportColumns+` FROM community_message_reports WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	reports, err := scanMessageReports(rows)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(reports) == 0 {
+		return nil, ErrMessageReportNotFound
+	}
+
+	return reports[0], nil
+}
+
+// GetMessageReports returns the community reports with the given status, all reports if status is 0
+func (p *Persistence) GetMessageReports(communityID types.HexBytes, status MessageReportStatus) ([]*MessageReport, error) {
+	query := `SELECT ` + messageReportColumns + ` FROM community_message_reports WHERE community_id = ?`
+	args := []interface{}{communityID}
+	if status != 0 {
+		query += ` AND status = ?`
+		args = append(args, status)
+	}
+	query += ` ORDER BY clock DESC`
+
+	rows, err := p.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanMessageReports(rows)
+}
+
+// ResolveMessageReports sets the status of all open reports of the given message
+func (p *Persistence) ResolveMessageReports(communityID types.HexBytes, messageID string, status MessageReportStatus, resolvedBy string, resolvedAt uint64) error {
+	_, err := p.db.Exec(`UPDATE community_message_reports SET status = ?, resolved_by = ?, resolved_at = ? WHERE community_id = ? AND message_id = ? AND status = ?`,
+		status, resolvedBy, resolvedAt, communityID, messageID, MessageReportStatusOpen)
+	return err
+}
+
+func (p *Persistence) DeleteMessageReports(communityID types.HexBytes) error {
+	_, err := p.db.Exec(`DELETE FROM community_message_reports WHERE community_id = ?`, communityID)
+	return err
+}
+
+func scanMessageReports(rows *sql.Rows) ([]*MessageReport, error) {
+	var reports []*MessageReport
+	for rows.Next() {
+		report := &MessageReport{}
+		err := rows.Scan(&report.ID, &report.CommunityID, &report.ChatID, &report.MessageID, &report.MemberID, &report.ReporterID,
+			&report.Reason, &report.Description, &report.Evidence, &report.Clock, &report.Status, &report.ResolvedBy, &report.ResolvedAt)
+		if err != nil {
+			return nil, err
+		}
+		reports = append(reports, report)
+	}
+	return reports, rows.Err()
+}
@@ -1004,3 +1004,77 @@ func (s *PersistenceSuite) TestGetCommunityRequestsToJoinRevealedAddresses() {
 	s.Require().True(exists)
 	s.Require().Len(memberAccounts, 1)
 }
+
+func (s *PersistenceSuite) TestMessageReports() {
+	communityID := types.HexBytes{1, 2, 3}
+	report := NewMessageReport("reporter-1", &protobuf.CommunityMessageReport{
+		Clock:       2,
+		CommunityId: communityID,
+		ChatId:      "chat-id",
+		MessageId:   "message-id",
+		MemberId:    "member-id",
+		Reason:      protobuf.CommunityMessageReport_SPAM,
+		Evidence:    []byte{4, 5, 6},
+	})
+	s.Require().NoError(s.db.SaveMessageReport(report))
+
+	otherReport := NewMessageReport("reporter-2", &protobuf.CommunityMessageReport{
+		Clock:       1,
+		CommunityId: communityID,
+		ChatId:      "chat-id",
+		MessageId:   "message-id",
+		MemberId:    "member-id",
+		Reason:      protobuf.CommunityMessageReport_HARASSMENT,
+	})
+	s.Require().NoError(s.db.SaveMessageReport(otherReport))
+
+	// Outdated report is ignored
+	outdated := *report
+	outdated.Clock = 1
+	s.Require().ErrorIs(s.db.SaveMessageReport(&outdated), ErrOldMessageReport)
+
+	fetched, err := s.db.GetMessageReport(report.ID)
+	s.Require().NoError(err)
+	s.Require().Equal(report, fetched)
+
+	reports, err := s.db.GetMessageReports(communityID, MessageReportStatusOpen)
+	s.Require().NoError(err)
+	s.Require().Len(reports, 2)
+
+	err = s.db.ResolveMessageReports(communityID, "message-id", MessageReportStatusDismissed, "moderator", 10)
+	s.Require().NoError(err)
+
+	reports, err = s.db.GetMessageReports(communityID, MessageReportStatusOpen)
+	s.Require().NoError(err)
+	s.Require().Len(reports, 0)
+
+	reports, err = s.db.GetMessageReports(communityID, 0)
+	s.Require().NoError(err)
+	s.Require().Len(reports, 2)
+	for _, r := range reports {
+		s.Require().Equal(MessageReportStatusDismissed, r.Status)
+		s.Require().Equal("moderator", r.ResolvedBy)
+		s.Require().Equal(uint64(10), r.ResolvedAt)
+	}
+
+	// Re-reporting a resolved message doesn't reopen the report
+	updated := *report
+	updated.Clock = 3
+	updated.Description = "still spam"
+	s.Require().NoError(s.db.SaveMessageReport(&updated))
+
+	fetched, err = s.db.GetMessageReport(report.ID)
+	s.Require().NoError(err)
+	s.Require().Equal("still spam", fetched.Description)
+	s.Require().Equal(uint64(3), fetched.Clock)
+	s.Require().Equal(MessageReportStatusDismissed, fetched.Status)
+	s.Require().Equal("moderator", fetched.ResolvedBy)
+
+	_, err = s.db.GetMessageReport(types.HexBytes{9})
+	s.Require().ErrorIs(err, ErrMessageReportNotFound)
+
+	s.Require().NoError(s.db.DeleteMessageReports(communityID))
+	reports, err = s.db.GetMessageReports(communityID, 0)
+	s.Require().NoError(err)
+	s.Require().Len(reports, 0)
+}
@@ -67,6 +67,10 @@ func ValidateCommunityDescription(desc *protobuf.CommunityDescription) error {
 		return ErrInvalidCommunityTags
 	}
 
+	if len(desc.MediaBlocklist) > requests.MaxCommunityMediaBlocklistSize {
+		return ErrInvalidCommunityMediaBlocklist
+	}
+
 	for _, category := range desc.Categories {
 		if err := validateCommunityCategory(category); err != nil {
 			return err
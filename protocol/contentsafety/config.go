@@ -0,0 +1,40 @@
+package contentsafety
+
+// MaxDistanceLimit bounds MaxDistance, past it unrelated images start matching
+const MaxDistanceLimit = 16
+
+// Config of the local content-safety checks of received media
+type Config struct {
+	Enabled bool `json:"enabled"`
+
+	// MaxDistance is the number of bits a media hash can differ by from a blocked hash to match it,
+	// at most MaxDistanceLimit
+	MaxDistance int `json:"maxDistance"`
+
+	// UseCommunityBlocklists matches the media received in communities against the blocklist they publish,
+	// on top of the local blocklist
+	UseCommunityBlocklists bool `json:"useCommunityBlocklists"`
+
+	// AutoReport reports media hidden in a community to its moderators. Only the
+	// matched hash is reported, the media never leaves the device.
+	AutoReport bool `json:"autoReport"`
+}
+
+func DefaultConfig() Config {
+	return Config{
+		Enabled:                true,
+		MaxDistance:            6,
+		UseCommunityBlocklists: true,
+		AutoReport:             false,
+	}
+}
+
+// Clamp keeps MaxDistance between 0 and MaxDistanceLimit
+func (c *Config) Clamp() {
+	if c.MaxDistance < 0 {
+		c.MaxDistance = 0
+	}
+	if c.MaxDistance > MaxDistanceLimit {
+		c.MaxDistance = MaxDistanceLimit
+	}
+}
@@ -0,0 +1,89 @@
+package contentsafety
+
+import (
+	"sync"
+
+	"github.com/status-im/status-go/images"
+)
+
+// Source is the blocklist a hash was matched in
+type Source string
+
+const (
+	SourceLocal     Source = "local"
+	SourceCommunity Source = "community"
+)
+
+// Match is the closest blocked hash a media hash matched
+type Match struct {
+	Source   Source `json:"source"`
+	Hash     Hash   `json:"hash"`
+	Distance int    `json:"distance"`
+}
+
+// Filter matches the hashes of received media against the local blocklist, kept in
+// memory, and the blocklist of the community the media was received in
+type Filter struct {
+	mutex     sync.Mutex
+	config    Config
+	blocklist []Hash
+}
+
+func NewFilter(config Config, blocklist []Hash) *Filter {
+	config.Clamp()
+	return &Filter{
+		config:    config,
+		blocklist: blocklist,
+	}
+}
+
+func (f *Filter) Config() Config {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	return f.config
+}
+
+func (f *Filter) SetConfig(config Config) {
+	config.Clamp()
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.config = config
+}
+
+func (f *Filter) SetBlocklist(blocklist []Hash) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	f.blocklist = blocklist
+}
+
+// Check returns the closest match of the hashes, the local blocklist winning ties, or nil if none matched
+func (f *Filter) Check(hashes []Hash, communityBlocklist []Hash) *Match {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+
+	if !f.config.Enabled {
+		return nil
+	}
+
+	match := closestMatch(hashes, f.blocklist, f.config.MaxDistance, SourceLocal, nil)
+	if f.config.UseCommunityBlocklists {
+		match = closestMatch(hashes, communityBlocklist, f.config.MaxDistance, SourceCommunity, match)
+	}
+	return match
+}
+
+func closestMatch(hashes []Hash, blocklist []Hash, maxDistance int, source Source, closest *Match) *Match {
+	for _, hash := range hashes {
+		for _, blocked := range blocklist {
+			if blocked.Algorithm != hash.Algorithm {
+				continue
+			}
+			distance := images.HammingDistance(hash.Value, blocked.Value)
+			if distance > maxDistance || closest != nil && distance >= closest.Distance {
+				continue
+			}
+			closest = &Match{Source: source, Hash: blocked, Distance: distance}
+		}
+	}
+	return closest
+}
@@ -0,0 +1,92 @@
+package contentsafety
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"hash/crc32"
+	"image"
+	"image/png"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterCheck(t *testing.T) {
+	local := Hash{Algorithm: AlgorithmPHash, Value: 0xff00ff00ff00ff00}
+	community := Hash{Algorithm: AlgorithmDHash, Value: 0x0123456789abcdef}
+	filter := NewFilter(DefaultConfig(), []Hash{local})
+
+	// a few bits off the local hash
+	match := filter.Check([]Hash{{Algorithm: AlgorithmPHash, Value: local.Value ^ 0b101}}, nil)
+	require.NotNil(t, match)
+	require.Equal(t, Match{Source: SourceLocal, Hash: local, Distance: 2}, *match)
+
+	// hashes of different algorithms never match
+	require.Nil(t, filter.Check([]Hash{{Algorithm: AlgorithmDHash, Value: local.Value}}, nil))
+
+	// too many bits off
+	require.Nil(t, filter.Check([]Hash{{Algorithm: AlgorithmPHash, Value: ^local.Value}}, nil))
+
+	// the closest match wins
+	hashes := []Hash{
+		{Algorithm: AlgorithmPHash, Value: local.Value ^ 0b111},
+		{Algorithm: AlgorithmDHash, Value: community.Value},
+	}
+	match = filter.Check(hashes, []Hash{community})
+	require.NotNil(t, match)
+	require.Equal(t, Match{Source: SourceCommunity, Hash: community, Distance: 0}, *match)
+
+	config := DefaultConfig()
+	config.UseCommunityBlocklists = false
+	filter.SetConfig(config)
+	match = filter.Check(hashes, []Hash{community})
+	require.NotNil(t, match)
+	require.Equal(t, SourceLocal, match.Source)
+
+	// any hash would match within 64 bits, the distance is clamped
+	config.MaxDistance = 64
+	filter.SetConfig(config)
+	require.Equal(t, MaxDistanceLimit, filter.Config().MaxDistance)
+	require.Nil(t, filter.Check([]Hash{{Algorithm: AlgorithmPHash, Value: ^local.Value}}, nil))
+
+	config.Enabled = false
+	filter.SetConfig(config)
+	require.Nil(t, filter.Check(hashes, []Hash{community}))
+}
+
+func TestHashMediaTooLarge(t *testing.T) {
+	bb := bytes.NewBuffer([]byte{})
+	require.NoError(t, png.Encode(bb, image.NewGray(image.Rect(0, 0, 2, 2))))
+	payload := bb.Bytes()
+
+	hashes, err := HashMedia(payload)
+	require.NoError(t, err)
+	require.Len(t, hashes, 2)
+
+	// claims a 65536x65536 image in the IHDR chunk, keeping its checksum valid
+	binary.BigEndian.PutUint32(payload[16:20], 1<<16)
+	binary.BigEndian.PutUint32(payload[20:24], 1<<16)
+	binary.BigEndian.PutUint32(payload[29:33], crc32.ChecksumIEEE(payload[12:29]))
+
+	_, err = HashMedia(payload)
+	require.ErrorIs(t, err, ErrMediaTooLarge)
+}
+
+func TestHashJSON(t *testing.T) {
+	hash := Hash{Algorithm: AlgorithmPHash, Value: 0xfedcba9876543210}
+
+	encoded, err := json.Marshal(hash)
+	require.NoError(t, err)
+	require.JSONEq(t, `{"algorithm":"phash","value":"fedcba9876543210"}`, string(encoded))
+
+	var decoded Hash
+	require.NoError(t, json.Unmarshal(encoded, &decoded))
+	require.Equal(t, hash, decoded)
+
+	require.NoError(t, json.Unmarshal([]byte(`{"algorithm":"dhash","value":"0x10"}`), &decoded))
+	require.Equal(t, Hash{Algorithm: AlgorithmDHash, Value: 0x10}, decoded)
+
+	require.Error(t, json.Unmarshal([]byte(`{"algorithm":"dhash","value":"zz"}`), &decoded))
+	require.ErrorIs(t, Hash{Algorithm: "md5"}.Validate(), ErrUnknownAlgorithm)
+}
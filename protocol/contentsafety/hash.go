@@ -0,0 +1,129 @@
+package contentsafety
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"image"
+	"strconv"
+	"strings"
+
+	"github.com/status-im/status-go/images"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+type Algorithm string
+
+const (
+	AlgorithmPHash Algorithm = "phash"
+	AlgorithmDHash Algorithm = "dhash"
+)
+
+// maxMediaDimension bounds the width and the height of the images hashed, checked before they're decoded
+const maxMediaDimension = 4096
+
+var ErrUnknownAlgorithm = errors.New("unknown perceptual hash algorithm")
+var ErrMediaTooLarge = errors.New("media is too large to hash")
+
+// Hash is a perceptual hash of an image. Its value is encoded in JSON as 16 hex digits,
+// as 64 bits integers don't survive JavaScript clients.
+type Hash struct {
+	Algorithm Algorithm `json:"algorithm"`
+	Value     uint64    `json:"-"`
+}
+
+func (h Hash) String() string {
+	return fmt.Sprintf("%s:%016x", h.Algorithm, h.Value)
+}
+
+func (h Hash) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Algorithm Algorithm `json:"algorithm"`
+		Value     string    `json:"value"`
+	}{h.Algorithm, fmt.Sprintf("%016x", h.Value)})
+}
+
+func (h *Hash) UnmarshalJSON(data []byte) error {
+	var aux struct {
+		Algorithm Algorithm `json:"algorithm"`
+		Value     string    `json:"value"`
+	}
+	if err := json.Unmarshal(data, &aux); err != nil {
+		return err
+	}
+
+	value, err := strconv.ParseUint(strings.TrimPrefix(aux.Value, "0x"), 16, 64)
+	if err != nil {
+		return err
+	}
+
+	h.Algorithm = aux.Algorithm
+	h.Value = value
+	return nil
+}
+
+func (h Hash) Validate() error {
+	switch h.Algorithm {
+	case AlgorithmPHash, AlgorithmDHash:
+		return nil
+	default:
+		return ErrUnknownAlgorithm
+	}
+}
+
+func (h Hash) ToProtobuf() *protobuf.CommunityMediaHash {
+	algorithm := protobuf.CommunityMediaHash_UNKNOWN_ALGORITHM
+	switch h.Algorithm {
+	case AlgorithmPHash:
+		algorithm = protobuf.CommunityMediaHash_PHASH
+	case AlgorithmDHash:
+		algorithm = protobuf.CommunityMediaHash_DHASH
+	}
+	return &protobuf.CommunityMediaHash{Algorithm: algorithm, Hash: h.Value}
+}
+
+// HashFromProtobuf returns false for hashes of algorithms this version doesn't know
+func HashFromProtobuf(hash *protobuf.CommunityMediaHash) (Hash, bool) {
+	switch hash.Algorithm {
+	case protobuf.CommunityMediaHash_PHASH:
+		return Hash{Algorithm: AlgorithmPHash, Value: hash.Hash}, true
+	case protobuf.CommunityMediaHash_DHASH:
+		return Hash{Algorithm: AlgorithmDHash, Value: hash.Hash}, true
+	default:
+		return Hash{}, false
+	}
+}
+
+func HashesFromProtobuf(hashes []*protobuf.CommunityMediaHash) []Hash {
+	var result []Hash
+	for _, hash := range hashes {
+		if h, ok := HashFromProtobuf(hash); ok {
+			result = append(result, h)
+		}
+	}
+	return result
+}
+
+// HashMedia returns the perceptual hashes of an image payload, with every algorithm.
+// ErrMediaTooLarge is returned for images larger than maxMediaDimension.
+func HashMedia(payload []byte) ([]Hash, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+	// each dimension is checked, their product overflowing on 32 bits platforms
+	if config.Width > maxMediaDimension || config.Height > maxMediaDimension {
+		return nil, ErrMediaTooLarge
+	}
+
+	img, err := images.DecodeImageData(payload, bytes.NewReader(payload))
+	if err != nil {
+		return nil, err
+	}
+
+	return []Hash{
+		{Algorithm: AlgorithmPHash, Value: images.PerceptualHash(img)},
+		{Algorithm: AlgorithmDHash, Value: images.DifferenceHash(img)},
+	}, nil
+}
@@ -0,0 +1,171 @@
+package contentsafety
+
+import (
+	"database/sql"
+	"encoding/json"
+)
+
+// BlockedHash is an entry of the local blocklist
+type BlockedHash struct {
+	Hash    Hash   `json:"hash"`
+	Label   string `json:"label"`
+	AddedAt uint64 `json:"addedAt"`
+}
+
+// HiddenMedia is a received message whose media matched a blocklist and was hidden
+type HiddenMedia struct {
+	MessageID   string `json:"messageId"`
+	ChatID      string `json:"chatId"`
+	CommunityID string `json:"communityId"`
+	SenderID    string `json:"senderId"`
+	Match       Match  `json:"match"`
+	HiddenAt    uint64 `json:"hiddenAt"`
+}
+
+type Persistence struct {
+	db *sql.DB
+}
+
+func NewPersistence(db *sql.DB) *Persistence {
+	return &Persistence{db: db}
+}
+
+func (p *Persistence) AddToBlocklist(hashes []BlockedHash) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	for _, blocked := range hashes {
+		_, err = tx.Exec(`INSERT OR REPLACE INTO content_safety_blocklist (algorithm, hash, label, added_at) VALUES (?, ?, ?, ?)`,
+			blocked.Hash.Algorithm, int64(blocked.Hash.Value), blocked.Label, blocked.AddedAt)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Persistence) RemoveFromBlocklist(hashes []Hash) (err error) {
+	tx, err := p.db.Begin()
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		_ = tx.Rollback()
+	}()
+
+	for _, hash := range hashes {
+		_, err = tx.Exec(`DELETE FROM content_safety_blocklist WHERE algorithm = ? AND hash = ?`, hash.Algorithm, int64(hash.Value))
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Persistence) Blocklist() ([]BlockedHash, error) {
+	rows, err := p.db.Query(`SELECT algorithm, hash, label, added_at FROM content_safety_blocklist ORDER BY added_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var hashes []BlockedHash
+	for rows.Next() {
+		var value int64
+		blocked := BlockedHash{}
+		if err := rows.Scan(&blocked.Hash.Algorithm, &value, &blocked.Label, &blocked.AddedAt); err != nil {
+			return nil, err
+		}
+		blocked.Hash.Value = uint64(value)
+		hashes = append(hashes, blocked)
+	}
+	return hashes, rows.Err()
+}
+
+func (p *Persistence) SaveHiddenMedia(media *HiddenMedia) error {
+	_, err := p.db.Exec(`INSERT OR REPLACE INTO content_safety_hidden_media (message_id, chat_id, community_id, sender_id, source, algorithm, hash, distance, hidden_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		media.MessageID, media.ChatID, media.CommunityID, media.SenderID, media.Match.Source, media.Match.Hash.Algorithm, int64(media.Match.Hash.Value), media.Match.Distance, media.HiddenAt)
+	return err
+}
+
+// HiddenMedia returns the media hidden in a community, all of them if communityID is empty
+func (p *Persistence) HiddenMedia(communityID string) ([]*HiddenMedia, error) {
+	if communityID == "" {
+		return p.queryHiddenMedia(`ORDER BY hidden_at DESC`)
+	}
+	return p.queryHiddenMedia(`WHERE community_id = ? ORDER BY hidden_at DESC`, communityID)
+}
+
+// HiddenMediaByMessageID returns the hidden media of a message, nil if it's not hidden
+func (p *Persistence) HiddenMediaByMessageID(messageID string) (*HiddenMedia, error) {
+	media, err := p.queryHiddenMedia(`WHERE message_id = ?`, messageID)
+	if err != nil || len(media) == 0 {
+		return nil, err
+	}
+	return media[0], nil
+}
+
+func (p *Persistence) DeleteHiddenMedia(messageID string) error {
+	_, err := p.db.Exec(`DELETE FROM content_safety_hidden_media WHERE message_id = ?`, messageID)
+	return err
+}
+
+func (p *Persistence) queryHiddenMedia(where string, args ...interface{}) ([]*HiddenMedia, error) {
+	rows, err := p.db.Query(`SELECT message_id, chat_id, community_id, sender_id, source, algorithm, hash, distance, hidden_at FROM content_safety_hidden_media `+where, args...) // nolint: gosec
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []*HiddenMedia
+	for rows.Next() {
+		var value int64
+		media := &HiddenMedia{}
+		err := rows.Scan(&media.MessageID, &media.ChatID, &media.CommunityID, &media.SenderID, &media.Match.Source, &media.Match.Hash.Algorithm, &value, &media.Match.Distance, &media.HiddenAt)
+		if err != nil {
+			return nil, err
+		}
+		media.Match.Hash.Value = uint64(value)
+		result = append(result, media)
+	}
+	return result, rows.Err()
+}
+
+func (p *Persistence) SaveConfig(config Config) error {
+	encoded, err := json.Marshal(config)
+	if err != nil {
+		return err
+	}
+
+	_, err = p.db.Exec(`INSERT OR REPLACE INTO content_safety_config (id, config) VALUES (1, ?)`, encoded)
+	return err
+}
+
+// GetConfig returns the stored config, or the default one if none was saved
+func (p *Persistence) GetConfig() (Config, error) {
+	var encoded []byte
+	err := p.db.QueryRow(`SELECT config FROM content_safety_config WHERE id = 1`).Scan(&encoded)
+	if err == sql.ErrNoRows {
+		return DefaultConfig(), nil
+	}
+	if err != nil {
+		return Config{}, err
+	}
+
+	config := DefaultConfig()
+	err = json.Unmarshal(encoded, &config)
+	return config, err
+}
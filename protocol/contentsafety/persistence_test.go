@@ -0,0 +1,100 @@
+package contentsafety
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/appdatabase"
+	"github.com/status-im/status-go/protocol/sqlite"
+	"github.com/status-im/status-go/t/helpers"
+)
+
+func TestPersistenceSuite(t *testing.T) {
+	suite.Run(t, new(PersistenceSuite))
+}
+
+type PersistenceSuite struct {
+	suite.Suite
+	p *Persistence
+}
+
+func (s *PersistenceSuite) SetupTest() {
+	db, err := helpers.SetupTestMemorySQLDB(appdatabase.DbInitializer{})
+	s.Require().NoError(err)
+
+	err = sqlite.Migrate(db)
+	s.Require().NoError(err)
+
+	s.p = NewPersistence(db)
+}
+
+func (s *PersistenceSuite) TestBlocklist() {
+	// the highest bit set survives the signed column
+	phash := Hash{Algorithm: AlgorithmPHash, Value: 0xfedcba9876543210}
+	dhash := Hash{Algorithm: AlgorithmDHash, Value: 0x10}
+
+	s.Require().NoError(s.p.AddToBlocklist([]BlockedHash{
+		{Hash: phash, Label: "abuse", AddedAt: 1},
+		{Hash: dhash, AddedAt: 2},
+	}))
+
+	blocklist, err := s.p.Blocklist()
+	s.Require().NoError(err)
+	s.Require().Equal([]BlockedHash{
+		{Hash: phash, Label: "abuse", AddedAt: 1},
+		{Hash: dhash, AddedAt: 2},
+	}, blocklist)
+
+	s.Require().NoError(s.p.RemoveFromBlocklist([]Hash{phash}))
+	blocklist, err = s.p.Blocklist()
+	s.Require().NoError(err)
+	s.Require().Equal([]BlockedHash{{Hash: dhash, AddedAt: 2}}, blocklist)
+}
+
+func (s *PersistenceSuite) TestHiddenMedia() {
+	media := &HiddenMedia{
+		MessageID:   "message-1",
+		ChatID:      "chat-1",
+		CommunityID: "community-1",
+		SenderID:    "sender-1",
+		Match: Match{
+			Source:   SourceCommunity,
+			Hash:     Hash{Algorithm: AlgorithmDHash, Value: 0x8000000000000001},
+			Distance: 3,
+		},
+		HiddenAt: 20,
+	}
+	s.Require().NoError(s.p.SaveHiddenMedia(media))
+
+	fetched, err := s.p.HiddenMediaByMessageID("message-1")
+	s.Require().NoError(err)
+	s.Require().Equal(media, fetched)
+
+	all, err := s.p.HiddenMedia("community-1")
+	s.Require().NoError(err)
+	s.Require().Len(all, 1)
+
+	all, err = s.p.HiddenMedia("community-2")
+	s.Require().NoError(err)
+	s.Require().Len(all, 0)
+
+	s.Require().NoError(s.p.DeleteHiddenMedia("message-1"))
+	fetched, err = s.p.HiddenMediaByMessageID("message-1")
+	s.Require().NoError(err)
+	s.Require().Nil(fetched)
+}
+
+func (s *PersistenceSuite) TestConfig() {
+	config, err := s.p.GetConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(DefaultConfig(), config)
+
+	config.AutoReport = true
+	config.MaxDistance = 10
+	s.Require().NoError(s.p.SaveConfig(config))
+
+	fetched, err := s.p.GetConfig()
+	s.Require().NoError(err)
+	s.Require().Equal(config, fetched)
+}
@@ -11,4 +11,7 @@ var (
 	ErrContactNotFound  = errors.New("contact not found")
 	ErrCommunityIDEmpty = errors.New("community ID is empty")
 	ErrUserNotMember    = errors.New("user not a member")
+
+	ErrMemberTimedOut             = errors.New("member is timed out")
+	ErrSlowModeIntervalNotElapsed = errors.New("slow mode interval has not elapsed")
 )
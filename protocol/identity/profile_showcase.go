@@ -99,6 +99,9 @@ type ProfileShowcaseCommunity struct {
 	Order            int                             `json:"order"`
 	MembershipStatus ProfileShowcaseMembershipStatus `json:"membershipStatus"`
 	Grant            []byte                          `json:"grant,omitempty"`
+	// ImageURL and AnimatedImageURL are the thumbnail of the community, set when it's known
+	ImageURL         string `json:"imageUrl,omitempty"`
+	AnimatedImageURL string `json:"animatedImageUrl,omitempty"`
 }
 
 type ProfileShowcaseAccount struct {
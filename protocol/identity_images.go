@@ -29,6 +29,15 @@ func EncryptIdentityImagesWithContactPubKeys(iis map[string]*protobuf.IdentityIm
 
 		// Overwrite the unencrypted payload with the newly encrypted payload
 		ii.Payload = encryptedPayload
+
+		// The animated payload of animated images is encrypted with the same key
+		if len(ii.AnimatedPayload) > 0 {
+			ii.AnimatedPayload, err = common.Encrypt(ii.AnimatedPayload, AESKey, crand.Reader)
+			if err != nil {
+				return err
+			}
+		}
+
 		ii.Encrypted = true
 		m.allContacts.Range(func(contactID string, contact *Contact) (shouldContinue bool) {
 			if !contact.added() {
@@ -98,6 +107,14 @@ image:
 				return errors.New("decrypting the payload resulted in no error and a nil payload")
 			}
 
+			if len(ii.AnimatedPayload) > 0 {
+				animatedPayload, err := common.Decrypt(ii.AnimatedPayload, dAESKey)
+				if err != nil {
+					return err
+				}
+				ii.AnimatedPayload = animatedPayload
+			}
+
 			// Overwrite the payload with the decrypted data
 			ii.Payload = payload
 			ii.Encrypted = false
@@ -0,0 +1,249 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	neturl "net/url"
+	"strings"
+	"time"
+)
+
+const (
+	// defaultLinkPreviewMaxBodySize is the largest response the unfurlers read
+	defaultLinkPreviewMaxBodySize = 5 * 1024 * 1024
+	// defaultLinkPreviewCacheTTL is how long an unfurled link is reused before being fetched again
+	defaultLinkPreviewCacheTTL = 24 * time.Hour
+)
+
+var (
+	ErrLinkPreviewDomainNotAllowed = errors.New("link preview domain not allowed")
+	ErrLinkPreviewTooLarge         = errors.New("link preview response too large")
+	ErrLinkPreviewInvalidProxy     = errors.New("invalid link preview proxy")
+)
+
+// LinkPreviewRelay fetches URLs on behalf of the unfurlers,
+// so that the sites being previewed only see the relay and not who's chatting about them
+type LinkPreviewRelay interface {
+	Fetch(req *http.Request) (*http.Response, error)
+}
+
+// LinkPreviewFetchConfig describes how the unfurlers reach the sites they preview
+type LinkPreviewFetchConfig struct {
+	// ProxyURL is a SOCKS5 (socks5://host:port) or an HTTP (http://host:port) proxy,
+	// SOCKS5 proxies resolve host names themselves
+	ProxyURL string
+	// Relay fetches the URLs instead of the proxy when set
+	Relay LinkPreviewRelay
+	// AllowedDomains, when not empty, are the only domains fetched, subdomains included
+	AllowedDomains []string
+	// DeniedDomains are never fetched, subdomains included
+	DeniedDomains []string
+	// MaxBodySize is the largest response read, in bytes
+	MaxBodySize int64
+	// Timeout is how long a single request may take
+	Timeout time.Duration
+	// CacheTTL is how long an unfurled link is reused before being fetched again
+	CacheTTL time.Duration
+	// MastodonInstances are the hosts whose posts are unfurled through the Mastodon API
+	MastodonInstances []string
+}
+
+// defaultMastodonInstances are the largest Mastodon instances, posts on other hosts get plain previews
+var defaultMastodonInstances = []string{
+	"mastodon.social",
+	"mastodon.online",
+	"mstdn.social",
+	"mas.to",
+	"fosstodon.org",
+	"hachyderm.io",
+	"infosec.exchange",
+	"techhub.social",
+	"mastodon.world",
+}
+
+// DefaultLinkPreviewFetchConfig fetches links directly, without any domain restriction
+func DefaultLinkPreviewFetchConfig() LinkPreviewFetchConfig {
+	return LinkPreviewFetchConfig{
+		MaxBodySize:       defaultLinkPreviewMaxBodySize,
+		Timeout:           DefaultRequestTimeout,
+		CacheTTL:          defaultLinkPreviewCacheTTL,
+		MastodonInstances: append([]string(nil), defaultMastodonInstances...),
+	}
+}
+
+// newLinkPreviewTransport returns the transport reaching the sites through the relay or the proxy,
+// directly when none is configured
+func newLinkPreviewTransport(c LinkPreviewFetchConfig) (http.RoundTripper, error) {
+	if c.Relay != nil {
+		return relayTransport{relay: c.Relay}, nil
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	if c.ProxyURL != "" {
+		proxyURL, err := neturl.Parse(c.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("%w: %s", ErrLinkPreviewInvalidProxy, err)
+		}
+		switch proxyURL.Scheme {
+		case "socks5", "socks5h", "http", "https":
+		default:
+			return nil, fmt.Errorf("%w: unsupported scheme '%s'", ErrLinkPreviewInvalidProxy, proxyURL.Scheme)
+		}
+		if proxyURL.Host == "" {
+			return nil, fmt.Errorf("%w: missing host", ErrLinkPreviewInvalidProxy)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	} else {
+		transport.Proxy = nil
+	}
+	return transport, nil
+}
+
+// newLinkPreviewHTTPClient returns a client applying the domain lists and the size and time budget
+// of the config to every request, redirects included. The transport of base is only used when
+// the config has neither a proxy nor a relay, so that links are never fetched around them.
+func newLinkPreviewHTTPClient(c LinkPreviewFetchConfig, transport http.RoundTripper, base *http.Client) *http.Client {
+	timeout := c.Timeout
+	if base != nil {
+		if base.Transport != nil && c.ProxyURL == "" && c.Relay == nil {
+			transport = base.Transport
+		}
+		if base.Timeout != 0 && (timeout == 0 || base.Timeout < timeout) {
+			timeout = base.Timeout
+		}
+	}
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+
+	return &http.Client{
+		Transport: &budgetTransport{
+			next:           transport,
+			allowedDomains: c.AllowedDomains,
+			deniedDomains:  c.DeniedDomains,
+			maxBodySize:    c.MaxBodySize,
+		},
+		Timeout: timeout,
+	}
+}
+
+// linkPreviewDomainAllowed checks a host name against the allow and deny lists, the deny list wins
+func linkPreviewDomainAllowed(hostname string, allowed []string, denied []string) bool {
+	hostname = normalizeHostname(hostname)
+	for _, domain := range denied {
+		if matchesDomain(hostname, domain) {
+			return false
+		}
+	}
+	if len(allowed) == 0 {
+		return true
+	}
+	for _, domain := range allowed {
+		if matchesDomain(hostname, domain) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchesDomain(hostname string, domain string) bool {
+	domain = normalizeHostname(strings.TrimPrefix(domain, "."))
+	return domain != "" && (hostname == domain || strings.HasSuffix(hostname, "."+domain))
+}
+
+// budgetTransport rejects the requests to domains not allowed and cuts responses above the size budget
+type budgetTransport struct {
+	next           http.RoundTripper
+	allowedDomains []string
+	deniedDomains  []string
+	maxBodySize    int64
+}
+
+func (t *budgetTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !linkPreviewDomainAllowed(req.URL.Hostname(), t.allowedDomains, t.deniedDomains) {
+		return nil, fmt.Errorf("%w: '%s'", ErrLinkPreviewDomainNotAllowed, req.URL.Hostname())
+	}
+
+	res, err := t.next.RoundTrip(req)
+	if err != nil || t.maxBodySize <= 0 {
+		return res, err
+	}
+	if res.ContentLength > t.maxBodySize {
+		res.Body.Close()
+		return nil, ErrLinkPreviewTooLarge
+	}
+	res.Body = &limitedBody{body: res.Body, remaining: t.maxBodySize}
+	return res, nil
+}
+
+// limitedBody fails the read once more than the size budget is read
+type limitedBody struct {
+	body      io.ReadCloser
+	remaining int64
+}
+
+func (b *limitedBody) Read(p []byte) (int, error) {
+	if b.remaining < 0 {
+		return 0, ErrLinkPreviewTooLarge
+	}
+	// read one byte past the budget, to tell a body of the exact size apart from a larger one
+	if int64(len(p)) > b.remaining+1 {
+		p = p[:b.remaining+1]
+	}
+	n, err := b.body.Read(p)
+	b.remaining -= int64(n)
+	if b.remaining < 0 {
+		return 0, ErrLinkPreviewTooLarge
+	}
+	return n, err
+}
+
+func (b *limitedBody) Close() error {
+	return b.body.Close()
+}
+
+type relayTransport struct {
+	relay LinkPreviewRelay
+}
+
+func (t relayTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.relay.Fetch(req)
+}
+
+// HTTPLinkPreviewRelay is a preview relay reached over HTTP, which is given the URL to fetch
+// in the url query parameter and answers with the response of the site
+type HTTPLinkPreviewRelay struct {
+	endpoint   *neturl.URL
+	httpClient *http.Client
+}
+
+func NewHTTPLinkPreviewRelay(endpoint string, httpClient *http.Client) (*HTTPLinkPreviewRelay, error) {
+	endpointURL, err := neturl.Parse(endpoint)
+	if err != nil {
+		return nil, err
+	}
+	if endpointURL.Scheme != "https" && endpointURL.Scheme != "http" {
+		return nil, fmt.Errorf("unsupported link preview relay scheme '%s'", endpointURL.Scheme)
+	}
+	if httpClient == nil {
+		httpClient = NewDefaultHTTPClient()
+	}
+	return &HTTPLinkPreviewRelay{endpoint: endpointURL, httpClient: httpClient}, nil
+}
+
+func (r *HTTPLinkPreviewRelay) Fetch(req *http.Request) (*http.Response, error) {
+	relayURL := *r.endpoint
+	query := relayURL.Query()
+	query.Set("url", req.URL.String())
+	relayURL.RawQuery = query.Encode()
+
+	relayReq, err := http.NewRequestWithContext(req.Context(), http.MethodGet, relayURL.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	relayReq.Header = req.Header.Clone()
+
+	return r.httpClient.Do(relayReq)
+}
@@ -0,0 +1,90 @@
+package protocol
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/tt"
+)
+
+func TestLinkPreviewDomainAllowed(t *testing.T) {
+	denied := []string{"tracker.com"}
+	require.True(t, linkPreviewDomainAllowed("status.im", nil, denied))
+	require.False(t, linkPreviewDomainAllowed("tracker.com", nil, denied))
+	require.False(t, linkPreviewDomainAllowed("www.cdn.tracker.com", nil, denied))
+	require.True(t, linkPreviewDomainAllowed("nottracker.com", nil, denied))
+
+	allowed := []string{"status.im", "tracker.com"}
+	require.True(t, linkPreviewDomainAllowed("www.status.im", allowed, denied))
+	require.False(t, linkPreviewDomainAllowed("github.com", allowed, denied))
+	// the deny list wins
+	require.False(t, linkPreviewDomainAllowed("tracker.com", allowed, denied))
+}
+
+func TestNewLinkPreviewTransport(t *testing.T) {
+	for _, proxyURL := range []string{"socks5://127.0.0.1:9050", "http://127.0.0.1:8080"} {
+		transport, err := newLinkPreviewTransport(LinkPreviewFetchConfig{ProxyURL: proxyURL})
+		require.NoError(t, err)
+
+		req, err := http.NewRequest(http.MethodGet, "https://status.im", nil)
+		require.NoError(t, err)
+		proxy, err := transport.(*http.Transport).Proxy(req)
+		require.NoError(t, err)
+		require.Equal(t, proxyURL, proxy.String())
+	}
+
+	_, err := newLinkPreviewTransport(LinkPreviewFetchConfig{ProxyURL: "ftp://127.0.0.1"})
+	require.ErrorIs(t, err, ErrLinkPreviewInvalidProxy)
+}
+
+type stubLinkPreviewRelay struct {
+	fetched []string
+	body    []byte
+}
+
+func (r *stubLinkPreviewRelay) Fetch(req *http.Request) (*http.Response, error) {
+	r.fetched = append(r.fetched, req.URL.String())
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Body:       ioutil.NopCloser(bytes.NewReader(r.body)),
+	}, nil
+}
+
+func TestLinkPreviewHTTPClient(t *testing.T) {
+	relay := &stubLinkPreviewRelay{body: bytes.Repeat([]byte{'a'}, 10)}
+	fetchConfig := DefaultLinkPreviewFetchConfig()
+	fetchConfig.Relay = relay
+	fetchConfig.DeniedDomains = []string{"tracker.com"}
+	fetchConfig.MaxBodySize = 10
+
+	transport, err := newLinkPreviewTransport(fetchConfig)
+	require.NoError(t, err)
+	httpClient := newLinkPreviewHTTPClient(fetchConfig, transport, nil)
+
+	body, err := fetchBody(tt.MustCreateTestLogger(), httpClient, "https://status.im", nil)
+	require.NoError(t, err)
+	require.Equal(t, relay.body, body)
+	require.Equal(t, []string{"https://status.im"}, relay.fetched)
+
+	_, err = fetchBody(tt.MustCreateTestLogger(), httpClient, "https://tracker.com", nil)
+	require.ErrorIs(t, err, ErrLinkPreviewDomainNotAllowed)
+	require.Len(t, relay.fetched, 1)
+
+	// Responses above the size budget are cut
+	relay.body = append(relay.body, 'a')
+	_, err = fetchBody(tt.MustCreateTestLogger(), httpClient, "https://status.im", nil)
+	require.ErrorIs(t, err, ErrLinkPreviewTooLarge)
+
+	// The relay wins over the transport of the client given
+	relay.body = relay.body[:10]
+	direct := &stubLinkPreviewRelay{body: relay.body}
+	httpClient = newLinkPreviewHTTPClient(fetchConfig, transport, &http.Client{Transport: relayTransport{relay: direct}})
+	_, err = fetchBody(tt.MustCreateTestLogger(), httpClient, "https://status.im", nil)
+	require.NoError(t, err)
+	require.Len(t, relay.fetched, 3)
+	require.Empty(t, direct.fetched)
+}
@@ -37,6 +37,11 @@ type Unfurler interface {
 	Unfurl() (*common.LinkPreview, error)
 }
 
+// RichUnfurler unfurls a link to a structured preview, see common.RichLinkPreview
+type RichUnfurler interface {
+	Unfurl() (*common.RichLinkPreview, error)
+}
+
 func newDefaultLinkPreview(url *neturl.URL) *common.LinkPreview {
 	return &common.LinkPreview{
 		URL:      url.String(),
@@ -0,0 +1,176 @@
+package protocol
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strings"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/images"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/services/wallet/bigint"
+	walletcommon "github.com/status-im/status-go/services/wallet/common"
+	"github.com/status-im/status-go/services/wallet/thirdparty"
+)
+
+// marketplaceChainIDs are the chains as named in the links of NFT marketplaces
+var marketplaceChainIDs = map[string]uint64{
+	"ethereum":         1,
+	"optimism":         10,
+	"arbitrum":         42161,
+	"base":             8453,
+	"sepolia":          11155111,
+	"optimism_sepolia": 11155420,
+	"arbitrum_sepolia": 421614,
+	"base_sepolia":     84532,
+}
+
+var (
+	marketplaceCollectibleRegexp = regexp.MustCompile(`^/(?:assets|item)/([a-z_]+)/(0x[0-9a-fA-F]{40})/(\d+)/?$`)
+	explorerCollectibleRegexp    = regexp.MustCompile(`^/nft/(0x[0-9a-fA-F]{40})/(\d+)/?$`)
+)
+
+// collectiblesFetcher returns the metadata of collectibles, see collectibles.Manager
+type collectiblesFetcher interface {
+	FetchAssetsByCollectibleUniqueID(ctx context.Context, uniqueIDs []thirdparty.CollectibleUniqueID, asyncFetch bool) ([]thirdparty.FullCollectibleData, error)
+}
+
+func parseCollectibleURL(url *neturl.URL) (thirdparty.CollectibleUniqueID, bool) {
+	hostname := normalizeHostname(url.Hostname())
+
+	var chainID uint64
+	var contract, tokenID string
+	if hostname == "opensea.io" || hostname == "testnets.opensea.io" {
+		matches := marketplaceCollectibleRegexp.FindStringSubmatch(url.Path)
+		if matches == nil {
+			return thirdparty.CollectibleUniqueID{}, false
+		}
+		var ok bool
+		chainID, ok = marketplaceChainIDs[matches[1]]
+		if !ok {
+			return thirdparty.CollectibleUniqueID{}, false
+		}
+		contract, tokenID = matches[2], matches[3]
+	} else {
+		var ok bool
+		chainID, ok = blockExplorerChainIDs[hostname]
+		if !ok {
+			return thirdparty.CollectibleUniqueID{}, false
+		}
+		matches := explorerCollectibleRegexp.FindStringSubmatch(url.Path)
+		if matches == nil {
+			return thirdparty.CollectibleUniqueID{}, false
+		}
+		contract, tokenID = matches[1], matches[2]
+	}
+
+	id, ok := new(big.Int).SetString(tokenID, 10)
+	if !ok {
+		return thirdparty.CollectibleUniqueID{}, false
+	}
+	return thirdparty.CollectibleUniqueID{
+		ContractID: thirdparty.ContractID{
+			ChainID: walletcommon.ChainID(chainID),
+			Address: gethcommon.HexToAddress(contract),
+		},
+		TokenID: &bigint.BigInt{Int: id},
+	}, true
+}
+
+// CollectibleUnfurler unfurls links to NFTs through the collectibles manager of the wallet,
+// the image is fetched through the link preview transport
+type CollectibleUnfurler struct {
+	url          *neturl.URL
+	id           thirdparty.CollectibleUniqueID
+	logger       *zap.Logger
+	httpClient   *http.Client
+	collectibles collectiblesFetcher
+}
+
+func newCollectibleUnfurler(URL *neturl.URL, id thirdparty.CollectibleUniqueID, logger *zap.Logger, httpClient *http.Client, collectibles collectiblesFetcher) *CollectibleUnfurler {
+	return &CollectibleUnfurler{
+		url:          URL,
+		id:           id,
+		logger:       logger,
+		httpClient:   httpClient,
+		collectibles: collectibles,
+	}
+}
+
+func (u *CollectibleUnfurler) Unfurl() (*common.RichLinkPreview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	assets, err := u.collectibles.FetchAssetsByCollectibleUniqueID(ctx, []thirdparty.CollectibleUniqueID{u.id}, false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch collectible: %w", err)
+	}
+	if len(assets) == 0 {
+		return nil, fmt.Errorf("collectible not found")
+	}
+	asset := assets[0]
+
+	preview := &common.CollectibleLinkPreview{
+		ChainID:         uint64(u.id.ContractID.ChainID),
+		ContractAddress: u.id.ContractID.Address.Hex(),
+		TokenID:         u.id.TokenID.String(),
+		Name:            asset.CollectibleData.Name,
+		Description:     asset.CollectibleData.Description,
+	}
+	if asset.CollectionData != nil {
+		preview.CollectionName = asset.CollectionData.Name
+	}
+
+	// The image is fetched on a best-effort basis
+	image, err := u.image(&asset.CollectibleData)
+	if err != nil {
+		u.logger.Info("failed to fetch collectible image", zap.String("url", u.url.String()), zap.Error(err))
+	} else {
+		preview.Image = image
+	}
+
+	return &common.RichLinkPreview{URL: u.url.String(), Collectible: preview}, nil
+}
+
+func (u *CollectibleUnfurler) image(data *thirdparty.CollectibleData) (common.LinkPreviewThumbnail, error) {
+	var thumbnail common.LinkPreviewThumbnail
+
+	payload := data.ImagePayload
+	if len(payload) == 0 {
+		if !strings.HasPrefix(data.ImageURL, "https://") && !strings.HasPrefix(data.ImageURL, "http://") {
+			return thumbnail, fmt.Errorf("unsupported image url='%s'", data.ImageURL)
+		}
+		var err error
+		payload, err = fetchBody(u.logger, u.httpClient, data.ImageURL, map[string]string{"user-agent": headerUserAgent})
+		if err != nil {
+			return thumbnail, err
+		}
+	}
+	if !isSupportedImage(payload) {
+		return thumbnail, fmt.Errorf("unsupported image type")
+	}
+
+	compressed, err := compressImage(payload)
+	if err != nil {
+		return thumbnail, fmt.Errorf("failed to compress image: %w", err)
+	}
+	width, height, err := images.GetImageDimensions(compressed)
+	if err != nil {
+		return thumbnail, fmt.Errorf("could not get image dimensions: %w", err)
+	}
+	dataURI, err := images.GetPayloadDataURI(compressed)
+	if err != nil {
+		return thumbnail, fmt.Errorf("could not build data URI: %w", err)
+	}
+
+	thumbnail.Width = width
+	thumbnail.Height = height
+	thumbnail.DataURI = dataURI
+	return thumbnail, nil
+}
@@ -0,0 +1,134 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"regexp"
+	"strconv"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	gitHubAPIEndpoint  = "https://api.github.com"
+	headerAcceptGitHub = "application/vnd.github+json"
+)
+
+var gitHubIssueRegexp = regexp.MustCompile(`^/([^/]+)/([^/]+)/(issues|pull)/(\d+)/?$`)
+
+// gitHubIssueRef points to an issue or a pull request of a GitHub repository
+type gitHubIssueRef struct {
+	owner  string
+	repo   string
+	kind   protobuf.UnfurledGitHubLink_Kind
+	number uint64
+}
+
+func parseGitHubIssueURL(url *neturl.URL) (gitHubIssueRef, bool) {
+	if normalizeHostname(url.Hostname()) != "github.com" {
+		return gitHubIssueRef{}, false
+	}
+	matches := gitHubIssueRegexp.FindStringSubmatch(url.Path)
+	if matches == nil {
+		return gitHubIssueRef{}, false
+	}
+	number, err := strconv.ParseUint(matches[4], 10, 64)
+	if err != nil || number == 0 {
+		return gitHubIssueRef{}, false
+	}
+
+	ref := gitHubIssueRef{owner: matches[1], repo: matches[2], kind: protobuf.UnfurledGitHubLink_ISSUE, number: number}
+	if matches[3] == "pull" {
+		ref.kind = protobuf.UnfurledGitHubLink_PULL_REQUEST
+	}
+	return ref, true
+}
+
+// GitHubUnfurler unfurls issues and pull requests through the GitHub REST API,
+// which serves both from the issues endpoint
+type GitHubUnfurler struct {
+	url        *neturl.URL
+	ref        gitHubIssueRef
+	logger     *zap.Logger
+	httpClient *http.Client
+}
+
+func newGitHubUnfurler(URL *neturl.URL, ref gitHubIssueRef, logger *zap.Logger, httpClient *http.Client) *GitHubUnfurler {
+	return &GitHubUnfurler{
+		url:        URL,
+		ref:        ref,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+type gitHubIssueResponse struct {
+	Number uint64 `json:"number"`
+	Title  string `json:"title"`
+	State  string `json:"state"`
+	Draft  bool   `json:"draft"`
+	Labels []struct {
+		Name string `json:"name"`
+	} `json:"labels"`
+	User struct {
+		Login string `json:"login"`
+	} `json:"user"`
+	PullRequest *struct {
+		MergedAt *string `json:"merged_at"`
+	} `json:"pull_request"`
+}
+
+func (r *gitHubIssueResponse) state() protobuf.UnfurledGitHubLink_State {
+	switch {
+	case r.PullRequest != nil && r.PullRequest.MergedAt != nil:
+		return protobuf.UnfurledGitHubLink_MERGED
+	case r.State == "closed":
+		return protobuf.UnfurledGitHubLink_CLOSED
+	case r.Draft:
+		return protobuf.UnfurledGitHubLink_DRAFT
+	default:
+		return protobuf.UnfurledGitHubLink_OPEN
+	}
+}
+
+func (u *GitHubUnfurler) Unfurl() (*common.RichLinkPreview, error) {
+	apiURL := fmt.Sprintf("%s/repos/%s/%s/issues/%d",
+		gitHubAPIEndpoint, neturl.PathEscape(u.ref.owner), neturl.PathEscape(u.ref.repo), u.ref.number)
+
+	headers := map[string]string{
+		"accept":     headerAcceptGitHub,
+		"user-agent": headerUserAgent,
+	}
+	body, err := fetchBody(u.logger, u.httpClient, apiURL, headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var issue gitHubIssueResponse
+	err = json.Unmarshal(body, &issue)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse GitHub response: %w", err)
+	}
+	if issue.Title == "" {
+		return nil, fmt.Errorf("missing required title in GitHub response")
+	}
+
+	preview := &common.GitHubLinkPreview{
+		Repository: u.ref.owner + "/" + u.ref.repo,
+		Kind:       u.ref.kind,
+		Number:     u.ref.number,
+		Title:      issue.Title,
+		State:      issue.state(),
+		Author:     issue.User.Login,
+	}
+	for _, label := range issue.Labels {
+		preview.Labels = append(preview.Labels, label.Name)
+	}
+
+	return &common.RichLinkPreview{URL: u.url.String(), GitHub: preview}, nil
+}
@@ -0,0 +1,216 @@
+package protocol
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	neturl "net/url"
+	"regexp"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/services/wallet/token"
+)
+
+// blockExplorerChainIDs are the chains of the block explorers whose links are unfurled from the chain itself
+var blockExplorerChainIDs = map[string]uint64{
+	"etherscan.io":                  1,
+	"sepolia.etherscan.io":          11155111,
+	"optimistic.etherscan.io":       10,
+	"sepolia-optimism.etherscan.io": 11155420,
+	"arbiscan.io":                   42161,
+	"sepolia.arbiscan.io":           421614,
+	"basescan.org":                  8453,
+	"sepolia.basescan.org":          84532,
+}
+
+var (
+	explorerTransactionRegexp = regexp.MustCompile(`^/tx/(0x[0-9a-fA-F]{64})/?$`)
+	explorerAddressRegexp     = regexp.MustCompile(`^/(?:address|token)/(0x[0-9a-fA-F]{40})/?$`)
+
+	// erc20TransferSelector is the selector of transfer(address,uint256)
+	erc20TransferSelector = []byte{0xa9, 0x05, 0x9c, 0xbb}
+)
+
+// onChainReader is the part of an Ethereum client used to unfurl on-chain links
+type onChainReader interface {
+	TransactionByHash(ctx context.Context, hash gethcommon.Hash) (*gethtypes.Transaction, bool, error)
+	TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*gethtypes.Receipt, error)
+	BalanceAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) (*big.Int, error)
+	CodeAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) ([]byte, error)
+}
+
+// onChainTokenFinder returns the metadata of the tokens known to the wallet,
+// or reads it from their contract without adding them to the wallet
+type onChainTokenFinder interface {
+	FindTokenByAddress(chainID uint64, address gethcommon.Address) *token.Token
+	DiscoverToken(ctx context.Context, chainID uint64, address gethcommon.Address) (*token.Token, error)
+}
+
+// onChainRef points to a transaction or an address on a chain
+type onChainRef struct {
+	chainID     uint64
+	transaction *gethcommon.Hash
+	address     *gethcommon.Address
+}
+
+func parseBlockExplorerURL(url *neturl.URL) (onChainRef, bool) {
+	chainID, ok := blockExplorerChainIDs[normalizeHostname(url.Hostname())]
+	if !ok {
+		return onChainRef{}, false
+	}
+
+	if matches := explorerTransactionRegexp.FindStringSubmatch(url.Path); matches != nil {
+		hash := gethcommon.HexToHash(matches[1])
+		return onChainRef{chainID: chainID, transaction: &hash}, true
+	}
+	if matches := explorerAddressRegexp.FindStringSubmatch(url.Path); matches != nil {
+		address := gethcommon.HexToAddress(matches[1])
+		return onChainRef{chainID: chainID, address: &address}, true
+	}
+	return onChainRef{}, false
+}
+
+// OnChainUnfurler unfurls block explorer links to transactions and addresses by reading the chain
+// through our own RPC client, the block explorer itself is never contacted
+type OnChainUnfurler struct {
+	url    *neturl.URL
+	ref    onChainRef
+	logger *zap.Logger
+	reader onChainReader
+	tokens onChainTokenFinder
+}
+
+func newOnChainUnfurler(URL *neturl.URL, ref onChainRef, logger *zap.Logger, reader onChainReader, tokens onChainTokenFinder) *OnChainUnfurler {
+	return &OnChainUnfurler{
+		url:    URL,
+		ref:    ref,
+		logger: logger,
+		reader: reader,
+		tokens: tokens,
+	}
+}
+
+func (u *OnChainUnfurler) Unfurl() (*common.RichLinkPreview, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultRequestTimeout)
+	defer cancel()
+
+	preview := &common.RichLinkPreview{URL: u.url.String()}
+	var err error
+	if u.ref.transaction != nil {
+		preview.Transaction, err = u.unfurlTransaction(ctx, *u.ref.transaction)
+	} else {
+		preview.Address, err = u.unfurlAddress(ctx, *u.ref.address)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return preview, nil
+}
+
+func (u *OnChainUnfurler) findToken(ctx context.Context, address gethcommon.Address) *token.Token {
+	if u.tokens == nil {
+		return nil
+	}
+	if token := u.tokens.FindTokenByAddress(u.ref.chainID, address); token != nil {
+		return token
+	}
+	token, err := u.tokens.DiscoverToken(ctx, u.ref.chainID, address)
+	if err != nil {
+		u.logger.Debug("contract is not a token", zap.String("address", address.Hex()), zap.Error(err))
+		return nil
+	}
+	return token
+}
+
+func (u *OnChainUnfurler) unfurlTransaction(ctx context.Context, hash gethcommon.Hash) (*common.TransactionLinkPreview, error) {
+	tx, isPending, err := u.reader.TransactionByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get transaction: %w", err)
+	}
+
+	preview := &common.TransactionLinkPreview{
+		ChainID: u.ref.chainID,
+		Hash:    hash.Hex(),
+		Value:   tx.Value().String(),
+		Status:  protobuf.UnfurledTransactionLink_PENDING,
+	}
+
+	from, err := gethtypes.Sender(gethtypes.LatestSignerForChainID(new(big.Int).SetUint64(u.ref.chainID)), tx)
+	if err != nil {
+		u.logger.Info("failed to recover transaction sender", zap.Error(err))
+	} else {
+		preview.From = from.Hex()
+	}
+	if tx.To() != nil {
+		preview.To = tx.To().Hex()
+		preview.TokenTransfer = u.tokenTransfer(ctx, *tx.To(), tx.Data())
+	}
+
+	if !isPending {
+		receipt, err := u.reader.TransactionReceipt(ctx, hash)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get transaction receipt: %w", err)
+		}
+		preview.Status = protobuf.UnfurledTransactionLink_FAILED
+		if receipt.Status == gethtypes.ReceiptStatusSuccessful {
+			preview.Status = protobuf.UnfurledTransactionLink_SUCCESS
+		}
+		if receipt.BlockNumber != nil {
+			preview.BlockNumber = receipt.BlockNumber.Uint64()
+		}
+	}
+
+	return preview, nil
+}
+
+// tokenTransfer decodes the ERC20 transfer a transaction makes, nil when it makes none of a known token
+func (u *OnChainUnfurler) tokenTransfer(ctx context.Context, contract gethcommon.Address, data []byte) *common.TokenTransferPreview {
+	// selector, then the recipient and the amount as 32 bytes words
+	if len(data) != 4+2*32 || !bytes.Equal(data[:4], erc20TransferSelector) {
+		return nil
+	}
+	token := u.findToken(ctx, contract)
+	if token == nil {
+		return nil
+	}
+
+	return &common.TokenTransferPreview{
+		ContractAddress: contract.Hex(),
+		Symbol:          token.Symbol,
+		Decimals:        uint32(token.Decimals),
+		To:              gethcommon.BytesToAddress(data[4:36]).Hex(),
+		Amount:          new(big.Int).SetBytes(data[36:68]).String(),
+	}
+}
+
+func (u *OnChainUnfurler) unfurlAddress(ctx context.Context, address gethcommon.Address) (*common.AddressLinkPreview, error) {
+	balance, err := u.reader.BalanceAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get balance: %w", err)
+	}
+	code, err := u.reader.CodeAt(ctx, address, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get code: %w", err)
+	}
+
+	preview := &common.AddressLinkPreview{
+		ChainID:    u.ref.chainID,
+		Address:    address.Hex(),
+		IsContract: len(code) > 0,
+		Balance:    balance.String(),
+	}
+	if preview.IsContract {
+		if token := u.findToken(ctx, address); token != nil {
+			preview.TokenName = token.Name
+			preview.TokenSymbol = token.Symbol
+			preview.TokenDecimals = uint32(token.Decimals)
+		}
+	}
+	return preview, nil
+}
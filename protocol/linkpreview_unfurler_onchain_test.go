@@ -0,0 +1,193 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	neturl "net/url"
+	"testing"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+	gethtypes "github.com/ethereum/go-ethereum/core/types"
+	gethcrypto "github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/tt"
+	"github.com/status-im/status-go/services/wallet/token"
+)
+
+type stubOnChainReader struct {
+	transaction *gethtypes.Transaction
+	isPending   bool
+	receipt     *gethtypes.Receipt
+	balance     *big.Int
+	code        []byte
+}
+
+func (r *stubOnChainReader) TransactionByHash(ctx context.Context, hash gethcommon.Hash) (*gethtypes.Transaction, bool, error) {
+	if r.transaction == nil {
+		return nil, false, errors.New("not found")
+	}
+	return r.transaction, r.isPending, nil
+}
+
+func (r *stubOnChainReader) TransactionReceipt(ctx context.Context, txHash gethcommon.Hash) (*gethtypes.Receipt, error) {
+	return r.receipt, nil
+}
+
+func (r *stubOnChainReader) BalanceAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) (*big.Int, error) {
+	return r.balance, nil
+}
+
+func (r *stubOnChainReader) CodeAt(ctx context.Context, account gethcommon.Address, blockNumber *big.Int) ([]byte, error) {
+	return r.code, nil
+}
+
+type stubOnChainTokenFinder struct {
+	tokens map[gethcommon.Address]*token.Token
+}
+
+func (f *stubOnChainTokenFinder) FindTokenByAddress(chainID uint64, address gethcommon.Address) *token.Token {
+	return f.tokens[address]
+}
+
+func (f *stubOnChainTokenFinder) DiscoverToken(ctx context.Context, chainID uint64, address gethcommon.Address) (*token.Token, error) {
+	return nil, errors.New("not a token")
+}
+
+func TestParseBlockExplorerURL(t *testing.T) {
+	hash := "0x5c504ed432cb51138bcf09aa5e8a410dd4a1e204ef84bfed1be16dfba1b22060"
+	address := "0x744d70FDBE2Ba4CF95131626614a1763DF805B9E"
+
+	u, err := neturl.Parse("https://etherscan.io/tx/" + hash)
+	require.NoError(t, err)
+	ref, ok := parseBlockExplorerURL(u)
+	require.True(t, ok)
+	require.Equal(t, uint64(1), ref.chainID)
+	require.Equal(t, gethcommon.HexToHash(hash), *ref.transaction)
+	require.Nil(t, ref.address)
+
+	u, err = neturl.Parse("https://www.arbiscan.io/token/" + address)
+	require.NoError(t, err)
+	ref, ok = parseBlockExplorerURL(u)
+	require.True(t, ok)
+	require.Equal(t, uint64(42161), ref.chainID)
+	require.Equal(t, gethcommon.HexToAddress(address), *ref.address)
+	require.Nil(t, ref.transaction)
+
+	for _, invalid := range []string{
+		"https://etherscan.io/tx/0x1234",
+		"https://etherscan.io/blocks",
+		"https://unknown-explorer.io/tx/" + hash,
+	} {
+		u, err = neturl.Parse(invalid)
+		require.NoError(t, err)
+		_, ok = parseBlockExplorerURL(u)
+		require.False(t, ok, invalid)
+	}
+}
+
+func TestOnChainUnfurlerTransaction(t *testing.T) {
+	key, err := gethcrypto.GenerateKey()
+	require.NoError(t, err)
+	sender := gethcrypto.PubkeyToAddress(key.PublicKey)
+
+	contract := gethcommon.HexToAddress("0x744d70FDBE2Ba4CF95131626614a1763DF805B9E")
+	recipient := gethcommon.HexToAddress("0x0000000000000000000000000000000000000042")
+	amount := big.NewInt(1000)
+
+	// transfer(recipient, amount)
+	data := append([]byte{}, erc20TransferSelector...)
+	data = append(data, gethcommon.LeftPadBytes(recipient.Bytes(), 32)...)
+	data = append(data, gethcommon.LeftPadBytes(amount.Bytes(), 32)...)
+
+	chainID := big.NewInt(1)
+	tx, err := gethtypes.SignNewTx(key, gethtypes.LatestSignerForChainID(chainID), &gethtypes.DynamicFeeTx{
+		ChainID:   chainID,
+		To:        &contract,
+		Value:     big.NewInt(0),
+		Gas:       21000,
+		GasFeeCap: big.NewInt(1),
+		Data:      data,
+	})
+	require.NoError(t, err)
+
+	reader := &stubOnChainReader{
+		transaction: tx,
+		receipt:     &gethtypes.Receipt{Status: gethtypes.ReceiptStatusSuccessful, BlockNumber: big.NewInt(42)},
+	}
+	tokens := &stubOnChainTokenFinder{tokens: map[gethcommon.Address]*token.Token{
+		contract: {Address: contract, Name: "Status", Symbol: "SNT", Decimals: 18},
+	}}
+
+	u, err := neturl.Parse("https://etherscan.io/tx/" + tx.Hash().Hex())
+	require.NoError(t, err)
+	ref, ok := parseBlockExplorerURL(u)
+	require.True(t, ok)
+
+	preview, err := newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, tokens).Unfurl()
+	require.NoError(t, err)
+	require.Equal(t, u.String(), preview.URL)
+
+	transaction := preview.Transaction
+	require.NotNil(t, transaction)
+	require.Equal(t, tx.Hash().Hex(), transaction.Hash)
+	require.Equal(t, sender.Hex(), transaction.From)
+	require.Equal(t, contract.Hex(), transaction.To)
+	require.Equal(t, "0", transaction.Value)
+	require.Equal(t, protobuf.UnfurledTransactionLink_SUCCESS, transaction.Status)
+	require.Equal(t, uint64(42), transaction.BlockNumber)
+
+	require.NotNil(t, transaction.TokenTransfer)
+	require.Equal(t, "SNT", transaction.TokenTransfer.Symbol)
+	require.Equal(t, uint32(18), transaction.TokenTransfer.Decimals)
+	require.Equal(t, recipient.Hex(), transaction.TokenTransfer.To)
+	require.Equal(t, "1000", transaction.TokenTransfer.Amount)
+
+	// Pending transactions have no receipt yet
+	reader.isPending = true
+	reader.receipt = nil
+	preview, err = newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, tokens).Unfurl()
+	require.NoError(t, err)
+	require.Equal(t, protobuf.UnfurledTransactionLink_PENDING, preview.Transaction.Status)
+	require.Zero(t, preview.Transaction.BlockNumber)
+
+	// Transfers of unknown tokens are not decoded
+	preview, err = newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, nil).Unfurl()
+	require.NoError(t, err)
+	require.Nil(t, preview.Transaction.TokenTransfer)
+
+	reader.transaction = nil
+	_, err = newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, tokens).Unfurl()
+	require.Error(t, err)
+}
+
+func TestOnChainUnfurlerAddress(t *testing.T) {
+	contract := gethcommon.HexToAddress("0x744d70FDBE2Ba4CF95131626614a1763DF805B9E")
+	reader := &stubOnChainReader{balance: big.NewInt(7), code: []byte{0x60, 0x80}}
+	tokens := &stubOnChainTokenFinder{tokens: map[gethcommon.Address]*token.Token{
+		contract: {Address: contract, Name: "Status", Symbol: "SNT", Decimals: 18},
+	}}
+
+	u, err := neturl.Parse("https://etherscan.io/address/" + contract.Hex())
+	require.NoError(t, err)
+	ref, ok := parseBlockExplorerURL(u)
+	require.True(t, ok)
+
+	preview, err := newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, tokens).Unfurl()
+	require.NoError(t, err)
+	require.NotNil(t, preview.Address)
+	require.Equal(t, contract.Hex(), preview.Address.Address)
+	require.True(t, preview.Address.IsContract)
+	require.Equal(t, "7", preview.Address.Balance)
+	require.Equal(t, "Status", preview.Address.TokenName)
+	require.Equal(t, "SNT", preview.Address.TokenSymbol)
+	require.Equal(t, uint32(18), preview.Address.TokenDecimals)
+
+	reader.code = nil
+	preview, err = newOnChainUnfurler(u, ref, tt.MustCreateTestLogger(), reader, tokens).Unfurl()
+	require.NoError(t, err)
+	require.False(t, preview.Address.IsContract)
+	require.Empty(t, preview.Address.TokenSymbol)
+}
@@ -0,0 +1,255 @@
+package protocol
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	neturl "net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/net/html"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	xOEmbedEndpoint = "https://publish.twitter.com/oembed"
+	// xEpoch is the epoch of X post IDs, in milliseconds, the IDs start with the time they were created at
+	xEpoch = 1288834974657
+
+	// maxSocialPostTextLength is the number of characters of a post kept in its preview
+	maxSocialPostTextLength = 500
+)
+
+var (
+	xPostRegexp        = regexp.MustCompile(`^/([A-Za-z0-9_]+)/status/(\d+)/?$`)
+	mastodonPostRegexp = regexp.MustCompile(`^/@([A-Za-z0-9_]+)(@[^/]+)?/(\d+)/?$`)
+)
+
+// socialPostRef points to a post on X or on a Mastodon instance
+type socialPostRef struct {
+	platform protobuf.UnfurledSocialPostLink_Platform
+	host     string
+	id       string
+}
+
+// parseSocialPostURL returns the post a link points to, on X or on one of the given Mastodon instances
+func parseSocialPostURL(url *neturl.URL, mastodonInstances []string) (socialPostRef, bool) {
+	hostname := normalizeHostname(url.Hostname())
+	switch hostname {
+	case "x.com", "twitter.com", "mobile.twitter.com":
+		matches := xPostRegexp.FindStringSubmatch(url.Path)
+		if matches == nil {
+			return socialPostRef{}, false
+		}
+		return socialPostRef{platform: protobuf.UnfurledSocialPostLink_X, host: hostname, id: matches[2]}, true
+	}
+
+	// Mastodon instances can be hosted anywhere, only the known ones are asked for their posts,
+	// so that any site with a matching path isn't sent API requests
+	if !isMastodonInstance(hostname, mastodonInstances) {
+		return socialPostRef{}, false
+	}
+	matches := mastodonPostRegexp.FindStringSubmatch(url.Path)
+	if matches == nil {
+		return socialPostRef{}, false
+	}
+	return socialPostRef{platform: protobuf.UnfurledSocialPostLink_MASTODON, host: url.Host, id: matches[3]}, true
+}
+
+func isMastodonInstance(hostname string, instances []string) bool {
+	for _, instance := range instances {
+		if hostname == normalizeHostname(instance) {
+			return true
+		}
+	}
+	return false
+}
+
+// SocialPostUnfurler unfurls posts on X through its oEmbed endpoint,
+// and posts on Mastodon through the API of their instance
+type SocialPostUnfurler struct {
+	url        *neturl.URL
+	ref        socialPostRef
+	logger     *zap.Logger
+	httpClient *http.Client
+}
+
+func newSocialPostUnfurler(URL *neturl.URL, ref socialPostRef, logger *zap.Logger, httpClient *http.Client) *SocialPostUnfurler {
+	return &SocialPostUnfurler{
+		url:        URL,
+		ref:        ref,
+		logger:     logger,
+		httpClient: httpClient,
+	}
+}
+
+func (u *SocialPostUnfurler) Unfurl() (*common.RichLinkPreview, error) {
+	var post *common.SocialPostLinkPreview
+	var err error
+	switch u.ref.platform {
+	case protobuf.UnfurledSocialPostLink_X:
+		post, err = u.unfurlX()
+	default:
+		post, err = u.unfurlMastodon()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	post.Platform = u.ref.platform
+	post.Text = truncateText(post.Text, maxSocialPostTextLength)
+	return &common.RichLinkPreview{URL: u.url.String(), SocialPost: post}, nil
+}
+
+type xOEmbedResponse struct {
+	AuthorName string `json:"author_name"`
+	AuthorURL  string `json:"author_url"`
+	HTML       string `json:"html"`
+}
+
+func (u *SocialPostUnfurler) unfurlX() (*common.SocialPostLinkPreview, error) {
+	oembedURL, err := neturl.Parse(xOEmbedEndpoint)
+	if err != nil {
+		return nil, err
+	}
+	oembedURL.RawQuery = neturl.Values{
+		"url":         {u.url.String()},
+		"omit_script": {"true"},
+		"dnt":         {"true"},
+	}.Encode()
+
+	headers := map[string]string{
+		"accept":     headerAcceptJSON,
+		"user-agent": headerUserAgent,
+	}
+	body, err := fetchBody(u.logger, u.httpClient, oembedURL.String(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var response xOEmbedResponse
+	err = json.Unmarshal(body, &response)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse X oEmbed response: %w", err)
+	}
+
+	post := &common.SocialPostLinkPreview{
+		AuthorName: response.AuthorName,
+		Text:       firstParagraphText(response.HTML),
+	}
+	if authorURL, err := neturl.Parse(response.AuthorURL); err == nil && authorURL.Path != "" {
+		post.AuthorHandle = "@" + path.Base(authorURL.Path)
+	}
+	if post.AuthorHandle == "" && post.Text == "" {
+		return nil, fmt.Errorf("missing required author and text in X oEmbed response")
+	}
+
+	if id, err := strconv.ParseUint(u.ref.id, 10, 64); err == nil {
+		post.PostedAt = ((id >> 22) + xEpoch) / 1000
+	}
+	return post, nil
+}
+
+type mastodonStatusResponse struct {
+	Content   string `json:"content"`
+	CreatedAt string `json:"created_at"`
+	Account   struct {
+		DisplayName string `json:"display_name"`
+		Acct        string `json:"acct"`
+	} `json:"account"`
+}
+
+func (u *SocialPostUnfurler) unfurlMastodon() (*common.SocialPostLinkPreview, error) {
+	apiURL := neturl.URL{
+		Scheme: "https",
+		Host:   u.ref.host,
+		Path:   "/api/v1/statuses/" + u.ref.id,
+	}
+
+	headers := map[string]string{
+		"accept":     headerAcceptJSON,
+		"user-agent": headerUserAgent,
+	}
+	body, err := fetchBody(u.logger, u.httpClient, apiURL.String(), headers)
+	if err != nil {
+		return nil, err
+	}
+
+	var status mastodonStatusResponse
+	err = json.Unmarshal(body, &status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse Mastodon response: %w", err)
+	}
+	if status.Account.Acct == "" {
+		return nil, fmt.Errorf("missing required account in Mastodon response")
+	}
+
+	// Accounts of the instance itself come without their domain
+	handle := "@" + status.Account.Acct
+	if !strings.Contains(status.Account.Acct, "@") {
+		handle += "@" + u.ref.host
+	}
+
+	post := &common.SocialPostLinkPreview{
+		AuthorName:   status.Account.DisplayName,
+		AuthorHandle: handle,
+		Text:         htmlText(status.Content),
+	}
+	if createdAt, err := time.Parse(time.RFC3339, status.CreatedAt); err == nil {
+		post.PostedAt = uint64(createdAt.Unix())
+	}
+	return post, nil
+}
+
+// htmlText returns the text of an HTML fragment, paragraphs and line breaks are kept as new lines
+func htmlText(fragment string) string {
+	var text strings.Builder
+	tokenizer := html.NewTokenizer(strings.NewReader(fragment))
+	for {
+		switch tokenizer.Next() {
+		case html.ErrorToken:
+			return strings.TrimSpace(text.String())
+		case html.TextToken:
+			text.Write(tokenizer.Text())
+		case html.StartTagToken, html.SelfClosingTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == "br" {
+				text.WriteString("\n")
+			}
+		case html.EndTagToken:
+			name, _ := tokenizer.TagName()
+			if string(name) == "p" {
+				text.WriteString("\n\n")
+			}
+		}
+	}
+}
+
+// firstParagraphText returns the text of the first paragraph of an HTML fragment
+func firstParagraphText(fragment string) string {
+	start := strings.Index(fragment, "<p")
+	if start == -1 {
+		return ""
+	}
+	end := strings.Index(fragment[start:], "</p>")
+	if end == -1 {
+		return htmlText(fragment[start:])
+	}
+	return htmlText(fragment[start : start+end+len("</p>")])
+}
+
+// truncateText cuts a text to the given number of characters, marking the cut with an ellipsis
+func truncateText(text string, maxLength int) string {
+	runes := []rune(text)
+	if len(runes) <= maxLength {
+		return text
+	}
+	return string(runes[:maxLength-1]) + "…"
+}
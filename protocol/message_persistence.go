@@ -116,7 +116,14 @@ func (db sqlitePersistence) tableUserMessagesAllFields() string {
 		mentioned,
 		replied,
     	discord_message_id,
-		payment_requests`
+		payment_requests,
+		forwarded_from,
+		file_manifest,
+		video_manifest,
+		image_placeholder,
+		unfurled_rich_links,
+		audio_waveform,
+		media_hidden`
 }
 
 // keep the same order as in tableUserMessagesScanAllFields
@@ -150,6 +157,13 @@ func (db sqlitePersistence) tableUserMessagesAllFieldsJoin() string {
 		m1.unfurled_links,
 		m1.unfurled_status_links,
 		m1.payment_requests,
+		m1.forwarded_from,
+		m1.file_manifest,
+		m1.video_manifest,
+		m1.image_placeholder,
+		m1.unfurled_rich_links,
+		m1.audio_waveform,
+		m1.media_hidden,
 		m1.command_id,
 		m1.command_value,
 		m1.command_from,
@@ -246,6 +260,10 @@ func (db sqlitePersistence) tableUserMessagesScanAllFields(row scanner, message
 	var serializedUnfurledLinks []byte
 	var serializedUnfurledStatusLinks []byte
 	var serializedPaymentRequests []byte
+	var serializedForwardedFrom []byte
+	var serializedFileManifest []byte
+	var serializedVideoManifest []byte
+	var serializedUnfurledRichLinks []byte
 	var alias sql.NullString
 	var identicon sql.NullString
 	var communityID sql.NullString
@@ -307,6 +325,13 @@ func (db sqlitePersistence) tableUserMessagesScanAllFields(row scanner, message
 		&serializedUnfurledLinks,
 		&serializedUnfurledStatusLinks,
 		&serializedPaymentRequests,
+		&serializedForwardedFrom,
+		&serializedFileManifest,
+		&serializedVideoManifest,
+		&image.Placeholder,
+		&serializedUnfurledRichLinks,
+		&audio.Waveform,
+		&message.MediaHidden,
 		&command.ID,
 		&command.Value,
 		&command.From,
@@ -478,6 +503,15 @@ func (db sqlitePersistence) tableUserMessagesScanAllFields(row scanner, message
 		message.UnfurledStatusLinks = &links
 	}
 
+	if serializedUnfurledRichLinks != nil {
+		var links protobuf.UnfurledRichLinks
+		err = proto.Unmarshal(serializedUnfurledRichLinks, &links)
+		if err != nil {
+			return err
+		}
+		message.UnfurledRichLinks = &links
+	}
+
 	if serializedPaymentRequests != nil {
 		err := json.Unmarshal(serializedPaymentRequests, &message.PaymentRequests)
 		if err != nil {
@@ -485,6 +519,33 @@ func (db sqlitePersistence) tableUserMessagesScanAllFields(row scanner, message
 		}
 	}
 
+	if serializedForwardedFrom != nil {
+		var forwardedFrom protobuf.ForwardedFrom
+		err = proto.Unmarshal(serializedForwardedFrom, &forwardedFrom)
+		if err != nil {
+			return err
+		}
+		message.ForwardedFrom = &forwardedFrom
+	}
+
+	if serializedFileManifest != nil {
+		var fileMessage protobuf.FileMessage
+		err = proto.Unmarshal(serializedFileManifest, &fileMessage)
+		if err != nil {
+			return err
+		}
+		message.Payload = &protobuf.ChatMessage_File{File: &fileMessage}
+	}
+
+	if serializedVideoManifest != nil {
+		var videoMessage protobuf.VideoMessage
+		err = proto.Unmarshal(serializedVideoManifest, &videoMessage)
+		if err != nil {
+			return err
+		}
+		message.Payload = &protobuf.ChatMessage_Video{Video: &videoMessage}
+	}
+
 	if attachment.Id != "" {
 		discordMessage.Attachments = append(discordMessage.Attachments, attachment)
 	}
@@ -592,6 +653,14 @@ func (db sqlitePersistence) tableUserMessagesAllValues(message *common.Message)
 		}
 	}
 
+	var serializedUnfurledRichLinks []byte
+	if links := message.GetUnfurledRichLinks(); links != nil {
+		serializedUnfurledRichLinks, err = proto.Marshal(links)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	var serializedPaymentRequests []byte
 	if len(message.PaymentRequests) != 0 {
 		serializedPaymentRequests, err = json.Marshal(message.PaymentRequests)
@@ -600,6 +669,36 @@ func (db sqlitePersistence) tableUserMessagesAllValues(message *common.Message)
 		}
 	}
 
+	var serializedForwardedFrom []byte
+	if forwardedFrom := message.GetForwardedFrom(); forwardedFrom != nil {
+		serializedForwardedFrom, err = proto.Marshal(forwardedFrom)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the payload of a file is stored apart, see saveFileAttachment
+	var serializedFileManifest []byte
+	if file := message.GetFile(); file != nil {
+		manifest := proto.Clone(file).(*protobuf.FileMessage)
+		manifest.Payload = nil
+		serializedFileManifest, err = proto.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the payload of a video is stored apart as well, see saveVideoPayload
+	var serializedVideoManifest []byte
+	if video := message.GetVideo(); video != nil {
+		manifest := proto.Clone(video).(*protobuf.VideoMessage)
+		manifest.Payload = nil
+		serializedVideoManifest, err = proto.Marshal(manifest)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	return []interface{}{
 		message.ID,
 		message.WhisperTimestamp,
@@ -658,6 +757,13 @@ func (db sqlitePersistence) tableUserMessagesAllValues(message *common.Message)
 		message.Replied,
 		discordMessage.Id,
 		serializedPaymentRequests,
+		serializedForwardedFrom,
+		serializedFileManifest,
+		serializedVideoManifest,
+		image.Placeholder,
+		serializedUnfurledRichLinks,
+		audio.Waveform,
+		message.MediaHidden,
 	}, nil
 }
 
@@ -887,6 +993,39 @@ func (db sqlitePersistence) LatestMessageByChatID(chatID string) ([]*common.Mess
 	return result, nil
 }
 
+// LatestWhisperTimestampFromSender returns the whisper timestamp of the latest message
+// the sender posted in the chat before the given timestamp, excluding the message itself
+func (db sqlitePersistence) LatestWhisperTimestampFromSender(chatID string, from string, messageID string, before uint64) (uint64, error) {
+	var timestamp sql.NullInt64
+	err := db.db.QueryRow(`
+		SELECT MAX(whisper_timestamp)
+		FROM user_messages
+		WHERE local_chat_id = ? AND source = ? AND id != ? AND whisper_timestamp <= ? AND NOT(deleted)`,
+		chatID, from, messageID, before).Scan(&timestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(timestamp.Int64), nil
+}
+
+// FirstWhisperTimestampFromSenderInCommunity returns the whisper timestamp of the first message
+// the sender posted in any chat of the community, 0 if none
+func (db sqlitePersistence) FirstWhisperTimestampFromSenderInCommunity(communityID string, from string) (uint64, error) {
+	var timestamp sql.NullInt64
+	err := db.db.QueryRow(`
+		SELECT MIN(m.whisper_timestamp)
+		FROM user_messages AS m
+		INNER JOIN chats AS ch ON ch.id = m.local_chat_id AND ch.community_id = ?
+		WHERE m.source = ?`,
+		communityID, from).Scan(&timestamp)
+	if err != nil {
+		return 0, err
+	}
+
+	return uint64(timestamp.Int64), nil
+}
+
 func (db sqlitePersistence) latestIncomingMessageClock(chatID string) (uint64, error) {
 	var clock uint64
 	err := db.db.QueryRow(
@@ -1606,6 +1745,20 @@ func (db sqlitePersistence) SaveMessages(messages []*common.Message) (err error)
 			return
 		}
 
+		if msg.GetFile() != nil && len(msg.FilePayload) != 0 {
+			err = db.saveFileAttachment(tx, msg.ID, msg.FilePayload)
+			if err != nil {
+				return
+			}
+		}
+
+		if video := msg.GetVideo(); video != nil && len(video.Payload) != 0 {
+			err = db.saveVideoPayload(tx, msg.ID, video.Payload)
+			if err != nil {
+				return
+			}
+		}
+
 		if msg.ContentType == protobuf.ChatMessage_BRIDGE_MESSAGE {
 			// check updates first
 			var hasMessage bool
@@ -1779,6 +1932,12 @@ func (db sqlitePersistence) HideMessage(id string) error {
 	return err
 }
 
+// SetMessageMediaHidden hides or reveals the media of a message that matched a blocklist
+func (db sqlitePersistence) SetMessageMediaHidden(id string, hidden bool) error {
+	_, err := db.db.Exec(`UPDATE user_messages SET media_hidden = ? WHERE id = ?`, hidden, id)
+	return err
+}
+
 // SetHideOnMessage set the hide flag, but not the seen flag, as it's needed by the client to understand whether the count should be updated
 func (db sqlitePersistence) SetHideOnMessage(id string) error {
 	_, err := db.db.Exec(`UPDATE user_messages SET hide = 1 WHERE id = ?`, id)
@@ -2734,12 +2893,21 @@ func (db sqlitePersistence) SaveEdit(editMessage *EditMessage) error {
 		return nil
 	}
 
-	_, err := db.db.Exec(`INSERT INTO user_messages_edits (clock, chat_id, message_id, text, source, id, unfurled_links, unfurled_status_links) VALUES(?,?,?,?,?,?,?,?)`, editMessage.Clock, editMessage.ChatId, editMessage.MessageId, editMessage.Text, editMessage.From, editMessage.ID, pq.Array(editMessage.UnfurledLinks), editMessage.UnfurledStatusLinks)
+	var serializedUnfurledRichLinks []byte
+	if links := editMessage.GetUnfurledRichLinks(); links != nil {
+		var err error
+		serializedUnfurledRichLinks, err = proto.Marshal(links)
+		if err != nil {
+			return err
+		}
+	}
+
+	_, err := db.db.Exec(`INSERT INTO user_messages_edits (clock, chat_id, message_id, text, source, id, unfurled_links, unfurled_status_links, unfurled_rich_links) VALUES(?,?,?,?,?,?,?,?,?)`, editMessage.Clock, editMessage.ChatId, editMessage.MessageId, editMessage.Text, editMessage.From, editMessage.ID, pq.Array(editMessage.UnfurledLinks), editMessage.UnfurledStatusLinks, serializedUnfurledRichLinks)
 	return err
 }
 
 func (db sqlitePersistence) GetEdits(messageID string, from string) ([]*EditMessage, error) {
-	rows, err := db.db.Query(`SELECT clock, chat_id, message_id, source, text, id, unfurled_links, unfurled_status_links FROM user_messages_edits WHERE message_id = ? AND source = ? ORDER BY CLOCK DESC`, messageID, from)
+	rows, err := db.db.Query(`SELECT clock, chat_id, message_id, source, text, id, unfurled_links, unfurled_status_links, unfurled_rich_links FROM user_messages_edits WHERE message_id = ? AND source = ? ORDER BY CLOCK DESC`, messageID, from)
 	if err != nil {
 		return nil, err
 	}
@@ -2748,10 +2916,18 @@ func (db sqlitePersistence) GetEdits(messageID string, from string) ([]*EditMess
 	var messages []*EditMessage
 	for rows.Next() {
 		e := NewEditMessage()
-		err := rows.Scan(&e.Clock, &e.ChatId, &e.MessageId, &e.From, &e.Text, &e.ID, pq.Array(&e.UnfurledLinks), &e.UnfurledStatusLinks)
+		var serializedUnfurledRichLinks []byte
+		err := rows.Scan(&e.Clock, &e.ChatId, &e.MessageId, &e.From, &e.Text, &e.ID, pq.Array(&e.UnfurledLinks), &e.UnfurledStatusLinks, &serializedUnfurledRichLinks)
 		if err != nil {
 			return nil, err
 		}
+		if serializedUnfurledRichLinks != nil {
+			var links protobuf.UnfurledRichLinks
+			if err := proto.Unmarshal(serializedUnfurledRichLinks, &links); err != nil {
+				return nil, err
+			}
+			e.UnfurledRichLinks = &links
+		}
 		messages = append(messages, e)
 
 	}
@@ -3,15 +3,27 @@ package protocol
 import (
 	"errors"
 	"fmt"
+	neturl "net/url"
 	"strconv"
 	"strings"
 
+	"github.com/golang/protobuf/proto"
+
 	utils "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/images"
+	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/protobuf"
 	"github.com/status-im/status-go/protocol/v1"
+	"github.com/status-im/status-go/protocol/video"
 )
 
 const maxChatMessageTextLength = 4096
+
+// maxUnfurledRichLinkURLLength is the longest URL of a rich preview received
+const maxUnfurledRichLinkURLLength = 2048
+
+// maxUnfurledRichLinkSize is the largest rich preview received, the image of a collectible and its text
+const maxUnfurledRichLinkSize = maxImageSize + 16*1024
 const maxStatusMessageText = 128
 
 // maxWhisperDrift is how many milliseconds we allow the clock value to differ
@@ -79,9 +91,40 @@ func ValidateEditMessage(message *protobuf.EditMessage) error {
 		return errors.New("unknown message type")
 	}
 
+	if err := validateUnfurledRichLinks(message.UnfurledRichLinks); err != nil {
+		return err
+	}
+
 	return ValidateText(message.Text)
 }
 
+// validateUnfurledRichLinks bounds the rich previews sent along a message,
+// as many as the links unfurled per message, each of a bounded size and about an http(s) link
+func validateUnfurledRichLinks(links *protobuf.UnfurledRichLinks) error {
+	if len(links.GetUnfurledRichLinks()) > UnfurledLinksPerMessageLimit {
+		return fmt.Errorf("too many rich link previews, at most %d", UnfurledLinksPerMessageLimit)
+	}
+	for _, link := range links.GetUnfurledRichLinks() {
+		if link.Payload == nil {
+			return errors.New("rich link preview is empty")
+		}
+		if proto.Size(link) > maxUnfurledRichLinkSize {
+			return errors.New("rich link preview too large")
+		}
+		if len(link.Url) > maxUnfurledRichLinkURLLength {
+			return errors.New("rich link preview url too long")
+		}
+		url, err := neturl.Parse(link.Url)
+		if err != nil {
+			return fmt.Errorf("invalid rich link preview url: %w", err)
+		}
+		if (url.Scheme != "https" && url.Scheme != "http") || url.Host == "" {
+			return fmt.Errorf("unsupported rich link preview url '%s'", link.Url)
+		}
+	}
+	return nil
+}
+
 func ValidateDeleteMessage(message *protobuf.DeleteMessage) error {
 	if message == nil {
 		return errors.New("message can't be nil")
@@ -297,6 +340,13 @@ func ValidateReceivedChatMessage(message *protobuf.ChatMessage, whisperTimestamp
 		if image.Format == protobuf.ImageFormat_UNKNOWN_IMAGE_FORMAT {
 			return errors.New("image type unknown")
 		}
+		if len(image.Placeholder) > images.MaxPlaceholderSize {
+			return errors.New("image placeholder too large")
+		}
+		// placeholders are shown as data URIs, SVGs and unknown types are rejected
+		if len(image.Placeholder) != 0 && images.GetProtobufImageFormat(image.Placeholder) == protobuf.ImageFormat_UNKNOWN_IMAGE_FORMAT {
+			return errors.New("image placeholder type unknown")
+		}
 
 	case protobuf.ChatMessage_BRIDGE_MESSAGE:
 		if message.Payload == nil {
@@ -334,6 +384,50 @@ func ValidateReceivedChatMessage(message *protobuf.ChatMessage, whisperTimestamp
 		}
 	}
 
+	if message.ContentType == protobuf.ChatMessage_FILE {
+		file := message.GetFile()
+		if file == nil {
+			return errors.New("no file content")
+		}
+		if err := common.ValidateFileManifest(file); err != nil {
+			return err
+		}
+		// the payload is fetched on demand, a message carrying it would be downloaded by every recipient
+		if len(file.Payload) != 0 {
+			return errors.New("file payload sent in the message")
+		}
+	}
+
+	if message.ContentType == protobuf.ChatMessage_VIDEO {
+		videoMessage := message.GetVideo()
+		if videoMessage == nil {
+			return errors.New("no video content")
+		}
+		metadata, err := video.Validate(videoMessage.Payload)
+		if err != nil {
+			return err
+		}
+		if metadata.Format != videoMessage.Format {
+			return errors.New("video format mismatch")
+		}
+		// the metadata shown is the one sent, it must be the one of the container
+		if uint64(metadata.Duration.Milliseconds()) != videoMessage.DurationMs {
+			return errors.New("video duration mismatch")
+		}
+		if metadata.Width != videoMessage.Width || metadata.Height != videoMessage.Height {
+			return errors.New("video dimensions mismatch")
+		}
+		if len(videoMessage.Thumbnail) != 0 {
+			thumbnailFormat := images.GetProtobufImageFormat(videoMessage.Thumbnail)
+			if thumbnailFormat == protobuf.ImageFormat_UNKNOWN_IMAGE_FORMAT {
+				return errors.New("video thumbnail type unknown")
+			}
+			if thumbnailFormat != videoMessage.ThumbnailFormat {
+				return errors.New("video thumbnail format mismatch")
+			}
+		}
+	}
+
 	if message.ContentType == protobuf.ChatMessage_SYSTEM_MESSAGE_CONTENT_PRIVATE_GROUP {
 		return errors.New("private group system message content type not allowed")
 	}
@@ -344,6 +438,10 @@ func ValidateReceivedChatMessage(message *protobuf.ChatMessage, whisperTimestamp
 		return errors.New("mutual state event system message content type not allowed")
 	}
 
+	if err := validateUnfurledRichLinks(message.UnfurledRichLinks); err != nil {
+		return err
+	}
+
 	if err := utils.ValidateDisplayName(&message.DisplayName); err != nil {
 		return err
 	}
@@ -351,6 +449,25 @@ func ValidateReceivedChatMessage(message *protobuf.ChatMessage, whisperTimestamp
 	return nil
 }
 
+// ValidateCommunityChatMessageRate checks a community chat message against the sender's
+// timeout and the channel slow mode. Timestamps are in milliseconds, slowModeInterval in seconds.
+// previousTimestamp is the timestamp of the sender's previous message in the channel, 0 if none.
+func ValidateCommunityChatMessageRate(timedOut bool, slowModeInterval uint32, previousTimestamp uint64, timestamp uint64) error {
+	if timedOut {
+		return ErrMemberTimedOut
+	}
+
+	if slowModeInterval == 0 || previousTimestamp == 0 || previousTimestamp > timestamp {
+		return nil
+	}
+
+	if timestamp-previousTimestamp < uint64(slowModeInterval)*1000 {
+		return ErrSlowModeIntervalNotElapsed
+	}
+
+	return nil
+}
+
 func ValidateReceivedEmojiReaction(emoji *protobuf.EmojiReaction, whisperTimestamp uint64) error {
 	if err := validateClockValue(emoji.Clock, whisperTimestamp); err != nil {
 		return err
@@ -269,6 +269,59 @@ func (s *MessageValidatorSuite) TestValidatePlainTextMessage() {
 				ContentType: protobuf.ChatMessage_TRANSACTION_COMMAND,
 			},
 		},
+		{
+			Name:             "Valid rich link preview",
+			WhisperTimestamp: 2,
+			Valid:            true,
+			Message: &protobuf.ChatMessage{
+				ChatId:      "a",
+				Text:        "https://github.com/status-im/status-go/issues/1",
+				Clock:       2,
+				Timestamp:   3,
+				MessageType: protobuf.MessageType_ONE_TO_ONE,
+				ContentType: protobuf.ChatMessage_TEXT_PLAIN,
+				UnfurledRichLinks: &protobuf.UnfurledRichLinks{UnfurledRichLinks: []*protobuf.UnfurledRichLink{
+					{Url: "https://github.com/status-im/status-go/issues/1", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+				}},
+			},
+		},
+		{
+			Name:             "Invalid rich link preview url",
+			WhisperTimestamp: 2,
+			Valid:            false,
+			Message: &protobuf.ChatMessage{
+				ChatId:      "a",
+				Text:        "valid",
+				Clock:       2,
+				Timestamp:   3,
+				MessageType: protobuf.MessageType_ONE_TO_ONE,
+				ContentType: protobuf.ChatMessage_TEXT_PLAIN,
+				UnfurledRichLinks: &protobuf.UnfurledRichLinks{UnfurledRichLinks: []*protobuf.UnfurledRichLink{
+					{Url: "javascript:alert(1)", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+				}},
+			},
+		},
+		{
+			Name:             "Too many rich link previews",
+			WhisperTimestamp: 2,
+			Valid:            false,
+			Message: &protobuf.ChatMessage{
+				ChatId:      "a",
+				Text:        "valid",
+				Clock:       2,
+				Timestamp:   3,
+				MessageType: protobuf.MessageType_ONE_TO_ONE,
+				ContentType: protobuf.ChatMessage_TEXT_PLAIN,
+				UnfurledRichLinks: &protobuf.UnfurledRichLinks{UnfurledRichLinks: []*protobuf.UnfurledRichLink{
+					{Url: "https://github.com/status-im/status-go/issues/1", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+					{Url: "https://github.com/status-im/status-go/issues/2", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+					{Url: "https://github.com/status-im/status-go/issues/3", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+					{Url: "https://github.com/status-im/status-go/issues/4", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+					{Url: "https://github.com/status-im/status-go/issues/5", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+					{Url: "https://github.com/status-im/status-go/issues/6", Payload: &protobuf.UnfurledRichLink_Github{Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1}}},
+				}},
+			},
+		},
 		{
 			Name:             "Valid  emoji only emssage",
 			WhisperTimestamp: 2,
@@ -398,6 +451,28 @@ func (s *MessageValidatorSuite) TestValidatePlainTextMessage() {
 				ContentType: protobuf.ChatMessage_STICKER,
 			},
 		},
+		{
+			Name:             "Invalid image message, SVG placeholder",
+			WhisperTimestamp: 2,
+			Valid:            false,
+			Message: &protobuf.ChatMessage{
+				ChatId:     "a",
+				Text:       "valid",
+				Clock:      2,
+				Timestamp:  3,
+				ResponseTo: "",
+				EnsName:    "",
+				Payload: &protobuf.ChatMessage_Image{
+					Image: &protobuf.ImageMessage{
+						Format:      1,
+						Payload:     []byte("some-payload"),
+						Placeholder: []byte("<svg xmlns=\"http://www.w3.org/2000/svg\"></svg>"),
+					},
+				},
+				MessageType: protobuf.MessageType_ONE_TO_ONE,
+				ContentType: protobuf.ChatMessage_IMAGE,
+			},
+		},
 		{
 			Name:             "Invalid image message, missing payload",
 			WhisperTimestamp: 2,
@@ -639,3 +714,55 @@ func (s *MessageValidatorSuite) TestValidateEmojiReaction() {
 	}
 
 }
+
+func (s *MessageValidatorSuite) TestValidateCommunityChatMessageRate() {
+	testCases := []struct {
+		Name              string
+		TimedOut          bool
+		SlowModeInterval  uint32
+		PreviousTimestamp uint64
+		Timestamp         uint64
+		Err               error
+	}{
+		{
+			Name:      "no restrictions",
+			Timestamp: 1000,
+		},
+		{
+			Name:      "timed out member",
+			TimedOut:  true,
+			Timestamp: 1000,
+			Err:       ErrMemberTimedOut,
+		},
+		{
+			Name:             "slow mode without previous message",
+			SlowModeInterval: 10,
+			Timestamp:        1000,
+		},
+		{
+			Name:              "slow mode interval elapsed",
+			SlowModeInterval:  10,
+			PreviousTimestamp: 1000,
+			Timestamp:         11000,
+		},
+		{
+			Name:              "slow mode interval not elapsed",
+			SlowModeInterval:  10,
+			PreviousTimestamp: 1000,
+			Timestamp:         10999,
+			Err:               ErrSlowModeIntervalNotElapsed,
+		},
+		{
+			Name:              "previous message received out of order",
+			SlowModeInterval:  10,
+			PreviousTimestamp: 2000,
+			Timestamp:         1000,
+		},
+	}
+	for _, tc := range testCases {
+		s.Run(tc.Name, func() {
+			err := ValidateCommunityChatMessageRate(tc.TimedOut, tc.SlowModeInterval, tc.PreviousTimestamp, tc.Timestamp)
+			s.Require().Equal(tc.Err, err)
+		})
+	}
+}
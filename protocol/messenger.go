@@ -41,8 +41,10 @@ import (
 	"github.com/status-im/status-go/multiaccounts/accounts"
 	"github.com/status-im/status-go/multiaccounts/settings"
 	"github.com/status-im/status-go/protocol/anonmetrics"
+	"github.com/status-im/status-go/protocol/antispam"
 	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/communities"
+	"github.com/status-im/status-go/protocol/contentsafety"
 	"github.com/status-im/status-go/protocol/encryption"
 	"github.com/status-im/status-go/protocol/encryption/multidevice"
 	"github.com/status-im/status-go/protocol/encryption/sharedsecret"
@@ -70,6 +72,7 @@ import (
 	"github.com/status-im/status-go/services/wallet/token"
 	"github.com/status-im/status-go/signal"
 	"github.com/status-im/status-go/telemetry"
+	"github.com/status-im/status-go/tracing"
 )
 
 const (
@@ -137,6 +140,7 @@ type Messenger struct {
 	modifiedInstallations      *stringBoolMap
 	installationID             string
 	communityStorenodes        *storenodes.CommunityStorenodes
+	storenodeScores            *storenodes.Scores
 	database                   *sql.DB
 	multiAccounts              *multiaccounts.Database
 	settings                   *accounts.Database
@@ -187,10 +191,38 @@ type Messenger struct {
 	// enables control over chat messages iteration
 	retrievedMessagesIteratorFactory func(map[transport.Filter][]*types.Message) MessagesIterator
 
-	peersyncing         *peersyncing.PeerSyncing
+	peersyncing *peersyncing.PeerSyncing
+	// peersyncingMutex guards peersyncingOffers and peersyncingRequests
+	peersyncingMutex    sync.Mutex
 	peersyncingOffers   map[string]uint64
 	peersyncingRequests map[string]uint64
 
+	storenodePreferencesMutex sync.Mutex
+	storenodePreferences      map[string]StorenodePreference
+	// communityStorenodeScores holds, per community, the scores of its storenode and of the
+	// active fleet storenode checked against the messages of the community only, created on demand
+	communityStorenodeScores map[string]*storenodes.Scores
+	// failedStorenodes holds when the last query to a storenode failed, the storenodes answering again are removed
+	failedStorenodes map[peer.ID]time.Time
+
+	// fileDownloadsMutex guards fileDownloads
+	fileDownloadsMutex sync.Mutex
+	// fileDownloads holds the messages whose file is being downloaded, by hex encoded file hash
+	fileDownloads map[string][]fileDownload
+
+	// historyGapsMutex serializes the merging of overlapping history gaps and guards historyGapsFromOffers
+	historyGapsMutex sync.Mutex
+	// historyGapsFromOffers holds when a gap was last detected from datasync offers, per chat, in seconds
+	historyGapsFromOffers map[string]uint32
+
+	antispam            *antispam.Detector
+	antispamPersistence *antispam.Persistence
+
+	mediaSafety            *contentsafety.Filter
+	mediaSafetyPersistence *contentsafety.Persistence
+	// mediaHashing is taken while a received image is hashed, bounding the images hashed at once
+	mediaHashing chan struct{}
+
 	mvdsStatusChangeEvent chan datasyncnode.PeerStatusChangeEvent
 }
 
@@ -521,6 +553,22 @@ func NewMessenger(
 		return nil, fmt.Errorf("failed to build contact of ourself: %w", err)
 	}
 
+	antispamPersistence := antispam.NewPersistence(database)
+	antispamConfig, err := antispamPersistence.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	mediaSafetyPersistence := contentsafety.NewPersistence(database)
+	mediaSafetyConfig, err := mediaSafetyPersistence.GetConfig()
+	if err != nil {
+		return nil, err
+	}
+	mediaBlocklist, err := mediaSafetyPersistence.Blocklist()
+	if err != nil {
+		return nil, err
+	}
+
 	ctx, cancel := context.WithCancel(context.Background())
 
 	var telemetryClient *telemetry.Client
@@ -572,11 +620,18 @@ func NewMessenger(
 		peersyncing:             peersyncing.New(peersyncing.Config{Database: database, Timesource: transp}),
 		peersyncingOffers:       make(map[string]uint64),
 		peersyncingRequests:     make(map[string]uint64),
+		antispam:                antispam.NewDetector(antispamConfig),
+		antispamPersistence:     antispamPersistence,
+		mediaSafety:             contentsafety.NewFilter(mediaSafetyConfig, blockedHashes(mediaBlocklist)),
+		mediaSafetyPersistence:  mediaSafetyPersistence,
+		mediaHashing:            make(chan struct{}, maxConcurrentMediaHashes),
 		peerStore:               peerStore,
 		mvdsStatusChangeEvent:   make(chan datasyncnode.PeerStatusChangeEvent, 5),
 		verificationDatabase:    verification.NewPersistence(database),
 		mailserversDatabase:     c.mailserversDatabase,
 		communityStorenodes:     storenodes.NewCommunityStorenodes(storenodes.NewDB(database), logger),
+		storenodeScores:         storenodes.NewScores(storenodes.NewDB(database), logger),
+		storenodePreferences:    make(map[string]StorenodePreference),
 		account:                 c.account,
 		quit:                    make(chan struct{}),
 		ctx:                     ctx,
@@ -815,6 +870,9 @@ func (m *Messenger) Start() (*MessengerResponse, error) {
 		}
 	}
 	m.startPeerSyncingLoop()
+	m.startScheduledMessagesLoop()
+	m.startHistoryGapsLoop()
+	m.startMediaRetentionLoop()
 	m.startSyncSettingsLoop()
 	m.startSettingsChangesLoop()
 	m.startCommunityRekeyLoop()
@@ -848,8 +906,13 @@ func (m *Messenger) Start() (*MessengerResponse, error) {
 		return nil, err
 	}
 
+	if err := m.storenodeScores.ReloadFromDB(); err != nil {
+		return nil, err
+	}
+
 	go m.checkForMissingMessagesLoop()
 	go m.checkForStorenodeCycleSignals()
+	go m.scoreStorenodesLoop()
 
 	controlledCommunities, err := m.communitiesManager.Controlled()
 	if err != nil {
@@ -1335,6 +1398,8 @@ func (m *Messenger) adaptIdentityImageToProtobuf(img *images.IdentityImage) *pro
 		Payload:     img.Payload,
 		SourceType:  protobuf.IdentityImage_RAW_PAYLOAD, // TODO add ENS avatar handling to dedicated PR
 		ImageFormat: images.GetProtobufImageFormat(img.Payload),
+
+		AnimatedPayload: img.AnimatedPayload,
 	}
 }
 
@@ -1956,8 +2021,13 @@ func (m *Messenger) dispatchPairInstallationMessage(ctx context.Context, spec co
 	return spec, nil
 }
 
-func (m *Messenger) dispatchMessage(ctx context.Context, rawMessage common.RawMessage) (common.RawMessage, error) {
-	var err error
+func (m *Messenger) dispatchMessage(ctx context.Context, rawMessage common.RawMessage) (_ common.RawMessage, err error) {
+	ctx, span := tracing.Start(ctx, "Messenger.dispatchMessage",
+		tracing.String("chatID", rawMessage.LocalChatID),
+		tracing.String("messageType", rawMessage.MessageType.String()),
+	)
+	defer func() { span.EndWithError(err) }()
+
 	var id []byte
 	logger := m.logger.With(zap.String("site", "dispatchMessage"), zap.String("chatID", rawMessage.LocalChatID))
 	chat, ok := m.allChats.Load(rawMessage.LocalChatID)
@@ -2147,15 +2217,9 @@ func (m *Messenger) SendChatMessages(ctx context.Context, messages []*common.Mes
 	return &response, nil
 }
 
-// sendChatMessage takes a minimal message and sends it based on the corresponding chat
-func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message) (*MessengerResponse, error) {
-	displayName, err := m.settings.DisplayName()
-	if err != nil {
-		return nil, err
-	}
-
-	message.DisplayName = displayName
-
+// buildChatMessageContent resolves mentions and loads images, audio, shared communities
+// and link previews into the protobuf payload of the message
+func (m *Messenger) buildChatMessageContent(message *common.Message) error {
 	replacedText, err := m.mentionsManager.ReplaceWithPublicKey(message.ChatId, message.Text)
 	if err == nil {
 		message.Text = replacedText
@@ -2167,18 +2231,18 @@ func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message
 
 		err := message.LoadImage()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 	} else if len(message.CommunityID) != 0 {
 		community, err := m.communitiesManager.GetByIDString(message.CommunityID)
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		wrappedCommunity, err := community.ToProtocolMessageBytes()
 		if err != nil {
-			return nil, err
+			return err
 		}
 
 		message.Payload = &protobuf.ChatMessage_Community{Community: wrappedCommunity}
@@ -2188,27 +2252,72 @@ func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message
 	} else if len(message.AudioPath) != 0 {
 		err := message.LoadAudio()
 		if err != nil {
-			return nil, err
+			return err
+		}
+	} else if len(message.FilePath) != 0 {
+		err := message.LoadFile()
+		if err != nil {
+			return err
+		}
+	} else if len(message.VideoPath) != 0 {
+		err := message.LoadVideo()
+		if err != nil {
+			return err
+		}
+	} else if message.ContentType == protobuf.ChatMessage_IMAGE {
+		err := message.SanitizeImage()
+		if err != nil {
+			return err
+		}
+	} else if message.ContentType == protobuf.ChatMessage_AUDIO {
+		err := message.SetAudioMetadata()
+		if err != nil {
+			return err
 		}
 	}
 
 	// We consider link previews non-critical data, so we do not want to block
 	// messages from being sent.
+	// Previews already in the payload, as for scheduled messages, are kept.
 
 	unfurledLinks, err := message.ConvertLinkPreviewsToProto()
 	if err != nil {
 		m.logger.Error("failed to convert link previews", zap.Error(err))
-	} else {
+	} else if unfurledLinks != nil {
 		message.UnfurledLinks = unfurledLinks
 	}
 
 	unfurledStatusLinks, err := message.ConvertStatusLinkPreviewsToProto()
 	if err != nil {
 		m.logger.Error("failed to convert status link previews", zap.Error(err))
-	} else {
+	} else if unfurledStatusLinks != nil {
 		message.UnfurledStatusLinks = unfurledStatusLinks
 	}
 
+	unfurledRichLinks, err := message.ConvertRichLinkPreviewsToProto()
+	if err != nil {
+		m.logger.Error("failed to convert rich link previews", zap.Error(err))
+	} else if unfurledRichLinks != nil {
+		message.UnfurledRichLinks = unfurledRichLinks
+	}
+
+	return nil
+}
+
+// sendChatMessage takes a minimal message and sends it based on the corresponding chat
+func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message) (*MessengerResponse, error) {
+	displayName, err := m.settings.DisplayName()
+	if err != nil {
+		return nil, err
+	}
+
+	message.DisplayName = displayName
+
+	err = m.buildChatMessageContent(message)
+	if err != nil {
+		return nil, err
+	}
+
 	var response MessengerResponse
 
 	// A valid added chat is required.
@@ -2227,6 +2336,18 @@ func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message
 		return nil, err
 	}
 
+	if chat.CommunityChat() {
+		community, err := m.communitiesManager.GetByIDString(chat.CommunityID)
+		if err != nil {
+			return nil, err
+		}
+
+		err = m.validateCommunityChatMessageRate(community, chat, &m.identity.PublicKey, "", message.WhisperTimestamp, nil)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	err = m.addContactRequestPropagatedState(message)
 	if err != nil {
 		return nil, err
@@ -2304,6 +2425,12 @@ func (m *Messenger) sendChatMessage(ctx context.Context, message *common.Message
 		return m.peersyncing.Add(syncMessage)
 	}
 
+	// the payload of a file is published first, so that it can be fetched once the message is received
+	err = m.sendFilePayload(ctx, chat, message)
+	if err != nil {
+		return nil, err
+	}
+
 	rawMessage, err = m.dispatchMessage(ctx, rawMessage)
 	if err != nil {
 		return nil, err
@@ -2951,6 +3078,9 @@ type CurrentMessageState struct {
 	PublicKey *ecdsa.PublicKey
 
 	StatusMessage *v1protocol.StatusMessage
+
+	// ReleasedFromQuarantine is set for messages released from the anti-spam quarantine, which aren't checked again
+	ReleasedFromQuarantine bool
 }
 
 type ReceivedMessageState struct {
@@ -3195,8 +3325,10 @@ func (m *Messenger) handleImportedMessages(messagesToHandle map[transport.Filter
 			handleMessageResponse, err := m.sender.HandleMessages(shhMessage)
 			if err != nil {
 				logger.Info("failed to decode messages", zap.Error(err))
+				wakuMessagesProcessedCounter.WithLabelValues("failed").Inc()
 				continue
 			}
+			wakuMessagesProcessedCounter.WithLabelValues("decoded").Inc()
 			statusMessages := handleMessageResponse.StatusMessages
 
 			for _, msg := range statusMessages {
@@ -3329,6 +3461,11 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 	m.handleMessagesMutex.Lock()
 	defer m.handleMessagesMutex.Unlock()
 
+	start := time.Now()
+	defer func() {
+		retrievedMessagesProcessingDuration.Observe(time.Since(start).Seconds())
+	}()
+
 	messageState := m.buildMessageState()
 
 	logger := m.logger.With(zap.String("site", "RetrieveAll"))
@@ -3453,11 +3590,13 @@ func (m *Messenger) handleRetrievedMessages(chatWithMessages map[transport.Filte
 					if err != nil {
 						allMessagesProcessed = false
 						logger.Warn("failed to process protobuf", zap.String("type", msg.ApplicationLayer.Type.String()), zap.Error(err))
+						statusMessagesProcessedCounter.WithLabelValues("failed").Inc()
 						if m.unhandledMessagesTracker != nil {
 							m.unhandledMessagesTracker(msg, err)
 						}
 						continue
 					}
+					statusMessagesProcessedCounter.WithLabelValues("handled").Inc()
 					logger.Debug("Handled parsed message")
 
 				} else {
@@ -3911,11 +4050,21 @@ func (m *Messenger) prepareMessage(msg *common.Message, s *server.MediaServer) e
 	if msg.ContentType == protobuf.ChatMessage_AUDIO {
 		msg.AudioLocalURL = s.MakeAudioURL(msg.ID)
 	}
+	if msg.ContentType == protobuf.ChatMessage_FILE {
+		msg.FileLocalURL = s.MakeFileURL(msg.ID)
+	}
+	if msg.ContentType == protobuf.ChatMessage_VIDEO {
+		msg.VideoLocalURL = s.MakeVideoURL(msg.ID)
+		if len(msg.GetVideo().GetThumbnail()) != 0 {
+			msg.VideoThumbnailLocalURL = s.MakeVideoThumbnailURL(msg.ID)
+		}
+	}
 	if msg.ContentType == protobuf.ChatMessage_STICKER {
 		msg.StickerLocalURL = s.MakeStickerURL(msg.GetSticker().Hash)
 	}
 	msg.LinkPreviews = msg.ConvertFromProtoToLinkPreviews(s.MakeLinkPreviewThumbnailURL, s.MakeLinkPreviewFaviconURL)
 	msg.StatusLinkPreviews = msg.ConvertFromProtoToStatusLinkPreviews(s.MakeStatusLinkPreviewThumbnailURL)
+	msg.RichLinkPreviews = msg.ConvertFromProtoToRichLinkPreviews(s.MakeRichLinkPreviewImageURL)
 
 	return nil
 }
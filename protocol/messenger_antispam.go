@@ -0,0 +1,256 @@
+package protocol
+
+import (
+	"crypto/ecdsa"
+	"strings"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/antispam"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/communities"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// quarantineIfSpam runs a received community message through the anti-spam detector
+// and stores it in quarantine if flagged. Privileged and whitelisted senders are never checked.
+func (m *Messenger) quarantineIfSpam(community *communities.Community, chat *Chat, sender *ecdsa.PublicKey, message *common.Message) (bool, error) {
+	if !m.antispam.Config().Enabled {
+		return false, nil
+	}
+
+	if community.IsPrivilegedMember(sender) {
+		return false, nil
+	}
+
+	whitelisted, err := m.antispamPersistence.IsWhitelisted(message.From)
+	if err != nil {
+		return false, err
+	}
+	if whitelisted {
+		return false, nil
+	}
+
+	firstSeen, err := m.persistence.FirstWhisperTimestampFromSenderInCommunity(community.IDString(), message.From)
+	if err != nil {
+		return false, err
+	}
+
+	// A sender whose messages were all quarantined so far must still leave probation eventually
+	firstQuarantined, err := m.antispamPersistence.FirstQuarantinedTimestampFromSender(community.IDString(), message.From)
+	if err != nil {
+		return false, err
+	}
+	if firstSeen == 0 || firstQuarantined != 0 && firstQuarantined < firstSeen {
+		firstSeen = firstQuarantined
+	}
+	if firstSeen == 0 {
+		firstSeen = message.WhisperTimestamp
+	}
+
+	verdict := m.antispam.Check(antispam.Message{
+		ID:        message.ID,
+		SenderID:  message.From,
+		ChatID:    chat.ID,
+		Text:      message.Text,
+		Timestamp: message.WhisperTimestamp,
+		FirstSeen: firstSeen,
+	})
+	if !verdict.Spam {
+		return false, nil
+	}
+
+	payload, err := proto.Marshal(message.ChatMessage)
+	if err != nil {
+		return false, err
+	}
+
+	err = m.antispamPersistence.SaveQuarantinedMessage(&antispam.QuarantinedMessage{
+		ID:               message.ID,
+		ChatID:           chat.ID,
+		CommunityID:      community.IDString(),
+		SenderID:         message.From,
+		Text:             message.Text,
+		Payload:          payload,
+		WhisperTimestamp: message.WhisperTimestamp,
+		Reasons:          verdict.Reasons,
+		QuarantinedAt:    m.GetCurrentTimeInMillis(),
+	})
+	if err != nil {
+		return false, err
+	}
+
+	if m.antispam.Config().AutoReport && community.IsControlNode() {
+		err = m.autoReportSpam(community, chat, message, payload, verdict)
+		if err != nil {
+			m.logger.Warn("failed to report quarantined message", zap.String("messageID", message.ID), zap.Error(err))
+		}
+	}
+
+	return true, nil
+}
+
+func (m *Messenger) autoReportSpam(community *communities.Community, chat *Chat, message *common.Message, evidence []byte, verdict antispam.Verdict) error {
+	reasons := make([]string, 0, len(verdict.Reasons))
+	for _, reason := range verdict.Reasons {
+		reasons = append(reasons, string(reason))
+	}
+
+	clock, _ := m.getLastClockWithRelatedChat()
+	report := &protobuf.CommunityMessageReport{
+		Clock:       clock,
+		CommunityId: community.ID(),
+		ChatId:      chat.ID,
+		MessageId:   message.ID,
+		MemberId:    message.From,
+		Reason:      protobuf.CommunityMessageReport_SPAM,
+		Description: "quarantined: " + strings.Join(reasons, ", "),
+		Evidence:    evidence,
+	}
+
+	err := m.communitiesManager.SaveMessageReport(communities.NewMessageReport(m.IdentityPublicKeyString(), report))
+	if err != nil && err != communities.ErrOldMessageReport {
+		return err
+	}
+
+	return m.sendCommunityMessageReport(community, report)
+}
+
+func (m *Messenger) GetAntiSpamConfig() antispam.Config {
+	return m.antispam.Config()
+}
+
+func (m *Messenger) SetAntiSpamConfig(config antispam.Config) error {
+	err := m.antispamPersistence.SaveConfig(config)
+	if err != nil {
+		return err
+	}
+
+	m.antispam.SetConfig(config)
+	return nil
+}
+
+// GetQuarantinedMessages returns the messages held back as spam, for all communities if communityID is empty
+func (m *Messenger) GetQuarantinedMessages(communityID string) ([]*antispam.QuarantinedMessage, error) {
+	return m.antispamPersistence.QuarantinedMessages(communityID)
+}
+
+// ReleaseQuarantinedMessages moves the given quarantined messages into their chats. They're handled
+// as received messages, notifications and unread counts included, without being checked again.
+func (m *Messenger) ReleaseQuarantinedMessages(ids []string) (*MessengerResponse, error) {
+	state := m.buildMessageState()
+	var released []string
+	for _, id := range ids {
+		quarantined, err := m.antispamPersistence.QuarantinedMessage(id)
+		if err != nil {
+			return nil, err
+		}
+
+		state.CurrentMessageState, err = m.messageStateFromQuarantine(quarantined)
+		if err != nil {
+			return nil, err
+		}
+
+		err = m.handleChatMessage(state, false)
+		if err != nil {
+			m.logger.Warn("failed to release quarantined message", zap.String("messageID", id), zap.Error(err))
+			continue
+		}
+		released = append(released, id)
+	}
+
+	response, err := m.saveDataAndPrepareResponse(state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.antispamPersistence.DeleteQuarantinedMessages(released)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+func (m *Messenger) messageFromQuarantine(quarantined *antispam.QuarantinedMessage) (*common.Message, error) {
+	state, err := m.messageStateFromQuarantine(quarantined)
+	if err != nil {
+		return nil, err
+	}
+
+	message := &common.Message{
+		ID:               state.MessageID,
+		ChatMessage:      state.Message,
+		From:             state.Contact.ID,
+		Alias:            state.Contact.Alias,
+		SigPubKey:        state.PublicKey,
+		Identicon:        state.Contact.Identicon,
+		WhisperTimestamp: state.WhisperTimestamp,
+		LocalChatID:      quarantined.ChatID,
+	}
+
+	err = message.PrepareContent(m.myHexIdentity())
+	if err != nil {
+		return nil, err
+	}
+
+	return message, nil
+}
+
+func (m *Messenger) messageStateFromQuarantine(quarantined *antispam.QuarantinedMessage) (*CurrentMessageState, error) {
+	chatMessage := &protobuf.ChatMessage{}
+	err := proto.Unmarshal(quarantined.Payload, chatMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	contact, ok := m.allContacts.Load(quarantined.SenderID)
+	if !ok {
+		contact, err = buildContactFromPkString(quarantined.SenderID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	publicKey, err := contact.PublicKey()
+	if err != nil {
+		return nil, err
+	}
+
+	return &CurrentMessageState{
+		Message:                chatMessage,
+		MessageID:              quarantined.ID,
+		WhisperTimestamp:       quarantined.WhisperTimestamp,
+		Contact:                contact,
+		PublicKey:              publicKey,
+		ReleasedFromQuarantine: true,
+	}, nil
+}
+
+func (m *Messenger) DeleteQuarantinedMessages(ids []string) error {
+	return m.antispamPersistence.DeleteQuarantinedMessages(ids)
+}
+
+// WhitelistSpamSender exempts the sender from anti-spam checks and releases their quarantined messages
+func (m *Messenger) WhitelistSpamSender(senderID string) (*MessengerResponse, error) {
+	err := m.antispamPersistence.AddToWhitelist(senderID, m.GetCurrentTimeInMillis())
+	if err != nil {
+		return nil, err
+	}
+
+	ids, err := m.antispamPersistence.QuarantinedMessageIDsBySender(senderID)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.ReleaseQuarantinedMessages(ids)
+}
+
+func (m *Messenger) RemoveSpamSenderFromWhitelist(senderID string) error {
+	return m.antispamPersistence.RemoveFromWhitelist(senderID)
+}
+
+func (m *Messenger) GetSpamSenderWhitelist() ([]string, error) {
+	return m.antispamPersistence.Whitelist()
+}
@@ -0,0 +1,59 @@
+package protocol
+
+import (
+	"context"
+	"time"
+
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// audioTranscodeTimeout bounds the time spent transcoding an audio, queueing included
+const audioTranscodeTimeout = time.Minute
+
+// processReceivedAudio replaces the duration and the waveform claimed by the sender of an audio
+// with the ones read from its payload, then transcodes it in the background if it's AMR.
+// Audios that can't be read are kept without duration and waveform, clients still try to play them.
+func (m *Messenger) processReceivedAudio(message *common.Message) {
+	if message.ContentType != protobuf.ChatMessage_AUDIO {
+		return
+	}
+	audioMessage := message.GetAudio()
+	if audioMessage == nil {
+		return
+	}
+	if err := message.SetAudioMetadata(); err != nil {
+		m.logger.Info("failed to read audio metadata", zap.String("messageID", message.ID), zap.Error(err))
+		audioMessage.DurationMs = 0
+		audioMessage.Waveform = nil
+		return
+	}
+
+	if m.config.audioTranscoder == nil || audioMessage.Type != protobuf.AudioMessage_AMR {
+		return
+	}
+
+	messageID := message.ID
+	payload := audioMessage.Payload
+	go func() {
+		defer gocommon.LogOnPanic()
+		m.transcodeAudio(messageID, payload, audioMessage.Type)
+	}()
+}
+
+func (m *Messenger) transcodeAudio(messageID string, payload []byte, audioType protobuf.AudioMessage_AudioType) {
+	ctx, cancel := context.WithTimeout(m.ctx, audioTranscodeTimeout)
+	defer cancel()
+
+	transcode, err := m.config.audioTranscoder.Transcode(ctx, payload, audioType)
+	if err != nil {
+		m.logger.Warn("failed to transcode audio", zap.String("messageID", messageID), zap.Error(err))
+		return
+	}
+	if err := m.persistence.SaveAudioTranscode(messageID, transcode); err != nil {
+		m.logger.Error("failed to save audio transcode", zap.String("messageID", messageID), zap.Error(err))
+	}
+}
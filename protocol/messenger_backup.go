@@ -477,6 +477,7 @@ func (m *Messenger) backupProfile(ctx context.Context, clock uint64) ([]*protobu
 		p.Height = uint32(image.Height)
 		p.FileSize = uint32(image.FileSize)
 		p.ResizeTarget = uint32(image.ResizeTarget)
+		p.AnimatedPayload = image.AnimatedPayload
 		if image.Clock == 0 {
 			p.Clock = clock
 		} else {
@@ -170,6 +170,8 @@ func (m *Messenger) handleBackedUpProfile(message *protobuf.BackedUpProfile, bac
 					FileSize:     int(pic.FileSize),
 					ResizeTarget: int(pic.ResizeTarget),
 					Clock:        pic.Clock,
+
+					AnimatedPayload: pic.AnimatedPayload,
 				}
 				idImages[i] = img
 			}
@@ -3215,6 +3215,86 @@ func (m *Messenger) BanUserFromCommunity(ctx context.Context, request *requests.
 	return response, nil
 }
 
+func (m *Messenger) TimeoutUserFromCommunity(request *requests.TimeoutUserFromCommunity) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	community, err := m.communitiesManager.TimeoutUserFromCommunity(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddCommunity(community)
+	return response, nil
+}
+
+func (m *Messenger) RemoveUserTimeoutFromCommunity(request *requests.RemoveUserTimeoutFromCommunity) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	community, err := m.communitiesManager.RemoveUserTimeoutFromCommunity(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddCommunity(community)
+	return response, nil
+}
+
+func (m *Messenger) SetCommunityChannelSlowMode(request *requests.SetCommunityChannelSlowMode) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	community, changes, err := m.communitiesManager.SetCommunityChannelSlowMode(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddCommunity(community)
+	response.CommunityChanges = []*communities.CommunityChanges{changes}
+	return response, nil
+}
+
+// validateCommunityChatMessageRate enforces member timeouts and channel slow mode.
+// Privileged members are not subject to either.
+// Messages accepted earlier in the same receive batch are not persisted yet,
+// so they're looked up in the response being built, if any.
+func (m *Messenger) validateCommunityChatMessageRate(community *communities.Community, chat *Chat, sender *ecdsa.PublicKey, messageID string, timestamp uint64, response *MessengerResponse) error {
+	if community.IsPrivilegedMember(sender) {
+		return nil
+	}
+
+	var previousTimestamp uint64
+	slowModeInterval := community.ChatSlowModeInterval(chat.CommunityChatID())
+	if slowModeInterval > 0 {
+		var err error
+		from := common.PubkeyToHex(sender)
+		previousTimestamp, err = m.persistence.LatestWhisperTimestampFromSender(chat.ID, from, messageID, timestamp)
+		if err != nil {
+			return err
+		}
+
+		if response != nil {
+			for _, message := range response.Messages() {
+				if message.LocalChatID != chat.ID || message.From != from || message.ID == messageID || message.Deleted {
+					continue
+				}
+				if message.WhisperTimestamp <= timestamp && message.WhisperTimestamp > previousTimestamp {
+					previousTimestamp = message.WhisperTimestamp
+				}
+			}
+		}
+	}
+
+	return ValidateCommunityChatMessageRate(community.IsTimedOutAt(sender, timestamp), slowModeInterval, previousTimestamp, timestamp)
+}
+
 func (m *Messenger) AddRoleToMember(request *requests.AddRoleToMember) (*MessengerResponse, error) {
 	if err := request.Validate(); err != nil {
 		return nil, err
@@ -5169,7 +5249,7 @@ func (m *Messenger) getCommunityStorenode(communityID ...string) peer.ID {
 		return m.transport.GetActiveStorenode()
 	}
 
-	ms, err := m.communityStorenodes.GetStorenodeByCommunityID(communityID[0])
+	preference, err := m.communityStorenodePreference(communityID[0])
 	if err != nil {
 		if !errors.Is(err, storenodes.ErrNotFound) {
 			m.logger.Error("getting storenode for community, using global", zap.String("communityID", communityID[0]), zap.Error(err))
@@ -5178,11 +5258,10 @@ func (m *Messenger) getCommunityStorenode(communityID ...string) peer.ID {
 		return m.transport.GetActiveStorenode()
 	}
 
-	peerID, err := ms.PeerID()
-	if err != nil {
-		m.logger.Error("getting storenode for community, using global", zap.String("communityID", communityID[0]), zap.Error(err))
-		return m.transport.GetActiveStorenode()
+	// the community storenode is used unless the global one scores better, the backup
+	// storenode is used for a while when the primary one fails
+	if preference.Backup != "" && m.storenodeFailedRecently(preference.Primary) {
+		return preference.Backup
 	}
-
-	return peerID
+	return preference.Primary
 }
@@ -1082,7 +1082,7 @@ func (m *Messenger) RequestImportDiscordCommunity(request *requests.ImportDiscor
 
 		imgs := discordCommunity.Images()
 		for t, i := range imgs {
-			importProgress.CommunityImages[t] = images.IdentityImage{Name: t, Payload: i.Payload}
+			importProgress.CommunityImages[t] = images.IdentityImage{Name: t, Payload: i.Payload, AnimatedPayload: i.AnimatedPayload}
 		}
 
 		importProgress.UpdateTaskProgress(discord.CommunityCreationTask, 1)
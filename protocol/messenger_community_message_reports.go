@@ -0,0 +1,323 @@
+package protocol
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"database/sql"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/antispam"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/communities"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/requests"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+var ErrCannotReportOwnMessage = errors.New("can't report own message")
+var ErrNotCommunityMessage = errors.New("message does not belong to a community chat")
+var ErrReportedMessageNotFound = errors.New("reported message not found")
+var ErrReportedMessageMismatch = errors.New("reported message doesn't match the report")
+
+// ReportCommunityMessage sends a report of the given message to the control node
+// and the privileged members of the community the message was posted in
+func (m *Messenger) ReportCommunityMessage(request *requests.ReportCommunityMessage) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	message, err := m.persistence.MessageByID(request.MessageID)
+	if err != nil {
+		return nil, err
+	}
+
+	if message.From == m.myHexIdentity() {
+		return nil, ErrCannotReportOwnMessage
+	}
+
+	chat, ok := m.allChats.Load(message.LocalChatID)
+	if !ok {
+		return nil, ErrChatNotFound
+	}
+
+	if !chat.CommunityChat() {
+		return nil, ErrNotCommunityMessage
+	}
+
+	community, err := m.communitiesManager.GetByIDString(chat.CommunityID)
+	if err != nil {
+		return nil, err
+	}
+
+	evidence, err := messageReportEvidence(message.ChatMessage)
+	if err != nil {
+		return nil, err
+	}
+
+	clock, _ := m.getLastClockWithRelatedChat()
+	report := &protobuf.CommunityMessageReport{
+		Clock:       clock,
+		CommunityId: community.ID(),
+		ChatId:      chat.ID,
+		MessageId:   message.ID,
+		MemberId:    message.From,
+		Reason:      request.Reason,
+		Description: request.Description,
+		Evidence:    evidence,
+	}
+
+	err = m.sendCommunityMessageReport(community, report)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MessengerResponse{}, nil
+}
+
+// messageReportEvidence serializes the reported message, without its attachment when the message
+// is too large to be attached to the report, or not at all when it is still too large
+func messageReportEvidence(message *protobuf.ChatMessage) ([]byte, error) {
+	evidence, err := proto.Marshal(message)
+	if err != nil || len(evidence) <= communities.MaxMessageReportEvidenceSize {
+		return evidence, err
+	}
+
+	stripped := proto.Clone(message).(*protobuf.ChatMessage)
+	stripped.Payload = nil
+	evidence, err = proto.Marshal(stripped)
+	if err != nil || len(evidence) <= communities.MaxMessageReportEvidenceSize {
+		return evidence, err
+	}
+
+	return nil, nil
+}
+
+func (m *Messenger) sendCommunityMessageReport(community *communities.Community, report *protobuf.CommunityMessageReport) error {
+	payload, err := proto.Marshal(report)
+	if err != nil {
+		return err
+	}
+
+	rawMessage := common.RawMessage{
+		Payload:      payload,
+		CommunityID:  community.ID(),
+		ResendType:   common.ResendTypeDataSync,
+		ResendMethod: common.ResendMethodSendPrivate,
+		MessageType:  protobuf.ApplicationMetadataMessage_COMMUNITY_MESSAGE_REPORT,
+		Recipients:   m.messageReportRecipients(community),
+	}
+
+	for _, recipient := range rawMessage.Recipients {
+		_, err := m.sender.SendPrivate(context.Background(), recipient, &rawMessage)
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// messageReportRecipients returns the control node and the privileged members, without ourselves
+func (m *Messenger) messageReportRecipients(community *communities.Community) []*ecdsa.PublicKey {
+	seen := map[string]struct{}{m.IdentityPublicKeyString(): {}}
+	var recipients []*ecdsa.PublicKey
+
+	candidates := append([]*ecdsa.PublicKey{community.ControlNode()}, community.GetPrivilegedMembers()...)
+	for _, pk := range candidates {
+		if pk == nil {
+			continue
+		}
+		key := common.PubkeyToHex(pk)
+		if _, exists := seen[key]; exists {
+			continue
+		}
+		seen[key] = struct{}{}
+		recipients = append(recipients, pk)
+	}
+
+	return recipients
+}
+
+func (m *Messenger) HandleCommunityMessageReport(state *ReceivedMessageState, message *protobuf.CommunityMessageReport, statusMessage *v1protocol.StatusMessage) error {
+	logger := m.logger.Named("HandleCommunityMessageReport")
+
+	community, err := m.communitiesManager.GetByID(message.CommunityId)
+	if err != nil {
+		return err
+	}
+
+	if !community.IsControlNode() && !community.IsPrivilegedMember(m.IdentityPublicKey()) {
+		return communities.ErrNotAuthorized
+	}
+
+	reporter := state.CurrentMessageState.PublicKey
+	if !community.HasMember(reporter) {
+		return communities.ErrMemberNotFound
+	}
+
+	if err := communities.ValidateMessageReport(message); err != nil {
+		return err
+	}
+
+	reportedMessage, err := m.reportedMessage(community.ID(), message.ChatId, message.MessageId, message.MemberId)
+	if err != nil {
+		logger.Warn("skipping message report", zap.String("messageID", message.MessageId), zap.Error(err))
+		return err
+	}
+
+	report := communities.NewMessageReport(common.PubkeyToHex(reporter), message)
+	err = m.communitiesManager.SaveMessageReport(report)
+	if err == communities.ErrOldMessageReport {
+		logger.Debug("skipping outdated message report", zap.String("reportID", report.ID.String()))
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	state.Response.AddCommunityMessageReport(report)
+
+	notification := &ActivityCenterNotification{
+		ID:          report.ID,
+		Type:        ActivityCenterNotificationTypeCommunityMessageReport,
+		Timestamp:   m.getTimesource().GetCurrentTime(),
+		CommunityID: community.IDString(),
+		ChatID:      report.ChatID,
+		Author:      report.ReporterID,
+		Read:        false,
+		Deleted:     false,
+		UpdatedAt:   m.GetCurrentTimeInMillis(),
+		Message:     reportedMessage,
+	}
+
+	return m.addActivityCenterNotification(state.Response, notification, nil)
+}
+
+// reportedMessage returns the local copy of a reported message, checking that it was posted
+// by the reported member in the reported chat of the community.
+// Reports only carry ids chosen by the reporter, so nothing in them is trusted otherwise.
+func (m *Messenger) reportedMessage(communityID types.HexBytes, chatID string, messageID string, memberID string) (*common.Message, error) {
+	message, err := m.persistence.MessageByID(messageID)
+	if err == sql.ErrNoRows || err == common.ErrRecordNotFound {
+		message, err = m.reportedQuarantinedMessage(messageID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if message.From != memberID || message.LocalChatID != chatID {
+		return nil, ErrReportedMessageMismatch
+	}
+
+	chat, ok := m.allChats.Load(chatID)
+	if !ok || !chat.CommunityChat() || chat.CommunityID != types.EncodeHex(communityID) {
+		return nil, ErrReportedMessageMismatch
+	}
+
+	return message, nil
+}
+
+// reportedQuarantinedMessage returns a reported message held back as spam, control nodes
+// report the messages they quarantine
+func (m *Messenger) reportedQuarantinedMessage(messageID string) (*common.Message, error) {
+	quarantined, err := m.antispamPersistence.QuarantinedMessage(messageID)
+	if err == antispam.ErrQuarantinedMessageNotFound {
+		return nil, ErrReportedMessageNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	return m.messageFromQuarantine(quarantined)
+}
+
+func (m *Messenger) GetCommunityMessageReports(communityID types.HexBytes, status communities.MessageReportStatus) ([]*communities.MessageReport, error) {
+	return m.communitiesManager.GetMessageReports(communityID, status)
+}
+
+// ResolveCommunityMessageReport applies the moderation action to the reported message
+// and closes all open reports of that message
+func (m *Messenger) ResolveCommunityMessageReport(ctx context.Context, request *requests.ResolveCommunityMessageReport) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	report, err := m.communitiesManager.GetMessageReport(request.ReportID)
+	if err != nil {
+		return nil, err
+	}
+
+	if request.Action != requests.CommunityMessageReportActionDismiss {
+		_, err = m.reportedMessage(report.CommunityID, report.ChatID, report.MessageID, report.MemberID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &MessengerResponse{}
+	status := communities.MessageReportStatusActioned
+
+	switch request.Action {
+	case requests.CommunityMessageReportActionDismiss:
+		status = communities.MessageReportStatusDismissed
+
+	case requests.CommunityMessageReportActionDeleteMessage:
+		response, err = m.DeleteCommunityMemberMessages(&requests.DeleteCommunityMemberMessages{
+			CommunityID:  report.CommunityID,
+			MemberPubKey: report.MemberID,
+			Messages: []*protobuf.DeleteCommunityMemberMessage{{
+				Id:     report.MessageID,
+				ChatId: report.ChatID,
+			}},
+		})
+		if err != nil {
+			return nil, err
+		}
+
+	case requests.CommunityMessageReportActionBanMember:
+		response, err = m.BanUserFromCommunity(ctx, &requests.BanUserFromCommunity{
+			CommunityID:       report.CommunityID,
+			User:              types.FromHex(report.MemberID),
+			DeleteAllMessages: request.DeleteAllMessages,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.communitiesManager.ResolveMessageReports(report.CommunityID, report.MessageID, status, m.IdentityPublicKeyString())
+	if err != nil {
+		return nil, err
+	}
+
+	reports, err := m.communitiesManager.GetMessageReports(report.CommunityID, status)
+	if err != nil {
+		return nil, err
+	}
+
+	var notificationIDs []types.HexBytes
+	for _, r := range reports {
+		if r.MessageID == report.MessageID {
+			response.AddCommunityMessageReport(r)
+			notificationIDs = append(notificationIDs, r.ID)
+		}
+	}
+
+	if len(notificationIDs) > 0 {
+		readResponse, err := m.MarkActivityCenterNotificationsRead(ctx, notificationIDs, 0, true)
+		if err != nil {
+			return nil, err
+		}
+
+		err = response.Merge(readResponse)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return response, nil
+}
@@ -3,6 +3,7 @@ package protocol
 import (
 	"database/sql"
 	"encoding/json"
+	"net/http"
 	"time"
 
 	"github.com/ethereum/go-ethereum/event"
@@ -21,6 +22,7 @@ import (
 	"github.com/status-im/status-go/multiaccounts/settings"
 	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/protocol/anonmetrics"
+	"github.com/status-im/status-go/protocol/audio"
 	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/communities"
 	"github.com/status-im/status-go/protocol/discord"
@@ -121,6 +123,17 @@ type config struct {
 	messageResendMinDelay time.Duration
 	messageResendMaxCount int
 
+	// mediaRetentionPeriod and mediaRetentionMaxSize bound the payloads of files and videos kept
+	mediaRetentionPeriod  time.Duration
+	mediaRetentionMaxSize int64
+
+	// linkPreviewFetchConfig and linkPreviewTransport describe how the unfurlers reach the sites they preview
+	linkPreviewFetchConfig LinkPreviewFetchConfig
+	linkPreviewTransport   http.RoundTripper
+
+	// audioTranscoder, when set, transcodes the AMR audios received to AAC for the clients unable to play them
+	audioTranscoder audio.Transcoder
+
 	communityManagerOptions []communities.ManagerOption
 
 	accountsFeed *event.Feed
@@ -130,6 +143,10 @@ func messengerDefaultConfig() config {
 	c := config{
 		messageResendMinDelay: 30 * time.Second,
 		messageResendMaxCount: 3,
+		mediaRetentionPeriod:  defaultMediaRetentionPeriod,
+		mediaRetentionMaxSize: defaultMediaRetentionMaxSize,
+
+		linkPreviewFetchConfig: DefaultLinkPreviewFetchConfig(),
 	}
 
 	c.codeControlFlags.AutoRequestHistoricMessages = true
@@ -170,6 +187,37 @@ func WithResendParams(minDelay time.Duration, maxCount int) Option {
 	}
 }
 
+// WithMediaRetention sets how long and how much of the payloads of files and videos are kept
+func WithMediaRetention(period time.Duration, maxSize int64) Option {
+	return func(c *config) error {
+		c.mediaRetentionPeriod = period
+		c.mediaRetentionMaxSize = maxSize
+		return nil
+	}
+}
+
+// WithAudioTranscoder sets the transcoder of the AMR audios received, see audio.NewFFmpegTranscoder
+func WithAudioTranscoder(transcoder audio.Transcoder) Option {
+	return func(c *config) error {
+		c.audioTranscoder = transcoder
+		return nil
+	}
+}
+
+// WithLinkPreviewFetchConfig sets the proxy or the relay the link previews are fetched through,
+// along with the domains fetched, the size and time budget and how long the previews are cached
+func WithLinkPreviewFetchConfig(fetchConfig LinkPreviewFetchConfig) Option {
+	return func(c *config) error {
+		transport, err := newLinkPreviewTransport(fetchConfig)
+		if err != nil {
+			return err
+		}
+		c.linkPreviewFetchConfig = fetchConfig
+		c.linkPreviewTransport = transport
+		return nil
+	}
+}
+
 func WithDatabase(db *sql.DB) Option {
 	return func(c *config) error {
 		c.appDb = db
@@ -779,6 +779,9 @@ func (m *Messenger) updateContactImagesURL(contact *Contact) error {
 				return err
 			}
 			v.LocalURL = m.httpServer.MakeContactImageURL(common.PubkeyToHex(publicKey), k)
+			if len(v.AnimatedPayload) > 0 {
+				v.AnimatedLocalURL = m.httpServer.MakeAnimatedContactImageURL(common.PubkeyToHex(publicKey), k)
+			}
 			contact.Images[k] = v
 		}
 	}
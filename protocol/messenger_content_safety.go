@@ -0,0 +1,332 @@
+package protocol
+
+import (
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/eth-node/crypto"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/communities"
+	"github.com/status-im/status-go/protocol/contentsafety"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/requests"
+)
+
+// maxConcurrentMediaHashes bounds the received images hashed at once, receiving waits past it
+const maxConcurrentMediaHashes = 2
+
+var ErrNotImageMessage = errors.New("message has no image")
+
+// hideIfBlockedMedia hashes the image of a received message in the background and hides it if it
+// matches the local blocklist or the one of the community it was received in. At most
+// maxConcurrentMediaHashes images are hashed at once.
+// Hashing and matching happen on device, nothing about the media is sent anywhere.
+func (m *Messenger) hideIfBlockedMedia(chat *Chat, message *common.Message) {
+	image := message.GetImage()
+	if message.ContentType != protobuf.ChatMessage_IMAGE || image == nil {
+		return
+	}
+
+	if !m.mediaSafety.Config().Enabled {
+		return
+	}
+
+	hidden := &contentsafety.HiddenMedia{
+		MessageID: message.ID,
+		ChatID:    chat.ID,
+		SenderID:  message.From,
+	}
+	if chat.ChatType == ChatTypeCommunityChat {
+		hidden.CommunityID = chat.CommunityID
+	}
+	payload := image.Payload
+	placeholder := image.Placeholder
+
+	select {
+	case m.mediaHashing <- struct{}{}:
+	case <-m.quit:
+		return
+	}
+	go func() {
+		defer gocommon.LogOnPanic()
+		defer func() { <-m.mediaHashing }()
+		err := m.checkBlockedMedia(hidden, payload, placeholder)
+		if err != nil {
+			m.logger.Error("failed to check received media", zap.String("messageID", hidden.MessageID), zap.Error(err))
+		}
+	}()
+}
+
+// hashImageMedia hashes an image, or its placeholder when it's too large to be decoded
+func hashImageMedia(payload []byte, placeholder []byte) ([]contentsafety.Hash, error) {
+	hashes, err := contentsafety.HashMedia(payload)
+	if errors.Is(err, contentsafety.ErrMediaTooLarge) && len(placeholder) > 0 {
+		return contentsafety.HashMedia(placeholder)
+	}
+	return hashes, err
+}
+
+func (m *Messenger) checkBlockedMedia(hidden *contentsafety.HiddenMedia, payload []byte, placeholder []byte) error {
+	var community *communities.Community
+	var communityBlocklist []contentsafety.Hash
+	if hidden.CommunityID != "" {
+		communityID, err := types.DecodeHex(hidden.CommunityID)
+		if err != nil {
+			return err
+		}
+
+		community, err = m.GetCommunityByID(communityID)
+		if err != nil {
+			return err
+		}
+		if community != nil {
+			communityBlocklist = contentsafety.HashesFromProtobuf(community.MediaBlocklist())
+		}
+	}
+
+	hashes, err := hashImageMedia(payload, placeholder)
+	if err != nil {
+		m.logger.Warn("failed to hash received media", zap.String("messageID", hidden.MessageID), zap.Error(err))
+		return nil
+	}
+
+	match := m.mediaSafety.Check(hashes, communityBlocklist)
+	if match == nil {
+		return nil
+	}
+
+	hidden.Match = *match
+	hidden.HiddenAt = m.GetCurrentTimeInMillis()
+	response, err := m.hideMedia(hidden)
+	if err != nil {
+		return err
+	}
+	m.PublishMessengerResponse(response)
+
+	if m.mediaSafety.Config().AutoReport && community != nil {
+		err = m.autoReportHiddenMedia(community, hidden)
+		if err != nil {
+			m.logger.Warn("failed to report hidden media", zap.String("messageID", hidden.MessageID), zap.Error(err))
+		}
+	}
+
+	return nil
+}
+
+// hideMedia hides the media of a message. The message may not be saved yet when its media is
+// hashed, in which case the media is hidden as it's saved, the hidden media being recorded first.
+func (m *Messenger) hideMedia(hidden *contentsafety.HiddenMedia) (*MessengerResponse, error) {
+	err := m.mediaSafetyPersistence.SaveHiddenMedia(hidden)
+	if err != nil {
+		return nil, err
+	}
+
+	err = m.persistence.SetMessageMediaHidden(hidden.MessageID, true)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	message, err := m.persistence.MessageByID(hidden.MessageID)
+	if err != nil && err != common.ErrRecordNotFound {
+		return nil, err
+	}
+	if message != nil {
+		response.AddMessage(message)
+	}
+
+	notification := &ActivityCenterNotification{
+		ID:          hiddenMediaNotificationID(hidden.MessageID),
+		Type:        ActivityCenterNotificationTypeHiddenMedia,
+		Timestamp:   m.getTimesource().GetCurrentTime(),
+		CommunityID: hidden.CommunityID,
+		ChatID:      hidden.ChatID,
+		Author:      hidden.SenderID,
+		Message:     message,
+		Read:        false,
+		Deleted:     false,
+		UpdatedAt:   m.GetCurrentTimeInMillis(),
+	}
+	err = m.addActivityCenterNotification(response, notification, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	return response, nil
+}
+
+// hiddenMediaNotificationID doesn't collide with the mention and reply notifications of the same message
+func hiddenMediaNotificationID(messageID string) types.HexBytes {
+	return crypto.Keccak256([]byte(fmt.Sprintf("%s-hidden-media", messageID)))
+}
+
+// autoReportHiddenMedia reports the message to the community moderators.
+// Only the matched hash is included, the media itself is not attached as evidence.
+func (m *Messenger) autoReportHiddenMedia(community *communities.Community, hidden *contentsafety.HiddenMedia) error {
+	clock, _ := m.getLastClockWithRelatedChat()
+	report := &protobuf.CommunityMessageReport{
+		Clock:       clock,
+		CommunityId: community.ID(),
+		ChatId:      hidden.ChatID,
+		MessageId:   hidden.MessageID,
+		MemberId:    hidden.SenderID,
+		Reason:      protobuf.CommunityMessageReport_ILLEGAL_CONTENT,
+		Description: fmt.Sprintf("media matched %s blocklist: %s", hidden.Match.Source, hidden.Match.Hash),
+	}
+
+	err := m.communitiesManager.SaveMessageReport(communities.NewMessageReport(m.IdentityPublicKeyString(), report))
+	if err != nil && err != communities.ErrOldMessageReport {
+		return err
+	}
+
+	return m.sendCommunityMessageReport(community, report)
+}
+
+func (m *Messenger) GetContentSafetyConfig() contentsafety.Config {
+	return m.mediaSafety.Config()
+}
+
+// SetContentSafetyConfig saves the config, its MaxDistance clamped to contentsafety.MaxDistanceLimit
+func (m *Messenger) SetContentSafetyConfig(config contentsafety.Config) error {
+	config.Clamp()
+	err := m.mediaSafetyPersistence.SaveConfig(config)
+	if err != nil {
+		return err
+	}
+
+	m.mediaSafety.SetConfig(config)
+	return nil
+}
+
+func (m *Messenger) GetMediaBlocklist() ([]contentsafety.BlockedHash, error) {
+	return m.mediaSafetyPersistence.Blocklist()
+}
+
+func (m *Messenger) AddToMediaBlocklist(hashes []contentsafety.BlockedHash) error {
+	now := m.GetCurrentTimeInMillis()
+	for i := range hashes {
+		if err := hashes[i].Hash.Validate(); err != nil {
+			return err
+		}
+		if hashes[i].AddedAt == 0 {
+			hashes[i].AddedAt = now
+		}
+	}
+
+	err := m.mediaSafetyPersistence.AddToBlocklist(hashes)
+	if err != nil {
+		return err
+	}
+
+	return m.reloadMediaBlocklist()
+}
+
+func (m *Messenger) RemoveFromMediaBlocklist(hashes []contentsafety.Hash) error {
+	err := m.mediaSafetyPersistence.RemoveFromBlocklist(hashes)
+	if err != nil {
+		return err
+	}
+
+	return m.reloadMediaBlocklist()
+}
+
+// BlockMessageMedia adds the hashes of the image of a stored message to the local blocklist
+func (m *Messenger) BlockMessageMedia(messageID string, label string) error {
+	message, err := m.persistence.MessageByID(messageID)
+	if err != nil {
+		return err
+	}
+
+	if message.ContentType != protobuf.ChatMessage_IMAGE || message.GetImage() == nil {
+		return ErrNotImageMessage
+	}
+
+	hashes, err := hashImageMedia(message.GetImage().Payload, message.GetImage().Placeholder)
+	if err != nil {
+		return err
+	}
+
+	blocked := make([]contentsafety.BlockedHash, 0, len(hashes))
+	for _, hash := range hashes {
+		blocked = append(blocked, contentsafety.BlockedHash{Hash: hash, Label: label})
+	}
+
+	return m.AddToMediaBlocklist(blocked)
+}
+
+func (m *Messenger) reloadMediaBlocklist() error {
+	blocklist, err := m.mediaSafetyPersistence.Blocklist()
+	if err != nil {
+		return err
+	}
+
+	m.mediaSafety.SetBlocklist(blockedHashes(blocklist))
+	return nil
+}
+
+func blockedHashes(blocklist []contentsafety.BlockedHash) []contentsafety.Hash {
+	hashes := make([]contentsafety.Hash, 0, len(blocklist))
+	for _, blocked := range blocklist {
+		hashes = append(hashes, blocked.Hash)
+	}
+	return hashes
+}
+
+// GetHiddenMedia returns the media hidden as blocked, for all chats if communityID is empty
+func (m *Messenger) GetHiddenMedia(communityID string) ([]*contentsafety.HiddenMedia, error) {
+	return m.mediaSafetyPersistence.HiddenMedia(communityID)
+}
+
+// RevealHiddenMedia shows the media of a message that was hidden as blocked
+func (m *Messenger) RevealHiddenMedia(messageID string) (*MessengerResponse, error) {
+	message, err := m.persistence.MessageByID(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	// the hidden media is deleted first, as messages saved while it's recorded stay hidden
+	err = m.mediaSafetyPersistence.DeleteHiddenMedia(messageID)
+	if err != nil {
+		return nil, err
+	}
+
+	message.MediaHidden = false
+	err = m.persistence.SetMessageMediaHidden(messageID, false)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddMessage(message)
+	return response, nil
+}
+
+// SetCommunityMediaBlocklist replaces the media blocklist of a community. Only its control node can set it,
+// the blocklist isn't part of the community events admins publish.
+func (m *Messenger) SetCommunityMediaBlocklist(request *requests.SetCommunityMediaBlocklist) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	community, err := m.communitiesManager.SetCommunityMediaBlocklist(request)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddCommunity(community)
+	return response, nil
+}
+
+func (m *Messenger) GetCommunityMediaBlocklist(communityID types.HexBytes) ([]contentsafety.Hash, error) {
+	community, err := m.communitiesManager.GetByID(communityID)
+	if err != nil {
+		return nil, err
+	}
+
+	return contentsafety.HashesFromProtobuf(community.MediaBlocklist()), nil
+}
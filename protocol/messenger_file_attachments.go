@@ -0,0 +1,259 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"os"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"go.uber.org/zap"
+
+	"github.com/waku-org/go-waku/waku/v2/api/history"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/transport"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+const (
+	// fileDownloadWindow is how long before and after its message the payload of a file is looked up in the store nodes
+	fileDownloadWindow = time.Hour
+	// fileDownloadTimeout is how long the payload of a file is waited for once requested from the store nodes
+	fileDownloadTimeout = 2 * time.Minute
+)
+
+// fileDownload is a message waiting for the payload of its file
+type fileDownload struct {
+	messageID string
+	// topic is the topic the payload is published on, its filter is removed once no download needs it
+	topic string
+}
+
+var ErrFileAttachmentNotFound = errors.New("file attachment not found")
+
+// SaveFileAttachment decrypts the file sent in a message and writes it to the given path.
+// The file is checked against the size and hash of its manifest before being written.
+func (m *Messenger) SaveFileAttachment(messageID string, path string) error {
+	message, err := m.persistence.MessageByID(messageID)
+	if err != nil {
+		return err
+	}
+
+	manifest := message.GetFile()
+	if manifest == nil || message.Deleted || message.DeletedForMe {
+		return ErrFileAttachmentNotFound
+	}
+
+	payload, err := m.persistence.FileAttachmentPayload(messageID)
+	if err != nil {
+		return err
+	}
+	if payload == nil {
+		return ErrFileAttachmentNotFound
+	}
+
+	content, err := common.DecryptFile(manifest, payload)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(path, content, 0600)
+}
+
+// sendFilePayload publishes the encrypted content of the file of a message on the topic of the file,
+// on the pubsub topic of the chat so that it's kept by the same store nodes as the message
+func (m *Messenger) sendFilePayload(ctx context.Context, chat *Chat, message *common.Message) error {
+	manifest := message.GetFile()
+	if manifest == nil || len(message.FilePayload) == 0 {
+		return nil
+	}
+
+	pubsubTopic, _, err := m.topicsForChat(chat.ID)
+	if err != nil {
+		return err
+	}
+
+	payload, err := proto.Marshal(&protobuf.FilePayload{Hash: manifest.Hash, Payload: message.FilePayload})
+	if err != nil {
+		return err
+	}
+
+	topic := common.FilePayloadTopic(manifest)
+	_, err = m.sender.SendPublic(ctx, topic, common.RawMessage{
+		LocalChatID: topic,
+		Payload:     payload,
+		MessageType: protobuf.ApplicationMetadataMessage_FILE_PAYLOAD,
+		PubsubTopic: pubsubTopic,
+	})
+	return err
+}
+
+// DownloadFileAttachment fetches the payload of the file of a message from the store nodes.
+// The payload is stored once received and checked, the message is then sent in a messenger response.
+func (m *Messenger) DownloadFileAttachment(messageID string) error {
+	message, err := m.persistence.MessageByID(messageID)
+	if err != nil {
+		return err
+	}
+
+	manifest := message.GetFile()
+	if manifest == nil || message.Deleted || message.DeletedForMe {
+		return ErrFileAttachmentNotFound
+	}
+
+	payload, err := m.persistence.FileAttachmentPayload(messageID)
+	if err != nil {
+		return err
+	}
+	if payload != nil {
+		return nil
+	}
+
+	chat, ok := m.allChats.Load(message.LocalChatID)
+	if !ok {
+		return ErrChatNotFound
+	}
+	pubsubTopic, _, err := m.topicsForChat(chat.ID)
+	if err != nil {
+		return err
+	}
+
+	// the filter is removed once the payload is received, see HandleFilePayload, or once the download timed out
+	topic := common.FilePayloadTopic(manifest)
+	filter, err := m.transport.JoinPublicOnPubsubTopic(topic, pubsubTopic)
+	if err != nil {
+		return err
+	}
+
+	m.fileDownloadsMutex.Lock()
+	if m.fileDownloads == nil {
+		m.fileDownloads = make(map[string][]fileDownload)
+	}
+	hash := types.EncodeHex(manifest.Hash)
+	m.fileDownloads[hash] = append(m.fileDownloads[hash], fileDownload{messageID: messageID, topic: topic})
+	m.fileDownloadsMutex.Unlock()
+
+	isDownload := func(download fileDownload) bool { return download.messageID == messageID }
+
+	sentAt := time.UnixMilli(int64(message.WhisperTimestamp))
+	batch := types.MailserverBatch{
+		From:        sentAt.Add(-fileDownloadWindow),
+		To:          sentAt.Add(fileDownloadWindow),
+		PubsubTopic: filter.PubsubTopic,
+		Topics:      []types.TopicType{filter.ContentTopic},
+	}
+
+	peerID := m.getCommunityStorenode(chat.CommunityID)
+	_, err = m.performStorenodeTask(func() (*MessengerResponse, error) {
+		return nil, m.processMailserverBatch(peerID, batch)
+	}, history.WithPeerID(peerID))
+	if err != nil {
+		m.removeFileDownloads(hash, isDownload)
+		return err
+	}
+
+	go func() {
+		defer gocommon.LogOnPanic()
+		select {
+		case <-time.After(fileDownloadTimeout):
+			m.removeFileDownloads(hash, isDownload)
+		case <-m.quit:
+		}
+	}()
+
+	return nil
+}
+
+// removeFileDownloads stops waiting for the payload of the given hash for the selected downloads,
+// and removes the filters of the topics no download is waiting on anymore
+func (m *Messenger) removeFileDownloads(hash string, selected func(fileDownload) bool) {
+	m.fileDownloadsMutex.Lock()
+	var remaining []fileDownload
+	removedTopics := make(map[string]bool)
+	for _, download := range m.fileDownloads[hash] {
+		if selected(download) {
+			removedTopics[download.topic] = true
+			continue
+		}
+		remaining = append(remaining, download)
+	}
+	if len(remaining) == 0 {
+		delete(m.fileDownloads, hash)
+	} else {
+		m.fileDownloads[hash] = remaining
+	}
+	for _, downloads := range m.fileDownloads {
+		for _, download := range downloads {
+			delete(removedTopics, download.topic)
+		}
+	}
+	m.fileDownloadsMutex.Unlock()
+
+	for topic := range removedTopics {
+		if filter := m.transport.FilterByChatID(topic); filter != nil {
+			if err := m.transport.RemoveFilters([]*transport.Filter{filter}); err != nil {
+				m.logger.Warn("failed to remove file filter", zap.Error(err))
+			}
+		}
+	}
+}
+
+// HandleFilePayload stores the payload of a file being downloaded, after checking it against the
+// manifest of the messages waiting for it. Payloads nobody is waiting for are ignored, and so are
+// invalid payloads, the messages keep waiting for a valid one.
+func (m *Messenger) HandleFilePayload(state *ReceivedMessageState, message *protobuf.FilePayload, statusMessage *v1protocol.StatusMessage) error {
+	hash := types.EncodeHex(message.Hash)
+
+	m.fileDownloadsMutex.Lock()
+	downloads := append([]fileDownload(nil), m.fileDownloads[hash]...)
+	m.fileDownloadsMutex.Unlock()
+
+	done := make(map[string]bool)
+	defer func() {
+		if len(done) > 0 {
+			m.removeFileDownloads(hash, func(download fileDownload) bool { return done[download.messageID] })
+		}
+	}()
+
+	for _, download := range downloads {
+		if done[download.messageID] {
+			continue
+		}
+
+		chatMessage, err := m.persistence.MessageByID(download.messageID)
+		if err == common.ErrRecordNotFound {
+			done[download.messageID] = true
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		manifest := chatMessage.GetFile()
+		if manifest == nil || chatMessage.Deleted || chatMessage.DeletedForMe {
+			done[download.messageID] = true
+			continue
+		}
+
+		err = common.ValidateFilePayload(manifest, message.Payload)
+		if err == nil {
+			_, err = common.DecryptFile(manifest, message.Payload)
+		}
+		if err != nil {
+			m.logger.Warn("ignoring invalid file payload", zap.String("messageID", download.messageID), zap.Error(err))
+			continue
+		}
+
+		if err := m.persistence.SaveFileAttachment(download.messageID, message.Payload); err != nil {
+			return err
+		}
+		done[download.messageID] = true
+		state.Response.AddMessage(chatMessage)
+	}
+
+	return nil
+}
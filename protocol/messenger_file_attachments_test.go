@@ -0,0 +1,72 @@
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func TestMessengerFileAttachmentsSuite(t *testing.T) {
+	suite.Run(t, new(MessengerFileAttachmentsSuite))
+}
+
+type MessengerFileAttachmentsSuite struct {
+	MessengerBaseTestSuite
+}
+
+func (s *MessengerFileAttachmentsSuite) TestHandleFilePayloadSkipsInvalidPayloads() {
+	file, err := common.EncryptFile("spec.pdf", []byte("some-spec"))
+	s.Require().NoError(err)
+
+	manifest := &protobuf.FileMessage{Name: file.Name, Size: file.Size, MimeType: file.MimeType, Hash: file.Hash, Key: file.Key}
+	err = s.m.persistence.SaveMessages([]*common.Message{{
+		ID:          "message-id",
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			ContentType: protobuf.ChatMessage_FILE,
+			Payload:     &protobuf.ChatMessage_File{File: manifest},
+		},
+		From: testPK,
+	}})
+	s.Require().NoError(err)
+
+	hash := types.EncodeHex(file.Hash)
+	s.m.fileDownloads = map[string][]fileDownload{
+		hash: {{messageID: "message-id", topic: common.FilePayloadTopic(manifest)}},
+	}
+
+	// a payload that doesn't match the manifest is ignored, the message keeps waiting
+	state := &ReceivedMessageState{Response: &MessengerResponse{}}
+	err = s.m.HandleFilePayload(state, &protobuf.FilePayload{Hash: file.Hash, Payload: []byte("invalid")}, nil)
+	s.Require().NoError(err)
+	s.Require().Len(s.m.fileDownloads[hash], 1)
+	s.Require().Empty(state.Response.Messages())
+
+	err = s.m.HandleFilePayload(state, &protobuf.FilePayload{Hash: file.Hash, Payload: file.Payload}, nil)
+	s.Require().NoError(err)
+	s.Require().Empty(s.m.fileDownloads)
+	s.Require().Len(state.Response.Messages(), 1)
+
+	payload, err := s.m.persistence.FileAttachmentPayload("message-id")
+	s.Require().NoError(err)
+	s.Require().Equal(file.Payload, payload)
+}
+
+func (s *MessengerFileAttachmentsSuite) TestRemoveFileDownloads() {
+	s.m.fileDownloads = map[string][]fileDownload{
+		"hash": {
+			{messageID: "message-1", topic: "topic-1"},
+			{messageID: "message-2", topic: "topic-2"},
+		},
+	}
+
+	s.m.removeFileDownloads("hash", func(download fileDownload) bool { return download.messageID == "message-1" })
+	s.Require().Equal([]fileDownload{{messageID: "message-2", topic: "topic-2"}}, s.m.fileDownloads["hash"])
+
+	s.m.removeFileDownloads("hash", func(download fileDownload) bool { return true })
+	s.Require().Empty(s.m.fileDownloads)
+}
@@ -0,0 +1,201 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/requests"
+)
+
+var ErrMessageNotForwardable = errors.New("message can't be forwarded")
+
+var forwardableContentTypes = map[protobuf.ChatMessage_ContentType]bool{
+	protobuf.ChatMessage_TEXT_PLAIN: true,
+	protobuf.ChatMessage_EMOJI:      true,
+	protobuf.ChatMessage_STICKER:    true,
+	protobuf.ChatMessage_IMAGE:      true,
+	protobuf.ChatMessage_AUDIO:      true,
+}
+
+// ForwardMessages sends copies of the given messages to each of the target chats.
+// Images and audio are sent from the payloads in the database, images selected from the same album
+// are forwarded together in a new album.
+func (m *Messenger) ForwardMessages(ctx context.Context, request *requests.ForwardMessages) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	groups, err := m.messagesToForward(request.MessageIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var targets []*Chat
+	for _, chatID := range request.ChatIDs {
+		chat, ok := m.allChats.Load(chatID)
+		if !ok {
+			return nil, ErrChatNotFoundError
+		}
+		targets = append(targets, chat)
+	}
+
+	response := &MessengerResponse{}
+	for _, target := range targets {
+		for _, group := range groups {
+			messages, err := m.forwardedMessages(group, target, request.WithForwardedFrom)
+			if err != nil {
+				return nil, err
+			}
+
+			for _, message := range messages {
+				messageResponse, err := m.sendChatMessage(ctx, message)
+				if err != nil {
+					return nil, err
+				}
+
+				err = response.Merge(messageResponse)
+				if err != nil {
+					return nil, err
+				}
+			}
+		}
+	}
+
+	return response, nil
+}
+
+// messagesToForward loads the messages to forward, grouping the selected images of the same album
+func (m *Messenger) messagesToForward(messageIDs []string) ([][]*common.Message, error) {
+	var groups [][]*common.Message
+	albums := make(map[string]int)
+
+	for _, messageID := range messageIDs {
+		message, err := m.persistence.MessageByID(messageID)
+		if err != nil {
+			return nil, err
+		}
+
+		if message.Deleted || message.DeletedForMe || !forwardableContentTypes[message.ContentType] {
+			return nil, ErrMessageNotForwardable
+		}
+
+		image := message.GetImage()
+		if image == nil || image.AlbumId == "" {
+			groups = append(groups, []*common.Message{message})
+			continue
+		}
+
+		if i, ok := albums[image.AlbumId]; ok {
+			groups[i] = append(groups[i], message)
+			continue
+		}
+		albums[image.AlbumId] = len(groups)
+		groups = append(groups, []*common.Message{message})
+	}
+
+	return groups, nil
+}
+
+func (m *Messenger) forwardedMessages(originals []*common.Message, target *Chat, withForwardedFrom bool) ([]*common.Message, error) {
+	albumID := ""
+	if len(originals) > 1 {
+		albumID = uuid.New().String()
+	}
+
+	messages := make([]*common.Message, 0, len(originals))
+	for _, original := range originals {
+		clone := proto.Clone(original.ChatMessage).(*protobuf.ChatMessage)
+
+		message := common.NewMessage()
+		message.ChatMessage = &protobuf.ChatMessage{
+			ChatId:              target.ID,
+			Text:                clone.Text,
+			ContentType:         clone.ContentType,
+			Payload:             clone.Payload,
+			UnfurledLinks:       clone.UnfurledLinks,
+			UnfurledStatusLinks: clone.UnfurledStatusLinks,
+			UnfurledRichLinks:   clone.UnfurledRichLinks,
+		}
+
+		if withForwardedFrom {
+			message.ForwardedFrom = m.forwardedFrom(original, target)
+		}
+
+		// a single image selected from an album is forwarded on its own
+		if message.ContentType == protobuf.ChatMessage_IMAGE {
+			imagesCount := uint32(len(originals))
+			if albumID == "" {
+				imagesCount = 0
+			}
+			err := message.SetAlbumIDAndImagesCount(albumID, imagesCount)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		messages = append(messages, message)
+	}
+
+	return messages, nil
+}
+
+// forwardedFrom references the original message, it is left out when members
+// of the target chat can't see the original chat
+func (m *Messenger) forwardedFrom(original *common.Message, target *Chat) *protobuf.ForwardedFrom {
+	source, ok := m.allChats.Load(original.LocalChatID)
+	if !ok || !m.sourceChatVisibleFrom(source, target) {
+		return nil
+	}
+
+	return &protobuf.ForwardedFrom{
+		ChatId:    source.ID,
+		MessageId: original.ID,
+		Sender:    original.From,
+	}
+}
+
+// verifiedForwardedFrom returns the reference to the original of a received message when it matches
+// our copy of the original, nil otherwise. The reference is set by the sender, so it's not trusted.
+func (m *Messenger) verifiedForwardedFrom(forwardedFrom *protobuf.ForwardedFrom) *protobuf.ForwardedFrom {
+	if forwardedFrom == nil || forwardedFrom.MessageId == "" {
+		return nil
+	}
+
+	original, err := m.persistence.MessageByID(forwardedFrom.MessageId)
+	if err != nil {
+		m.logger.Debug("dropping reference to unknown forwarded message", zap.String("messageID", forwardedFrom.MessageId), zap.Error(err))
+		return nil
+	}
+
+	if original.From != forwardedFrom.Sender || original.LocalChatID != forwardedFrom.ChatId {
+		m.logger.Warn("dropping reference to mismatching forwarded message", zap.String("messageID", forwardedFrom.MessageId))
+		return nil
+	}
+
+	return forwardedFrom
+}
+
+func (m *Messenger) sourceChatVisibleFrom(source *Chat, target *Chat) bool {
+	if source.ID == target.ID || source.Public() {
+		return true
+	}
+
+	if !source.CommunityChat() || !target.CommunityChat() || source.CommunityID != target.CommunityID {
+		return false
+	}
+
+	// Token gated channels are only visible to the members holding the tokens
+	community, err := m.communitiesManager.GetByIDString(source.CommunityID)
+	if err != nil {
+		m.logger.Warn("failed to load community of forwarded message", zap.String("communityID", source.CommunityID), zap.Error(err))
+		return false
+	}
+
+	return community.ChannelViewableFromChannel(source.CommunityChatID(), target.CommunityChatID())
+}
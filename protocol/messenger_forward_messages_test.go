@@ -0,0 +1,205 @@
+package protocol
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/requests"
+)
+
+func TestMessengerForwardMessagesSuite(t *testing.T) {
+	suite.Run(t, new(MessengerForwardMessagesSuite))
+}
+
+type MessengerForwardMessagesSuite struct {
+	MessengerBaseTestSuite
+}
+
+func (s *MessengerForwardMessagesSuite) TestForwardPublicMessage() {
+	theirMessenger := s.newMessenger()
+	defer TearDownMessenger(&s.Suite, theirMessenger)
+
+	publicChat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(publicChat))
+	_, err := s.m.Join(publicChat)
+	s.Require().NoError(err)
+
+	theirPublicChat := CreatePublicChat("status", theirMessenger.transport)
+	s.Require().NoError(theirMessenger.SaveChat(theirPublicChat))
+	_, err = theirMessenger.Join(theirPublicChat)
+	s.Require().NoError(err)
+
+	sendResponse, err := s.m.SendChatMessage(context.Background(), buildTestMessage(*publicChat))
+	s.Require().NoError(err)
+	s.Require().Len(sendResponse.Messages(), 1)
+	original := sendResponse.Messages()[0]
+
+	// the recipient checks the reference against its copy of the original
+	_, err = WaitOnMessengerResponse(
+		theirMessenger,
+		func(r *MessengerResponse) bool { return len(r.Messages()) > 0 },
+		"no original message",
+	)
+	s.Require().NoError(err)
+
+	ourChat := CreateOneToOneChat("Our 1TO1", &theirMessenger.identity.PublicKey, s.m.transport)
+	s.Require().NoError(s.m.SaveChat(ourChat))
+
+	forwardResponse, err := s.m.ForwardMessages(context.Background(), &requests.ForwardMessages{
+		MessageIDs:        []string{original.ID},
+		ChatIDs:           []string{ourChat.ID},
+		WithForwardedFrom: true,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(forwardResponse.Messages(), 1)
+	s.Require().NotEqual(original.ID, forwardResponse.Messages()[0].ID)
+
+	response, err := WaitOnMessengerResponse(
+		theirMessenger,
+		func(r *MessengerResponse) bool { return len(r.Messages()) > 0 },
+		"no forwarded message",
+	)
+	s.Require().NoError(err)
+	s.Require().Len(response.Messages(), 1)
+
+	forwarded := response.Messages()[0]
+	s.Require().Equal(original.Text, forwarded.Text)
+	s.Require().NotNil(forwarded.ForwardedFrom)
+	s.Require().Equal(publicChat.ID, forwarded.ForwardedFrom.ChatId)
+	s.Require().Equal(original.ID, forwarded.ForwardedFrom.MessageId)
+	s.Require().Equal(s.m.myHexIdentity(), forwarded.ForwardedFrom.Sender)
+}
+
+func (s *MessengerForwardMessagesSuite) TestForwardPrivateMessageOmitsProvenance() {
+	theirMessenger := s.newMessenger()
+	defer TearDownMessenger(&s.Suite, theirMessenger)
+
+	theirChat := CreateOneToOneChat("Their 1TO1", &s.privateKey.PublicKey, theirMessenger.transport)
+	s.Require().NoError(theirMessenger.SaveChat(theirChat))
+
+	_, err := theirMessenger.SendChatMessage(context.Background(), buildTestMessage(*theirChat))
+	s.Require().NoError(err)
+
+	response, err := WaitOnMessengerResponse(
+		s.m,
+		func(r *MessengerResponse) bool { return len(r.Messages()) > 0 },
+		"no messages",
+	)
+	s.Require().NoError(err)
+	s.Require().Len(response.Messages(), 1)
+	original := response.Messages()[0]
+
+	publicChat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(publicChat))
+	_, err = s.m.Join(publicChat)
+	s.Require().NoError(err)
+
+	forwardResponse, err := s.m.ForwardMessages(context.Background(), &requests.ForwardMessages{
+		MessageIDs:        []string{original.ID},
+		ChatIDs:           []string{publicChat.ID},
+		WithForwardedFrom: true,
+	})
+	s.Require().NoError(err)
+	s.Require().Len(forwardResponse.Messages(), 1)
+
+	// Readers of the public chat can't see the private chat, the original sender is not disclosed
+	forwarded := forwardResponse.Messages()[0]
+	s.Require().Equal(original.Text, forwarded.Text)
+	s.Require().Nil(forwarded.ForwardedFrom)
+}
+
+func (s *MessengerForwardMessagesSuite) TestForwardedFromIsVerified() {
+	publicChat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(publicChat))
+	_, err := s.m.Join(publicChat)
+	s.Require().NoError(err)
+
+	sendResponse, err := s.m.SendChatMessage(context.Background(), buildTestMessage(*publicChat))
+	s.Require().NoError(err)
+	original := sendResponse.Messages()[0]
+
+	forwardedFrom := &protobuf.ForwardedFrom{
+		ChatId:    publicChat.ID,
+		MessageId: original.ID,
+		Sender:    original.From,
+	}
+	s.Require().Equal(forwardedFrom, s.m.verifiedForwardedFrom(forwardedFrom))
+
+	// a reference to another sender, or to a message we don't have, is dropped
+	s.Require().Nil(s.m.verifiedForwardedFrom(&protobuf.ForwardedFrom{
+		ChatId:    publicChat.ID,
+		MessageId: original.ID,
+		Sender:    "0x04deadbeef",
+	}))
+	s.Require().Nil(s.m.verifiedForwardedFrom(&protobuf.ForwardedFrom{
+		ChatId:    publicChat.ID,
+		MessageId: "0xdeadbeef",
+		Sender:    original.From,
+	}))
+	s.Require().Nil(s.m.verifiedForwardedFrom(&protobuf.ForwardedFrom{}))
+}
+
+func (s *MessengerForwardMessagesSuite) TestForwardImageOfAlbum() {
+	publicChat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(publicChat))
+	_, err := s.m.Join(publicChat)
+	s.Require().NoError(err)
+
+	var album []*common.Message
+	for i := 0; i < 3; i++ {
+		message, err := buildImageWithAlbumIDMessage(*publicChat, "album-id")
+		s.Require().NoError(err)
+		album = append(album, message)
+	}
+
+	sendResponse, err := s.m.SendChatMessages(context.Background(), album)
+	s.Require().NoError(err)
+	s.Require().Len(sendResponse.Messages(), 3)
+	images := sendResponse.Messages()
+
+	// a single image is forwarded on its own
+	forwardResponse, err := s.m.ForwardMessages(context.Background(), &requests.ForwardMessages{
+		MessageIDs: []string{images[0].ID},
+		ChatIDs:    []string{publicChat.ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(forwardResponse.Messages(), 1)
+	s.Require().Empty(forwardResponse.Messages()[0].GetImage().AlbumId)
+
+	// images selected from the same album are forwarded in a new album
+	forwardResponse, err = s.m.ForwardMessages(context.Background(), &requests.ForwardMessages{
+		MessageIDs: []string{images[0].ID, images[1].ID},
+		ChatIDs:    []string{publicChat.ID},
+	})
+	s.Require().NoError(err)
+	s.Require().Len(forwardResponse.Messages(), 2)
+	for _, message := range forwardResponse.Messages() {
+		s.Require().NotEmpty(message.GetImage().AlbumId)
+		s.Require().NotEqual(images[0].GetImage().AlbumId, message.GetImage().AlbumId)
+		s.Require().Equal(uint32(2), message.GetImage().AlbumImagesCount)
+	}
+}
+
+func (s *MessengerForwardMessagesSuite) TestForwardDeletedMessage() {
+	publicChat := CreatePublicChat("status", s.m.transport)
+	s.Require().NoError(s.m.SaveChat(publicChat))
+	_, err := s.m.Join(publicChat)
+	s.Require().NoError(err)
+
+	sendResponse, err := s.m.SendChatMessage(context.Background(), buildTestMessage(*publicChat))
+	s.Require().NoError(err)
+	original := sendResponse.Messages()[0]
+
+	_, err = s.m.DeleteMessageAndSend(context.Background(), original.ID)
+	s.Require().NoError(err)
+
+	_, err = s.m.ForwardMessages(context.Background(), &requests.ForwardMessages{
+		MessageIDs: []string{original.ID},
+		ChatIDs:    []string{publicChat.ID},
+	})
+	s.Require().ErrorIs(err, ErrMessageNotForwardable)
+}
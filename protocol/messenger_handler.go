@@ -783,6 +783,8 @@ func (m *Messenger) HandleSyncProfilePictures(state *ReceivedMessageState, messa
 			FileSize:     int(message.FileSize),
 			ResizeTarget: int(message.ResizeTarget),
 			Clock:        message.Clock,
+
+			AnimatedPayload: message.AnimatedPayload,
 		}
 		idImages[i] = image
 		i++
@@ -2168,6 +2170,10 @@ func (m *Messenger) handleChatMessage(state *ReceivedMessageState, forceSeen boo
 		}
 	}
 
+	if !isSyncMessage {
+		receivedMessage.ForwardedFrom = m.verifiedForwardedFrom(receivedMessage.ForwardedFrom)
+	}
+
 	chat, err := m.matchChatEntity(receivedMessage, protobuf.ApplicationMetadataMessage_CHAT_MESSAGE)
 	if err != nil {
 		return err // matchChatEntity returns a descriptive error message
@@ -2217,6 +2223,31 @@ func (m *Messenger) handleChatMessage(state *ReceivedMessageState, forceSeen boo
 				zap.String("communityID", chat.CommunityID))
 			return errors.New("received a messaged from banned user")
 		}
+
+		err = m.validateCommunityChatMessageRate(community, chat, pk, receivedMessage.ID, receivedMessage.WhisperTimestamp, state.Response)
+		if err != nil {
+			logger.Warn("skipping msg violating community posting limits",
+				zap.String("messageID", receivedMessage.ID),
+				zap.String("from", receivedMessage.From),
+				zap.String("communityID", chat.CommunityID),
+				zap.Error(err))
+			return err
+		}
+
+		// Archived messages were already accepted by the control node
+		if !isSyncMessage && !forceSeen && !state.CurrentMessageState.ReleasedFromQuarantine {
+			quarantined, err := m.quarantineIfSpam(community, chat, pk, receivedMessage)
+			if err != nil {
+				return err
+			}
+			if quarantined {
+				logger.Info("quarantined msg flagged as spam",
+					zap.String("messageID", receivedMessage.ID),
+					zap.String("from", receivedMessage.From),
+					zap.String("communityID", chat.CommunityID))
+				return nil
+			}
+		}
 	}
 
 	// It looks like status-mobile created profile chats as public chats
@@ -2227,6 +2258,10 @@ func (m *Messenger) handleChatMessage(state *ReceivedMessageState, forceSeen boo
 			return errors.New("images are not allowed in public chats")
 		case protobuf.ChatMessage_AUDIO:
 			return errors.New("audio messages are not allowed in public chats")
+		case protobuf.ChatMessage_FILE:
+			return errors.New("files are not allowed in public chats")
+		case protobuf.ChatMessage_VIDEO:
+			return errors.New("videos are not allowed in public chats")
 		}
 	}
 
@@ -2240,9 +2275,20 @@ func (m *Messenger) handleChatMessage(state *ReceivedMessageState, forceSeen boo
 		return nil
 	}
 
+	m.processReceivedAudio(receivedMessage)
+
+	if !isSyncMessage {
+		m.hideIfBlockedMedia(chat, receivedMessage)
+	}
+
 	// Set the LocalChatID for the message
 	receivedMessage.LocalChatID = chat.ID
 
+	// Archived messages are expected to fill the history
+	if !forceSeen {
+		m.detectHistoryGapFromClock(chat, receivedMessage)
+	}
+
 	if err := m.updateChatFirstMessageTimestamp(chat, whisperToUnixTimestamp(receivedMessage.WhisperTimestamp), state.Response); err != nil {
 		return err
 	}
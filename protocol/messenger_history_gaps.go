@@ -0,0 +1,422 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	"github.com/waku-org/go-waku/waku/v2/api/history"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/peersyncing"
+	"github.com/status-im/status-go/protocol/requests"
+)
+
+const (
+	historyGapsLoopInterval = time.Minute
+	// historyGapsPerLoop limits the store queries sent at once to backfill gaps
+	historyGapsPerLoop    = 5
+	historyGapMaxAttempts = 8
+	// historyGapMinBackoff and historyGapMaxBackoff bound the delay before a failed backfill is retried, in seconds
+	historyGapMinBackoff = uint32(60)
+	historyGapMaxBackoff = uint32(6 * 60 * 60)
+
+	// historyGapClockThreshold is how much older than the last message of a chat, in milliseconds,
+	// a message has to be for its late arrival to be considered as a sign of missing messages
+	historyGapClockThreshold = uint64(10 * 60 * 1000)
+	// historyGapClockWindow is the period around a late message that is backfilled, in seconds
+	historyGapClockWindow = uint32(30 * 60)
+	// historyGapDatasyncWindow is the period backfilled when peers offer messages we don't have, in seconds
+	historyGapDatasyncWindow = uint32(24 * 60 * 60)
+	// historyGapDatasyncInterval is the minimum time between two gaps detected from the offers of a chat, in seconds
+	historyGapDatasyncInterval = uint32(60 * 60)
+)
+
+// HistoryGapSource is how a gap in the history of a chat was detected
+type HistoryGapSource int
+
+const (
+	// HistoryGapSourceRepair is a gap requested by the user to repair the history of a chat
+	HistoryGapSourceRepair HistoryGapSource = iota + 1
+	// HistoryGapSourceClock is a gap around a message that arrived long after messages with a later clock
+	HistoryGapSourceClock
+	// HistoryGapSourceDatasync is a gap for messages offered by peers over datasync that we don't have
+	HistoryGapSourceDatasync
+	// HistoryGapSourceStoreQuery is a gap for a store query that failed before fetching its last page
+	HistoryGapSourceStoreQuery
+)
+
+// HistoryGap is a period of the history of a chat that is likely missing messages,
+// it is backfilled from the store nodes until it is resolved
+type HistoryGap struct {
+	ID     string `json:"id"`
+	ChatID string `json:"chatId"`
+	// From and To delimit the missing period, in seconds
+	From     uint32           `json:"from"`
+	To       uint32           `json:"to"`
+	Source   HistoryGapSource `json:"source"`
+	Attempts int              `json:"attempts"`
+	// NextAttemptAt is the time of the next backfill, in seconds
+	NextAttemptAt uint32 `json:"nextAttemptAt"`
+	Resolved      bool   `json:"resolved"`
+}
+
+func historyGapBackoff(attempts int) uint32 {
+	backoff := historyGapMinBackoff
+	for i := 1; i < attempts && backoff < historyGapMaxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > historyGapMaxBackoff {
+		return historyGapMaxBackoff
+	}
+	return backoff
+}
+
+func (m *Messenger) nowInSeconds() uint32 {
+	return uint32(m.GetCurrentTimeInMillis() / 1000)
+}
+
+// GetHistoryGaps returns the unresolved gaps of a chat, of all chats if chatID is empty
+func (m *Messenger) GetHistoryGaps(chatID string) ([]*HistoryGap, error) {
+	return m.persistence.HistoryGaps(chatID)
+}
+
+// RepairChatHistory fetches the history of a chat again from the store nodes, for the requested period
+// or the one the chat was synced for, and resolves the gaps it covers
+func (m *Messenger) RepairChatHistory(request *requests.RepairChatHistory) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	chat, ok := m.allChats.Load(request.ChatID)
+	if !ok {
+		return nil, ErrChatNotFound
+	}
+
+	from, to := request.From, request.To
+	if from == 0 {
+		from = chat.SyncedFrom
+	}
+	from, err := m.capToDefaultSyncPeriod(from)
+	if err != nil {
+		return nil, err
+	}
+	if to == 0 {
+		to = m.nowInSeconds()
+	}
+	if from >= to {
+		return nil, requests.ErrRepairChatHistoryInvalidRange
+	}
+
+	gap, err := m.recordHistoryGap(chat.ID, from, to, HistoryGapSourceRepair)
+	if err != nil {
+		return nil, err
+	}
+
+	return m.backfillHistoryGap(gap)
+}
+
+// recordHistoryGap saves a gap for the period of a chat, merged with the unresolved gap it overlaps if any.
+// Periods already backfilled are skipped unless the user asked for a repair, and gaps that failed too many
+// times are left as they are so that a new gap is tried instead.
+func (m *Messenger) recordHistoryGap(chatID string, from uint32, to uint32, source HistoryGapSource) (*HistoryGap, error) {
+	if source != HistoryGapSourceRepair {
+		resolvedUntil, err := m.persistence.HistoryGapResolvedUntil(chatID, from)
+		if err != nil {
+			return nil, err
+		}
+		if resolvedUntil > from {
+			from = resolvedUntil
+		}
+	}
+
+	if from >= to {
+		return nil, nil
+	}
+
+	m.historyGapsMutex.Lock()
+	defer m.historyGapsMutex.Unlock()
+
+	gaps, err := m.persistence.HistoryGaps(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, gap := range gaps {
+		if gap.From > to || from > gap.To {
+			continue
+		}
+		if source != HistoryGapSourceRepair && gap.Attempts >= historyGapMaxAttempts {
+			continue
+		}
+		if from < gap.From {
+			gap.From = from
+		}
+		if to > gap.To {
+			gap.To = to
+		}
+		if source == HistoryGapSourceRepair {
+			gap.Source = source
+			gap.Attempts = 0
+			gap.NextAttemptAt = 0
+		}
+		return gap, m.persistence.SaveHistoryGap(gap)
+	}
+
+	// the same period can be missing again once resolved, ids are unique so that resolved gaps are kept
+	gap := &HistoryGap{
+		ID:     uuid.New().String(),
+		ChatID: chatID,
+		From:   from,
+		To:     to,
+		Source: source,
+	}
+	m.logger.Debug("history gap detected",
+		zap.String("chatID", chatID),
+		zap.Uint32("from", from),
+		zap.Uint32("to", to),
+		zap.Int("source", int(source)))
+
+	return gap, m.persistence.SaveHistoryGap(gap)
+}
+
+// detectHistoryGapFromClock records a gap around a message that arrives long after messages with a
+// later clock, in a period the chat was already synced for: messages sent at the same time are likely
+// missing too. It must be called before the chat clock is updated with the message.
+func (m *Messenger) detectHistoryGapFromClock(chat *Chat, message *common.Message) {
+	if m.transport.WakuVersion() != 2 || chat.SyncedTo == 0 {
+		return
+	}
+
+	if message.Clock+historyGapClockThreshold > chat.LastClockValue {
+		return
+	}
+
+	sentAt := uint32(message.WhisperTimestamp / 1000)
+	if sentAt < chat.SyncedFrom || sentAt > chat.SyncedTo {
+		return
+	}
+
+	from := chat.SyncedFrom
+	if sentAt > from+historyGapClockWindow {
+		from = sentAt - historyGapClockWindow
+	}
+	to := sentAt + historyGapClockWindow
+	if to > chat.SyncedTo {
+		to = chat.SyncedTo
+	}
+
+	if _, err := m.recordHistoryGap(chat.ID, from, to, HistoryGapSourceClock); err != nil {
+		m.logger.Error("failed to record history gap", zap.String("chatID", chat.ID), zap.Error(err))
+	}
+}
+
+// detectHistoryGapsFromOffers records a gap for the chats of the messages offered by peers over datasync
+// that we don't have and never requested before. The messages sent after the chat was last synced with
+// the store nodes are fetched by the next sync, so the gap covers the synced period of the window only.
+// Gaps are detected at most once per historyGapDatasyncInterval for each chat.
+func (m *Messenger) detectHistoryGapsFromOffers(messages []peersyncing.SyncMessage) {
+	if m.transport.WakuVersion() != 2 {
+		return
+	}
+
+	now := m.nowInSeconds()
+	chatIDs := make(map[string]bool)
+	for _, message := range messages {
+		chatIDs[string(message.ChatID)] = true
+	}
+
+	for chatID := range chatIDs {
+		chat, ok := m.allChats.Load(chatID)
+		if !ok || chat.SyncedTo == 0 {
+			continue
+		}
+		if !m.historyGapFromOffersDue(chatID, now) {
+			continue
+		}
+
+		from := chat.SyncedFrom
+		if now > historyGapDatasyncWindow && from < now-historyGapDatasyncWindow {
+			from = now - historyGapDatasyncWindow
+		}
+		if _, err := m.recordHistoryGap(chatID, from, chat.SyncedTo, HistoryGapSourceDatasync); err != nil {
+			m.logger.Error("failed to record history gap", zap.String("chatID", chatID), zap.Error(err))
+		}
+	}
+}
+
+// historyGapFromOffersDue tells whether a gap can be detected from datasync offers for a chat,
+// and marks it as detected if so
+func (m *Messenger) historyGapFromOffersDue(chatID string, now uint32) bool {
+	m.historyGapsMutex.Lock()
+	defer m.historyGapsMutex.Unlock()
+
+	if m.historyGapsFromOffers == nil {
+		m.historyGapsFromOffers = make(map[string]uint32)
+	}
+	if detectedAt, ok := m.historyGapsFromOffers[chatID]; ok && detectedAt+historyGapDatasyncInterval > now {
+		return false
+	}
+	m.historyGapsFromOffers[chatID] = now
+	return true
+}
+
+// detectHistoryGapsFromStoreQuery records a gap for the chats of a store query that failed,
+// as the pages after the failure have not been fetched
+func (m *Messenger) detectHistoryGapsFromStoreQuery(batch types.MailserverBatch, err error) {
+	if err == nil || errors.Is(err, context.Canceled) {
+		return
+	}
+
+	for _, chatID := range batch.ChatIDs {
+		if _, err := m.recordHistoryGap(chatID, uint32(batch.From.Unix()), uint32(batch.To.Unix()), HistoryGapSourceStoreQuery); err != nil {
+			m.logger.Error("failed to record history gap", zap.String("chatID", chatID), zap.Error(err))
+		}
+	}
+}
+
+// backfillHistoryGap fetches the period of a gap from the store node of the chat. Once the whole period
+// is fetched the gap is resolved along with the gap messages it covers, otherwise it is retried later
+// with an exponential backoff.
+func (m *Messenger) backfillHistoryGap(gap *HistoryGap) (*MessengerResponse, error) {
+	response := &MessengerResponse{}
+
+	canSync, err := m.canSyncWithStoreNodes()
+	if err != nil {
+		return nil, err
+	}
+	if !canSync {
+		response.AddHistoryGap(gap)
+		return response, nil
+	}
+
+	chat, ok := m.allChats.Load(gap.ChatID)
+	if !ok || !chat.Active {
+		gap.Resolved = true
+		return response, m.persistence.SaveHistoryGap(gap)
+	}
+
+	pubsubTopic, topics, err := m.topicsForChat(chat.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	batch := types.MailserverBatch{
+		ChatIDs:     []string{chat.ID},
+		From:        time.Unix(int64(gap.From), 0),
+		To:          time.Unix(int64(gap.To), 0),
+		PubsubTopic: pubsubTopic,
+		Topics:      topics,
+	}
+
+	if m.config.messengerSignalsHandler != nil {
+		m.config.messengerSignalsHandler.HistoryRequestStarted(1)
+	}
+
+	peerID := m.getCommunityStorenode(chat.CommunityID)
+	_, err = m.performStorenodeTask(func() (*MessengerResponse, error) {
+		return nil, m.processMailserverBatch(peerID, batch)
+	}, history.WithPeerID(peerID))
+
+	if m.config.messengerSignalsHandler != nil {
+		m.config.messengerSignalsHandler.HistoryRequestCompleted()
+	}
+
+	if err != nil {
+		gap.Attempts++
+		gap.NextAttemptAt = m.nowInSeconds() + historyGapBackoff(gap.Attempts)
+		if saveErr := m.persistence.SaveHistoryGap(gap); saveErr != nil {
+			return nil, saveErr
+		}
+		return nil, err
+	}
+
+	gap.Resolved = true
+	err = m.persistence.SaveHistoryGap(gap)
+	if err != nil {
+		return nil, err
+	}
+	response.AddHistoryGap(gap)
+
+	gapMessageIDs, err := m.persistence.GapMessageIDs(chat.ID, gap.From, gap.To)
+	if err != nil {
+		return nil, err
+	}
+	if len(gapMessageIDs) > 0 {
+		err = m.persistence.DeleteMessages(gapMessageIDs)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range gapMessageIDs {
+			response.AddRemovedMessage(&RemovedMessage{ChatID: chat.ID, MessageID: id})
+		}
+	}
+
+	return response, nil
+}
+
+func (m *Messenger) startHistoryGapsLoop() {
+	if m.transport.WakuVersion() != 2 {
+		return
+	}
+
+	logger := m.logger.Named("HistoryGapsLoop")
+
+	ticker := time.NewTicker(historyGapsLoopInterval)
+	go func() {
+		defer gocommon.LogOnPanic()
+		for {
+			select {
+			case <-ticker.C:
+				err := m.backfillDueHistoryGaps()
+				if err != nil {
+					logger.Warn("failed to backfill history gaps", zap.Error(err))
+				}
+
+			case <-m.quit:
+				ticker.Stop()
+				logger.Debug("history gaps loop stopped")
+				return
+			}
+		}
+	}()
+}
+
+func (m *Messenger) backfillDueHistoryGaps() error {
+	canSync, err := m.canSyncWithStoreNodes()
+	if err != nil || !canSync {
+		return err
+	}
+
+	gaps, err := m.persistence.DueHistoryGaps(m.nowInSeconds(), historyGapMaxAttempts)
+	if err != nil {
+		return err
+	}
+	if len(gaps) > historyGapsPerLoop {
+		gaps = gaps[:historyGapsPerLoop]
+	}
+
+	response := &MessengerResponse{}
+	for _, gap := range gaps {
+		gapResponse, err := m.backfillHistoryGap(gap)
+		if err != nil {
+			m.logger.Warn("failed to backfill history gap",
+				zap.String("chatID", gap.ChatID),
+				zap.Int("attempts", gap.Attempts),
+				zap.Error(err))
+			continue
+		}
+		err = response.Merge(gapResponse)
+		if err != nil {
+			return err
+		}
+	}
+
+	m.PublishMessengerResponse(response)
+	return nil
+}
@@ -8,6 +8,7 @@ import (
 	neturl "net/url"
 	"regexp"
 	"strings"
+	"time"
 
 	"go.uber.org/zap"
 	"golang.org/x/net/publicsuffix"
@@ -16,6 +17,7 @@ import (
 
 	"github.com/status-im/status-go/multiaccounts/settings"
 	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
 )
 
 const UnfurledLinksPerMessageLimit = 5
@@ -46,6 +48,7 @@ func URLUnfurlingSupported(url string) bool {
 type UnfurlURLsResponse struct {
 	LinkPreviews       []*common.LinkPreview       `json:"linkPreviews,omitempty"`
 	StatusLinkPreviews []*common.StatusLinkPreview `json:"statusLinkPreviews,omitempty"`
+	RichLinkPreviews   []*common.RichLinkPreview   `json:"richLinkPreviews,omitempty"`
 }
 
 func normalizeHostname(hostname string) string {
@@ -78,6 +81,85 @@ func (m *Messenger) newURLUnfurler(httpClient *http.Client, url *neturl.URL) Unf
 	}
 }
 
+// newRichURLUnfurler returns the unfurler of links to code hosts, social posts and on-chain entities,
+// nil when the link is none of them or when the data they need can't be reached
+func (m *Messenger) newRichURLUnfurler(httpClient *http.Client, url *neturl.URL) RichUnfurler {
+	if ref, ok := parseGitHubIssueURL(url); ok {
+		return newGitHubUnfurler(url, ref, m.logger, httpClient)
+	}
+
+	if id, ok := parseCollectibleURL(url); ok {
+		if m.config.walletService == nil || m.config.walletService.GetCollectiblesManager() == nil {
+			return nil
+		}
+		return newCollectibleUnfurler(url, id, m.logger, httpClient, m.config.walletService.GetCollectiblesManager())
+	}
+
+	if ref, ok := parseBlockExplorerURL(url); ok {
+		if m.config.rpcClient == nil {
+			return nil
+		}
+		reader, err := m.config.rpcClient.EthClient(ref.chainID)
+		if err != nil {
+			m.logger.Info("no client to unfurl on-chain link", zap.Uint64("chainID", ref.chainID), zap.Error(err))
+			return nil
+		}
+		var tokens onChainTokenFinder
+		if m.config.walletService != nil && m.config.walletService.GetTokenManager() != nil {
+			tokens = m.config.walletService.GetTokenManager()
+		}
+		return newOnChainUnfurler(url, ref, m.logger, reader, tokens)
+	}
+
+	if ref, ok := parseSocialPostURL(url, m.config.linkPreviewFetchConfig.MastodonInstances); ok {
+		return newSocialPostUnfurler(url, ref, m.logger, httpClient)
+	}
+
+	return nil
+}
+
+// unfurlRichURL returns nil when the link is not unfurled to a rich preview
+func (m *Messenger) unfurlRichURL(httpClient *http.Client, url string) (*common.RichLinkPreview, error) {
+	parsedURL, err := neturl.Parse(url)
+	if err != nil {
+		return nil, err
+	}
+
+	fetchConfig := m.config.linkPreviewFetchConfig
+	if !linkPreviewDomainAllowed(parsedURL.Hostname(), fetchConfig.AllowedDomains, fetchConfig.DeniedDomains) {
+		return nil, ErrLinkPreviewDomainNotAllowed
+	}
+
+	unfurler := m.newRichURLUnfurler(httpClient, parsedURL)
+	if unfurler == nil {
+		return nil, nil
+	}
+
+	if fetchConfig.CacheTTL > 0 {
+		cached, err := m.persistence.CachedRichLinkPreview(url, time.Now().Add(-fetchConfig.CacheTTL).Unix())
+		if err != nil {
+			m.logger.Warn("failed to read cached rich link preview", zap.String("url", url), zap.Error(err))
+		} else if cached != nil {
+			return cached, nil
+		}
+	}
+
+	preview, err := unfurler.Unfurl()
+	if err != nil {
+		return nil, err
+	}
+
+	// pending transactions are fetched again until they're mined
+	pending := preview.Transaction != nil && preview.Transaction.Status == protobuf.UnfurledTransactionLink_PENDING
+	if fetchConfig.CacheTTL > 0 && !pending {
+		if err := m.persistence.CacheRichLinkPreview(url, preview, time.Now().Unix()); err != nil {
+			m.logger.Warn("failed to cache rich link preview", zap.String("url", url), zap.Error(err))
+		}
+	}
+
+	return preview, nil
+}
+
 func (m *Messenger) unfurlURL(httpClient *http.Client, url string) (*common.LinkPreview, error) {
 	preview := new(common.LinkPreview)
 
@@ -86,6 +168,20 @@ func (m *Messenger) unfurlURL(httpClient *http.Client, url string) (*common.Link
 		return preview, err
 	}
 
+	fetchConfig := m.config.linkPreviewFetchConfig
+	if !linkPreviewDomainAllowed(parsedURL.Hostname(), fetchConfig.AllowedDomains, fetchConfig.DeniedDomains) {
+		return preview, ErrLinkPreviewDomainNotAllowed
+	}
+
+	if fetchConfig.CacheTTL > 0 {
+		cached, err := m.persistence.CachedLinkPreview(url, time.Now().Add(-fetchConfig.CacheTTL).Unix())
+		if err != nil {
+			m.logger.Warn("failed to read cached link preview", zap.String("url", url), zap.Error(err))
+		} else if cached != nil {
+			return cached, nil
+		}
+	}
+
 	unfurler := m.newURLUnfurler(httpClient, parsedURL)
 	preview, err = unfurler.Unfurl()
 	if err != nil {
@@ -93,6 +189,12 @@ func (m *Messenger) unfurlURL(httpClient *http.Client, url string) (*common.Link
 	}
 	preview.Hostname = strings.ToLower(parsedURL.Hostname())
 
+	if fetchConfig.CacheTTL > 0 {
+		if err := m.persistence.CacheLinkPreview(url, preview, time.Now().Unix()); err != nil {
+			m.logger.Warn("failed to cache link preview", zap.String("url", url), zap.Error(err))
+		}
+	}
+
 	return preview, nil
 }
 
@@ -209,10 +311,10 @@ func (m *Messenger) UnfurlURLs(httpClient *http.Client, urls []string) (UnfurlUR
 
 	response.LinkPreviews = make([]*common.LinkPreview, 0, len(urls))
 	response.StatusLinkPreviews = make([]*common.StatusLinkPreview, 0, len(urls))
+	response.RichLinkPreviews = make([]*common.RichLinkPreview, 0, len(urls))
 
-	if httpClient == nil {
-		httpClient = NewDefaultHTTPClient()
-	}
+	// the client given is only used for its transport, the fetch config still applies
+	httpClient = newLinkPreviewHTTPClient(m.config.linkPreviewFetchConfig, m.config.linkPreviewTransport, httpClient)
 
 	for _, url := range urls {
 		m.logger.Debug("unfurling", zap.String("url", url))
@@ -228,6 +330,15 @@ func (m *Messenger) UnfurlURLs(httpClient *http.Client, urls []string) (UnfurlUR
 			continue
 		}
 
+		richPreview, err := m.unfurlRichURL(httpClient, url)
+		if err != nil {
+			// Rich previews fall back to plain link previews
+			m.logger.Info("failed to unfurl rich link", zap.String("url", url), zap.Error(err))
+		} else if richPreview != nil {
+			response.RichLinkPreviews = append(response.RichLinkPreviews, richPreview)
+			continue
+		}
+
 		p, err := m.unfurlURL(httpClient, url)
 		if err != nil {
 			m.logger.Warn("failed to unfurl", zap.String("url", url), zap.Error(err))
@@ -745,3 +745,111 @@ func (s *MessengerLinkPreviewsTestSuite) Test_UnfurlURLs_Limit() {
 	urls := s.m.GetURLs(text)
 	s.Require().Equal(UnfurledLinksPerMessageLimit, len(urls))
 }
+
+func (s *MessengerLinkPreviewsTestSuite) Test_UnfurlURLs_GitHub() {
+	u := "https://github.com/status-im/status-go/pull/4242"
+
+	transport := StubTransport{}
+	transport.AddURLMatcher(
+		"https://api.github.com/repos/status-im/status-go/issues/4242",
+		[]byte(`
+			{
+				"number": 4242,
+				"title": "Add rich link previews",
+				"state": "closed",
+				"labels": [{"name": "feature"}, {"name": "messenger"}],
+				"user": {"login": "octocat"},
+				"pull_request": {"merged_at": "2024-12-06T10:00:00Z"}
+			}
+		`),
+		nil,
+	)
+	stubbedClient := http.Client{Transport: &transport}
+
+	response, err := s.m.UnfurlURLs(&stubbedClient, []string{u})
+	s.Require().NoError(err)
+	s.Require().Len(response.LinkPreviews, 0)
+	s.Require().Len(response.RichLinkPreviews, 1)
+	preview := response.RichLinkPreviews[0]
+
+	s.Require().Equal(u, preview.URL)
+	s.Require().NotNil(preview.GitHub)
+	s.Require().Equal("status-im/status-go", preview.GitHub.Repository)
+	s.Require().Equal(protobuf.UnfurledGitHubLink_PULL_REQUEST, preview.GitHub.Kind)
+	s.Require().Equal(uint64(4242), preview.GitHub.Number)
+	s.Require().Equal("Add rich link previews", preview.GitHub.Title)
+	s.Require().Equal(protobuf.UnfurledGitHubLink_MERGED, preview.GitHub.State)
+	s.Require().Equal([]string{"feature", "messenger"}, preview.GitHub.Labels)
+	s.Require().Equal("octocat", preview.GitHub.Author)
+}
+
+func (s *MessengerLinkPreviewsTestSuite) Test_UnfurlURLs_Mastodon() {
+	u := "https://mastodon.social/@status/113606016442540437"
+
+	transport := StubTransport{}
+	transport.AddURLMatcher(
+		"https://mastodon.social/api/v1/statuses/113606016442540437",
+		[]byte(`
+			{
+				"content": "<p>First line<br>second line</p><p>Second paragraph &amp; more</p>",
+				"created_at": "2024-12-06T10:00:00.000Z",
+				"account": {"display_name": "Status", "acct": "status"}
+			}
+		`),
+		nil,
+	)
+	stubbedClient := http.Client{Transport: &transport}
+
+	response, err := s.m.UnfurlURLs(&stubbedClient, []string{u})
+	s.Require().NoError(err)
+	s.Require().Len(response.LinkPreviews, 0)
+	s.Require().Len(response.RichLinkPreviews, 1)
+	post := response.RichLinkPreviews[0].SocialPost
+
+	s.Require().NotNil(post)
+	s.Require().Equal(protobuf.UnfurledSocialPostLink_MASTODON, post.Platform)
+	s.Require().Equal("Status", post.AuthorName)
+	s.Require().Equal("@status@mastodon.social", post.AuthorHandle)
+	s.Require().Equal("First line\nsecond line\n\nSecond paragraph & more", post.Text)
+	s.Require().Equal(uint64(1733479200), post.PostedAt)
+
+	// The post is served from the cache while fresh
+	response, err = s.m.UnfurlURLs(&http.Client{Transport: &StubTransport{}}, []string{u})
+	s.Require().NoError(err)
+	s.Require().Len(response.RichLinkPreviews, 1)
+	s.Require().Equal(post, response.RichLinkPreviews[0].SocialPost)
+
+	// Hosts that aren't known Mastodon instances get plain previews
+	u = "https://example.com/@status/113606016442540437"
+	transport = StubTransport{}
+	transport.AddURLMatcher(
+		u,
+		[]byte(`<html><head><meta property="og:title" content="Not a post"></head></html>`),
+		nil,
+	)
+	response, err = s.m.UnfurlURLs(&http.Client{Transport: &transport}, []string{u})
+	s.Require().NoError(err)
+	s.Require().Len(response.RichLinkPreviews, 0)
+	s.Require().Len(response.LinkPreviews, 1)
+	s.Require().Equal("Not a post", response.LinkPreviews[0].Title)
+}
+
+func (s *MessengerLinkPreviewsTestSuite) Test_UnfurlURLs_RichFallback() {
+	// Rich previews which fail to unfurl fall back to plain link previews
+	u := "https://github.com/status-im/status-go/issues/1"
+
+	transport := StubTransport{}
+	transport.AddURLMatcher("https://api.github.com/repos/status-im/status-go/issues/1", []byte(`{}`), nil)
+	transport.AddURLMatcher(
+		u,
+		[]byte(`<html><head><meta property="og:title" content="Issue 1"></head></html>`),
+		nil,
+	)
+	stubbedClient := http.Client{Transport: &transport}
+
+	response, err := s.m.UnfurlURLs(&stubbedClient, []string{u})
+	s.Require().NoError(err)
+	s.Require().Len(response.RichLinkPreviews, 0)
+	s.Require().Len(response.LinkPreviews, 1)
+	s.Require().Equal("Issue 1", response.LinkPreviews[0].Title)
+}
@@ -667,7 +667,10 @@ func (m *Messenger) processMailserverBatch(peerID peer.ID, batch types.Mailserve
 		return nil
 	}
 
-	return m.transport.ProcessMailserverBatch(m.ctx, batch, peerID, defaultStoreNodeRequestPageSize, nil, false)
+	err = m.transport.ProcessMailserverBatch(m.ctx, batch, peerID, defaultStoreNodeRequestPageSize, nil, false)
+	m.recordStorenodeQuery(peerID, err)
+	m.detectHistoryGapsFromStoreQuery(batch, err)
+	return err
 }
 
 func (m *Messenger) processMailserverBatchWithOptions(peerID peer.ID, batch types.MailserverBatch, pageLimit uint64, shouldProcessNextPage func(int) (bool, uint64), processEnvelopes bool) error {
@@ -679,7 +682,10 @@ func (m *Messenger) processMailserverBatchWithOptions(peerID peer.ID, batch type
 		return nil
 	}
 
-	return m.transport.ProcessMailserverBatch(m.ctx, batch, peerID, pageLimit, shouldProcessNextPage, processEnvelopes)
+	err = m.transport.ProcessMailserverBatch(m.ctx, batch, peerID, pageLimit, shouldProcessNextPage, processEnvelopes)
+	m.recordStorenodeQuery(peerID, err)
+	m.detectHistoryGapsFromStoreQuery(batch, err)
+	return err
 }
 
 func (m *Messenger) SyncChatFromSyncedFrom(chatID string) (uint32, error) {
@@ -39,6 +39,67 @@ func (m *Messenger) AllMailservers() ([]mailservers.Mailserver, error) {
 	return allMailservers, nil
 }
 
+// StorenodeState is the state of a storenode known to the storenode cycle
+type StorenodeState struct {
+	ID        string  `json:"id"`
+	PeerID    peer.ID `json:"peerId"`
+	Available bool    `json:"available"`
+	Active    bool    `json:"active"`
+}
+
+// StorenodeCycleState describes the storenodes the cycle picks from
+type StorenodeCycleState struct {
+	Fleet           string           `json:"fleet"`
+	UseStorenodes   bool             `json:"useStorenodes"`
+	PinnedStorenode peer.ID          `json:"pinnedStorenode,omitempty"`
+	ActiveStorenode peer.ID          `json:"activeStorenode,omitempty"`
+	Storenodes      []StorenodeState `json:"storenodes"`
+}
+
+// StorenodeCycleState returns the configured storenodes and whether the cycle considers them available
+func (m *Messenger) StorenodeCycleState() (*StorenodeCycleState, error) {
+	fleet, err := m.getFleet()
+	if err != nil {
+		return nil, err
+	}
+
+	useStorenodes, err := m.UseStorenodes()
+	if err != nil {
+		return nil, err
+	}
+
+	pinned, err := m.GetPinnedStorenode()
+	if err != nil {
+		return nil, err
+	}
+
+	allMailservers, err := m.AllMailservers()
+	if err != nil {
+		return nil, err
+	}
+
+	state := &StorenodeCycleState{
+		Fleet:           fleet,
+		UseStorenodes:   useStorenodes,
+		PinnedStorenode: pinned.ID,
+		ActiveStorenode: m.transport.GetActiveStorenode(),
+	}
+	for _, ms := range allMailservers {
+		peerInfo, err := ms.PeerInfo()
+		if err != nil {
+			return nil, err
+		}
+		state.Storenodes = append(state.Storenodes, StorenodeState{
+			ID:        ms.ID,
+			PeerID:    peerInfo.ID,
+			Available: m.transport.IsStorenodeAvailable(peerInfo.ID),
+			Active:    peerInfo.ID == state.ActiveStorenode,
+		})
+	}
+
+	return state, nil
+}
+
 func (m *Messenger) setupStorenodes(storenodes []mailservers.Mailserver) error {
 	if m.transport.WakuVersion() != 2 {
 		return nil
@@ -153,7 +214,7 @@ func (m *Messenger) Storenodes() ([]peer.AddrInfo, error) {
 		result = append(result, peerInfo)
 	}
 
-	return result, nil
+	return m.rankStorenodes(result), nil
 }
 
 func (m *Messenger) checkForStorenodeCycleSignals() {
@@ -0,0 +1,76 @@
+package protocol
+
+import (
+	"time"
+
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+)
+
+const (
+	mediaRetentionLoopInterval = time.Hour
+	// defaultMediaRetentionPeriod is how long the payloads of files and videos are kept
+	defaultMediaRetentionPeriod = 90 * 24 * time.Hour
+	// defaultMediaRetentionMaxSize is how much space the payloads of files and videos take at most
+	defaultMediaRetentionMaxSize = 1024 * 1024 * 1024
+)
+
+// pruneMediaPayloads deletes the payloads of files and videos, and the transcodes of audios, past the retention policy,
+// their messages are kept
+func (m *Messenger) pruneMediaPayloads() error {
+	storedBefore := time.Now().Add(-m.config.mediaRetentionPeriod).Unix()
+	pruned, err := m.persistence.PruneMediaPayloads(storedBefore, m.config.mediaRetentionMaxSize)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		m.logger.Info("pruned media payloads", zap.Int64("count", pruned))
+	}
+	return nil
+}
+
+// pruneLinkPreviewCache deletes the unfurled links older than the cache TTL
+func (m *Messenger) pruneLinkPreviewCache() error {
+	fetchedBefore := time.Now().Add(-m.config.linkPreviewFetchConfig.CacheTTL).Unix()
+	pruned, err := m.persistence.PruneLinkPreviewCache(fetchedBefore)
+	if err != nil {
+		return err
+	}
+	if pruned > 0 {
+		m.logger.Debug("pruned link preview cache", zap.Int64("count", pruned))
+	}
+	return nil
+}
+
+func (m *Messenger) startMediaRetentionLoop() {
+	logger := m.logger.Named("MediaRetentionLoop")
+
+	prune := func() {
+		if err := m.pruneMediaPayloads(); err != nil {
+			logger.Warn("failed to prune media payloads", zap.Error(err))
+		}
+		if err := m.pruneLinkPreviewCache(); err != nil {
+			logger.Warn("failed to prune link preview cache", zap.Error(err))
+		}
+	}
+
+	ticker := time.NewTicker(mediaRetentionLoopInterval)
+	go func() {
+		defer gocommon.LogOnPanic()
+
+		prune()
+
+		for {
+			select {
+			case <-ticker.C:
+				prune()
+
+			case <-m.quit:
+				ticker.Stop()
+				logger.Debug("media retention loop stopped")
+				return
+			}
+		}
+	}()
+}
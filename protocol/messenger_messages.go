@@ -58,6 +58,9 @@ func (m *Messenger) EditMessage(ctx context.Context, request *requests.EditMessa
 		if len(request.StatusLinkPreviews) > 0 {
 			message.StatusLinkPreviews = request.StatusLinkPreviews
 		}
+		if len(request.RichLinkPreviews) > 0 {
+			message.RichLinkPreviews = request.RichLinkPreviews
+		}
 
 		clock, _ := chat.NextClockAndTimestamp(m.getTimesource())
 
@@ -92,6 +95,13 @@ func (m *Messenger) EditMessage(ctx context.Context, request *requests.EditMessa
 			editMessage.UnfurledStatusLinks = unfurledStatusLinks
 		}
 
+		unfurledRichLinks, err := message.ConvertRichLinkPreviewsToProto()
+		if err != nil {
+			m.logger.Error("failed to convert rich link previews", zap.Error(err))
+		} else {
+			editMessage.UnfurledRichLinks = unfurledRichLinks
+		}
+
 		err = m.applyEditMessage(editMessage.EditMessage, message)
 		if err != nil {
 			return nil, err
@@ -204,7 +214,9 @@ func (m *Messenger) DeleteMessageAndSend(ctx context.Context, messageID string)
 		message.ContentType != protobuf.ChatMessage_STICKER &&
 		message.ContentType != protobuf.ChatMessage_EMOJI &&
 		message.ContentType != protobuf.ChatMessage_IMAGE &&
-		message.ContentType != protobuf.ChatMessage_AUDIO {
+		message.ContentType != protobuf.ChatMessage_AUDIO &&
+		message.ContentType != protobuf.ChatMessage_FILE &&
+		message.ContentType != protobuf.ChatMessage_VIDEO {
 		return nil, ErrInvalidDeleteTypeAuthor
 	}
 
@@ -294,7 +306,9 @@ func (m *Messenger) DeleteMessageForMeAndSync(ctx context.Context, localChatID s
 		message.ContentType != protobuf.ChatMessage_STICKER &&
 		message.ContentType != protobuf.ChatMessage_EMOJI &&
 		message.ContentType != protobuf.ChatMessage_IMAGE &&
-		message.ContentType != protobuf.ChatMessage_AUDIO {
+		message.ContentType != protobuf.ChatMessage_AUDIO &&
+		message.ContentType != protobuf.ChatMessage_FILE &&
+		message.ContentType != protobuf.ChatMessage_VIDEO {
 		return nil, ErrInvalidDeleteTypeAuthor
 	}
 
@@ -384,6 +398,7 @@ func (m *Messenger) applyEditMessage(editMessage *protobuf.EditMessage, message
 	message.EditedAt = editMessage.Clock
 	message.UnfurledLinks = editMessage.UnfurledLinks
 	message.UnfurledStatusLinks = editMessage.UnfurledStatusLinks
+	message.UnfurledRichLinks = editMessage.UnfurledRichLinks
 	if editMessage.ContentType != protobuf.ChatMessage_UNKNOWN_CONTENT_TYPE {
 		message.ContentType = editMessage.ContentType
 	}
@@ -399,6 +414,7 @@ func (m *Messenger) applyEditMessage(editMessage *protobuf.EditMessage, message
 		originalEdit.From = message.From
 		originalEdit.UnfurledLinks = message.UnfurledLinks
 		originalEdit.UnfurledStatusLinks = message.UnfurledStatusLinks
+		originalEdit.UnfurledRichLinks = message.UnfurledRichLinks
 		err := m.persistence.SaveEdit(originalEdit)
 		if err != nil {
 			return err
@@ -300,6 +300,10 @@ func (m *Messenger) SyncDevices(ctx context.Context, ensName, photoPath string,
 		return err
 	}
 
+	if err = m.syncScheduledMessages(ctx, rawMessageHandler); err != nil {
+		return err
+	}
+
 	err = m.syncAccountsPositions(rawMessageHandler)
 	if err != nil {
 		return err
@@ -331,6 +335,7 @@ func (m *Messenger) syncProfilePictures(rawMessageHandler RawMessageHandler, ide
 		p.Height = uint32(image.Height)
 		p.FileSize = uint32(image.FileSize)
 		p.ResizeTarget = uint32(image.ResizeTarget)
+		p.AnimatedPayload = image.AnimatedPayload
 		if image.Clock == 0 {
 			p.Clock = clock
 		} else {
@@ -254,15 +254,24 @@ func (m *Messenger) OnDatasyncOffer(response *common.HandleMessageResponse) erro
 	}
 
 	datasyncMessage := &datasyncproto.Payload{}
+	var neverRequested []peersyncing.SyncMessage
+	m.peersyncingMutex.Lock()
 	for _, msg := range messagesToFetch {
 		idString := types.Bytes2Hex(msg.ID)
 		lastOffered := m.peersyncingOffers[idString]
 		timeNow := m.GetCurrentTimeInMillis() / 1000
+		if lastOffered == 0 {
+			neverRequested = append(neverRequested, msg)
+		}
 		if lastOffered+30 < timeNow {
 			m.peersyncingOffers[idString] = timeNow
 			datasyncMessage.Requests = append(datasyncMessage.Requests, msg.ID)
 		}
 	}
+	m.peersyncingMutex.Unlock()
+
+	m.detectHistoryGapsFromOffers(neverRequested)
+
 	payload, err := proto.Marshal(datasyncMessage)
 	if err != nil {
 		return err
@@ -338,10 +347,14 @@ func (m *Messenger) OnDatasyncRequests(requester *ecdsa.PublicKey, messageIDs []
 			continue
 		}
 		idString := common.PubkeyToHex(requester) + types.Bytes2Hex(msg.ID)
-		lastRequested := m.peersyncingRequests[idString]
 		timeNow := m.GetCurrentTimeInMillis() / 1000
+		m.peersyncingMutex.Lock()
+		lastRequested := m.peersyncingRequests[idString]
 		if lastRequested+30 < timeNow {
 			m.peersyncingRequests[idString] = timeNow
+		}
+		m.peersyncingMutex.Unlock()
+		if lastRequested+30 < timeNow {
 
 			// Check permissions
 			rawMessage := common.RawMessage{
@@ -415,7 +428,7 @@ func (m *Messenger) sendDataSync(receiver state.PeerID, payload *datasyncproto.P
 	}
 
 	m.logger.Debug("sent private messages", zap.Any("messageIDs", hexMessageIDs), zap.Strings("hashes", types.EncodeHexes(hashes)))
-	m.transport.TrackMany(messageIDs, hashes, newMessages)
+	m.transport.TrackMany(ctx, messageIDs, hashes, newMessages)
 
 	return nil
 }
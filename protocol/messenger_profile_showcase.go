@@ -18,6 +18,7 @@ import (
 
 	"github.com/status-im/status-go/eth-node/crypto"
 	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/images"
 	"github.com/status-im/status-go/multiaccounts/accounts"
 	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/communities"
@@ -138,6 +139,22 @@ func (m *Messenger) validateCommunityMembershipEntry(
 	return identity.ProfileShowcaseMembershipStatusNotAMember, nil
 }
 
+// setProfileShowcaseCommunityImage sets the URLs of the thumbnail of a showcased community,
+// its animated variant being served only when it's a GIF
+func (m *Messenger) setProfileShowcaseCommunityImage(entry *identity.ProfileShowcaseCommunity, community *communities.Community) {
+	if community == nil || m.httpServer == nil {
+		return
+	}
+	image, ok := community.Images()[images.SmallDimName]
+	if !ok {
+		return
+	}
+	entry.ImageURL = m.httpServer.MakeCommunityImageURL(entry.CommunityID, images.SmallDimName)
+	if images.GetType(image.AnimatedPayload) == images.GIF {
+		entry.AnimatedImageURL = m.httpServer.MakeAnimatedCommunityImageURL(entry.CommunityID, images.SmallDimName)
+	}
+}
+
 func (m *Messenger) validateCommunitiesMembership(communities []*identity.ProfileShowcaseCommunity, contactPubKey *ecdsa.PublicKey) ([]*identity.ProfileShowcaseCommunity, error) {
 	validatedCommunities := []*identity.ProfileShowcaseCommunity{}
 
@@ -157,6 +174,7 @@ func (m *Messenger) validateCommunitiesMembership(communities []*identity.Profil
 		if err != nil {
 			m.logger.Warn("failed to verify grant signature ", zap.Error(err))
 		}
+		m.setProfileShowcaseCommunityImage(communityEntry, community)
 		validatedCommunities = append(validatedCommunities, communityEntry)
 	}
 
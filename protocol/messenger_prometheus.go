@@ -0,0 +1,27 @@
+package protocol
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	wakuMessagesProcessedCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "status_waku_messages_processed_total",
+		Help: "Number of retrieved waku messages processed by the messenger.",
+	}, []string{"result"})
+	statusMessagesProcessedCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "status_messages_processed_total",
+		Help: "Number of decoded status messages dispatched to their handler.",
+	}, []string{"result"})
+	retrievedMessagesProcessingDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "status_retrieved_messages_processing_duration_seconds",
+		Help:    "The time it took to process a batch of retrieved messages.",
+		Buckets: prom.ExponentialBuckets(0.005, 2, 14),
+	})
+)
+
+func init() {
+	prom.MustRegister(wakuMessagesProcessedCounter)
+	prom.MustRegister(statusMessagesProcessedCounter)
+	prom.MustRegister(retrievedMessagesProcessingDuration)
+}
@@ -93,6 +93,9 @@ type MessengerResponse struct {
 	ensUsernameDetails               []*ensservice.UsernameDetail
 	updatedProfileShowcaseContactIDs map[string]bool
 	seenAndUnseenMessages            map[string]*SeenUnseenMessages
+	communityMessageReports          map[string]*communities.MessageReport
+	scheduledMessages                map[string]*ScheduledMessage
+	historyGaps                      map[string]*HistoryGap
 }
 
 func (r *MessengerResponse) MarshalJSON() ([]byte, error) {
@@ -141,6 +144,9 @@ func (r *MessengerResponse) MarshalJSON() ([]byte, error) {
 		EnsUsernameDetails               []*ensservice.UsernameDetail            `json:"ensUsernameDetails,omitempty"`
 		UpdatedProfileShowcaseContactIDs []string                                `json:"updatedProfileShowcaseContactIDs,omitempty"`
 		SeenAndUnseenMessages            []*SeenUnseenMessages                   `json:"seenAndUnseenMessages,omitempty"`
+		CommunityMessageReports          []*communities.MessageReport            `json:"communityMessageReports,omitempty"`
+		ScheduledMessages                []*ScheduledMessage                     `json:"scheduledMessages,omitempty"`
+		HistoryGaps                      []*HistoryGap                           `json:"historyGaps,omitempty"`
 	}{
 		Contacts:                r.Contacts,
 		Installations:           r.Installations(),
@@ -182,6 +188,9 @@ func (r *MessengerResponse) MarshalJSON() ([]byte, error) {
 		EnsUsernameDetails:               r.EnsUsernameDetails(),
 		UpdatedProfileShowcaseContactIDs: r.GetUpdatedProfileShowcaseContactIDs(),
 		SeenAndUnseenMessages:            r.GetSeenAndUnseenMessages(),
+		CommunityMessageReports:          r.CommunityMessageReports(),
+		ScheduledMessages:                r.ScheduledMessages(),
+		HistoryGaps:                      r.HistoryGaps(),
 	}
 
 	responseItem.TrustStatus = r.TrustStatus()
@@ -334,6 +343,9 @@ func (r *MessengerResponse) IsEmpty() bool {
 		len(r.savedAddresses)+
 		len(r.updatedProfileShowcaseContactIDs)+
 		len(r.seenAndUnseenMessages)+
+		len(r.communityMessageReports)+
+		len(r.scheduledMessages)+
+		len(r.historyGaps)+
 		len(r.ensUsernameDetails) == 0 &&
 		r.currentStatus == nil &&
 		r.activityCenterState == nil &&
@@ -373,6 +385,9 @@ func (r *MessengerResponse) Merge(response *MessengerResponse) error {
 	r.AddBookmarks(response.GetBookmarks())
 	r.AddSeveralUpdatedProfileShowcaseContactIDs(response.GetUpdatedProfileShowcaseContactIDs())
 	r.AddSeveralSeenAndUnseenMessages(response.GetSeenAndUnseenMessages())
+	r.AddCommunityMessageReports(response.CommunityMessageReports())
+	r.AddScheduledMessages(response.ScheduledMessages())
+	r.AddHistoryGaps(response.HistoryGaps())
 	r.CommunityChanges = append(r.CommunityChanges, response.CommunityChanges...)
 	r.BackupHandled = response.BackupHandled
 	r.CustomizationColor = response.CustomizationColor
@@ -914,3 +929,57 @@ func (r *MessengerResponse) GetSeenAndUnseenMessages() []*SeenUnseenMessages {
 	}
 	return messages
 }
+
+func (r *MessengerResponse) AddCommunityMessageReport(report *communities.MessageReport) {
+	if r.communityMessageReports == nil {
+		r.communityMessageReports = make(map[string]*communities.MessageReport)
+	}
+
+	r.communityMessageReports[report.ID.String()] = report
+}
+
+func (r *MessengerResponse) AddCommunityMessageReports(reports []*communities.MessageReport) {
+	for _, report := range reports {
+		r.AddCommunityMessageReport(report)
+	}
+}
+
+func (r *MessengerResponse) CommunityMessageReports() []*communities.MessageReport {
+	return maps.Values(r.communityMessageReports)
+}
+
+func (r *MessengerResponse) AddScheduledMessage(message *ScheduledMessage) {
+	if r.scheduledMessages == nil {
+		r.scheduledMessages = make(map[string]*ScheduledMessage)
+	}
+
+	r.scheduledMessages[message.ID] = message
+}
+
+func (r *MessengerResponse) AddScheduledMessages(messages []*ScheduledMessage) {
+	for _, message := range messages {
+		r.AddScheduledMessage(message)
+	}
+}
+
+func (r *MessengerResponse) ScheduledMessages() []*ScheduledMessage {
+	return maps.Values(r.scheduledMessages)
+}
+
+func (r *MessengerResponse) AddHistoryGap(gap *HistoryGap) {
+	if r.historyGaps == nil {
+		r.historyGaps = make(map[string]*HistoryGap)
+	}
+
+	r.historyGaps[gap.ID] = gap
+}
+
+func (r *MessengerResponse) AddHistoryGaps(gaps []*HistoryGap) {
+	for _, gap := range gaps {
+		r.AddHistoryGap(gap)
+	}
+}
+
+func (r *MessengerResponse) HistoryGaps() []*HistoryGap {
+	return maps.Values(r.historyGaps)
+}
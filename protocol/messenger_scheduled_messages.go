@@ -0,0 +1,384 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+	"github.com/status-im/status-go/protocol/requests"
+	v1protocol "github.com/status-im/status-go/protocol/v1"
+)
+
+const scheduledMessagesLoopInterval = 10 * time.Second
+
+// scheduledMessageTombstoneRetention is how long sent and cancelled scheduled messages are kept
+// for paired devices to catch up
+const scheduledMessageTombstoneRetention = 30 * 24 * time.Hour
+
+var ErrScheduledMessageNotFound = errors.New("scheduled message not found")
+var ErrScheduledMessageInPast = errors.New("scheduled time is in the past")
+
+// ScheduledMessage is a chat message queued to be sent at ScheduledAt.
+// Only the installation that scheduled it sends it, edits are synced across paired devices.
+type ScheduledMessage struct {
+	ID     string `json:"id"`
+	ChatID string `json:"chatId"`
+	// ScheduledAt is the time the message is due at, in milliseconds
+	ScheduledAt    uint64          `json:"scheduledAt"`
+	InstallationID string          `json:"installationId"`
+	Clock          uint64          `json:"clock"`
+	Deleted        bool            `json:"deleted,omitempty"`
+	Message        *common.Message `json:"message"`
+}
+
+func (s *ScheduledMessage) ToSyncProtobuf() *protobuf.SyncScheduledMessage {
+	return &protobuf.SyncScheduledMessage{
+		Clock:          s.Clock,
+		Id:             s.ID,
+		ChatId:         s.ChatID,
+		ScheduledAt:    s.ScheduledAt,
+		InstallationId: s.InstallationID,
+		Message:        s.Message.ChatMessage,
+		Deleted:        s.Deleted,
+	}
+}
+
+func scheduledMessageFromSyncProtobuf(message *protobuf.SyncScheduledMessage) *ScheduledMessage {
+	return &ScheduledMessage{
+		ID:             message.Id,
+		ChatID:         message.ChatId,
+		ScheduledAt:    message.ScheduledAt,
+		InstallationID: message.InstallationId,
+		Clock:          message.Clock,
+		Deleted:        message.Deleted,
+		Message: &common.Message{
+			ID:          message.Id,
+			LocalChatID: message.ChatId,
+			ChatMessage: message.Message,
+		},
+	}
+}
+
+// ScheduleChatMessage builds the message and stores it to be sent at the requested time
+func (m *Messenger) ScheduleChatMessage(ctx context.Context, request *requests.ScheduleMessage) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	if request.ScheduledAt <= m.GetCurrentTimeInMillis() {
+		return nil, ErrScheduledMessageInPast
+	}
+
+	if _, ok := m.allChats.Load(request.Message.ChatId); !ok {
+		return nil, ErrChatNotFoundError
+	}
+
+	message := request.Message
+	err := m.buildChatMessageContent(message)
+	if err != nil {
+		return nil, err
+	}
+
+	scheduled := &ScheduledMessage{
+		ID:             uuid.New().String(),
+		ChatID:         message.ChatId,
+		ScheduledAt:    request.ScheduledAt,
+		InstallationID: m.installationID,
+		Message:        message,
+	}
+
+	err = m.saveAndSyncScheduledMessage(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddScheduledMessage(scheduled)
+	return response, nil
+}
+
+// GetScheduledMessages returns the pending scheduled messages of a chat, of all chats if chatID is empty
+func (m *Messenger) GetScheduledMessages(chatID string) ([]*ScheduledMessage, error) {
+	scheduledMessages, err := m.persistence.ScheduledMessages(chatID)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, scheduled := range scheduledMessages {
+		err = m.prepareScheduledMessage(scheduled)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return scheduledMessages, nil
+}
+
+func (m *Messenger) EditScheduledMessage(ctx context.Context, request *requests.EditScheduledMessage) (*MessengerResponse, error) {
+	if err := request.Validate(); err != nil {
+		return nil, err
+	}
+
+	scheduled, err := m.persistence.ScheduledMessage(request.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduled == nil || scheduled.Deleted {
+		return nil, ErrScheduledMessageNotFound
+	}
+
+	if request.ScheduledAt != 0 {
+		if request.ScheduledAt <= m.GetCurrentTimeInMillis() {
+			return nil, ErrScheduledMessageInPast
+		}
+		scheduled.ScheduledAt = request.ScheduledAt
+	}
+
+	if len(request.Text) != 0 {
+		message := scheduled.Message
+		message.ChatId = scheduled.ChatID
+		message.Text = request.Text
+		message.LinkPreviews = request.LinkPreviews
+		message.StatusLinkPreviews = request.StatusLinkPreviews
+		message.RichLinkPreviews = request.RichLinkPreviews
+		message.UnfurledLinks = nil
+		message.UnfurledStatusLinks = nil
+		message.UnfurledRichLinks = nil
+
+		err = m.buildChatMessageContent(message)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	err = m.saveAndSyncScheduledMessage(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddScheduledMessage(scheduled)
+	return response, nil
+}
+
+func (m *Messenger) CancelScheduledMessage(ctx context.Context, id string) (*MessengerResponse, error) {
+	scheduled, err := m.persistence.ScheduledMessage(id)
+	if err != nil {
+		return nil, err
+	}
+
+	if scheduled == nil || scheduled.Deleted {
+		return nil, ErrScheduledMessageNotFound
+	}
+
+	scheduled.Deleted = true
+	err = m.saveAndSyncScheduledMessage(ctx, scheduled)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &MessengerResponse{}
+	response.AddScheduledMessage(scheduled)
+	return response, nil
+}
+
+// saveAndSyncScheduledMessage bumps the clock of the scheduled message, saves it
+// and sends it to our paired devices
+func (m *Messenger) saveAndSyncScheduledMessage(ctx context.Context, scheduled *ScheduledMessage) error {
+	err := m.withChatClock(func(chatID string, clock uint64) error {
+		scheduled.Clock = clock
+
+		err := m.persistence.SaveScheduledMessage(scheduled)
+		if err != nil {
+			return err
+		}
+
+		return m.dispatchSyncScheduledMessage(ctx, chatID, scheduled, m.dispatchMessage)
+	})
+	if err != nil {
+		return err
+	}
+
+	return m.prepareScheduledMessage(scheduled)
+}
+
+func (m *Messenger) prepareScheduledMessage(scheduled *ScheduledMessage) error {
+	scheduled.Message.ID = scheduled.ID
+	scheduled.Message.LocalChatID = scheduled.ChatID
+	scheduled.Message.From = m.myHexIdentity()
+	return scheduled.Message.PrepareContent(m.myHexIdentity())
+}
+
+func (m *Messenger) dispatchSyncScheduledMessage(ctx context.Context, chatID string, scheduled *ScheduledMessage, rawMessageHandler RawMessageHandler) error {
+	if !m.hasPairedDevices() {
+		return nil
+	}
+
+	encodedMessage, err := proto.Marshal(scheduled.ToSyncProtobuf())
+	if err != nil {
+		return err
+	}
+
+	rawMessage := common.RawMessage{
+		LocalChatID: chatID,
+		Payload:     encodedMessage,
+		MessageType: protobuf.ApplicationMetadataMessage_SYNC_SCHEDULED_MESSAGE,
+		ResendType:  common.ResendTypeDataSync,
+	}
+
+	_, err = rawMessageHandler(ctx, rawMessage)
+	return err
+}
+
+func (m *Messenger) syncScheduledMessages(ctx context.Context, rawMessageHandler RawMessageHandler) error {
+	scheduledMessages, err := m.persistence.AllScheduledMessages()
+	if err != nil {
+		return err
+	}
+
+	return m.withChatClock(func(chatID string, _ uint64) error {
+		for _, scheduled := range scheduledMessages {
+			err := m.dispatchSyncScheduledMessage(ctx, chatID, scheduled, rawMessageHandler)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (m *Messenger) HandleSyncScheduledMessage(state *ReceivedMessageState, message *protobuf.SyncScheduledMessage, statusMessage *v1protocol.StatusMessage) error {
+	if len(message.Id) == 0 || len(message.ChatId) == 0 || message.Message == nil {
+		return errors.New("invalid scheduled message")
+	}
+
+	existing, err := m.persistence.ScheduledMessage(message.Id)
+	if err != nil {
+		return err
+	}
+
+	if existing != nil && existing.Clock >= message.Clock {
+		return nil
+	}
+
+	// Its tombstone might have been pruned already, it was sent or cancelled long ago
+	if existing == nil && message.ScheduledAt+uint64(scheduledMessageTombstoneRetention.Milliseconds()) < m.GetCurrentTimeInMillis() {
+		return nil
+	}
+
+	scheduled := scheduledMessageFromSyncProtobuf(message)
+	err = m.persistence.SaveScheduledMessage(scheduled)
+	if err != nil {
+		return err
+	}
+
+	err = m.prepareScheduledMessage(scheduled)
+	if err != nil {
+		return err
+	}
+
+	state.Response.AddScheduledMessage(scheduled)
+	return nil
+}
+
+func (m *Messenger) startScheduledMessagesLoop() {
+	logger := m.logger.Named("ScheduledMessagesLoop")
+
+	ticker := time.NewTicker(scheduledMessagesLoopInterval)
+	go func() {
+		defer gocommon.LogOnPanic()
+		for {
+			select {
+			case <-ticker.C:
+				err := m.sendDueScheduledMessages()
+				if err != nil {
+					logger.Warn("failed to send scheduled messages", zap.Error(err))
+				}
+
+				err = m.pruneScheduledMessageTombstones()
+				if err != nil {
+					logger.Warn("failed to prune scheduled messages", zap.Error(err))
+				}
+
+			case <-m.quit:
+				ticker.Stop()
+				logger.Debug("scheduled messages loop stopped")
+				return
+			}
+		}
+	}()
+}
+
+// sendDueScheduledMessages sends the scheduled messages this installation is in charge of
+// through the regular send path, which assigns clocks at send time.
+// A message is marked as sent before being sent, so that it's never sent twice.
+func (m *Messenger) sendDueScheduledMessages() error {
+	dueMessages, err := m.persistence.DueScheduledMessages(m.installationID, m.GetCurrentTimeInMillis())
+	if err != nil {
+		return err
+	}
+
+	response := &MessengerResponse{}
+	for _, scheduled := range dueMessages {
+		scheduled.Deleted = true
+		err = m.persistence.SaveScheduledMessage(scheduled)
+		if err != nil {
+			return err
+		}
+
+		message := &common.Message{
+			ChatMessage: proto.Clone(scheduled.Message.ChatMessage).(*protobuf.ChatMessage),
+		}
+		message.ChatId = scheduled.ChatID
+
+		sendResponse, err := m.sendChatMessage(context.Background(), message)
+		if err == ErrChatNotFoundError {
+			m.logger.Warn("dropping scheduled message of unknown chat", zap.String("id", scheduled.ID), zap.String("chatID", scheduled.ChatID))
+		} else if err != nil {
+			// Put it back in the queue, it will be retried on the next tick
+			m.logger.Warn("failed to send scheduled message", zap.String("id", scheduled.ID), zap.Error(err))
+			scheduled.Deleted = false
+			err = m.persistence.SaveScheduledMessage(scheduled)
+			if err != nil {
+				return err
+			}
+			continue
+		} else {
+			err = response.Merge(sendResponse)
+			if err != nil {
+				return err
+			}
+		}
+
+		err = m.saveAndSyncScheduledMessage(context.Background(), scheduled)
+		if err != nil {
+			// Already marked as sent locally, paired devices catch up on the next sync
+			m.logger.Warn("failed to sync sent scheduled message", zap.String("id", scheduled.ID), zap.Error(err))
+			continue
+		}
+		response.AddScheduledMessage(scheduled)
+	}
+
+	if !response.IsEmpty() {
+		m.PublishMessengerResponse(response)
+	}
+
+	return nil
+}
+
+func (m *Messenger) pruneScheduledMessageTombstones() error {
+	retention := uint64(scheduledMessageTombstoneRetention.Milliseconds())
+	now := m.GetCurrentTimeInMillis()
+	if now <= retention {
+		return nil
+	}
+	return m.persistence.DeleteScheduledMessageTombstones(now - retention)
+}
@@ -0,0 +1,391 @@
+package protocol
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"go.uber.org/zap"
+
+	gethcommon "github.com/ethereum/go-ethereum/common"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/protocol/storenodes"
+	"github.com/status-im/status-go/signal"
+)
+
+const (
+	// storenodeScoringInterval is how often the storenodes are pinged and checked for the recently relayed messages
+	storenodeScoringInterval = 10 * time.Minute
+	// storenodeScoringDelay leaves time to the storenodes to store the relayed messages before they are checked
+	storenodeScoringDelay      = time.Minute
+	storenodeScoringSampleSize = 20
+	storenodeScoringTimeout    = 15 * time.Second
+
+	// communityStorenodeBonus favours the storenode set up by a community over the fleet ones
+	communityStorenodeBonus = 0.1
+	// minStorenodesToCycle is the minimum number of storenodes the storenode cycle picks from
+	minStorenodesToCycle = 3
+	// storenodeScoreTolerance leaves out of the storenode cycle the storenodes scoring below this ratio of the best score
+	storenodeScoreTolerance = 0.8
+	// storenodeFailureBackoff is how long the backup storenode of a community is used after a query to its primary failed
+	storenodeFailureBackoff = 5 * time.Minute
+)
+
+// StorenodePreference holds the storenodes used for a community, or for the fleet when CommunityID is empty
+type StorenodePreference struct {
+	CommunityID string  `json:"communityId,omitempty"`
+	Primary     peer.ID `json:"primary"`
+	Backup      peer.ID `json:"backup,omitempty"`
+}
+
+// StorenodeScores returns the scores of the storenodes, best first
+func (m *Messenger) StorenodeScores() []storenodes.Score {
+	return m.storenodeScores.All()
+}
+
+// StorenodePreferences returns the storenodes preferred for the fleet and for the communities with a storenode setup
+func (m *Messenger) StorenodePreferences() []StorenodePreference {
+	m.storenodePreferencesMutex.Lock()
+	defer m.storenodePreferencesMutex.Unlock()
+
+	result := make([]StorenodePreference, 0, len(m.storenodePreferences))
+	for _, preference := range m.storenodePreferences {
+		result = append(result, preference)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].CommunityID < result[j].CommunityID
+	})
+	return result
+}
+
+// recordStorenodeQuery accounts the result of a store query in the score of the storenode
+func (m *Messenger) recordStorenodeQuery(peerID peer.ID, err error) {
+	m.recordStorenodeQueryIn(m.storenodeScores, peerID, err)
+
+	if peerID == "" || errors.Is(err, context.Canceled) {
+		return
+	}
+	m.storenodePreferencesMutex.Lock()
+	defer m.storenodePreferencesMutex.Unlock()
+	if err == nil {
+		delete(m.failedStorenodes, peerID)
+		return
+	}
+	if m.failedStorenodes == nil {
+		m.failedStorenodes = make(map[peer.ID]time.Time)
+	}
+	m.failedStorenodes[peerID] = time.Now()
+}
+
+// storenodeFailedRecently tells whether the last query to the storenode failed less than storenodeFailureBackoff ago
+func (m *Messenger) storenodeFailedRecently(peerID peer.ID) bool {
+	m.storenodePreferencesMutex.Lock()
+	defer m.storenodePreferencesMutex.Unlock()
+
+	failedAt, ok := m.failedStorenodes[peerID]
+	return ok && time.Since(failedAt) < storenodeFailureBackoff
+}
+
+func (m *Messenger) recordStorenodeQueryIn(scores *storenodes.Scores, peerID peer.ID, err error) {
+	if peerID == "" || errors.Is(err, context.Canceled) {
+		return
+	}
+	if err := scores.RecordQuery(peerID, err == nil); err != nil {
+		m.logger.Error("failed to record storenode query", zap.Stringer("peerID", peerID), zap.Error(err))
+	}
+}
+
+// rankStorenodes orders the storenodes by score and leaves out the ones scoring far below the best,
+// keeping at least minStorenodesToCycle so that the storenode cycle still picks among several storenodes
+func (m *Messenger) rankStorenodes(storenodes []peer.AddrInfo) []peer.AddrInfo {
+	byID := make(map[peer.ID]peer.AddrInfo, len(storenodes))
+	peerIDs := make([]peer.ID, 0, len(storenodes))
+	for _, storenode := range storenodes {
+		byID[storenode.ID] = storenode
+		peerIDs = append(peerIDs, storenode.ID)
+	}
+
+	var result []peer.AddrInfo
+	var best float64
+	for i, peerID := range m.storenodeScores.Rank(peerIDs) {
+		value := m.storenodeScores.Get(peerID).Value
+		if i == 0 {
+			best = value
+		}
+		if i >= minStorenodesToCycle && value < best*storenodeScoreTolerance {
+			break
+		}
+		result = append(result, byID[peerID])
+	}
+	return result
+}
+
+func (m *Messenger) fleetStorenodes() ([]peer.AddrInfo, error) {
+	allMailservers, err := m.AllMailservers()
+	if err != nil {
+		return nil, err
+	}
+
+	var result []peer.AddrInfo
+	for _, ms := range allMailservers {
+		peerInfo, err := ms.PeerInfo()
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, peerInfo)
+	}
+	return result, nil
+}
+
+// communityScores returns the scores of the storenodes checked for the messages of a community.
+// They are kept apart from the scores of the fleet, which are checked for the messages of all
+// pubsub topics, so that the storenodes of a community are compared on the same messages.
+func (m *Messenger) communityScores(communityID string) *storenodes.Scores {
+	m.storenodePreferencesMutex.Lock()
+	defer m.storenodePreferencesMutex.Unlock()
+
+	if m.communityStorenodeScores == nil {
+		m.communityStorenodeScores = make(map[string]*storenodes.Scores)
+	}
+	scores, ok := m.communityStorenodeScores[communityID]
+	if !ok {
+		scores = storenodes.NewCommunityScores(storenodes.NewDB(m.database), communityID, m.logger)
+		if err := scores.ReloadFromDB(); err != nil {
+			m.logger.Error("failed to load community storenode scores", zap.String("communityID", communityID), zap.Error(err))
+		}
+		m.communityStorenodeScores[communityID] = scores
+	}
+	return scores
+}
+
+// communityStorenodePreference picks the primary and backup storenodes of a community among
+// its own storenode and the active storenode of the fleet
+func (m *Messenger) communityStorenodePreference(communityID string) (StorenodePreference, error) {
+	preference := StorenodePreference{CommunityID: communityID}
+
+	ms, err := m.communityStorenodes.GetStorenodeByCommunityID(communityID)
+	if err != nil {
+		return preference, err
+	}
+	communityStorenode, err := ms.PeerID()
+	if err != nil {
+		return preference, err
+	}
+
+	preference.Primary = communityStorenode
+	fleetStorenode := m.transport.GetActiveStorenode()
+	if fleetStorenode == "" || fleetStorenode == communityStorenode {
+		return preference, nil
+	}
+
+	preference.Backup = fleetStorenode
+	scores := m.communityScores(communityID)
+	if scores.Get(fleetStorenode).Value > scores.Get(communityStorenode).Value+communityStorenodeBonus {
+		preference.Primary, preference.Backup = fleetStorenode, communityStorenode
+	}
+	return preference, nil
+}
+
+// fleetStorenodePreference reports the active storenode as the primary one, the storenode cycle picks
+// it among the best ranked storenodes, and the best ranked other storenode as the backup
+func (m *Messenger) fleetStorenodePreference() (StorenodePreference, error) {
+	fleetStorenodes, err := m.fleetStorenodes()
+	if err != nil {
+		return StorenodePreference{}, err
+	}
+
+	preference := StorenodePreference{Primary: m.transport.GetActiveStorenode()}
+	if preference.Primary == "" {
+		return preference, nil
+	}
+	for _, storenode := range m.rankStorenodes(fleetStorenodes) {
+		if storenode.ID != preference.Primary {
+			preference.Backup = storenode.ID
+			break
+		}
+	}
+	return preference, nil
+}
+
+// updateStorenodePreferences recomputes the preferred storenodes and signals the ones that changed
+func (m *Messenger) updateStorenodePreferences() {
+	preferences := make(map[string]StorenodePreference)
+
+	preference, err := m.fleetStorenodePreference()
+	if err != nil {
+		m.logger.Error("failed to pick fleet storenodes", zap.Error(err))
+	} else if preference.Primary != "" {
+		preferences[""] = preference
+	}
+
+	for _, communityID := range m.communityStorenodes.CommunityIDs() {
+		preference, err := m.communityStorenodePreference(communityID)
+		if err != nil {
+			m.logger.Error("failed to pick community storenodes", zap.String("communityID", communityID), zap.Error(err))
+			continue
+		}
+		preferences[communityID] = preference
+	}
+
+	m.storenodePreferencesMutex.Lock()
+	var changed []StorenodePreference
+	for communityID, preference := range preferences {
+		if m.storenodePreferences[communityID] != preference {
+			changed = append(changed, preference)
+		}
+	}
+	m.storenodePreferences = preferences
+	m.storenodePreferencesMutex.Unlock()
+
+	for _, preference := range changed {
+		m.logger.Info("storenode preference changed",
+			zap.String("communityID", preference.CommunityID),
+			zap.Stringer("primary", preference.Primary),
+			zap.Stringer("backup", preference.Backup))
+		signal.SendStorenodePreferenceChanged(preference.CommunityID, preference.Primary.String(), preference.Backup.String())
+	}
+}
+
+func (m *Messenger) scoreStorenodesLoop() {
+	defer gocommon.LogOnPanic()
+
+	if m.transport.WakuVersion() != 2 {
+		return
+	}
+
+	t := time.NewTicker(storenodeScoringInterval)
+	defer t.Stop()
+
+	storenodeChanged := m.transport.OnStorenodeChanged()
+
+	for {
+		select {
+		case <-m.quit:
+			return
+
+		// the active storenode is the backup of the communities
+		case <-storenodeChanged:
+			m.updateStorenodePreferences()
+
+		case <-t.C:
+			m.scoreStorenodes()
+			m.updateStorenodePreferences()
+		}
+	}
+}
+
+// scoreStorenodes pings the storenodes and checks whether they store the messages recently relayed.
+// Fleet storenodes are checked for the messages of all pubsub topics, community storenodes and the
+// active fleet storenode for the messages of each community, in the scores of the community.
+func (m *Messenger) scoreStorenodes() {
+	canSync, err := m.canSyncWithStoreNodes()
+	if err != nil || !canSync {
+		return
+	}
+
+	now := time.Now()
+	from, to := now.Add(-storenodeScoringInterval-storenodeScoringDelay), now.Add(-storenodeScoringDelay)
+
+	fleetStorenodes, err := m.fleetStorenodes()
+	if err != nil {
+		m.logger.Error("failed to get fleet storenodes", zap.Error(err))
+	} else {
+		m.scoreStorenodeGroup(m.storenodeScores, fleetStorenodes, m.transport.RecentMessageHashes("", from, to, storenodeScoringSampleSize))
+	}
+
+	// the active fleet storenode is the backup of the communities, it is checked along with them
+	activeStorenode := m.transport.GetActiveStorenode()
+	var activeStorenodeInfo peer.AddrInfo
+	for _, storenode := range fleetStorenodes {
+		if storenode.ID == activeStorenode {
+			activeStorenodeInfo = storenode
+		}
+	}
+
+	for _, communityID := range m.communityStorenodes.CommunityIDs() {
+		ms, err := m.communityStorenodes.GetStorenodeByCommunityID(communityID)
+		if err != nil {
+			continue
+		}
+		peerInfo, err := ms.PeerInfo()
+		if err != nil {
+			m.logger.Error("invalid community storenode", zap.String("communityID", communityID), zap.Error(err))
+			continue
+		}
+		community, err := m.communitiesManager.GetByIDString(communityID)
+		if err != nil {
+			m.logger.Error("failed to get community", zap.String("communityID", communityID), zap.Error(err))
+			continue
+		}
+		group := []peer.AddrInfo{peerInfo}
+		if activeStorenodeInfo.ID != "" && activeStorenodeInfo.ID != peerInfo.ID {
+			group = append(group, activeStorenodeInfo)
+		}
+		m.scoreStorenodeGroup(m.communityScores(communityID), group, m.transport.RecentMessageHashes(community.PubsubTopic(), from, to, storenodeScoringSampleSize))
+	}
+}
+
+// scoreStorenodeGroup scores, in scores, storenodes expected to store the same messages. A storenode misses a
+// message when another storenode of the group has it, so that the messages none of them store are
+// not held against them. A storenode alone in its group is expected to have all the messages.
+func (m *Messenger) scoreStorenodeGroup(scores *storenodes.Scores, group []peer.AddrInfo, hashes []gethcommon.Hash) {
+	found := make(map[peer.ID]int)
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+
+	for _, storenode := range group {
+		wg.Add(1)
+		go func(storenode peer.AddrInfo) {
+			defer gocommon.LogOnPanic()
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(m.ctx, storenodeScoringTimeout)
+			defer cancel()
+
+			rtt, err := m.transport.PingStorenode(ctx, storenode)
+			if err != nil {
+				m.logger.Debug("failed to ping storenode", zap.Stringer("peerID", storenode.ID), zap.Error(err))
+				m.recordStorenodeQueryIn(scores, storenode.ID, err)
+				return
+			}
+			if err := scores.RecordRTT(storenode.ID, rtt); err != nil {
+				m.logger.Error("failed to record storenode rtt", zap.Stringer("peerID", storenode.ID), zap.Error(err))
+			}
+
+			if len(hashes) == 0 {
+				return
+			}
+			count, err := m.transport.CountStoredMessages(ctx, storenode.ID, hashes)
+			m.recordStorenodeQueryIn(scores, storenode.ID, err)
+			if err != nil {
+				m.logger.Debug("failed to check storenode messages", zap.Stringer("peerID", storenode.ID), zap.Error(err))
+				return
+			}
+
+			mutex.Lock()
+			found[storenode.ID] = count
+			mutex.Unlock()
+		}(storenode)
+	}
+	wg.Wait()
+
+	expected := 0
+	for _, count := range found {
+		if count > expected {
+			expected = count
+		}
+	}
+	if len(group) == 1 {
+		expected = len(hashes)
+	}
+
+	for peerID, count := range found {
+		if err := scores.RecordCompleteness(peerID, expected, expected-count); err != nil {
+			m.logger.Error("failed to record storenode completeness", zap.Stringer("peerID", peerID), zap.Error(err))
+		}
+	}
+}
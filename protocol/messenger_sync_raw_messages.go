@@ -275,6 +275,17 @@ func (m *Messenger) HandleSyncRawMessages(rawMessages []*protobuf.RawMessage) er
 				m.logger.Error("failed to HandleDeleteForMeMessage when HandleSyncRawMessages", zap.Error(err))
 				continue
 			}
+		case protobuf.ApplicationMetadataMessage_SYNC_SCHEDULED_MESSAGE:
+			var message protobuf.SyncScheduledMessage
+			err := proto.Unmarshal(rawMessage.GetPayload(), &message)
+			if err != nil {
+				return err
+			}
+			err = m.HandleSyncScheduledMessage(state, &message, nil)
+			if err != nil {
+				m.logger.Error("failed to HandleSyncScheduledMessage when HandleSyncRawMessages", zap.Error(err))
+				continue
+			}
 		case protobuf.ApplicationMetadataMessage_SYNC_PAIR_INSTALLATION:
 			var message protobuf.SyncPairInstallation
 			err := proto.Unmarshal(rawMessage.GetPayload(), &message)
@@ -195,10 +195,13 @@ func buildAudioMessage(s *MessengerSuite, chat Chat) *common.Message {
 	message.LocalChatID = chat.ID
 	message.MessageType = protobuf.MessageType_PUBLIC_GROUP
 	message.ContentType = protobuf.ChatMessage_AUDIO
+	// audios are parsed when sent
+	payload, err := os.ReadFile("../_assets/tests/test.aac")
+	s.Require().NoError(err)
 	message.Payload = &protobuf.ChatMessage_Audio{
 		Audio: &protobuf.AudioMessage{
 			Type:    1,
-			Payload: []byte("some-payload"),
+			Payload: payload,
 		},
 	}
 
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS community_message_reports (
+    id BLOB PRIMARY KEY,
+    community_id BLOB NOT NULL,
+    chat_id TEXT NOT NULL,
+    message_id TEXT NOT NULL,
+    member_id TEXT NOT NULL,
+    reporter_id TEXT NOT NULL,
+    reason INT NOT NULL DEFAULT 0,
+    description TEXT NOT NULL DEFAULT "",
+    evidence BLOB,
+    clock INT NOT NULL,
+    status INT NOT NULL DEFAULT 1,
+    resolved_by TEXT NOT NULL DEFAULT "",
+    resolved_at INT NOT NULL DEFAULT 0
+);
+
+CREATE INDEX IF NOT EXISTS community_message_reports_community_id_status ON community_message_reports(community_id, status);
+CREATE INDEX IF NOT EXISTS community_message_reports_message_id ON community_message_reports(message_id);
@@ -0,0 +1,24 @@
+CREATE TABLE IF NOT EXISTS antispam_quarantined_messages (
+    id TEXT PRIMARY KEY,
+    chat_id TEXT NOT NULL,
+    community_id TEXT NOT NULL DEFAULT "",
+    sender_id TEXT NOT NULL,
+    text TEXT NOT NULL DEFAULT "",
+    payload BLOB NOT NULL,
+    whisper_timestamp INT NOT NULL,
+    reasons TEXT NOT NULL DEFAULT "",
+    quarantined_at INT NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS antispam_quarantined_messages_community_id ON antispam_quarantined_messages(community_id);
+CREATE INDEX IF NOT EXISTS antispam_quarantined_messages_sender_id ON antispam_quarantined_messages(sender_id);
+
+CREATE TABLE IF NOT EXISTS antispam_whitelist (
+    sender_id TEXT PRIMARY KEY,
+    added_at INT NOT NULL
+);
+
+CREATE TABLE IF NOT EXISTS antispam_config (
+    id INT PRIMARY KEY,
+    config BLOB NOT NULL
+);
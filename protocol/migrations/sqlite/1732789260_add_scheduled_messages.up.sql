@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS scheduled_messages (
+  id TEXT PRIMARY KEY ON CONFLICT REPLACE,
+  chat_id TEXT NOT NULL,
+  scheduled_at INT NOT NULL,
+  installation_id TEXT NOT NULL,
+  message BLOB NOT NULL,
+  clock INT NOT NULL,
+  deleted BOOLEAN NOT NULL DEFAULT FALSE
+);
+
+CREATE INDEX IF NOT EXISTS scheduled_messages_due ON scheduled_messages(installation_id, deleted, scheduled_at);
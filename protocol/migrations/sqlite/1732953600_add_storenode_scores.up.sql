@@ -0,0 +1,11 @@
+CREATE TABLE IF NOT EXISTS storenode_scores (
+    peer_id VARCHAR NOT NULL PRIMARY KEY,
+    rtt_ms INT NOT NULL DEFAULT 0,
+    success_rate REAL NOT NULL DEFAULT 1,
+    completeness REAL NOT NULL DEFAULT 1,
+    queries INT NOT NULL DEFAULT 0,
+    failed_queries INT NOT NULL DEFAULT 0,
+    checked_messages INT NOT NULL DEFAULT 0,
+    missing_messages INT NOT NULL DEFAULT 0,
+    updated_at INT NOT NULL DEFAULT 0
+) WITHOUT ROWID;
@@ -0,0 +1,13 @@
+CREATE TABLE IF NOT EXISTS chat_history_gaps (
+    id VARCHAR NOT NULL PRIMARY KEY,
+    chat_id VARCHAR NOT NULL,
+    gap_from INT NOT NULL,
+    gap_to INT NOT NULL,
+    source INT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at INT NOT NULL DEFAULT 0,
+    resolved BOOLEAN NOT NULL DEFAULT FALSE
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS chat_history_gaps_chat_id_resolved ON chat_history_gaps(chat_id, resolved);
+CREATE INDEX IF NOT EXISTS chat_history_gaps_next_attempt_at ON chat_history_gaps(resolved, next_attempt_at);
@@ -0,0 +1,6 @@
+ALTER TABLE user_messages ADD COLUMN file_manifest BLOB;
+
+CREATE TABLE chat_file_attachments (
+  message_id VARCHAR PRIMARY KEY NOT NULL,
+  payload BLOB NOT NULL
+) WITHOUT ROWID;
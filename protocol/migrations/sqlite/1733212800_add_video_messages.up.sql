@@ -0,0 +1,13 @@
+ALTER TABLE user_messages ADD COLUMN video_manifest BLOB;
+
+CREATE TABLE chat_video_payloads (
+  message_id VARCHAR PRIMARY KEY NOT NULL,
+  payload BLOB NOT NULL,
+  stored_at INT NOT NULL
+) WITHOUT ROWID;
+
+ALTER TABLE chat_file_attachments ADD COLUMN stored_at INT NOT NULL DEFAULT 0;
+UPDATE chat_file_attachments SET stored_at = CAST(strftime('%s', 'now') AS INT);
+
+CREATE INDEX chat_video_payloads_stored_at ON chat_video_payloads(stored_at);
+CREATE INDEX chat_file_attachments_stored_at ON chat_file_attachments(stored_at);
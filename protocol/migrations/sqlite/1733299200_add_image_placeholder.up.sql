@@ -0,0 +1 @@
+ALTER TABLE user_messages ADD COLUMN image_placeholder BLOB;
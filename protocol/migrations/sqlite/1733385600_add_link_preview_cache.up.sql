@@ -0,0 +1,7 @@
+CREATE TABLE link_preview_cache (
+  url VARCHAR PRIMARY KEY NOT NULL,
+  preview BLOB NOT NULL,
+  fetched_at INT NOT NULL
+) WITHOUT ROWID;
+
+CREATE INDEX link_preview_cache_fetched_at ON link_preview_cache(fetched_at);
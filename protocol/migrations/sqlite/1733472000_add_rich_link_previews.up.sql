@@ -0,0 +1,2 @@
+ALTER TABLE user_messages ADD COLUMN unfurled_rich_links BLOB;
+ALTER TABLE user_messages_edits ADD COLUMN unfurled_rich_links BLOB;
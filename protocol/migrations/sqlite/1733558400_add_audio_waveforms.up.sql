@@ -0,0 +1,9 @@
+ALTER TABLE user_messages ADD COLUMN audio_waveform BLOB;
+
+CREATE TABLE chat_audio_transcodes (
+  message_id VARCHAR PRIMARY KEY NOT NULL,
+  payload BLOB NOT NULL,
+  stored_at INT NOT NULL
+) WITHOUT ROWID;
+
+CREATE INDEX chat_audio_transcodes_stored_at ON chat_audio_transcodes(stored_at);
@@ -0,0 +1 @@
+ALTER TABLE chat_identity_contacts ADD COLUMN animated_payload BLOB;
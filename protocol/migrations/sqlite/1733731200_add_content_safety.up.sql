@@ -0,0 +1,28 @@
+ALTER TABLE user_messages ADD COLUMN media_hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+CREATE TABLE IF NOT EXISTS content_safety_blocklist (
+    algorithm TEXT NOT NULL,
+    hash INT NOT NULL,
+    label TEXT NOT NULL DEFAULT "",
+    added_at INT NOT NULL,
+    PRIMARY KEY (algorithm, hash)
+) WITHOUT ROWID;
+
+CREATE TABLE IF NOT EXISTS content_safety_hidden_media (
+    message_id TEXT PRIMARY KEY,
+    chat_id TEXT NOT NULL,
+    community_id TEXT NOT NULL DEFAULT "",
+    sender_id TEXT NOT NULL,
+    source TEXT NOT NULL,
+    algorithm TEXT NOT NULL,
+    hash INT NOT NULL,
+    distance INT NOT NULL,
+    hidden_at INT NOT NULL
+) WITHOUT ROWID;
+
+CREATE INDEX IF NOT EXISTS content_safety_hidden_media_community_id ON content_safety_hidden_media(community_id);
+
+CREATE TABLE IF NOT EXISTS content_safety_config (
+    id INT PRIMARY KEY,
+    config BLOB NOT NULL
+);
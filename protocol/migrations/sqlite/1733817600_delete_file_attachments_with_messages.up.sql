@@ -0,0 +1,14 @@
+CREATE TRIGGER IF NOT EXISTS delete_file_attachment_of_deleted_message AFTER DELETE ON user_messages
+BEGIN
+  DELETE FROM chat_file_attachments WHERE message_id = OLD.id;
+END;
+
+CREATE TRIGGER IF NOT EXISTS delete_file_attachment_of_hidden_message AFTER UPDATE OF deleted, deleted_for_me ON user_messages
+WHEN NEW.deleted OR NEW.deleted_for_me
+BEGIN
+  DELETE FROM chat_file_attachments WHERE message_id = NEW.id;
+END;
+
+DELETE FROM chat_file_attachments WHERE NOT EXISTS (
+  SELECT 1 FROM user_messages m WHERE m.id = message_id AND NOT COALESCE(m.deleted, 0) AND NOT COALESCE(m.deleted_for_me, 0)
+);
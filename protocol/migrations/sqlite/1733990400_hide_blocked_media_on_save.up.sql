@@ -0,0 +1,5 @@
+CREATE TRIGGER IF NOT EXISTS hide_blocked_media_of_saved_message AFTER INSERT ON user_messages
+WHEN NOT NEW.media_hidden AND EXISTS (SELECT 1 FROM content_safety_hidden_media WHERE message_id = NEW.id)
+BEGIN
+  UPDATE user_messages SET media_hidden = 1 WHERE id = NEW.id;
+END;
@@ -0,0 +1,20 @@
+CREATE TABLE IF NOT EXISTS storenode_scores_new (
+    community_id VARCHAR NOT NULL DEFAULT '',
+    peer_id VARCHAR NOT NULL,
+    rtt_ms INT NOT NULL DEFAULT 0,
+    success_rate REAL NOT NULL DEFAULT 1,
+    completeness REAL NOT NULL DEFAULT 1,
+    queries INT NOT NULL DEFAULT 0,
+    failed_queries INT NOT NULL DEFAULT 0,
+    checked_messages INT NOT NULL DEFAULT 0,
+    missing_messages INT NOT NULL DEFAULT 0,
+    updated_at INT NOT NULL DEFAULT 0,
+    PRIMARY KEY (community_id, peer_id)
+) WITHOUT ROWID;
+
+INSERT INTO storenode_scores_new (peer_id, rtt_ms, success_rate, completeness, queries, failed_queries, checked_messages, missing_messages, updated_at)
+SELECT peer_id, rtt_ms, success_rate, completeness, queries, failed_queries, checked_messages, missing_messages, updated_at FROM storenode_scores;
+
+DROP TABLE storenode_scores;
+
+ALTER TABLE storenode_scores_new RENAME TO storenode_scores;
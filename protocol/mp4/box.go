@@ -0,0 +1,110 @@
+package mp4
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+)
+
+var (
+	ErrInvalidBox         = errors.New("invalid mp4 box")
+	ErrInvalidMovieHeader = errors.New("invalid mp4 movie header")
+	ErrDurationTooLong    = errors.New("mp4 movie duration too long")
+)
+
+// Box is an ISO base media file format box, payload excludes the header
+type Box struct {
+	Kind    string
+	Payload []byte
+}
+
+// ReadBoxes splits buf in the boxes it contains
+func ReadBoxes(buf []byte) ([]Box, error) {
+	var boxes []Box
+	for len(buf) > 0 {
+		if len(buf) < 8 {
+			return nil, ErrInvalidBox
+		}
+		size := uint64(binary.BigEndian.Uint32(buf[0:4]))
+		kind := string(buf[4:8])
+		headerSize := uint64(8)
+
+		switch size {
+		case 0:
+			// the box extends to the end of its parent
+			size = uint64(len(buf))
+		case 1:
+			if len(buf) < 16 {
+				return nil, ErrInvalidBox
+			}
+			size = binary.BigEndian.Uint64(buf[8:16])
+			headerSize = 16
+		}
+
+		if size < headerSize || size > uint64(len(buf)) {
+			return nil, ErrInvalidBox
+		}
+		boxes = append(boxes, Box{Kind: kind, Payload: buf[headerSize:size]})
+		buf = buf[size:]
+	}
+	return boxes, nil
+}
+
+// FindBox returns the first box found at the given path
+func FindBox(buf []byte, path ...string) (Box, bool) {
+	boxes, err := ReadBoxes(buf)
+	if err != nil {
+		return Box{}, false
+	}
+	for _, b := range boxes {
+		if b.Kind != path[0] {
+			continue
+		}
+		if len(path) == 1 {
+			return b, true
+		}
+		return FindBox(b.Payload, path[1:]...)
+	}
+	return Box{}, false
+}
+
+// MovieDuration reads the duration of a movie header, the payload of an mvhd box.
+// Durations longer than max are rejected before being converted, so that they can't overflow.
+func MovieDuration(mvhd []byte, max time.Duration) (time.Duration, error) {
+	if len(mvhd) < 4 {
+		return 0, ErrInvalidMovieHeader
+	}
+
+	var timescale, duration uint64
+	switch mvhd[0] {
+	case 0:
+		if len(mvhd) < 20 {
+			return 0, ErrInvalidMovieHeader
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[12:16]))
+		duration = uint64(binary.BigEndian.Uint32(mvhd[16:20]))
+	case 1:
+		if len(mvhd) < 32 {
+			return 0, ErrInvalidMovieHeader
+		}
+		timescale = uint64(binary.BigEndian.Uint32(mvhd[20:24]))
+		duration = binary.BigEndian.Uint64(mvhd[24:32])
+	default:
+		return 0, ErrInvalidMovieHeader
+	}
+
+	if timescale == 0 {
+		return 0, ErrInvalidMovieHeader
+	}
+
+	// the whole seconds are compared first, the remainder is less than a second of at most 2^32 units
+	seconds := duration / timescale
+	if seconds > uint64(max/time.Second) {
+		return 0, ErrDurationTooLong
+	}
+	nanoseconds := seconds*uint64(time.Second) + (duration%timescale)*uint64(time.Second)/timescale
+	if nanoseconds > uint64(max) {
+		return 0, ErrDurationTooLong
+	}
+	return time.Duration(nanoseconds), nil
+}
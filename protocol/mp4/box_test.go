@@ -0,0 +1,34 @@
+package mp4
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMovieDuration(t *testing.T) {
+	mvhd := make([]byte, 20)
+	binary.BigEndian.PutUint32(mvhd[12:16], 600)
+	binary.BigEndian.PutUint32(mvhd[16:20], 7500)
+
+	duration, err := MovieDuration(mvhd, time.Minute)
+	require.NoError(t, err)
+	require.Equal(t, 12500*time.Millisecond, duration)
+
+	_, err = MovieDuration(mvhd, 12*time.Second)
+	require.ErrorIs(t, err, ErrDurationTooLong)
+
+	// a version 1 header whose duration overflows a time.Duration
+	mvhd = make([]byte, 32)
+	mvhd[0] = 1
+	binary.BigEndian.PutUint32(mvhd[20:24], 1)
+	binary.BigEndian.PutUint64(mvhd[24:32], 1<<63)
+	_, err = MovieDuration(mvhd, time.Duration(1<<63-1))
+	require.ErrorIs(t, err, ErrDurationTooLong)
+
+	binary.BigEndian.PutUint32(mvhd[20:24], 0)
+	_, err = MovieDuration(mvhd, time.Minute)
+	require.ErrorIs(t, err, ErrInvalidMovieHeader)
+}
@@ -583,6 +583,7 @@ func (db sqlitePersistence) Contacts() ([]*Contact, error) {
 			i.image_type,
 			i.payload,
                         i.clock_value,
+			i.animated_payload,
 			COALESCE(c.verification_status, 0) as verification_status,
 			COALESCE(t.trust_status, 0) as trust_status
 		FROM contacts c
@@ -615,6 +616,7 @@ func (db sqlitePersistence) Contacts() ([]*Contact, error) {
 			lastUpdatedLocally        sql.NullInt64
 			identityImageClock        sql.NullInt64
 			imagePayload              []byte
+			animatedImagePayload      []byte
 		)
 
 		contact.Images = make(map[string]images.IdentityImage)
@@ -641,6 +643,7 @@ func (db sqlitePersistence) Contacts() ([]*Contact, error) {
 			&imageType,
 			&imagePayload,
 			&identityImageClock,
+			&animatedImagePayload,
 			&contact.VerificationStatus,
 			&contact.TrustStatus,
 		)
@@ -703,13 +706,13 @@ func (db sqlitePersistence) Contacts() ([]*Contact, error) {
 		previousContact, ok := allContacts[contact.ID]
 		if !ok {
 			if imageType.Valid {
-				contact.Images[imageType.String] = images.IdentityImage{Name: imageType.String, Payload: imagePayload, Clock: uint64(identityImageClock.Int64)}
+				contact.Images[imageType.String] = images.IdentityImage{Name: imageType.String, Payload: imagePayload, AnimatedPayload: animatedImagePayload, Clock: uint64(identityImageClock.Int64)}
 			}
 
 			allContacts[contact.ID] = &contact
 
 		} else if imageType.Valid {
-			previousContact.Images[imageType.String] = images.IdentityImage{Name: imageType.String, Payload: imagePayload, Clock: uint64(identityImageClock.Int64)}
+			previousContact.Images[imageType.String] = images.IdentityImage{Name: imageType.String, Payload: imagePayload, AnimatedPayload: animatedImagePayload, Clock: uint64(identityImageClock.Int64)}
 			allContacts[contact.ID] = previousContact
 
 		}
@@ -834,7 +837,7 @@ func (db sqlitePersistence) UpdateContactChatIdentity(contactID string, chatIden
 			continue
 		}
 
-		stmt, err := tx.Prepare(`INSERT INTO chat_identity_contacts (contact_id, image_type, clock_value, payload) VALUES (?, ?, ?, ?)`)
+		stmt, err := tx.Prepare(`INSERT INTO chat_identity_contacts (contact_id, image_type, clock_value, payload, animated_payload) VALUES (?, ?, ?, ?, ?)`)
 		if err != nil {
 			return clockUpdated, false, err
 		}
@@ -850,11 +853,18 @@ func (db sqlitePersistence) UpdateContactChatIdentity(contactID string, chatIden
 			return clockUpdated, false, err
 		}
 
+		// Animated payloads are GIFs, anything else is dropped and the image displayed still
+		animatedPayload := image.AnimatedPayload
+		if len(animatedPayload) > 0 && images.GetType(animatedPayload) != images.GIF {
+			animatedPayload = nil
+		}
+
 		_, err = stmt.Exec(
 			contactID,
 			imageType,
 			chatIdentity.Clock,
 			image.Payload,
+			animatedPayload,
 		)
 		if err != nil {
 			return false, false, err
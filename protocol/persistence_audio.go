@@ -0,0 +1,23 @@
+package protocol
+
+import (
+	"database/sql"
+	"time"
+)
+
+// SaveAudioTranscode stores the AAC transcode of the audio of a message, which the media server
+// serves instead of the original audio
+func (db sqlitePersistence) SaveAudioTranscode(messageID string, payload []byte) error {
+	_, err := db.db.Exec(`INSERT OR REPLACE INTO chat_audio_transcodes (message_id, payload, stored_at) VALUES (?, ?, ?)`, messageID, payload, time.Now().Unix())
+	return err
+}
+
+// AudioTranscode returns the AAC transcode of the audio of a message, nil when there's none
+func (db sqlitePersistence) AudioTranscode(messageID string) ([]byte, error) {
+	var payload []byte
+	err := db.db.QueryRow(`SELECT payload FROM chat_audio_transcodes WHERE message_id = ?`, messageID).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return payload, err
+}
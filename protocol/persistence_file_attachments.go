@@ -0,0 +1,29 @@
+package protocol
+
+import (
+	"database/sql"
+	"time"
+)
+
+// saveFileAttachment stores the encrypted payload of a file apart from its message,
+// so that it's only loaded when the file is opened
+func (db sqlitePersistence) saveFileAttachment(tx *sql.Tx, messageID string, payload []byte) error {
+	_, err := tx.Exec(`INSERT OR REPLACE INTO chat_file_attachments (message_id, payload, stored_at) VALUES (?, ?, ?)`, messageID, payload, time.Now().Unix())
+	return err
+}
+
+// SaveFileAttachment stores the encrypted payload of the file of a message, once downloaded
+func (db sqlitePersistence) SaveFileAttachment(messageID string, payload []byte) error {
+	_, err := db.db.Exec(`INSERT OR REPLACE INTO chat_file_attachments (message_id, payload, stored_at) VALUES (?, ?, ?)`, messageID, payload, time.Now().Unix())
+	return err
+}
+
+// FileAttachmentPayload returns the encrypted payload of the file of a message
+func (db sqlitePersistence) FileAttachmentPayload(messageID string) ([]byte, error) {
+	var payload []byte
+	err := db.db.QueryRow(`SELECT payload FROM chat_file_attachments WHERE message_id = ?`, messageID).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return payload, err
+}
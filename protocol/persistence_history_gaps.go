@@ -0,0 +1,107 @@
+package protocol
+
+import (
+	"database/sql"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const selectHistoryGapsQuery = `
+  SELECT
+    id,
+    chat_id,
+    gap_from,
+    gap_to,
+    source,
+    attempts,
+    next_attempt_at,
+    resolved
+  FROM
+    chat_history_gaps
+  `
+
+func (db sqlitePersistence) SaveHistoryGap(gap *HistoryGap) error {
+	_, err := db.db.Exec(`INSERT OR REPLACE INTO chat_history_gaps (id, chat_id, gap_from, gap_to, source, attempts, next_attempt_at, resolved) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`,
+		gap.ID, gap.ChatID, gap.From, gap.To, gap.Source, gap.Attempts, gap.NextAttemptAt, gap.Resolved)
+	return err
+}
+
+// HistoryGaps returns the unresolved gaps of a chat, of all chats if chatID is empty
+func (db sqlitePersistence) HistoryGaps(chatID string) ([]*HistoryGap, error) {
+	if chatID == "" {
+		return db.queryHistoryGaps(selectHistoryGapsQuery + ` WHERE NOT resolved ORDER BY gap_from ASC`)
+	}
+	return db.queryHistoryGaps(selectHistoryGapsQuery+` WHERE chat_id = ? AND NOT resolved ORDER BY gap_from ASC`, chatID)
+}
+
+// DueHistoryGaps returns the unresolved gaps to backfill at the given time, in seconds,
+// leaving out the ones that failed maxAttempts times
+func (db sqlitePersistence) DueHistoryGaps(now uint32, maxAttempts int) ([]*HistoryGap, error) {
+	return db.queryHistoryGaps(selectHistoryGapsQuery+` WHERE NOT resolved AND next_attempt_at <= ? AND attempts < ? ORDER BY next_attempt_at ASC`, now, maxAttempts)
+}
+
+// HistoryGapResolvedUntil returns until when, in seconds, the period of a chat starting at from was
+// already backfilled by resolved gaps, from itself if it wasn't
+func (db sqlitePersistence) HistoryGapResolvedUntil(chatID string, from uint32) (uint32, error) {
+	for {
+		var to sql.NullInt64
+		err := db.db.QueryRow(`SELECT MAX(gap_to) FROM chat_history_gaps WHERE chat_id = ? AND resolved AND gap_from <= ? AND gap_to > ?`, chatID, from, from).Scan(&to)
+		if err != nil {
+			return 0, err
+		}
+		if !to.Valid {
+			return from, nil
+		}
+		from = uint32(to.Int64)
+	}
+}
+
+// GapMessageIDs returns the ids of the gap messages of a chat that are within from and to, in seconds
+func (db sqlitePersistence) GapMessageIDs(chatID string, from uint32, to uint32) ([]string, error) {
+	rows, err := db.db.Query(`SELECT id FROM user_messages WHERE local_chat_id = ? AND content_type = ? AND gap_from >= ? AND gap_to <= ?`,
+		chatID, protobuf.ChatMessage_SYSTEM_MESSAGE_GAP, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+
+	return ids, rows.Err()
+}
+
+func (db sqlitePersistence) queryHistoryGaps(query string, args ...interface{}) ([]*HistoryGap, error) {
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var gaps []*HistoryGap
+	for rows.Next() {
+		gap := &HistoryGap{}
+		err = rows.Scan(
+			&gap.ID,
+			&gap.ChatID,
+			&gap.From,
+			&gap.To,
+			&gap.Source,
+			&gap.Attempts,
+			&gap.NextAttemptAt,
+			&gap.Resolved,
+		)
+		if err != nil {
+			return nil, err
+		}
+		gaps = append(gaps, gap)
+	}
+
+	return gaps, rows.Err()
+}
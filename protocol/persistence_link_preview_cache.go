@@ -0,0 +1,71 @@
+package protocol
+
+import (
+	"database/sql"
+	"encoding/json"
+
+	"github.com/status-im/status-go/protocol/common"
+)
+
+// richLinkPreviewCachePrefix keys the rich previews apart from the plain ones of the same URL
+const richLinkPreviewCachePrefix = "rich:"
+
+// CacheLinkPreview stores an unfurled link, so that it's not fetched again while fresh
+func (db sqlitePersistence) CacheLinkPreview(url string, preview *common.LinkPreview, fetchedAt int64) error {
+	return db.cachePreview(url, preview, fetchedAt)
+}
+
+// CachedLinkPreview returns the unfurled link cached for a URL, nil when missing or fetched before fetchedAfter
+func (db sqlitePersistence) CachedLinkPreview(url string, fetchedAfter int64) (*common.LinkPreview, error) {
+	preview := &common.LinkPreview{}
+	found, err := db.cachedPreview(url, fetchedAfter, preview)
+	if err != nil || !found {
+		return nil, err
+	}
+	return preview, nil
+}
+
+// CacheRichLinkPreview stores a link unfurled to a rich preview, so that it's not fetched again while fresh
+func (db sqlitePersistence) CacheRichLinkPreview(url string, preview *common.RichLinkPreview, fetchedAt int64) error {
+	return db.cachePreview(richLinkPreviewCachePrefix+url, preview, fetchedAt)
+}
+
+// CachedRichLinkPreview returns the rich preview cached for a URL, nil when missing or fetched before fetchedAfter
+func (db sqlitePersistence) CachedRichLinkPreview(url string, fetchedAfter int64) (*common.RichLinkPreview, error) {
+	preview := &common.RichLinkPreview{}
+	found, err := db.cachedPreview(richLinkPreviewCachePrefix+url, fetchedAfter, preview)
+	if err != nil || !found {
+		return nil, err
+	}
+	return preview, nil
+}
+
+func (db sqlitePersistence) cachePreview(key string, preview interface{}, fetchedAt int64) error {
+	encoded, err := json.Marshal(preview)
+	if err != nil {
+		return err
+	}
+	_, err = db.db.Exec(`INSERT OR REPLACE INTO link_preview_cache (url, preview, fetched_at) VALUES (?, ?, ?)`, key, encoded, fetchedAt)
+	return err
+}
+
+func (db sqlitePersistence) cachedPreview(key string, fetchedAfter int64, preview interface{}) (bool, error) {
+	var encoded []byte
+	err := db.db.QueryRow(`SELECT preview FROM link_preview_cache WHERE url = ? AND fetched_at >= ?`, key, fetchedAfter).Scan(&encoded)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, json.Unmarshal(encoded, preview)
+}
+
+// PruneLinkPreviewCache deletes the unfurled links fetched before fetchedBefore
+func (db sqlitePersistence) PruneLinkPreviewCache(fetchedBefore int64) (int64, error) {
+	result, err := db.db.Exec(`DELETE FROM link_preview_cache WHERE fetched_at < ?`, fetchedBefore)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
@@ -0,0 +1,86 @@
+package protocol
+
+import (
+	"context"
+	"database/sql"
+)
+
+// mediaPayloadTables are the tables storing the payloads of media apart from their messages
+var mediaPayloadTables = []string{"chat_file_attachments", "chat_video_payloads", "chat_audio_transcodes"}
+
+// PruneMediaPayloads deletes the media payloads of the messages deleted and the ones stored before
+// storedBefore, in seconds. The oldest payloads left are then deleted until they take no more than
+// maxTotalSize bytes. It returns how many payloads were deleted.
+func (db sqlitePersistence) PruneMediaPayloads(storedBefore int64, maxTotalSize int64) (pruned int64, err error) {
+	tx, err := db.db.BeginTx(context.Background(), &sql.TxOptions{})
+	if err != nil {
+		return 0, err
+	}
+	defer func() {
+		if err == nil {
+			err = tx.Commit()
+			return
+		}
+		// don't shadow original error
+		_ = tx.Rollback()
+	}()
+
+	for _, table := range mediaPayloadTables {
+		// nolint: gosec
+		result, err := tx.Exec(`DELETE FROM `+table+` WHERE stored_at < ? OR NOT EXISTS (
+			SELECT 1 FROM user_messages m WHERE m.id = message_id AND NOT COALESCE(m.deleted, 0) AND NOT COALESCE(m.deleted_for_me, 0))`, storedBefore)
+		if err != nil {
+			return 0, err
+		}
+		count, err := result.RowsAffected()
+		if err != nil {
+			return 0, err
+		}
+		pruned += count
+	}
+
+	rows, err := tx.Query(`
+		SELECT 0, message_id, LENGTH(payload), stored_at FROM chat_file_attachments
+		UNION ALL
+		SELECT 1, message_id, LENGTH(payload), stored_at FROM chat_video_payloads
+		UNION ALL
+		SELECT 2, message_id, LENGTH(payload), stored_at FROM chat_audio_transcodes
+		ORDER BY stored_at DESC`)
+	if err != nil {
+		return 0, err
+	}
+
+	type mediaPayload struct {
+		table     int
+		messageID string
+	}
+	var toPrune []mediaPayload
+	var totalSize int64
+	for rows.Next() {
+		var payload mediaPayload
+		var size, storedAt int64
+		if err = rows.Scan(&payload.table, &payload.messageID, &size, &storedAt); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		totalSize += size
+		if totalSize > maxTotalSize {
+			toPrune = append(toPrune, payload)
+		}
+	}
+	rows.Close()
+	if err = rows.Err(); err != nil {
+		return 0, err
+	}
+
+	for _, payload := range toPrune {
+		// nolint: gosec
+		_, err = tx.Exec(`DELETE FROM `+mediaPayloadTables[payload.table]+` WHERE message_id = ?`, payload.messageID)
+		if err != nil {
+			return 0, err
+		}
+		pruned++
+	}
+
+	return pruned, nil
+}
@@ -0,0 +1,112 @@
+package protocol
+
+import (
+	"github.com/golang/protobuf/proto"
+
+	"github.com/status-im/status-go/protocol/common"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const selectScheduledMessagesQuery = `
+  SELECT
+    id,
+    chat_id,
+    scheduled_at,
+    installation_id,
+    message,
+    clock,
+    deleted
+  FROM
+    scheduled_messages
+  `
+
+func (db *sqlitePersistence) SaveScheduledMessage(message *ScheduledMessage) error {
+	payload, err := proto.Marshal(message.Message.ChatMessage)
+	if err != nil {
+		return err
+	}
+
+	_, err = db.db.Exec(`INSERT INTO scheduled_messages (id, chat_id, scheduled_at, installation_id, message, clock, deleted) VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		message.ID, message.ChatID, message.ScheduledAt, message.InstallationID, payload, message.Clock, message.Deleted)
+	return err
+}
+
+// ScheduledMessage returns the scheduled message with the given id, including deleted ones, nil if not found
+func (db *sqlitePersistence) ScheduledMessage(id string) (*ScheduledMessage, error) {
+	messages, err := db.queryScheduledMessages(selectScheduledMessagesQuery+` WHERE id = ?`, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if len(messages) == 0 {
+		return nil, nil
+	}
+
+	return messages[0], nil
+}
+
+// ScheduledMessages returns the pending scheduled messages of a chat, of all chats if chatID is empty
+func (db *sqlitePersistence) ScheduledMessages(chatID string) ([]*ScheduledMessage, error) {
+	if chatID == "" {
+		return db.queryScheduledMessages(selectScheduledMessagesQuery + ` WHERE NOT deleted ORDER BY scheduled_at ASC`)
+	}
+	return db.queryScheduledMessages(selectScheduledMessagesQuery+` WHERE chat_id = ? AND NOT deleted ORDER BY scheduled_at ASC`, chatID)
+}
+
+// DueScheduledMessages returns the pending messages the installation has to send at the given time
+func (db *sqlitePersistence) DueScheduledMessages(installationID string, now uint64) ([]*ScheduledMessage, error) {
+	return db.queryScheduledMessages(selectScheduledMessagesQuery+` WHERE installation_id = ? AND NOT deleted AND scheduled_at <= ? ORDER BY scheduled_at ASC`, installationID, now)
+}
+
+// AllScheduledMessages returns scheduled messages including deleted ones, for syncing with paired devices
+func (db *sqlitePersistence) AllScheduledMessages() ([]*ScheduledMessage, error) {
+	return db.queryScheduledMessages(selectScheduledMessagesQuery)
+}
+
+// DeleteScheduledMessageTombstones removes the deleted scheduled messages with a clock lower than before.
+// Clocks are timestamps in milliseconds.
+func (db *sqlitePersistence) DeleteScheduledMessageTombstones(before uint64) error {
+	_, err := db.db.Exec(`DELETE FROM scheduled_messages WHERE deleted AND clock < ?`, before)
+	return err
+}
+
+func (db *sqlitePersistence) queryScheduledMessages(query string, args ...interface{}) ([]*ScheduledMessage, error) {
+	rows, err := db.db.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var messages []*ScheduledMessage
+	for rows.Next() {
+		var payload []byte
+		message := &ScheduledMessage{}
+		err = rows.Scan(
+			&message.ID,
+			&message.ChatID,
+			&message.ScheduledAt,
+			&message.InstallationID,
+			&payload,
+			&message.Clock,
+			&message.Deleted,
+		)
+		if err != nil {
+			return nil, err
+		}
+
+		chatMessage := &protobuf.ChatMessage{}
+		err = proto.Unmarshal(payload, chatMessage)
+		if err != nil {
+			return nil, err
+		}
+
+		message.Message = &common.Message{
+			ID:          message.ID,
+			LocalChatID: message.ChatID,
+			ChatMessage: chatMessage,
+		}
+		messages = append(messages, message)
+	}
+
+	return messages, rows.Err()
+}
@@ -100,6 +100,247 @@ func TestMessageByID(t *testing.T) {
 	require.EqualValues(t, id, m.ID)
 }
 
+func TestMessageByID_WithForwardedFrom(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+	id := "1"
+
+	err = p.SaveMessages([]*common.Message{{
+		ID:          id,
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			Text: "some-text",
+			ForwardedFrom: &protobuf.ForwardedFrom{
+				ChatId:    "original-chat",
+				MessageId: "original-message",
+				Sender:    testPK,
+			},
+		},
+		From: testPK,
+	}})
+	require.NoError(t, err)
+
+	m, err := p.MessageByID(id)
+	require.NoError(t, err)
+	require.NotNil(t, m.ForwardedFrom)
+	require.Equal(t, "original-chat", m.ForwardedFrom.ChatId)
+	require.Equal(t, "original-message", m.ForwardedFrom.MessageId)
+	require.Equal(t, testPK, m.ForwardedFrom.Sender)
+}
+
+func TestMessageByID_WithFile(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+	id := "1"
+
+	file, err := common.EncryptFile("spec.pdf", []byte("some-spec"))
+	require.NoError(t, err)
+
+	err = p.SaveMessages([]*common.Message{{
+		ID:          id,
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			ContentType: protobuf.ChatMessage_FILE,
+			Payload:     &protobuf.ChatMessage_File{File: &protobuf.FileMessage{Name: file.Name, Size: file.Size, MimeType: file.MimeType, Hash: file.Hash, Key: file.Key}},
+		},
+		FilePayload: file.Payload,
+		From:        testPK,
+	}})
+	require.NoError(t, err)
+
+	// The payload is stored apart from the manifest
+	m, err := p.MessageByID(id)
+	require.NoError(t, err)
+	require.NotNil(t, m.GetFile())
+	require.Empty(t, m.GetFile().Payload)
+	require.Equal(t, "spec.pdf", m.GetFile().Name)
+	require.Equal(t, "application/pdf", m.GetFile().MimeType)
+
+	payload, err := p.FileAttachmentPayload(id)
+	require.NoError(t, err)
+	require.Equal(t, file.Payload, payload)
+
+	content, err := common.DecryptFile(m.GetFile(), payload)
+	require.NoError(t, err)
+	require.Equal(t, []byte("some-spec"), content)
+
+	payload, err = p.FileAttachmentPayload("2")
+	require.NoError(t, err)
+	require.Nil(t, payload)
+
+	// The payload is deleted along with the message
+	_, err = db.Exec(`UPDATE user_messages SET deleted = 1 WHERE id = ?`, id)
+	require.NoError(t, err)
+	payload, err = p.FileAttachmentPayload(id)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+
+	require.NoError(t, p.SaveFileAttachment(id, file.Payload))
+	require.NoError(t, p.DeleteMessage(id))
+	payload, err = p.FileAttachmentPayload(id)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+}
+
+func TestMessageByID_WithImagePlaceholder(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+	id := "1"
+
+	err = p.SaveMessages([]*common.Message{{
+		ID:          id,
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			ContentType: protobuf.ChatMessage_IMAGE,
+			Payload: &protobuf.ChatMessage_Image{Image: &protobuf.ImageMessage{
+				Payload:     []byte("some-image"),
+				Format:      protobuf.ImageFormat_JPEG,
+				Placeholder: []byte("some-placeholder"),
+			}},
+		},
+		From: testPK,
+	}})
+	require.NoError(t, err)
+
+	m, err := p.MessageByID(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("some-placeholder"), m.GetImage().Placeholder)
+}
+
+func TestMessageByID_WithVideo(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+	id := "1"
+
+	err = p.SaveMessages([]*common.Message{{
+		ID:          id,
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			ContentType: protobuf.ChatMessage_VIDEO,
+			Payload: &protobuf.ChatMessage_Video{Video: &protobuf.VideoMessage{
+				Payload:    []byte("some-video"),
+				Format:     protobuf.VideoMessage_MP4,
+				DurationMs: 12500,
+				VideoCodec: "avc1",
+				Thumbnail:  []byte("some-thumbnail"),
+			}},
+		},
+		From: testPK,
+	}})
+	require.NoError(t, err)
+
+	m, err := p.MessageByID(id)
+	require.NoError(t, err)
+	require.NotNil(t, m.GetVideo())
+	require.Empty(t, m.GetVideo().Payload)
+	require.Equal(t, uint64(12500), m.GetVideo().DurationMs)
+	require.Equal(t, "avc1", m.GetVideo().VideoCodec)
+	require.Equal(t, []byte("some-thumbnail"), m.GetVideo().Thumbnail)
+
+	payload, err := p.VideoPayload(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("some-video"), payload)
+
+	// The payload is deleted along with the message
+	require.NoError(t, p.DeleteMessage(id))
+	payload, err = p.VideoPayload(id)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+}
+
+func TestMessageByID_WithAudio(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+	id := "1"
+
+	err = p.SaveMessages([]*common.Message{{
+		ID:          id,
+		LocalChatID: testPublicChatID,
+		ChatMessage: &protobuf.ChatMessage{
+			ContentType: protobuf.ChatMessage_AUDIO,
+			Payload: &protobuf.ChatMessage_Audio{Audio: &protobuf.AudioMessage{
+				Payload:    []byte("some-audio"),
+				Type:       protobuf.AudioMessage_AMR,
+				DurationMs: 1500,
+				Waveform:   []byte{0, 127, 255},
+			}},
+		},
+		From: testPK,
+	}})
+	require.NoError(t, err)
+
+	m, err := p.MessageByID(id)
+	require.NoError(t, err)
+	require.NotNil(t, m.GetAudio())
+	require.Equal(t, uint64(1500), m.GetAudio().DurationMs)
+	require.Equal(t, []byte{0, 127, 255}, m.GetAudio().Waveform)
+
+	transcode, err := p.AudioTranscode(id)
+	require.NoError(t, err)
+	require.Nil(t, transcode)
+
+	require.NoError(t, p.SaveAudioTranscode(id, []byte("some-transcode")))
+	transcode, err = p.AudioTranscode(id)
+	require.NoError(t, err)
+	require.Equal(t, []byte("some-transcode"), transcode)
+
+	// Transcodes follow the retention of the other media payloads
+	require.NoError(t, p.DeleteMessage(id))
+	pruned, err := p.PruneMediaPayloads(0, 1024)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), pruned)
+	transcode, err = p.AudioTranscode(id)
+	require.NoError(t, err)
+	require.Nil(t, transcode)
+}
+
+func TestPruneMediaPayloads(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+
+	var messages []*common.Message
+	for _, id := range []string{"1", "2", "3", "4"} {
+		messages = append(messages, &common.Message{
+			ID:          id,
+			LocalChatID: testPublicChatID,
+			ChatMessage: &protobuf.ChatMessage{
+				ContentType: protobuf.ChatMessage_VIDEO,
+				Payload:     &protobuf.ChatMessage_Video{Video: &protobuf.VideoMessage{Payload: make([]byte, 100)}},
+			},
+			From: testPK,
+		})
+	}
+	messages[3].Deleted = true
+	require.NoError(t, p.SaveMessages(messages))
+
+	_, err = db.Exec(`UPDATE chat_video_payloads SET stored_at = 1000 WHERE message_id = ?`, "1")
+	require.NoError(t, err)
+	_, err = db.Exec(`UPDATE chat_video_payloads SET stored_at = 3000 WHERE message_id = ?`, "2")
+	require.NoError(t, err)
+
+	// The expired and deleted payloads go first, then the oldest ones past the size limit
+	pruned, err := p.PruneMediaPayloads(2000, 100)
+	require.NoError(t, err)
+	require.Equal(t, int64(3), pruned)
+
+	for id, expected := range map[string]bool{"1": false, "2": false, "3": true, "4": false} {
+		payload, err := p.VideoPayload(id)
+		require.NoError(t, err)
+		require.Equal(t, expected, payload != nil, id)
+	}
+
+	// The messages are kept
+	m, err := p.MessageByID("1")
+	require.NoError(t, err)
+	require.NotNil(t, m.GetVideo())
+}
+
 func TestMessageByID_WithDiscordMessagePayload(t *testing.T) {
 
 	db, err := openTestDB()
@@ -2170,3 +2411,196 @@ func TestPaymentRequestMessages(t *testing.T) {
 
 	require.Equal(t, m[0].PaymentRequests, message.PaymentRequests)
 }
+
+func TestScheduledMessages(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+
+	newScheduledMessage := func(id string, chatID string, installationID string, scheduledAt uint64) *ScheduledMessage {
+		return &ScheduledMessage{
+			ID:             id,
+			ChatID:         chatID,
+			ScheduledAt:    scheduledAt,
+			InstallationID: installationID,
+			Clock:          1,
+			Message: &common.Message{
+				ChatMessage: &protobuf.ChatMessage{
+					ChatId:      chatID,
+					Text:        "scheduled " + id,
+					ContentType: protobuf.ChatMessage_TEXT_PLAIN,
+				},
+			},
+		}
+	}
+
+	require.NoError(t, p.SaveScheduledMessage(newScheduledMessage("1", "chat-1", "installation-1", 100)))
+	require.NoError(t, p.SaveScheduledMessage(newScheduledMessage("2", "chat-1", "installation-2", 200)))
+	require.NoError(t, p.SaveScheduledMessage(newScheduledMessage("3", "chat-2", "installation-1", 300)))
+
+	scheduled, err := p.ScheduledMessage("1")
+	require.NoError(t, err)
+	require.NotNil(t, scheduled)
+	require.Equal(t, "scheduled 1", scheduled.Message.Text)
+	require.Equal(t, "chat-1", scheduled.Message.LocalChatID)
+
+	scheduled, err = p.ScheduledMessage("4")
+	require.NoError(t, err)
+	require.Nil(t, scheduled)
+
+	scheduledMessages, err := p.ScheduledMessages("chat-1")
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 2)
+	require.Equal(t, "1", scheduledMessages[0].ID)
+
+	scheduledMessages, err = p.ScheduledMessages("")
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 3)
+
+	// Only messages of our installation are due
+	dueMessages, err := p.DueScheduledMessages("installation-1", 250)
+	require.NoError(t, err)
+	require.Len(t, dueMessages, 1)
+	require.Equal(t, "1", dueMessages[0].ID)
+
+	cancelled := newScheduledMessage("1", "chat-1", "installation-1", 100)
+	cancelled.Deleted = true
+	cancelled.Clock = 2
+	require.NoError(t, p.SaveScheduledMessage(cancelled))
+
+	dueMessages, err = p.DueScheduledMessages("installation-1", 250)
+	require.NoError(t, err)
+	require.Len(t, dueMessages, 0)
+
+	scheduledMessages, err = p.ScheduledMessages("")
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 2)
+
+	// Deleted messages are kept for syncing
+	scheduledMessages, err = p.AllScheduledMessages()
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 3)
+
+	// Tombstones older than the cutoff are pruned, pending messages never are
+	require.NoError(t, p.DeleteScheduledMessageTombstones(2))
+	scheduledMessages, err = p.AllScheduledMessages()
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 3)
+
+	require.NoError(t, p.DeleteScheduledMessageTombstones(3))
+	scheduledMessages, err = p.AllScheduledMessages()
+	require.NoError(t, err)
+	require.Len(t, scheduledMessages, 2)
+}
+
+func TestHistoryGaps(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+
+	require.NoError(t, p.SaveHistoryGap(&HistoryGap{ID: "1", ChatID: "chat-1", From: 100, To: 200, Source: HistoryGapSourceClock}))
+	require.NoError(t, p.SaveHistoryGap(&HistoryGap{ID: "2", ChatID: "chat-1", From: 300, To: 400, Source: HistoryGapSourceDatasync, Attempts: 1, NextAttemptAt: 1000}))
+	require.NoError(t, p.SaveHistoryGap(&HistoryGap{ID: "3", ChatID: "chat-2", From: 100, To: 200, Source: HistoryGapSourceStoreQuery, Attempts: historyGapMaxAttempts}))
+
+	gaps, err := p.HistoryGaps("chat-1")
+	require.NoError(t, err)
+	require.Len(t, gaps, 2)
+	require.Equal(t, "1", gaps[0].ID)
+	require.Equal(t, HistoryGapSourceDatasync, gaps[1].Source)
+
+	// Gaps waiting for their backoff or failed too many times are not due
+	dueGaps, err := p.DueHistoryGaps(500, historyGapMaxAttempts)
+	require.NoError(t, err)
+	require.Len(t, dueGaps, 1)
+	require.Equal(t, "1", dueGaps[0].ID)
+
+	gaps[0].Resolved = true
+	require.NoError(t, p.SaveHistoryGap(gaps[0]))
+
+	gaps, err = p.HistoryGaps("")
+	require.NoError(t, err)
+	require.Len(t, gaps, 2)
+
+	resolvedUntil, err := p.HistoryGapResolvedUntil("chat-1", 120)
+	require.NoError(t, err)
+	require.Equal(t, uint32(200), resolvedUntil)
+
+	resolvedUntil, err = p.HistoryGapResolvedUntil("chat-1", 250)
+	require.NoError(t, err)
+	require.Equal(t, uint32(250), resolvedUntil)
+
+	// Adjacent resolved gaps are followed
+	require.NoError(t, p.SaveHistoryGap(&HistoryGap{ID: "4", ChatID: "chat-1", From: 200, To: 260, Resolved: true}))
+	resolvedUntil, err = p.HistoryGapResolvedUntil("chat-1", 120)
+	require.NoError(t, err)
+	require.Equal(t, uint32(260), resolvedUntil)
+
+	gapMessage := &common.Message{
+		ID:          "gap-1",
+		LocalChatID: "chat-1",
+		ChatMessage: &protobuf.ChatMessage{
+			ChatId:      "chat-1",
+			Text:        "Gap message",
+			MessageType: protobuf.MessageType_SYSTEM_MESSAGE_GAP,
+			ContentType: protobuf.ChatMessage_SYSTEM_MESSAGE_GAP,
+			Clock:       150000,
+		},
+		GapParameters: &common.GapParameters{From: 110, To: 150},
+	}
+	require.NoError(t, p.SaveMessages([]*common.Message{gapMessage}))
+
+	ids, err := p.GapMessageIDs("chat-1", 100, 200)
+	require.NoError(t, err)
+	require.Equal(t, []string{"gap-1"}, ids)
+
+	ids, err = p.GapMessageIDs("chat-1", 120, 200)
+	require.NoError(t, err)
+	require.Empty(t, ids)
+}
+
+func TestLinkPreviewCache(t *testing.T) {
+	db, err := openTestDB()
+	require.NoError(t, err)
+	p := newSQLitePersistence(db)
+
+	preview := &common.LinkPreview{
+		Type:     protobuf.UnfurledLink_LINK,
+		URL:      "https://status.im",
+		Hostname: "status.im",
+		Title:    "Status",
+	}
+	require.NoError(t, p.CacheLinkPreview(preview.URL, preview, 100))
+
+	cached, err := p.CachedLinkPreview(preview.URL, 50)
+	require.NoError(t, err)
+	require.Equal(t, preview, cached)
+
+	// Rich previews of the same URL are cached apart
+	richPreview := &common.RichLinkPreview{
+		URL:        preview.URL,
+		SocialPost: &common.SocialPostLinkPreview{AuthorHandle: "@status", Text: "Hello"},
+	}
+	cachedRich, err := p.CachedRichLinkPreview(preview.URL, 50)
+	require.NoError(t, err)
+	require.Nil(t, cachedRich)
+	require.NoError(t, p.CacheRichLinkPreview(preview.URL, richPreview, 100))
+	cachedRich, err = p.CachedRichLinkPreview(preview.URL, 50)
+	require.NoError(t, err)
+	require.Equal(t, richPreview, cachedRich)
+	cached, err = p.CachedLinkPreview(preview.URL, 50)
+	require.NoError(t, err)
+	require.Equal(t, preview, cached)
+
+	// Stale previews are fetched again
+	cached, err = p.CachedLinkPreview(preview.URL, 150)
+	require.NoError(t, err)
+	require.Nil(t, cached)
+
+	pruned, err := p.PruneLinkPreviewCache(150)
+	require.NoError(t, err)
+	require.Equal(t, int64(2), pruned)
+
+	cached, err = p.CachedLinkPreview(preview.URL, 0)
+	require.NoError(t, err)
+	require.Nil(t, cached)
+}
@@ -0,0 +1,23 @@
+package protocol
+
+import (
+	"database/sql"
+	"time"
+)
+
+// saveVideoPayload stores the payload of a video apart from its message,
+// so that it's only loaded when the video is played
+func (db sqlitePersistence) saveVideoPayload(tx *sql.Tx, messageID string, payload []byte) error {
+	_, err := tx.Exec(`INSERT OR REPLACE INTO chat_video_payloads (message_id, payload, stored_at) VALUES (?, ?, ?)`, messageID, payload, time.Now().Unix())
+	return err
+}
+
+// VideoPayload returns the payload of the video of a message
+func (db sqlitePersistence) VideoPayload(messageID string) ([]byte, error) {
+	var payload []byte
+	err := db.db.QueryRow(`SELECT payload FROM chat_video_payloads WHERE message_id = ?`, messageID).Scan(&payload)
+	if err == sql.ErrNoRows {
+		return nil, nil
+	}
+	return payload, err
+}
@@ -30,6 +30,13 @@ type APIConfig struct {
 	WSEnabled        bool     `json:"wsEnabled"`
 	WSHost           string   `json:"wsHost"`
 	WSPort           int      `json:"wsPort"`
+	MetricsEnabled   bool     `json:"metricsEnabled"`
+	MetricsAddress   string   `json:"metricsAddress"`
+
+	TracingEnabled      bool    `json:"tracingEnabled"`
+	TracingSampleRatio  float64 `json:"tracingSampleRatio"`
+	TracingOTLPEndpoint string  `json:"tracingOtlpEndpoint"`
+	TracingFilePath     string  `json:"tracingFilePath"`
 }
 
 type CreateAccount struct {
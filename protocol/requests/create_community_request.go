@@ -52,6 +52,8 @@ func adaptIdentityImageToProtobuf(img images.IdentityImage) *protobuf.IdentityIm
 		Payload:     img.Payload,
 		SourceType:  protobuf.IdentityImage_RAW_PAYLOAD,
 		ImageFormat: images.GetProtobufImageFormat(img.Payload),
+
+		AnimatedPayload: img.AnimatedPayload,
 	}
 }
 
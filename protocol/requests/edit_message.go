@@ -15,6 +15,7 @@ type EditMessage struct {
 	Text               string                     `json:"text"`
 	LinkPreviews       []common.LinkPreview       `json:"linkPreviews"`
 	StatusLinkPreviews []common.StatusLinkPreview `json:"statusLinkPreviews"`
+	RichLinkPreviews   []common.RichLinkPreview   `json:"richLinkPreviews"`
 }
 
 func (e *EditMessage) Validate() error {
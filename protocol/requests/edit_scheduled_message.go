@@ -0,0 +1,33 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/protocol/common"
+)
+
+var ErrEditScheduledMessageInvalidID = errors.New("edit-scheduled-message: invalid id")
+var ErrEditScheduledMessageNoChanges = errors.New("edit-scheduled-message: nothing to edit")
+
+type EditScheduledMessage struct {
+	ID string `json:"id"`
+	// Text replaces the text of the message if not empty
+	Text               string                     `json:"text"`
+	LinkPreviews       []common.LinkPreview       `json:"linkPreviews"`
+	StatusLinkPreviews []common.StatusLinkPreview `json:"statusLinkPreviews"`
+	RichLinkPreviews   []common.RichLinkPreview   `json:"richLinkPreviews"`
+	// ScheduledAt moves the message to a new time if not 0, in milliseconds
+	ScheduledAt uint64 `json:"scheduledAt"`
+}
+
+func (e *EditScheduledMessage) Validate() error {
+	if len(e.ID) == 0 {
+		return ErrEditScheduledMessageInvalidID
+	}
+
+	if len(e.Text) == 0 && e.ScheduledAt == 0 {
+		return ErrEditScheduledMessageNoChanges
+	}
+
+	return nil
+}
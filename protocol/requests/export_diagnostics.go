@@ -0,0 +1,20 @@
+package requests
+
+import (
+	"errors"
+)
+
+var ErrExportDiagnosticsInvalidPath = errors.New("export-diagnostics: invalid bundle path")
+
+type ExportDiagnostics struct {
+	// BundlePath is the file the zip archive is written to
+	BundlePath string `json:"bundlePath"`
+}
+
+func (e *ExportDiagnostics) Validate() error {
+	if len(e.BundlePath) == 0 {
+		return ErrExportDiagnosticsInvalidPath
+	}
+
+	return nil
+}
@@ -0,0 +1,26 @@
+package requests
+
+import (
+	"errors"
+)
+
+var ErrExportLocalBackupInvalidPassword = errors.New("export-local-backup: invalid password")
+var ErrExportLocalBackupInvalidPath = errors.New("export-local-backup: invalid backup path")
+
+type ExportLocalBackup struct {
+	Password string `json:"password"`
+	// BackupPath is the file the archive is written to
+	BackupPath string `json:"backupPath"`
+}
+
+func (e *ExportLocalBackup) Validate() error {
+	if len(e.Password) == 0 {
+		return ErrExportLocalBackupInvalidPassword
+	}
+
+	if len(e.BackupPath) == 0 {
+		return ErrExportLocalBackupInvalidPath
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package requests
+
+import (
+	"errors"
+)
+
+var ErrForwardMessagesInvalidMessageIDs = errors.New("forward-messages: invalid message ids")
+var ErrForwardMessagesInvalidChatIDs = errors.New("forward-messages: invalid chat ids")
+
+type ForwardMessages struct {
+	MessageIDs []string `json:"messageIds"`
+	// ChatIDs are the chats the messages are forwarded to
+	ChatIDs []string `json:"chatIds"`
+	// WithForwardedFrom attaches a reference to the original messages
+	WithForwardedFrom bool `json:"withForwardedFrom"`
+}
+
+func (f *ForwardMessages) Validate() error {
+	if len(f.MessageIDs) == 0 {
+		return ErrForwardMessagesInvalidMessageIDs
+	}
+
+	if len(f.ChatIDs) == 0 {
+		return ErrForwardMessagesInvalidChatIDs
+	}
+
+	return nil
+}
@@ -2,6 +2,8 @@ package requests
 
 import (
 	"errors"
+
+	"github.com/status-im/status-go/logutils"
 )
 
 var (
@@ -23,11 +25,16 @@ type InitializeApplication struct {
 	LogEnabled        bool   `json:"logEnabled"`
 	LogLevel          string `json:"logLevel"`
 	APILoggingEnabled bool   `json:"apiLoggingEnabled"`
+
+	// LogRedaction overrides the redaction policies of the logs, e.g. "secrets,protocol:full".
+	// If empty, release builds redact secrets and personal data and development builds only secrets,
+	// unless the build disables redaction.
+	LogRedaction string `json:"logRedaction"`
 }
 
 func (i *InitializeApplication) Validate() error {
 	if len(i.DataDir) == 0 {
 		return ErrInitializeApplicationInvalidDataDir
 	}
-	return nil
+	return logutils.ValidateRedactionPolicies(i.LogRedaction)
 }
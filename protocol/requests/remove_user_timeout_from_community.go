@@ -0,0 +1,27 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+var ErrRemoveUserTimeoutFromCommunityInvalidCommunityID = errors.New("remove-user-timeout-from-community: invalid community id")
+var ErrRemoveUserTimeoutFromCommunityInvalidUser = errors.New("remove-user-timeout-from-community: invalid user id")
+
+type RemoveUserTimeoutFromCommunity struct {
+	CommunityID types.HexBytes `json:"communityId"`
+	User        types.HexBytes `json:"user"`
+}
+
+func (r *RemoveUserTimeoutFromCommunity) Validate() error {
+	if len(r.CommunityID) == 0 {
+		return ErrRemoveUserTimeoutFromCommunityInvalidCommunityID
+	}
+
+	if len(r.User) == 0 {
+		return ErrRemoveUserTimeoutFromCommunityInvalidUser
+	}
+
+	return nil
+}
@@ -0,0 +1,28 @@
+package requests
+
+import (
+	"errors"
+)
+
+var ErrRepairChatHistoryInvalidChatID = errors.New("repair-chat-history: invalid chat id")
+var ErrRepairChatHistoryInvalidRange = errors.New("repair-chat-history: invalid range")
+
+// RepairChatHistory requests the history of a chat to be fetched again from the store nodes.
+// From and To are in seconds, they default to the period the chat was synced for.
+type RepairChatHistory struct {
+	ChatID string `json:"chatId"`
+	From   uint32 `json:"from"`
+	To     uint32 `json:"to"`
+}
+
+func (r *RepairChatHistory) Validate() error {
+	if len(r.ChatID) == 0 {
+		return ErrRepairChatHistoryInvalidChatID
+	}
+
+	if r.To != 0 && r.From > r.To {
+		return ErrRepairChatHistoryInvalidRange
+	}
+
+	return nil
+}
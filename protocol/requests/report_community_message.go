@@ -0,0 +1,35 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const maxReportCommunityMessageDescriptionLength = 1024
+
+var ErrReportCommunityMessageInvalidMessageID = errors.New("report-community-message: invalid message id")
+var ErrReportCommunityMessageInvalidReason = errors.New("report-community-message: invalid reason")
+var ErrReportCommunityMessageDescriptionTooLong = errors.New("report-community-message: description too long")
+
+type ReportCommunityMessage struct {
+	MessageID   string                                 `json:"messageId"`
+	Reason      protobuf.CommunityMessageReport_Reason `json:"reason"`
+	Description string                                 `json:"description"`
+}
+
+func (r *ReportCommunityMessage) Validate() error {
+	if len(r.MessageID) == 0 {
+		return ErrReportCommunityMessageInvalidMessageID
+	}
+
+	if r.Reason == protobuf.CommunityMessageReport_UNKNOWN_REASON {
+		return ErrReportCommunityMessageInvalidReason
+	}
+
+	if len([]rune(r.Description)) > maxReportCommunityMessageDescriptionLength {
+		return ErrReportCommunityMessageDescriptionTooLong
+	}
+
+	return nil
+}
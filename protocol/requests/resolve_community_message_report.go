@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+type CommunityMessageReportAction uint
+
+const (
+	CommunityMessageReportActionDismiss CommunityMessageReportAction = iota + 1
+	CommunityMessageReportActionDeleteMessage
+	CommunityMessageReportActionBanMember
+)
+
+var ErrResolveCommunityMessageReportInvalidReportID = errors.New("resolve-community-message-report: invalid report id")
+var ErrResolveCommunityMessageReportInvalidAction = errors.New("resolve-community-message-report: invalid action")
+
+type ResolveCommunityMessageReport struct {
+	ReportID types.HexBytes               `json:"reportId"`
+	Action   CommunityMessageReportAction `json:"action"`
+	// DeleteAllMessages deletes all messages of the member when banning
+	DeleteAllMessages bool `json:"deleteAllMessages"`
+}
+
+func (r *ResolveCommunityMessageReport) Validate() error {
+	if len(r.ReportID) == 0 {
+		return ErrResolveCommunityMessageReportInvalidReportID
+	}
+
+	if r.Action < CommunityMessageReportActionDismiss || r.Action > CommunityMessageReportActionBanMember {
+		return ErrResolveCommunityMessageReportInvalidAction
+	}
+
+	return nil
+}
@@ -7,6 +7,7 @@ import (
 var (
 	ErrRestoreAccountInvalidMnemonic    = errors.New("restore-account: no mnemonic or keycard is set")
 	ErrRestoreAccountMnemonicAndKeycard = errors.New("restore-account: both mnemonic and keycard info are set")
+	ErrRestoreAccountLocalBackupKeycard = errors.New("restore-account: local backup can only be restored with a mnemonic")
 )
 
 type RestoreAccount struct {
@@ -18,6 +19,10 @@ type RestoreAccount struct {
 
 	FetchBackup bool `json:"fetchBackup"`
 
+	// LocalBackupPath is the path of an archive exported with ExportLocalBackup.
+	// When set, the account databases are restored from the archive instead of being created.
+	LocalBackupPath string `json:"localBackupPath"`
+
 	CreateAccount
 }
 
@@ -30,6 +35,10 @@ func (c *RestoreAccount) Validate() error {
 		return ErrRestoreAccountMnemonicAndKeycard
 	}
 
+	if len(c.LocalBackupPath) > 0 && c.Keycard != nil {
+		return ErrRestoreAccountLocalBackupKeycard
+	}
+
 	return c.CreateAccount.Validate(&CreateAccountValidation{
 		AllowEmptyDisplayName: true,
 	})
@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// MaxCommunityChannelSlowModeInterval caps the slow mode interval to 6 hours
+const MaxCommunityChannelSlowModeInterval = 6 * 60 * 60
+
+var ErrSetCommunityChannelSlowModeInvalidCommunityID = errors.New("set-community-channel-slow-mode: invalid community id")
+var ErrSetCommunityChannelSlowModeInvalidChatID = errors.New("set-community-channel-slow-mode: invalid chat id")
+var ErrSetCommunityChannelSlowModeInvalidInterval = errors.New("set-community-channel-slow-mode: invalid interval")
+
+type SetCommunityChannelSlowMode struct {
+	CommunityID types.HexBytes `json:"communityId"`
+	ChatID      string         `json:"chatId"`
+	// Interval in seconds between two messages of the same member, 0 disables slow mode
+	Interval uint32 `json:"interval"`
+}
+
+func (s *SetCommunityChannelSlowMode) Validate() error {
+	if len(s.CommunityID) == 0 {
+		return ErrSetCommunityChannelSlowModeInvalidCommunityID
+	}
+
+	if len(s.ChatID) == 0 {
+		return ErrSetCommunityChannelSlowModeInvalidChatID
+	}
+
+	if s.Interval > MaxCommunityChannelSlowModeInterval {
+		return ErrSetCommunityChannelSlowModeInvalidInterval
+	}
+
+	return nil
+}
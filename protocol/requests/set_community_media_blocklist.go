@@ -0,0 +1,47 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/protocol/contentsafety"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+// MaxCommunityMediaBlocklistSize caps the hashes carried in every community description
+const MaxCommunityMediaBlocklistSize = 4096
+
+var ErrSetCommunityMediaBlocklistInvalidCommunityID = errors.New("set-community-media-blocklist: invalid community id")
+var ErrSetCommunityMediaBlocklistTooLarge = errors.New("set-community-media-blocklist: too many hashes")
+
+type SetCommunityMediaBlocklist struct {
+	CommunityID types.HexBytes `json:"communityId"`
+	// Hashes replace the community blocklist, an empty list clears it
+	Hashes []contentsafety.Hash `json:"hashes"`
+}
+
+func (s *SetCommunityMediaBlocklist) Validate() error {
+	if len(s.CommunityID) == 0 {
+		return ErrSetCommunityMediaBlocklistInvalidCommunityID
+	}
+
+	if len(s.Hashes) > MaxCommunityMediaBlocklistSize {
+		return ErrSetCommunityMediaBlocklistTooLarge
+	}
+
+	for _, hash := range s.Hashes {
+		if err := hash.Validate(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (s *SetCommunityMediaBlocklist) ToProtobuf() []*protobuf.CommunityMediaHash {
+	hashes := make([]*protobuf.CommunityMediaHash, 0, len(s.Hashes))
+	for _, hash := range s.Hashes {
+		hashes = append(hashes, hash.ToProtobuf())
+	}
+	return hashes
+}
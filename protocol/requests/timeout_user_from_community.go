@@ -0,0 +1,37 @@
+package requests
+
+import (
+	"errors"
+
+	"github.com/status-im/status-go/eth-node/types"
+)
+
+// MaxCommunityMemberTimeoutMinutes caps a single timeout to 28 days
+const MaxCommunityMemberTimeoutMinutes = 28 * 24 * 60
+
+var ErrTimeoutUserFromCommunityInvalidCommunityID = errors.New("timeout-user-from-community: invalid community id")
+var ErrTimeoutUserFromCommunityInvalidUser = errors.New("timeout-user-from-community: invalid user id")
+var ErrTimeoutUserFromCommunityInvalidDuration = errors.New("timeout-user-from-community: invalid duration")
+
+type TimeoutUserFromCommunity struct {
+	CommunityID types.HexBytes `json:"communityId"`
+	User        types.HexBytes `json:"user"`
+	// Duration of the timeout in minutes
+	Duration uint64 `json:"duration"`
+}
+
+func (t *TimeoutUserFromCommunity) Validate() error {
+	if len(t.CommunityID) == 0 {
+		return ErrTimeoutUserFromCommunityInvalidCommunityID
+	}
+
+	if len(t.User) == 0 {
+		return ErrTimeoutUserFromCommunityInvalidUser
+	}
+
+	if t.Duration == 0 || t.Duration > MaxCommunityMemberTimeoutMinutes {
+		return ErrTimeoutUserFromCommunityInvalidDuration
+	}
+
+	return nil
+}
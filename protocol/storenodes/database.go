@@ -6,6 +6,7 @@ import (
 	"fmt"
 	"time"
 
+	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/multiformats/go-multiaddr"
 
 	"github.com/status-im/status-go/eth-node/types"
@@ -198,3 +199,71 @@ func find(n Storenode, nodes []Storenode) *Storenode {
 	}
 	return nil
 }
+
+func (d *Database) saveScore(communityID string, s Score) error {
+	_, err := d.db.Exec(`INSERT OR REPLACE INTO storenode_scores(
+		community_id,
+		peer_id,
+		rtt_ms,
+		success_rate,
+		completeness,
+		queries,
+		failed_queries,
+		checked_messages,
+		missing_messages,
+		updated_at
+	) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		communityID,
+		s.PeerID.String(),
+		s.RTT.Milliseconds(),
+		s.SuccessRate,
+		s.Completeness,
+		s.Queries,
+		s.FailedQueries,
+		s.CheckedMessages,
+		s.MissingMessages,
+		s.UpdatedAt,
+	)
+	return err
+}
+
+func (d *Database) getScores(communityID string) ([]Score, error) {
+	rows, err := d.db.Query(`
+		SELECT peer_id, rtt_ms, success_rate, completeness, queries, failed_queries, checked_messages, missing_messages, updated_at
+		FROM storenode_scores
+		WHERE community_id = ?
+	`, communityID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []Score
+	for rows.Next() {
+		var s Score
+		var peerID string
+		var rtt int64
+		if err := rows.Scan(
+			&peerID,
+			&rtt,
+			&s.SuccessRate,
+			&s.Completeness,
+			&s.Queries,
+			&s.FailedQueries,
+			&s.CheckedMessages,
+			&s.MissingMessages,
+			&s.UpdatedAt,
+		); err != nil {
+			return nil, err
+		}
+
+		s.PeerID, err = peer.Decode(peerID)
+		if err != nil {
+			return nil, err
+		}
+		s.RTT = time.Duration(rtt) * time.Millisecond
+		result = append(result, s)
+	}
+
+	return result, rows.Err()
+}
@@ -1,6 +1,5 @@
 // package storenodes provides functionality to work with community specific storenodes
 // Current limitations:
 // - we support only one storenode per community
-// - we assume that the storenode is always active
-// - we don't support a way to regularly check connection similar to the `messenger_mailserver_cycle.go`
+// - the storenode is not cycled like in `messenger_mailserver_cycle.go`, the fleet storenode is used instead when it scores better (see `Scores`)
 package storenodes
@@ -0,0 +1,213 @@
+package storenodes
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+)
+
+const (
+	// scoreSmoothing is the weight of the latest result in the moving averages of a score
+	scoreSmoothing = 0.2
+	// referenceRTT is the RTT for which a storenode gets half of the RTT points
+	referenceRTT = 300 * time.Millisecond
+
+	successRateWeight  = 0.4
+	completenessWeight = 0.4
+	rttWeight          = 0.2
+)
+
+// Score holds the quality of a storenode learnt from the past queries and checks
+type Score struct {
+	PeerID peer.ID `json:"peerId"`
+	// RTT is the moving average of the round trip time, 0 when the storenode has never answered a ping
+	RTT time.Duration `json:"rtt"`
+	// SuccessRate is the moving average of the query results, between 0 and 1
+	SuccessRate float64 `json:"successRate"`
+	// Completeness is the moving average of the ratio of checked messages the storenode had, between 0 and 1
+	Completeness    float64 `json:"completeness"`
+	Queries         uint64  `json:"queries"`
+	FailedQueries   uint64  `json:"failedQueries"`
+	CheckedMessages uint64  `json:"checkedMessages"`
+	MissingMessages uint64  `json:"missingMessages"`
+	UpdatedAt       int64   `json:"updatedAt"`
+	// Value is the overall quality of the storenode, between 0 and 1
+	Value float64 `json:"value"`
+}
+
+func newScore(peerID peer.ID) Score {
+	s := Score{
+		PeerID:       peerID,
+		SuccessRate:  1,
+		Completeness: 1,
+	}
+	s.Value = s.value()
+	return s
+}
+
+func (s Score) value() float64 {
+	// storenodes that have never answered get an average RTT
+	rtt := 0.5
+	if s.RTT > 0 {
+		rtt = float64(referenceRTT) / float64(referenceRTT+s.RTT)
+	}
+	return successRateWeight*s.SuccessRate + completenessWeight*s.Completeness + rttWeight*rtt
+}
+
+func movingAverage(average float64, value float64) float64 {
+	return (1-scoreSmoothing)*average + scoreSmoothing*value
+}
+
+// Scores keeps track of the scores of the storenodes, in memory and in the database
+type Scores struct {
+	mutex  sync.RWMutex
+	scores map[peer.ID]Score
+
+	// communityID is empty for the scores of the fleet storenodes
+	communityID string
+	db          *Database
+	logger      *zap.Logger
+}
+
+func NewScores(db *Database, logger *zap.Logger) *Scores {
+	return NewCommunityScores(db, "", logger)
+}
+
+// NewCommunityScores creates the scores of the storenodes checked for the messages of a community,
+// saved apart from the scores of the fleet
+func NewCommunityScores(db *Database, communityID string, logger *zap.Logger) *Scores {
+	if logger == nil {
+		logger = zap.NewNop()
+	}
+	return &Scores{
+		scores:      make(map[peer.ID]Score),
+		communityID: communityID,
+		db:          db,
+		logger:      logger.With(zap.Namespace("StorenodeScores"), zap.String("communityID", communityID)),
+	}
+}
+
+// ReloadFromDB loads the scores saved in the database
+func (s *Scores) ReloadFromDB() error {
+	if s.db == nil {
+		return nil
+	}
+	dbScores, err := s.db.getScores(s.communityID)
+	if err != nil {
+		return err
+	}
+
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	s.scores = make(map[peer.ID]Score)
+	for _, score := range dbScores {
+		score.Value = score.value()
+		s.scores[score.PeerID] = score
+	}
+	s.logger.Debug("loaded storenode scores", zap.Int("count", len(dbScores)))
+	return nil
+}
+
+// RecordRTT records the round trip time of a ping to a storenode
+func (s *Scores) RecordRTT(peerID peer.ID, rtt time.Duration) error {
+	return s.update(peerID, func(score *Score) {
+		if score.RTT == 0 {
+			score.RTT = rtt
+			return
+		}
+		score.RTT = time.Duration(movingAverage(float64(score.RTT), float64(rtt)))
+	})
+}
+
+// RecordQuery records the result of a query to a storenode
+func (s *Scores) RecordQuery(peerID peer.ID, success bool) error {
+	return s.update(peerID, func(score *Score) {
+		score.Queries++
+		result := 1.0
+		if !success {
+			score.FailedQueries++
+			result = 0
+		}
+		score.SuccessRate = movingAverage(score.SuccessRate, result)
+	})
+}
+
+// RecordCompleteness records how many of the checked messages were missing from a storenode
+func (s *Scores) RecordCompleteness(peerID peer.ID, checked int, missing int) error {
+	if checked <= 0 {
+		return nil
+	}
+	return s.update(peerID, func(score *Score) {
+		score.CheckedMessages += uint64(checked)
+		score.MissingMessages += uint64(missing)
+		score.Completeness = movingAverage(score.Completeness, float64(checked-missing)/float64(checked))
+	})
+}
+
+func (s *Scores) update(peerID peer.ID, fn func(*Score)) error {
+	s.mutex.Lock()
+	score, ok := s.scores[peerID]
+	if !ok {
+		score = newScore(peerID)
+	}
+	fn(&score)
+	score.UpdatedAt = time.Now().Unix()
+	score.Value = score.value()
+	s.scores[peerID] = score
+	s.mutex.Unlock()
+
+	if s.db == nil {
+		return nil
+	}
+	return s.db.saveScore(s.communityID, score)
+}
+
+// Get returns the score of a storenode, storenodes without history get a default score
+func (s *Scores) Get(peerID peer.ID) Score {
+	s.mutex.RLock()
+	defer s.mutex.RUnlock()
+
+	score, ok := s.scores[peerID]
+	if !ok {
+		return newScore(peerID)
+	}
+	return score
+}
+
+// All returns the scores of all the storenodes, best first
+func (s *Scores) All() []Score {
+	s.mutex.RLock()
+	result := make([]Score, 0, len(s.scores))
+	for _, score := range s.scores {
+		result = append(result, score)
+	}
+	s.mutex.RUnlock()
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Value == result[j].Value {
+			return result[i].PeerID < result[j].PeerID
+		}
+		return result[i].Value > result[j].Value
+	})
+	return result
+}
+
+// Rank sorts the storenodes from the best to the worst score, storenodes with
+// the same score keep their order
+func (s *Scores) Rank(peerIDs []peer.ID) []peer.ID {
+	values := make(map[peer.ID]float64, len(peerIDs))
+	for _, peerID := range peerIDs {
+		values[peerID] = s.Get(peerID).Value
+	}
+
+	result := make([]peer.ID, len(peerIDs))
+	copy(result, peerIDs)
+	sort.SliceStable(result, func(i, j int) bool {
+		return values[result[i]] > values[result[j]]
+	})
+	return result
+}
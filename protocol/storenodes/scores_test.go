@@ -0,0 +1,98 @@
+package storenodes
+
+import (
+	"testing"
+	"time"
+
+	"github.com/libp2p/go-libp2p/core/peer"
+	"github.com/stretchr/testify/require"
+)
+
+var (
+	peerID1 = mustDecodePeerID("16Uiu2HAkykgaECHswi3YKJ5dMLbq2kPVCo89fcyTd38UcQD6ej5W")
+	peerID2 = mustDecodePeerID("16Uiu2HAkzHaTP5JsUwfR9NR8Rj9HC24puS6ocaU8wze4QrXr9iXp")
+	peerID3 = mustDecodePeerID("16Uiu2HAm2M7xs7cLPc3jamawkEqbr7cUJX11uvY7LxQ6WFUdUKUT")
+)
+
+func mustDecodePeerID(s string) peer.ID {
+	peerID, err := peer.Decode(s)
+	if err != nil {
+		panic(err)
+	}
+	return peerID
+}
+
+func TestScoresRank(t *testing.T) {
+	scores := NewScores(nil, nil)
+
+	// without history, storenodes keep their order
+	require.Equal(t, []peer.ID{peerID1, peerID2, peerID3}, scores.Rank([]peer.ID{peerID1, peerID2, peerID3}))
+
+	require.NoError(t, scores.RecordRTT(peerID1, 800*time.Millisecond))
+	require.NoError(t, scores.RecordRTT(peerID2, 50*time.Millisecond))
+	require.NoError(t, scores.RecordRTT(peerID3, 50*time.Millisecond))
+	require.Equal(t, []peer.ID{peerID2, peerID3, peerID1}, scores.Rank([]peer.ID{peerID1, peerID2, peerID3}))
+
+	require.NoError(t, scores.RecordQuery(peerID2, false))
+	require.Equal(t, []peer.ID{peerID3, peerID2, peerID1}, scores.Rank([]peer.ID{peerID1, peerID2, peerID3}))
+
+	require.NoError(t, scores.RecordCompleteness(peerID3, 10, 10))
+	require.NoError(t, scores.RecordCompleteness(peerID3, 10, 10))
+	require.Equal(t, []peer.ID{peerID2, peerID1, peerID3}, scores.Rank([]peer.ID{peerID1, peerID2, peerID3}))
+
+	all := scores.All()
+	require.Len(t, all, 3)
+	require.Equal(t, peerID2, all[0].PeerID)
+	require.Equal(t, uint64(1), all[0].Queries)
+	require.Equal(t, uint64(1), all[0].FailedQueries)
+	require.Equal(t, uint64(20), all[2].MissingMessages)
+}
+
+func TestScoresRecovery(t *testing.T) {
+	scores := NewScores(nil, nil)
+
+	require.NoError(t, scores.RecordQuery(peerID1, false))
+	failed := scores.Get(peerID1).Value
+
+	for i := 0; i < 10; i++ {
+		require.NoError(t, scores.RecordQuery(peerID1, true))
+	}
+	require.Greater(t, scores.Get(peerID1).Value, failed)
+	require.InDelta(t, newScore(peerID1).Value, scores.Get(peerID1).Value, 0.05)
+}
+
+func TestScoresPersistence(t *testing.T) {
+	db, close := setupTestDB(t)
+	defer close()
+
+	scores := NewScores(db, nil)
+	require.NoError(t, scores.RecordRTT(peerID1, 120*time.Millisecond))
+	require.NoError(t, scores.RecordQuery(peerID1, true))
+	require.NoError(t, scores.RecordQuery(peerID1, false))
+	require.NoError(t, scores.RecordCompleteness(peerID1, 4, 1))
+	require.NoError(t, scores.RecordQuery(peerID2, false))
+
+	reloaded := NewScores(db, nil)
+	require.NoError(t, reloaded.ReloadFromDB())
+	require.Equal(t, scores.All(), reloaded.All())
+}
+
+func TestCommunityScoresPersistence(t *testing.T) {
+	db, close := setupTestDB(t)
+	defer close()
+
+	fleet := NewScores(db, nil)
+	require.NoError(t, fleet.RecordQuery(peerID1, false))
+
+	community := NewCommunityScores(db, "community", nil)
+	require.NoError(t, community.RecordQuery(peerID1, true))
+	require.NoError(t, community.RecordQuery(peerID2, true))
+
+	reloaded := NewCommunityScores(db, "community", nil)
+	require.NoError(t, reloaded.ReloadFromDB())
+	require.Equal(t, community.All(), reloaded.All())
+
+	reloaded = NewScores(db, nil)
+	require.NoError(t, reloaded.ReloadFromDB())
+	require.Equal(t, fleet.All(), reloaded.All())
+}
@@ -55,6 +55,20 @@ func (m *CommunityStorenodes) GetStorenodeByCommunityID(communityID string) (mai
 	return toMailserver(msData.storenodes[0]), nil
 }
 
+// CommunityIDs returns the IDs of the communities with a storenode setup
+func (m *CommunityStorenodes) CommunityIDs() []string {
+	m.storenodesByCommunityIDMutex.RLock()
+	defer m.storenodesByCommunityIDMutex.RUnlock()
+
+	result := make([]string, 0, len(m.storenodesByCommunityID))
+	for communityID, data := range m.storenodesByCommunityID {
+		if len(data.storenodes) > 0 {
+			result = append(result, communityID)
+		}
+	}
+	return result
+}
+
 func (m *CommunityStorenodes) IsCommunityStoreNode(peerID peer.ID) bool {
 	m.storenodesByCommunityIDMutex.RLock()
 	defer m.storenodesByCommunityIDMutex.RUnlock()
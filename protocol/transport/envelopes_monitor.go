@@ -7,10 +7,12 @@ import (
 	"sync"
 	"time"
 
+	prom "github.com/prometheus/client_golang/prometheus"
 	"go.uber.org/zap"
 
 	gocommon "github.com/status-im/status-go/common"
 	"github.com/status-im/status-go/eth-node/types"
+	"github.com/status-im/status-go/tracing"
 )
 
 // EnvelopeState in local tracker
@@ -81,6 +83,34 @@ type monitoredEnvelope struct {
 	message         *types.NewMessage
 	messageIDs      [][]byte
 	lastAttemptTime time.Time
+	// postedTime is the time of the first attempt, used to measure send and confirmation latencies
+	postedTime time.Time
+	// span lasts from the first attempt until the envelope is confirmed or expires
+	span *tracing.Span
+}
+
+// observe records the time elapsed since the envelope was first posted
+func (e *monitoredEnvelope) observe(histogram prom.Histogram) {
+	if !e.postedTime.IsZero() {
+		histogram.Observe(time.Since(e.postedTime).Seconds())
+	}
+}
+
+// confirm marks the envelope as sent and records its confirmation latency
+func (e *monitoredEnvelope) confirm() {
+	e.state = EnvelopeSent
+	e.observe(envelopeConfirmationDuration)
+	e.span.SetAttributes(tracing.Int("attempts", e.attempts))
+	e.span.End()
+}
+
+// expire ends the span of an envelope that is dropped without being confirmed
+func (e *monitoredEnvelope) expire(err error) {
+	if err == nil {
+		err = errors.New("envelope expired")
+	}
+	e.span.SetAttributes(tracing.Int("attempts", e.attempts))
+	e.span.EndWithError(err)
 }
 
 // EnvelopesMonitor is responsible for monitoring waku envelopes state.
@@ -126,11 +156,23 @@ func (m *EnvelopesMonitor) Start() {
 func (m *EnvelopesMonitor) Stop() {
 	close(m.quit)
 	m.wg.Wait()
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	stopped := errors.New("envelopes monitor stopped")
+	for _, envelope := range m.envelopes {
+		if envelope.state != EnvelopeSent {
+			envelope.expire(stopped)
+		}
+	}
+	for _, envelope := range m.retryQueue {
+		envelope.expire(stopped)
+	}
 }
 
 // Add hashes to a tracker.
 // Identifiers may be backed by multiple envelopes. It happens when message is split in segmentation layer.
-func (m *EnvelopesMonitor) Add(messageIDs [][]byte, envelopeHashes []types.Hash, messages []*types.NewMessage) error {
+func (m *EnvelopesMonitor) Add(ctx context.Context, messageIDs [][]byte, envelopeHashes []types.Hash, messages []*types.NewMessage) error {
 	if len(envelopeHashes) != len(messages) {
 		return errors.New("hashes don't match messages")
 	}
@@ -144,14 +186,19 @@ func (m *EnvelopesMonitor) Add(messageIDs [][]byte, envelopeHashes []types.Hash,
 
 	for i, envelopeHash := range envelopeHashes {
 		if _, ok := m.envelopes[envelopeHash]; !ok {
+			now := time.Now()
+			_, span := tracing.Start(ctx, "envelope.confirmation", tracing.String("hash", envelopeHash.String()))
 			m.envelopes[envelopeHash] = &monitoredEnvelope{
 				envelopeHashID:  envelopeHash,
 				state:           EnvelopePosted,
 				attempts:        1,
-				lastAttemptTime: time.Now(),
+				lastAttemptTime: now,
+				postedTime:      now,
 				message:         messages[i],
 				messageIDs:      messageIDs,
+				span:            span,
 			}
+			envelopesPostedCounter.Inc()
 		}
 	}
 
@@ -228,6 +275,7 @@ func (m *EnvelopesMonitor) handleEventEnvelopeSent(event types.EnvelopeEvent) {
 		return
 	}
 	m.logger.Debug("envelope is sent", zap.String("hash", event.Hash.String()), zap.String("peer", event.Peer.String()))
+	envelope.observe(envelopeSendDuration)
 	if confirmationExpected {
 		if _, ok := m.batches[event.Batch]; !ok {
 			m.batches[event.Batch] = map[types.Hash]struct{}{}
@@ -236,7 +284,7 @@ func (m *EnvelopesMonitor) handleEventEnvelopeSent(event types.EnvelopeEvent) {
 		m.logger.Debug("waiting for a confirmation", zap.String("batch", event.Batch.String()))
 	} else {
 		m.logger.Debug("confirmation not expected, marking as sent")
-		envelope.state = EnvelopeSent
+		envelope.confirm()
 		m.processMessageIDs(envelope.messageIDs)
 	}
 }
@@ -283,7 +331,7 @@ func (m *EnvelopesMonitor) handleAcknowledgedBatch(event types.EnvelopeEvent) {
 		if !ok || envelope.state == EnvelopeSent {
 			continue
 		}
-		envelope.state = EnvelopeSent
+		envelope.confirm()
 		m.processMessageIDs(envelope.messageIDs)
 	}
 	delete(m.batches, event.Batch)
@@ -307,6 +355,8 @@ func (m *EnvelopesMonitor) handleEnvelopeFailure(hash types.Hash, err error) {
 			m.retryQueue = append(m.retryQueue, envelope)
 		} else {
 			m.logger.Debug("envelope expired", zap.String("hash", hash.String()))
+			envelopesExpiredCounter.Inc()
+			envelope.expire(err)
 			m.removeFromRetryQueue(hash)
 			if m.handler != nil {
 				m.handler.EnvelopeExpired(envelope.messageIDs, err)
@@ -353,12 +403,18 @@ func (m *EnvelopesMonitor) retryOnce() {
 			}
 
 			m.logger.Debug("retrying to send a message", zap.String("hash", envelope.envelopeHashID.String()), zap.Int("attempt", envelope.attempts+1))
+			envelopesRetriedCounter.Inc()
 			hex, err := m.api.Post(context.TODO(), *envelope.message)
 			if err != nil {
 				m.logger.Error("failed to retry sending message", zap.String("hash", envelope.envelopeHashID.String()), zap.Int("attempt", envelope.attempts+1), zap.Error(err))
 				if m.handler != nil {
 					m.handler.EnvelopeExpired(envelope.messageIDs, err)
 				}
+				if envelope.attempts+1 >= m.maxAttempts {
+					// Nothing retries it anymore
+					envelope.expire(err)
+					m.removeFromRetryQueue(envelope.envelopeHashID)
+				}
 			} else {
 				m.removeFromRetryQueue(envelope.envelopeHashID)
 				envelope.envelopeHashID = types.BytesToHash(hex)
@@ -393,7 +449,7 @@ func (m *EnvelopesMonitor) handleEventEnvelopeReceived(event types.EnvelopeEvent
 		return
 	}
 	m.logger.Debug("expected envelope received", zap.String("hash", event.Hash.String()), zap.String("peer", event.Peer.String()))
-	envelope.state = EnvelopeSent
+	envelope.confirm()
 	m.processMessageIDs(envelope.messageIDs)
 }
 
@@ -62,7 +62,7 @@ func (s *EnvelopesMonitorSuite) SetupTest() {
 }
 
 func (s *EnvelopesMonitorSuite) TestEnvelopePosted() {
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.Contains(s.monitor.envelopes, testHash)
 	s.Equal(EnvelopePosted, s.monitor.envelopes[testHash].state)
@@ -80,7 +80,7 @@ func (s *EnvelopesMonitorSuite) TestEnvelopePostedOutOfOrder() {
 		Hash:  testHash,
 	})
 
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.Require().Contains(s.monitor.envelopes, testHash)
 	s.Require().Equal(EnvelopeSent, s.monitor.envelopes[testHash].state)
@@ -91,7 +91,7 @@ func (s *EnvelopesMonitorSuite) TestConfirmedWithAcknowledge() {
 	pkey, err := crypto.GenerateKey()
 	s.Require().NoError(err)
 	node := enode.NewV4(&pkey.PublicKey, nil, 0, 0)
-	err = s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err = s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.Contains(s.monitor.envelopes, testHash)
 	s.Equal(EnvelopePosted, s.monitor.envelopes[testHash].state)
@@ -111,7 +111,7 @@ func (s *EnvelopesMonitorSuite) TestConfirmedWithAcknowledge() {
 }
 
 func (s *EnvelopesMonitorSuite) TestRemoved() {
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.Contains(s.monitor.envelopes, testHash)
 	s.monitor.handleEvent(types.EnvelopeEvent{
@@ -124,7 +124,7 @@ func (s *EnvelopesMonitorSuite) TestRemoved() {
 func (s *EnvelopesMonitorSuite) TestIgnoreNotFromMailserver() {
 	// enables filter in the tracker to drop confirmations from non-mailserver peers
 	s.monitor.awaitOnlyMailServerConfirmations = true
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.monitor.handleEvent(types.EnvelopeEvent{
 		Event: types.EventEnvelopeSent,
@@ -138,7 +138,7 @@ func (s *EnvelopesMonitorSuite) TestReceived() {
 	s.monitor.isMailserver = func(peer types.EnodeID) bool {
 		return true
 	}
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	s.Contains(s.monitor.envelopes, testHash)
 	s.monitor.handleEvent(types.EnvelopeEvent{
@@ -153,7 +153,7 @@ func (s *EnvelopesMonitorSuite) TestMultipleHashes() {
 	hashes := []types.Hash{{0x01}, {0x02}, {0x03}}
 	messages := []*types.NewMessage{{}, {}, {}}
 
-	err := s.monitor.Add(messageIDs, hashes, messages)
+	err := s.monitor.Add(context.Background(), messageIDs, hashes, messages)
 	s.Require().NoError(err)
 	for _, hash := range hashes {
 		s.Contains(s.monitor.envelopes, hash)
@@ -198,7 +198,7 @@ func (s *EnvelopesMonitorSuite) TestMultipleHashes_EnvelopeExpired() {
 	hashes := []types.Hash{{0x01}, {0x02}, {0x03}}
 	messages := []*types.NewMessage{{}, {}, {}}
 
-	err := s.monitor.Add(messageIDs, hashes, messages)
+	err := s.monitor.Add(context.Background(), messageIDs, hashes, messages)
 	s.Require().NoError(err)
 
 	// If any envelope fails, then messageIDs are considered as not sent
@@ -221,13 +221,13 @@ func (s *EnvelopesMonitorSuite) TestMultipleHashes_EnvelopeExpired() {
 }
 
 func (s *EnvelopesMonitorSuite) TestMultipleHashes_Failure() {
-	err := s.monitor.Add(testIDs, []types.Hash{{0x01}, {0x02}}, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, []types.Hash{{0x01}, {0x02}}, []*types.NewMessage{{}})
 	s.Require().Error(err)
 }
 
 func (s *EnvelopesMonitorSuite) TestRetryOnce() {
 	s.monitor.api = &mockWakuAPI{}
-	err := s.monitor.Add(testIDs, testHashes, []*types.NewMessage{{}})
+	err := s.monitor.Add(context.Background(), testIDs, testHashes, []*types.NewMessage{{}})
 	s.Require().NoError(err)
 	envelope := s.monitor.envelopes[testHash]
 	envelope.attempts = 2
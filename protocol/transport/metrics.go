@@ -0,0 +1,38 @@
+package transport
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	envelopesPostedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "status_envelopes_posted_total",
+		Help: "Number of envelopes posted and tracked by the envelopes monitor.",
+	})
+	envelopesRetriedCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "status_envelopes_retried_total",
+		Help: "Number of envelopes posted again after a failure.",
+	})
+	envelopesExpiredCounter = prom.NewCounter(prom.CounterOpts{
+		Name: "status_envelopes_expired_total",
+		Help: "Number of envelopes given up after the maximum number of attempts.",
+	})
+	envelopeSendDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "status_envelope_send_duration_seconds",
+		Help:    "The time between posting an envelope and it being sent to a peer.",
+		Buckets: prom.ExponentialBuckets(0.01, 2, 14),
+	})
+	envelopeConfirmationDuration = prom.NewHistogram(prom.HistogramOpts{
+		Name:    "status_envelope_confirmation_duration_seconds",
+		Help:    "The time between posting an envelope and its confirmation.",
+		Buckets: prom.ExponentialBuckets(0.05, 2, 14),
+	})
+)
+
+func init() {
+	prom.MustRegister(envelopesPostedCounter)
+	prom.MustRegister(envelopesRetriedCounter)
+	prom.MustRegister(envelopesExpiredCounter)
+	prom.MustRegister(envelopeSendDuration)
+	prom.MustRegister(envelopeConfirmationDuration)
+}
@@ -194,6 +194,11 @@ func (t *Transport) JoinPublic(chatID string) (*Filter, error) {
 	return t.filters.LoadPublic(chatID, "")
 }
 
+// JoinPublicOnPubsubTopic loads the filter of a public topic on a pubsub topic, the default one if empty
+func (t *Transport) JoinPublicOnPubsubTopic(chatID string, pubsubTopic string) (*Filter, error) {
+	return t.filters.LoadPublic(chatID, pubsubTopic)
+}
+
 func (t *Transport) LeavePublic(chatID string) error {
 	chat := t.filters.Filter(chatID)
 	if chat != nil {
@@ -387,11 +392,11 @@ func (t *Transport) addSig(newMessage *types.NewMessage) error {
 	return nil
 }
 
-func (t *Transport) Track(identifier []byte, hashes [][]byte, newMessages []*types.NewMessage) {
-	t.TrackMany([][]byte{identifier}, hashes, newMessages)
+func (t *Transport) Track(ctx context.Context, identifier []byte, hashes [][]byte, newMessages []*types.NewMessage) {
+	t.TrackMany(ctx, [][]byte{identifier}, hashes, newMessages)
 }
 
-func (t *Transport) TrackMany(identifiers [][]byte, hashes [][]byte, newMessages []*types.NewMessage) {
+func (t *Transport) TrackMany(ctx context.Context, identifiers [][]byte, hashes [][]byte, newMessages []*types.NewMessage) {
 	if t.envelopesMonitor == nil {
 		return
 	}
@@ -401,7 +406,7 @@ func (t *Transport) TrackMany(identifiers [][]byte, hashes [][]byte, newMessages
 		envelopeHashes[i] = types.BytesToHash(hash)
 	}
 
-	err := t.envelopesMonitor.Add(identifiers, envelopeHashes, newMessages)
+	err := t.envelopesMonitor.Add(ctx, identifiers, envelopeHashes, newMessages)
 	if err != nil {
 		t.logger.Error("failed to track messages", zap.Error(err))
 	}
@@ -663,6 +668,18 @@ func (t *Transport) PerformStorenodeTask(fn func() error, opts ...history.Storen
 	return t.waku.PerformStorenodeTask(fn, opts...)
 }
 
+func (t *Transport) PingStorenode(ctx context.Context, peerInfo peer.AddrInfo) (time.Duration, error) {
+	return t.waku.PingStorenode(ctx, peerInfo)
+}
+
+func (t *Transport) RecentMessageHashes(pubsubTopic string, from time.Time, to time.Time, limit int) []common.Hash {
+	return t.waku.RecentMessageHashes(pubsubTopic, from, to, limit)
+}
+
+func (t *Transport) CountStoredMessages(ctx context.Context, peerID peer.ID, hashes []common.Hash) (int, error) {
+	return t.waku.CountStoredMessages(ctx, peerID, hashes)
+}
+
 func (t *Transport) ProcessMailserverBatch(
 	ctx context.Context,
 	batch types.MailserverBatch,
@@ -0,0 +1,106 @@
+package video
+
+import (
+	"encoding/binary"
+
+	"github.com/status-im/status-go/protocol/mp4"
+)
+
+// parseMP4 reads the metadata of an MP4 or QuickTime container
+func parseMP4(buf []byte) (*Metadata, error) {
+	boxes, err := mp4.ReadBoxes(buf)
+	if err != nil {
+		return nil, err
+	}
+	if len(boxes) == 0 || boxes[0].Kind != "ftyp" || len(boxes[0].Payload) < 4 {
+		return nil, ErrUnsupportedFormat
+	}
+
+	metadata := &Metadata{Format: FormatMP4}
+	if string(boxes[0].Payload[0:4]) == "qt  " {
+		metadata.Format = FormatMOV
+	}
+
+	var moov []byte
+	for _, b := range boxes {
+		if b.Kind == "moov" {
+			moov = b.Payload
+		}
+	}
+	if moov == nil {
+		return nil, ErrInvalidContainer
+	}
+
+	mvhd, ok := mp4.FindBox(moov, "mvhd")
+	if !ok {
+		return nil, ErrInvalidContainer
+	}
+	metadata.Duration, err = mp4.MovieDuration(mvhd.Payload, MaxDuration)
+	if err == mp4.ErrDurationTooLong {
+		return nil, ErrTooLong
+	}
+	if err != nil {
+		return nil, ErrInvalidContainer
+	}
+
+	moovBoxes, err := mp4.ReadBoxes(moov)
+	if err != nil {
+		return nil, err
+	}
+	for _, trak := range moovBoxes {
+		if trak.Kind != "trak" {
+			continue
+		}
+		if err := metadata.addTrack(trak.Payload); err != nil {
+			return nil, err
+		}
+	}
+
+	return metadata, nil
+}
+
+// addTrack reads the codec of a track, and the dimensions of the video track
+func (m *Metadata) addTrack(trak []byte) error {
+	hdlr, ok := mp4.FindBox(trak, "mdia", "hdlr")
+	if !ok || len(hdlr.Payload) < 12 {
+		return ErrInvalidContainer
+	}
+	stsd, ok := mp4.FindBox(trak, "mdia", "minf", "stbl", "stsd")
+	if !ok || len(stsd.Payload) < 16 {
+		return ErrInvalidContainer
+	}
+	// version and flags, entry count, then the first sample entry
+	codec := string(stsd.Payload[12:16])
+
+	switch string(hdlr.Payload[8:12]) {
+	case "vide":
+		if m.VideoCodec != "" {
+			return nil
+		}
+		m.VideoCodec = codec
+
+		tkhd, ok := mp4.FindBox(trak, "tkhd")
+		if !ok {
+			return ErrInvalidContainer
+		}
+		m.Width, m.Height = parseTrackDimensions(tkhd.Payload)
+
+	case "soun":
+		if m.AudioCodec == "" {
+			m.AudioCodec = codec
+		}
+	}
+	return nil
+}
+
+// parseTrackDimensions reads the width and height of a track header, stored as 16.16 fixed point numbers
+func parseTrackDimensions(tkhd []byte) (uint32, uint32) {
+	offset := 84
+	if len(tkhd) > 0 && tkhd[0] == 1 {
+		offset = 96
+	}
+	if len(tkhd) < offset {
+		return 0, 0
+	}
+	return binary.BigEndian.Uint32(tkhd[offset-8:offset-4]) >> 16, binary.BigEndian.Uint32(tkhd[offset-4:offset]) >> 16
+}
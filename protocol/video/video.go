@@ -0,0 +1,100 @@
+package video
+
+import (
+	"errors"
+	"time"
+
+	"github.com/status-im/status-go/protocol/mp4"
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+const (
+	// MaxSize is the maximum size of a video message, the message carrying it is split in segments
+	MaxSize = 10 * 1024 * 1024
+	// MaxDuration is the maximum duration of a video message
+	MaxDuration = 2 * time.Minute
+)
+
+const (
+	FormatMP4 = protobuf.VideoMessage_MP4
+	FormatMOV = protobuf.VideoMessage_MOV
+)
+
+var (
+	ErrEmpty             = errors.New("video is empty")
+	ErrTooLarge          = errors.New("video is too large")
+	ErrTooLong           = errors.New("video is too long")
+	ErrUnsupportedFormat = errors.New("video format not supported")
+	ErrInvalidContainer  = errors.New("invalid video container")
+	ErrUnsupportedCodec  = errors.New("video codec not supported")
+	ErrNoVideoTrack      = errors.New("no video track")
+)
+
+// supportedVideoCodecs are the codecs mobile players decode in hardware: H.264 and H.265
+var supportedVideoCodecs = map[string]bool{
+	"avc1": true,
+	"avc3": true,
+	"hvc1": true,
+	"hev1": true,
+}
+
+// supportedAudioCodecs are the codecs of the audio track, AAC
+var supportedAudioCodecs = map[string]bool{
+	"mp4a": true,
+}
+
+// Metadata describes a video as read from its container
+type Metadata struct {
+	Format     protobuf.VideoMessage_VideoFormat
+	Duration   time.Duration
+	Width      uint32
+	Height     uint32
+	VideoCodec string
+	AudioCodec string
+}
+
+// Parse reads the metadata of a video, only MP4 and QuickTime containers are supported.
+// Videos longer than MaxDuration are rejected while reading their movie header.
+func Parse(buf []byte) (*Metadata, error) {
+	if len(buf) == 0 {
+		return nil, ErrEmpty
+	}
+	metadata, err := parseMP4(buf)
+	if errors.Is(err, mp4.ErrInvalidBox) {
+		return nil, ErrInvalidContainer
+	}
+	return metadata, err
+}
+
+// Validate reads the metadata of a video and checks it's short enough and playable on mobile
+func Validate(buf []byte) (*Metadata, error) {
+	if len(buf) > MaxSize {
+		return nil, ErrTooLarge
+	}
+
+	metadata, err := Parse(buf)
+	if err != nil {
+		return nil, err
+	}
+
+	if metadata.VideoCodec == "" {
+		return nil, ErrNoVideoTrack
+	}
+	if !supportedVideoCodecs[metadata.VideoCodec] {
+		return nil, ErrUnsupportedCodec
+	}
+	if metadata.AudioCodec != "" && !supportedAudioCodecs[metadata.AudioCodec] {
+		return nil, ErrUnsupportedCodec
+	}
+	return metadata, nil
+}
+
+// MimeType returns the MIME type of a video format
+func MimeType(format protobuf.VideoMessage_VideoFormat) string {
+	switch format {
+	case FormatMOV:
+		return "video/quicktime"
+	default:
+		return "video/mp4"
+	}
+}
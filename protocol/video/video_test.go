@@ -0,0 +1,107 @@
+package video
+
+import (
+	"encoding/binary"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+
+	"github.com/status-im/status-go/protocol/protobuf"
+)
+
+func makeBox(kind string, payloads ...[]byte) []byte {
+	size := 8
+	for _, payload := range payloads {
+		size += len(payload)
+	}
+	buf := make([]byte, 8, size)
+	binary.BigEndian.PutUint32(buf[0:4], uint32(size))
+	copy(buf[4:8], kind)
+	for _, payload := range payloads {
+		buf = append(buf, payload...)
+	}
+	return buf
+}
+
+func makeTrack(handler string, codec string, width uint32, height uint32) []byte {
+	tkhd := make([]byte, 84)
+	binary.BigEndian.PutUint32(tkhd[76:80], width<<16)
+	binary.BigEndian.PutUint32(tkhd[80:84], height<<16)
+
+	hdlr := make([]byte, 24)
+	copy(hdlr[8:12], handler)
+
+	stsd := make([]byte, 8)
+	binary.BigEndian.PutUint32(stsd[4:8], 1)
+	stsd = append(stsd, makeBox(codec, make([]byte, 16))...)
+
+	return makeBox("trak",
+		makeBox("tkhd", tkhd),
+		makeBox("mdia",
+			makeBox("hdlr", hdlr),
+			makeBox("minf", makeBox("stbl", makeBox("stsd", stsd)))))
+}
+
+func makeVideo(brand string, duration time.Duration, tracks ...[]byte) []byte {
+	mvhd := make([]byte, 100)
+	binary.BigEndian.PutUint32(mvhd[12:16], 1000)
+	binary.BigEndian.PutUint32(mvhd[16:20], uint32(duration.Milliseconds()))
+
+	ftyp := make([]byte, 8)
+	copy(ftyp[0:4], brand)
+
+	return append(makeBox("ftyp", ftyp), makeBox("moov", append([][]byte{makeBox("mvhd", mvhd)}, tracks...)...)...)
+}
+
+func TestValidate(t *testing.T) {
+	buf := makeVideo("isom", 12500*time.Millisecond,
+		makeTrack("vide", "avc1", 1280, 720),
+		makeTrack("soun", "mp4a", 0, 0))
+
+	metadata, err := Validate(buf)
+	require.NoError(t, err)
+	require.Equal(t, FormatMP4, metadata.Format)
+	require.Equal(t, 12500*time.Millisecond, metadata.Duration)
+	require.Equal(t, uint32(1280), metadata.Width)
+	require.Equal(t, uint32(720), metadata.Height)
+	require.Equal(t, "avc1", metadata.VideoCodec)
+	require.Equal(t, "mp4a", metadata.AudioCodec)
+	require.Equal(t, "video/mp4", MimeType(metadata.Format))
+
+	metadata, err = Validate(makeVideo("qt  ", time.Second, makeTrack("vide", "hvc1", 640, 480)))
+	require.NoError(t, err)
+	require.Equal(t, protobuf.VideoMessage_MOV, metadata.Format)
+	require.Empty(t, metadata.AudioCodec)
+}
+
+func TestValidateErrors(t *testing.T) {
+	_, err := Validate(nil)
+	require.ErrorIs(t, err, ErrEmpty)
+
+	_, err = Validate([]byte("not a video at all"))
+	require.ErrorIs(t, err, ErrInvalidContainer)
+
+	_, err = Validate(makeBox("moov"))
+	require.ErrorIs(t, err, ErrUnsupportedFormat)
+
+	_, err = Validate(makeVideo("isom", time.Second, makeTrack("soun", "mp4a", 0, 0)))
+	require.ErrorIs(t, err, ErrNoVideoTrack)
+
+	_, err = Validate(makeVideo("isom", time.Second, makeTrack("vide", "vp09", 640, 480)))
+	require.ErrorIs(t, err, ErrUnsupportedCodec)
+
+	_, err = Validate(makeVideo("isom", MaxDuration+time.Second, makeTrack("vide", "avc1", 640, 480)))
+	require.ErrorIs(t, err, ErrTooLong)
+
+	// a version 1 movie header with a duration that doesn't fit a time.Duration
+	mvhd := make([]byte, 112)
+	mvhd[0] = 1
+	binary.BigEndian.PutUint32(mvhd[20:24], 1)
+	binary.BigEndian.PutUint64(mvhd[24:32], 1<<63)
+	_, err = Validate(append(makeBox("ftyp", []byte("isom\x00\x00\x00\x00")), makeBox("moov", makeBox("mvhd", mvhd), makeTrack("vide", "avc1", 640, 480))...))
+	require.ErrorIs(t, err, ErrTooLong)
+
+	_, err = Validate(make([]byte, MaxSize+1))
+	require.ErrorIs(t, err, ErrTooLarge)
+}
@@ -29,6 +29,7 @@ import (
 	"github.com/status-im/status-go/rpc/chain/tagger"
 	"github.com/status-im/status-go/services/rpcstats"
 	"github.com/status-im/status-go/services/wallet/connection"
+	"github.com/status-im/status-go/tracing"
 )
 
 type ClientInterface interface {
@@ -192,7 +193,10 @@ func (c *ClientWithFallback) IsConnected() bool {
 	return c.isConnected.Load()
 }
 
-func (c *ClientWithFallback) makeCall(ctx context.Context, ethClients []ethclient.RPSLimitedEthClientInterface, f func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error)) (interface{}, error) {
+func (c *ClientWithFallback) makeCall(ctx context.Context, method string, ethClients []ethclient.RPSLimitedEthClientInterface, f func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error)) (_ interface{}, err error) {
+	ctx, span := tracing.Start(ctx, "ethclient "+method, tracing.Uint64("chain_id", c.ChainID))
+	defer func() { span.EndWithError(err) }()
+
 	if c.commonLimiter != nil {
 		if allow, err := c.commonLimiter.Allow(c.tag); !allow {
 			return nil, fmt.Errorf("tag=%s, %w", c.tag, err)
@@ -208,7 +212,10 @@ func (c *ClientWithFallback) makeCall(ctx context.Context, ethClients []ethclien
 	cmd := circuitbreaker.NewCommand(ctx, nil)
 	for _, provider := range ethClients {
 		provider := provider
-		cmd.Add(circuitbreaker.NewFunctor(func() ([]interface{}, error) {
+		cmd.Add(circuitbreaker.NewFunctor(func() (_ []interface{}, err error) {
+			_, providerSpan := tracing.Start(ctx, "provider "+provider.GetName())
+			defer func() { providerSpan.EndWithError(err) }()
+
 			limiter := provider.GetLimiter()
 			if limiter != nil {
 				err := provider.GetLimiter().WaitForRequestsAvailability(1)
@@ -244,6 +251,7 @@ func (c *ClientWithFallback) makeCall(ctx context.Context, ethClients []ethclien
 	}
 
 	result := c.circuitbreaker.Execute(cmd)
+	countProviderCalls(c.ChainID, result.FunctorCallStatuses())
 	if c.providersHealthManager != nil {
 		rpcCallStatuses := convertFunctorCallStatuses(result.FunctorCallStatuses())
 		c.providersHealthManager.Update(ctx, rpcCallStatuses)
@@ -259,7 +267,7 @@ func (c *ClientWithFallback) BlockByHash(ctx context.Context, hash common.Hash)
 	rpcstats.CountCallWithTag("eth_BlockByHash", c.tag)
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_BlockByHash", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.BlockByHash(ctx, hash)
 		},
 	)
@@ -276,7 +284,7 @@ func (c *ClientWithFallback) BlockByHash(ctx context.Context, hash common.Hash)
 func (c *ClientWithFallback) BlockByNumber(ctx context.Context, number *big.Int) (*types.Block, error) {
 	rpcstats.CountCallWithTag("eth_BlockByNumber", c.tag)
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_BlockByNumber", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.BlockByNumber(ctx, number)
 		},
 	)
@@ -294,7 +302,7 @@ func (c *ClientWithFallback) BlockNumber(ctx context.Context) (uint64, error) {
 	rpcstats.CountCallWithTag("eth_BlockNumber", c.tag)
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_BlockNumber", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.BlockNumber(ctx)
 		},
 	)
@@ -311,7 +319,7 @@ func (c *ClientWithFallback) BlockNumber(ctx context.Context) (uint64, error) {
 func (c *ClientWithFallback) HeaderByHash(ctx context.Context, hash common.Hash) (*types.Header, error) {
 	rpcstats.CountCallWithTag("eth_HeaderByHash", c.tag)
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_HeaderByHash", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.HeaderByHash(ctx, hash)
 		},
 	)
@@ -328,7 +336,7 @@ func (c *ClientWithFallback) HeaderByHash(ctx context.Context, hash common.Hash)
 func (c *ClientWithFallback) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
 	rpcstats.CountCallWithTag("eth_HeaderByNumber", c.tag)
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_HeaderByNumber", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.HeaderByNumber(ctx, number)
 		},
 	)
@@ -346,7 +354,7 @@ func (c *ClientWithFallback) TransactionByHash(ctx context.Context, hash common.
 	rpcstats.CountCallWithTag("eth_TransactionByHash", c.tag)
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_TransactionByHash", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			tx, isPending, err := client.TransactionByHash(ctx, hash)
 			return []any{tx, isPending}, err
 		},
@@ -366,7 +374,7 @@ func (c *ClientWithFallback) TransactionSender(ctx context.Context, tx *types.Tr
 	rpcstats.CountCall("eth_TransactionSender")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_TransactionSender", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.TransactionSender(ctx, tx, block, index)
 		},
 	)
@@ -380,7 +388,7 @@ func (c *ClientWithFallback) TransactionReceipt(ctx context.Context, txHash comm
 	rpcstats.CountCall("eth_TransactionReceipt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_TransactionReceipt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.TransactionReceipt(ctx, txHash)
 		},
 	)
@@ -398,7 +406,7 @@ func (c *ClientWithFallback) SyncProgress(ctx context.Context) (*ethereum.SyncPr
 	rpcstats.CountCall("eth_SyncProgress")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_SyncProgress", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.SyncProgress(ctx)
 		},
 	)
@@ -420,7 +428,7 @@ func (c *ClientWithFallback) BalanceAt(ctx context.Context, account common.Addre
 	rpcstats.CountCallWithTag("eth_BalanceAt", c.tag)
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_BalanceAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.BalanceAt(ctx, account, blockNumber)
 		},
 	)
@@ -438,7 +446,7 @@ func (c *ClientWithFallback) StorageAt(ctx context.Context, account common.Addre
 	rpcstats.CountCall("eth_StorageAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_StorageAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.StorageAt(ctx, account, key, blockNumber)
 		},
 	)
@@ -456,7 +464,7 @@ func (c *ClientWithFallback) CodeAt(ctx context.Context, account common.Address,
 	rpcstats.CountCall("eth_CodeAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_CodeAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.CodeAt(ctx, account, blockNumber)
 		},
 	)
@@ -474,7 +482,7 @@ func (c *ClientWithFallback) NonceAt(ctx context.Context, account common.Address
 	rpcstats.CountCallWithTag("eth_NonceAt", c.tag)
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_NonceAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.NonceAt(ctx, account, blockNumber)
 		},
 	)
@@ -498,7 +506,7 @@ func (c *ClientWithFallback) FilterLogs(ctx context.Context, q ethereum.FilterQu
 	}
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_FilterLogs", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.FilterLogs(ctx, q)
 		},
 	)
@@ -517,7 +525,7 @@ func (c *ClientWithFallback) SubscribeFilterLogs(ctx context.Context, q ethereum
 	rpcstats.CountCall("eth_SubscribeFilterLogs")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_SubscribeFilterLogs", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.SubscribeFilterLogs(ctx, q, ch)
 		},
 	)
@@ -535,7 +543,7 @@ func (c *ClientWithFallback) PendingBalanceAt(ctx context.Context, account commo
 	rpcstats.CountCall("eth_PendingBalanceAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingBalanceAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingBalanceAt(ctx, account)
 		},
 	)
@@ -553,7 +561,7 @@ func (c *ClientWithFallback) PendingStorageAt(ctx context.Context, account commo
 	rpcstats.CountCall("eth_PendingStorageAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingStorageAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingStorageAt(ctx, account, key)
 		},
 	)
@@ -571,7 +579,7 @@ func (c *ClientWithFallback) PendingCodeAt(ctx context.Context, account common.A
 	rpcstats.CountCall("eth_PendingCodeAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingCodeAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingCodeAt(ctx, account)
 		},
 	)
@@ -589,7 +597,7 @@ func (c *ClientWithFallback) PendingNonceAt(ctx context.Context, account common.
 	rpcstats.CountCall("eth_PendingNonceAt")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingNonceAt", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingNonceAt(ctx, account)
 		},
 	)
@@ -607,7 +615,7 @@ func (c *ClientWithFallback) PendingTransactionCount(ctx context.Context) (uint,
 	rpcstats.CountCall("eth_PendingTransactionCount")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingTransactionCount", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingTransactionCount(ctx)
 		},
 	)
@@ -625,7 +633,7 @@ func (c *ClientWithFallback) CallContract(ctx context.Context, msg ethereum.Call
 	rpcstats.CountCall("eth_CallContract_" + msg.To.String())
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_CallContract", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.CallContract(ctx, msg, blockNumber)
 		},
 	)
@@ -643,7 +651,7 @@ func (c *ClientWithFallback) PendingCallContract(ctx context.Context, msg ethere
 	rpcstats.CountCall("eth_PendingCallContract")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_PendingCallContract", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.PendingCallContract(ctx, msg)
 		},
 	)
@@ -661,7 +669,7 @@ func (c *ClientWithFallback) SuggestGasPrice(ctx context.Context) (*big.Int, err
 	rpcstats.CountCall("eth_SuggestGasPrice")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_SuggestGasPrice", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.SuggestGasPrice(ctx)
 		},
 	)
@@ -679,7 +687,7 @@ func (c *ClientWithFallback) SuggestGasTipCap(ctx context.Context) (*big.Int, er
 	rpcstats.CountCall("eth_SuggestGasTipCap")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_SuggestGasTipCap", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.SuggestGasTipCap(ctx)
 		},
 	)
@@ -697,7 +705,7 @@ func (c *ClientWithFallback) FeeHistory(ctx context.Context, blockCount uint64,
 	rpcstats.CountCall("eth_FeeHistory")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_FeeHistory", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.FeeHistory(ctx, blockCount, lastBlock, rewardPercentiles)
 		},
 	)
@@ -715,7 +723,7 @@ func (c *ClientWithFallback) EstimateGas(ctx context.Context, msg ethereum.CallM
 	rpcstats.CountCall("eth_EstimateGas")
 
 	res, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_EstimateGas", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return client.EstimateGas(ctx, msg)
 		},
 	)
@@ -733,7 +741,7 @@ func (c *ClientWithFallback) SendTransaction(ctx context.Context, tx *types.Tran
 	rpcstats.CountCall("eth_SendTransaction")
 
 	_, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_SendTransaction", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return nil, client.SendTransaction(ctx, tx)
 		},
 	)
@@ -747,7 +755,7 @@ func (c *ClientWithFallback) CallContext(ctx context.Context, result interface{}
 	rpcstats.CountCall("eth_CallContext")
 
 	_, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, method, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return nil, client.CallContext(ctx, result, method, args...)
 		},
 	)
@@ -761,7 +769,7 @@ func (c *ClientWithFallback) BatchCallContext(ctx context.Context, b []rpc.Batch
 	rpcstats.CountCall("eth_BatchCallContext")
 
 	_, err := c.makeCall(
-		ctx, c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
+		ctx, "eth_BatchCallContext", c.ethClients, func(client ethclient.RPSLimitedEthClientInterface) (interface{}, error) {
 			return nil, client.BatchCallContext(ctx, b)
 		},
 	)
@@ -0,0 +1,35 @@
+package chain
+
+import (
+	"strconv"
+
+	prom "github.com/prometheus/client_golang/prometheus"
+
+	"github.com/status-im/status-go/circuitbreaker"
+)
+
+var (
+	providerCallsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "rpc_provider_calls_total",
+		Help: "Number of calls made to RPC providers, fallbacks included.",
+	}, []string{"chain_id", "provider"})
+	providerErrorsCounter = prom.NewCounterVec(prom.CounterOpts{
+		Name: "rpc_provider_errors_total",
+		Help: "Number of calls to RPC providers that failed.",
+	}, []string{"chain_id", "provider"})
+)
+
+func init() {
+	prom.MustRegister(providerCallsCounter)
+	prom.MustRegister(providerErrorsCounter)
+}
+
+func countProviderCalls(chainID uint64, statuses []circuitbreaker.FunctorCallStatus) {
+	chain := strconv.FormatUint(chainID, 10)
+	for _, status := range statuses {
+		providerCallsCounter.WithLabelValues(chain, status.Name).Inc()
+		if status.Err != nil {
+			providerErrorsCounter.WithLabelValues(chain, status.Name).Inc()
+		}
+	}
+}
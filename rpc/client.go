@@ -33,6 +33,7 @@ import (
 	"github.com/status-im/status-go/services/rpcstats"
 	"github.com/status-im/status-go/services/wallet/common"
 	"github.com/status-im/status-go/services/wallet/walletevent"
+	"github.com/status-im/status-go/tracing"
 )
 
 const (
@@ -225,6 +226,11 @@ func (c *Client) GetNetworkManager() *network.Manager {
 	return c.NetworkManager
 }
 
+// GetBlockchainHealthStatus returns the health of the RPC providers of each chain
+func (c *Client) GetBlockchainHealthStatus() healthmanager.BlockchainFullStatus {
+	return c.healthMgr.GetFullStatus()
+}
+
 func (c *Client) SetWalletNotifier(notifier func(chainID uint64, message string)) {
 	c.walletNotifier = notifier
 }
@@ -391,7 +397,10 @@ func (c *Client) Call(result interface{}, chainID uint64, method string, args ..
 //
 // It uses custom routing scheme for calls.
 // If there are any local handlers registered for this call, they will handle it.
-func (c *Client) CallContext(ctx context.Context, result interface{}, chainID uint64, method string, args ...interface{}) error {
+func (c *Client) CallContext(ctx context.Context, result interface{}, chainID uint64, method string, args ...interface{}) (err error) {
+	ctx, span := tracing.Start(ctx, "rpc "+method, tracing.Uint64("chain_id", chainID))
+	defer func() { span.EndWithError(err) }()
+
 	rpcstats.CountCall(method)
 	if c.router.routeBlocked(method) {
 		return ErrMethodNotFound
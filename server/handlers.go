@@ -7,6 +7,7 @@ import (
 	"image"
 	"image/color"
 	"math/big"
+	"mime"
 	"net/http"
 	"net/url"
 	"os"
@@ -16,6 +17,8 @@ import (
 
 	"github.com/status-im/status-go/protocol/protobuf"
 
+	"github.com/golang/protobuf/proto"
+	lru "github.com/hashicorp/golang-lru/v2"
 	"go.uber.org/zap"
 
 	eth_common "github.com/ethereum/go-ethereum/common"
@@ -23,8 +26,11 @@ import (
 	"github.com/status-im/status-go/images"
 	"github.com/status-im/status-go/ipfs"
 	"github.com/status-im/status-go/multiaccounts"
+	"github.com/status-im/status-go/protocol/audio"
+	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/identity/colorhash"
 	"github.com/status-im/status-go/protocol/identity/ring"
+	"github.com/status-im/status-go/protocol/video"
 	"github.com/status-im/status-go/services/wallet/bigint"
 )
 
@@ -32,12 +38,16 @@ const (
 	basePath                            = "/messages"
 	imagesPath                          = basePath + "/images"
 	audioPath                           = basePath + "/audio"
+	filesPath                           = basePath + "/files"
+	videosPath                          = basePath + "/videos"
+	videoThumbnailsPath                 = basePath + "/videos/thumbnail"
 	ipfsPath                            = "/ipfs"
 	discordAuthorsPath                  = "/discord/authors"
 	discordAttachmentsPath              = basePath + "/discord/attachments"
 	LinkPreviewThumbnailPath            = "/link-preview/thumbnail"
 	LinkPreviewFaviconPath              = "/link-preview/favicon"
 	StatusLinkPreviewThumbnailPath      = "/status-link-preview/thumbnail"
+	RichLinkPreviewImagePath            = "/rich-link-preview/image"
 	communityTokenImagesPath            = "/communityTokenImages"
 	communityDescriptionImagesPath      = "/communityDescriptionImages"
 	communityDescriptionTokenImagesPath = "/communityDescriptionTokenImages"
@@ -88,6 +98,7 @@ type ImageParams struct {
 	IndicatorBorder       float64
 	IndicatorCenterToEdge float64
 	IndicatorColor        color.Color
+	Animated              bool
 
 	AuthorID     string
 	URL          string
@@ -95,8 +106,7 @@ type ImageParams struct {
 	AttachmentID string
 	ImageID      string
 
-	Hash     string
-	Download bool
+	Hash string
 }
 
 func ParseImageParams(logger *zap.Logger, params url.Values) ImageParams {
@@ -264,6 +274,7 @@ func ParseImageParams(logger *zap.Logger, params url.Values) ImageParams {
 
 	parsed.Theme = getTheme(params, logger)
 	parsed.Ring = ringEnabled(params)
+	parsed.Animated = animatedEnabled(params)
 
 	messageIDs := params["message-id"]
 	if len(messageIDs) != 0 {
@@ -298,9 +309,6 @@ func ParseImageParams(logger *zap.Logger, params url.Values) ImageParams {
 		parsed.Hash = hash[0]
 	}
 
-	_, download := params["download"]
-	parsed.Download = download
-
 	return parsed
 }
 
@@ -321,6 +329,10 @@ func handleAccountImagesImpl(multiaccountsDB *multiaccounts.Database, logger *za
 		return
 	}
 
+	if parsed.Animated && writeAnimatedImage(logger, w, identityImage.AnimatedPayload) {
+		return
+	}
+
 	if parsed.Ring && parsed.RingWidth == 0 {
 		logger.Error("handleAccountImagesImpl: no ringWidth.")
 		return
@@ -643,13 +655,17 @@ func handleContactImages(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
 			return
 		}
 
-		var payload []byte
-		err := db.QueryRow(`SELECT payload FROM chat_identity_contacts WHERE contact_id = ? and image_type = ?`, parsed.PublicKey, parsed.ImageName).Scan(&payload)
+		var payload, animatedPayload []byte
+		err := db.QueryRow(`SELECT payload, animated_payload FROM chat_identity_contacts WHERE contact_id = ? and image_type = ?`, parsed.PublicKey, parsed.ImageName).Scan(&payload, &animatedPayload)
 		if err != nil {
 			logger.Error("failed to load image.", zap.String("contact id", parsed.PublicKey), zap.String("image type", parsed.ImageName), zap.Error(err))
 			return
 		}
 
+		if parsed.Animated && writeAnimatedImage(logger, w, animatedPayload) {
+			return
+		}
+
 		img, _, err := image.Decode(bytes.NewReader(payload))
 		if err != nil {
 			logger.Error("failed to decode config.", zap.String("contact id", parsed.PublicKey), zap.String("image type", parsed.ImageName), zap.Error(err))
@@ -718,6 +734,29 @@ func ringEnabled(params url.Values) bool {
 	return ok && len(addRings) == 1 && addRings[0] == "1"
 }
 
+func animatedEnabled(params url.Values) bool {
+	animated, ok := params["animated"]
+	return ok && len(animated) == 1 && animated[0] == "1"
+}
+
+// writeAnimatedImage serves the animated variant of an image as is, the round crop, ring and status
+// indicator of still images being left to the client. It returns false when the image isn't animated.
+func writeAnimatedImage(logger *zap.Logger, w http.ResponseWriter, animatedPayload []byte) bool {
+	// animated variants are GIFs, anything else received from others is served still
+	if len(animatedPayload) == 0 || images.GetType(animatedPayload) != images.GIF {
+		return false
+	}
+
+	w.Header().Set("Content-Type", "image/gif")
+	w.Header().Set("Cache-Control", "no-store")
+
+	_, err := w.Write(animatedPayload)
+	if err != nil {
+		logger.Error("failed to write animated image", zap.Error(err))
+	}
+	return true
+}
+
 func getTheme(params url.Values, logger *zap.Logger) ring.Theme {
 	theme := ring.LightTheme // default
 	themes, ok := params["theme"]
@@ -867,27 +906,188 @@ func handleAudio(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
 			return
 		}
 
-		var audio []byte
-		err := db.QueryRow(`SELECT audio_payload FROM user_messages WHERE id = ?`, parsed.MessageID).Scan(&audio)
+		// AMR audios are served transcoded to AAC when they were
+		var audioPayload []byte
+		audioType := protobuf.AudioMessage_AAC
+		err := db.QueryRow(`SELECT payload FROM chat_audio_transcodes WHERE message_id = ?`, parsed.MessageID).Scan(&audioPayload)
+		if err == sql.ErrNoRows {
+			err = db.QueryRow(`SELECT audio_payload, audio_type FROM user_messages WHERE id = ?`, parsed.MessageID).Scan(&audioPayload, &audioType)
+		}
 		if err != nil {
-			logger.Error("failed to find image", zap.Error(err))
+			logger.Error("failed to find audio", zap.Error(err))
 			return
 		}
-		if len(audio) == 0 {
+		if len(audioPayload) == 0 {
 			logger.Error("empty audio")
 			return
 		}
 
-		w.Header().Set("Content-Type", "audio/aac")
+		w.Header().Set("Content-Type", audio.MimeType(audioType))
 		w.Header().Set("Cache-Control", "no-store")
 
-		_, err = w.Write(audio)
+		_, err = w.Write(audioPayload)
 		if err != nil {
 			logger.Error("failed to write audio", zap.Error(err))
 		}
 	}
 }
 
+func handleFile(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
+	if db == nil {
+		return handleRequestDBMissing(logger)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		parsed := ParseImageParams(logger, params)
+
+		if parsed.MessageID == "" {
+			logger.Error("no messageID")
+			return
+		}
+
+		var serializedManifest, payload []byte
+		err := db.QueryRow(`SELECT m.file_manifest, a.payload FROM chat_file_attachments a JOIN user_messages m ON m.id = a.message_id WHERE a.message_id = ? AND NOT COALESCE(m.deleted, 0) AND NOT COALESCE(m.deleted_for_me, 0)`, parsed.MessageID).Scan(&serializedManifest, &payload)
+		if err != nil {
+			logger.Error("failed to find file", zap.Error(err))
+			http.Error(w, "file not found", http.StatusNotFound)
+			return
+		}
+
+		manifest := &protobuf.FileMessage{}
+		if err := proto.Unmarshal(serializedManifest, manifest); err != nil {
+			logger.Error("failed to unmarshal file manifest", zap.Error(err))
+			http.Error(w, "invalid file", http.StatusInternalServerError)
+			return
+		}
+
+		content, err := common.DecryptFile(manifest, payload)
+		if err != nil {
+			logger.Error("failed to decrypt file", zap.Error(err))
+			http.Error(w, "invalid file", http.StatusInternalServerError)
+			return
+		}
+
+		// the MIME type of the manifest is set by the sender, files are always served as downloads
+		w.Header().Set("Content-Type", "application/octet-stream")
+		w.Header().Set("X-Content-Type-Options", "nosniff")
+		w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": manifest.Name}))
+		w.Header().Set("Content-Length", strconv.Itoa(len(content)))
+		w.Header().Set("Cache-Control", "no-store")
+
+		_, err = w.Write(content)
+		if err != nil {
+			logger.Error("failed to write file", zap.Error(err))
+		}
+	}
+}
+
+// videoCacheSize is the number of videos whose payload is kept in memory, players
+// send many range requests for the video being played and the ones around it
+const videoCacheSize = 3
+
+// handleVideo serves videos with range requests, so that players stream them.
+// Payloads are cached per message, so that every range request doesn't load the whole video.
+func handleVideo(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
+	if db == nil {
+		return handleRequestDBMissing(logger)
+	}
+
+	// creating the cache only fails for sizes that aren't positive
+	payloads, _ := lru.New[string, []byte](videoCacheSize)
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		parsed := ParseImageParams(logger, params)
+
+		if parsed.MessageID == "" {
+			logger.Error("no messageID")
+			return
+		}
+
+		// the message is checked on every request, deleted videos aren't served from the cache
+		var serializedManifest []byte
+		err := db.QueryRow(`SELECT m.video_manifest FROM chat_video_payloads v JOIN user_messages m ON m.id = v.message_id WHERE v.message_id = ? AND NOT COALESCE(m.deleted, 0) AND NOT COALESCE(m.deleted_for_me, 0)`, parsed.MessageID).Scan(&serializedManifest)
+		if err != nil {
+			payloads.Remove(parsed.MessageID)
+			logger.Error("failed to find video", zap.Error(err))
+			http.Error(w, "video not found", http.StatusNotFound)
+			return
+		}
+
+		payload, ok := payloads.Get(parsed.MessageID)
+		if !ok {
+			err = db.QueryRow(`SELECT payload FROM chat_video_payloads WHERE message_id = ?`, parsed.MessageID).Scan(&payload)
+			if err != nil {
+				logger.Error("failed to load video", zap.Error(err))
+				http.Error(w, "video not found", http.StatusNotFound)
+				return
+			}
+			payloads.Add(parsed.MessageID, payload)
+		}
+
+		manifest := &protobuf.VideoMessage{}
+		if err := proto.Unmarshal(serializedManifest, manifest); err != nil {
+			logger.Error("failed to unmarshal video manifest", zap.Error(err))
+			http.Error(w, "invalid video", http.StatusInternalServerError)
+			return
+		}
+
+		w.Header().Set("Content-Type", video.MimeType(manifest.Format))
+		w.Header().Set("Cache-Control", "no-store")
+
+		http.ServeContent(w, r, "", time.Time{}, bytes.NewReader(payload))
+	}
+}
+
+func handleVideoThumbnail(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
+	if db == nil {
+		return handleRequestDBMissing(logger)
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		params := r.URL.Query()
+		parsed := ParseImageParams(logger, params)
+
+		if parsed.MessageID == "" {
+			logger.Error("no messageID")
+			return
+		}
+
+		var serializedManifest []byte
+		err := db.QueryRow(`SELECT video_manifest FROM user_messages WHERE id = ? AND NOT COALESCE(deleted, 0) AND NOT COALESCE(deleted_for_me, 0)`, parsed.MessageID).Scan(&serializedManifest)
+		if err != nil {
+			logger.Error("failed to find video", zap.Error(err))
+			http.Error(w, "video not found", http.StatusNotFound)
+			return
+		}
+
+		manifest := &protobuf.VideoMessage{}
+		if err := proto.Unmarshal(serializedManifest, manifest); err != nil {
+			logger.Error("failed to unmarshal video manifest", zap.Error(err))
+			http.Error(w, "invalid video", http.StatusInternalServerError)
+			return
+		}
+		if len(manifest.Thumbnail) == 0 {
+			http.Error(w, "video thumbnail not found", http.StatusNotFound)
+			return
+		}
+
+		mime, err := images.GetProtobufImageMime(manifest.Thumbnail)
+		if err != nil {
+			logger.Error("failed to get mime", zap.Error(err))
+		}
+
+		w.Header().Set("Content-Type", mime)
+		w.Header().Set("Cache-Control", "no-store")
+
+		_, err = w.Write(manifest.Thumbnail)
+		if err != nil {
+			logger.Error("failed to write video thumbnail", zap.Error(err))
+		}
+	}
+}
+
 func handleIPFS(downloader *ipfs.Downloader, logger *zap.Logger) http.HandlerFunc {
 	if downloader == nil {
 		return handleRequestDownloaderMissing(logger)
@@ -902,12 +1102,13 @@ func handleIPFS(downloader *ipfs.Downloader, logger *zap.Logger) http.HandlerFun
 			return
 		}
 
-		content, err := downloader.Get(parsed.Hash, parsed.Download)
+		content, err := downloader.Get(parsed.Hash)
 		if err != nil {
 			logger.Error("could not download hash", zap.Error(err))
 			return
 		}
 
+		w.Header().Set("Content-Type", http.DetectContentType(content))
 		w.Header().Set("Cache-Control", "max-age:290304000, public")
 		w.Header().Set("Expires", time.Now().AddDate(60, 0, 0).Format(http.TimeFormat))
 
@@ -1021,10 +1222,11 @@ func handleCommunityDescriptionImagesPath(db *sql.DB, getCommunityImages func(co
 			return
 		}
 
-		var imagePayload []byte
+		var imagePayload, animatedPayload []byte
 		for t, i := range communityImages {
 			if t == name {
 				imagePayload = i.Payload
+				animatedPayload = i.AnimatedPayload
 			}
 		}
 		if imagePayload == nil {
@@ -1032,6 +1234,10 @@ func handleCommunityDescriptionImagesPath(db *sql.DB, getCommunityImages func(co
 			return
 		}
 
+		if animatedEnabled(params) && writeAnimatedImage(logger, w, animatedPayload) {
+			return
+		}
+
 		mime, err := images.GetProtobufImageMime(imagePayload)
 		if err != nil {
 			logger.Error("failed to get community image mime", zap.String("community id", communityID), zap.Error(err))
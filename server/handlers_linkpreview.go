@@ -257,3 +257,46 @@ func handleStatusLinkPreviewThumbnail(db *sql.DB, logger *zap.Logger) http.Handl
 		}
 	}
 }
+
+func getRichLinkPreviewImage(db *sql.DB, messageID string, URL string) ([]byte, int, error) {
+	var messageLinks []byte
+	err := db.QueryRow(`SELECT unfurled_rich_links FROM user_messages WHERE id = ?`, messageID).Scan(&messageLinks)
+	if err != nil {
+		return nil, http.StatusBadRequest, fmt.Errorf("could not find message with message-id '%s': %w", messageID, err)
+	}
+
+	var links protobuf.UnfurledRichLinks
+	err = proto.Unmarshal(messageLinks, &links)
+	if err != nil {
+		return nil, http.StatusInternalServerError, fmt.Errorf("failed to unmarshal protobuf.UnfurledRichLinks: %w", err)
+	}
+
+	for _, p := range links.UnfurledRichLinks {
+		if p.Url != URL {
+			continue
+		}
+		collectible := p.GetCollectible()
+		if collectible == nil || len(collectible.GetImage().GetPayload()) == 0 {
+			return nil, http.StatusNotFound, fmt.Errorf("no image for given url")
+		}
+		return collectible.Image.Payload, http.StatusOK, nil
+	}
+
+	return nil, http.StatusBadRequest, fmt.Errorf("no link preview found for given url")
+}
+
+func handleRichLinkPreviewImage(db *sql.DB, logger *zap.Logger) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		parsed := validateAndReturnImageParams(r, w, logger)
+		if parsed.URL == "" {
+			return
+		}
+
+		image, httpStatusCode, err := getRichLinkPreviewImage(db, parsed.MessageID, parsed.URL)
+		if err != nil {
+			http.Error(w, err.Error(), httpStatusCode)
+			return
+		}
+		getMimeTypeAndWriteImage(w, logger, image)
+	}
+}
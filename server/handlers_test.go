@@ -75,14 +75,16 @@ func (s *HandlersSuite) saveUserMessage(msg *common.Message) {
 			response_to,
 			clock_value,
 			unfurled_links,
-		    unfurled_status_links
-		) VALUES (?,?,?,?,?,?,?,?,?,?,?,?)
+		    unfurled_status_links,
+			unfurled_rich_links
+		) VALUES (?,?,?,?,?,?,?,?,?,?,?,?,?)
 	`)
 
 	s.Require().NoError(err)
 
 	links := []byte{}
 	statusLinks := []byte{}
+	richLinks := []byte{}
 
 	if msg.UnfurledLinks != nil {
 		links, err = json.Marshal(msg.UnfurledLinks)
@@ -94,6 +96,11 @@ func (s *HandlersSuite) saveUserMessage(msg *common.Message) {
 		s.Require().NoError(err)
 	}
 
+	if msg.UnfurledRichLinks != nil {
+		richLinks, err = proto.Marshal(msg.UnfurledRichLinks)
+		s.Require().NoError(err)
+	}
+
 	_, err = stmt.Exec(
 		msg.ID,
 		whisperTimestamp,
@@ -107,6 +114,7 @@ func (s *HandlersSuite) saveUserMessage(msg *common.Message) {
 		clockValue,
 		links,
 		statusLinks,
+		richLinks,
 	)
 	s.Require().NoError(err)
 }
@@ -556,6 +564,161 @@ func (s *HandlersSuite) TestHandleStatusLinkPreviewThumbnail() {
 	}
 }
 
+func (s *HandlersSuite) TestHandleRichLinkPreviewImage() {
+	imagePayload := []byte{0xff, 0xd8, 0xff, 0xdb, 0x0, 0x84, 0x0, 0x50, 0x37, 0x3c, 0x46, 0x3c, 0x32, 0x50}
+	collectibleURL := "https://opensea.io/assets/ethereum/0x0000000000000000000000000000000000000001/1"
+	gitHubURL := "https://github.com/status-im/status-go/issues/1"
+
+	msg := common.Message{
+		ID: "1",
+		ChatMessage: &protobuf.ChatMessage{
+			UnfurledRichLinks: &protobuf.UnfurledRichLinks{
+				UnfurledRichLinks: []*protobuf.UnfurledRichLink{
+					{
+						Url: collectibleURL,
+						Payload: &protobuf.UnfurledRichLink_Collectible{
+							Collectible: &protobuf.UnfurledCollectibleLink{
+								ChainId:         1,
+								ContractAddress: "0x0000000000000000000000000000000000000001",
+								TokenId:         "1",
+								Image:           &protobuf.UnfurledLinkThumbnail{Width: 10, Height: 20, Payload: imagePayload},
+							},
+						},
+					},
+					{
+						Url: gitHubURL,
+						Payload: &protobuf.UnfurledRichLink_Github{
+							Github: &protobuf.UnfurledGitHubLink{Repository: "status-im/status-go", Number: 1},
+						},
+					},
+				},
+			},
+		},
+	}
+	s.saveUserMessage(&msg)
+
+	handler := handleRichLinkPreviewImage(s.db, s.logger)
+	makeURL := func(previewURL string) string {
+		return "https://localhost:8080/rich-link-preview/image?" + url.Values{"message-id": {msg.ID}, "url": {previewURL}}.Encode()
+	}
+
+	rr := s.httpGetReqRecorder(handler, makeURL(collectibleURL))
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.verifyHTTPResponseThumbnail(rr, imagePayload)
+
+	rr = s.httpGetReqRecorder(handler, makeURL(gitHubURL))
+	s.Require().Equal(http.StatusNotFound, rr.Code)
+
+	rr = s.httpGetReqRecorder(handler, makeURL("https://status.app"))
+	s.Require().Equal(http.StatusBadRequest, rr.Code)
+}
+
+func (s *HandlersSuite) TestHandleFile() {
+	content := []byte("some-spec")
+	file, err := common.EncryptFile("spec.pdf", content)
+	s.Require().NoError(err)
+
+	s.saveUserMessage(&common.Message{ID: "1"})
+
+	manifest := proto.Clone(file).(*protobuf.FileMessage)
+	manifest.Payload = nil
+	serializedManifest, err := proto.Marshal(manifest)
+	s.Require().NoError(err)
+
+	_, err = s.db.Exec(`UPDATE user_messages SET file_manifest = ? WHERE id = ?`, serializedManifest, "1")
+	s.Require().NoError(err)
+	_, err = s.db.Exec(`INSERT INTO chat_file_attachments (message_id, payload) VALUES (?, ?)`, "1", file.Payload)
+	s.Require().NoError(err)
+
+	handler := handleFile(s.db, s.logger)
+
+	rr := s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().Equal(content, rr.Body.Bytes())
+	s.Require().Equal("application/octet-stream", rr.Header().Get("Content-Type"))
+	s.Require().Equal("nosniff", rr.Header().Get("X-Content-Type-Options"))
+	s.Require().Equal("attachment; filename=spec.pdf", rr.Header().Get("Content-Disposition"))
+
+	rr = s.httpGetReqRecorder(handler, "/dummy?messageId=2")
+	s.Require().Equal(http.StatusNotFound, rr.Code)
+
+	// Deleted messages don't serve their file anymore
+	_, err = s.db.Exec(`UPDATE user_messages SET deleted = 1 WHERE id = ?`, "1")
+	s.Require().NoError(err)
+
+	rr = s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *HandlersSuite) TestHandleVideo() {
+	payload := []byte("0123456789")
+
+	s.saveUserMessage(&common.Message{ID: "1"})
+
+	serializedManifest, err := proto.Marshal(&protobuf.VideoMessage{Format: protobuf.VideoMessage_MOV})
+	s.Require().NoError(err)
+
+	_, err = s.db.Exec(`UPDATE user_messages SET video_manifest = ? WHERE id = ?`, serializedManifest, "1")
+	s.Require().NoError(err)
+	_, err = s.db.Exec(`INSERT INTO chat_video_payloads (message_id, payload, stored_at) VALUES (?, ?, ?)`, "1", payload, 1)
+	s.Require().NoError(err)
+
+	handler := handleVideo(s.db, s.logger)
+
+	rr := s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().Equal(payload, rr.Body.Bytes())
+	s.Require().Equal("video/quicktime", rr.Header().Get("Content-Type"))
+	s.Require().Equal("bytes", rr.Header().Get("Accept-Ranges"))
+
+	// Players stream the video with range requests
+	req, err := http.NewRequest("GET", "/dummy?messageId=1", nil)
+	s.Require().NoError(err)
+	req.Header.Set("Range", "bytes=2-5")
+
+	rr = httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+	s.Require().Equal(http.StatusPartialContent, rr.Code)
+	s.Require().Equal([]byte("2345"), rr.Body.Bytes())
+	s.Require().Equal("bytes 2-5/10", rr.Header().Get("Content-Range"))
+
+	// No thumbnail was sent with the video
+	rr = s.httpGetReqRecorder(handleVideoThumbnail(s.db, s.logger), "/dummy?messageId=1")
+	s.Require().Equal(http.StatusNotFound, rr.Code)
+
+	// Cached videos aren't served anymore once their message is deleted
+	_, err = s.db.Exec(`UPDATE user_messages SET deleted = 1 WHERE id = ?`, "1")
+	s.Require().NoError(err)
+
+	rr = s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusNotFound, rr.Code)
+}
+
+func (s *HandlersSuite) TestHandleAudio() {
+	payload := []byte("#!AMR\n0123456789")
+	transcode := []byte{0xff, 0xf1, 0x50, 0x80}
+
+	s.saveUserMessage(&common.Message{ID: "1"})
+	_, err := s.db.Exec(`UPDATE user_messages SET audio_payload = ?, audio_type = ? WHERE id = ?`, payload, protobuf.AudioMessage_AMR, "1")
+	s.Require().NoError(err)
+
+	handler := handleAudio(s.db, s.logger)
+
+	rr := s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().Equal(payload, rr.Body.Bytes())
+	s.Require().Equal("audio/amr", rr.Header().Get("Content-Type"))
+
+	// The transcode is served once there's one
+	_, err = s.db.Exec(`INSERT INTO chat_audio_transcodes (message_id, payload, stored_at) VALUES (?, ?, ?)`, "1", transcode, 1)
+	s.Require().NoError(err)
+
+	rr = s.httpGetReqRecorder(handler, "/dummy?messageId=1")
+	s.Require().Equal(http.StatusOK, rr.Code)
+	s.Require().Equal(transcode, rr.Body.Bytes())
+	s.Require().Equal("audio/aac", rr.Header().Get("Content-Type"))
+}
+
 func (s *HandlersSuite) validateResponse(w *httptest.ResponseRecorder) {
 	s.Require().Equal(http.StatusOK, w.Code)
 	s.Require().Equal("image/png", w.Header().Get("Content-Type"))
@@ -661,3 +824,33 @@ func (s *HandlersSuite) TestHandleAccountImagesImpl() {
 	handleAccountImagesImpl(db, s.logger, w, p)
 	s.Require().Equal(http.StatusOK, w.Code)
 }
+
+func (s *HandlersSuite) TestHandleAccountImagesImplAnimated() {
+	dbFile := filepath.Join(s.T().TempDir(), "accounts-tests-")
+	db, err := multiaccounts.InitializeDB(dbFile)
+	s.Require().NoError(err)
+	defer db.Close()
+
+	keyUID := "0x1"
+	animatedPayload := []byte("GIF89a animated payload")
+	identityImages := images.SampleIdentityImageForQRCode()
+	identityImages[0].AnimatedPayload = animatedPayload
+	s.Require().NoError(db.SaveAccount(multiaccounts.Account{
+		Name:          "Lopsided Goodnatured Bedbug",
+		KeyUID:        keyUID,
+		KDFIterations: dbsetup.ReducedKDFIterationsNumber,
+		Images:        identityImages,
+	}))
+
+	// the animated variant is served as is
+	w := httptest.NewRecorder()
+	handleAccountImagesImpl(db, s.logger, w, ImageParams{KeyUID: keyUID, ImageName: images.LargeDimName, Animated: true})
+	s.Require().Equal(http.StatusOK, w.Code)
+	s.Require().Equal("image/gif", w.Header().Get("Content-Type"))
+	s.Require().Equal(animatedPayload, w.Body.Bytes())
+
+	// the still variant is served otherwise
+	w = httptest.NewRecorder()
+	handleAccountImagesImpl(db, s.logger, w, ImageParams{KeyUID: keyUID, ImageName: images.LargeDimName})
+	s.validateResponse(w)
+}
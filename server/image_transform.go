@@ -0,0 +1,323 @@
+package server
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"image"
+	"image/jpeg"
+	"image/png"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/images"
+)
+
+const (
+	// maxImageTransformDimension bounds the box images are resized to, images are never upscaled anyway
+	maxImageTransformDimension = 4096
+	// maxImageTransformPixels bounds the images transformed, checked before they're decoded
+	maxImageTransformPixels = maxImageTransformDimension * maxImageTransformDimension
+)
+
+var (
+	ErrInvalidImageTransform  = errors.New("invalid image transform")
+	ErrImageTransformTooLarge = errors.New("image is too large to transform")
+)
+
+// ImageTransform is a transform of an image served by the media server, requested with the query
+// parameters width, height, fit, circle, format and quality on any image URL
+type ImageTransform struct {
+	Width  int
+	Height int
+	Fit    images.FitMode
+	// Circle crops the image to a circle as the account and contact images are
+	Circle bool
+	// Format is the format of the transformed image, it's picked from the source when unset
+	Format images.ImageType
+	// Quality is the JPEG quality, 0 uses images.MaxJpegQuality. PNG and WebP variants are lossless,
+	// so it's rejected along with those formats and ignored when they're picked from the source.
+	Quality int
+}
+
+// ParseImageTransform returns the transform requested by params, or nil if none is
+func ParseImageTransform(params url.Values) (*ImageTransform, error) {
+	transform := &ImageTransform{Fit: images.FitCover}
+	requested := false
+
+	parseDimension := func(name string) (int, error) {
+		value := params.Get(name)
+		if value == "" {
+			return 0, nil
+		}
+		requested = true
+		dimension, err := strconv.Atoi(value)
+		if err != nil || dimension < 0 || dimension > maxImageTransformDimension {
+			return 0, fmt.Errorf("%w: %s %q", ErrInvalidImageTransform, name, value)
+		}
+		return dimension, nil
+	}
+
+	var err error
+	transform.Width, err = parseDimension("width")
+	if err != nil {
+		return nil, err
+	}
+	transform.Height, err = parseDimension("height")
+	if err != nil {
+		return nil, err
+	}
+
+	if fit := params.Get("fit"); fit != "" {
+		requested = true
+		transform.Fit, err = images.ParseFitMode(fit)
+		if err != nil {
+			return nil, fmt.Errorf("%w: fit %q", ErrInvalidImageTransform, fit)
+		}
+	}
+
+	if circle := params.Get("circle"); circle != "" {
+		requested = true
+		transform.Circle = circle == "1"
+	}
+
+	if format := params.Get("format"); format != "" {
+		requested = true
+		switch format {
+		case "webp":
+			transform.Format = images.WEBP
+		case "jpeg", "jpg":
+			transform.Format = images.JPEG
+		case "png":
+			transform.Format = images.PNG
+		default:
+			return nil, fmt.Errorf("%w: format %q", ErrInvalidImageTransform, format)
+		}
+	}
+
+	if quality := params.Get("quality"); quality != "" {
+		requested = true
+		transform.Quality, err = strconv.Atoi(quality)
+		if err != nil || transform.Quality < 1 || transform.Quality > 100 {
+			return nil, fmt.Errorf("%w: quality %q", ErrInvalidImageTransform, quality)
+		}
+	}
+
+	if transform.Quality != 0 && (transform.Format == images.PNG || transform.Format == images.WEBP) {
+		return nil, fmt.Errorf("%w: quality of a lossless format", ErrInvalidImageTransform)
+	}
+
+	if !requested {
+		return nil, nil
+	}
+	return transform, nil
+}
+
+// key identifies the variant of source produced by the transform
+func (t *ImageTransform) key(source []byte) string {
+	hash := sha256.New()
+	hash.Write(source)
+	fmt.Fprintf(hash, "|%d|%d|%s|%t|%d|%d", t.Width, t.Height, t.Fit, t.Circle, t.Format, t.Quality)
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// outputFormat returns the format of the variant. By default transparency is kept as PNG,
+// WebP images stay WebP, and anything else is a JPEG.
+func (t *ImageTransform) outputFormat(source []byte) images.ImageType {
+	if t.Format != 0 {
+		return t.Format
+	}
+	switch images.GetType(source) {
+	case images.PNG:
+		return images.PNG
+	case images.WEBP:
+		return images.WEBP
+	}
+	if t.Circle {
+		return images.PNG
+	}
+	return images.JPEG
+}
+
+// Apply transforms the image in payload, returning the variant and its mime type
+func (t *ImageTransform) Apply(payload []byte) ([]byte, string, error) {
+	config, _, err := image.DecodeConfig(bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+	// in int64, as it'd overflow an int on 32 bits platforms
+	if int64(config.Width)*int64(config.Height) > maxImageTransformPixels {
+		return nil, "", ErrImageTransformTooLarge
+	}
+
+	img, err := images.DecodeImageData(payload, bytes.NewReader(payload))
+	if err != nil {
+		return nil, "", err
+	}
+
+	img, err = images.ResizeToFit(img, t.Width, t.Height, t.Fit)
+	if err != nil {
+		return nil, "", err
+	}
+	if t.Circle {
+		img = images.CropCircle(img)
+	}
+
+	bb := bytes.NewBuffer([]byte{})
+	switch t.outputFormat(payload) {
+	case images.WEBP:
+		err = images.EncodeWebPLossless(bb, img)
+	case images.PNG:
+		err = png.Encode(bb, img)
+	default:
+		quality := t.Quality
+		if quality == 0 {
+			quality = images.MaxJpegQuality
+		}
+		err = jpeg.Encode(bb, img, &jpeg.Options{Quality: quality})
+	}
+	if err != nil {
+		return nil, "", err
+	}
+
+	mime, err := images.GetProtobufImageMime(bb.Bytes())
+	if err != nil {
+		return nil, "", err
+	}
+	return bb.Bytes(), mime, nil
+}
+
+// applyCached applies the transform, reusing the variant stored in cache if there's one. cache can be nil.
+func (t *ImageTransform) applyCached(cache *imageTransformCache, payload []byte) ([]byte, string, error) {
+	var key string
+	if cache != nil {
+		key = t.key(payload)
+		if variant := cache.Get(key); variant != nil {
+			mime, err := images.GetProtobufImageMime(variant)
+			if err != nil {
+				return nil, "", err
+			}
+			return variant, mime, nil
+		}
+	}
+
+	variant, mime, err := t.Apply(payload)
+	if err != nil {
+		return nil, "", err
+	}
+
+	if cache != nil {
+		cache.Put(key, variant)
+	}
+	return variant, mime, nil
+}
+
+// bufferedResponseWriter keeps a still image response in memory, so that it can be transformed before
+// being written. Other responses, such as audios and videos, are written through to rw as they come.
+type bufferedResponseWriter struct {
+	rw     http.ResponseWriter
+	header http.Header
+	status int
+	body   bytes.Buffer
+	// started is set once the status and the headers are known, passthrough then if they aren't an image's
+	started     bool
+	passthrough bool
+}
+
+func newBufferedResponseWriter(rw http.ResponseWriter) *bufferedResponseWriter {
+	return &bufferedResponseWriter{rw: rw, header: make(http.Header), status: http.StatusOK}
+}
+
+func (w *bufferedResponseWriter) Header() http.Header {
+	return w.header
+}
+
+func (w *bufferedResponseWriter) start() {
+	if w.started {
+		return
+	}
+	w.started = true
+	if w.status == http.StatusOK && strings.HasPrefix(w.header.Get("Content-Type"), "image/") {
+		return
+	}
+	w.passthrough = true
+	for name, values := range w.header {
+		w.rw.Header()[name] = values
+	}
+	w.rw.WriteHeader(w.status)
+}
+
+func (w *bufferedResponseWriter) Write(b []byte) (int, error) {
+	w.start()
+	if w.passthrough {
+		return w.rw.Write(b)
+	}
+	return w.body.Write(b)
+}
+
+func (w *bufferedResponseWriter) WriteHeader(status int) {
+	if w.started {
+		return
+	}
+	w.status = status
+	w.start()
+}
+
+func (w *bufferedResponseWriter) writeTo(body []byte) error {
+	for name, values := range w.header {
+		w.rw.Header()[name] = values
+	}
+	w.rw.WriteHeader(w.status)
+	_, err := w.rw.Write(body)
+	return err
+}
+
+// withImageTransform applies the transform requested in the query to the images served by handler.
+// Responses other than still images are served as is, as are images the transform fails on.
+func withImageTransform(cache *imageTransformCache, logger *zap.Logger, handler http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		transform, err := ParseImageTransform(r.URL.Query())
+		if err != nil {
+			logger.Error("withImageTransform: invalid transform", zap.Error(err))
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if transform == nil {
+			handler(w, r)
+			return
+		}
+
+		buffered := newBufferedResponseWriter(w)
+		handler(buffered, r)
+		if buffered.passthrough {
+			return
+		}
+
+		payload := buffered.body.Bytes()
+		if !buffered.started || images.IsAnimated(payload) {
+			if err := buffered.writeTo(payload); err != nil {
+				logger.Error("withImageTransform: failed to write response", zap.Error(err))
+			}
+			return
+		}
+
+		variant, mime, err := transform.applyCached(cache, payload)
+		if err != nil {
+			logger.Error("withImageTransform: failed to transform image", zap.String("path", r.URL.Path), zap.Error(err))
+			variant = payload
+		} else {
+			buffered.header.Set("Content-Type", mime)
+		}
+		buffered.header.Del("Content-Length")
+
+		if err := buffered.writeTo(variant); err != nil {
+			logger.Error("withImageTransform: failed to write image", zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,74 @@
+package server
+
+import (
+	"container/list"
+	"sync"
+)
+
+const (
+	// defaultImageTransformCacheMaxSize is the size over which variants are evicted, least recently used first
+	defaultImageTransformCacheMaxSize = 16 * 1024 * 1024
+)
+
+type imageTransformCacheEntry struct {
+	key     string
+	content []byte
+}
+
+// imageTransformCache keeps the transformed variants of images in memory, by key. Variants of
+// chat media are never written to disk, where they'd outlive the account and the messages.
+// Keys are derived from the source image and the transform, so entries are never replaced with
+// different content. Variants are evicted once the cache grows over its maximum size.
+type imageTransformCache struct {
+	maxSize int64
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	// lru has the most recently used entries at the front
+	lru  *list.List
+	size int64
+}
+
+func newImageTransformCache(maxSize int64) *imageTransformCache {
+	return &imageTransformCache{
+		maxSize: maxSize,
+		entries: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+}
+
+// Get returns the variant stored under key, or nil if there's none
+func (c *imageTransformCache) Get(key string) []byte {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	element, ok := c.entries[key]
+	if !ok {
+		return nil
+	}
+	c.lru.MoveToFront(element)
+	return element.Value.(*imageTransformCacheEntry).content
+}
+
+// Put stores a variant under key, then evicts variants if the cache is over its maximum size
+func (c *imageTransformCache) Put(key string, content []byte) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if element, ok := c.entries[key]; ok {
+		c.lru.MoveToFront(element)
+		return
+	}
+
+	c.entries[key] = c.lru.PushFront(&imageTransformCacheEntry{key: key, content: content})
+	c.size += int64(len(content))
+	c.evict()
+}
+
+func (c *imageTransformCache) evict() {
+	for element := c.lru.Back(); element != nil && c.size > c.maxSize; element = c.lru.Back() {
+		entry := c.lru.Remove(element).(*imageTransformCacheEntry)
+		delete(c.entries, entry.key)
+		c.size -= int64(len(entry.content))
+	}
+}
@@ -0,0 +1,178 @@
+package server
+
+import (
+	"bytes"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/png"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/images"
+)
+
+func makeTestPNG(t *testing.T, width, height int) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.Set(x, y, color.RGBA{R: uint8(x), G: uint8(y), B: 0x80, A: 0xff})
+		}
+	}
+	bb := bytes.NewBuffer([]byte{})
+	require.NoError(t, png.Encode(bb, img))
+	return bb.Bytes()
+}
+
+func serveBytes(mime string, payload []byte) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", mime)
+		w.Header().Set("Cache-Control", "no-store")
+		_, _ = w.Write(payload)
+	}
+}
+
+func TestParseImageTransform(t *testing.T) {
+	transform, err := ParseImageTransform(url.Values{"keyUid": {"0x1"}, "animated": {"1"}})
+	require.NoError(t, err)
+	require.Nil(t, transform)
+
+	transform, err = ParseImageTransform(url.Values{
+		"width":   {"120"},
+		"fit":     {"contain"},
+		"circle":  {"1"},
+		"format":  {"jpeg"},
+		"quality": {"60"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, &ImageTransform{
+		Width:   120,
+		Fit:     images.FitContain,
+		Circle:  true,
+		Format:  images.JPEG,
+		Quality: 60,
+	}, transform)
+
+	for _, params := range []url.Values{
+		{"width": {"-1"}},
+		{"height": {"100000"}},
+		{"fit": {"zoom"}},
+		{"format": {"bmp"}},
+		{"quality": {"0"}},
+		{"format": {"webp"}, "quality": {"50"}},
+	} {
+		_, err = ParseImageTransform(params)
+		require.ErrorIs(t, err, ErrInvalidImageTransform, params.Encode())
+	}
+}
+
+func TestWithImageTransform(t *testing.T) {
+	cache := newImageTransformCache(defaultImageTransformCacheMaxSize)
+	handler := withImageTransform(cache, zap.NewNop(), serveBytes("image/png", makeTestPNG(t, 200, 100)))
+
+	request := func(query string) *httptest.ResponseRecorder {
+		rr := httptest.NewRecorder()
+		handler(rr, httptest.NewRequest(http.MethodGet, "/messages/images?"+query, nil))
+		return rr
+	}
+
+	cases := []struct {
+		query    string
+		mime     string
+		expected image.Rectangle
+	}{
+		{"width=50&height=50", "image/png", image.Rect(0, 0, 50, 50)},
+		{"width=50&height=50&fit=contain&format=webp", "image/webp", image.Rect(0, 0, 50, 25)},
+		{"width=50&format=jpeg&quality=50", "image/jpeg", image.Rect(0, 0, 50, 25)},
+		{"circle=1", "image/png", image.Rect(0, 0, 100, 100)},
+	}
+	for _, c := range cases {
+		for i := 0; i < 2; i++ {
+			// the second time, the variant is served from the cache
+			rr := request(c.query)
+			require.Equal(t, http.StatusOK, rr.Code)
+			require.Equal(t, c.mime, rr.Header().Get("Content-Type"), c.query)
+			require.Equal(t, "no-store", rr.Header().Get("Cache-Control"))
+
+			payload := rr.Body.Bytes()
+			img, err := images.DecodeImageData(payload, bytes.NewReader(payload))
+			require.NoError(t, err)
+			require.Equal(t, c.expected, img.Bounds(), c.query)
+		}
+	}
+
+	require.Len(t, cache.entries, len(cases))
+
+	// the circle's corners are transparent
+	payload := request("circle=1").Body.Bytes()
+	img, err := png.Decode(bytes.NewReader(payload))
+	require.NoError(t, err)
+	_, _, _, a := img.At(0, 0).RGBA()
+	require.Zero(t, a)
+
+	rr := request("width=abc")
+	require.Equal(t, http.StatusBadRequest, rr.Code)
+}
+
+func TestImageTransformTooLarge(t *testing.T) {
+	payload := makeTestPNG(t, 2, 2)
+	// claims a 65536x65536 image in the IHDR chunk, keeping its checksum valid
+	binary.BigEndian.PutUint32(payload[16:20], 1<<16)
+	binary.BigEndian.PutUint32(payload[20:24], 1<<16)
+	binary.BigEndian.PutUint32(payload[29:33], crc32.ChecksumIEEE(payload[12:29]))
+
+	_, _, err := (&ImageTransform{Width: 10}).Apply(payload)
+	require.ErrorIs(t, err, ErrImageTransformTooLarge)
+}
+
+func TestWithImageTransform_Passthrough(t *testing.T) {
+	audio := []byte("not an image")
+	handler := withImageTransform(nil, zap.NewNop(), serveBytes("audio/aac", audio))
+
+	rr := httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/messages/audio?width=10", nil))
+	require.Equal(t, audio, rr.Body.Bytes())
+	require.Equal(t, "audio/aac", rr.Header().Get("Content-Type"))
+
+	// videos are written through as they're served, rather than buffered
+	rr = httptest.NewRecorder()
+	handler = withImageTransform(nil, zap.NewNop(), func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "video/mp4")
+		w.WriteHeader(http.StatusPartialContent)
+		_, _ = w.Write([]byte("first"))
+		require.Equal(t, "first", rr.Body.String())
+		_, _ = w.Write([]byte("second"))
+	})
+	handler(rr, httptest.NewRequest(http.MethodGet, "/messages/videos?width=10", nil))
+	require.Equal(t, http.StatusPartialContent, rr.Code)
+	require.Equal(t, "firstsecond", rr.Body.String())
+
+	// images the transform fails on are served as they are
+	broken := []byte("\x89PNG\r\n\x1a\n broken")
+	handler = withImageTransform(nil, zap.NewNop(), serveBytes("image/png", broken))
+
+	rr = httptest.NewRecorder()
+	handler(rr, httptest.NewRequest(http.MethodGet, "/messages/images?width=10", nil))
+	require.Equal(t, broken, rr.Body.Bytes())
+}
+
+func TestImageTransformCacheEviction(t *testing.T) {
+	cache := newImageTransformCache(10)
+
+	cache.Put("a", []byte("aaaa"))
+	cache.Put("b", []byte("bbbb"))
+
+	// a was used last, so b is evicted first
+	require.Equal(t, []byte("aaaa"), cache.Get("a"))
+	cache.Put("c", []byte("cccc"))
+
+	require.Nil(t, cache.Get("b"))
+	require.Equal(t, []byte("cccc"), cache.Get("c"))
+	require.Equal(t, int64(8), cache.size)
+}
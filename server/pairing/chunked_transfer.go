@@ -0,0 +1,667 @@
+package pairing
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/server/pairing/statecontrol"
+	"github.com/status-im/status-go/server/pairing/versioning"
+	"github.com/status-im/status-go/signal"
+)
+
+const (
+	transferChunkSize  = 256 * 1024
+	transferMaxRetries = 5
+	transferRetryDelay = time.Second
+
+	// Query parameters of chunked transfer requests, requests without any of them are handled by the
+	// LocalPairingV1 handlers
+	transferQueryManifest = "manifest"
+	transferQueryChunk    = "chunk"
+	transferQueryComplete = "complete"
+)
+
+var (
+	ErrInvalidTransferManifest = errors.New("invalid transfer manifest")
+	ErrTransferChunkMismatch   = errors.New("transfer chunk doesn't match its content address")
+	ErrTransferNotStarted      = errors.New("no chunked transfer in progress")
+	ErrTransferIncomplete      = errors.New("chunked transfer is incomplete")
+)
+
+/*
+|--------------------------------------------------------------------------
+| TransferManifest
+|--------------------------------------------------------------------------
+|
+| Describes a payload split in chunks, the payload and its chunks are addressed by their SHA256
+|
+*/
+
+type TransferManifest struct {
+	ID     string   `json:"id"`
+	Size   int      `json:"size"`
+	Chunks []string `json:"chunks"`
+}
+
+func contentAddress(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
+
+func newTransferManifest(payload []byte) *TransferManifest {
+	tm := &TransferManifest{
+		ID:   contentAddress(payload),
+		Size: len(payload),
+	}
+	for offset := 0; offset < len(payload); offset += transferChunkSize {
+		tm.Chunks = append(tm.Chunks, contentAddress(payload[offset:min(offset+transferChunkSize, len(payload))]))
+	}
+	return tm
+}
+
+func (tm *TransferManifest) validate() error {
+	if len(tm.ID) == 0 || tm.Size <= 0 || len(tm.Chunks) != (tm.Size+transferChunkSize-1)/transferChunkSize {
+		return ErrInvalidTransferManifest
+	}
+	return nil
+}
+
+// chunk returns the chunk at index of the given payload, or nil if the index is out of range
+func (tm *TransferManifest) chunk(payload []byte, index int) []byte {
+	if index < 0 || index >= len(tm.Chunks) {
+		return nil
+	}
+	offset := index * transferChunkSize
+	return payload[offset:min(offset+transferChunkSize, len(payload))]
+}
+
+func (tm *TransferManifest) verifyChunk(index int, chunk []byte) error {
+	if index < 0 || index >= len(tm.Chunks) || contentAddress(chunk) != tm.Chunks[index] {
+		return ErrTransferChunkMismatch
+	}
+	return nil
+}
+
+func (tm *TransferManifest) progress(chunks int) TransferProgress {
+	return TransferProgress{Chunks: chunks, TotalChunks: len(tm.Chunks), Size: tm.Size}
+}
+
+func encryptJSON(e *PayloadEncryptor, v interface{}) ([]byte, error) {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return e.encryptPlain(data)
+}
+
+func decryptJSON(e *PayloadEncryptor, data []byte, v interface{}) error {
+	plain, err := e.decryptPlain(data)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(plain, v)
+}
+
+/*
+|--------------------------------------------------------------------------
+| Server handlers
+|--------------------------------------------------------------------------
+|
+| Chunks are encrypted one by one with the PayloadEncryptor, so each of them is authenticated on its own
+|
+*/
+
+func handlePairingVersion() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain")
+		_, _ = w.Write([]byte(strconv.Itoa(int(versioning.LatestLocalPairingVer))))
+	}
+}
+
+func parseChunkIndex(r *http.Request) (int, error) {
+	return strconv.Atoi(r.URL.Query().Get(transferQueryChunk))
+}
+
+// chunkedSender serves the payload of a PayloadMounter in chunks. The payload is mounted only once
+// so that its chunks don't change when the receiver resumes the transfer.
+type chunkedSender struct {
+	logger        *zap.Logger
+	action        Action
+	mounter       PayloadMounter
+	encryptor     *PayloadEncryptor
+	beforeSending func()
+
+	mu       sync.Mutex
+	payload  []byte
+	manifest *TransferManifest
+}
+
+func handleChunkedSend(logger *zap.Logger, action Action, pm PayloadMounter, e *PayloadEncryptor, beforeSending func(), legacy http.HandlerFunc) http.HandlerFunc {
+	cs := &chunkedSender{
+		logger:        logger,
+		action:        action,
+		mounter:       pm,
+		encryptor:     e.Renew(),
+		beforeSending: beforeSending,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Has(transferQueryManifest):
+			cs.sendManifest(w)
+		case query.Has(transferQueryChunk):
+			cs.sendChunk(w, r)
+		case query.Has(transferQueryComplete):
+			cs.complete(w)
+		default:
+			legacy(w, r)
+		}
+	}
+}
+
+func (cs *chunkedSender) sendManifest(w http.ResponseWriter) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.manifest == nil {
+		signal.SendLocalPairingEvent(Event{Type: EventConnectionSuccess, Action: cs.action})
+		err := cs.mounter.Mount()
+		if err != nil {
+			signal.SendLocalPairingEvent(Event{Type: EventTransferError, Error: err.Error(), Action: cs.action})
+			cs.logger.Error("chunkedSender.sendManifest cs.mounter.Mount()", zap.Error(err))
+			http.Error(w, "error", http.StatusInternalServerError)
+			return
+		}
+
+		cs.beforeSending()
+		cs.payload = cs.mounter.ToSend()
+		cs.manifest = newTransferManifest(cs.payload)
+		// the chunks are served from cs.payload, the mounted payload is locked as soon as it is read
+		cs.mounter.LockPayload()
+	}
+
+	manifest, err := encryptJSON(cs.encryptor, cs.manifest)
+	if err != nil {
+		cs.logger.Error("chunkedSender.sendManifest encryptJSON(cs.encryptor, cs.manifest)", zap.Error(err))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = w.Write(manifest)
+	if err != nil {
+		cs.logger.Error("chunkedSender.sendManifest w.Write(manifest)", zap.Error(err))
+	}
+}
+
+func (cs *chunkedSender) sendChunk(w http.ResponseWriter, r *http.Request) {
+	index, err := parseChunkIndex(r)
+	if err != nil {
+		http.Error(w, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.manifest == nil {
+		http.Error(w, ErrTransferNotStarted.Error(), http.StatusBadRequest)
+		return
+	}
+
+	chunk := cs.manifest.chunk(cs.payload, index)
+	if chunk == nil {
+		http.Error(w, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	encrypted, err := cs.encryptor.encryptPlain(chunk)
+	if err != nil {
+		cs.logger.Error("chunkedSender.sendChunk cs.encryptor.encryptPlain(chunk)", zap.Error(err))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = w.Write(encrypted)
+	if err != nil {
+		cs.logger.Error("chunkedSender.sendChunk w.Write(encrypted)", zap.Error(err), zap.Int("index", index))
+		return
+	}
+	signal.SendLocalPairingEvent(Event{Type: EventTransferProgress, Action: cs.action, Data: cs.manifest.progress(index + 1)})
+}
+
+func (cs *chunkedSender) complete(w http.ResponseWriter) {
+	cs.mu.Lock()
+	defer cs.mu.Unlock()
+
+	if cs.manifest == nil {
+		http.Error(w, ErrTransferNotStarted.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cs.payload = nil
+	signal.SendLocalPairingEvent(Event{Type: EventTransferSuccess, Action: cs.action})
+}
+
+// chunkedReceiver accepts a payload in chunks and passes it to a PayloadReceiver once all the chunks
+// have been acknowledged. Acknowledged chunks are kept in a statecontrol.TransferState.
+type chunkedReceiver struct {
+	logger    *zap.Logger
+	action    Action
+	receiver  PayloadReceiver
+	encryptor *PayloadEncryptor
+	transfers *statecontrol.ProcessStateManager
+
+	mu       sync.Mutex
+	manifest *TransferManifest
+}
+
+func handleChunkedReceive(logger *zap.Logger, action Action, pr PayloadReceiver, e *PayloadEncryptor, transfers *statecontrol.ProcessStateManager, legacy http.HandlerFunc) http.HandlerFunc {
+	cr := &chunkedReceiver{
+		logger:    logger,
+		action:    action,
+		receiver:  pr,
+		encryptor: e.Renew(),
+		transfers: transfers,
+	}
+
+	return func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch {
+		case query.Has(transferQueryManifest):
+			cr.receiveManifest(w, r)
+		case query.Has(transferQueryChunk):
+			cr.receiveChunk(w, r)
+		case query.Has(transferQueryComplete):
+			cr.complete(w)
+		default:
+			legacy(w, r)
+		}
+	}
+}
+
+func (cr *chunkedReceiver) receiveManifest(w http.ResponseWriter, r *http.Request) {
+	signal.SendLocalPairingEvent(Event{Type: EventConnectionSuccess, Action: cr.action})
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveManifest io.ReadAll(r.Body)", zap.Error(err))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+
+	manifest := new(TransferManifest)
+	err = decryptJSON(cr.encryptor, body, manifest)
+	if err == nil {
+		err = manifest.validate()
+	}
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveManifest invalid manifest", zap.Error(err))
+		http.Error(w, ErrInvalidTransferManifest.Error(), http.StatusBadRequest)
+		return
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	// a new payload replaces the transfer in progress
+	if cr.manifest != nil && cr.manifest.ID != manifest.ID {
+		cr.transfers.RemoveTransfer(cr.manifest.ID)
+	}
+	cr.manifest = manifest
+	acked, err := encryptJSON(cr.encryptor, cr.transfers.Transfer(manifest.ID, len(manifest.Chunks)).Acked())
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveManifest encryptJSON(acked)", zap.Error(err))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, err = w.Write(acked)
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveManifest w.Write(acked)", zap.Error(err))
+	}
+}
+
+func (cr *chunkedReceiver) receiveChunk(w http.ResponseWriter, r *http.Request) {
+	index, err := parseChunkIndex(r)
+	if err != nil {
+		http.Error(w, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	body, err := io.ReadAll(r.Body)
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveChunk io.ReadAll(r.Body)", zap.Error(err), zap.Int("index", index))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+
+	chunk, err := cr.encryptor.decryptPlain(body)
+	if err != nil {
+		cr.logger.Error("chunkedReceiver.receiveChunk cr.encryptor.decryptPlain(body)", zap.Error(err), zap.Int("index", index))
+		http.Error(w, "invalid chunk", http.StatusBadRequest)
+		return
+	}
+
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cr.manifest == nil {
+		http.Error(w, ErrTransferNotStarted.Error(), http.StatusBadRequest)
+		return
+	}
+
+	err = cr.manifest.verifyChunk(index, chunk)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	state := cr.transfers.Transfer(cr.manifest.ID, len(cr.manifest.Chunks))
+	state.Ack(index, chunk)
+	signal.SendLocalPairingEvent(Event{Type: EventTransferProgress, Action: cr.action, Data: cr.manifest.progress(len(state.Acked()))})
+}
+
+func (cr *chunkedReceiver) complete(w http.ResponseWriter) {
+	cr.mu.Lock()
+	defer cr.mu.Unlock()
+
+	if cr.manifest == nil {
+		http.Error(w, ErrTransferNotStarted.Error(), http.StatusBadRequest)
+		return
+	}
+
+	payload := cr.transfers.Transfer(cr.manifest.ID, len(cr.manifest.Chunks)).Assemble()
+	if payload == nil {
+		http.Error(w, ErrTransferIncomplete.Error(), http.StatusBadRequest)
+		return
+	}
+	// the payload is dropped once assembled, a failed transfer has to start over
+	cr.transfers.RemoveTransfer(cr.manifest.ID)
+	valid := contentAddress(payload) == cr.manifest.ID
+	cr.manifest = nil
+	if !valid {
+		http.Error(w, ErrTransferChunkMismatch.Error(), http.StatusBadRequest)
+		return
+	}
+	signal.SendLocalPairingEvent(Event{Type: EventTransferSuccess, Action: cr.action})
+
+	err := cr.receiver.Receive(payload)
+	if err != nil {
+		signal.SendLocalPairingEvent(Event{Type: EventProcessError, Error: err.Error(), Action: cr.action})
+		cr.logger.Error("chunkedReceiver.complete cr.receiver.Receive(payload)", zap.Error(err))
+		http.Error(w, "error", http.StatusInternalServerError)
+		return
+	}
+	signal.SendLocalPairingEvent(Event{Type: EventProcessSuccess, Action: cr.action})
+}
+
+/*
+|--------------------------------------------------------------------------
+| Client transfers
+|--------------------------------------------------------------------------
+|
+| Failed requests are retried over a new connection, only the chunks that weren't acknowledged are transferred again
+|
+*/
+
+// transferStatusError is returned when the server rejected a request, these requests aren't retried
+type transferStatusError struct {
+	status string
+}
+
+func (e *transferStatusError) Error() string {
+	return fmt.Sprintf("[client] status not ok during chunked transfer, received '%s'", e.status)
+}
+
+// negotiateVersion returns the highest LocalPairingVersion supported by both sides,
+// servers without the version handler only support LocalPairingV1
+func (c *BaseClient) negotiateVersion() versioning.LocalPairingVersion {
+	resp, err := c.Get(c.transferURL(pairingVersion, nil))
+	if err != nil {
+		return versioning.LocalPairingV1
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return versioning.LocalPairingV1
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return versioning.LocalPairingV1
+	}
+
+	v, err := strconv.Atoi(strings.TrimSpace(string(body)))
+	if err != nil || v < int(versioning.LocalPairingV1) {
+		return versioning.LocalPairingV1
+	}
+
+	return min(versioning.LocalPairingVersion(v), versioning.LatestLocalPairingVer)
+}
+
+func (c *BaseClient) transferURL(path string, query url.Values) string {
+	u := *c.baseAddress
+	u.Path = path
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+func (c *BaseClient) doTransferRequest(method string, path string, query url.Values, body []byte) ([]byte, error) {
+	err := c.getChallenge()
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequest(method, c.transferURL(path, query), bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	err = c.challengeTaker.DoChallenge(req)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &transferStatusError{status: resp.Status}
+	}
+
+	return io.ReadAll(resp.Body)
+}
+
+func retryTransfer(fn func() error) error {
+	var err error
+	for attempt := 0; attempt < transferMaxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(time.Duration(attempt) * transferRetryDelay)
+		}
+
+		err = fn()
+		var statusErr *transferStatusError
+		if err == nil || errors.As(err, &statusErr) {
+			return err
+		}
+	}
+	return err
+}
+
+// sendChunked uploads the mounted payload in chunks, skipping the chunks the server already acknowledged.
+// The payload is locked once transferred, like the payloads sent in a single request.
+func (c *BaseClient) sendChunked(path string, action Action, pm PayloadMounter) error {
+	err := c.sendChunks(path, action, pm.ToSend())
+	if err != nil {
+		signal.SendLocalPairingEvent(Event{Type: EventTransferError, Error: err.Error(), Action: action})
+		return err
+	}
+
+	signal.SendLocalPairingEvent(Event{Type: EventTransferSuccess, Action: action})
+	pm.LockPayload()
+	return nil
+}
+
+func (c *BaseClient) sendChunks(path string, action Action, payload []byte) error {
+	manifest := newTransferManifest(payload)
+	encryptedManifest, err := encryptJSON(c.encryptor, manifest)
+	if err != nil {
+		return err
+	}
+
+	var acked []int
+	err = retryTransfer(func() error {
+		response, err := c.doTransferRequest(http.MethodPost, path, url.Values{transferQueryManifest: {"1"}}, encryptedManifest)
+		if err != nil {
+			return err
+		}
+		return decryptJSON(c.encryptor, response, &acked)
+	})
+	if err != nil {
+		return err
+	}
+
+	done := make(map[int]bool, len(acked))
+	for _, index := range acked {
+		done[index] = true
+	}
+
+	for index := range manifest.Chunks {
+		if done[index] {
+			continue
+		}
+
+		chunk, err := c.encryptor.encryptPlain(manifest.chunk(payload, index))
+		if err != nil {
+			return err
+		}
+
+		err = retryTransfer(func() error {
+			_, err := c.doTransferRequest(http.MethodPost, path, url.Values{transferQueryChunk: {strconv.Itoa(index)}}, chunk)
+			return err
+		})
+		if err != nil {
+			return err
+		}
+
+		done[index] = true
+		signal.SendLocalPairingEvent(Event{Type: EventTransferProgress, Action: action, Data: manifest.progress(len(done))})
+	}
+
+	return retryTransfer(func() error {
+		_, err := c.doTransferRequest(http.MethodPost, path, url.Values{transferQueryComplete: {"1"}}, nil)
+		return err
+	})
+}
+
+// receiveChunked downloads a payload in chunks. Downloaded chunks are kept in a statecontrol.TransferState
+// while the requests are retried, and dropped once the transfer succeeded or failed.
+func (c *BaseClient) receiveChunked(path string, action Action) ([]byte, error) {
+	payload, err := c.receiveChunks(path, action)
+	if err != nil {
+		signal.SendLocalPairingEvent(Event{Type: EventTransferError, Error: err.Error(), Action: action})
+		return nil, err
+	}
+
+	signal.SendLocalPairingEvent(Event{Type: EventTransferSuccess, Action: action})
+	return payload, nil
+}
+
+func (c *BaseClient) receiveChunks(path string, action Action) ([]byte, error) {
+	manifest := new(TransferManifest)
+	err := retryTransfer(func() error {
+		response, err := c.doTransferRequest(http.MethodGet, path, url.Values{transferQueryManifest: {"1"}}, nil)
+		if err != nil {
+			return err
+		}
+
+		err = decryptJSON(c.encryptor, response, manifest)
+		if err != nil {
+			return err
+		}
+		return manifest.validate()
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	state := c.transfers.Transfer(manifest.ID, len(manifest.Chunks))
+	defer c.transfers.RemoveTransfer(manifest.ID)
+
+	for index := range manifest.Chunks {
+		if state.IsAcked(index) {
+			continue
+		}
+
+		err = retryTransfer(func() error {
+			response, err := c.doTransferRequest(http.MethodGet, path, url.Values{transferQueryChunk: {strconv.Itoa(index)}}, nil)
+			if err != nil {
+				return err
+			}
+
+			chunk, err := c.encryptor.decryptPlain(response)
+			if err != nil {
+				return err
+			}
+
+			err = manifest.verifyChunk(index, chunk)
+			if err != nil {
+				return err
+			}
+
+			state.Ack(index, chunk)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		signal.SendLocalPairingEvent(Event{Type: EventTransferProgress, Action: action, Data: manifest.progress(len(state.Acked()))})
+	}
+
+	payload := state.Assemble()
+	if contentAddress(payload) != manifest.ID {
+		return nil, ErrTransferChunkMismatch
+	}
+
+	err = retryTransfer(func() error {
+		_, err := c.doTransferRequest(http.MethodPost, path, url.Values{transferQueryComplete: {"1"}}, nil)
+		return err
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return payload, nil
+}
+
+// processReceived passes a payload received in chunks to its PayloadReceiver
+func processReceived(pr PayloadReceiver, payload []byte, action Action) error {
+	err := pr.Receive(payload)
+	if err != nil {
+		signal.SendLocalPairingEvent(Event{Type: EventProcessError, Error: err.Error(), Action: action})
+		return err
+	}
+	signal.SendLocalPairingEvent(Event{Type: EventProcessSuccess, Action: action})
+	return nil
+}
@@ -0,0 +1,241 @@
+package pairing
+
+import (
+	"crypto/rand"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+
+	"github.com/status-im/status-go/server/pairing/statecontrol"
+	"github.com/status-im/status-go/server/pairing/versioning"
+)
+
+const (
+	testChunkedSendPath    = pairingBase + "/testSend"
+	testChunkedReceivePath = pairingBase + "/testReceive"
+)
+
+type testPayloadMounter struct {
+	*MockPayloadMounter
+	data []byte
+}
+
+func (m *testPayloadMounter) Mount() error {
+	return m.encryptor.encrypt(m.data)
+}
+
+// dropChunkOnce closes the connection of the first request for the given chunk, the client has to retry it
+func dropChunkOnce(chunk string, next http.HandlerFunc) http.HandlerFunc {
+	var once sync.Once
+	return func(w http.ResponseWriter, r *http.Request) {
+		dropped := false
+		if r.URL.Query().Get(transferQueryChunk) == chunk {
+			once.Do(func() {
+				dropped = true
+				conn, _, err := w.(http.Hijacker).Hijack()
+				if err == nil {
+					_ = conn.Close()
+				}
+			})
+		}
+		if !dropped {
+			next(w, r)
+		}
+	}
+}
+
+func setupChunkedTransfer(t *testing.T, aesKey []byte, mounter PayloadMounter, receiver PayloadReceiver, withVersion bool) *BaseClient {
+	logger := zap.NewNop()
+	e := NewPayloadEncryptor(aesKey)
+	cg, err := NewChallengeGiver(e, logger)
+	require.NoError(t, err)
+
+	legacy := func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unexpected legacy request", http.StatusTeapot)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc(pairingChallenge, handlePairingChallenge(cg))
+	if withVersion {
+		mux.HandleFunc(pairingVersion, handlePairingVersion())
+	}
+	mux.HandleFunc(testChunkedSendPath, middlewareChallenge(cg, dropChunkOnce("1", handleChunkedSend(logger, ActionPairingAccount, mounter, e, func() {}, legacy))))
+	mux.HandleFunc(testChunkedReceivePath, dropChunkOnce("2", handleChunkedReceive(logger, ActionSyncDevice, receiver, e, new(statecontrol.ProcessStateManager), legacy)))
+
+	ts := httptest.NewServer(mux)
+	t.Cleanup(ts.Close)
+
+	baseAddress, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+
+	return &BaseClient{
+		Client:         &http.Client{Jar: jar},
+		baseAddress:    baseAddress,
+		challengeTaker: NewChallengeTaker(e),
+		version:        versioning.LocalPairingV1,
+		encryptor:      NewPayloadEncryptor(aesKey),
+		transfers:      new(statecontrol.ProcessStateManager),
+	}
+}
+
+func randomPayload(t *testing.T, size int) []byte {
+	data := make([]byte, size)
+	_, err := rand.Read(data)
+	require.NoError(t, err)
+	return data
+}
+
+func TestChunkedTransfer(t *testing.T) {
+	aesKey := randomPayload(t, 32)
+	sent := randomPayload(t, 3*transferChunkSize+42)
+
+	mounter := &testPayloadMounter{MockPayloadMounter: NewMockPayloadMounter(aesKey), data: sent}
+	receiver := NewMockPayloadReceiver(aesKey)
+	c := setupChunkedTransfer(t, aesKey, mounter, receiver, true)
+
+	require.Equal(t, versioning.LocalPairingV2, c.negotiateVersion())
+
+	// Pull the mounted payload, chunk 1 is retried after the connection is dropped
+	payload, err := c.receiveChunked(testChunkedSendPath, ActionPairingAccount)
+	require.NoError(t, err)
+
+	clientReceiver := NewMockPayloadReceiver(aesKey)
+	require.NoError(t, processReceived(clientReceiver, payload, ActionPairingAccount))
+	require.Equal(t, sent, clientReceiver.Received())
+	// The payload is locked once it is read for the transfer
+	require.Nil(t, mounter.ToSend())
+
+	// Push a payload, chunk 2 is retried after the connection is dropped
+	pushed := randomPayload(t, 2*transferChunkSize+1)
+	pushMounter := &testPayloadMounter{MockPayloadMounter: NewMockPayloadMounter(aesKey), data: pushed}
+	require.NoError(t, pushMounter.Mount())
+	require.NoError(t, c.sendChunked(testChunkedReceivePath, ActionSyncDevice, pushMounter))
+	require.Equal(t, pushed, receiver.Received())
+	require.Nil(t, pushMounter.ToSend())
+}
+
+func TestChunkedTransfer_DropStateOnFailure(t *testing.T) {
+	aesKey := randomPayload(t, 32)
+	e := NewPayloadEncryptor(aesKey)
+	mounter := &testPayloadMounter{MockPayloadMounter: NewMockPayloadMounter(aesKey), data: randomPayload(t, 2*transferChunkSize)}
+
+	// chunk 1 is rejected on the first attempt, the transfer fails without retries
+	rejectChunk := true
+	var requestedChunks []string
+	sender := handleChunkedSend(zap.NewNop(), ActionPairingAccount, mounter, e, func() {}, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc(pairingChallenge, func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc(testChunkedSendPath, func(w http.ResponseWriter, r *http.Request) {
+		chunk := r.URL.Query().Get(transferQueryChunk)
+		if chunk != "" {
+			requestedChunks = append(requestedChunks, chunk)
+		}
+		if chunk == "1" && rejectChunk {
+			http.Error(w, "error", http.StatusBadRequest)
+			return
+		}
+		sender(w, r)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseAddress, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	c := &BaseClient{
+		Client:         ts.Client(),
+		baseAddress:    baseAddress,
+		challengeTaker: NewChallengeTaker(e),
+		encryptor:      NewPayloadEncryptor(aesKey),
+		transfers:      new(statecontrol.ProcessStateManager),
+	}
+
+	_, err = c.receiveChunked(testChunkedSendPath, ActionPairingAccount)
+	require.Error(t, err)
+	// the mounted payload is locked once read, the chunks are served from the copy of the transfer
+	require.Nil(t, mounter.ToSend())
+
+	// the chunk received by the failed attempt was dropped, the next attempt starts over
+	rejectChunk = false
+	requestedChunks = nil
+	payload, err := c.receiveChunked(testChunkedSendPath, ActionPairingAccount)
+	require.NoError(t, err)
+	require.Equal(t, []string{"0", "1", "2"}, requestedChunks)
+
+	clientReceiver := NewMockPayloadReceiver(aesKey)
+	require.NoError(t, processReceived(clientReceiver, payload, ActionPairingAccount))
+	require.Equal(t, mounter.data, clientReceiver.Received())
+}
+
+func TestChunkedTransfer_ResumeAcknowledgedChunks(t *testing.T) {
+	aesKey := randomPayload(t, 32)
+	e := NewPayloadEncryptor(aesKey)
+	receiver := NewMockPayloadReceiver(aesKey)
+	transfers := new(statecontrol.ProcessStateManager)
+
+	var requestedChunks []string
+	handler := handleChunkedReceive(zap.NewNop(), ActionSyncDevice, receiver, e, transfers, nil)
+	mux := http.NewServeMux()
+	mux.HandleFunc(pairingChallenge, func(w http.ResponseWriter, r *http.Request) {})
+	mux.HandleFunc(testChunkedReceivePath, func(w http.ResponseWriter, r *http.Request) {
+		if chunk := r.URL.Query().Get(transferQueryChunk); chunk != "" {
+			requestedChunks = append(requestedChunks, chunk)
+		}
+		handler(w, r)
+	})
+	ts := httptest.NewServer(mux)
+	defer ts.Close()
+
+	baseAddress, err := url.Parse(ts.URL)
+	require.NoError(t, err)
+	c := &BaseClient{
+		Client:         ts.Client(),
+		baseAddress:    baseAddress,
+		challengeTaker: NewChallengeTaker(e),
+		encryptor:      NewPayloadEncryptor(aesKey),
+		transfers:      new(statecontrol.ProcessStateManager),
+	}
+
+	mounter := &testPayloadMounter{MockPayloadMounter: NewMockPayloadMounter(aesKey), data: randomPayload(t, 3*transferChunkSize)}
+	require.NoError(t, mounter.Mount())
+	payload := mounter.ToSend()
+
+	// The first attempt was interrupted after chunks 0 and 2 were acknowledged
+	manifest := newTransferManifest(payload)
+	state := transfers.Transfer(manifest.ID, len(manifest.Chunks))
+	state.Ack(0, manifest.chunk(payload, 0))
+	state.Ack(2, manifest.chunk(payload, 2))
+
+	require.NoError(t, c.sendChunked(testChunkedReceivePath, ActionSyncDevice, mounter))
+	require.Equal(t, []string{"1", "3"}, requestedChunks)
+	require.Equal(t, mounter.data, receiver.Received())
+}
+
+func TestChunkedTransfer_NegotiateLegacyServer(t *testing.T) {
+	aesKey := randomPayload(t, 32)
+	c := setupChunkedTransfer(t, aesKey, NewMockPayloadMounter(aesKey), NewMockPayloadReceiver(aesKey), false)
+	require.Equal(t, versioning.LocalPairingV1, c.negotiateVersion())
+}
+
+func TestTransferManifest(t *testing.T) {
+	payload := randomPayload(t, 2*transferChunkSize+10)
+	manifest := newTransferManifest(payload)
+	require.NoError(t, manifest.validate())
+	require.Len(t, manifest.Chunks, 3)
+	require.Len(t, manifest.chunk(payload, 2), 10)
+	require.Nil(t, manifest.chunk(payload, 3))
+
+	require.NoError(t, manifest.verifyChunk(1, manifest.chunk(payload, 1)))
+	require.ErrorIs(t, manifest.verifyChunk(0, manifest.chunk(payload, 1)), ErrTransferChunkMismatch)
+
+	manifest.Chunks = manifest.Chunks[:2]
+	require.ErrorIs(t, manifest.validate(), ErrInvalidTransferManifest)
+}
@@ -19,6 +19,9 @@ import (
 	gocommon "github.com/status-im/status-go/common"
 	"github.com/status-im/status-go/logutils"
 	"github.com/status-im/status-go/server"
+	"github.com/status-im/status-go/server/pairing/relay"
+	"github.com/status-im/status-go/server/pairing/statecontrol"
+	"github.com/status-im/status-go/server/pairing/versioning"
 	"github.com/status-im/status-go/signal"
 	"github.com/status-im/status-go/timesource"
 )
@@ -38,6 +41,11 @@ type BaseClient struct {
 	serverCert     *x509.Certificate
 	baseAddress    *url.URL
 	challengeTaker *ChallengeTaker
+
+	// version is the LocalPairingVersion negotiated with the server, chunked transfers are used from LocalPairingV2
+	version   versioning.LocalPairingVersion
+	encryptor *PayloadEncryptor
+	transfers *statecontrol.ProcessStateManager
 }
 
 func findServerCert(c *ConnectionParams, reachableIPs []net.IP) (*url.URL, *x509.Certificate, error) {
@@ -98,12 +106,18 @@ func NewBaseClient(c *ConnectionParams, logger *zap.Logger) (*BaseClient, error)
 	netIPs, err := server.FindReachableAddressesForPairingClient(c.netIPs)
 	if err != nil {
 		logger.Error("[local pair client] failed to find reachable addresses", zap.Error(err), zap.Any("netIPs", netIPs))
+		if c.relayURL != "" {
+			return newRelayBaseClient(c, logger)
+		}
 		signal.SendLocalPairingEvent(Event{Type: EventConnectionError, Error: err.Error(), Action: ActionConnect})
 		return nil, err
 	}
 	// if client and server aren't on the same network, netIPs maybe empty, we should check it before invoking findServerCert
 	if len(netIPs) == 0 {
 		logger.Error("[local pair client] no reachable addresses found")
+		if c.relayURL != "" {
+			return newRelayBaseClient(c, logger)
+		}
 		signal.SendLocalPairingEvent(Event{Type: EventConnectionError, Error: "no reachable addresses found", Action: ActionConnect})
 		return nil, fmt.Errorf("no reachable addresses found")
 	}
@@ -120,6 +134,10 @@ func NewBaseClient(c *ConnectionParams, logger *zap.Logger) (*BaseClient, error)
 	}
 
 	if serverCert == nil {
+		if c.relayURL != "" {
+			logger.Warn("[local pair client] falling back to relay", zap.Error(certErrs))
+			return newRelayBaseClient(c, logger)
+		}
 		certErrs = fmt.Errorf("failed to connect to any of given addresses. %w", certErrs)
 		signal.SendLocalPairingEvent(Event{Type: EventConnectionError, Error: certErrs.Error(), Action: ActionConnect})
 		return nil, certErrs
@@ -162,6 +180,36 @@ func NewBaseClient(c *ConnectionParams, logger *zap.Logger) (*BaseClient, error)
 		serverCert:     serverCert,
 		challengeTaker: NewChallengeTaker(NewPayloadEncryptor(c.aesKey)),
 		baseAddress:    baseAddress,
+		version:        versioning.LocalPairingV1,
+		encryptor:      NewPayloadEncryptor(c.aesKey),
+		transfers:      new(statecontrol.ProcessStateManager),
+	}, nil
+}
+
+// newRelayBaseClient returns a BaseClient tunneling its requests through the relay of the given ConnectionParams.
+// Relayed requests are sealed with the AES key of the connection string instead of relying on TLS.
+func newRelayBaseClient(c *ConnectionParams, logger *zap.Logger) (*BaseClient, error) {
+	tr, err := relay.NewTransport(c.relayURL, c.aesKey)
+	if err != nil {
+		signal.SendLocalPairingEvent(Event{Type: EventConnectionError, Error: err.Error(), Action: ActionConnect})
+		return nil, err
+	}
+
+	cj, err := cookiejar.New(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	logger.Info("[local pair client] connecting through relay", zap.String("relayURL", c.relayURL))
+	signal.SendLocalPairingEvent(Event{Type: EventConnectionSuccess, Action: ActionConnect, Data: ConnectionData{Relayed: true}})
+
+	return &BaseClient{
+		Client:         &http.Client{Transport: tr, Jar: cj},
+		challengeTaker: NewChallengeTaker(NewPayloadEncryptor(c.aesKey)),
+		baseAddress:    &url.URL{Scheme: "https", Host: "relay"},
+		version:        versioning.LocalPairingV1,
+		encryptor:      NewPayloadEncryptor(c.aesKey),
+		transfers:      new(statecontrol.ProcessStateManager),
 	}, nil
 }
 
@@ -226,6 +274,10 @@ func (c *SenderClient) sendAccountData() error {
 		return err
 	}
 
+	if c.version >= versioning.LocalPairingV2 {
+		return c.sendChunked(pairingReceiveAccount, ActionPairingAccount, c.accountMounter)
+	}
+
 	c.baseAddress.Path = pairingReceiveAccount
 	resp, err := c.Post(c.baseAddress.String(), "application/octet-stream", bytes.NewBuffer(c.accountMounter.ToSend()))
 	if err != nil {
@@ -251,6 +303,10 @@ func (c *SenderClient) sendSyncDeviceData() error {
 		return err
 	}
 
+	if c.version >= versioning.LocalPairingV2 {
+		return c.sendChunked(pairingReceiveSyncDevice, ActionSyncDevice, c.rawMessageMounter)
+	}
+
 	c.baseAddress.Path = pairingReceiveSyncDevice
 	resp, err := c.Post(c.baseAddress.String(), "application/octet-stream", bytes.NewBuffer(c.rawMessageMounter.ToSend()))
 	if err != nil {
@@ -269,6 +325,14 @@ func (c *SenderClient) sendSyncDeviceData() error {
 }
 
 func (c *SenderClient) receiveInstallationData() error {
+	if c.version >= versioning.LocalPairingV2 {
+		payload, err := c.receiveChunked(pairingSendInstallation, ActionPairingInstallation)
+		if err != nil {
+			return err
+		}
+		return processReceived(c.installationMounter, payload, ActionPairingInstallation)
+	}
+
 	c.baseAddress.Path = pairingSendInstallation
 	req, err := http.NewRequest(http.MethodGet, c.baseAddress.String(), nil)
 	if err != nil {
@@ -333,6 +397,7 @@ func StartUpSendingClient(backend *api.GethStatusBackend, cs string, conf *Sende
 	if err != nil {
 		return err
 	}
+	c.version = c.negotiateVersion()
 	err = c.sendAccountData()
 	if err != nil {
 		return err
@@ -391,6 +456,14 @@ func NewReceiverClient(backend *api.GethStatusBackend, c *ConnectionParams, conf
 }
 
 func (c *ReceiverClient) receiveAccountData() error {
+	if c.version >= versioning.LocalPairingV2 {
+		payload, err := c.receiveChunked(pairingSendAccount, ActionPairingAccount)
+		if err != nil {
+			return err
+		}
+		return processReceived(c.accountReceiver, payload, ActionPairingAccount)
+	}
+
 	c.baseAddress.Path = pairingSendAccount
 	req, err := http.NewRequest(http.MethodGet, c.baseAddress.String(), nil)
 	if err != nil {
@@ -432,6 +505,14 @@ func (c *ReceiverClient) receiveAccountData() error {
 }
 
 func (c *ReceiverClient) receiveSyncDeviceData() error {
+	if c.version >= versioning.LocalPairingV2 {
+		payload, err := c.receiveChunked(pairingSendSyncDevice, ActionSyncDevice)
+		if err != nil {
+			return err
+		}
+		return processReceived(c.rawMessageReceiver, payload, ActionSyncDevice)
+	}
+
 	c.baseAddress.Path = pairingSendSyncDevice
 	req, err := http.NewRequest(http.MethodGet, c.baseAddress.String(), nil)
 	if err != nil {
@@ -478,6 +559,10 @@ func (c *ReceiverClient) sendInstallationData() error {
 		return err
 	}
 
+	if c.version >= versioning.LocalPairingV2 {
+		return c.sendChunked(pairingReceiveInstallation, ActionPairingInstallation, c.installationReceiver)
+	}
+
 	c.baseAddress.Path = pairingReceiveInstallation
 	req, err := http.NewRequest(http.MethodPost, c.baseAddress.String(), bytes.NewBuffer(c.installationReceiver.ToSend()))
 	if err != nil {
@@ -541,6 +626,7 @@ func StartUpReceivingClient(backend *api.GethStatusBackend, cs string, conf *Rec
 	if err != nil {
 		return err
 	}
+	c.version = c.negotiateVersion()
 
 	err = c.getChallenge()
 	if err != nil {
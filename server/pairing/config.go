@@ -55,6 +55,10 @@ type ServerConfig struct {
 	// Timeout the number of milliseconds after which the pairing server will automatically terminate
 	Timeout uint `json:"timeout" validate:"omitempty,gte=0"`
 
+	// RelayURL is the rendezvous relay the pairing requests are tunneled through when the client
+	// can't reach the server on the local network. Relaying is disabled when empty.
+	RelayURL string `json:"relayURL" validate:"omitempty,url"`
+
 	// Connection fields, not json (un)marshalled
 	// Required for the server, but MUST NOT come from client
 
@@ -28,6 +28,8 @@ type ConnectionParams struct {
 	aesKey         []byte
 	installationID string
 	keyUID         string
+	// relayURL is the optional rendezvous relay used when the server isn't reachable on the local network
+	relayURL string
 }
 
 func NewConnectionParams(netIPs []net.IP, port int, publicKey *ecdsa.PublicKey, aesKey []byte, installationID, keyUID string) *ConnectionParams {
@@ -59,6 +61,7 @@ func NewConnectionParams(netIPs []net.IP, port int, publicKey *ecdsa.PublicKey,
 //   - AES encryption key
 //   - string InstallationID of the sending device
 //   - string KeyUID of the sending device
+//   - string URL of the rendezvous relay, only present when set
 //
 // NOTE:
 // - append(accrete) parameters instead of changing(breaking) existing parameters. Appending should **never** break, modifying existing parameters will break. Watch this before making changes: https://www.youtube.com/watch?v=oyLBGkS5ICk
@@ -87,7 +90,11 @@ func (cp *ConnectionParams) ToString() string {
 		kuid = base58.Encode([]byte(cp.keyUID))
 	}
 
-	return fmt.Sprintf("%s%s:%s:%s:%s:%s:%s:%s", connectionStringID, v, ips, p, k, ek, i, kuid)
+	cs := fmt.Sprintf("%s%s:%s:%s:%s:%s:%s:%s", connectionStringID, v, ips, p, k, ek, i, kuid)
+	if cp.relayURL != "" {
+		cs += ":" + base58.Encode([]byte(cp.relayURL))
+	}
+	return cs
 }
 
 func (cp *ConnectionParams) InstallationID() string {
@@ -98,6 +105,15 @@ func (cp *ConnectionParams) KeyUID() string {
 	return cp.keyUID
 }
 
+func (cp *ConnectionParams) RelayURL() string {
+	return cp.relayURL
+}
+
+// SetRelayURL sets the rendezvous relay the client falls back to when the server isn't reachable directly
+func (cp *ConnectionParams) SetRelayURL(relayURL string) {
+	cp.relayURL = relayURL
+}
+
 func SerializeNetIps(ips []net.IP) []byte {
 	var out []byte
 	var ipv4 []net.IP
@@ -196,6 +212,10 @@ func (cp *ConnectionParams) FromString(s string) error {
 		cp.keyUID = string(decodedBytes)
 	}
 
+	if len(sData) > 7 && len(sData[7]) != 0 {
+		cp.relayURL = string(base58.Decode(sData[7]))
+	}
+
 	return cp.validate()
 }
 
@@ -215,6 +235,11 @@ func (cp *ConnectionParams) validate() error {
 		return err
 	}
 
+	err = cp.validateRelayURL()
+	if err != nil {
+		return err
+	}
+
 	return cp.validateAESKey()
 }
 
@@ -248,6 +273,18 @@ func (cp *ConnectionParams) validatePublicKey() error {
 	}
 }
 
+func (cp *ConnectionParams) validateRelayURL() error {
+	if cp.relayURL == "" {
+		return nil
+	}
+
+	u, err := url.Parse(cp.relayURL)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return fmt.Errorf("invalid relay url '%s'", cp.relayURL)
+	}
+	return nil
+}
+
 func (cp *ConnectionParams) validateAESKey() error {
 	if len(cp.aesKey) != 32 {
 		return fmt.Errorf("AES key invalid length, expect length 32, received length '%d'", len(cp.aesKey))
@@ -5,6 +5,7 @@ import (
 	"net"
 	"sort"
 	"strconv"
+	"strings"
 	"testing"
 
 	"github.com/stretchr/testify/suite"
@@ -152,3 +153,21 @@ func (s *ConnectionParamsSuite) TestParseConnectionStringWithKeyUIDAndInstallati
 	s.Require().NotEmpty(cp.InstallationID)
 	s.Require().NotEmpty(cp.KeyUID)
 }
+
+func (s *ConnectionParamsSuite) TestConnectionParams_RelayURL() {
+	cp := new(ConnectionParams)
+	s.Require().NoError(cp.FromString(connectionString))
+	s.Require().Empty(cp.RelayURL())
+
+	cp.SetRelayURL("https://relay.status.im")
+	cs := cp.ToString()
+	s.Require().True(strings.HasPrefix(cs, connectionString+":"))
+
+	parsed := new(ConnectionParams)
+	s.Require().NoError(parsed.FromString(cs))
+	s.Require().Equal("https://relay.status.im", parsed.RelayURL())
+	s.Require().Equal(cp.KeyUID(), parsed.KeyUID())
+
+	cp.SetRelayURL("ftp://relay.status.im")
+	s.Require().Error(new(ConnectionParams).FromString(cp.ToString()))
+}
@@ -13,6 +13,7 @@ const (
 	EventConnectionSuccess    EventType = "connection-success"
 	EventTransferError        EventType = "transfer-error"
 	EventTransferSuccess      EventType = "transfer-success"
+	EventTransferProgress     EventType = "transfer-progress"
 	EventReceivedInstallation EventType = "received-installation"
 
 	// Only Receiver side
@@ -47,3 +48,16 @@ type AccountData struct {
 	Password string                 `json:"password,omitempty"`
 	ChatKey  string                 `json:"chatKey,omitempty"`
 }
+
+// ConnectionData is the Data of EventConnectionSuccess events of ActionConnect
+type ConnectionData struct {
+	// Relayed is true when the devices couldn't reach each other and pairing goes through the relay
+	Relayed bool `json:"relayed"`
+}
+
+// TransferProgress is the Data of EventTransferProgress events, sent after each acknowledged chunk
+type TransferProgress struct {
+	Chunks      int `json:"chunks"`
+	TotalChunks int `json:"totalChunks"`
+	Size        int `json:"size"`
+}
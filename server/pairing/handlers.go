@@ -13,6 +13,7 @@ const (
 	// Handler routes for pairing
 	pairingBase                = "/pairing"
 	pairingChallenge           = pairingBase + "/challenge"
+	pairingVersion             = pairingBase + "/version"
 	pairingSendAccount         = pairingBase + "/sendAccount"
 	pairingReceiveAccount      = pairingBase + "/receiveAccount"
 	pairingSendSyncDevice      = pairingBase + "/sendSyncDevice"
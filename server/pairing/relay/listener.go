@@ -0,0 +1,176 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+)
+
+const (
+	// RemoteAddr is the remote address of relayed requests, all of them come from the same relay
+	RemoteAddr = "127.0.0.2:0"
+
+	defaultIdleTimeout = 5 * time.Minute
+	retryDelay         = time.Second
+)
+
+// Listener polls a relay for the requests of a rendezvous and serves them with an http.Handler
+type Listener struct {
+	endpoint    *endpoint
+	handler     http.Handler
+	logger      *zap.Logger
+	idleTimeout time.Duration
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewListener returns a Listener, it stops once no request has been relayed for idleTimeout
+func NewListener(relayURL string, aesKey []byte, handler http.Handler, idleTimeout time.Duration, logger *zap.Logger) (*Listener, error) {
+	e, err := newEndpoint(relayURL, aesKey)
+	if err != nil {
+		return nil, err
+	}
+
+	if idleTimeout == 0 {
+		idleTimeout = defaultIdleTimeout
+	}
+
+	return &Listener{
+		endpoint:    e,
+		handler:     handler,
+		logger:      logger,
+		idleTimeout: idleTimeout,
+	}, nil
+}
+
+func (l *Listener) Start() {
+	ctx, cancel := context.WithCancel(context.Background())
+	l.cancel = cancel
+
+	l.wg.Add(1)
+	go func() {
+		defer gocommon.LogOnPanic()
+		defer l.wg.Done()
+		l.run(ctx)
+	}()
+}
+
+func (l *Listener) Stop() {
+	if l.cancel != nil {
+		l.cancel()
+	}
+	l.wg.Wait()
+}
+
+func (l *Listener) run(ctx context.Context) {
+	lastRequest := time.Now()
+	for ctx.Err() == nil {
+		if time.Since(lastRequest) > l.idleTimeout {
+			l.logger.Info("relay listener idle, stopping")
+			return
+		}
+
+		msg, err := l.endpoint.poll(ctx, requestsPath, "")
+		if ctx.Err() != nil {
+			return
+		}
+		if err != nil {
+			l.logger.Warn("failed to poll relay", zap.Error(err))
+			select {
+			case <-time.After(retryDelay):
+			case <-ctx.Done():
+			}
+			continue
+		}
+		if msg == nil {
+			continue
+		}
+
+		lastRequest = time.Now()
+		// Requests are served one at a time, the pairing handlers expect the client's order
+		err = l.serve(ctx, msg)
+		if err != nil {
+			l.logger.Warn("failed to serve relayed request", zap.String("requestID", msg.id), zap.Error(err))
+		}
+	}
+}
+
+func (l *Listener) serve(ctx context.Context, msg *message) error {
+	req, err := http.ReadRequest(bufio.NewReader(bytes.NewReader(msg.data)))
+	if err != nil {
+		return err
+	}
+	req.RemoteAddr = RemoteAddr
+	req = req.WithContext(ctx)
+
+	rw := newResponseWriter()
+	l.handler.ServeHTTP(rw, req)
+
+	response, err := rw.dump()
+	if err != nil {
+		return err
+	}
+
+	return l.endpoint.post(ctx, responsesPath, msg.id, response)
+}
+
+// responseWriter buffers the response of a relayed request
+type responseWriter struct {
+	header http.Header
+	status int
+	body   bytes.Buffer
+}
+
+func newResponseWriter() *responseWriter {
+	return &responseWriter{header: make(http.Header)}
+}
+
+func (rw *responseWriter) Header() http.Header {
+	return rw.header
+}
+
+func (rw *responseWriter) Write(b []byte) (int, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+	return rw.body.Write(b)
+}
+
+func (rw *responseWriter) WriteHeader(status int) {
+	if rw.status == 0 {
+		rw.status = status
+	}
+}
+
+func (rw *responseWriter) dump() ([]byte, error) {
+	if rw.status == 0 {
+		rw.status = http.StatusOK
+	}
+
+	rw.header.Set("Content-Length", strconv.Itoa(rw.body.Len()))
+	resp := &http.Response{
+		StatusCode:    rw.status,
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        rw.header,
+		ContentLength: int64(rw.body.Len()),
+		Body:          http.NoBody,
+	}
+	if rw.body.Len() > 0 {
+		resp.Body = io.NopCloser(bytes.NewReader(rw.body.Bytes()))
+	}
+
+	var out bytes.Buffer
+	err := resp.Write(&out)
+	return out.Bytes(), err
+}
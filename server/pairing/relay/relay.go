@@ -0,0 +1,298 @@
+// Package relay tunnels local pairing HTTP requests through a rendezvous relay, for devices that
+// can't reach each other over the local network.
+//
+// The device running the pairing server polls the relay for requests addressed to its rendezvous
+// and posts back the responses, the device running the pairing client uses a Transport that posts
+// requests and polls for their responses. Requests and responses are sealed with the AES key shared
+// through the connection string and bound to their request id, the relay only sees opaque messages.
+package relay
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"net"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	rendezvousPath  = "/rendezvous/"
+	requestsPath    = "requests"
+	responsesPath   = "responses"
+	requestIDHeader = "X-Relay-Request-Id"
+
+	// maxMessageSize limits the size of relayed messages, pairing payloads are sent in chunks
+	maxMessageSize = 4 * 1024 * 1024
+	// maxPendingRequests limits the number of requests waiting to be polled or answered per rendezvous
+	maxPendingRequests = 64
+	// maxMailboxes limits the number of rendezvous served at once, and maxMailboxesPerIP the number
+	// of them opened from the same address
+	maxMailboxes       = 1024
+	maxMailboxesPerIP  = 8
+	defaultPollTimeout = 25 * time.Second
+	mailboxTTL         = 10 * time.Minute
+)
+
+var (
+	rendezvousPattern = regexp.MustCompile(`^[0-9a-f]{64}$`)
+	requestIDPattern  = regexp.MustCompile(`^[0-9a-zA-Z-]{1,64}$`)
+)
+
+// Rendezvous derives the rendezvous identifier of a pairing session from its AES key
+func Rendezvous(aesKey []byte) string {
+	hash := sha256.Sum256(append([]byte("status-pairing-relay"), aesKey...))
+	return hex.EncodeToString(hash[:])
+}
+
+type message struct {
+	id   string
+	data []byte
+}
+
+type mailbox struct {
+	requests chan message
+	// remoteIP is the address the mailbox was opened from
+	remoteIP string
+
+	mu sync.Mutex
+	// responses holds a channel per queued request, responses to other requests are rejected
+	responses map[string]chan []byte
+	lastSeen  time.Time
+}
+
+func newMailbox(remoteIP string) *mailbox {
+	return &mailbox{
+		requests:  make(chan message, maxPendingRequests),
+		remoteIP:  remoteIP,
+		responses: make(map[string]chan []byte),
+		lastSeen:  time.Now(),
+	}
+}
+
+// addRequest registers a request waiting for its response, it returns false if the request
+// is already pending or too many are
+func (m *mailbox) addRequest(requestID string) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if _, ok := m.responses[requestID]; ok || len(m.responses) >= maxPendingRequests {
+		return false
+	}
+	m.responses[requestID] = make(chan []byte, 1)
+	return true
+}
+
+// response returns the channel of the response to the given request, nil if the request is unknown
+func (m *mailbox) response(requestID string) chan []byte {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.responses[requestID]
+}
+
+func (m *mailbox) removeResponse(requestID string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	delete(m.responses, requestID)
+}
+
+func (m *mailbox) touch() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.lastSeen = time.Now()
+}
+
+func (m *mailbox) idleSince(t time.Time) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.lastSeen.Before(t)
+}
+
+// Relay is the http.Handler of a rendezvous relay, it keeps messages in memory until they're polled
+type Relay struct {
+	logger      *zap.Logger
+	pollTimeout time.Duration
+
+	mu        sync.Mutex
+	mailboxes map[string]*mailbox
+}
+
+func NewRelay(logger *zap.Logger) *Relay {
+	return &Relay{
+		logger:      logger,
+		pollTimeout: defaultPollTimeout,
+		mailboxes:   make(map[string]*mailbox),
+	}
+}
+
+// mailbox returns the mailbox of a rendezvous, dropping the mailboxes that haven't been used recently.
+// A missing mailbox is only opened if create is set and neither the relay nor the remote address
+// have reached their limit, nil is returned otherwise.
+func (r *Relay) mailbox(rendezvous string, remoteIP string, create bool) *mailbox {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	expired := time.Now().Add(-mailboxTTL)
+	for id, m := range r.mailboxes {
+		if m.idleSince(expired) {
+			delete(r.mailboxes, id)
+		}
+	}
+
+	m, ok := r.mailboxes[rendezvous]
+	if !ok {
+		if !create || len(r.mailboxes) >= maxMailboxes || r.mailboxCount(remoteIP) >= maxMailboxesPerIP {
+			return nil
+		}
+		m = newMailbox(remoteIP)
+		r.mailboxes[rendezvous] = m
+	}
+	m.touch()
+	return m
+}
+
+func (r *Relay) mailboxCount(remoteIP string) int {
+	count := 0
+	for _, m := range r.mailboxes {
+		if m.remoteIP == remoteIP {
+			count++
+		}
+	}
+	return count
+}
+
+func remoteIP(req *http.Request) string {
+	host, _, err := net.SplitHostPort(req.RemoteAddr)
+	if err != nil {
+		return req.RemoteAddr
+	}
+	return host
+}
+
+// ServeHTTP handles:
+//   - POST /rendezvous/{id}/requests, queues a request
+//   - GET /rendezvous/{id}/requests, polls the next request
+//   - POST /rendezvous/{id}/responses, stores the response of a request
+//   - GET /rendezvous/{id}/responses/{requestID}, polls the response of a request
+func (r *Relay) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	parts := strings.Split(strings.TrimPrefix(req.URL.Path, rendezvousPath), "/")
+	if !strings.HasPrefix(req.URL.Path, rendezvousPath) || len(parts) < 2 || !rendezvousPattern.MatchString(parts[0]) {
+		http.NotFound(w, req)
+		return
+	}
+
+	// Only the requests path opens mailboxes, the listener polls it before the client posts anything
+	m := r.mailbox(parts[0], remoteIP(req), parts[1] == requestsPath)
+	if m == nil {
+		if parts[1] == requestsPath {
+			http.Error(w, "too many rendezvous", http.StatusServiceUnavailable)
+			return
+		}
+		http.NotFound(w, req)
+		return
+	}
+
+	switch {
+	case parts[1] == requestsPath && len(parts) == 2 && req.Method == http.MethodPost:
+		r.queueRequest(w, req, m)
+	case parts[1] == requestsPath && len(parts) == 2 && req.Method == http.MethodGet:
+		r.pollRequest(w, req, m)
+	case parts[1] == responsesPath && len(parts) == 2 && req.Method == http.MethodPost:
+		r.storeResponse(w, req, m)
+	case parts[1] == responsesPath && len(parts) == 3 && req.Method == http.MethodGet && requestIDPattern.MatchString(parts[2]):
+		r.pollResponse(w, req, m, parts[2])
+	default:
+		http.NotFound(w, req)
+	}
+}
+
+func readMessage(w http.ResponseWriter, req *http.Request) (*message, bool) {
+	requestID := req.Header.Get(requestIDHeader)
+	if !requestIDPattern.MatchString(requestID) {
+		http.Error(w, "invalid request id", http.StatusBadRequest)
+		return nil, false
+	}
+
+	data, err := io.ReadAll(http.MaxBytesReader(w, req.Body, maxMessageSize))
+	if err != nil {
+		http.Error(w, "message too large", http.StatusRequestEntityTooLarge)
+		return nil, false
+	}
+
+	return &message{id: requestID, data: data}, true
+}
+
+func writeMessage(w http.ResponseWriter, msg message) {
+	w.Header().Set(requestIDHeader, msg.id)
+	w.Header().Set("Content-Type", "application/octet-stream")
+	_, _ = w.Write(msg.data)
+}
+
+func (r *Relay) queueRequest(w http.ResponseWriter, req *http.Request, m *mailbox) {
+	msg, ok := readMessage(w, req)
+	if !ok {
+		return
+	}
+
+	if !m.addRequest(msg.id) {
+		http.Error(w, "request already pending or too many pending requests", http.StatusConflict)
+		return
+	}
+
+	select {
+	case m.requests <- *msg:
+	default:
+		m.removeResponse(msg.id)
+		http.Error(w, "too many pending requests", http.StatusServiceUnavailable)
+	}
+}
+
+func (r *Relay) pollRequest(w http.ResponseWriter, req *http.Request, m *mailbox) {
+	select {
+	case msg := <-m.requests:
+		writeMessage(w, msg)
+	case <-time.After(r.pollTimeout):
+		w.WriteHeader(http.StatusNoContent)
+	case <-req.Context().Done():
+	}
+}
+
+func (r *Relay) storeResponse(w http.ResponseWriter, req *http.Request, m *mailbox) {
+	msg, ok := readMessage(w, req)
+	if !ok {
+		return
+	}
+
+	ch := m.response(msg.id)
+	if ch == nil {
+		http.Error(w, "unknown request", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case ch <- msg.data:
+	default:
+		http.Error(w, "response already stored", http.StatusConflict)
+	}
+}
+
+func (r *Relay) pollResponse(w http.ResponseWriter, req *http.Request, m *mailbox, requestID string) {
+	ch := m.response(requestID)
+	if ch == nil {
+		http.Error(w, "unknown request", http.StatusNotFound)
+		return
+	}
+
+	select {
+	case data := <-ch:
+		m.removeResponse(requestID)
+		writeMessage(w, message{id: requestID, data: data})
+	case <-time.After(r.pollTimeout):
+		w.WriteHeader(http.StatusNoContent)
+	case <-req.Context().Done():
+	}
+}
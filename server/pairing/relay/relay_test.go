@@ -0,0 +1,201 @@
+package relay
+
+import (
+	"context"
+	"crypto/rand"
+	"io"
+	"net/http"
+	"net/http/cookiejar"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func newTestKey(t *testing.T) []byte {
+	key := make([]byte, 32)
+	_, err := rand.Read(key)
+	require.NoError(t, err)
+	return key
+}
+
+func TestRelay_RoundTrip(t *testing.T) {
+	logger := zap.NewNop()
+	relay := httptest.NewServer(NewRelay(logger))
+	defer relay.Close()
+
+	aesKey := newTestKey(t)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/pairing/echo", func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, RemoteAddr, r.RemoteAddr)
+
+		body, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+
+		http.SetCookie(w, &http.Cookie{Name: "session", Value: "1"})
+		if cookie, err := r.Cookie("session"); err == nil {
+			w.Header().Set("X-Session", cookie.Value)
+		}
+		_, _ = w.Write(append([]byte("echo:"), body...))
+	})
+
+	listener, err := NewListener(relay.URL, aesKey, mux, time.Minute, logger)
+	require.NoError(t, err)
+	listener.Start()
+	defer listener.Stop()
+
+	transport, err := NewTransport(relay.URL, aesKey)
+	require.NoError(t, err)
+	jar, err := cookiejar.New(nil)
+	require.NoError(t, err)
+	client := &http.Client{Transport: transport, Jar: jar}
+
+	for i, expectedSession := range []string{"", "1"} {
+		resp, err := client.Post("https://relay/pairing/echo", "text/plain", strings.NewReader("hello"))
+		require.NoError(t, err, "request %d", i)
+		require.Equal(t, http.StatusOK, resp.StatusCode)
+		require.Equal(t, expectedSession, resp.Header.Get("X-Session"))
+
+		body, err := io.ReadAll(resp.Body)
+		require.NoError(t, err)
+		require.Equal(t, "echo:hello", string(body))
+		require.NoError(t, resp.Body.Close())
+	}
+
+	resp, err := client.Get("https://relay/pairing/unknown")
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRelay_Mailboxes(t *testing.T) {
+	relay := NewRelay(zap.NewNop())
+	relay.pollTimeout = 10 * time.Millisecond
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	rendezvous := Rendezvous(newTestKey(t))
+	require.Len(t, rendezvous, 64)
+
+	// Nothing to poll yet
+	resp, err := http.Get(server.URL + rendezvousPath + rendezvous + "/" + requestsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+
+	// Requests without an id are rejected
+	resp, err = http.Post(server.URL+rendezvousPath+rendezvous+"/"+requestsPath, "application/octet-stream", strings.NewReader("data"))
+	require.NoError(t, err)
+	require.Equal(t, http.StatusBadRequest, resp.StatusCode)
+
+	// Unknown rendezvous format
+	resp, err = http.Get(server.URL + rendezvousPath + "not-a-rendezvous/" + requestsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestRelay_WrongKey(t *testing.T) {
+	logger := zap.NewNop()
+	relay := httptest.NewServer(NewRelay(logger))
+	defer relay.Close()
+
+	aesKey := newTestKey(t)
+	listenerEndpoint, err := newEndpoint(relay.URL, aesKey)
+	require.NoError(t, err)
+
+	// A client with another key posts to a different rendezvous
+	otherKey := newTestKey(t)
+	other, err := newEndpoint(relay.URL, otherKey)
+	require.NoError(t, err)
+	require.NotEqual(t, listenerEndpoint.rendezvous, other.rendezvous)
+
+	// A message sealed with another key can't be opened on the right rendezvous
+	other.rendezvous = listenerEndpoint.rendezvous
+	require.NoError(t, other.post(context.Background(), requestsPath, "1", []byte("GET / HTTP/1.1\r\n\r\n")))
+	_, err = listenerEndpoint.poll(context.Background(), requestsPath, "")
+	require.Error(t, err)
+}
+
+func TestRelay_UnknownRequestID(t *testing.T) {
+	relay := NewRelay(zap.NewNop())
+	relay.pollTimeout = 10 * time.Millisecond
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	e, err := newEndpoint(server.URL, newTestKey(t))
+	require.NoError(t, err)
+
+	// No mailbox is opened by the responses path
+	_, err = e.poll(context.Background(), responsesPath, "1")
+	require.Error(t, err)
+
+	require.NoError(t, e.post(context.Background(), requestsPath, "1", []byte("request")))
+
+	// Responses are only accepted for queued requests
+	require.Error(t, e.post(context.Background(), responsesPath, "2", []byte("response")))
+	_, err = e.poll(context.Background(), responsesPath, "2")
+	require.Error(t, err)
+
+	// The same request can't be queued twice
+	require.Error(t, e.post(context.Background(), requestsPath, "1", []byte("request")))
+
+	require.NoError(t, e.post(context.Background(), responsesPath, "1", []byte("response")))
+	msg, err := e.poll(context.Background(), responsesPath, "1")
+	require.NoError(t, err)
+	require.Equal(t, "response", string(msg.data))
+}
+
+func TestRelay_SwappedRequestID(t *testing.T) {
+	relay := NewRelay(zap.NewNop())
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	e, err := newEndpoint(server.URL, newTestKey(t))
+	require.NoError(t, err)
+
+	// A response sealed for a request can't be opened as the response of another one
+	sealed, err := e.seal(responsesPath, "1", []byte("response"))
+	require.NoError(t, err)
+	_, err = e.open(responsesPath, "2", sealed)
+	require.Error(t, err)
+
+	// Nor replayed as a request
+	_, err = e.open(requestsPath, "1", sealed)
+	require.Error(t, err)
+
+	data, err := e.open(responsesPath, "1", sealed)
+	require.NoError(t, err)
+	require.Equal(t, "response", string(data))
+}
+
+func TestRelay_MailboxLimits(t *testing.T) {
+	relay := NewRelay(zap.NewNop())
+	relay.pollTimeout = 10 * time.Millisecond
+	server := httptest.NewServer(relay)
+	defer server.Close()
+
+	for i := 0; i < maxMailboxesPerIP; i++ {
+		resp, err := http.Get(server.URL + rendezvousPath + Rendezvous(newTestKey(t)) + "/" + requestsPath)
+		require.NoError(t, err)
+		require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	}
+
+	// The address has opened as many mailboxes as it may
+	resp, err := http.Get(server.URL + rendezvousPath + Rendezvous(newTestKey(t)) + "/" + requestsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusServiceUnavailable, resp.StatusCode)
+
+	// Idle mailboxes expire
+	relay.mu.Lock()
+	for _, m := range relay.mailboxes {
+		m.lastSeen = time.Now().Add(-2 * mailboxTTL)
+	}
+	relay.mu.Unlock()
+
+	resp, err = http.Get(server.URL + rendezvousPath + Rendezvous(newTestKey(t)) + "/" + requestsPath)
+	require.NoError(t, err)
+	require.Equal(t, http.StatusNoContent, resp.StatusCode)
+	require.Len(t, relay.mailboxes, 1)
+}
@@ -0,0 +1,211 @@
+package relay
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const responseTimeout = 2 * time.Minute
+
+var (
+	ErrResponseTimeout   = errors.New("relay: timed out waiting for the response")
+	ErrInvalidCiphertext = errors.New("relay: invalid ciphertext")
+)
+
+// endpoint addresses the mailbox of a rendezvous on a relay
+type endpoint struct {
+	client     *http.Client
+	relayURL   *url.URL
+	rendezvous string
+	aesKey     []byte
+}
+
+func newEndpoint(relayURL string, aesKey []byte) (*endpoint, error) {
+	u, err := url.Parse(relayURL)
+	if err != nil {
+		return nil, err
+	}
+	if u.Scheme != "https" && u.Scheme != "http" {
+		return nil, fmt.Errorf("relay: unsupported scheme '%s'", u.Scheme)
+	}
+
+	return &endpoint{
+		client:     &http.Client{Timeout: defaultPollTimeout + 10*time.Second},
+		relayURL:   u,
+		rendezvous: Rendezvous(aesKey),
+		aesKey:     aesKey,
+	}, nil
+}
+
+func (e *endpoint) url(elem ...string) string {
+	return e.relayURL.JoinPath(append([]string{rendezvousPath, e.rendezvous}, elem...)...).String()
+}
+
+// additionalData binds a sealed message to its rendezvous, mailbox path and request id,
+// the relay can't swap the responses of two requests or replay a message on another path
+func (e *endpoint) additionalData(path string, requestID string) []byte {
+	return []byte(e.rendezvous + "/" + path + "/" + requestID)
+}
+
+func (e *endpoint) aead() (cipher.AEAD, error) {
+	c, err := aes.NewCipher(e.aesKey)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(c)
+}
+
+func (e *endpoint) seal(path string, requestID string, data []byte) ([]byte, error) {
+	gcm, err := e.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err = io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, err
+	}
+
+	return gcm.Seal(nonce, nonce, data, e.additionalData(path, requestID)), nil
+}
+
+func (e *endpoint) open(path string, requestID string, sealed []byte) ([]byte, error) {
+	gcm, err := e.aead()
+	if err != nil {
+		return nil, err
+	}
+
+	if len(sealed) < gcm.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+
+	nonce := sealed[:gcm.NonceSize()]
+	return gcm.Open(nil, nonce, sealed[gcm.NonceSize():], e.additionalData(path, requestID))
+}
+
+// post seals data and posts it to the given mailbox path
+func (e *endpoint) post(ctx context.Context, path string, requestID string, data []byte) error {
+	sealed, err := e.seal(path, requestID, data)
+	if err != nil {
+		return err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, e.url(path), bytes.NewReader(sealed))
+	if err != nil {
+		return err
+	}
+	req.Header.Set(requestIDHeader, requestID)
+	req.Header.Set("Content-Type", "application/octet-stream")
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return fmt.Errorf("relay: status not ok when posting to '%s', received '%s'", path, resp.Status)
+	}
+	return nil
+}
+
+// poll waits for a message on the given mailbox path, it returns a nil message if none arrived before the relay poll timeout.
+// Requests are polled with an empty requestID, the id of the message is then taken from the relay.
+func (e *endpoint) poll(ctx context.Context, path string, requestID string) (*message, error) {
+	elem := []string{path}
+	if requestID != "" {
+		elem = append(elem, requestID)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, e.url(elem...), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := e.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusNoContent:
+		return nil, nil
+	case http.StatusOK:
+	default:
+		return nil, fmt.Errorf("relay: status not ok when polling, received '%s'", resp.Status)
+	}
+
+	sealed, err := io.ReadAll(io.LimitReader(resp.Body, maxMessageSize))
+	if err != nil {
+		return nil, err
+	}
+
+	if requestID == "" {
+		requestID = resp.Header.Get(requestIDHeader)
+	}
+
+	data, err := e.open(path, requestID, sealed)
+	if err != nil {
+		return nil, err
+	}
+
+	return &message{id: requestID, data: data}, nil
+}
+
+// Transport is an http.RoundTripper sending requests to a pairing server through a relay
+type Transport struct {
+	endpoint *endpoint
+}
+
+func NewTransport(relayURL string, aesKey []byte) (*Transport, error) {
+	e, err := newEndpoint(relayURL, aesKey)
+	if err != nil {
+		return nil, err
+	}
+	return &Transport{endpoint: e}, nil
+}
+
+func (t *Transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	dump, err := httputil.DumpRequestOut(req, true)
+	if err != nil {
+		return nil, err
+	}
+
+	ctx, cancel := context.WithTimeout(req.Context(), responseTimeout)
+	defer cancel()
+
+	requestID := uuid.New().String()
+	err = t.endpoint.post(ctx, requestsPath, requestID, dump)
+	if err != nil {
+		return nil, err
+	}
+
+	for {
+		msg, err := t.endpoint.poll(ctx, responsesPath, requestID)
+		if ctx.Err() != nil {
+			return nil, ErrResponseTimeout
+		}
+		if err != nil {
+			return nil, err
+		}
+		if msg == nil {
+			continue
+		}
+
+		return http.ReadResponse(bufio.NewReader(bytes.NewReader(msg.data)), req)
+	}
+}
@@ -6,6 +6,7 @@ import (
 	"crypto/rand"
 	"encoding/json"
 	"net"
+	"net/http"
 	"runtime"
 	"time"
 
@@ -16,6 +17,8 @@ import (
 	"github.com/status-im/status-go/api"
 	"github.com/status-im/status-go/logutils"
 	"github.com/status-im/status-go/server"
+	"github.com/status-im/status-go/server/pairing/relay"
+	"github.com/status-im/status-go/server/pairing/statecontrol"
 )
 
 /*
@@ -30,6 +33,8 @@ import (
 type BaseServer struct {
 	server.Server
 	challengeGiver *ChallengeGiver
+	encryptor      *PayloadEncryptor
+	relayListener  *relay.Listener
 
 	config ServerConfig
 }
@@ -49,6 +54,7 @@ func NewBaseServer(logger *zap.Logger, e *PayloadEncryptor, config *ServerConfig
 			logger,
 		),
 		challengeGiver: cg,
+		encryptor:      e,
 		config:         *config,
 	}
 	bs.SetTimeout(config.Timeout)
@@ -57,7 +63,42 @@ func NewBaseServer(logger *zap.Logger, e *PayloadEncryptor, config *ServerConfig
 
 // MakeConnectionParams generates a *ConnectionParams based on the Server's current state
 func (s *BaseServer) MakeConnectionParams() (*ConnectionParams, error) {
-	return NewConnectionParams(s.config.IPAddresses, s.MustGetPort(), s.config.PK, s.config.EK, s.config.InstallationID, s.config.KeyUID), nil
+	cp := NewConnectionParams(s.config.IPAddresses, s.MustGetPort(), s.config.PK, s.config.EK, s.config.InstallationID, s.config.KeyUID)
+	cp.SetRelayURL(s.config.RelayURL)
+	return cp, nil
+}
+
+// startWithHandlers starts the server with the given handlers and, if a relay is configured,
+// serves the same handlers to the requests tunneled through the relay
+func (s *BaseServer) startWithHandlers(handlers server.HandlerPatternMap) error {
+	s.SetHandlers(handlers)
+
+	if s.config.RelayURL != "" {
+		mux := http.NewServeMux()
+		for p, h := range handlers {
+			mux.HandleFunc(p, h)
+		}
+
+		l, err := relay.NewListener(s.config.RelayURL, s.config.EK, mux, time.Duration(s.config.Timeout)*time.Millisecond, s.GetLogger().Named("RelayListener"))
+		if err != nil {
+			return err
+		}
+		if s.relayListener != nil {
+			s.relayListener.Stop()
+		}
+		s.relayListener = l
+		s.relayListener.Start()
+	}
+
+	return s.Start()
+}
+
+// Stop stops the server and the relay listener
+func (s *BaseServer) Stop() error {
+	if s.relayListener != nil {
+		s.relayListener.Stop()
+	}
+	return s.Server.Stop()
 }
 
 func MakeServerConfig(config *ServerConfig) error {
@@ -147,17 +188,20 @@ func (s *SenderServer) startSendingData() error {
 			}
 		}
 	}
-	s.SetHandlers(server.HandlerPatternMap{
-		pairingChallenge:      handlePairingChallenge(s.challengeGiver),
-		pairingSendAccount:    middlewareChallenge(s.challengeGiver, handleSendAccount(logger, s.accountMounter, beforeSending)),
-		pairingSendSyncDevice: middlewareChallenge(s.challengeGiver, handlePairingSyncDeviceSend(logger, s.rawMessageMounter, beforeSending)),
+	return s.startWithHandlers(server.HandlerPatternMap{
+		pairingChallenge: handlePairingChallenge(s.challengeGiver),
+		pairingVersion:   handlePairingVersion(),
+		pairingSendAccount: middlewareChallenge(s.challengeGiver, handleChunkedSend(logger, ActionPairingAccount, s.accountMounter, s.encryptor, beforeSending,
+			handleSendAccount(logger, s.accountMounter, beforeSending))),
+		pairingSendSyncDevice: middlewareChallenge(s.challengeGiver, handleChunkedSend(logger, ActionSyncDevice, s.rawMessageMounter, s.encryptor, beforeSending,
+			handlePairingSyncDeviceSend(logger, s.rawMessageMounter, beforeSending))),
 		// TODO implement refactor of installation data exchange to follow the send/receive pattern of
 		//  the other handlers.
 		//  https://github.com/status-im/status-go/issues/3304
 		// receive installation data from receiver
-		pairingReceiveInstallation: middlewareChallenge(s.challengeGiver, handleReceiveInstallation(s.GetLogger(), s.installationMounter)),
+		pairingReceiveInstallation: middlewareChallenge(s.challengeGiver, handleChunkedReceive(logger, ActionPairingInstallation, s.installationMounter, s.encryptor, new(statecontrol.ProcessStateManager),
+			handleReceiveInstallation(s.GetLogger(), s.installationMounter))),
 	})
-	return s.Start()
 }
 
 // MakeFullSenderServer generates a fully configured and randomly seeded SenderServer
@@ -257,17 +301,22 @@ func (s *ReceiverServer) startReceivingData() error {
 			}
 		}
 	}
-	s.SetHandlers(server.HandlerPatternMap{
-		pairingChallenge:         handlePairingChallenge(s.challengeGiver),
-		pairingReceiveAccount:    handleReceiveAccount(logger, s.accountReceiver),
-		pairingReceiveSyncDevice: handleParingSyncDeviceReceive(logger, s.rawMessageReceiver),
+	// transfers are encrypted with the keys of this server, they can't be resumed by another one
+	transfers := new(statecontrol.ProcessStateManager)
+	return s.startWithHandlers(server.HandlerPatternMap{
+		pairingChallenge: handlePairingChallenge(s.challengeGiver),
+		pairingVersion:   handlePairingVersion(),
+		pairingReceiveAccount: handleChunkedReceive(logger, ActionPairingAccount, s.accountReceiver, s.encryptor, transfers,
+			handleReceiveAccount(logger, s.accountReceiver)),
+		pairingReceiveSyncDevice: handleChunkedReceive(logger, ActionSyncDevice, s.rawMessageReceiver, s.encryptor, transfers,
+			handleParingSyncDeviceReceive(logger, s.rawMessageReceiver)),
 		// TODO implement refactor of installation data exchange to follow the send/receive pattern of
 		//  the other handlers.
 		//  https://github.com/status-im/status-go/issues/3304
 		// send installation data back to sender
-		pairingSendInstallation: middlewareChallenge(s.challengeGiver, handleSendInstallation(logger, s.installationReceiver, beforeSending)),
+		pairingSendInstallation: middlewareChallenge(s.challengeGiver, handleChunkedSend(logger, ActionPairingInstallation, s.installationReceiver, s.encryptor, beforeSending,
+			handleSendInstallation(logger, s.installationReceiver, beforeSending))),
 	})
-	return s.Start()
 }
 
 // MakeFullReceiverServer generates a fully configured and randomly seeded ReceiverServer
@@ -369,11 +418,10 @@ func (s *KeystoreFilesSenderServer) startSendingData() error {
 			}
 		}
 	}
-	s.SetHandlers(server.HandlerPatternMap{
+	return s.startWithHandlers(server.HandlerPatternMap{
 		pairingChallenge:   handlePairingChallenge(s.challengeGiver),
 		pairingSendAccount: middlewareChallenge(s.challengeGiver, handleSendAccount(logger, s.keystoreFilesMounter, beforeSending)),
 	})
-	return s.Start()
 }
 
 // MakeFullSenderServer generates a fully configured and randomly seeded KeystoreFilesSenderServer
@@ -22,6 +22,10 @@ type ProcessStateManager struct {
 	// sessions represents a map[string]bool:
 	// where string is a ConnectionParams string and bool is the transfer success state of that connection string
 	sessions sync.Map
+
+	// transfers represents a map[string]*TransferState:
+	// where string is the content address of a chunked payload and *TransferState its acknowledged chunks
+	transfers sync.Map
 }
 
 // IsPairing returns if the ProcessStateManager is currently in pairing mode
@@ -91,3 +95,19 @@ func (psm *ProcessStateManager) StopPairing(sessionName string, err error) {
 	}
 	psm.SetPairing(false)
 }
+
+// Transfer returns the TransferState of the chunked payload with the given transferID,
+// a new TransferState is registered if the transfer is unknown or has a different number of chunks.
+func (psm *ProcessStateManager) Transfer(transferID string, total int) *TransferState {
+	ts, loaded := psm.transfers.LoadOrStore(transferID, newTransferState(total))
+	if loaded && ts.(*TransferState).Total() != total {
+		ts = newTransferState(total)
+		psm.transfers.Store(transferID, ts)
+	}
+	return ts.(*TransferState)
+}
+
+// RemoveTransfer drops the TransferState of the given transferID once the payload has been processed
+func (psm *ProcessStateManager) RemoveTransfer(transferID string) {
+	psm.transfers.Delete(transferID)
+}
@@ -56,3 +56,32 @@ func TestProcessStateManager_StartPairing(t *testing.T) {
 	err = psm.StartPairing(cs2)
 	require.EqualError(t, err, ErrProcessStateManagerAlreadyPaired(cs2).Error())
 }
+
+func TestProcessStateManager_Transfer(t *testing.T) {
+	psm := new(ProcessStateManager)
+
+	ts := psm.Transfer("transfer", 3)
+	require.False(t, ts.Complete())
+	require.Nil(t, ts.Assemble())
+
+	ts.Ack(2, []byte("c"))
+	ts.Ack(0, []byte("a"))
+	ts.Ack(3, []byte("out of range"))
+	require.Equal(t, []int{0, 2}, ts.Acked())
+	require.False(t, ts.IsAcked(1))
+
+	// A resumed transfer keeps the acknowledged chunks
+	ts = psm.Transfer("transfer", 3)
+	require.Equal(t, []int{0, 2}, ts.Acked())
+
+	ts.Ack(1, []byte("b"))
+	require.True(t, ts.Complete())
+	require.Equal(t, []byte("abc"), ts.Assemble())
+
+	// A transfer with the same id but a different number of chunks starts over
+	ts = psm.Transfer("transfer", 2)
+	require.Empty(t, ts.Acked())
+
+	psm.RemoveTransfer("transfer")
+	require.Empty(t, psm.Transfer("transfer", 2).Acked())
+}
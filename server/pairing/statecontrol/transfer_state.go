@@ -0,0 +1,79 @@
+package statecontrol
+
+import (
+	"sort"
+	"sync"
+)
+
+// TransferState keeps the acknowledged chunks of a chunked payload transfer, allowing an interrupted
+// transfer to be resumed from the last acknowledged chunk. It is kept in memory only: payloads are
+// encrypted with the keys of a pairing session, so a transfer can't be resumed by another session.
+type TransferState struct {
+	mu     sync.Mutex
+	total  int
+	chunks map[int][]byte
+}
+
+func newTransferState(total int) *TransferState {
+	return &TransferState{
+		total:  total,
+		chunks: make(map[int][]byte),
+	}
+}
+
+// Ack records a chunk as successfully transferred
+func (ts *TransferState) Ack(index int, chunk []byte) {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if index < 0 || index >= ts.total {
+		return
+	}
+	ts.chunks[index] = chunk
+}
+
+// IsAcked returns if the chunk at the given index has been transferred
+func (ts *TransferState) IsAcked(index int) bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	_, ok := ts.chunks[index]
+	return ok
+}
+
+// Acked returns the sorted indexes of the transferred chunks
+func (ts *TransferState) Acked() []int {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	indexes := make([]int, 0, len(ts.chunks))
+	for index := range ts.chunks {
+		indexes = append(indexes, index)
+	}
+	sort.Ints(indexes)
+	return indexes
+}
+
+// Total returns the number of chunks of the transfer
+func (ts *TransferState) Total() int {
+	return ts.total
+}
+
+// Complete returns if all the chunks of the transfer have been acknowledged
+func (ts *TransferState) Complete() bool {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	return len(ts.chunks) == ts.total
+}
+
+// Assemble concatenates the chunks in order, it returns nil if the transfer isn't complete
+func (ts *TransferState) Assemble() []byte {
+	ts.mu.Lock()
+	defer ts.mu.Unlock()
+	if len(ts.chunks) != ts.total {
+		return nil
+	}
+
+	var payload []byte
+	for i := 0; i < ts.total; i++ {
+		payload = append(payload, ts.chunks[i]...)
+	}
+	return payload
+}
@@ -11,9 +11,11 @@ type LocalPairingVersion int
 
 const (
 	LocalPairingV1 LocalPairingVersion = iota + 1
+	// LocalPairingV2 transfers payloads in chunks that are acknowledged one by one and can be resumed
+	LocalPairingV2
 )
 
 const (
 	LatestConnectionParamVer = ConnectionParamsV2
-	LatestLocalPairingVer    = LocalPairingV1
+	LatestLocalPairingVer    = LocalPairingV2
 )
@@ -25,6 +25,14 @@ func WithMediaServerDisableTLS(disableTLS bool) MediaServerOption {
 	}
 }
 
+// WithMediaServerTransformCache keeps up to maxSize bytes of transformed variants of images in memory,
+// 0 being the default size
+func WithMediaServerTransformCache(maxSize int64) MediaServerOption {
+	return func(s *MediaServer) {
+		s.transformCacheMaxSize = maxSize
+	}
+}
+
 type MediaServer struct {
 	Server
 
@@ -41,6 +49,9 @@ type MediaServer struct {
 	// on Android, which has limitations with dynamic certificate updates.
 	// Pls check doc/use-status-backend-server.md in status-mobile for more details
 	disableTLS bool
+
+	transformCacheMaxSize int64
+	transformCache        *imageTransformCache
 }
 
 func initMediaCertificate(disableTLS bool) (*tls.Certificate, error) {
@@ -74,6 +85,11 @@ func NewMediaServer(db *sql.DB, downloader *ipfs.Downloader, multiaccountsDB *mu
 	if err != nil {
 		return nil, err
 	}
+
+	if s.transformCacheMaxSize == 0 {
+		s.transformCacheMaxSize = defaultImageTransformCacheMaxSize
+	}
+	s.transformCache = newImageTransformCache(s.transformCacheMaxSize)
 	s.Server = NewServer(
 		cert,
 		Localhost,
@@ -81,7 +97,7 @@ func NewMediaServer(db *sql.DB, downloader *ipfs.Downloader, multiaccountsDB *mu
 		logutils.ZapLogger().Named("MediaServer"),
 	)
 
-	s.SetHandlers(HandlerPatternMap{
+	handlers := HandlerPatternMap{
 		accountImagesPath:                   handleAccountImages(s.multiaccountsDB, s.logger),
 		accountInitialsPath:                 handleAccountInitials(s.multiaccountsDB, s.logger),
 		audioPath:                           handleAudio(s.db, s.logger),
@@ -89,18 +105,27 @@ func NewMediaServer(db *sql.DB, downloader *ipfs.Downloader, multiaccountsDB *mu
 		discordAttachmentsPath:              handleDiscordAttachment(s.db, s.logger),
 		discordAuthorsPath:                  handleDiscordAuthorAvatar(s.db, s.logger),
 		generateQRCode:                      handleQRCodeGeneration(s.multiaccountsDB, s.logger),
+		filesPath:                           handleFile(s.db, s.logger),
 		imagesPath:                          handleImage(s.db, s.logger),
+		videosPath:                          handleVideo(s.db, s.logger),
+		videoThumbnailsPath:                 handleVideoThumbnail(s.db, s.logger),
 		ipfsPath:                            handleIPFS(s.downloader, s.logger),
 		LinkPreviewThumbnailPath:            handleLinkPreviewThumbnail(s.db, s.logger),
 		LinkPreviewFaviconPath:              handleLinkPreviewFavicon(s.db, s.logger),
 		StatusLinkPreviewThumbnailPath:      handleStatusLinkPreviewThumbnail(s.db, s.logger),
+		RichLinkPreviewImagePath:            handleRichLinkPreviewImage(s.db, s.logger),
 		communityTokenImagesPath:            handleCommunityTokenImages(s.db, s.logger),
 		communityDescriptionImagesPath:      handleCommunityDescriptionImagesPath(s.db, s.getCommunityImage, s.logger),
 		communityDescriptionTokenImagesPath: handleCommunityDescriptionTokenImagesPath(s.db, s.getCommunityTokens, s.logger),
 		walletCommunityImagesPath:           handleWalletCommunityImages(s.walletDB, s.logger),
 		walletCollectionImagesPath:          handleWalletCollectionImages(s.walletDB, s.logger),
 		walletCollectibleImagesPath:         handleWalletCollectibleImages(s.walletDB, s.logger),
-	})
+	}
+	// every image served can be transformed, other responses are left as they are
+	for path, handler := range handlers {
+		handlers[path] = withImageTransform(s.transformCache, s.logger, handler)
+	}
+	s.SetHandlers(handlers)
 
 	return s, nil
 }
@@ -173,6 +198,13 @@ func (s *MediaServer) MakeStatusLinkPreviewThumbnailURL(msgID string, previewURL
 	return u.String()
 }
 
+func (s *MediaServer) MakeRichLinkPreviewImageURL(msgID string, previewURL string) string {
+	u := s.MakeBaseURL()
+	u.Path = RichLinkPreviewImagePath
+	u.RawQuery = url.Values{"message-id": {msgID}, "url": {previewURL}}.Encode()
+	return u.String()
+}
+
 func (s *MediaServer) MakeLinkPreviewFaviconURL(msgID string, previewURL string) string {
 	u := s.MakeBaseURL()
 	u.Path = LinkPreviewFaviconPath
@@ -204,6 +236,30 @@ func (s *MediaServer) MakeAudioURL(id string) string {
 	return u.String()
 }
 
+func (s *MediaServer) MakeFileURL(id string) string {
+	u := s.MakeBaseURL()
+	u.Path = filesPath
+	u.RawQuery = url.Values{"messageId": {id}}.Encode()
+
+	return u.String()
+}
+
+func (s *MediaServer) MakeVideoURL(id string) string {
+	u := s.MakeBaseURL()
+	u.Path = videosPath
+	u.RawQuery = url.Values{"messageId": {id}}.Encode()
+
+	return u.String()
+}
+
+func (s *MediaServer) MakeVideoThumbnailURL(id string) string {
+	u := s.MakeBaseURL()
+	u.Path = videoThumbnailsPath
+	u.RawQuery = url.Values{"messageId": {id}}.Encode()
+
+	return u.String()
+}
+
 func (s *MediaServer) MakeStickerURL(stickerHash string) string {
 	u := s.MakeBaseURL()
 	u.Path = ipfsPath
@@ -238,6 +294,16 @@ func (s *MediaServer) MakeContactImageURL(publicKey string, imageType string) st
 	return u.String()
 }
 
+// MakeAnimatedContactImageURL returns the URL of the animated variant of a contact image,
+// the still variant is served when the image isn't animated
+func (s *MediaServer) MakeAnimatedContactImageURL(publicKey string, imageType string) string {
+	u := s.MakeBaseURL()
+	u.Path = contactImagesPath
+	u.RawQuery = url.Values{"publicKey": {publicKey}, "imageName": {imageType}, "animated": {"1"}}.Encode()
+
+	return u.String()
+}
+
 func (s *MediaServer) MakeCommunityTokenImagesURL(communityID string, chainID uint64, symbol string) string {
 	u := s.MakeBaseURL()
 	u.Path = communityTokenImagesPath
@@ -262,6 +328,21 @@ func (s *MediaServer) MakeCommunityImageURL(communityID, name string) string {
 	return u.String()
 }
 
+// MakeAnimatedCommunityImageURL returns the URL of the animated variant of a community image,
+// the still variant is served when the image isn't animated
+func (s *MediaServer) MakeAnimatedCommunityImageURL(communityID, name string) string {
+	u := s.MakeBaseURL()
+	u.Path = communityDescriptionImagesPath
+	u.RawQuery = url.Values{
+		"communityID": {communityID},
+		"name":        {name},
+		"version":     {fmt.Sprintf("%d", (s.getCommunityImageVersion(communityID)))},
+		"animated":    {"1"},
+	}.Encode()
+
+	return u.String()
+}
+
 func (s *MediaServer) MakeCommunityDescriptionTokenImageURL(communityID, symbol string) string {
 	u := s.MakeBaseURL()
 	u.Path = communityDescriptionTokenImagesPath
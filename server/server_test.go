@@ -127,6 +127,16 @@ func (s *ServerURLSuite) TestServer_MakeStatusLinkPreviewThumbnailURL() {
 		s.serverNoPort.MakeStatusLinkPreviewThumbnailURL("99", "https://status.app", common.MediaServerContactIcon))
 }
 
+func (s *ServerURLSuite) TestServer_MakeRichLinkPreviewImageURL() {
+	s.Require().Equal(
+		baseURLWithCustomPort+"/rich-link-preview/image?message-id=99&url=https%3A%2F%2Fopensea.io",
+		s.server.MakeRichLinkPreviewImageURL("99", "https://opensea.io"))
+
+	s.testNoPort(
+		baseURLWithDefaultPort+"/rich-link-preview/image?message-id=99&url=https%3A%2F%2Fopensea.io",
+		s.serverNoPort.MakeRichLinkPreviewImageURL("99", "https://opensea.io"))
+}
+
 func (s *ServerURLSuite) TestServer_MakeAudioURL() {
 	s.Require().Equal(
 		baseURLWithCustomPort+"/messages/audio?messageId=0xde1e7ebee71e",
@@ -136,6 +146,24 @@ func (s *ServerURLSuite) TestServer_MakeAudioURL() {
 		s.serverNoPort.MakeAudioURL("0xde1e7ebee71e"))
 }
 
+func (s *ServerURLSuite) TestServer_MakeFileURL() {
+	s.Require().Equal(
+		baseURLWithCustomPort+"/messages/files?messageId=0xde1e7ebee71e",
+		s.server.MakeFileURL("0xde1e7ebee71e"))
+	s.testNoPort(
+		baseURLWithDefaultPort+"/messages/files?messageId=0xde1e7ebee71e",
+		s.serverNoPort.MakeFileURL("0xde1e7ebee71e"))
+}
+
+func (s *ServerURLSuite) TestServer_MakeVideoURL() {
+	s.Require().Equal(
+		baseURLWithCustomPort+"/messages/videos?messageId=0xde1e7ebee71e",
+		s.server.MakeVideoURL("0xde1e7ebee71e"))
+	s.testNoPort(
+		baseURLWithDefaultPort+"/messages/videos/thumbnail?messageId=0xde1e7ebee71e",
+		s.serverNoPort.MakeVideoThumbnailURL("0xde1e7ebee71e"))
+}
+
 func (s *ServerURLSuite) TestServer_MakeStickerURL() {
 	s.Require().Equal(
 		baseURLWithCustomPort+"/ipfs?hash=0xdeadbeef4ac0",
@@ -148,3 +148,50 @@ func (api *API) SendAudio(ctx context.Context, communityID types.HexBytes, chatI
 
 	return api.toSendMessageResponse(response)
 }
+
+func (api *API) SendFile(ctx context.Context, communityID types.HexBytes, chatID string, filePath string, responseTo string) (*SendMessageResponse, error) {
+	ensName, _ := api.s.accountsDB.GetPreferredUsername()
+
+	msg := &common.Message{
+		CommunityID: string(communityID.Bytes()),
+		ChatMessage: &protobuf.ChatMessage{
+			ChatId:      chatID,
+			Text:        "Update to latest version to download a file here!",
+			ContentType: protobuf.ChatMessage_FILE,
+			ResponseTo:  responseTo,
+			EnsName:     ensName,
+		},
+		FilePath: filePath,
+	}
+
+	response, err := api.s.messenger.SendChatMessage(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.toSendMessageResponse(response)
+}
+
+func (api *API) SendVideo(ctx context.Context, communityID types.HexBytes, chatID string, videoPath string, thumbnailPath string, responseTo string) (*SendMessageResponse, error) {
+	ensName, _ := api.s.accountsDB.GetPreferredUsername()
+
+	msg := &common.Message{
+		CommunityID: string(communityID.Bytes()),
+		ChatMessage: &protobuf.ChatMessage{
+			ChatId:      chatID,
+			Text:        "Update to latest version to watch a video here!",
+			ContentType: protobuf.ChatMessage_VIDEO,
+			ResponseTo:  responseTo,
+			EnsName:     ensName,
+		},
+		VideoPath:          videoPath,
+		VideoThumbnailPath: thumbnailPath,
+	}
+
+	response, err := api.s.messenger.SendChatMessage(ctx, msg)
+	if err != nil {
+		return nil, err
+	}
+
+	return api.toSendMessageResponse(response)
+}
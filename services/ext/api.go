@@ -32,19 +32,23 @@ import (
 	multiaccountscommon "github.com/status-im/status-go/multiaccounts/common"
 	"github.com/status-im/status-go/multiaccounts/settings"
 	"github.com/status-im/status-go/protocol"
+	"github.com/status-im/status-go/protocol/antispam"
 	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/common/shard"
 	"github.com/status-im/status-go/protocol/communities"
 	"github.com/status-im/status-go/protocol/communities/token"
+	"github.com/status-im/status-go/protocol/contentsafety"
 	"github.com/status-im/status-go/protocol/discord"
 	"github.com/status-im/status-go/protocol/encryption/multidevice"
 	"github.com/status-im/status-go/protocol/identity"
 	"github.com/status-im/status-go/protocol/protobuf"
 	"github.com/status-im/status-go/protocol/pushnotificationclient"
 	"github.com/status-im/status-go/protocol/requests"
+	"github.com/status-im/status-go/protocol/storenodes"
 	"github.com/status-im/status-go/protocol/transport"
 	"github.com/status-im/status-go/protocol/verification"
 	"github.com/status-im/status-go/services/ext/mailservers"
+	"github.com/status-im/status-go/tracing"
 )
 
 const (
@@ -476,6 +480,16 @@ func (api *PublicAPI) GetCommunityStorenodes(id types.HexBytes) (*protocol.Messe
 	return api.service.messenger.GetCommunityStorenodes(id)
 }
 
+// GetStorenodeScores returns the quality scores of the storenodes, best first
+func (api *PublicAPI) GetStorenodeScores() []storenodes.Score {
+	return api.service.messenger.StorenodeScores()
+}
+
+// GetStorenodePreferences returns the primary and backup storenodes picked for the fleet and for the communities
+func (api *PublicAPI) GetStorenodePreferences() []protocol.StorenodePreference {
+	return api.service.messenger.StorenodePreferences()
+}
+
 // ExportCommunity exports the private key of the community with given ID
 func (api *PublicAPI) ExportCommunity(id types.HexBytes) (types.HexBytes, error) {
 	key, err := api.service.messenger.ExportCommunity(id)
@@ -536,6 +550,17 @@ func (api *PublicAPI) ShareCommunity(request *requests.ShareCommunity) (*protoco
 	return api.service.messenger.ShareCommunity(request)
 }
 
+// DownloadFileAttachment fetches the file sent in a message from the store nodes, the message is
+// sent in a messenger response once the file is downloaded
+func (api *PublicAPI) DownloadFileAttachment(messageID string) error {
+	return api.service.messenger.DownloadFileAttachment(messageID)
+}
+
+// SaveFileAttachment decrypts the file sent in a message and writes it to the given path
+func (api *PublicAPI) SaveFileAttachment(messageID string, path string) error {
+	return api.service.messenger.SaveFileAttachment(messageID, path)
+}
+
 // ShareImageMessage share the selected chat image with a set of users
 func (api *PublicAPI) ShareImageMessage(request *requests.ShareImageMessage) (*protocol.MessengerResponse, error) {
 	return api.service.messenger.ShareImageMessage(request)
@@ -561,6 +586,116 @@ func (api *PublicAPI) UnbanUserFromCommunity(request *requests.UnbanUserFromComm
 	return api.service.messenger.UnbanUserFromCommunity(request)
 }
 
+// TimeoutUserFromCommunity prevents the user from posting in the community for the given number of minutes
+func (api *PublicAPI) TimeoutUserFromCommunity(request *requests.TimeoutUserFromCommunity) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.TimeoutUserFromCommunity(request)
+}
+
+// RemoveUserTimeoutFromCommunity lifts the user's timeout in the community
+func (api *PublicAPI) RemoveUserTimeoutFromCommunity(request *requests.RemoveUserTimeoutFromCommunity) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.RemoveUserTimeoutFromCommunity(request)
+}
+
+// SetCommunityChannelSlowMode sets the minimum interval in seconds between two messages of the same member in a channel
+func (api *PublicAPI) SetCommunityChannelSlowMode(request *requests.SetCommunityChannelSlowMode) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.SetCommunityChannelSlowMode(request)
+}
+
+// ReportCommunityMessage reports a community message to the control node and privileged members
+func (api *PublicAPI) ReportCommunityMessage(request *requests.ReportCommunityMessage) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.ReportCommunityMessage(request)
+}
+
+// GetCommunityMessageReports returns the moderation queue of the community, filtered by status if not 0
+func (api *PublicAPI) GetCommunityMessageReports(communityID types.HexBytes, status communities.MessageReportStatus) ([]*communities.MessageReport, error) {
+	return api.service.messenger.GetCommunityMessageReports(communityID, status)
+}
+
+// ResolveCommunityMessageReport dismisses a report or acts on the reported message
+func (api *PublicAPI) ResolveCommunityMessageReport(ctx context.Context, request *requests.ResolveCommunityMessageReport) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.ResolveCommunityMessageReport(ctx, request)
+}
+
+func (api *PublicAPI) GetAntiSpamConfig() antispam.Config {
+	return api.service.messenger.GetAntiSpamConfig()
+}
+
+func (api *PublicAPI) SetAntiSpamConfig(config antispam.Config) error {
+	return api.service.messenger.SetAntiSpamConfig(config)
+}
+
+// GetQuarantinedMessages returns the received community messages flagged as spam
+func (api *PublicAPI) GetQuarantinedMessages(communityID string) ([]*antispam.QuarantinedMessage, error) {
+	return api.service.messenger.GetQuarantinedMessages(communityID)
+}
+
+// ReleaseQuarantinedMessages marks quarantined messages as not spam and adds them to their chats
+func (api *PublicAPI) ReleaseQuarantinedMessages(ids []string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.ReleaseQuarantinedMessages(ids)
+}
+
+func (api *PublicAPI) DeleteQuarantinedMessages(ids []string) error {
+	return api.service.messenger.DeleteQuarantinedMessages(ids)
+}
+
+// WhitelistSpamSender stops anti-spam checks for the sender and releases their quarantined messages
+func (api *PublicAPI) WhitelistSpamSender(senderID string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.WhitelistSpamSender(senderID)
+}
+
+func (api *PublicAPI) RemoveSpamSenderFromWhitelist(senderID string) error {
+	return api.service.messenger.RemoveSpamSenderFromWhitelist(senderID)
+}
+
+func (api *PublicAPI) GetSpamSenderWhitelist() ([]string, error) {
+	return api.service.messenger.GetSpamSenderWhitelist()
+}
+
+func (api *PublicAPI) GetContentSafetyConfig() contentsafety.Config {
+	return api.service.messenger.GetContentSafetyConfig()
+}
+
+func (api *PublicAPI) SetContentSafetyConfig(config contentsafety.Config) error {
+	return api.service.messenger.SetContentSafetyConfig(config)
+}
+
+// GetMediaBlocklist returns the perceptual hashes of the media hidden when received
+func (api *PublicAPI) GetMediaBlocklist() ([]contentsafety.BlockedHash, error) {
+	return api.service.messenger.GetMediaBlocklist()
+}
+
+func (api *PublicAPI) AddToMediaBlocklist(hashes []contentsafety.BlockedHash) error {
+	return api.service.messenger.AddToMediaBlocklist(hashes)
+}
+
+func (api *PublicAPI) RemoveFromMediaBlocklist(hashes []contentsafety.Hash) error {
+	return api.service.messenger.RemoveFromMediaBlocklist(hashes)
+}
+
+// BlockMessageMedia adds the image of a message to the media blocklist
+func (api *PublicAPI) BlockMessageMedia(messageID string, label string) error {
+	return api.service.messenger.BlockMessageMedia(messageID, label)
+}
+
+// GetHiddenMedia returns the received media hidden because it matched a blocklist
+func (api *PublicAPI) GetHiddenMedia(communityID string) ([]*contentsafety.HiddenMedia, error) {
+	return api.service.messenger.GetHiddenMedia(communityID)
+}
+
+func (api *PublicAPI) RevealHiddenMedia(messageID string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.RevealHiddenMedia(messageID)
+}
+
+// SetCommunityMediaBlocklist publishes the perceptual hashes of the media members of the community hide.
+// Only the control node of the community can set them.
+func (api *PublicAPI) SetCommunityMediaBlocklist(request *requests.SetCommunityMediaBlocklist) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.SetCommunityMediaBlocklist(request)
+}
+
+func (api *PublicAPI) GetCommunityMediaBlocklist(communityID types.HexBytes) ([]contentsafety.Hash, error) {
+	return api.service.messenger.GetCommunityMediaBlocklist(communityID)
+}
+
 func (api *PublicAPI) AddRoleToMember(request *requests.AddRoleToMember) (*protocol.MessengerResponse, error) {
 	return api.service.messenger.AddRoleToMember(request)
 }
@@ -898,10 +1033,36 @@ func (api *PublicAPI) UpdateMessageOutgoingStatus(id, newOutgoingStatus string)
 	return api.service.messenger.UpdateMessageOutgoingStatus(id, newOutgoingStatus)
 }
 
-func (api *PublicAPI) SendChatMessage(ctx context.Context, message *common.Message) (*protocol.MessengerResponse, error) {
+func (api *PublicAPI) SendChatMessage(ctx context.Context, message *common.Message) (response *protocol.MessengerResponse, err error) {
+	ctx, span := tracing.Start(ctx, "wakuext_sendChatMessage", tracing.String("chatID", message.ChatId))
+	defer func() { span.EndWithError(err) }()
+
 	return api.service.messenger.SendChatMessage(ctx, message)
 }
 
+// ScheduleChatMessage queues a message to be sent at the requested time
+func (api *PublicAPI) ScheduleChatMessage(ctx context.Context, request *requests.ScheduleMessage) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.ScheduleChatMessage(ctx, request)
+}
+
+// GetScheduledMessages returns the pending scheduled messages of a chat, of all chats if chatID is empty
+func (api *PublicAPI) GetScheduledMessages(chatID string) ([]*protocol.ScheduledMessage, error) {
+	return api.service.messenger.GetScheduledMessages(chatID)
+}
+
+func (api *PublicAPI) EditScheduledMessage(ctx context.Context, request *requests.EditScheduledMessage) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.EditScheduledMessage(ctx, request)
+}
+
+func (api *PublicAPI) CancelScheduledMessage(ctx context.Context, id string) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.CancelScheduledMessage(ctx, id)
+}
+
+// ForwardMessages sends copies of the messages, with their attachments, to the given chats
+func (api *PublicAPI) ForwardMessages(ctx context.Context, request *requests.ForwardMessages) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.ForwardMessages(ctx, request)
+}
+
 func (api *PublicAPI) ReSendChatMessage(ctx context.Context, messageID string) error {
 	return api.service.messenger.ReSendChatMessage(ctx, messageID)
 }
@@ -1395,6 +1556,16 @@ func (api *PublicAPI) FillGaps(chatID string, messageIDs []string) error {
 	return api.service.messenger.FillGaps(chatID, messageIDs)
 }
 
+// RepairChatHistory fetches the history of a chat again from the store nodes and resolves the gaps it covers
+func (api *PublicAPI) RepairChatHistory(request *requests.RepairChatHistory) (*protocol.MessengerResponse, error) {
+	return api.service.messenger.RepairChatHistory(request)
+}
+
+// GetHistoryGaps returns the detected gaps in the history of a chat that are not backfilled yet
+func (api *PublicAPI) GetHistoryGaps(chatID string) ([]*protocol.HistoryGap, error) {
+	return api.service.messenger.GetHistoryGaps(chatID)
+}
+
 func (api *PublicAPI) SyncChatFromSyncedFrom(chatID string) (uint32, error) {
 	return api.service.messenger.SyncChatFromSyncedFrom(chatID)
 }
@@ -41,6 +41,7 @@ import (
 	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/protocol"
 	"github.com/status-im/status-go/protocol/anonmetrics"
+	"github.com/status-im/status-go/protocol/audio"
 	"github.com/status-im/status-go/protocol/common"
 	"github.com/status-im/status-go/protocol/communities"
 	"github.com/status-im/status-go/protocol/communities/token"
@@ -434,6 +435,30 @@ func buildMessengerOptions(
 		options = append(options, protocol.WithDatasync())
 	}
 
+	linkPreviewFetchConfig := protocol.DefaultLinkPreviewFetchConfig()
+	linkPreviewFetchConfig.ProxyURL = config.ShhextConfig.LinkPreviewProxyURL
+	linkPreviewFetchConfig.AllowedDomains = config.ShhextConfig.LinkPreviewAllowedDomains
+	linkPreviewFetchConfig.DeniedDomains = config.ShhextConfig.LinkPreviewDeniedDomains
+	linkPreviewFetchConfig.MastodonInstances = append(linkPreviewFetchConfig.MastodonInstances, config.ShhextConfig.LinkPreviewMastodonInstances...)
+	if config.ShhextConfig.LinkPreviewRelayURL != "" {
+		relay, err := protocol.NewHTTPLinkPreviewRelay(config.ShhextConfig.LinkPreviewRelayURL, nil)
+		if err != nil {
+			return nil, err
+		}
+		linkPreviewFetchConfig.Relay = relay
+	}
+	options = append(options, protocol.WithLinkPreviewFetchConfig(linkPreviewFetchConfig))
+
+	if config.ShhextConfig.AudioTranscoderFFmpegPath != "" {
+		// audios are still received without transcoder, only the clients unable to play AMR miss them
+		transcoder, err := audio.NewFFmpegTranscoder(config.ShhextConfig.AudioTranscoderFFmpegPath)
+		if err != nil {
+			logger.Warn("audio transcoder disabled", zap.Error(err))
+		} else {
+			options = append(options, protocol.WithAudioTranscoder(transcoder))
+		}
+	}
+
 	settings, err := accountsDB.GetSettings()
 	if err != sql.ErrNoRows && err != nil {
 		return nil, err
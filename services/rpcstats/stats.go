@@ -2,8 +2,19 @@ package rpcstats
 
 import (
 	"sync"
+
+	prom "github.com/prometheus/client_golang/prometheus"
 )
 
+var rpcCallsCounter = prom.NewCounterVec(prom.CounterOpts{
+	Name: "rpc_calls_total",
+	Help: "Number of RPC calls made to the blockchain, split by method.",
+}, []string{"method"})
+
+func init() {
+	prom.MustRegister(rpcCallsCounter)
+}
+
 type RPCUsageStats struct {
 	total                  uint
 	counterPerMethod       *sync.Map
@@ -38,6 +49,7 @@ func resetStats() {
 }
 
 func CountCall(method string) {
+	rpcCallsCounter.WithLabelValues(method).Inc()
 	stats := getInstance()
 	stats.total++
 	value, _ := stats.counterPerMethod.LoadOrStore(method, uint(0))
@@ -50,6 +62,7 @@ func CountCallWithTag(method string, tag string) {
 		return
 	}
 
+	rpcCallsCounter.WithLabelValues(method).Inc()
 	stats := getInstance()
 	value, _ := stats.counterPerMethodPerTag.LoadOrStore(tag, &sync.Map{})
 	methodMap := value.(*sync.Map)
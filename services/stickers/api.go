@@ -67,6 +67,8 @@ type StickerPack struct {
 	Preview   string         `json:"preview"`
 	Thumbnail string         `json:"thumbnail"`
 	Stickers  []Sticker      `json:"stickers"`
+	// ContentHash is the IPFS contenthash of the pack data
+	ContentHash string `json:"contentHash,omitempty"`
 
 	Status stickerStatus `json:"status"`
 }
@@ -332,7 +334,8 @@ func (api *API) fetchPackData(stickerType *stickers.StickerType, packID *big.Int
 }
 
 func (api *API) downloadPackData(stickerPack *StickerPack, contentHash []byte, translateHashes bool) error {
-	fileContent, err := api.downloader.Get(hexutil.Encode(contentHash)[2:], true)
+	stickerPack.ContentHash = hexutil.Encode(contentHash)[2:]
+	fileContent, err := api.downloader.Get(stickerPack.ContentHash)
 	if err != nil {
 		return err
 	}
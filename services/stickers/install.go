@@ -37,6 +37,8 @@ func (api *API) Install(chainID uint64, packID *bigint.BigInt) error {
 		return err
 	}
 
+	api.pinStickerPack(uint(packID.Uint64()), *stickerPack)
+
 	return nil
 }
 
@@ -100,6 +102,8 @@ func (api *API) Uninstall(packID *bigint.BigInt) error {
 		return err
 	}
 
+	api.unpinStickerPack(uint(packID.Uint64()))
+
 	// Removing uninstalled pack from recent stickers
 
 	recentStickers, err := api.recentStickers()
@@ -0,0 +1,78 @@
+package stickers
+
+import (
+	"fmt"
+	"os"
+
+	"go.uber.org/zap"
+
+	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/logutils"
+)
+
+// importedContentPinOwner pins the content imported from CAR files
+const importedContentPinOwner = "sticker-packs-import"
+
+func stickerPackPinOwner(packID uint) string {
+	return fmt.Sprintf("sticker-pack:%d", packID)
+}
+
+// contentHashes returns the IPFS contenthashes of the pack data and images
+func (s StickerPack) contentHashes() []string {
+	var hashes []string
+	for _, hash := range []string{s.ContentHash, s.Preview, s.Thumbnail} {
+		if hash != "" {
+			hashes = append(hashes, hash)
+		}
+	}
+	for _, sticker := range s.Stickers {
+		hashes = append(hashes, sticker.Hash)
+	}
+	return hashes
+}
+
+// pinStickerPack keeps the content of an installed pack in the IPFS cache, so that it's
+// available offline, and downloads what's missing in the background
+func (api *API) pinStickerPack(packID uint, stickerPack StickerPack) {
+	hashes := stickerPack.contentHashes()
+	if err := api.downloader.Pin(stickerPackPinOwner(packID), hashes...); err != nil {
+		logutils.ZapLogger().Warn("failed to pin sticker pack", zap.Uint("packID", packID), zap.Error(err))
+		return
+	}
+
+	go func() {
+		defer gocommon.LogOnPanic()
+		api.downloader.Prefetch(hashes...)
+	}()
+}
+
+func (api *API) unpinStickerPack(packID uint) {
+	if err := api.downloader.Unpin(stickerPackPinOwner(packID)); err != nil {
+		logutils.ZapLogger().Warn("failed to unpin sticker pack", zap.Uint("packID", packID), zap.Error(err))
+	}
+}
+
+// pinInstalledStickerPacks pins the packs installed before their content was pinned
+func (api *API) pinInstalledStickerPacks() error {
+	installedPacks, err := api.installedStickerPacks()
+	if err != nil {
+		return err
+	}
+
+	for packID, stickerPack := range installedPacks {
+		api.pinStickerPack(packID, stickerPack)
+	}
+	return nil
+}
+
+// ImportCAR stores the sticker pack data and images of a CAR file in the IPFS cache, where
+// they're kept for offline use. It returns the CIDs of the files imported.
+func (api *API) ImportCAR(path string) ([]string, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	return api.downloader.ImportCAR(file, importedContentPinOwner)
+}
@@ -3,10 +3,13 @@ package stickers
 import (
 	"context"
 
+	"go.uber.org/zap"
+
 	"github.com/ethereum/go-ethereum/p2p"
 	ethRpc "github.com/ethereum/go-ethereum/rpc"
 	"github.com/status-im/status-go/account"
 	"github.com/status-im/status-go/ipfs"
+	"github.com/status-im/status-go/logutils"
 	"github.com/status-im/status-go/multiaccounts/accounts"
 	"github.com/status-im/status-go/params"
 	"github.com/status-im/status-go/rpc"
@@ -46,6 +49,9 @@ type Service struct {
 
 // Start a service.
 func (s *Service) Start() error {
+	if err := s.api.pinInstalledStickerPacks(); err != nil {
+		logutils.ZapLogger().Warn("failed to pin installed sticker packs", zap.Error(err))
+	}
 	return nil
 }
 
@@ -25,6 +25,7 @@ import (
 	"github.com/status-im/status-go/services/wallet/walletevent"
 	"github.com/status-im/status-go/services/wallet/wallettypes"
 	"github.com/status-im/status-go/signal"
+	"github.com/status-im/status-go/tracing"
 )
 
 const (
@@ -40,6 +41,8 @@ type Manager struct {
 
 	// Local data used for storage purposes
 	buildInputParams *requests.RouterBuildTransactionsParams
+	// buildSpanContext links sending the transactions to the trace of building them
+	buildSpanContext tracing.SpanContext
 }
 
 func NewManager(walletDB *sql.DB, eventFeed *event.Feed, router *router.Router, transactionManager *transfer.TransactionManager, transferController *transfer.Controller) *Manager {
@@ -54,6 +57,7 @@ func NewManager(walletDB *sql.DB, eventFeed *event.Feed, router *router.Router,
 
 func (m *Manager) ClearLocalRouteData() {
 	m.buildInputParams = nil
+	m.buildSpanContext = tracing.SpanContext{}
 	m.transactionManager.ClearLocalRouterTransactionsData()
 }
 
@@ -63,6 +67,9 @@ func (m *Manager) BuildTransactionsFromRoute(ctx context.Context, buildInputPara
 
 		m.router.StopSuggestedRoutesAsyncCalculation()
 
+		_, span := tracing.Start(tracing.ContextWithSpanContext(ctx, m.router.GetRouteSpanContext()),
+			"RouteExecution.BuildTransactionsFromRoute", tracing.String("uuid", buildInputParams.Uuid))
+
 		var err error
 		response := &responses.RouterTransactionsForSigning{
 			SendDetails: &responses.SendDetails{
@@ -71,6 +78,7 @@ func (m *Manager) BuildTransactionsFromRoute(ctx context.Context, buildInputPara
 		}
 
 		defer func() {
+			span.EndWithError(err)
 			if err != nil {
 				m.ClearLocalRouteData()
 				err = statusErrors.CreateErrorResponseFromError(err)
@@ -87,6 +95,7 @@ func (m *Manager) BuildTransactionsFromRoute(ctx context.Context, buildInputPara
 		}
 
 		m.buildInputParams = buildInputParams
+		m.buildSpanContext = span.SpanContext()
 
 		response.SendDetails.UpdateFields(routeInputParams)
 
@@ -112,6 +121,9 @@ func (m *Manager) SendRouterTransactionsWithSignatures(ctx context.Context, send
 	go func() {
 		defer status_common.LogOnPanic()
 
+		ctx, span := tracing.Start(tracing.ContextWithSpanContext(ctx, m.buildSpanContext),
+			"RouteExecution.SendRouterTransactionsWithSignatures", tracing.String("uuid", sendInputParams.Uuid))
+
 		var (
 			err              error
 			routeInputParams requests.RouteInputParams
@@ -137,6 +149,7 @@ func (m *Manager) SendRouterTransactionsWithSignatures(ctx context.Context, send
 				m.ClearLocalRouteData()
 			}
 
+			span.EndWithError(err)
 			if err != nil {
 				err = statusErrors.CreateErrorResponseFromError(err)
 				response.SendDetails.ErrorResponse = err.(*statusErrors.ErrorResponse)
@@ -158,7 +171,9 @@ func (m *Manager) SendRouterTransactionsWithSignatures(ctx context.Context, send
 
 		response.SendDetails.UpdateFields(routeInputParams)
 
+		_, signaturesSpan := tracing.Start(ctx, "RouteExecution.AddSignatures", tracing.Int("signatures", len(sendInputParams.Signatures)))
 		err = m.transactionManager.ValidateAndAddSignaturesToRouterTransactions(sendInputParams.Signatures)
+		signaturesSpan.EndWithError(err)
 		if err != nil {
 			return
 		}
@@ -30,6 +30,7 @@ import (
 	"github.com/status-im/status-go/services/wallet/token"
 	walletToken "github.com/status-im/status-go/services/wallet/token"
 	"github.com/status-im/status-go/signal"
+	"github.com/status-im/status-go/tracing"
 	"github.com/status-im/status-go/transactions"
 )
 
@@ -82,6 +83,8 @@ type Router struct {
 
 	lastInputParamsMutex sync.Mutex
 	lastInputParams      *requests.RouteInputParams
+	// lastSpanContext is the span of the last routes calculation, building and sending its transactions continue that trace
+	lastSpanContext tracing.SpanContext
 
 	clientsForUpdatesPerChains sync.Map
 }
@@ -134,6 +137,13 @@ func (r *Router) GetBestRouteAndAssociatedInputParams() (routes.Route, requests.
 	return r.activeRoutes.Best.Copy(), ip
 }
 
+// GetRouteSpanContext returns the span context of the last routes calculation
+func (r *Router) GetRouteSpanContext() tracing.SpanContext {
+	r.lastInputParamsMutex.Lock()
+	defer r.lastInputParamsMutex.Unlock()
+	return r.lastSpanContext
+}
+
 func (r *Router) SetTestBalanceMap(balanceMap map[string]*big.Int) {
 	for k, v := range balanceMap {
 		r.activeBalanceMap.Store(k, v)
@@ -226,6 +236,12 @@ func (r *Router) StopSuggestedRoutesCalculation() {
 }
 
 func (r *Router) SuggestedRoutes(ctx context.Context, input *requests.RouteInputParams) (suggestedRoutes *SuggestedRoutes, err error) {
+	ctx, span := tracing.Start(ctx, "Router.SuggestedRoutes",
+		tracing.String("uuid", input.Uuid),
+		tracing.Int("sendType", int(input.SendType)),
+	)
+	defer func() { span.EndWithError(err) }()
+
 	r.clearActiveRoute()
 	r.abortUpdates()
 	r.markRouteCanceled(false)
@@ -239,6 +255,7 @@ func (r *Router) SuggestedRoutes(ctx context.Context, input *requests.RouteInput
 
 	r.lastInputParamsMutex.Lock()
 	r.lastInputParams = input
+	r.lastSpanContext = span.SpanContext()
 	r.lastInputParamsMutex.Unlock()
 
 	defer func() {
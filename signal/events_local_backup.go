@@ -0,0 +1,10 @@
+package signal
+
+const (
+	// EventLocalBackupProgress is emitted while a local backup archive is exported or restored
+	EventLocalBackupProgress = "localBackup.progress"
+)
+
+func SendLocalBackupProgress(progress interface{}) {
+	send(EventLocalBackupProgress, progress)
+}
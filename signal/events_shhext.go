@@ -59,6 +59,9 @@ const (
 	// EventMailserverNotWorking is triggered when the mailserver has failed to connect or failed to respond to requests
 	EventMailserverNotWorking = "mailserver.not.working"
 
+	// EventStorenodePreferenceChanged is triggered when the scores of the storenodes change the storenodes preferred for the fleet or a community
+	EventStorenodePreferenceChanged = "storenode.preference.changed"
+
 	// EventUpdateAvailable is triggered after a update verification is performed
 	EventUpdateAvailable = "update.available"
 )
@@ -108,6 +111,13 @@ type MailserverSignal struct {
 	ID      string               `json:"id"`
 }
 
+// StorenodePreferenceSignal holds the storenodes preferred for a community, or for the fleet when CommunityID is empty
+type StorenodePreferenceSignal struct {
+	CommunityID string `json:"communityId,omitempty"`
+	Primary     string `json:"primary"`
+	Backup      string `json:"backup,omitempty"`
+}
+
 type Filter struct {
 	// ChatID is the identifier of the chat
 	ChatID string `json:"chatId"`
@@ -240,3 +250,7 @@ func SendMailserverChanged(ms *mailservers.Mailserver) {
 func SendMailserverNotWorking() {
 	sendMailserverSignal(nil, EventMailserverNotWorking)
 }
+
+func SendStorenodePreferenceChanged(communityID string, primary string, backup string) {
+	send(EventStorenodePreferenceChanged, StorenodePreferenceSignal{CommunityID: communityID, Primary: primary, Backup: backup})
+}
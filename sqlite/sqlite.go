@@ -37,6 +37,7 @@ func DecryptDB(oldPath string, newPath string, key string, kdfIterationsNumber i
 	if err != nil {
 		return err
 	}
+	defer db.Close()
 
 	_, err = db.Exec(`ATTACH DATABASE '` + newPath + `' AS plaintext KEY ''`)
 	if err != nil {
@@ -0,0 +1 @@
+DROP TABLE waku_messages;
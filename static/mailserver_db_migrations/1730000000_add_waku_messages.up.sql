@@ -0,0 +1,4 @@
+CREATE TABLE waku_messages (message_hash BYTEA NOT NULL PRIMARY KEY, pubsub_topic TEXT NOT NULL, content_topic TEXT NOT NULL, timestamp BIGINT NOT NULL, data BYTEA NOT NULL);
+
+CREATE INDEX waku_messages_topics_timestamp_idx ON waku_messages (pubsub_topic, content_topic, timestamp, message_hash);
+CREATE INDEX waku_messages_timestamp_idx ON waku_messages (timestamp, message_hash);
@@ -0,0 +1,87 @@
+package tracing
+
+import (
+	"context"
+
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanContext identifies a span within a trace, it is what's propagated to child spans
+type SpanContext = trace.SpanContext
+
+type Attribute = attribute.KeyValue
+
+func String(key string, value string) Attribute {
+	return attribute.String(key, value)
+}
+
+func Int(key string, value int) Attribute {
+	return attribute.Int(key, value)
+}
+
+func Int64(key string, value int64) Attribute {
+	return attribute.Int64(key, value)
+}
+
+func Uint64(key string, value uint64) Attribute {
+	return attribute.Int64(key, int64(value))
+}
+
+func Bool(key string, value bool) Attribute {
+	return attribute.Bool(key, value)
+}
+
+// Span is a timed operation of a trace. A nil Span is valid and records nothing.
+type Span struct {
+	span trace.Span
+}
+
+// SpanContext returns the span context of the span, spans that aren't sampled still carry it
+// so that their children follow the same sampling decision
+func (s *Span) SpanContext() SpanContext {
+	if s == nil {
+		return SpanContext{}
+	}
+	return s.span.SpanContext()
+}
+
+func (s *Span) SetAttributes(attrs ...Attribute) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(attrs...)
+}
+
+// RecordError marks the span as failed, nil errors are ignored
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// End ends the span and queues it for export, calling it more than once has no effect
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+// EndWithError records err, if any, and ends the span
+func (s *Span) EndWithError(err error) {
+	s.RecordError(err)
+	s.End()
+}
+
+// ContextWithSpanContext returns a copy of ctx whose next span will be a child of sc.
+// It is used to continue a trace across calls that don't share a context, e.g. separate RPC calls of one flow.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	if !sc.IsValid() {
+		return ctx
+	}
+	return trace.ContextWithRemoteSpanContext(ctx, sc)
+}
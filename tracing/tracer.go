@@ -0,0 +1,143 @@
+// Package tracing records OpenTelemetry spans of the messenger, RPC and wallet flows with the
+// OpenTelemetry SDK and exports them with OTLP/HTTP, e.g. to a local Jaeger, and/or to a file.
+//
+// Spans are started with Start, which makes the new span the current span of the returned
+// context, so that spans started further down the call chain become its children:
+//
+//	ctx, span := tracing.Start(ctx, "MessageSender.SendPrivate", tracing.String("chatID", chatID))
+//	defer func() { span.EndWithError(err) }()
+//
+// When tracing is disabled Start returns a span that records nothing.
+package tracing
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/otel"
+	sdkresource "go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultServiceName  = "status-go"
+	instrumentationName = "github.com/status-im/status-go/tracing"
+	shutdownTimeout     = 5 * time.Second
+)
+
+var ErrNoExporter = errors.New("tracing: no exporter configured")
+
+// Config configures the global tracer
+type Config struct {
+	// ServiceName is reported as the service.name resource attribute
+	ServiceName string
+	// SampleRatio is the ratio of new traces that are recorded, between 0 and 1.
+	// Children spans follow the decision of their parent.
+	SampleRatio float64
+	// OTLPEndpoint is the base URL of an OTLP/HTTP collector, e.g. http://localhost:4318
+	OTLPEndpoint string
+	// FilePath is a file spans are appended to, one JSON encoded span per line
+	FilePath string
+}
+
+var (
+	globalMu       sync.RWMutex
+	globalProvider *sdktrace.TracerProvider
+	globalTracer   trace.Tracer = noop.NewTracerProvider().Tracer(instrumentationName)
+)
+
+// Init configures the global tracer, replacing and shutting down the previous one
+func Init(config Config, logger *zap.Logger) error {
+	exporters, err := newExporters(config)
+	if err != nil {
+		return err
+	}
+	if len(exporters) == 0 {
+		return ErrNoExporter
+	}
+
+	otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+		logger.Warn("failed to export spans", zap.Error(err))
+	}))
+
+	provider, err := newTracerProvider(config, exporters...)
+	if err != nil {
+		return err
+	}
+
+	globalMu.Lock()
+	previous := globalProvider
+	globalProvider = provider
+	globalTracer = provider.Tracer(instrumentationName)
+	globalMu.Unlock()
+
+	if previous != nil {
+		shutdown(previous)
+	}
+	return nil
+}
+
+// Shutdown flushes the spans of the global tracer and disables tracing
+func Shutdown() {
+	globalMu.Lock()
+	provider := globalProvider
+	globalProvider = nil
+	globalTracer = noop.NewTracerProvider().Tracer(instrumentationName)
+	globalMu.Unlock()
+
+	if provider != nil {
+		shutdown(provider)
+	}
+}
+
+func shutdown(provider *sdktrace.TracerProvider) {
+	ctx, cancel := context.WithTimeout(context.Background(), shutdownTimeout)
+	defer cancel()
+	_ = provider.Shutdown(ctx)
+}
+
+// newTracerProvider returns a provider batching the spans of the sampled traces to the given exporters
+func newTracerProvider(config Config, exporters ...sdktrace.SpanExporter) (*sdktrace.TracerProvider, error) {
+	serviceName := config.ServiceName
+	if serviceName == "" {
+		serviceName = defaultServiceName
+	}
+
+	resource, err := sdkresource.Merge(
+		sdkresource.Default(),
+		sdkresource.NewWithAttributes(semconv.SchemaURL, semconv.ServiceName(serviceName)),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	options := []sdktrace.TracerProviderOption{
+		sdktrace.WithResource(resource),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(config.SampleRatio))),
+	}
+	for _, exporter := range exporters {
+		options = append(options, sdktrace.WithBatcher(exporter))
+	}
+
+	return sdktrace.NewTracerProvider(options...), nil
+}
+
+// Start starts a span with the global tracer, child of the current span of ctx if any
+func Start(ctx context.Context, name string, attrs ...Attribute) (context.Context, *Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+
+	globalMu.RLock()
+	tracer := globalTracer
+	globalMu.RUnlock()
+
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(attrs...))
+	return ctx, &Span{span: span}
+}
@@ -0,0 +1,140 @@
+package tracing
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+)
+
+// initTestTracer installs a global tracer exporting to memory, the spans are returned by the returned func
+func initTestTracer(t *testing.T, sampleRatio float64) func() tracetest.SpanStubs {
+	exporter := tracetest.NewInMemoryExporter()
+	provider, err := newTracerProvider(Config{SampleRatio: sampleRatio}, exporter)
+	require.NoError(t, err)
+
+	globalMu.Lock()
+	globalProvider = provider
+	globalTracer = provider.Tracer(instrumentationName)
+	globalMu.Unlock()
+	t.Cleanup(Shutdown)
+
+	return func() tracetest.SpanStubs {
+		require.NoError(t, provider.ForceFlush(context.Background()))
+		return exporter.GetSpans()
+	}
+}
+
+func TestTracer_Parenting(t *testing.T) {
+	spans := initTestTracer(t, 1)
+
+	ctx, root := Start(context.Background(), "root", String("chatID", "0x01"))
+	_, child := Start(ctx, "child", Int("count", 2), Bool("ok", true))
+	child.EndWithError(errors.New("failed"))
+	child.End()
+	root.End()
+
+	exported := spans()
+	require.Len(t, exported, 2)
+	require.Equal(t, "child", exported[0].Name)
+	require.Equal(t, "root", exported[1].Name)
+	require.Equal(t, exported[1].SpanContext.TraceID(), exported[0].SpanContext.TraceID())
+	require.Equal(t, exported[1].SpanContext.SpanID(), exported[0].Parent.SpanID())
+	require.False(t, exported[1].Parent.IsValid())
+
+	require.Equal(t, codes.Error, exported[0].Status.Code)
+	require.Equal(t, "failed", exported[0].Status.Description)
+	require.Equal(t, int64(2), exported[0].Attributes[0].Value.AsInt64())
+	require.True(t, exported[0].Attributes[1].Value.AsBool())
+	require.Equal(t, "0x01", exported[1].Attributes[0].Value.AsString())
+}
+
+func TestTracer_Sampling(t *testing.T) {
+	spans := initTestTracer(t, 0)
+
+	ctx, root := Start(context.Background(), "root")
+	require.True(t, root.SpanContext().IsValid())
+	require.False(t, root.SpanContext().IsSampled())
+
+	_, child := Start(ctx, "child")
+	require.Equal(t, root.SpanContext().TraceID(), child.SpanContext().TraceID())
+	require.False(t, child.SpanContext().IsSampled())
+	child.End()
+	root.End()
+
+	// A sampled remote parent is followed even if the ratio is 0
+	remote := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	_, span := Start(ContextWithSpanContext(context.Background(), remote), "continued")
+	require.Equal(t, remote.TraceID(), span.SpanContext().TraceID())
+	span.End()
+
+	exported := spans()
+	require.Len(t, exported, 1)
+	require.Equal(t, "continued", exported[0].Name)
+	require.Equal(t, remote.SpanID(), exported[0].Parent.SpanID())
+}
+
+func TestStart_Disabled(t *testing.T) {
+	_, span := Start(context.Background(), "noop")
+	require.False(t, span.SpanContext().IsValid())
+	span.SetAttributes(String("key", "value"))
+	span.EndWithError(errors.New("failed"))
+
+	// Methods of a nil span do nothing
+	var nilSpan *Span
+	nilSpan.SetAttributes(String("key", "value"))
+	nilSpan.EndWithError(errors.New("failed"))
+	require.False(t, nilSpan.SpanContext().IsValid())
+}
+
+func TestInit_Exporters(t *testing.T) {
+	var requests int
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, otlpTracesPath, r.URL.Path)
+		require.Equal(t, "application/x-protobuf", r.Header.Get("Content-Type"))
+		_, err := io.ReadAll(r.Body)
+		require.NoError(t, err)
+		requests++
+	}))
+	defer collector.Close()
+
+	path := filepath.Join(t.TempDir(), "spans.jsonl")
+	require.ErrorIs(t, Init(Config{SampleRatio: 1}, zap.NewNop()), ErrNoExporter)
+	require.NoError(t, Init(Config{SampleRatio: 1, OTLPEndpoint: collector.URL, FilePath: path}, zap.NewNop()))
+
+	_, span := Start(context.Background(), "exported")
+	span.End()
+	Shutdown()
+
+	require.Equal(t, 1, requests)
+
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+
+	var lines int
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		var exported struct{ Name string }
+		require.NoError(t, json.Unmarshal(scanner.Bytes(), &exported))
+		require.Equal(t, "exported", exported.Name)
+		lines++
+	}
+	require.Equal(t, 1, lines)
+}
@@ -0,0 +1,14 @@
+package transactions
+
+import (
+	prom "github.com/prometheus/client_golang/prometheus"
+)
+
+var pendingTxGauge = prom.NewGauge(prom.GaugeOpts{
+	Name: "wallet_pending_transactions",
+	Help: "Number of tracked transactions still pending after the last status check.",
+})
+
+func init() {
+	prom.MustRegister(pendingTxGauge)
+}
@@ -163,6 +163,7 @@ func (tm *PendingTxTracker) fetchAndUpdateDB(ctx context.Context) bool {
 	if len(txs) == doneCount {
 		res = WorkDone
 	}
+	pendingTxGauge.Set(float64(len(txs) - doneCount))
 
 	tm.logger.Debug("Done PTs iteration", zap.Int("count", doneCount), zap.Bool("completed", res))
 
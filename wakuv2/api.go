@@ -41,6 +41,7 @@ import (
 	"google.golang.org/protobuf/proto"
 
 	gocommon "github.com/status-im/status-go/common"
+	"github.com/status-im/status-go/tracing"
 )
 
 // List of errors
@@ -189,11 +190,16 @@ type NewMessage struct {
 
 // Post posts a message on the Waku network.
 // returns the hash of the message in case of success.
-func (api *PublicWakuAPI) Post(ctx context.Context, req NewMessage) (hexutil.Bytes, error) {
+func (api *PublicWakuAPI) Post(ctx context.Context, req NewMessage) (_ hexutil.Bytes, err error) {
+	_, span := tracing.Start(ctx, "waku.Post",
+		tracing.String("pubsubTopic", req.PubsubTopic),
+		tracing.String("contentTopic", req.ContentTopic.String()),
+	)
+	defer func() { span.EndWithError(err) }()
+
 	var (
 		symKeyGiven = len(req.SymKeyID) > 0
 		pubKeyGiven = len(req.PublicKey) > 0
-		err         error
 	)
 
 	// user must specify either a symmetric or an asymmetric key
@@ -263,6 +269,7 @@ func (api *PublicWakuAPI) Post(ctx context.Context, req NewMessage) (hexutil.Byt
 	if err != nil {
 		return nil, err
 	}
+	span.SetAttributes(tracing.String("hash", hexutil.Encode(hash)))
 
 	return hash, nil
 }
@@ -48,12 +48,29 @@ var (
 		Help:    "Size of processed Waku envelopes in bytes.",
 		Buckets: prom.ExponentialBuckets(256, 4, 10),
 	})
+	PeersGauge = prom.NewGauge(prom.GaugeOpts{
+		Name: "waku2_peers",
+		Help: "Number of connected peers.",
+	})
+	PeersByShardGauge = prom.NewGaugeVec(prom.GaugeOpts{
+		Name: "waku2_peers_by_shard",
+		Help: "Number of connected peers split by relay shard.",
+	}, []string{"shard"})
+	StoreQueryDurationMeter = prom.NewHistogramVec(prom.HistogramOpts{
+		Name:    "waku2_store_query_duration_seconds",
+		Help:    "The time it took to query a store node, including all pages.",
+		Buckets: prom.ExponentialBuckets(0.05, 2, 12),
+	}, []string{"result"})
 )
 
 func init() {
 	prom.MustRegister(EnvelopesReceivedCounter)
+	prom.MustRegister(EnvelopesValidatedCounter)
 	prom.MustRegister(EnvelopesRejectedCounter)
 	prom.MustRegister(EnvelopesCacheFailedCounter)
 	prom.MustRegister(EnvelopesCachedCounter)
 	prom.MustRegister(EnvelopesSizeMeter)
+	prom.MustRegister(PeersGauge)
+	prom.MustRegister(PeersByShardGauge)
+	prom.MustRegister(StoreQueryDurationMeter)
 }
@@ -28,12 +28,14 @@ import (
 	"math"
 	"net"
 	"runtime"
+	"strconv"
 	"strings"
 	"sync"
 	"testing"
 	"time"
 
 	"github.com/jellydator/ttlcache/v3"
+	"github.com/libp2p/go-libp2p/core/host"
 	"github.com/libp2p/go-libp2p/core/peer"
 	"github.com/libp2p/go-libp2p/core/peerstore"
 	"github.com/multiformats/go-multiaddr"
@@ -196,13 +198,30 @@ type Waku struct {
 
 	statusTelemetryClient ITelemetryClient
 
+	storeServer StoreServer
+
 	defaultShardInfo protocol.RelayShards
 }
 
+// StoreServer serves the history of the messages relayed by the node with the store protocol
+type StoreServer interface {
+	Start(ctx context.Context, h host.Host, r *relay.WakuRelay) error
+	Stop() error
+}
+
 func (w *Waku) SetStatusTelemetryClient(client ITelemetryClient) {
 	w.statusTelemetryClient = client
 }
 
+// SetStoreServer makes the node a storenode. It must be called before Start, and requires relay.
+func (w *Waku) SetStoreServer(server StoreServer) error {
+	if w.cfg.LightClient {
+		return errors.New("only available for full nodes")
+	}
+	w.storeServer = server
+	return nil
+}
+
 func newTTLCache() *ttlcache.Cache[gethcommon.Hash, *common.ReceivedMessage] {
 	cache := ttlcache.New[gethcommon.Hash, *common.ReceivedMessage](ttlcache.WithTTL[gethcommon.Hash, *common.ReceivedMessage](cacheTTL))
 	go func() {
@@ -1073,6 +1092,12 @@ func (w *Waku) Start() error {
 
 	w.logger.Info("WakuV2 PeerID", zap.Stringer("id", w.node.Host().ID()))
 
+	if w.storeServer != nil {
+		if err := w.storeServer.Start(w.ctx, w.node.Host(), w.node.Relay()); err != nil {
+			return fmt.Errorf("failed to start the store server: %v", err)
+		}
+	}
+
 	w.discoverAndConnectPeers()
 
 	if w.cfg.EnableDiscV5 {
@@ -1253,6 +1278,8 @@ func (w *Waku) checkForConnectionChanges() {
 		Type:    w.state.Type, //setting state type as previous one since there won't be a change here
 		Offline: !latestConnStatus.IsOnline,
 	})
+
+	w.updatePeerGauges()
 }
 
 func (w *Waku) reportPeerMetrics() {
@@ -1261,39 +1288,87 @@ func (w *Waku) reportPeerMetrics() {
 		w.statusTelemetryClient.PushPeerCount(w.ctx, w.PeerCount())
 		w.statusTelemetryClient.PushPeerConnFailures(w.ctx, connFailures)
 
-		peerCountByOrigin := make(map[wps.Origin]uint)
-		peerCountByShard := make(map[uint16]uint)
-		wakuPeerStore := w.node.Host().Peerstore().(wps.WakuPeerstore)
+		peerCountByOrigin, peerCountByShard := w.peerCounts()
+		w.statusTelemetryClient.PushPeerCountByShard(w.ctx, peerCountByShard)
+		w.statusTelemetryClient.PushPeerCountByOrigin(w.ctx, peerCountByOrigin)
+	}
+}
 
-		for _, peerID := range w.node.Host().Network().Peers() {
-			origin, err := wakuPeerStore.Origin(peerID)
-			if err != nil {
-				origin = wps.Unknown
-			}
+// peerCounts returns the number of connected peers split by origin and by relay shard
+func (w *Waku) peerCounts() (map[wps.Origin]uint, map[uint16]uint) {
+	peerCountByOrigin := make(map[wps.Origin]uint)
+	peerCountByShard := make(map[uint16]uint)
+	wakuPeerStore := w.node.Host().Peerstore().(wps.WakuPeerstore)
 
-			peerCountByOrigin[origin]++
-			pubsubTopics, err := wakuPeerStore.PubSubTopics(peerID)
-			if err != nil {
-				continue
-			}
+	for _, peerID := range w.node.Host().Network().Peers() {
+		origin, err := wakuPeerStore.Origin(peerID)
+		if err != nil {
+			origin = wps.Unknown
+		}
 
-			keys := make([]string, 0, len(pubsubTopics))
-			for k := range pubsubTopics {
-				keys = append(keys, k)
-			}
-			relayShards, err := protocol.TopicsToRelayShards(keys...)
-			if err != nil {
-				continue
-			}
+		peerCountByOrigin[origin]++
+		pubsubTopics, err := wakuPeerStore.PubSubTopics(peerID)
+		if err != nil {
+			continue
+		}
 
-			for _, shards := range relayShards {
-				for _, shard := range shards.ShardIDs {
-					peerCountByShard[shard]++
-				}
+		keys := make([]string, 0, len(pubsubTopics))
+		for k := range pubsubTopics {
+			keys = append(keys, k)
+		}
+		relayShards, err := protocol.TopicsToRelayShards(keys...)
+		if err != nil {
+			continue
+		}
+
+		for _, shards := range relayShards {
+			for _, shard := range shards.ShardIDs {
+				peerCountByShard[shard]++
 			}
 		}
-		w.statusTelemetryClient.PushPeerCountByShard(w.ctx, peerCountByShard)
-		w.statusTelemetryClient.PushPeerCountByOrigin(w.ctx, peerCountByOrigin)
+	}
+
+	return peerCountByOrigin, peerCountByShard
+}
+
+// PeerState is a snapshot of the connectivity of the node
+type PeerState struct {
+	PeerID          peer.ID             `json:"peerId"`
+	Online          bool                `json:"online"`
+	LightClient     bool                `json:"lightClient"`
+	PeerCount       int                 `json:"peerCount"`
+	PeersByOrigin   map[wps.Origin]uint `json:"peersByOrigin"`
+	PeersByShard    map[uint16]uint     `json:"peersByShard"`
+	Peers           types.PeerStats     `json:"peers"`
+	ActiveStorenode peer.ID             `json:"activeStorenode,omitempty"`
+}
+
+// PeerState returns the connected peers split by origin and shard, and the storenode in use
+func (w *Waku) PeerState() PeerState {
+	peerCountByOrigin, peerCountByShard := w.peerCounts()
+	state := PeerState{
+		PeerID:        w.PeerID(),
+		Online:        w.onlineChecker.IsOnline(),
+		LightClient:   w.cfg.LightClient,
+		PeerCount:     w.PeerCount(),
+		PeersByOrigin: peerCountByOrigin,
+		PeersByShard:  peerCountByShard,
+		Peers:         w.Peers(),
+	}
+	if w.StorenodeCycle != nil {
+		state.ActiveStorenode = w.StorenodeCycle.GetActiveStorenode()
+	}
+	return state
+}
+
+// updatePeerGauges refreshes the prometheus peer gauges, shards without peers are dropped
+func (w *Waku) updatePeerGauges() {
+	common.PeersGauge.Set(float64(w.PeerCount()))
+
+	_, peerCountByShard := w.peerCounts()
+	common.PeersByShardGauge.Reset()
+	for shard, count := range peerCountByShard {
+		common.PeersByShardGauge.WithLabelValues(strconv.Itoa(int(shard))).Set(float64(count))
 	}
 }
 
@@ -1367,6 +1442,59 @@ func (w *Waku) MessageExists(mh pb.MessageHash) (bool, error) {
 	return w.envelopeCache.Has(gethcommon.Hash(mh)), nil
 }
 
+// PingStorenode returns the round trip time to a storenode
+func (w *Waku) PingStorenode(ctx context.Context, peerInfo peer.AddrInfo) (time.Duration, error) {
+	return commonapi.NewDefaultPinger(w.node.Host()).PingPeer(ctx, peerInfo)
+}
+
+// RecentMessageHashes returns up to limit hashes of the messages received via relay with a timestamp
+// between from and to, on pubsubTopic or on any pubsub topic if it is empty. They can be used to check
+// that storenodes store the messages of the network.
+func (w *Waku) RecentMessageHashes(pubsubTopic string, from time.Time, to time.Time, limit int) []gethcommon.Hash {
+	w.poolMu.Lock()
+	defer w.poolMu.Unlock()
+
+	var result []gethcommon.Hash
+	w.envelopeCache.Range(func(item *ttlcache.Item[gethcommon.Hash, *common.ReceivedMessage]) bool {
+		msg := item.Value()
+		if msg.MsgType != common.RelayedMessageType || (pubsubTopic != "" && msg.PubsubTopic != pubsubTopic) {
+			return true
+		}
+		if msg.Envelope.Message().GetEphemeral() {
+			return true
+		}
+		timestamp := time.Unix(0, msg.Envelope.Message().GetTimestamp())
+		if timestamp.Before(from) || timestamp.After(to) {
+			return true
+		}
+		result = append(result, item.Key())
+		return len(result) < limit
+	})
+	return result
+}
+
+// CountStoredMessages returns how many of the messages are stored by a storenode
+func (w *Waku) CountStoredMessages(ctx context.Context, peerID peer.ID, hashes []gethcommon.Hash) (int, error) {
+	if len(hashes) == 0 {
+		return 0, nil
+	}
+
+	messageHashes := make([]pb.MessageHash, len(hashes))
+	for i, hash := range hashes {
+		messageHashes[i] = pb.MessageHash(hash)
+	}
+
+	result, err := w.node.Store().QueryByHash(ctx, messageHashes,
+		store.WithPeer(peerID),
+		store.IncludeData(false),
+		store.WithPaging(false, uint64(len(hashes))))
+	if err != nil {
+		return 0, err
+	}
+
+	return len(result.Messages()), nil
+}
+
 func (w *Waku) SetTopicsToVerifyForMissingMessages(peerID peer.ID, pubsubTopic string, contentTopics []string) {
 	if !w.cfg.EnableMissingMessageVerification {
 		return
@@ -1410,6 +1538,12 @@ func (w *Waku) Stop() error {
 
 	w.envelopeCache.Stop()
 
+	if w.storeServer != nil {
+		if err := w.storeServer.Stop(); err != nil {
+			w.logger.Error("failed to stop the store server", zap.Error(err))
+		}
+	}
+
 	w.node.Stop()
 
 	if w.protectedTopicStore != nil {